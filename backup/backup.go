@@ -0,0 +1,218 @@
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// encMagic prefixes client-side encrypted dumps, so Restore can tell an
+// encrypted blob from a plaintext one regardless of current config.
+const encMagic = "WMBENC1\n"
+
+// Store is the object storage surface the runner needs; storage.S3Client
+// satisfies it.
+type Store interface {
+	Put(key string, body []byte) error
+	Get(key string) ([]byte, error)
+}
+
+// dump is the serialized shape of a full backup.
+type dump struct {
+	CreatedAt    time.Time            `json:"created_at"`
+	Users        []models.User        `json:"users"`
+	Restaurants  []models.Restaurant  `json:"restaurants"`
+	MenuItems    []models.MenuItem    `json:"menu_items"`
+	Reservations []models.Reservation `json:"reservations"`
+	Orders       []models.Order       `json:"orders"`
+	OrderItems   []models.OrderItem   `json:"order_items"`
+	DeviceTokens []models.DeviceToken `json:"device_tokens"`
+	Images       []models.Image       `json:"images"`
+}
+
+// Runner produces and restores application data dumps. When an
+// encryption key is configured, dumps are sealed with AES-256-GCM before
+// leaving the process; server-side encryption is requested either way.
+type Runner struct {
+	db      *gorm.DB
+	store   Store
+	backups *repositories.BackupRepository
+	encKey  []byte // nil disables client-side encryption
+}
+
+// NewRunner builds a Runner. encKey must be empty or 32 bytes.
+func NewRunner(db *gorm.DB, store Store, backups *repositories.BackupRepository, encKey []byte) (*Runner, error) {
+	if len(encKey) != 0 && len(encKey) != 32 {
+		return nil, errors.New("backup encryption key must be 32 bytes")
+	}
+	return &Runner{db: db, store: store, backups: backups, encKey: encKey}, nil
+}
+
+// Run performs a backup synchronously and returns its metadata record.
+func (r *Runner) Run() (*models.Backup, error) {
+	meta, err := r.begin()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.finish(meta); err != nil {
+		return meta, err
+	}
+	return meta, nil
+}
+
+// Start creates the metadata record and finishes the dump and upload in
+// the background, so the admin endpoint can answer immediately.
+func (r *Runner) Start() (*models.Backup, error) {
+	meta, err := r.begin()
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := r.finish(meta); err != nil {
+			log.Printf("backup %s: %v", meta.Key, err)
+		}
+	}()
+	return meta, nil
+}
+
+// Restore loads the dump stored under key into the database. It is meant
+// for disaster recovery drills against an empty database; rows are
+// inserted with their original ids and clash with existing ones.
+func (r *Runner) Restore(key string) error {
+	data, err := r.store.Get(key)
+	if err != nil {
+		return err
+	}
+	if data, err = r.unseal(data); err != nil {
+		return err
+	}
+
+	var d dump
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, rows := range [][]interface{}{
+			toAny(d.Users), toAny(d.Restaurants), toAny(d.MenuItems),
+			toAny(d.Reservations), toAny(d.Orders), toAny(d.OrderItems),
+			toAny(d.DeviceTokens), toAny(d.Images),
+		} {
+			for _, row := range rows {
+				if err := tx.Create(row).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Runner) begin() (*models.Backup, error) {
+	meta := &models.Backup{
+		Key:       fmt.Sprintf("backups/%s.json", time.Now().UTC().Format("20060102-150405")),
+		Encrypted: len(r.encKey) > 0,
+		Status:    models.BackupPending,
+	}
+	if err := r.backups.Create(meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+func (r *Runner) finish(meta *models.Backup) error {
+	data, err := r.dump()
+	if err == nil {
+		if data, err = r.seal(data); err == nil {
+			err = r.store.Put(meta.Key, data)
+		}
+	}
+
+	if err != nil {
+		meta.Status = models.BackupFailed
+		meta.LastError = err.Error()
+		if saveErr := r.backups.Update(meta); saveErr != nil {
+			return saveErr
+		}
+		return err
+	}
+
+	meta.Status = models.BackupCompleted
+	meta.SizeBytes = int64(len(data))
+	return r.backups.Update(meta)
+}
+
+func (r *Runner) dump() ([]byte, error) {
+	d := dump{CreatedAt: time.Now().UTC()}
+	for _, dest := range []interface{}{
+		&d.Users, &d.Restaurants, &d.MenuItems, &d.Reservations,
+		&d.Orders, &d.OrderItems, &d.DeviceTokens, &d.Images,
+	} {
+		if err := r.db.Find(dest).Error; err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(d)
+}
+
+func (r *Runner) seal(plaintext []byte) ([]byte, error) {
+	if len(r.encKey) == 0 {
+		return plaintext, nil
+	}
+	gcm, err := newGCM(r.encKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return append([]byte(encMagic), sealed...), nil
+}
+
+func (r *Runner) unseal(data []byte) ([]byte, error) {
+	if len(data) < len(encMagic) || string(data[:len(encMagic)]) != encMagic {
+		return data, nil
+	}
+	if len(r.encKey) == 0 {
+		return nil, errors.New("backup is encrypted but no encryption key is configured")
+	}
+	gcm, err := newGCM(r.encKey)
+	if err != nil {
+		return nil, err
+	}
+	sealed := data[len(encMagic):]
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("encrypted backup is truncated")
+	}
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// toAny flattens a typed slice into []interface{} of row pointers so
+// Restore can insert heterogeneous tables with one loop.
+func toAny[T any](rows []T) []interface{} {
+	out := make([]interface{}, len(rows))
+	for i := range rows {
+		out[i] = &rows[i]
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package booking
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/config"
+)
+
+// ExternalReservation is one booking as an external platform describes
+// it. Status carries the platform-agnostic values "confirmed" and
+// "cancelled"; each driver maps its platform's vocabulary onto them.
+type ExternalReservation struct {
+	Ref           string
+	CustomerName  string
+	CustomerEmail string
+	PartySize     int
+	Date          time.Time
+	Status        string
+}
+
+// Provider talks to an external booking platform. Like the marketing
+// driver, credentials are per restaurant and travel with each call; the
+// platform name picks the wire format inside multi-platform drivers.
+type Provider interface {
+	// FetchReservations returns the venue's bookings changed since the
+	// given time.
+	FetchReservations(platform, apiKey, venueID string, since time.Time) ([]ExternalReservation, error)
+	// PushReservation mirrors one of our bookings onto the platform so
+	// its availability stays honest.
+	PushReservation(platform, apiKey, venueID string, res ExternalReservation) error
+}
+
+// LogProvider logs sync traffic instead of executing it; it is the dev
+// default.
+type LogProvider struct{}
+
+// FetchReservations implements Provider.
+func (LogProvider) FetchReservations(platform, apiKey, venueID string, since time.Time) ([]ExternalReservation, error) {
+	log.Printf("booking (dev mode) fetch platform=%s venue=%s since=%s", platform, venueID, since.Format(time.RFC3339))
+	return nil, nil
+}
+
+// PushReservation implements Provider.
+func (LogProvider) PushReservation(platform, apiKey, venueID string, res ExternalReservation) error {
+	log.Printf("booking (dev mode) push platform=%s venue=%s ref=%s party=%d", platform, venueID, res.Ref, res.PartySize)
+	return nil
+}
+
+// NewFromConfig builds the configured driver. "log" is the dev default:
+// platform traffic is printed instead of executed.
+func NewFromConfig(cfg *config.Config) (Provider, error) {
+	switch cfg.BookingDriver {
+	case "log", "":
+		return LogProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported booking driver %q", cfg.BookingDriver)
+	}
+}
@@ -0,0 +1,109 @@
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+// Breaker states.
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half-open"
+)
+
+// Breaker is a classic three-state circuit breaker. It trips open after
+// a run of consecutive failures, rejects work while open, and after
+// openTimeout lets a single probe through (half-open); the probe's
+// outcome decides whether it closes again or re-opens.
+type Breaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	openTimeout      time.Duration
+
+	state        string
+	failures     int
+	openedAt     time.Time
+	probing      bool
+	probeStarted time.Time
+}
+
+// New builds a closed Breaker that trips after failureThreshold
+// consecutive failures and probes every openTimeout while open.
+func New(failureThreshold int, openTimeout time.Duration) *Breaker {
+	return &Breaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		state:            StateClosed,
+	}
+}
+
+// Allow reports whether work may proceed. While open it returns false
+// until openTimeout has elapsed, then admits one probe. A probe that
+// never reports back (e.g. the request skipped the database) is
+// forgotten after another openTimeout so the breaker cannot wedge.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateClosed:
+		return true
+	case StateOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.startProbe()
+		return true
+	default: // half-open: one probe at a time
+		if b.probing && time.Since(b.probeStarted) < b.openTimeout {
+			return false
+		}
+		b.startProbe()
+		return true
+	}
+}
+
+// Success records a successful operation, closing the breaker.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = StateClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure records a failed operation. In half-open it re-opens
+// immediately; in closed it trips once the threshold is reached.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if b.state == StateHalfOpen {
+		b.trip()
+		return
+	}
+	b.failures++
+	if b.failures >= b.failureThreshold {
+		b.trip()
+	}
+}
+
+// State returns the current state name for health and metrics endpoints.
+func (b *Breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *Breaker) startProbe() {
+	b.probing = true
+	b.probeStarted = time.Now()
+}
+
+func (b *Breaker) trip() {
+	b.state = StateOpen
+	b.openedAt = time.Now()
+	b.failures = 0
+}
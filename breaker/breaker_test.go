@@ -0,0 +1,65 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreakerTripsAfterThreshold(t *testing.T) {
+	b := New(3, time.Minute)
+
+	b.Failure()
+	b.Failure()
+	if !b.Allow() {
+		t.Fatal("breaker should stay closed below the threshold")
+	}
+	b.Failure()
+	if b.State() != StateOpen {
+		t.Fatalf("state = %q, want %q", b.State(), StateOpen)
+	}
+	if b.Allow() {
+		t.Fatal("open breaker should reject work")
+	}
+}
+
+func TestBreakerHalfOpenProbe(t *testing.T) {
+	b := New(1, 10*time.Millisecond)
+	b.Failure()
+
+	time.Sleep(20 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatal("breaker should admit a probe after the open timeout")
+	}
+	if b.Allow() {
+		t.Fatal("only one probe should be admitted at a time")
+	}
+
+	b.Success()
+	if b.State() != StateClosed {
+		t.Fatalf("state after successful probe = %q, want %q", b.State(), StateClosed)
+	}
+}
+
+func TestBreakerReopensOnFailedProbe(t *testing.T) {
+	b := New(1, 10*time.Millisecond)
+	b.Failure()
+
+	time.Sleep(20 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatal("breaker should admit a probe after the open timeout")
+	}
+	b.Failure()
+	if b.State() != StateOpen {
+		t.Fatalf("state after failed probe = %q, want %q", b.State(), StateOpen)
+	}
+}
+
+func TestSuccessResetsFailureCount(t *testing.T) {
+	b := New(2, time.Minute)
+	b.Failure()
+	b.Success()
+	b.Failure()
+	if b.State() != StateClosed {
+		t.Fatalf("state = %q, want %q", b.State(), StateClosed)
+	}
+}
@@ -0,0 +1,53 @@
+package breaker
+
+import (
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Instrument registers GORM callbacks that feed every database
+// operation's outcome into the breaker. Only connection-level failures
+// count against it; business errors like gorm.ErrRecordNotFound say
+// nothing about database health.
+func Instrument(db *gorm.DB, b *Breaker) error {
+	report := func(tx *gorm.DB) {
+		if tx.Error != nil && isConnectionError(tx.Error) {
+			b.Failure()
+			return
+		}
+		b.Success()
+	}
+
+	for name, register := range map[string]func(string, func(*gorm.DB)) error{
+		"create": db.Callback().Create().After("gorm:create").Register,
+		"query":  db.Callback().Query().After("gorm:query").Register,
+		"update": db.Callback().Update().After("gorm:update").Register,
+		"delete": db.Callback().Delete().After("gorm:delete").Register,
+		"row":    db.Callback().Row().After("gorm:row").Register,
+		"raw":    db.Callback().Raw().After("gorm:raw").Register,
+	} {
+		if err := register("breaker:"+name, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isConnectionError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "connection refused") ||
+		strings.Contains(msg, "invalid connection") ||
+		strings.Contains(msg, "bad connection") ||
+		strings.Contains(msg, "broken pipe")
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache is a small TTL key-value store used to avoid re-reading hot,
+// mostly-static data (public menus, restaurant profiles) on every request.
+type Cache interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{}, ttl time.Duration)
+	Delete(key string)
+}
+
+type entry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// Memory is an in-process Cache implementation.
+type Memory struct {
+	mu      sync.RWMutex
+	entries map[string]entry
+}
+
+// NewMemory builds an empty in-memory cache.
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]entry)}
+}
+
+// Get returns the cached value if present and not expired.
+func (m *Memory) Get(key string) (interface{}, bool) {
+	m.mu.RLock()
+	e, ok := m.entries[key]
+	m.mu.RUnlock()
+	if !ok || time.Now().After(e.expiresAt) {
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set stores a value with the given time-to-live.
+func (m *Memory) Set(key string, value interface{}, ttl time.Duration) {
+	m.mu.Lock()
+	m.entries[key] = entry{value: value, expiresAt: time.Now().Add(ttl)}
+	m.mu.Unlock()
+}
+
+// Delete removes a key. Missing keys are a no-op.
+func (m *Memory) Delete(key string) {
+	m.mu.Lock()
+	delete(m.entries, key)
+	m.mu.Unlock()
+}
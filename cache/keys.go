@@ -0,0 +1,23 @@
+package cache
+
+import "fmt"
+
+// MenuKey is the cache key for a restaurant's public menu.
+func MenuKey(restaurantID uint) string {
+	return fmt.Sprintf("menu:%d", restaurantID)
+}
+
+// RestaurantKey is the cache key for a restaurant profile.
+func RestaurantKey(id uint) string {
+	return fmt.Sprintf("restaurant:%d", id)
+}
+
+// RevenueKey is the cache key for one revenue report.
+func RevenueKey(restaurantID uint, granularity, from, to string) string {
+	return fmt.Sprintf("revenue:%d:%s:%s:%s", restaurantID, granularity, from, to)
+}
+
+// BusyHoursKey is the cache key for one busy-hours heatmap.
+func BusyHoursKey(restaurantID uint, from, to string) string {
+	return fmt.Sprintf("busyhours:%d:%s:%s", restaurantID, from, to)
+}
@@ -0,0 +1,318 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// Config holds the application configuration loaded from environment variables.
+type Config struct {
+	Port       string
+	DBDriver   string // "mysql" (default) or "sqlite"
+	DBHost     string
+	DBPort     string
+	DBUser     string
+	DBPassword string
+	DBName     string
+	JWTSecret  string
+
+	// Email delivery. MailDriver is "log" (dev default), "smtp" or
+	// "sendgrid".
+	MailDriver     string
+	MailFrom       string
+	SMTPHost       string
+	SMTPPort       string
+	SMTPUser       string
+	SMTPPassword   string
+	SendGridAPIKey string
+
+	// Push notifications. PushDriver is "log" (dev default) or "fcm".
+	PushDriver   string
+	FCMServerKey string
+
+	// Domain event publishing. EventsDriver is "log" (dev default) or
+	// "nats".
+	EventsDriver string
+	NATSURL      string
+
+	// Full-text search. SearchDriver is "db" (dev default) or
+	// "elasticsearch"; the latter needs a reachable cluster.
+	SearchDriver string
+	ElasticURL   string
+	ElasticIndex string
+
+	// Directory where uploaded media and derived variants are stored;
+	// served under /media.
+	MediaDir string
+
+	// Object storage for backups (S3 or compatible). Backups are disabled
+	// until a bucket is configured. BackupEncryptionKey is hex-encoded,
+	// 32 bytes once decoded; empty relies on server-side encryption only.
+	S3Endpoint          string
+	S3Region            string
+	S3Bucket            string
+	S3AccessKey         string
+	S3SecretKey         string
+	BackupEncryptionKey string
+
+	// Sentry-compatible error reporting; empty disables it.
+	SentryDSN string
+
+	// TLS. Set TLSCertFile/TLSKeyFile to serve HTTPS directly, or enable
+	// AutoTLS for Let's Encrypt certificates (requires port 443 and the
+	// listed hostnames pointing at this server). Both off means plain HTTP
+	// behind a reverse proxy.
+	TLSCertFile     string
+	TLSKeyFile      string
+	AutoTLSEnabled  bool
+	AutoTLSHosts    string // comma-separated hostnames
+	AutoTLSCacheDir string
+
+	// Connection pool tuning for the underlying sql.DB.
+	DBMaxOpenConns           int
+	DBMaxIdleConns           int
+	DBConnMaxLifetimeMinutes int
+
+	// HTTP server hardening. Read/write/idle timeouts and the header size
+	// cap bound what a single connection can hold open (slowloris et al.).
+	// HTTP2Enabled only matters when the server terminates TLS itself.
+	ServerReadTimeoutSeconds       int
+	ServerReadHeaderTimeoutSeconds int
+	ServerWriteTimeoutSeconds      int
+	ServerIdleTimeoutSeconds       int
+	ServerMaxHeaderBytes           int
+	HTTP2Enabled                   bool
+
+	// Transient-error retries for repository writes and transactions.
+	DBRetryMaxAttempts int
+	DBRetryBaseDelayMS int
+
+	// Database circuit breaker: trip after this many consecutive
+	// connection failures, probe again after the timeout.
+	BreakerFailureThreshold   int
+	BreakerOpenTimeoutSeconds int
+
+	// Maximum time a request may run before its context is cancelled.
+	RequestTimeoutSeconds int
+
+	// How long to wait for in-flight requests to drain on shutdown.
+	ShutdownTimeoutSeconds int
+
+	// Logging and feature flags; both hot-reloadable (see Runtime).
+	LogLevel     string
+	FeatureFlags string // comma-separated names of enabled flags
+
+	// Password hashing. PasswordHashDriver is "bcrypt" (default) or
+	// "argon2id"; stored hashes made with other parameters are upgraded
+	// transparently on login.
+	PasswordHashDriver string
+	BcryptCost         int
+	Argon2MemoryKB     int
+	Argon2Time         int
+	Argon2Threads      int
+
+	// TaxRatePct is the sales tax rate in percent; menu prices include
+	// it, and revenue reports break out the included portion.
+	TaxRatePct int
+
+	// Payments. PaymentsDriver is "log" (dev default, refunds are
+	// printed instead of executed) or "stripe". The webhook secret
+	// verifies inbound provider events; empty disables verification
+	// (development only).
+	PaymentsDriver        string
+	StripeAPIKey          string
+	PaymentsWebhookSecret string
+
+	// MarketingDriver is "log" (dev default, contact syncs are printed
+	// instead of executed) or "mailchimp". API credentials are per
+	// restaurant and configured through the API, not here.
+	MarketingDriver string
+
+	// BookingDriver is "log" (dev default, platform syncs are printed
+	// instead of executed). External booking platforms plug in as
+	// drivers; credentials are per restaurant and configured through
+	// the API, not here.
+	BookingDriver string
+
+	// ReviewsDriver is "log" (dev default, review pulls are printed
+	// instead of executed). Review platforms plug in as drivers;
+	// credentials are per restaurant and configured through the API.
+	ReviewsDriver string
+
+	// SentimentDriver is "keyword" (default: a local lexicon-based
+	// analyzer). A hosted language model plugs in as another driver.
+	SentimentDriver string
+
+	// WalletDriver is "log" (dev default, pass traffic is printed
+	// instead of executed). Apple and Google Wallet plug in as drivers
+	// once their signing certificates are provisioned.
+	WalletDriver string
+
+	// OCRDriver is "log" (dev default: invoice extraction returns
+	// nothing and every invoice is reviewed by hand). A hosted OCR
+	// service plugs in as another driver.
+	OCRDriver string
+
+	// CORS. Comma-separated lists; origins accept wildcard subdomains
+	// (https://*.example.com). The "*" default suits development only —
+	// production deployments should list their frontends explicitly,
+	// which also allows credentialed requests.
+	CORSAllowOrigins     string
+	CORSAllowMethods     string
+	CORSAllowHeaders     string
+	CORSAllowCredentials bool
+
+	// What happens when a user who still owns restaurants is deleted:
+	// "block" refuses until they are transferred or removed, "cascade"
+	// soft-deletes the restaurants along with the account.
+	UserDeletePolicy string
+
+	// Redis, used for rate limiting shared across replicas; empty means
+	// each replica keeps local counters.
+	RedisAddr     string
+	RedisPassword string
+
+	// Rate limiting, in requests per minute per caller.
+	RateLimitEnabled    bool
+	RateLimitDefaultRPM int
+	RateLimitOwnerRPM   int
+	RateLimitAdminRPM   int
+}
+
+// Load reads the configuration from the environment, falling back to
+// development defaults.
+func Load() *Config {
+	return &Config{
+		Port:       getEnv("PORT", "8080"),
+		DBDriver:   getEnv("DB_DRIVER", "mysql"),
+		DBHost:     getEnv("DB_HOST", "localhost"),
+		DBPort:     getEnv("DB_PORT", "3306"),
+		DBUser:     getEnv("DB_USER", "root"),
+		DBPassword: getEnv("DB_PASSWORD", ""),
+		DBName:     getEnv("DB_NAME", "wets_ma_bu"),
+		JWTSecret:  getEnv("JWT_SECRET", "dev-secret-change-me"),
+
+		MailDriver:     getEnv("MAIL_DRIVER", "log"),
+		MailFrom:       getEnv("MAIL_FROM", "no-reply@wets-ma-bu.local"),
+		SMTPHost:       getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:       getEnv("SMTP_PORT", "587"),
+		SMTPUser:       getEnv("SMTP_USER", ""),
+		SMTPPassword:   getEnv("SMTP_PASSWORD", ""),
+		SendGridAPIKey: getEnv("SENDGRID_API_KEY", ""),
+
+		PushDriver:   getEnv("PUSH_DRIVER", "log"),
+		FCMServerKey: getEnv("FCM_SERVER_KEY", ""),
+
+		EventsDriver: getEnv("EVENTS_DRIVER", "log"),
+		NATSURL:      getEnv("NATS_URL", "nats://localhost:4222"),
+
+		SearchDriver: getEnv("SEARCH_DRIVER", "db"),
+		ElasticURL:   getEnv("ELASTIC_URL", "http://localhost:9200"),
+		ElasticIndex: getEnv("ELASTIC_INDEX", "wets_ma_bu"),
+
+		MediaDir: getEnv("MEDIA_DIR", "media"),
+
+		S3Endpoint:          getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+		S3Region:            getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:            getEnv("S3_BUCKET", ""),
+		S3AccessKey:         getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:         getEnv("S3_SECRET_KEY", ""),
+		BackupEncryptionKey: getEnv("BACKUP_ENCRYPTION_KEY", ""),
+
+		SentryDSN: getEnv("SENTRY_DSN", ""),
+
+		TLSCertFile:     getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:      getEnv("TLS_KEY_FILE", ""),
+		AutoTLSEnabled:  getEnvBool("AUTO_TLS_ENABLED", false),
+		AutoTLSHosts:    getEnv("AUTO_TLS_HOSTS", ""),
+		AutoTLSCacheDir: getEnv("AUTO_TLS_CACHE_DIR", ".autocert-cache"),
+
+		DBMaxOpenConns:           getEnvInt("DB_MAX_OPEN_CONNS", 25),
+		DBMaxIdleConns:           getEnvInt("DB_MAX_IDLE_CONNS", 5),
+		DBConnMaxLifetimeMinutes: getEnvInt("DB_CONN_MAX_LIFETIME_MINUTES", 30),
+
+		ServerReadTimeoutSeconds:       getEnvInt("SERVER_READ_TIMEOUT_SECONDS", 30),
+		ServerReadHeaderTimeoutSeconds: getEnvInt("SERVER_READ_HEADER_TIMEOUT_SECONDS", 10),
+		ServerWriteTimeoutSeconds:      getEnvInt("SERVER_WRITE_TIMEOUT_SECONDS", 30),
+		ServerIdleTimeoutSeconds:       getEnvInt("SERVER_IDLE_TIMEOUT_SECONDS", 120),
+		ServerMaxHeaderBytes:           getEnvInt("SERVER_MAX_HEADER_BYTES", 1<<20),
+		HTTP2Enabled:                   getEnvBool("HTTP2_ENABLED", true),
+
+		DBRetryMaxAttempts: getEnvInt("DB_RETRY_MAX_ATTEMPTS", 3),
+		DBRetryBaseDelayMS: getEnvInt("DB_RETRY_BASE_DELAY_MS", 50),
+
+		BreakerFailureThreshold:   getEnvInt("BREAKER_FAILURE_THRESHOLD", 5),
+		BreakerOpenTimeoutSeconds: getEnvInt("BREAKER_OPEN_TIMEOUT_SECONDS", 30),
+
+		RequestTimeoutSeconds: getEnvInt("REQUEST_TIMEOUT_SECONDS", 30),
+
+		ShutdownTimeoutSeconds: getEnvInt("SHUTDOWN_TIMEOUT_SECONDS", 15),
+
+		LogLevel:     getEnv("LOG_LEVEL", "info"),
+		FeatureFlags: getEnv("FEATURE_FLAGS", ""),
+
+		PasswordHashDriver: getEnv("PASSWORD_HASH_DRIVER", "bcrypt"),
+		BcryptCost:         getEnvInt("BCRYPT_COST", 12),
+		Argon2MemoryKB:     getEnvInt("ARGON2_MEMORY_KB", 65536),
+		Argon2Time:         getEnvInt("ARGON2_TIME", 1),
+		Argon2Threads:      getEnvInt("ARGON2_THREADS", 4),
+
+		TaxRatePct: getEnvInt("TAX_RATE_PCT", 19),
+
+		PaymentsDriver:        getEnv("PAYMENTS_DRIVER", "log"),
+		StripeAPIKey:          getEnv("STRIPE_API_KEY", ""),
+		PaymentsWebhookSecret: getEnv("PAYMENTS_WEBHOOK_SECRET", ""),
+
+		MarketingDriver: getEnv("MARKETING_DRIVER", "log"),
+
+		BookingDriver: getEnv("BOOKING_DRIVER", "log"),
+
+		ReviewsDriver: getEnv("REVIEWS_DRIVER", "log"),
+
+		SentimentDriver: getEnv("SENTIMENT_DRIVER", "keyword"),
+
+		WalletDriver: getEnv("WALLET_DRIVER", "log"),
+
+		OCRDriver: getEnv("OCR_DRIVER", "log"),
+
+		CORSAllowOrigins:     getEnv("CORS_ALLOW_ORIGINS", "*"),
+		CORSAllowMethods:     getEnv("CORS_ALLOW_METHODS", "GET,POST,PUT,DELETE,OPTIONS"),
+		CORSAllowHeaders:     getEnv("CORS_ALLOW_HEADERS", "Origin,Content-Type,Accept,Authorization"),
+		CORSAllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", true),
+
+		UserDeletePolicy: getEnv("USER_DELETE_POLICY", "block"),
+
+		RedisAddr:     getEnv("REDIS_ADDR", ""),
+		RedisPassword: getEnv("REDIS_PASSWORD", ""),
+
+		RateLimitEnabled:    getEnvBool("RATE_LIMIT_ENABLED", true),
+		RateLimitDefaultRPM: getEnvInt("RATE_LIMIT_DEFAULT_RPM", 60),
+		RateLimitOwnerRPM:   getEnvInt("RATE_LIMIT_OWNER_RPM", 300),
+		RateLimitAdminRPM:   getEnvInt("RATE_LIMIT_ADMIN_RPM", 600),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if v := os.Getenv(key); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
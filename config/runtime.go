@@ -0,0 +1,87 @@
+package config
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Reloadable is the subset of settings that may change at runtime
+// without a restart. It is read on every request, so it is swapped
+// atomically as a unit instead of field by field.
+type Reloadable struct {
+	RateLimitEnabled    bool            `json:"rate_limit_enabled"`
+	RateLimitDefaultRPM int             `json:"rate_limit_default_rpm"`
+	RateLimitOwnerRPM   int             `json:"rate_limit_owner_rpm"`
+	RateLimitAdminRPM   int             `json:"rate_limit_admin_rpm"`
+	LogLevel            string          `json:"log_level"`
+	FeatureFlags        map[string]bool `json:"feature_flags"`
+}
+
+// FeatureEnabled reports whether a named feature flag is on.
+func (r *Reloadable) FeatureEnabled(name string) bool {
+	return r.FeatureFlags[name]
+}
+
+// Runtime hands out the current Reloadable snapshot and swaps in a
+// fresh one when the environment is re-read (SIGHUP or the admin
+// endpoint). Settings not in Reloadable still require a restart.
+type Runtime struct {
+	current atomic.Value
+
+	mu    sync.Mutex
+	hooks []func(*Reloadable)
+}
+
+// NewRuntime builds a Runtime seeded from cfg.
+func NewRuntime(cfg *Config) *Runtime {
+	r := &Runtime{}
+	r.current.Store(reloadableFrom(cfg))
+	return r
+}
+
+// Current returns the active snapshot. The returned value must be
+// treated as read-only.
+func (r *Runtime) Current() *Reloadable {
+	return r.current.Load().(*Reloadable)
+}
+
+// OnReload registers a hook invoked with each new snapshot, e.g. to
+// adjust the logger level.
+func (r *Runtime) OnReload(hook func(*Reloadable)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, hook)
+}
+
+// Reload re-reads the environment, swaps the reloadable settings and
+// notifies hooks. It returns the new snapshot.
+func (r *Runtime) Reload() *Reloadable {
+	fresh := reloadableFrom(Load())
+	r.current.Store(fresh)
+
+	r.mu.Lock()
+	hooks := r.hooks
+	r.mu.Unlock()
+	for _, hook := range hooks {
+		hook(fresh)
+	}
+	return fresh
+}
+
+func reloadableFrom(cfg *Config) *Reloadable {
+	flags := make(map[string]bool)
+	for _, name := range strings.Split(cfg.FeatureFlags, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			flags[name] = true
+		}
+	}
+	return &Reloadable{
+		RateLimitEnabled:    cfg.RateLimitEnabled,
+		RateLimitDefaultRPM: cfg.RateLimitDefaultRPM,
+		RateLimitOwnerRPM:   cfg.RateLimitOwnerRPM,
+		RateLimitAdminRPM:   cfg.RateLimitAdminRPM,
+		LogLevel:            cfg.LogLevel,
+		FeatureFlags:        flags,
+	}
+}
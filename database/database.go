@@ -0,0 +1,231 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/config"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// Connect opens the database described by cfg. MySQL is the production
+// driver; sqlite (file or :memory:) runs the API without any server for
+// local development and tests.
+func Connect(cfg *config.Config) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch cfg.DBDriver {
+	case "sqlite":
+		dialector = sqlite.Open(cfg.DBName)
+	case "mysql", "":
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort, cfg.DBName)
+		dialector = mysql.Open(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.DBDriver)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("getting sql.DB: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.DBConnMaxLifetimeMinutes) * time.Minute)
+
+	return db, nil
+}
+
+// ConnectTest opens a fresh in-memory sqlite database with all
+// migrations applied, for use in integration tests.
+func ConnectTest() (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	if err := Migrate(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// allModels lists every migrated model, in dependency order.
+func allModels() []interface{} {
+	return []interface{}{
+		&models.User{},
+		&models.Restaurant{},
+		&models.MenuItem{},
+		&models.Reservation{},
+		&models.Order{},
+		&models.OrderItem{},
+		&models.OutboxEvent{},
+		&models.Job{},
+		&models.DeviceToken{},
+		&models.Image{},
+		&models.Backup{},
+		&models.CustomerAddress{},
+		&models.CustomerPaymentMethod{},
+		&models.Refund{},
+		&models.Dispute{},
+		&models.AccountMapping{},
+		&models.Employee{},
+		&models.Shift{},
+		&models.PriceChange{},
+		&models.Experiment{},
+		&models.ExperimentVariant{},
+		&models.ExperimentExposure{},
+		&models.Segment{},
+		&models.MarketingAccount{},
+		&models.Consent{},
+		&models.Coupon{},
+		&models.BirthdayTemplate{},
+		&models.Event{},
+		&models.EventTier{},
+		&models.Ticket{},
+		&models.CateringRequest{},
+		&models.CateringQuoteLine{},
+		&models.PrivateRoom{},
+		&models.RoomBooking{},
+		&models.RoyaltyRule{},
+		&models.HealthInspection{},
+		&models.LicenseDocument{},
+		&models.ComplianceItem{},
+		&models.ComplianceCompletion{},
+		&models.SupplierInvoice{},
+		&models.SupplierInvoiceLine{},
+		&models.Ingredient{},
+		&models.RecipeItem{},
+		&models.StockMovement{},
+		&models.WasteEntry{},
+		&models.TemperatureUnit{},
+		&models.TemperatureReading{},
+		&models.TrainingRecord{},
+		&models.ShiftSwap{},
+		&models.ShiftChange{},
+		&models.TimePunch{},
+		&models.Approval{},
+		&models.Brand{},
+		&models.APIKey{},
+		&models.BookingAccount{},
+		&models.Review{},
+		&models.ReviewAccount{},
+		&models.FAQEntry{},
+		&models.LoyaltyPass{},
+		&models.Printer{},
+		&models.PrintJob{},
+		&models.HardwareDevice{},
+		&models.WebhookDelivery{},
+		&models.APIKeyUsage{},
+	}
+}
+
+// Migrate runs the gorm auto-migrations for all models. Before touching
+// the real schema it takes an advisory lock (so concurrently deploying
+// replicas do not race), dry-runs the models against a shadow database,
+// and rejects changes that would break the still-running old version.
+func Migrate(db *gorm.DB) error {
+	release, err := acquireMigrationLock(db)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := shadowDryRun(); err != nil {
+		return fmt.Errorf("migration dry-run failed: %w", err)
+	}
+	if err := preflight(db); err != nil {
+		return err
+	}
+	// Invalid roles must be fixed before AutoMigrate adds the check
+	// constraint, which validates existing rows.
+	if err := fixInvalidRoles(db); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(allModels()...); err != nil {
+		return err
+	}
+	if err := backfillPublicIDs(db); err != nil {
+		return err
+	}
+	return normalizeEmails(db)
+}
+
+// fixInvalidRoles resets roles outside the known set to "owner", the
+// least privileged one. Earlier versions accepted any string here.
+func fixInvalidRoles(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.User{}) {
+		return nil
+	}
+	result := db.Model(&models.User{}).
+		Where("role NOT IN ?", []models.Role{models.RoleOwner, models.RoleAdmin, models.RoleCustomer}).
+		Update("role", models.RoleOwner)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("migrations: reset %d invalid user role(s) to %q", result.RowsAffected, models.RoleOwner)
+	}
+	return nil
+}
+
+// normalizeEmails lowercases emails stored before normalization was
+// enforced at registration. An address whose lowercase form collides
+// with another account is left unchanged and flagged in the log for
+// manual merging — guessing which of the two accounts to keep is not a
+// migration's call.
+func normalizeEmails(db *gorm.DB) error {
+	var users []models.User
+	if err := db.Where("email != LOWER(email)").Find(&users).Error; err != nil {
+		return err
+	}
+	for i := range users {
+		lower := strings.ToLower(users[i].Email)
+		var clashes int64
+		if err := db.Model(&models.User{}).Where("email = ? AND id <> ?", lower, users[i].ID).Count(&clashes).Error; err != nil {
+			return err
+		}
+		if clashes > 0 {
+			log.Printf("migrations: user %d email %q collides with another account after lowercasing; left unchanged", users[i].ID, users[i].Email)
+			continue
+		}
+		if err := db.Model(&users[i]).Update("email", lower).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillPublicIDs assigns UUIDs to rows created before the public_id
+// columns existed. New rows get theirs from the BeforeCreate hooks.
+func backfillPublicIDs(db *gorm.DB) error {
+	var users []models.User
+	if err := db.Where("public_id IS NULL OR public_id = ''").Find(&users).Error; err != nil {
+		return err
+	}
+	for i := range users {
+		if err := db.Model(&users[i]).Update("public_id", models.NewUUID()).Error; err != nil {
+			return err
+		}
+	}
+
+	var restaurants []models.Restaurant
+	if err := db.Where("public_id IS NULL OR public_id = ''").Find(&restaurants).Error; err != nil {
+		return err
+	}
+	for i := range restaurants {
+		if err := db.Model(&restaurants[i]).Update("public_id", models.NewUUID()).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
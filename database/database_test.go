@@ -0,0 +1,32 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+func TestConnectTestMigratesAllModels(t *testing.T) {
+	db, err := ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	user := models.User{Name: "Test", Email: "test@example.com", Password: "x", Role: "owner"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+
+	restaurant := models.Restaurant{Name: "Testaurant", UserID: user.ID}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("creating restaurant: %v", err)
+	}
+
+	var count int64
+	if err := db.Model(&models.Restaurant{}).Where("user_id = ?", user.ID).Count(&count).Error; err != nil {
+		t.Fatalf("counting restaurants: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 restaurant, got %d", count)
+	}
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// migrationLockName is the advisory lock shared by all replicas.
+const migrationLockName = "wets_ma_bu_migrations"
+
+// acquireMigrationLock serializes migrations across replicas using a
+// MySQL advisory lock, so a rolling deploy cannot run AutoMigrate twice
+// concurrently. Other drivers (sqlite) are single-writer and skip it.
+func acquireMigrationLock(db *gorm.DB) (release func(), err error) {
+	if db.Dialector.Name() != "mysql" {
+		return func() {}, nil
+	}
+
+	var got int
+	if err := db.Raw("SELECT GET_LOCK(?, 60)", migrationLockName).Scan(&got).Error; err != nil {
+		return nil, fmt.Errorf("acquiring migration lock: %w", err)
+	}
+	if got != 1 {
+		return nil, fmt.Errorf("another replica is holding the migration lock")
+	}
+	return func() {
+		if err := db.Exec("SELECT RELEASE_LOCK(?)", migrationLockName).Error; err != nil {
+			log.Printf("migrations: releasing lock: %v", err)
+		}
+	}, nil
+}
+
+// shadowDryRun applies the full model set to a throwaway in-memory
+// database, catching malformed tags and model conflicts before anything
+// touches the production schema.
+func shadowDryRun() error {
+	shadow, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if sqlDB, err := shadow.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}()
+	return shadow.AutoMigrate(allModels()...)
+}
+
+// preflight compares each model against the live schema and rejects
+// changes that would break the previous version still serving traffic
+// during a rolling deploy. Columns that exist in the database but not in
+// the model are only warned about: AutoMigrate leaves them in place and
+// the old version keeps working.
+func preflight(db *gorm.DB) error {
+	for _, model := range allModels() {
+		if !db.Migrator().HasTable(model) {
+			continue // brand-new table, nothing running depends on it
+		}
+
+		s, err := schema.Parse(model, &sync.Map{}, db.NamingStrategy)
+		if err != nil {
+			return err
+		}
+		columns, err := db.Migrator().ColumnTypes(model)
+		if err != nil {
+			return err
+		}
+
+		existing := make(map[string]bool, len(columns))
+		for _, column := range columns {
+			existing[column.Name()] = true
+			if s.LookUpField(column.Name()) == nil {
+				log.Printf("migrations: %s.%s is no longer in the model; column left in place", s.Table, column.Name())
+			}
+		}
+
+		var rows int64
+		if err := db.Table(s.Table).Count(&rows).Error; err != nil {
+			return err
+		}
+		for _, field := range s.Fields {
+			if field.DBName == "" || existing[field.DBName] {
+				continue
+			}
+			// A NOT NULL column without a default cannot be added to a
+			// populated table, and even with one the old version's
+			// INSERTs (which omit it) would fail if it had no default.
+			if field.NotNull && !field.HasDefaultValue && rows > 0 {
+				return fmt.Errorf(
+					"migrations: adding NOT NULL column %s.%s without a default would break the running version; add a default or make it nullable first",
+					s.Table, field.DBName)
+			}
+		}
+	}
+	return nil
+}
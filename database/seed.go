@@ -0,0 +1,125 @@
+package database
+
+import (
+	"log"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// Seed populates demo accounts, restaurants, menus and a few orders and
+// reservations for local development. It is idempotent: rows are looked
+// up by their natural key (email, name) and only created when missing,
+// so running `go run . seed` repeatedly is safe.
+func Seed(db *gorm.DB) error {
+	password, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	admin := models.User{Email: "admin@example.com"}
+	if err := db.Where(&admin).Attrs(models.User{
+		Name:     "Platform Admin",
+		Password: string(password),
+		Role:     models.RoleAdmin,
+	}).FirstOrCreate(&admin).Error; err != nil {
+		return err
+	}
+
+	owner := models.User{Email: "maria@example.com"}
+	if err := db.Where(&owner).Attrs(models.User{
+		Name:     "Maria Soto",
+		Password: string(password),
+		Phone:    "+56 9 1234 5678",
+		Role:     models.RoleOwner,
+	}).FirstOrCreate(&owner).Error; err != nil {
+		return err
+	}
+
+	restaurants := []struct {
+		restaurant models.Restaurant
+		menu       []models.MenuItem
+	}{
+		{
+			restaurant: models.Restaurant{
+				Name:        "La Picada de Maria",
+				Description: "Traditional Chilean home cooking.",
+				Address:     "Av. Italia 1234, Santiago",
+				Phone:       "+56 2 2345 6789",
+				UserID:      owner.ID,
+			},
+			menu: []models.MenuItem{
+				{Name: "Pastel de Choclo", Description: "Corn pie with beef and chicken.", Price: 8900, Category: "mains"},
+				{Name: "Empanada de Pino", Description: "Baked empanada with beef, onion, egg and olive.", Price: 2500, Category: "starters"},
+				{Name: "Cazuela de Vacuno", Description: "Beef stew with potato, pumpkin and corn.", Price: 7500, Category: "mains"},
+				{Name: "Mote con Huesillo", Description: "Peach and husked-wheat drink.", Price: 2000, Category: "drinks"},
+			},
+		},
+		{
+			restaurant: models.Restaurant{
+				Name:        "Sushi Nikkei",
+				Description: "Peruvian-Japanese fusion bar.",
+				Address:     "Merced 456, Santiago",
+				Phone:       "+56 2 3456 7890",
+				UserID:      owner.ID,
+			},
+			menu: []models.MenuItem{
+				{Name: "Acevichado Roll", Description: "Shrimp roll topped with tuna and acevichada sauce.", Price: 9800, Category: "rolls"},
+				{Name: "Tiradito Nikkei", Description: "Thin-sliced salmon in passion fruit leche de tigre.", Price: 8200, Category: "starters"},
+				{Name: "Gyozas de Cerdo", Description: "Pan-fried pork dumplings, five units.", Price: 5400, Category: "starters"},
+			},
+		},
+	}
+
+	for _, entry := range restaurants {
+		restaurant := models.Restaurant{Name: entry.restaurant.Name, UserID: owner.ID}
+		if err := db.Where(&restaurant).Attrs(entry.restaurant).FirstOrCreate(&restaurant).Error; err != nil {
+			return err
+		}
+		for _, item := range entry.menu {
+			lookup := models.MenuItem{RestaurantID: restaurant.ID, Name: item.Name}
+			item.RestaurantID = restaurant.ID
+			if err := db.Where(&lookup).Attrs(item).FirstOrCreate(&lookup).Error; err != nil {
+				return err
+			}
+		}
+		if err := seedActivity(db, restaurant.ID); err != nil {
+			return err
+		}
+	}
+
+	log.Println("seed: demo data ready (admin@example.com / maria@example.com, password \"password123\")")
+	return nil
+}
+
+// seedActivity creates one demo reservation and one demo order per
+// restaurant so dashboards are not empty on first run.
+func seedActivity(db *gorm.DB, restaurantID uint) error {
+	reservation := models.Reservation{RestaurantID: restaurantID, CustomerName: "Pedro Pascal"}
+	if err := db.Where(&reservation).Attrs(models.Reservation{
+		CustomerEmail: "pedro@example.com",
+		PartySize:     4,
+		Date:          time.Now().Add(48 * time.Hour).Truncate(time.Hour),
+		Status:        models.ReservationPending,
+		Notes:         "Window table if possible.",
+	}).FirstOrCreate(&reservation).Error; err != nil {
+		return err
+	}
+
+	var item models.MenuItem
+	if err := db.Where("restaurant_id = ?", restaurantID).First(&item).Error; err != nil {
+		return err
+	}
+	order := models.Order{RestaurantID: restaurantID, CustomerName: "Ana Rojas"}
+	return db.Where(&order).Attrs(models.Order{
+		CustomerEmail: "ana@example.com",
+		Status:        models.OrderPending,
+		Total:         item.Price * 2,
+		Items: []models.OrderItem{
+			{MenuItemID: item.ID, Quantity: 2, Price: item.Price},
+		},
+	}).FirstOrCreate(&order).Error
+}
@@ -0,0 +1,102 @@
+package errreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Event is an error occurrence with enough request context to debug it.
+type Event struct {
+	Message   string `json:"message"`
+	Route     string `json:"route"`
+	Method    string `json:"method"`
+	RequestID string `json:"request_id"`
+	UserID    uint   `json:"user_id,omitempty"`
+	Stack     string `json:"stack,omitempty"`
+}
+
+// Reporter ships error events to an external sink.
+type Reporter interface {
+	Report(event Event)
+}
+
+// NoopReporter discards events; used when no DSN is configured.
+type NoopReporter struct{}
+
+// Report implements Reporter.
+func (NoopReporter) Report(Event) {}
+
+// SentryReporter posts events to a Sentry-compatible store endpoint
+// derived from the DSN (https://key@host/projectID).
+type SentryReporter struct {
+	storeURL string
+	authKey  string
+	client   *http.Client
+}
+
+// NewFromDSN parses a Sentry DSN. An empty DSN yields a NoopReporter.
+func NewFromDSN(dsn string) (Reporter, error) {
+	if dsn == "" {
+		return NoopReporter{}, nil
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parsing sentry DSN: %w", err)
+	}
+	if u.User == nil || u.Path == "" {
+		return nil, fmt.Errorf("sentry DSN is missing key or project id")
+	}
+	project := strings.Trim(u.Path, "/")
+	storeURL := fmt.Sprintf("%s://%s/api/%s/store/", u.Scheme, u.Host, project)
+	return &SentryReporter{
+		storeURL: storeURL,
+		authKey:  u.User.Username(),
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+// Report implements Reporter. Delivery is best-effort and asynchronous:
+// error reporting must never slow down or fail a request.
+func (r *SentryReporter) Report(event Event) {
+	go func() {
+		payload, err := json.Marshal(map[string]interface{}{
+			"message":   event.Message,
+			"level":     "error",
+			"platform":  "go",
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+			"tags": map[string]string{
+				"route":      event.Route,
+				"method":     event.Method,
+				"request_id": event.RequestID,
+			},
+			"extra": map[string]interface{}{
+				"user_id": event.UserID,
+				"stack":   event.Stack,
+			},
+		})
+		if err != nil {
+			return
+		}
+
+		req, err := http.NewRequest(http.MethodPost, r.storeURL, bytes.NewReader(payload))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Sentry-Auth",
+			fmt.Sprintf("Sentry sentry_version=7, sentry_key=%s, sentry_client=wets-ma-bu-api/1.0", r.authKey))
+
+		resp, err := r.client.Do(req)
+		if err != nil {
+			log.Printf("errreport: delivering event: %v", err)
+			return
+		}
+		resp.Body.Close()
+	}()
+}
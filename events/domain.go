@@ -0,0 +1,29 @@
+package events
+
+import (
+	"encoding/json"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// Domain event types. Names are dotted so they map directly onto broker
+// subjects (NATS) or topics (Kafka).
+const (
+	EventUserRegistered    = "user.registered"
+	EventRestaurantCreated = "restaurant.created"
+	EventOrderPlaced       = "order.created"
+	EventOrderRefunded     = "order.refunded"
+)
+
+// NewOutboxEvent builds an outbox row for the given event type, with the
+// payload marshalled to JSON.
+func NewOutboxEvent(eventType string, payload interface{}) (*models.OutboxEvent, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &models.OutboxEvent{
+		EventType: eventType,
+		Payload:   string(body),
+	}, nil
+}
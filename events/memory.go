@@ -0,0 +1,38 @@
+package events
+
+import "sync"
+
+// PublishedEvent is an event captured by a MemoryPublisher.
+type PublishedEvent struct {
+	EventType string
+	Payload   []byte
+}
+
+// MemoryPublisher records events in memory. It is meant for tests and
+// local experimentation, not production.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []PublishedEvent
+}
+
+// NewMemoryPublisher builds an empty MemoryPublisher.
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publish implements Publisher.
+func (p *MemoryPublisher) Publish(eventType string, payload []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, PublishedEvent{EventType: eventType, Payload: payload})
+	return nil
+}
+
+// Events returns a copy of everything published so far.
+func (p *MemoryPublisher) Events() []PublishedEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]PublishedEvent, len(p.events))
+	copy(out, p.events)
+	return out
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/config"
+)
+
+// NATSPublisher publishes events over the NATS text protocol. It speaks
+// just enough of the protocol to PUB (the same approach we take with
+// SendGrid and FCM: a thin client instead of a heavyweight SDK). The
+// connection is kept open across publishes and re-dialled once on error,
+// so a broker restart costs one failed attempt at most.
+type NATSPublisher struct {
+	addr string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewNATSPublisher builds a publisher for the given NATS URL
+// (nats://host:4222).
+func NewNATSPublisher(rawURL string) (*NATSPublisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing NATS URL: %w", err)
+	}
+	addr := u.Host
+	if addr == "" {
+		addr = rawURL
+	}
+	return &NATSPublisher{addr: addr}, nil
+}
+
+// Publish implements Publisher. The event type doubles as the subject.
+func (p *NATSPublisher) Publish(eventType string, payload []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.publish(eventType, payload); err != nil {
+		// The connection may have died since the last publish; re-dial
+		// once before giving up. The outbox relay retries on failure, so
+		// a returned error is not a lost event.
+		p.close()
+		return p.publish(eventType, payload)
+	}
+	return nil
+}
+
+func (p *NATSPublisher) publish(subject string, payload []byte) error {
+	if p.conn == nil {
+		if err := p.connect(); err != nil {
+			return err
+		}
+	}
+	p.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	if _, err := fmt.Fprintf(p.conn, "PUB %s %d\r\n", subject, len(payload)); err != nil {
+		return err
+	}
+	if _, err := p.conn.Write(append(payload, '\r', '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *NATSPublisher) connect() error {
+	conn, err := net.DialTimeout("tcp", p.addr, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	// The server greets with an INFO line; consume it and identify.
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := bufio.NewReader(conn).ReadString('\n'); err != nil {
+		conn.Close()
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "CONNECT {\"verbose\":false,\"name\":\"wets-ma-bu-api\"}\r\n"); err != nil {
+		conn.Close()
+		return err
+	}
+	p.conn = conn
+	return nil
+}
+
+func (p *NATSPublisher) close() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+}
+
+// NewFromConfig picks the publisher for the configured events driver.
+func NewFromConfig(cfg *config.Config) (Publisher, error) {
+	switch cfg.EventsDriver {
+	case "nats":
+		return NewNATSPublisher(cfg.NATSURL)
+	case "log", "":
+		return LogPublisher{}, nil
+	default:
+		return nil, fmt.Errorf("unknown events driver %q", cfg.EventsDriver)
+	}
+}
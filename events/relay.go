@@ -0,0 +1,103 @@
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// Publisher delivers a domain event to whatever transport is configured.
+type Publisher interface {
+	Publish(eventType string, payload []byte) error
+}
+
+// LogPublisher writes events to the process log. It is the default until
+// a real broker is configured.
+type LogPublisher struct{}
+
+// Publish implements Publisher.
+func (LogPublisher) Publish(eventType string, payload []byte) error {
+	log.Printf("event %s: %s", eventType, payload)
+	return nil
+}
+
+// Fanout publishes every event to each publisher in turn. The first
+// error is returned after all publishers ran, so the relay's retry
+// reaches everyone; publishers must therefore tolerate duplicates.
+type Fanout []Publisher
+
+// Publish implements Publisher.
+func (f Fanout) Publish(eventType string, payload []byte) error {
+	var first error
+	for _, p := range f {
+		if err := p.Publish(eventType, payload); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// OutboxStore is the slice of the outbox repository the relay needs.
+type OutboxStore interface {
+	GetUnpublished(limit, maxAttempts int) ([]models.OutboxEvent, error)
+	MarkPublished(id uint) error
+	IncrementAttempts(id uint) error
+}
+
+// Relay drains the outbox table in the background, publishing each event
+// at least once. Events whose attempts exceed maxAttempts are left in the
+// table for manual inspection.
+type Relay struct {
+	store       OutboxStore
+	publisher   Publisher
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+// NewRelay builds a Relay with sensible batch and retry defaults.
+func NewRelay(store OutboxStore, publisher Publisher, interval time.Duration) *Relay {
+	return &Relay{
+		store:       store,
+		publisher:   publisher,
+		interval:    interval,
+		batchSize:   100,
+		maxAttempts: 10,
+	}
+}
+
+// Start polls the outbox until ctx is cancelled.
+func (r *Relay) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.drainOnce()
+		}
+	}
+}
+
+func (r *Relay) drainOnce() {
+	events, err := r.store.GetUnpublished(r.batchSize, r.maxAttempts)
+	if err != nil {
+		log.Printf("outbox relay: fetching events: %v", err)
+		return
+	}
+	for _, event := range events {
+		if err := r.publisher.Publish(event.EventType, []byte(event.Payload)); err != nil {
+			log.Printf("outbox relay: publishing event %d: %v", event.ID, err)
+			if err := r.store.IncrementAttempts(event.ID); err != nil {
+				log.Printf("outbox relay: recording attempt for event %d: %v", event.ID, err)
+			}
+			continue
+		}
+		if err := r.store.MarkPublished(event.ID); err != nil {
+			log.Printf("outbox relay: marking event %d published: %v", event.ID, err)
+		}
+	}
+}
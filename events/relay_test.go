@@ -0,0 +1,67 @@
+package events
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+type fakeStore struct {
+	events    []models.OutboxEvent
+	published []uint
+	attempts  []uint
+}
+
+func (s *fakeStore) GetUnpublished(limit, maxAttempts int) ([]models.OutboxEvent, error) {
+	return s.events, nil
+}
+
+func (s *fakeStore) MarkPublished(id uint) error {
+	s.published = append(s.published, id)
+	return nil
+}
+
+func (s *fakeStore) IncrementAttempts(id uint) error {
+	s.attempts = append(s.attempts, id)
+	return nil
+}
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(string, []byte) error { return errors.New("broker down") }
+
+func TestRelayPublishesAndMarks(t *testing.T) {
+	store := &fakeStore{events: []models.OutboxEvent{
+		{EventType: EventOrderPlaced, Payload: `{"order_id":1}`},
+	}}
+	store.events[0].ID = 7
+	publisher := NewMemoryPublisher()
+
+	relay := NewRelay(store, publisher, time.Second)
+	relay.drainOnce()
+
+	got := publisher.Events()
+	if len(got) != 1 || got[0].EventType != EventOrderPlaced {
+		t.Fatalf("published events = %+v, want one %s event", got, EventOrderPlaced)
+	}
+	if len(store.published) != 1 || store.published[0] != 7 {
+		t.Fatalf("published ids = %v, want [7]", store.published)
+	}
+}
+
+func TestRelayRecordsFailedAttempts(t *testing.T) {
+	store := &fakeStore{events: []models.OutboxEvent{{EventType: EventUserRegistered}}}
+	store.events[0].ID = 3
+
+	relay := NewRelay(store, failingPublisher{}, time.Second)
+	relay.drainOnce()
+
+	if len(store.published) != 0 {
+		t.Fatalf("nothing should be marked published, got %v", store.published)
+	}
+	if len(store.attempts) != 1 || store.attempts[0] != 3 {
+		t.Fatalf("attempt ids = %v, want [3]", store.attempts)
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// AccountingHandler exposes bookkeeping exports and their
+// chart-of-account mappings.
+type AccountingHandler struct {
+	accountingService AccountingService
+	restaurantService RestaurantService
+}
+
+// NewAccountingHandler builds an AccountingHandler.
+func NewAccountingHandler(accountingService AccountingService, restaurantService RestaurantService) *AccountingHandler {
+	return &AccountingHandler{accountingService: accountingService, restaurantService: restaurantService}
+}
+
+// GetMappings handles GET /restaurants/:id/account-mappings.
+func (h *AccountingHandler) GetMappings(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	mapping, err := h.accountingService.Mappings(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", mapping))
+}
+
+// AccountMappingRequest is the payload for PUT
+// /restaurants/:id/account-mappings. Omitted fields keep their value.
+type AccountMappingRequest struct {
+	CashAccount    *string `json:"cash_account"`
+	SalesAccount   *string `json:"sales_account"`
+	TaxAccount     *string `json:"tax_account"`
+	TipsAccount    *string `json:"tips_account"`
+	RefundsAccount *string `json:"refunds_account"`
+}
+
+// UpdateMappings handles PUT /restaurants/:id/account-mappings.
+func (h *AccountingHandler) UpdateMappings(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req AccountMappingRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	mapping, err := h.accountingService.UpdateMappings(id,
+		req.CashAccount, req.SalesAccount, req.TaxAccount, req.TipsAccount, req.RefundsAccount)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("mappings updated", mapping))
+}
+
+// Export handles GET /restaurants/:id/accounting-export. format is csv
+// (default) or iif; from/to default to the last 30 days.
+func (h *AccountingHandler) Export(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -30)
+	if param := c.QueryParam("from"); param != "" {
+		if from, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "from must look like 2006-01-02")
+		}
+	}
+	if param := c.QueryParam("to"); param != "" {
+		if to, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "to must look like 2006-01-02")
+		}
+	}
+
+	body, filename, err := h.accountingService.Export(id, from, to, c.QueryParam("format"))
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", body)
+}
+
+// ownedRestaurant resolves the :id param and checks it belongs to the
+// caller.
+func (h *AccountingHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
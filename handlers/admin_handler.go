@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/backup"
+	"github.com/marc0cl/wets-ma-bu-api/config"
+	"github.com/marc0cl/wets-ma-bu-api/middleware"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// AdminHandler exposes platform-administration endpoints.
+type AdminHandler struct {
+	restaurantService RestaurantService
+	deprecations      *middleware.DeprecationRegistry
+	jobRepo           *repositories.JobRepository
+	backupRunner      *backup.Runner // nil when object storage is not configured
+	backupRepo        *repositories.BackupRepository
+	runtime           *config.Runtime
+}
+
+// NewAdminHandler builds an AdminHandler.
+func NewAdminHandler(restaurantService RestaurantService, deprecations *middleware.DeprecationRegistry, jobRepo *repositories.JobRepository, backupRunner *backup.Runner, backupRepo *repositories.BackupRepository, runtime *config.Runtime) *AdminHandler {
+	return &AdminHandler{
+		restaurantService: restaurantService,
+		deprecations:      deprecations,
+		jobRepo:           jobRepo,
+		backupRunner:      backupRunner,
+		backupRepo:        backupRepo,
+		runtime:           runtime,
+	}
+}
+
+// RuntimeConfig handles GET /admin/config. It shows the reloadable
+// settings currently in effect.
+func (h *AdminHandler) RuntimeConfig(c echo.Context) error {
+	return c.JSON(http.StatusOK, utils.OK("", h.runtime.Current()))
+}
+
+// ReloadConfig handles POST /admin/config/reload. It re-reads the
+// environment and applies the reloadable settings, same as SIGHUP.
+func (h *AdminHandler) ReloadConfig(c echo.Context) error {
+	return c.JSON(http.StatusOK, utils.OK("configuration reloaded", h.runtime.Reload()))
+}
+
+// CreateBackup handles POST /admin/backups. The dump and upload run in
+// the background; poll GET /admin/backups for completion.
+func (h *AdminHandler) CreateBackup(c echo.Context) error {
+	if h.backupRunner == nil {
+		return utils.Error(c, http.StatusServiceUnavailable, "object storage is not configured")
+	}
+	meta, err := h.backupRunner.Start()
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusAccepted, utils.OK("backup started", meta))
+}
+
+// ListBackups handles GET /admin/backups.
+func (h *AdminHandler) ListBackups(c echo.Context) error {
+	backups, err := h.backupRepo.List(50)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", backups))
+}
+
+// JobQueueHealth handles GET /admin/jobs. It reports queue depth by
+// status plus the most recent dead jobs.
+func (h *AdminHandler) JobQueueHealth(c echo.Context) error {
+	stats, err := h.jobRepo.Stats()
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	dead, err := h.jobRepo.ListDead(20)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"counts": stats,
+		"dead":   dead,
+	}))
+}
+
+// DeprecationUsage handles GET /admin/deprecations. It reports how often
+// each deprecated route is still being called.
+func (h *AdminHandler) DeprecationUsage(c echo.Context) error {
+	return c.JSON(http.StatusOK, utils.OK("", h.deprecations.Usage()))
+}
+
+// ListRestaurants handles GET /admin/restaurants. Supports ?page=, ?per_page=,
+// ?status= and ?owner= (name or email substring).
+func (h *AdminHandler) ListRestaurants(c echo.Context) error {
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	perPage, _ := strconv.Atoi(c.QueryParam("per_page"))
+
+	restaurants, total, err := h.restaurantService.ListRestaurants(repositories.RestaurantFilter{
+		Status:  c.QueryParam("status"),
+		Owner:   c.QueryParam("owner"),
+		Page:    page,
+		PerPage: perPage,
+	})
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"restaurants": restaurants,
+		"total":       total,
+	}))
+}
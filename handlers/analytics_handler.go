@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// AnalyticsHandler exposes the owner dashboard reports.
+type AnalyticsHandler struct {
+	analyticsService  AnalyticsService
+	restaurantService RestaurantService
+}
+
+// NewAnalyticsHandler builds an AnalyticsHandler.
+func NewAnalyticsHandler(analyticsService AnalyticsService, restaurantService RestaurantService) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsService: analyticsService, restaurantService: restaurantService}
+}
+
+// Revenue handles GET /restaurants/:id/revenue. granularity defaults to
+// day; from/to default to the last 30 days.
+func (h *AnalyticsHandler) Revenue(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return err
+	}
+
+	granularity := c.QueryParam("granularity")
+	if granularity == "" {
+		granularity = "day"
+	}
+
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -30)
+	if param := c.QueryParam("from"); param != "" {
+		if from, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "from must look like 2006-01-02")
+		}
+	}
+	if param := c.QueryParam("to"); param != "" {
+		if to, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "to must look like 2006-01-02")
+		}
+	}
+
+	buckets, err := h.analyticsService.Revenue(id, granularity, from, to)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"granularity": granularity,
+		"from":        from.Format("2006-01-02"),
+		"to":          to.Format("2006-01-02"),
+		"buckets":     buckets,
+	}))
+}
+
+// BusyHours handles GET /restaurants/:id/busy-hours. from/to default to
+// the last 90 days.
+func (h *AnalyticsHandler) BusyHours(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return err
+	}
+
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -90)
+	if param := c.QueryParam("from"); param != "" {
+		if from, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "from must look like 2006-01-02")
+		}
+	}
+	if param := c.QueryParam("to"); param != "" {
+		if to, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "to must look like 2006-01-02")
+		}
+	}
+
+	heatmap, err := h.analyticsService.BusyHours(id, from, to)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"from":    from.Format("2006-01-02"),
+		"to":      to.Format("2006-01-02"),
+		"heatmap": heatmap,
+	}))
+}
+
+// TurnTimes handles GET /restaurants/:id/turn-times. from/to default to
+// the last 90 days.
+func (h *AnalyticsHandler) TurnTimes(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return err
+	}
+
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -90)
+	if param := c.QueryParam("from"); param != "" {
+		if from, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "from must look like 2006-01-02")
+		}
+	}
+	if param := c.QueryParam("to"); param != "" {
+		if to, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "to must look like 2006-01-02")
+		}
+	}
+
+	report, err := h.analyticsService.TurnTimes(id, from, to)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"from":       from.Format("2006-01-02"),
+		"to":         to.Format("2006-01-02"),
+		"turn_times": report,
+	}))
+}
+
+// MenuEngineering handles GET /restaurants/:id/menu-engineering.
+// from/to default to the last 90 days.
+func (h *AnalyticsHandler) MenuEngineering(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return err
+	}
+
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -90)
+	if param := c.QueryParam("from"); param != "" {
+		if from, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "from must look like 2006-01-02")
+		}
+	}
+	if param := c.QueryParam("to"); param != "" {
+		if to, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "to must look like 2006-01-02")
+		}
+	}
+
+	report, err := h.analyticsService.MenuEngineering(id, from, to)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"from":  from.Format("2006-01-02"),
+		"to":    to.Format("2006-01-02"),
+		"items": report,
+	}))
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// APIKeyHandler manages publishable widget keys and serves the widget
+// endpoints they unlock.
+type APIKeyHandler struct {
+	apiKeyService      APIKeyService
+	menuService        MenuService
+	reservationService ReservationService
+	restaurantService  RestaurantService
+}
+
+// NewAPIKeyHandler builds an APIKeyHandler.
+func NewAPIKeyHandler(apiKeyService APIKeyService, menuService MenuService, reservationService ReservationService, restaurantService RestaurantService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService:      apiKeyService,
+		menuService:        menuService,
+		reservationService: reservationService,
+		restaurantService:  restaurantService,
+	}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *APIKeyHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ownedKey resolves the :id path parameter to a key whose restaurant
+// the caller owns.
+func (h *APIKeyHandler) ownedKey(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid key id")
+	}
+	key, err := h.apiKeyService.GetKeyByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(key.RestaurantID, claims.UserID); err != nil {
+		return 0, err
+	}
+	return key.ID, nil
+}
+
+// APIKeyRequest is the payload for issuing a key. An omitted plan means
+// free; billing_ref is the billing provider's subscription item for
+// metered usage, empty when the key is not billed.
+type APIKeyRequest struct {
+	Name            string `json:"name"`
+	Plan            string `json:"plan"`
+	AllowedOrigins  string `json:"allowed_origins"`
+	BillingRef      string `json:"billing_ref"`
+	RateLimitPerMin int    `json:"rate_limit_per_min"`
+}
+
+// IssueKey handles POST /restaurants/:id/api-keys.
+func (h *APIKeyHandler) IssueKey(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req APIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	key, err := h.apiKeyService.IssueKey(id, req.Name, req.Plan, req.AllowedOrigins, req.BillingRef, req.RateLimitPerMin)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("API key issued", key))
+}
+
+// GetKeys handles GET /restaurants/:id/api-keys.
+func (h *APIKeyHandler) GetKeys(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	keys, err := h.apiKeyService.Keys(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", keys))
+}
+
+// RevokeKey handles POST /api-keys/:id/revoke.
+func (h *APIKeyHandler) RevokeKey(c echo.Context) error {
+	id, err := h.ownedKey(c)
+	if err != nil {
+		return err
+	}
+	key, err := h.apiKeyService.RevokeKey(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("API key revoked", key))
+}
+
+// widgetKey authorizes a widget request for one feature. The key
+// travels in the "key" query parameter (script tags cannot set headers)
+// with X-API-Key as a fallback; the response is opened to the calling
+// origin so browsers accept it cross-site. Every admitted request is
+// counted against the plan's monthly quota, reported back in X-Quota-*
+// headers.
+func (h *APIKeyHandler) widgetKey(c echo.Context, feature string) (restaurantID uint, err error) {
+	value := c.QueryParam("key")
+	if value == "" {
+		value = c.Request().Header.Get("X-API-Key")
+	}
+	if value == "" {
+		return 0, utils.Error(c, http.StatusUnauthorized, "missing API key")
+	}
+
+	origin := c.Request().Header.Get(echo.HeaderOrigin)
+	key, err := h.apiKeyService.Authorize(value, origin)
+	if err != nil {
+		return 0, err
+	}
+	if err := h.apiKeyService.RequireFeature(key, feature); err != nil {
+		return 0, err
+	}
+	if !h.apiKeyService.Allow(key) {
+		return 0, utils.Error(c, http.StatusTooManyRequests, "rate limit exceeded")
+	}
+	quota, err := h.apiKeyService.Consume(key)
+	if err != nil {
+		return 0, err
+	}
+	if quota.Quota > 0 {
+		c.Response().Header().Set("X-Quota-Limit", strconv.FormatInt(quota.Quota, 10))
+		c.Response().Header().Set("X-Quota-Used", strconv.FormatInt(quota.Used, 10))
+		c.Response().Header().Set("X-Quota-Remaining", strconv.FormatInt(quota.Remaining, 10))
+	}
+	if quota.Exhausted() {
+		return 0, utils.Error(c, http.StatusTooManyRequests, "monthly request quota exhausted")
+	}
+
+	if origin != "" {
+		c.Response().Header().Set(echo.HeaderAccessControlAllowOrigin, origin)
+		c.Response().Header().Add(echo.HeaderVary, echo.HeaderOrigin)
+	} else {
+		c.Response().Header().Set(echo.HeaderAccessControlAllowOrigin, "*")
+	}
+	return key.RestaurantID, nil
+}
+
+// GetUsageReport handles GET /restaurants/:id/api-usage, the
+// month-by-month usage of every key the restaurant has issued.
+func (h *APIKeyHandler) GetUsageReport(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	rows, err := h.apiKeyService.UsageReport(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", rows))
+}
+
+// GetUsage handles GET /api-keys/:id/usage, the owner's view of a key's
+// standing against its plan.
+func (h *APIKeyHandler) GetUsage(c echo.Context) error {
+	id, err := h.ownedKey(c)
+	if err != nil {
+		return err
+	}
+	usage, err := h.apiKeyService.Usage(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", usage))
+}
+
+// WidgetMenu handles GET /widget/menu.
+func (h *APIKeyHandler) WidgetMenu(c echo.Context) error {
+	restaurantID, err := h.widgetKey(c, "menu")
+	if err != nil {
+		return err
+	}
+	items, err := h.menuService.GetMenuByRestaurantID(restaurantID)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", items))
+}
+
+// WidgetProfile handles GET /widget/profile.
+func (h *APIKeyHandler) WidgetProfile(c echo.Context) error {
+	restaurantID, err := h.widgetKey(c, "profile")
+	if err != nil {
+		return err
+	}
+	profile, err := h.apiKeyService.Profile(restaurantID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", profile))
+}
+
+// WidgetAvailability handles GET /widget/availability?date=YYYY-MM-DD.
+func (h *APIKeyHandler) WidgetAvailability(c echo.Context) error {
+	restaurantID, err := h.widgetKey(c, "availability")
+	if err != nil {
+		return err
+	}
+	day := c.QueryParam("date")
+	if day == "" {
+		return utils.Error(c, http.StatusBadRequest, "a date query parameter is required")
+	}
+	slots, err := h.reservationService.Availability(restaurantID, day)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"date":  day,
+		"slots": slots,
+	}))
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// ApprovalHandler exposes the manager's approvals inbox and its
+// decisions.
+type ApprovalHandler struct {
+	approvalService   ApprovalService
+	restaurantService RestaurantService
+}
+
+// NewApprovalHandler builds an ApprovalHandler.
+func NewApprovalHandler(approvalService ApprovalService, restaurantService RestaurantService) *ApprovalHandler {
+	return &ApprovalHandler{approvalService: approvalService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *ApprovalHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ownedApproval resolves the :id path parameter to an approval whose
+// restaurant the caller owns.
+func (h *ApprovalHandler) ownedApproval(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid approval id")
+	}
+	approval, err := h.approvalService.GetApprovalByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(approval.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return approval.ID, nil
+}
+
+// Inbox handles GET /restaurants/:id/approvals.
+func (h *ApprovalHandler) Inbox(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	approvals, err := h.approvalService.Inbox(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", approvals))
+}
+
+// Approve handles POST /approvals/:id/approve.
+func (h *ApprovalHandler) Approve(c echo.Context) error {
+	id, err := h.ownedApproval(c)
+	if err != nil {
+		return err
+	}
+	approval, err := h.approvalService.Approve(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("approved", approval))
+}
+
+// Reject handles POST /approvals/:id/reject.
+func (h *ApprovalHandler) Reject(c echo.Context) error {
+	id, err := h.ownedApproval(c)
+	if err != nil {
+		return err
+	}
+	approval, err := h.approvalService.Reject(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("rejected", approval))
+}
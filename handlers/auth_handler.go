@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// AuthHandler exposes registration and login endpoints.
+type AuthHandler struct {
+	authService AuthService
+}
+
+// NewAuthHandler builds an AuthHandler.
+func NewAuthHandler(authService AuthService) *AuthHandler {
+	return &AuthHandler{authService: authService}
+}
+
+// RegisterRequest is the payload for POST /auth/register.
+type RegisterRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8"`
+	Phone    string `json:"phone"`
+}
+
+// LoginRequest is the payload for POST /auth/login.
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// Register handles POST /auth/register.
+func (h *AuthHandler) Register(c echo.Context) error {
+	var req RegisterRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Name == "" || req.Email == "" || req.Password == "" {
+		return utils.Error(c, http.StatusBadRequest, "name, email and password are required")
+	}
+
+	user, err := h.authService.Register(req.Name, req.Email, req.Password, req.Phone)
+	if err != nil {
+		return err // mapped by HTTPErrorHandler
+	}
+	return c.JSON(http.StatusCreated, utils.OK("user registered", user))
+}
+
+// RegisterCustomer handles POST /auth/register/customer.
+func (h *AuthHandler) RegisterCustomer(c echo.Context) error {
+	var req RegisterRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Name == "" || req.Email == "" || req.Password == "" {
+		return utils.Error(c, http.StatusBadRequest, "name, email and password are required")
+	}
+
+	user, err := h.authService.RegisterCustomer(req.Name, req.Email, req.Password, req.Phone)
+	if err != nil {
+		return err // mapped by HTTPErrorHandler
+	}
+	return c.JSON(http.StatusCreated, utils.OK("customer registered", user))
+}
+
+// Login handles POST /auth/login.
+func (h *AuthHandler) Login(c echo.Context) error {
+	var req LoginRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	token, user, err := h.authService.Login(req.Email, req.Password)
+	if err != nil {
+		return utils.Error(c, http.StatusUnauthorized, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("login successful", map[string]interface{}{
+		"token": token,
+		"user":  user,
+	}))
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/services"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// fakeAuthService implements the AuthService interface for unit tests.
+type fakeAuthService struct {
+	registerErr error
+	loginErr    error
+}
+
+func (f *fakeAuthService) Register(name, email, password, phone string) (*models.User, error) {
+	if f.registerErr != nil {
+		return nil, f.registerErr
+	}
+	return &models.User{Name: name, Email: email, Role: "owner"}, nil
+}
+
+func (f *fakeAuthService) RegisterCustomer(name, email, password, phone string) (*models.User, error) {
+	if f.registerErr != nil {
+		return nil, f.registerErr
+	}
+	return &models.User{Name: name, Email: email, Role: models.RoleCustomer}, nil
+}
+
+func (f *fakeAuthService) Login(email, password string) (string, *models.User, error) {
+	if f.loginErr != nil {
+		return "", nil, f.loginErr
+	}
+	return "token-123", &models.User{Email: email, Role: "owner"}, nil
+}
+
+func doJSON(t *testing.T, handler echo.HandlerFunc, method, target, body string) (*httptest.ResponseRecorder, utils.Response) {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if err := handler(c); err != nil {
+		HTTPErrorHandler(err, c)
+	}
+	var resp utils.Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	return rec, resp
+}
+
+func TestRegisterSuccess(t *testing.T) {
+	h := NewAuthHandler(&fakeAuthService{})
+	rec, resp := doJSON(t, h.Register, http.MethodPost, "/auth/register",
+		`{"name":"Ana","email":"ana@example.com","password":"secret123"}`)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rec.Code)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success response, got %+v", resp)
+	}
+}
+
+func TestRegisterMissingFields(t *testing.T) {
+	h := NewAuthHandler(&fakeAuthService{})
+	rec, resp := doJSON(t, h.Register, http.MethodPost, "/auth/register", `{"email":"ana@example.com"}`)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	if resp.Success {
+		t.Fatalf("expected failure response, got %+v", resp)
+	}
+}
+
+func TestRegisterDuplicateEmail(t *testing.T) {
+	h := NewAuthHandler(&fakeAuthService{registerErr: services.ErrEmailTaken})
+	rec, resp := doJSON(t, h.Register, http.MethodPost, "/auth/register",
+		`{"name":"Ana","email":"ana@example.com","password":"secret123"}`)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+	if resp.Code != "email_taken" {
+		t.Fatalf("expected code email_taken, got %q", resp.Code)
+	}
+}
+
+func TestLoginSuccess(t *testing.T) {
+	h := NewAuthHandler(&fakeAuthService{})
+	rec, resp := doJSON(t, h.Login, http.MethodPost, "/auth/login",
+		`{"email":"ana@example.com","password":"secret123"}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	data, ok := resp.Data.(map[string]interface{})
+	if !ok || data["token"] != "token-123" {
+		t.Fatalf("expected token in response, got %+v", resp.Data)
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// BirthdayHandler exposes birthday reward endpoints.
+type BirthdayHandler struct {
+	birthdayService   BirthdayService
+	restaurantService RestaurantService
+}
+
+// NewBirthdayHandler builds a BirthdayHandler.
+func NewBirthdayHandler(birthdayService BirthdayService, restaurantService RestaurantService) *BirthdayHandler {
+	return &BirthdayHandler{birthdayService: birthdayService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *BirthdayHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetTemplate handles GET /restaurants/:id/birthday-template.
+func (h *BirthdayHandler) GetTemplate(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	template, err := h.birthdayService.Template(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", template))
+}
+
+// BirthdayTemplateRequest is the payload for
+// PUT /restaurants/:id/birthday-template.
+type BirthdayTemplateRequest struct {
+	Enabled     bool   `json:"enabled"`
+	Subject     string `json:"subject"`
+	Body        string `json:"body"`
+	DiscountPct int    `json:"discount_pct"`
+	ValidDays   int    `json:"valid_days"`
+}
+
+// UpdateTemplate handles PUT /restaurants/:id/birthday-template.
+func (h *BirthdayHandler) UpdateTemplate(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req BirthdayTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	template, err := h.birthdayService.UpdateTemplate(id, req.Enabled, req.Subject, req.Body, req.DiscountPct, req.ValidDays)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("birthday template updated", template))
+}
+
+// GetMyCoupons handles GET /me/coupons.
+func (h *BirthdayHandler) GetMyCoupons(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+
+	coupons, err := h.birthdayService.Coupons(claims.Email)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", coupons))
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/booking"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// BookingSyncHandler manages a restaurant's external booking platform
+// links and receives the platforms' webhooks.
+type BookingSyncHandler struct {
+	bookingSyncService BookingSyncService
+	restaurantService  RestaurantService
+}
+
+// NewBookingSyncHandler builds a BookingSyncHandler.
+func NewBookingSyncHandler(bookingSyncService BookingSyncService, restaurantService RestaurantService) *BookingSyncHandler {
+	return &BookingSyncHandler{bookingSyncService: bookingSyncService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *BookingSyncHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// BookingAccountRequest is the payload for connecting a platform.
+type BookingAccountRequest struct {
+	Platform        string `json:"platform"`
+	APIKey          string `json:"api_key"`
+	ExternalVenueID string `json:"external_venue_id"`
+}
+
+// Connect handles POST /restaurants/:id/booking-accounts.
+func (h *BookingSyncHandler) Connect(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req BookingAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	account, err := h.bookingSyncService.Connect(id, req.Platform, req.APIKey, req.ExternalVenueID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("booking platform connected", account))
+}
+
+// GetAccounts handles GET /restaurants/:id/booking-accounts.
+func (h *BookingSyncHandler) GetAccounts(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	accounts, err := h.bookingSyncService.Accounts(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", accounts))
+}
+
+// SyncNow handles POST /restaurants/:id/booking-accounts/sync, a manual
+// pass outside the periodic schedule.
+func (h *BookingSyncHandler) SyncNow(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	synced, err := h.bookingSyncService.SyncRestaurant(id, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("sync complete", map[string]interface{}{"synced": synced}))
+}
+
+// bookingWebhook is one booking pushed by a platform. Key identifies
+// the account it belongs to.
+type bookingWebhook struct {
+	Key           string    `json:"key"`
+	Ref           string    `json:"ref"`
+	CustomerName  string    `json:"customer_name"`
+	CustomerEmail string    `json:"customer_email"`
+	PartySize     int       `json:"party_size"`
+	Date          time.Time `json:"date"`
+	Status        string    `json:"status"`
+}
+
+// Webhook handles POST /webhooks/bookings.
+func (h *BookingSyncHandler) Webhook(c echo.Context) error {
+	var payload bookingWebhook
+	if err := c.Bind(&payload); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	if payload.Key == "" {
+		return utils.Error(c, http.StatusUnauthorized, "missing webhook key")
+	}
+
+	reservation, err := h.bookingSyncService.ImportWebhook(payload.Key, booking.ExternalReservation{
+		Ref:           payload.Ref,
+		CustomerName:  payload.CustomerName,
+		CustomerEmail: payload.CustomerEmail,
+		PartySize:     payload.PartySize,
+		Date:          payload.Date,
+		Status:        payload.Status,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("booking received", reservation))
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// BrandHandler manages an owner's brands and each restaurant's brand
+// membership and overrides.
+type BrandHandler struct {
+	brandService      BrandService
+	restaurantService RestaurantService
+}
+
+// NewBrandHandler builds a BrandHandler.
+func NewBrandHandler(brandService BrandService, restaurantService RestaurantService) *BrandHandler {
+	return &BrandHandler{brandService: brandService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *BrandHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// BrandRequest is the payload for creating or updating a brand.
+type BrandRequest struct {
+	Name          string `json:"name"`
+	LogoURL       string `json:"logo_url"`
+	WebhookURL    string `json:"webhook_url"`
+	EmailTemplate string `json:"email_template"`
+}
+
+// CreateBrand handles POST /brands.
+func (h *BrandHandler) CreateBrand(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+
+	var req BrandRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	brand, err := h.brandService.CreateBrand(claims.UserID, req.Name, req.LogoURL, req.WebhookURL, req.EmailTemplate)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("brand created", brand))
+}
+
+// GetBrands handles GET /brands.
+func (h *BrandHandler) GetBrands(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	brands, err := h.brandService.Brands(claims.UserID)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", brands))
+}
+
+// UpdateBrand handles PUT /brands/:id.
+func (h *BrandHandler) UpdateBrand(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid brand id")
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+
+	var req BrandRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	brand, err := h.brandService.UpdateBrand(uint(id), claims.UserID, req.Name, req.LogoURL, req.WebhookURL, req.EmailTemplate)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("brand updated", brand))
+}
+
+// BrandAssignmentRequest names the brand a restaurant joins; a zero
+// BrandID takes it back out.
+type BrandAssignmentRequest struct {
+	BrandID uint `json:"brand_id"`
+}
+
+// AssignRestaurant handles PUT /restaurants/:id/brand.
+func (h *BrandHandler) AssignRestaurant(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+
+	var req BrandAssignmentRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	restaurant, err := h.brandService.AssignRestaurant(id, req.BrandID, claims.UserID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("brand assigned", restaurant))
+}
+
+// BrandOverridesRequest is the payload for a restaurant's branding
+// overrides; empty fields fall back to the brand.
+type BrandOverridesRequest struct {
+	LogoURL       string `json:"logo_url"`
+	WebhookURL    string `json:"webhook_url"`
+	EmailTemplate string `json:"email_template"`
+}
+
+// SetOverrides handles PUT /restaurants/:id/brand-overrides.
+func (h *BrandHandler) SetOverrides(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+
+	var req BrandOverridesRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	restaurant, err := h.brandService.SetOverrides(id, claims.UserID, req.LogoURL, req.WebhookURL, req.EmailTemplate)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("overrides saved", restaurant))
+}
+
+// GetSettings handles GET /restaurants/:id/brand-settings.
+func (h *BrandHandler) GetSettings(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	settings, err := h.brandService.EffectiveSettings(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", settings))
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/services"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// CateringHandler exposes the catering inquiry pipeline.
+type CateringHandler struct {
+	cateringService   CateringService
+	restaurantService RestaurantService
+}
+
+// NewCateringHandler builds a CateringHandler.
+func NewCateringHandler(cateringService CateringService, restaurantService RestaurantService) *CateringHandler {
+	return &CateringHandler{cateringService: cateringService, restaurantService: restaurantService}
+}
+
+// ownedRequest resolves the :id path parameter to a catering request
+// whose restaurant the caller owns.
+func (h *CateringHandler) ownedRequest(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid catering request id")
+	}
+	request, err := h.cateringService.GetRequestByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(request.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return request.ID, nil
+}
+
+// CateringInquiryRequest is the payload for POST /restaurants/:id/catering-requests.
+type CateringInquiryRequest struct {
+	CustomerName  string  `json:"customer_name"`
+	CustomerEmail string  `json:"customer_email"`
+	EventDate     string  `json:"event_date"` // RFC3339
+	Headcount     int     `json:"headcount"`
+	Budget        float64 `json:"budget"`
+	Preferences   string  `json:"preferences"`
+}
+
+// CreateInquiry handles POST /restaurants/:id/catering-requests. It is
+// public: prospective customers inquire without an account.
+func (h *CateringHandler) CreateInquiry(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	var req CateringInquiryRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	eventDate, err := time.Parse(time.RFC3339, req.EventDate)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "event_date must be RFC3339")
+	}
+
+	request, err := h.cateringService.CreateInquiry(id, req.CustomerName, req.CustomerEmail, eventDate, req.Headcount, req.Budget, req.Preferences)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("catering request created", request))
+}
+
+// GetRequests handles GET /restaurants/:id/catering-requests.
+func (h *CateringHandler) GetRequests(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return err
+	}
+
+	requests, err := h.cateringService.GetRequestsByRestaurantID(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", requests))
+}
+
+// CateringQuoteRequest is the payload for PUT /catering-requests/:id/quote.
+type CateringQuoteRequest struct {
+	Lines []services.QuoteLineInput `json:"lines"`
+}
+
+// Quote handles PUT /catering-requests/:id/quote.
+func (h *CateringHandler) Quote(c echo.Context) error {
+	id, err := h.ownedRequest(c)
+	if err != nil {
+		return err
+	}
+
+	var req CateringQuoteRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	request, err := h.cateringService.Quote(id, req.Lines)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("quote saved", request))
+}
+
+// CateringStatusRequest is the payload for PUT /catering-requests/:id/status.
+type CateringStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// SetStatus handles PUT /catering-requests/:id/status.
+func (h *CateringHandler) SetStatus(c echo.Context) error {
+	id, err := h.ownedRequest(c)
+	if err != nil {
+		return err
+	}
+
+	var req CateringStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	request, err := h.cateringService.SetStatus(id, req.Status)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("catering request updated", request))
+}
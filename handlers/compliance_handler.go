@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// ComplianceHandler exposes recurring compliance obligations and their
+// sign-offs.
+type ComplianceHandler struct {
+	complianceService ComplianceService
+	restaurantService RestaurantService
+}
+
+// NewComplianceHandler builds a ComplianceHandler.
+func NewComplianceHandler(complianceService ComplianceService, restaurantService RestaurantService) *ComplianceHandler {
+	return &ComplianceHandler{complianceService: complianceService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *ComplianceHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ownedItem resolves the :id path parameter to a compliance item whose
+// restaurant the caller owns.
+func (h *ComplianceHandler) ownedItem(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid item id")
+	}
+	item, err := h.complianceService.GetItemByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(item.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return item.ID, nil
+}
+
+// ComplianceItemRequest is the payload for creating an obligation.
+type ComplianceItemRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	EmployeeID  uint   `json:"employee_id"`
+	DueAt       string `json:"due_at"` // "2006-01-02"
+	RecurDays   int    `json:"recur_days"`
+}
+
+// CreateItem handles POST /restaurants/:id/compliance-items.
+func (h *ComplianceHandler) CreateItem(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req ComplianceItemRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	dueAt, err := time.Parse("2006-01-02", req.DueAt)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "due_at must look like 2006-01-02")
+	}
+
+	item, err := h.complianceService.CreateItem(id, req.Name, req.Description, req.EmployeeID, dueAt, req.RecurDays)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("compliance item created", item))
+}
+
+// GetItems handles GET /restaurants/:id/compliance-items.
+func (h *ComplianceHandler) GetItems(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.complianceService.Items(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", items))
+}
+
+// CompletionRequest is the payload for signing off an item.
+type CompletionRequest struct {
+	EmployeeID uint   `json:"employee_id"`
+	Notes      string `json:"notes"`
+}
+
+// Complete handles POST /compliance-items/:id/completions.
+func (h *ComplianceHandler) Complete(c echo.Context) error {
+	id, err := h.ownedItem(c)
+	if err != nil {
+		return err
+	}
+
+	var req CompletionRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	item, err := h.complianceService.Complete(id, req.EmployeeID, req.Notes, time.Now())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("item signed off", item))
+}
+
+// GetItem handles GET /compliance-items/:id, completions included.
+func (h *ComplianceHandler) GetItem(c echo.Context) error {
+	id, err := h.ownedItem(c)
+	if err != nil {
+		return err
+	}
+	item, err := h.complianceService.GetItemByID(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", item))
+}
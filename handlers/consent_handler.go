@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// ConsentHandler exposes marketing consent endpoints.
+type ConsentHandler struct {
+	consentService ConsentService
+}
+
+// NewConsentHandler builds a ConsentHandler.
+func NewConsentHandler(consentService ConsentService) *ConsentHandler {
+	return &ConsentHandler{consentService: consentService}
+}
+
+// GetConsents handles GET /me/consents.
+func (h *ConsentHandler) GetConsents(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+
+	consents, err := h.consentService.Consents(claims.Email)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", consents))
+}
+
+// ConsentRequest is the payload for PUT /me/consents.
+type ConsentRequest struct {
+	Channel string `json:"channel"`
+	Granted bool   `json:"granted"`
+}
+
+// UpdateConsent handles PUT /me/consents.
+func (h *ConsentHandler) UpdateConsent(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+
+	var req ConsentRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	consent, err := h.consentService.Set(claims.Email, req.Channel, req.Granted, "account settings")
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("consent updated", consent))
+}
+
+// OptOutRequest is the payload for POST /marketing/opt-out.
+type OptOutRequest struct {
+	Email   string `json:"email"`
+	Channel string `json:"channel"`
+}
+
+// OptOut handles POST /marketing/opt-out. It is public so unsubscribe
+// links work without a login, and for the same reason it only ever
+// revokes consent — granting it for someone else's email must not be
+// possible here.
+func (h *ConsentHandler) OptOut(c echo.Context) error {
+	var req OptOutRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	if _, err := h.consentService.Set(req.Email, req.Channel, false, "unsubscribe link"); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("opted out", nil))
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// CustomerHandler exposes customer profile endpoints: saved addresses,
+// payment methods and order/reservation history. All routes are scoped
+// to the authenticated customer; there is no cross-account access.
+type CustomerHandler struct {
+	customerService CustomerService
+}
+
+// NewCustomerHandler builds a CustomerHandler.
+func NewCustomerHandler(customerService CustomerService) *CustomerHandler {
+	return &CustomerHandler{customerService: customerService}
+}
+
+// AddressRequest is the payload for POST /me/addresses.
+type AddressRequest struct {
+	Label      string `json:"label"`
+	Street     string `json:"street"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+// PaymentMethodRequest is the payload for POST /me/payment-methods. The
+// token comes from the payment provider's client SDK; card numbers are
+// never sent to this API.
+type PaymentMethodRequest struct {
+	Provider  string `json:"provider"`
+	Token     string `json:"token"`
+	Brand     string `json:"brand"`
+	Last4     string `json:"last4"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// ListAddresses handles GET /me/addresses.
+func (h *CustomerHandler) ListAddresses(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	addresses, err := h.customerService.Addresses(claims.UserID)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", addresses))
+}
+
+// CreateAddress handles POST /me/addresses.
+func (h *CustomerHandler) CreateAddress(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	var req AddressRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	address, err := h.customerService.AddAddress(claims.UserID, req.Label, req.Street, req.City, req.PostalCode, req.IsDefault)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("address saved", address))
+}
+
+// DeleteAddress handles DELETE /me/addresses/:id.
+func (h *CustomerHandler) DeleteAddress(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid address id")
+	}
+	if err := h.customerService.RemoveAddress(uint(id), claims.UserID); err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("address deleted", nil))
+}
+
+// ListPaymentMethods handles GET /me/payment-methods.
+func (h *CustomerHandler) ListPaymentMethods(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	methods, err := h.customerService.PaymentMethods(claims.UserID)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", methods))
+}
+
+// CreatePaymentMethod handles POST /me/payment-methods.
+func (h *CustomerHandler) CreatePaymentMethod(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	var req PaymentMethodRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	method, err := h.customerService.AddPaymentMethod(claims.UserID, req.Provider, req.Token, req.Brand, req.Last4, req.IsDefault)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("payment method saved", method))
+}
+
+// DeletePaymentMethod handles DELETE /me/payment-methods/:id.
+func (h *CustomerHandler) DeletePaymentMethod(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid payment method id")
+	}
+	if err := h.customerService.RemovePaymentMethod(uint(id), claims.UserID); err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("payment method deleted", nil))
+}
+
+// OrderHistory handles GET /me/orders.
+func (h *CustomerHandler) OrderHistory(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	orders, err := h.customerService.OrderHistory(claims.UserID)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", orders))
+}
+
+// ReservationHistory handles GET /me/reservations.
+func (h *CustomerHandler) ReservationHistory(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	reservations, err := h.customerService.ReservationHistory(claims.UserID)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", reservations))
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// PushService is the device/notification surface used by DeviceHandler
+// and the order/reservation handlers.
+type PushService interface {
+	RegisterDevice(userID uint, token, platform string, notifyOrders, notifyReservations bool) (*models.DeviceToken, error)
+	UnregisterDevice(userID uint, token string) error
+	NotifyUser(userID uint, event, title, body string, data map[string]string)
+}
+
+// DeviceHandler exposes push device registration endpoints.
+type DeviceHandler struct {
+	pushService PushService
+}
+
+// NewDeviceHandler builds a DeviceHandler.
+func NewDeviceHandler(pushService PushService) *DeviceHandler {
+	return &DeviceHandler{pushService: pushService}
+}
+
+// RegisterDeviceRequest is the payload for POST /me/devices.
+type RegisterDeviceRequest struct {
+	Token              string `json:"token"`
+	Platform           string `json:"platform"`
+	NotifyOrders       *bool  `json:"notify_orders"`
+	NotifyReservations *bool  `json:"notify_reservations"`
+}
+
+// RegisterDevice handles POST /me/devices.
+func (h *DeviceHandler) RegisterDevice(c echo.Context) error {
+	var req RegisterDeviceRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Token == "" || (req.Platform != "fcm" && req.Platform != "apns") {
+		return utils.Error(c, http.StatusBadRequest, "token and platform (fcm|apns) are required")
+	}
+
+	// Preferences default to on unless explicitly disabled.
+	notifyOrders := req.NotifyOrders == nil || *req.NotifyOrders
+	notifyReservations := req.NotifyReservations == nil || *req.NotifyReservations
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	device, err := h.pushService.RegisterDevice(claims.UserID, req.Token, req.Platform, notifyOrders, notifyReservations)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusCreated, utils.OK("device registered", device))
+}
+
+// UnregisterDevice handles DELETE /me/devices/:token.
+func (h *DeviceHandler) UnregisterDevice(c echo.Context) error {
+	token := c.Param("token")
+	if token == "" {
+		return utils.Error(c, http.StatusBadRequest, "token is required")
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if err := h.pushService.UnregisterDevice(claims.UserID, token); err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("device unregistered", nil))
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+	"github.com/marc0cl/wets-ma-bu-api/services"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// DisputeHandler exposes payment dispute endpoints.
+type DisputeHandler struct {
+	disputeService    DisputeService
+	orderService      OrderService
+	restaurantService RestaurantService
+	pushService       PushService
+	webhookSecret     string
+}
+
+// NewDisputeHandler builds a DisputeHandler.
+func NewDisputeHandler(disputeService DisputeService, orderService OrderService, restaurantService RestaurantService, pushService PushService, webhookSecret string) *DisputeHandler {
+	return &DisputeHandler{
+		disputeService:    disputeService,
+		orderService:      orderService,
+		restaurantService: restaurantService,
+		pushService:       pushService,
+		webhookSecret:     webhookSecret,
+	}
+}
+
+// disputeWebhook is the provider's dispute event payload. Amounts come
+// in cents and deadlines as unix timestamps.
+type disputeWebhook struct {
+	ProviderRef   string `json:"id"`
+	PaymentRef    string `json:"payment_intent"`
+	Amount        int64  `json:"amount"`
+	Reason        string `json:"reason"`
+	Status        string `json:"status"`
+	EvidenceDueBy *int64 `json:"evidence_due_by"`
+}
+
+// Webhook handles POST /webhooks/payments/disputes. The route is public
+// but the payload must carry a valid provider signature.
+func (h *DisputeHandler) Webhook(c echo.Context) error {
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	if !payments.VerifyWebhookSignature(h.webhookSecret, payload, c.Request().Header.Get("Stripe-Signature")) {
+		return utils.Error(c, http.StatusUnauthorized, "invalid webhook signature")
+	}
+
+	var event disputeWebhook
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	var dueBy *time.Time
+	if event.EvidenceDueBy != nil {
+		t := time.Unix(*event.EvidenceDueBy, 0).UTC()
+		dueBy = &t
+	}
+
+	dispute, err := h.disputeService.Ingest(event.ProviderRef, event.PaymentRef,
+		float64(event.Amount)/100, event.Reason, event.Status, dueBy)
+	if err != nil {
+		return err
+	}
+
+	if order, err := h.orderService.GetOrderByID(dispute.OrderID); err == nil {
+		if ownerID, name, err := h.restaurantService.RestaurantOwner(order.RestaurantID); err == nil {
+			h.pushService.NotifyUser(ownerID, services.PushEventOrder,
+				"Payment dispute", fmt.Sprintf("Order #%d at %s was disputed", order.ID, name),
+				map[string]string{"dispute_id": fmt.Sprint(dispute.ID)})
+		}
+	}
+	return c.JSON(http.StatusOK, utils.OK("dispute recorded", dispute))
+}
+
+// GetDisputes handles GET /restaurants/:id/disputes.
+func (h *DisputeHandler) GetDisputes(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return err
+	}
+
+	disputes, err := h.disputeService.Disputes(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", disputes))
+}
+
+// SubmitEvidence handles PUT /disputes/:id/evidence.
+func (h *DisputeHandler) SubmitEvidence(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid dispute id")
+	}
+
+	dispute, err := h.disputeService.GetDisputeByID(uint(id))
+	if err != nil {
+		return err
+	}
+	order, err := h.orderService.GetOrderByID(dispute.OrderID)
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(order.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	updated, err := h.disputeService.SubmitEvidence(uint(id))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("evidence submitted", updated))
+}
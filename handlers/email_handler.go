@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// EmailHandler lets owners preview and test-send their branded
+// transactional email templates.
+type EmailHandler struct {
+	emailService      EmailService
+	restaurantService RestaurantService
+}
+
+// NewEmailHandler builds an EmailHandler.
+func NewEmailHandler(emailService EmailService, restaurantService RestaurantService) *EmailHandler {
+	return &EmailHandler{emailService: emailService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *EmailHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetTemplates handles GET /restaurants/:id/email-templates.
+func (h *EmailHandler) GetTemplates(c echo.Context) error {
+	if _, err := h.ownedRestaurant(c); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", h.emailService.TemplateNames()))
+}
+
+// Preview handles GET /restaurants/:id/email-preview. It returns the
+// rendered HTML itself so the dashboard can drop it in an iframe.
+func (h *EmailHandler) Preview(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	html, err := h.emailService.Preview(id, c.QueryParam("template"))
+	if err != nil {
+		return err
+	}
+	return c.HTML(http.StatusOK, html)
+}
+
+// TestSendRequest is the payload for a test send.
+type TestSendRequest struct {
+	Template string `json:"template"`
+	To       string `json:"to"`
+}
+
+// TestSend handles POST /restaurants/:id/email-test.
+func (h *EmailHandler) TestSend(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req TestSendRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.emailService.TestSend(id, req.Template, req.To); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("test email queued", nil))
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// EmbedHandler serves the public menu feed websites embed: a JSON feed,
+// a self-contained HTML fragment, and an oEmbed card pointing at it.
+// All three are anonymous, CORS-open and cacheable.
+type EmbedHandler struct {
+	embedService EmbedService
+}
+
+// NewEmbedHandler builds an EmbedHandler.
+func NewEmbedHandler(embedService EmbedService) *EmbedHandler {
+	return &EmbedHandler{embedService: embedService}
+}
+
+// menuTemplate renders the HTML fragment variant. It is deliberately
+// plain markup with class hooks so the embedding site styles it.
+var menuTemplate = template.Must(template.New("menu").Parse(`<div class="wets-menu">
+<h2 class="wets-menu-title">{{.Restaurant}}</h2>
+{{range .Categories}}<section class="wets-menu-category">
+<h3>{{.Name}}</h3>
+<ul>
+{{range .Items}}<li><span class="wets-menu-item">{{.Name}}</span>{{if .Description}} <span class="wets-menu-description">{{.Description}}</span>{{end}} <span class="wets-menu-price">{{printf "%.0f" .Price}}</span></li>
+{{end}}</ul>
+</section>
+{{end}}</div>
+`))
+
+// corsOpen marks the response CORS-open so any embedding site can fetch it.
+func corsOpen(c echo.Context) {
+	c.Response().Header().Set(echo.HeaderAccessControlAllowOrigin, "*")
+}
+
+// MenuJSON handles GET /embed/:slug/menu.json. It answers conditional
+// requests with 304 so embedding sites can poll it cheaply.
+func (h *EmbedHandler) MenuJSON(c echo.Context) error {
+	feed, err := h.embedService.Menu(c.Param("slug"))
+	if err != nil {
+		return err
+	}
+	corsOpen(c)
+
+	etag := fmt.Sprintf(`"%x"`, fnv32(feed.Slug+feed.UpdatedAt.String()))
+	c.Response().Header().Set("ETag", etag)
+	if c.Request().Header.Get("If-None-Match") == etag {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.JSON(http.StatusOK, feed)
+}
+
+// MenuHTML handles GET /embed/:slug/menu.html.
+func (h *EmbedHandler) MenuHTML(c echo.Context) error {
+	feed, err := h.embedService.Menu(c.Param("slug"))
+	if err != nil {
+		return err
+	}
+	corsOpen(c)
+
+	var b strings.Builder
+	if err := menuTemplate.Execute(&b, feed); err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.HTML(http.StatusOK, b.String())
+}
+
+// JSONLD handles GET /embed/:slug/restaurant.jsonld, the schema.org
+// feed voice assistants and aggregators crawl for menu and hours data.
+func (h *EmbedHandler) JSONLD(c echo.Context) error {
+	restaurant, err := h.embedService.Restaurant(c.Param("slug"))
+	if err != nil {
+		return err
+	}
+	feed, err := h.embedService.Menu(c.Param("slug"))
+	if err != nil {
+		return err
+	}
+	corsOpen(c)
+
+	sections := make([]map[string]interface{}, 0, len(feed.Categories))
+	for _, category := range feed.Categories {
+		items := make([]map[string]interface{}, 0, len(category.Items))
+		for _, item := range category.Items {
+			entry := map[string]interface{}{
+				"@type": "MenuItem",
+				"name":  item.Name,
+				"offers": map[string]interface{}{
+					"@type":         "Offer",
+					"price":         fmt.Sprintf("%.0f", item.Price),
+					"priceCurrency": "CLP",
+				},
+			}
+			if item.Description != "" {
+				entry["description"] = item.Description
+			}
+			items = append(items, entry)
+		}
+		sections = append(sections, map[string]interface{}{
+			"@type":       "MenuSection",
+			"name":        category.Name,
+			"hasMenuItem": items,
+		})
+	}
+
+	base := c.Scheme() + "://" + c.Request().Host
+	doc := map[string]interface{}{
+		"@context": "https://schema.org",
+		"@type":    "Restaurant",
+		"@id":      fmt.Sprintf("%s/embed/%s/restaurant.jsonld", base, restaurant.Slug),
+		"name":     restaurant.Name,
+		// schema.org openingHours is day-range plus local times; the
+		// booking rules apply every day of the week.
+		"openingHours": fmt.Sprintf("Mo-Su %s-%s", restaurant.OpensAt, restaurant.ClosesAt),
+		"hasMenu": map[string]interface{}{
+			"@type":          "Menu",
+			"hasMenuSection": sections,
+		},
+	}
+	if restaurant.Description != "" {
+		doc["description"] = restaurant.Description
+	}
+	if restaurant.Address != "" {
+		doc["address"] = restaurant.Address
+	}
+	if restaurant.Phone != "" {
+		doc["telephone"] = restaurant.Phone
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "application/ld+json")
+	return c.JSON(http.StatusOK, doc)
+}
+
+// OEmbed handles GET /embed/:slug/oembed, the discovery card a CMS
+// fetches to turn a pasted link into an embedded menu.
+func (h *EmbedHandler) OEmbed(c echo.Context) error {
+	restaurant, err := h.embedService.Restaurant(c.Param("slug"))
+	if err != nil {
+		return err
+	}
+	corsOpen(c)
+
+	base := c.Scheme() + "://" + c.Request().Host
+	src := fmt.Sprintf("%s/embed/%s/menu.html", base, restaurant.Slug)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"version":       "1.0",
+		"type":          "rich",
+		"provider_name": "wets-ma-bu",
+		"title":         restaurant.Name,
+		"html":          fmt.Sprintf(`<iframe src="%s" width="400" height="600" frameborder="0" title="%s menu"></iframe>`, src, template.HTMLEscapeString(restaurant.Name)),
+		"width":         400,
+		"height":        600,
+	})
+}
+
+func fnv32(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
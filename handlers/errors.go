@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/services"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// HTTPErrorHandler is the central mapping from errors returned by
+// handlers to HTTP responses. Typed service errors map by Kind, echo's
+// own HTTPErrors keep their status, and anything else becomes a generic
+// 500 so internal details never reach clients. Register it as the echo
+// instance's HTTPErrorHandler.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status := http.StatusInternalServerError
+	code := ""
+	message := "internal server error"
+
+	var svcErr *services.Error
+	var httpErr *echo.HTTPError
+	switch {
+	case errors.As(err, &svcErr):
+		status = statusFor(svcErr.Kind)
+		code = svcErr.Code
+		message = svcErr.Message
+	case errors.As(err, &httpErr):
+		status = httpErr.Code
+		if msg, ok := httpErr.Message.(string); ok {
+			message = msg
+		} else {
+			message = http.StatusText(status)
+		}
+	}
+
+	if writeErr := utils.ErrorCode(c, status, code, message); writeErr != nil {
+		c.Logger().Error(writeErr)
+	}
+}
+
+func statusFor(kind services.Kind) int {
+	switch kind {
+	case services.KindNotFound:
+		return http.StatusNotFound
+	case services.KindConflict:
+		return http.StatusConflict
+	case services.KindForbidden:
+		return http.StatusForbidden
+	case services.KindValidation:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/services"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// EventHandler exposes ticketed event endpoints.
+type EventHandler struct {
+	eventService      EventService
+	restaurantService RestaurantService
+}
+
+// NewEventHandler builds an EventHandler.
+func NewEventHandler(eventService EventService, restaurantService RestaurantService) *EventHandler {
+	return &EventHandler{eventService: eventService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *EventHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ownedEvent resolves the :id path parameter to an event whose
+// restaurant the caller owns.
+func (h *EventHandler) ownedEvent(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid event id")
+	}
+	event, err := h.eventService.GetEventByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(event.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return event.ID, nil
+}
+
+// CreateEventRequest is the payload for POST /restaurants/:id/events.
+type CreateEventRequest struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	StartsAt    string               `json:"starts_at"` // RFC3339
+	Tiers       []services.TierInput `json:"tiers"`
+}
+
+// CreateEvent handles POST /restaurants/:id/events.
+func (h *EventHandler) CreateEvent(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req CreateEventRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "starts_at must be RFC3339")
+	}
+
+	event, err := h.eventService.CreateEvent(id, req.Name, req.Description, startsAt, req.Tiers)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("event created", event))
+}
+
+// GetEvents handles GET /restaurants/:id/events. It is public so
+// customers can browse what is coming up before buying.
+func (h *EventHandler) GetEvents(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+	events, err := h.eventService.GetEventsByRestaurantID(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", events))
+}
+
+// PurchaseTicketRequest is the payload for POST /events/:id/tickets.
+type PurchaseTicketRequest struct {
+	TierID uint `json:"tier_id"`
+}
+
+// PurchaseTicket handles POST /events/:id/tickets. Buying requires a
+// customer account with a saved payment method; the response carries
+// the code the ticket's QR encodes.
+func (h *EventHandler) PurchaseTicket(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid event id")
+	}
+
+	var req PurchaseTicketRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	ticket, err := h.eventService.PurchaseTicket(uint(id), req.TierID, claims.UserID, claims.Email)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("ticket purchased", ticket))
+}
+
+// GetTickets handles GET /events/:id/tickets for the event's owner.
+func (h *EventHandler) GetTickets(c echo.Context) error {
+	id, err := h.ownedEvent(c)
+	if err != nil {
+		return err
+	}
+	tickets, err := h.eventService.Tickets(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", tickets))
+}
+
+// GetMyTickets handles GET /me/tickets.
+func (h *EventHandler) GetMyTickets(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	tickets, err := h.eventService.MyTickets(claims.UserID)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", tickets))
+}
+
+// CheckInRequest is the payload for POST /events/:id/check-in.
+type CheckInRequest struct {
+	Code string `json:"code"`
+}
+
+// CheckIn handles POST /events/:id/check-in: staff scan the ticket QR
+// at the door and post its code here.
+func (h *EventHandler) CheckIn(c echo.Context) error {
+	id, err := h.ownedEvent(c)
+	if err != nil {
+		return err
+	}
+
+	var req CheckInRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Code == "" {
+		return utils.Error(c, http.StatusBadRequest, "a ticket code is required")
+	}
+
+	ticket, err := h.eventService.CheckIn(id, req.Code)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("ticket checked in", ticket))
+}
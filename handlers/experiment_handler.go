@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/services"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// ExperimentHandler exposes menu A/B testing endpoints.
+type ExperimentHandler struct {
+	experimentService ExperimentService
+	restaurantService RestaurantService
+}
+
+// NewExperimentHandler builds an ExperimentHandler.
+func NewExperimentHandler(experimentService ExperimentService, restaurantService RestaurantService) *ExperimentHandler {
+	return &ExperimentHandler{experimentService: experimentService, restaurantService: restaurantService}
+}
+
+// visitorCookie names the anonymous id that ties a visitor's menu views
+// to their later orders.
+const visitorCookie = "visitor_id"
+
+// visitorID returns the caller's anonymous visitor id, minting one and
+// setting a year-long cookie on first sight.
+func visitorID(c echo.Context) string {
+	if cookie, err := c.Cookie(visitorCookie); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+	id := models.NewUUID()
+	c.SetCookie(&http.Cookie{
+		Name:     visitorCookie,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   365 * 24 * 60 * 60,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return id
+}
+
+// ExperimentRequest is the payload for POST /restaurants/:id/experiments.
+type ExperimentRequest struct {
+	Name     string                  `json:"name"`
+	Variants []services.VariantInput `json:"variants"`
+}
+
+// CreateExperiment handles POST /restaurants/:id/experiments.
+func (h *ExperimentHandler) CreateExperiment(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return err
+	}
+
+	var req ExperimentRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	experiment, err := h.experimentService.CreateExperiment(id, req.Name, req.Variants)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("experiment created", experiment))
+}
+
+// GetExperiments handles GET /restaurants/:id/experiments.
+func (h *ExperimentHandler) GetExperiments(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return err
+	}
+
+	experiments, err := h.experimentService.Experiments(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", experiments))
+}
+
+// ExperimentStatusRequest is the payload for PUT /experiments/:id/status.
+type ExperimentStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// SetStatus handles PUT /experiments/:id/status.
+func (h *ExperimentHandler) SetStatus(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid experiment id")
+	}
+
+	experiment, err := h.experimentService.GetExperimentByID(uint(id))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(experiment.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	var req ExperimentStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	updated, err := h.experimentService.SetStatus(uint(id), req.Status)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("experiment updated", updated))
+}
+
+// GetResults handles GET /experiments/:id/results.
+func (h *ExperimentHandler) GetResults(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid experiment id")
+	}
+
+	experiment, err := h.experimentService.GetExperimentByID(uint(id))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(experiment.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	results, err := h.experimentService.Results(uint(id))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"experiment_id": experiment.ID,
+		"name":          experiment.Name,
+		"status":        experiment.Status,
+		"variants":      results,
+	}))
+}
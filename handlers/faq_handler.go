@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// FAQHandler lets owners maintain FAQ entries and exposes the public
+// ask endpoint that powers chat widgets.
+type FAQHandler struct {
+	faqService        FAQService
+	restaurantService RestaurantService
+}
+
+// NewFAQHandler builds a FAQHandler.
+func NewFAQHandler(faqService FAQService, restaurantService RestaurantService) *FAQHandler {
+	return &FAQHandler{faqService: faqService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *FAQHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// FAQRequest is the payload for creating a FAQ entry.
+type FAQRequest struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// AddEntry handles POST /restaurants/:id/faqs.
+func (h *FAQHandler) AddEntry(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req FAQRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	entry, err := h.faqService.AddEntry(id, req.Question, req.Answer)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("FAQ entry created", entry))
+}
+
+// GetEntries handles GET /restaurants/:id/faqs.
+func (h *FAQHandler) GetEntries(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	entries, err := h.faqService.Entries(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", entries))
+}
+
+// ownedEntry resolves the :id path parameter to a FAQ entry owned by
+// the caller.
+func (h *FAQHandler) ownedEntry(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid FAQ entry id")
+	}
+
+	entry, err := h.faqService.GetEntryByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(entry.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return uint(id), nil
+}
+
+// DeleteEntry handles DELETE /faqs/:id.
+func (h *FAQHandler) DeleteEntry(c echo.Context) error {
+	id, err := h.ownedEntry(c)
+	if err != nil {
+		return err
+	}
+	if err := h.faqService.DeleteEntry(id); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("FAQ entry deleted", nil))
+}
+
+// AskRequest is the payload for a visitor question.
+type AskRequest struct {
+	Question string `json:"question"`
+}
+
+// Ask handles POST /restaurants/:id/ask. It is public: the questions
+// come from chat widgets on restaurant websites.
+func (h *FAQHandler) Ask(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	var req AskRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	answer, err := h.faqService.Ask(id, req.Question)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", answer))
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// HardwareHandler lets owners manage their restaurant's hardware fleet
+// and serves the heartbeat the devices themselves poll.
+type HardwareHandler struct {
+	hardwareService   HardwareService
+	restaurantService RestaurantService
+}
+
+// NewHardwareHandler builds a HardwareHandler.
+func NewHardwareHandler(hardwareService HardwareService, restaurantService RestaurantService) *HardwareHandler {
+	return &HardwareHandler{hardwareService: hardwareService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *HardwareHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// DeviceRequest is the payload for registering a hardware device.
+type DeviceRequest struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Config string `json:"config"`
+}
+
+// RegisterDevice handles POST /restaurants/:id/devices. The response
+// carries the device token; the owner types it into the device once.
+func (h *HardwareHandler) RegisterDevice(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req DeviceRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	device, err := h.hardwareService.RegisterDevice(id, req.Name, req.Kind, req.Config)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("device registered", device))
+}
+
+// GetDevices handles GET /restaurants/:id/devices. LastSeenAt in the
+// response is what the dashboard reads to flag a quiet device.
+func (h *HardwareHandler) GetDevices(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	devices, err := h.hardwareService.Devices(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", devices))
+}
+
+// ownedDevice resolves the :id path parameter to a hardware device
+// owned by the caller.
+func (h *HardwareHandler) ownedDevice(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid device id")
+	}
+
+	device, err := h.hardwareService.GetDeviceByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(device.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return uint(id), nil
+}
+
+// DeviceUpdateRequest is the payload for updating a device. Omitted
+// fields are left untouched.
+type DeviceUpdateRequest struct {
+	Active *bool   `json:"active"`
+	Config *string `json:"config"`
+}
+
+// UpdateDevice handles PUT /devices/:id: remote deactivation and config
+// pushes both land here; the device picks them up on its next
+// heartbeat.
+func (h *HardwareHandler) UpdateDevice(c echo.Context) error {
+	id, err := h.ownedDevice(c)
+	if err != nil {
+		return err
+	}
+
+	var req DeviceUpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	device, err := h.hardwareService.UpdateDevice(id, req.Active, req.Config)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("device updated", device))
+}
+
+// Heartbeat handles POST /devices/heartbeat. Devices poll it with their
+// token; the response carries their active flag and configuration.
+func (h *HardwareHandler) Heartbeat(c echo.Context) error {
+	token := c.Request().Header.Get("X-Device-Token")
+	if token == "" {
+		return utils.Error(c, http.StatusUnauthorized, "missing device token")
+	}
+	device, err := h.hardwareService.Heartbeat(token)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", device))
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// HealthHandler exposes health inspection records and the public score
+// badge.
+type HealthHandler struct {
+	healthService     HealthService
+	restaurantService RestaurantService
+}
+
+// NewHealthHandler builds a HealthHandler.
+func NewHealthHandler(healthService HealthService, restaurantService RestaurantService) *HealthHandler {
+	return &HealthHandler{healthService: healthService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *HealthHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// CreateInspection handles POST /restaurants/:id/inspections. The body
+// is a multipart form with date ("2006-01-02"), score, notes and an
+// optional report file.
+func (h *HealthHandler) CreateInspection(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	date, err := time.Parse("2006-01-02", c.FormValue("date"))
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "date must look like 2006-01-02")
+	}
+	score, err := strconv.Atoi(c.FormValue("score"))
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "a numeric score is required")
+	}
+
+	var report io.Reader
+	var filename string
+	if file, err := c.FormFile("report"); err == nil {
+		src, err := file.Open()
+		if err != nil {
+			return utils.Error(c, http.StatusBadRequest, "could not read upload")
+		}
+		defer src.Close()
+		report = src
+		filename = file.Filename
+	}
+
+	inspection, err := h.healthService.CreateInspection(id, date, score, c.FormValue("notes"), filename, report)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("inspection recorded", inspection))
+}
+
+// GetInspections handles GET /restaurants/:id/inspections.
+func (h *HealthHandler) GetInspections(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	inspections, err := h.healthService.Inspections(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", inspections))
+}
+
+// GetReport handles GET /inspections/:id/report, serving the attached
+// report file.
+func (h *HealthHandler) GetReport(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid inspection id")
+	}
+
+	inspection, err := h.healthService.GetInspectionByID(uint(id))
+	if err != nil {
+		return err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(inspection.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	path, err := h.healthService.ReportPath(inspection.ID)
+	if err != nil {
+		return err
+	}
+	return c.File(path)
+}
+
+// HealthBadgeRequest is the payload for PUT /restaurants/:id/health-badge.
+type HealthBadgeRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// SetBadge handles PUT /restaurants/:id/health-badge.
+func (h *HealthHandler) SetBadge(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req HealthBadgeRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.healthService.SetBadge(id, req.Enabled); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("health badge updated", map[string]interface{}{"enabled": req.Enabled}))
+}
+
+// GetBadge handles GET /restaurants/:id/health-badge. It is public;
+// restaurants opt in before anything is exposed.
+func (h *HealthHandler) GetBadge(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+	badge, err := h.healthService.Badge(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", badge))
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// ImageHandler exposes image upload and retrieval endpoints.
+type ImageHandler struct {
+	imageService      ImageService
+	restaurantService RestaurantService
+	menuService       MenuService
+}
+
+// NewImageHandler builds an ImageHandler.
+func NewImageHandler(imageService ImageService, restaurantService RestaurantService, menuService MenuService) *ImageHandler {
+	return &ImageHandler{imageService: imageService, restaurantService: restaurantService, menuService: menuService}
+}
+
+// UploadRestaurantImage handles POST /restaurants/:id/image.
+func (h *ImageHandler) UploadRestaurantImage(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return err
+	}
+	return h.upload(c, models.ImageEntityRestaurant, id)
+}
+
+// UploadMenuItemImage handles POST /menu-items/:id/image.
+func (h *ImageHandler) UploadMenuItemImage(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid menu item id")
+	}
+
+	item, err := h.menuService.GetMenuItemByID(uint(id))
+	if err != nil {
+		return err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(item.RestaurantID, claims.UserID); err != nil {
+		return err
+	}
+	return h.upload(c, models.ImageEntityMenuItem, uint(id))
+}
+
+// GetRestaurantImage handles GET /restaurants/:id/image. It is public.
+func (h *ImageHandler) GetRestaurantImage(c echo.Context) error {
+	return h.get(c, models.ImageEntityRestaurant)
+}
+
+// GetMenuItemImage handles GET /menu-items/:id/image. It is public.
+func (h *ImageHandler) GetMenuItemImage(c echo.Context) error {
+	return h.get(c, models.ImageEntityMenuItem)
+}
+
+func (h *ImageHandler) upload(c echo.Context, entityType string, entityID uint) error {
+	file, err := c.FormFile("image")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "an image file field is required")
+	}
+	src, err := file.Open()
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "could not read upload")
+	}
+	defer src.Close()
+
+	image, err := h.imageService.SaveUpload(entityType, entityID, file.Filename, src)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusAccepted, utils.OK("image queued for processing", h.imageResponse(image)))
+}
+
+func (h *ImageHandler) get(c echo.Context, entityType string) error {
+	var id uint
+	if entityType == models.ImageEntityRestaurant {
+		resolved, err := h.restaurantService.ResolveID(c.Param("id"))
+		if err != nil {
+			return err
+		}
+		id = resolved
+	} else {
+		n, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			return utils.Error(c, http.StatusBadRequest, "invalid id")
+		}
+		id = uint(n)
+	}
+	image, err := h.imageService.GetLatest(entityType, id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", h.imageResponse(image)))
+}
+
+func (h *ImageHandler) imageResponse(image *models.Image) map[string]interface{} {
+	return map[string]interface{}{
+		"id":       image.ID,
+		"status":   image.Status,
+		"variants": h.imageService.VariantURLs(image),
+	}
+}
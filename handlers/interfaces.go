@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"io"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/booking"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/services"
+)
+
+// The handlers depend on these interfaces rather than the concrete
+// service structs, so unit tests can substitute fakes without a database.
+// The concrete types in the services package satisfy them.
+
+// AuthService is the authentication surface used by AuthHandler.
+type AuthService interface {
+	Register(name, email, password, phone string) (*models.User, error)
+	RegisterCustomer(name, email, password, phone string) (*models.User, error)
+	Login(email, password string) (string, *models.User, error)
+}
+
+// UserService is the user-profile surface used by UserHandler.
+type UserService interface {
+	ResolveID(param string) (uint, error)
+	GetUserByID(id uint) (*models.User, error)
+	UpdateUser(id uint, name, phone *string, birthday *time.Time) (*models.User, error)
+	UpdateRole(id uint, role models.Role) (*models.User, error)
+	DeleteUser(id, callerID uint) error
+}
+
+// RestaurantService is the restaurant surface used by several handlers.
+type RestaurantService interface {
+	ResolveID(param string) (uint, error)
+	CreateRestaurant(userID uint, name, description, address, phone, timezone string) (*models.Restaurant, error)
+	GetRestaurantByID(id, userID uint) (*models.Restaurant, error)
+	RestaurantOwner(id uint) (ownerID uint, name string, err error)
+	GetRestaurantsByUserID(userID uint) ([]models.Restaurant, error)
+	UpdateRestaurant(id, callerID uint, name, description, address, phone, timezone *string) (*models.Restaurant, error)
+	UpdateBookingRules(id, callerID uint, seats *int, opensAt, closesAt *string, turnMinutes *int, noShowFee, depositAmount *float64, depositMinParty, depositRefundHours *int) (*models.Restaurant, error)
+	DeleteRestaurant(id, callerID uint) error
+	CountByUserID(userID uint) (int64, error)
+	ListRestaurants(filter repositories.RestaurantFilter) ([]models.Restaurant, int64, error)
+}
+
+// MenuService is the menu surface used by MenuHandler.
+type MenuService interface {
+	CreateMenuItem(restaurantID uint, name, description, category string, price, cost float64) (*models.MenuItem, error)
+	GetMenuItemByID(id uint) (*models.MenuItem, error)
+	GetMenuByRestaurantID(restaurantID uint) ([]models.MenuItem, error)
+	UpdateMenuItem(id, callerID uint, name, description, category string, price, cost float64, available bool) (*models.MenuItem, error)
+	PriceHistory(id uint) ([]models.PriceChange, error)
+	DeleteMenuItem(id uint) error
+}
+
+// ReservationService is the reservation surface used by ReservationHandler.
+type ReservationService interface {
+	CreateReservation(restaurantID, customerID uint, customerName, customerEmail string, partySize int, date time.Time, notes string) (*models.Reservation, error)
+	Availability(restaurantID uint, day string) ([]services.AvailabilitySlot, error)
+	GetReservationByID(id uint) (*models.Reservation, error)
+	GetReservationsByRestaurantID(restaurantID uint) ([]models.Reservation, error)
+	UpdateReservationStatus(id uint, status string, table int) (*models.Reservation, error)
+	CancelReservation(id, customerID uint) (*models.Reservation, error)
+	CountPendingByOwner(userID uint) (int64, error)
+	NoShowCount(customerEmail string) (int64, error)
+}
+
+// OrderService is the order surface used by OrderHandler.
+type OrderService interface {
+	CreateOrder(restaurantID, customerID uint, customerName, customerEmail string, lines []services.OrderLine, tip float64) (*models.Order, error)
+	GetOrderByID(id uint) (*models.Order, error)
+	GetOrdersByRestaurantID(restaurantID uint) ([]models.Order, error)
+	UpdateOrderStatus(id uint, status string) (*models.Order, error)
+	RefundOrder(id uint, amount float64, reason string) (*models.Refund, error)
+	GetRefundsByOrderID(orderID uint) ([]models.Refund, error)
+	KitchenTicket(id uint) (*services.KitchenTicket, error)
+}
+
+// AnalyticsService is the dashboard surface used by AnalyticsHandler.
+type AnalyticsService interface {
+	Revenue(restaurantID uint, granularity string, from, to time.Time) ([]services.RevenueBucket, error)
+	BusyHours(restaurantID uint, from, to time.Time) (*services.Heatmap, error)
+	MenuEngineering(restaurantID uint, from, to time.Time) ([]services.MenuEngineeringItem, error)
+	TurnTimes(restaurantID uint, from, to time.Time) (*services.TurnTimeReport, error)
+}
+
+// ExperimentService is the A/B testing surface used by ExperimentHandler
+// and, for variant serving, by MenuHandler and OrderHandler.
+type ExperimentService interface {
+	CreateExperiment(restaurantID uint, name string, variants []services.VariantInput) (*models.Experiment, error)
+	GetExperimentByID(id uint) (*models.Experiment, error)
+	Experiments(restaurantID uint) ([]models.Experiment, error)
+	SetStatus(id uint, status string) (*models.Experiment, error)
+	ApplyMenu(restaurantID uint, visitorID string, items []models.MenuItem) ([]models.MenuItem, error)
+	RecordConversion(restaurantID uint, visitorID string, orderValue float64) error
+	Results(id uint) ([]services.VariantResult, error)
+}
+
+// SegmentService is the marketing-audience surface used by SegmentHandler.
+type SegmentService interface {
+	CreateSegment(restaurantID uint, name string, minOrders int, minSpend float64, windowDays int) (*models.Segment, error)
+	GetSegmentByID(id uint) (*models.Segment, error)
+	Segments(restaurantID uint) ([]models.Segment, error)
+	Members(id uint) ([]services.SegmentMember, error)
+	ExportMembers(id uint) (body []byte, filename string, err error)
+	DeleteSegment(id uint) error
+}
+
+// ConsentService is the marketing-consent surface used by ConsentHandler.
+type ConsentService interface {
+	Set(email, channel string, granted bool, source string) (*models.Consent, error)
+	Consents(email string) ([]models.Consent, error)
+	Allowed(email, channel string) (bool, error)
+}
+
+// BirthdayService is the birthday reward surface used by BirthdayHandler.
+type BirthdayService interface {
+	Template(restaurantID uint) (*models.BirthdayTemplate, error)
+	UpdateTemplate(restaurantID uint, enabled bool, subject, body string, discountPct, validDays int) (*models.BirthdayTemplate, error)
+	Coupons(email string) ([]models.Coupon, error)
+}
+
+// EventService is the ticketed-events surface used by EventHandler.
+type EventService interface {
+	CreateEvent(restaurantID uint, name, description string, startsAt time.Time, tiers []services.TierInput) (*models.Event, error)
+	GetEventByID(id uint) (*models.Event, error)
+	GetEventsByRestaurantID(restaurantID uint) ([]models.Event, error)
+	PurchaseTicket(eventID, tierID, customerID uint, customerEmail string) (*models.Ticket, error)
+	Tickets(eventID uint) ([]models.Ticket, error)
+	MyTickets(customerID uint) ([]models.Ticket, error)
+	CheckIn(eventID uint, code string) (*models.Ticket, error)
+}
+
+// CateringService is the catering pipeline surface used by CateringHandler.
+type CateringService interface {
+	CreateInquiry(restaurantID uint, name, email string, eventDate time.Time, headcount int, budget float64, preferences string) (*models.CateringRequest, error)
+	GetRequestByID(id uint) (*models.CateringRequest, error)
+	GetRequestsByRestaurantID(restaurantID uint) ([]models.CateringRequest, error)
+	Quote(id uint, lines []services.QuoteLineInput) (*models.CateringRequest, error)
+	SetStatus(id uint, status string) (*models.CateringRequest, error)
+}
+
+// RoomService is the private dining surface used by RoomHandler.
+type RoomService interface {
+	CreateRoom(restaurantID uint, name string, capacity int, minSpend float64, opensAt, closesAt string) (*models.PrivateRoom, error)
+	GetRoomByID(id uint) (*models.PrivateRoom, error)
+	GetRoomsByRestaurantID(restaurantID uint) ([]models.PrivateRoom, error)
+	DeleteRoom(id uint) error
+	BookRoom(roomID, customerID uint, customerName, customerEmail string, partySize int, startsAt, endsAt time.Time, notes string) (*models.RoomBooking, error)
+	GetBookingByID(id uint) (*models.RoomBooking, error)
+	GetBookingsByRestaurantID(restaurantID uint) ([]models.RoomBooking, error)
+	UpdateBookingStatus(id uint, status string) (*models.RoomBooking, error)
+}
+
+// RoyaltyService is the franchise reporting surface used by RoyaltyHandler.
+type RoyaltyService interface {
+	SetRule(restaurantID uint, pct float64) (*models.RoyaltyRule, error)
+	Rule(restaurantID uint) (*models.RoyaltyRule, error)
+	Report(ownerID uint, from, to time.Time) ([]services.RoyaltyRow, error)
+	ExportReport(ownerID uint, from, to time.Time, format string) ([]byte, string, error)
+}
+
+// HealthService is the inspection surface used by HealthHandler.
+type HealthService interface {
+	CreateInspection(restaurantID uint, date time.Time, score int, notes, filename string, report io.Reader) (*models.HealthInspection, error)
+	GetInspectionByID(id uint) (*models.HealthInspection, error)
+	Inspections(restaurantID uint) ([]models.HealthInspection, error)
+	ReportPath(id uint) (string, error)
+	SetBadge(restaurantID uint, enabled bool) error
+	Badge(restaurantID uint) (*services.HealthBadge, error)
+}
+
+// LicenseService is the compliance-document surface used by LicenseHandler.
+type LicenseService interface {
+	UploadDocument(restaurantID uint, kind string, expiresAt time.Time, filename string, file io.Reader) (*models.LicenseDocument, error)
+	GetDocumentByID(id uint) (*models.LicenseDocument, error)
+	Documents(restaurantID uint) ([]models.LicenseDocument, error)
+	DocumentPath(id uint) (string, error)
+	ComplianceOverview(now time.Time) ([]services.ComplianceRow, error)
+}
+
+// ComplianceService is the obligation-tracking surface used by
+// ComplianceHandler.
+type ComplianceService interface {
+	CreateItem(restaurantID uint, name, description string, employeeID uint, dueAt time.Time, recurDays int) (*models.ComplianceItem, error)
+	GetItemByID(id uint) (*models.ComplianceItem, error)
+	Items(restaurantID uint) ([]models.ComplianceItem, error)
+	Complete(itemID, employeeID uint, notes string, now time.Time) (*models.ComplianceItem, error)
+}
+
+// InvoiceService is the supplier-invoice surface used by InvoiceHandler.
+type InvoiceService interface {
+	Upload(restaurantID uint, filename string, file io.Reader) (*models.SupplierInvoice, error)
+	GetInvoiceByID(id uint) (*models.SupplierInvoice, error)
+	Invoices(restaurantID uint) ([]models.SupplierInvoice, error)
+	InvoicePath(id uint) (string, error)
+	Review(id uint, supplier string, total float64, lines []services.InvoiceLineInput) (*models.SupplierInvoice, error)
+	Confirm(id uint) (*models.SupplierInvoice, error)
+}
+
+// RecipeService is the food-costing surface used by RecipeHandler.
+type RecipeService interface {
+	CreateIngredient(restaurantID uint, name, unit string, costPerUnit float64) (*models.Ingredient, error)
+	GetIngredientByID(id uint) (*models.Ingredient, error)
+	Ingredients(restaurantID uint) ([]models.Ingredient, error)
+	UpdateIngredient(id uint, name, unit string, costPerUnit float64) (*models.Ingredient, error)
+	SetRecipe(menuItemID uint, inputs []services.RecipeItemInput) (*services.FoodCost, error)
+	Recipe(menuItemID uint) (*services.FoodCost, error)
+	FoodCosts(restaurantID uint) ([]services.FoodCost, error)
+}
+
+// StockService is the inventory-level surface used by RecipeHandler.
+type StockService interface {
+	Adjust(ingredientID uint, delta float64, reason string) (*models.Ingredient, error)
+	Movements(ingredientID uint) ([]models.StockMovement, error)
+}
+
+// WasteService is the loss-logging surface used by WasteHandler and
+// the food-cost report.
+type WasteService interface {
+	LogWaste(restaurantID, ingredientID uint, description string, quantity float64, reason string, cost float64) (*models.WasteEntry, error)
+	Entries(restaurantID uint, from, to time.Time) ([]models.WasteEntry, error)
+	Report(restaurantID uint, from, to time.Time) (*services.WasteReport, error)
+}
+
+// TemperatureService is the HACCP monitoring surface used by
+// TemperatureHandler.
+type TemperatureService interface {
+	CreateUnit(restaurantID uint, name string, minC, maxC float64) (*models.TemperatureUnit, error)
+	GetUnitByID(id uint) (*models.TemperatureUnit, error)
+	Units(restaurantID uint) ([]models.TemperatureUnit, error)
+	RecordManual(unitID uint, celsius float64) (*models.TemperatureReading, error)
+	RecordSensor(sensorKey string, celsius float64) (*models.TemperatureReading, error)
+	Readings(unitID uint, from, to time.Time) ([]models.TemperatureReading, error)
+	ExportLog(restaurantID uint, from, to time.Time, format string) ([]byte, string, error)
+}
+
+// TrainingService is the certification surface used by
+// TrainingHandler.
+type TrainingService interface {
+	AddRecord(employeeID uint, name string, completedAt, expiresAt time.Time) (*models.TrainingRecord, error)
+	Records(employeeID uint) ([]models.TrainingRecord, error)
+	Status(restaurantID uint, now time.Time) ([]services.TrainingStatusRow, error)
+}
+
+// SwapService is the shift-swap surface used by SwapHandler.
+type SwapService interface {
+	RequestSwap(shiftID, toEmployeeID uint, note string) (*models.ShiftSwap, error)
+	GetSwapByID(id uint) (*models.ShiftSwap, error)
+	Swaps(restaurantID uint) ([]models.ShiftSwap, error)
+	Approve(id uint) (*models.ShiftSwap, error)
+	Reject(id uint) (*models.ShiftSwap, error)
+	Changes(restaurantID uint) ([]models.ShiftChange, error)
+}
+
+// TimeclockService is the punch-clock surface used by
+// TimeclockHandler.
+type TimeclockService interface {
+	SetPin(employeeID uint, pin string) error
+	ClockIn(employeeID uint) (*models.TimePunch, error)
+	ClockOut(employeeID uint) (*models.TimePunch, error)
+	PunchByPin(restaurantID uint, pin string, lat, lng float64) (*models.TimePunch, error)
+	SetGeofence(restaurantID uint, lat, lng float64, meters int) (*models.Restaurant, error)
+	Timesheet(restaurantID uint, from, to time.Time) ([]services.TimesheetRow, error)
+	Variance(restaurantID uint, from, to time.Time) ([]services.VarianceRow, error)
+}
+
+// ApprovalService is the decision surface used by ApprovalHandler.
+type ApprovalService interface {
+	GetApprovalByID(id uint) (*models.Approval, error)
+	Inbox(restaurantID uint) ([]models.Approval, error)
+	Approve(id uint) (*models.Approval, error)
+	Reject(id uint) (*models.Approval, error)
+}
+
+// BrandService is the multi-brand surface used by BrandHandler.
+type BrandService interface {
+	CreateBrand(userID uint, name, logoURL, webhookURL, emailTemplate string) (*models.Brand, error)
+	Brands(userID uint) ([]models.Brand, error)
+	UpdateBrand(id, callerID uint, name, logoURL, webhookURL, emailTemplate string) (*models.Brand, error)
+	AssignRestaurant(restaurantID, brandID, callerID uint) (*models.Restaurant, error)
+	SetOverrides(restaurantID, callerID uint, logoURL, webhookURL, emailTemplate string) (*models.Restaurant, error)
+	EffectiveSettings(restaurantID uint) (*services.BrandSettings, error)
+}
+
+// APIKeyService is the publishable-key surface used by APIKeyHandler.
+type APIKeyService interface {
+	IssueKey(restaurantID uint, name, plan, allowedOrigins, billingRef string, rateLimitPerMin int) (*models.APIKey, error)
+	Keys(restaurantID uint) ([]models.APIKey, error)
+	GetKeyByID(id uint) (*models.APIKey, error)
+	RevokeKey(id uint) (*models.APIKey, error)
+	Authorize(value, origin string) (*models.APIKey, error)
+	Allow(key *models.APIKey) bool
+	Consume(key *models.APIKey) (*services.QuotaState, error)
+	RequireFeature(key *models.APIKey, feature string) error
+	Usage(id uint) (*services.QuotaState, error)
+	UsageReport(restaurantID uint) ([]repositories.KeyUsageRow, error)
+	Profile(restaurantID uint) (*services.WidgetProfile, error)
+}
+
+// EmbedService is the public feed surface used by EmbedHandler.
+type EmbedService interface {
+	Menu(slug string) (*services.EmbedMenu, error)
+	Restaurant(slug string) (*models.Restaurant, error)
+}
+
+// BookingSyncService is the platform-sync surface used by
+// BookingSyncHandler.
+type BookingSyncService interface {
+	Connect(restaurantID uint, platform, apiKey, venueID string) (*models.BookingAccount, error)
+	Accounts(restaurantID uint) ([]models.BookingAccount, error)
+	SyncRestaurant(restaurantID uint, now time.Time) (int, error)
+	ImportWebhook(webhookKey string, ext booking.ExternalReservation) (*models.Reservation, error)
+}
+
+// ReviewService is the review surface used by ReviewHandler.
+type ReviewService interface {
+	AddReview(restaurantID uint, author string, rating int, comment string) (*models.Review, error)
+	Reviews(restaurantID uint) ([]models.Review, error)
+	Ratings(restaurantID uint) (*services.RatingSummary, error)
+	Trends(restaurantID uint, from, to time.Time) (*services.ReviewTrends, error)
+	Connect(restaurantID uint, platform, apiKey, externalID string) (*models.ReviewAccount, error)
+	Accounts(restaurantID uint) ([]models.ReviewAccount, error)
+	SyncRestaurant(restaurantID uint, now time.Time) (int, error)
+}
+
+// FAQService is the knowledge-base surface used by FAQHandler.
+type FAQService interface {
+	AddEntry(restaurantID uint, question, answer string) (*models.FAQEntry, error)
+	Entries(restaurantID uint) ([]models.FAQEntry, error)
+	GetEntryByID(id uint) (*models.FAQEntry, error)
+	DeleteEntry(id uint) error
+	Ask(restaurantID uint, question string) (*services.ChatAnswer, error)
+}
+
+// LoyaltyService is the wallet-pass surface used by LoyaltyHandler.
+type LoyaltyService interface {
+	IssuePass(userID, restaurantID uint, platform string) (*models.LoyaltyPass, error)
+	Passes(userID uint) ([]models.LoyaltyPass, error)
+	PassForPlatform(serial, authToken string) (*services.PassState, error)
+}
+
+// EmailService is the template-rendering surface used by EmailHandler.
+type EmailService interface {
+	TemplateNames() []string
+	Preview(restaurantID uint, name string) (string, error)
+	TestSend(restaurantID uint, name, to string) error
+}
+
+// PrinterService is the print-queue surface used by PrinterHandler.
+type PrinterService interface {
+	RegisterPrinter(restaurantID uint, name, format string) (*models.Printer, error)
+	Printers(restaurantID uint) ([]models.Printer, error)
+	Jobs(restaurantID uint) ([]models.PrintJob, error)
+	PullJobs(key string) (*models.Printer, []models.PrintJob, error)
+	AckJob(key string, jobID uint) error
+}
+
+// HardwareService is the device-fleet surface used by HardwareHandler.
+type HardwareService interface {
+	RegisterDevice(restaurantID uint, name, kind, config string) (*models.HardwareDevice, error)
+	Devices(restaurantID uint) ([]models.HardwareDevice, error)
+	GetDeviceByID(id uint) (*models.HardwareDevice, error)
+	UpdateDevice(id uint, active *bool, config *string) (*models.HardwareDevice, error)
+	Heartbeat(token string) (*models.HardwareDevice, error)
+}
+
+// WebhookService is the outbound-webhook surface used by WebhookHandler.
+type WebhookService interface {
+	Secret(restaurantID uint) (string, error)
+	Deliveries(restaurantID uint) ([]models.WebhookDelivery, error)
+	GetDeliveryByID(id uint) (*models.WebhookDelivery, error)
+	Replay(id uint) (*models.WebhookDelivery, error)
+}
+
+// SyncService is the delta-sync surface used by SyncHandler.
+type SyncService interface {
+	Changes(restaurantID uint, token string) (*services.SyncDelta, error)
+	Push(restaurantID, callerID uint, push services.SyncPush) (*services.SyncResult, error)
+}
+
+// MarketingService is the contact-sync surface used by MarketingHandler.
+type MarketingService interface {
+	Configure(restaurantID uint, apiKey, listID string) (*models.MarketingAccount, error)
+	Status(restaurantID uint) (*models.MarketingAccount, error)
+	SyncSegment(restaurantID, segmentID uint) (*models.MarketingAccount, error)
+}
+
+// AccountingService is the bookkeeping surface used by AccountingHandler.
+type AccountingService interface {
+	Mappings(restaurantID uint) (*models.AccountMapping, error)
+	UpdateMappings(restaurantID uint, cash, sales, tax, tips, refunds *string) (*models.AccountMapping, error)
+	Export(restaurantID uint, from, to time.Time, format string) (body []byte, filename string, err error)
+}
+
+// StaffService is the staffing surface used by StaffHandler.
+type StaffService interface {
+	CreateEmployee(restaurantID uint, name, email, payrollRef string, hourlyRate float64) (*models.Employee, error)
+	GetEmployeeByID(id uint) (*models.Employee, error)
+	Employees(restaurantID uint) ([]models.Employee, error)
+	CreateShift(restaurantID, employeeID uint, startsAt, endsAt time.Time) (*models.Shift, error)
+	GetShiftByID(id uint) (*models.Shift, error)
+	Shifts(restaurantID uint, from, to time.Time) ([]models.Shift, error)
+	PayrollExport(restaurantID uint, from, to time.Time) (body []byte, filename string, err error)
+}
+
+// DisputeService is the chargeback surface used by DisputeHandler.
+type DisputeService interface {
+	Ingest(providerRef, paymentRef string, amount float64, reason, status string, evidenceDueBy *time.Time) (*models.Dispute, error)
+	GetDisputeByID(id uint) (*models.Dispute, error)
+	Disputes(restaurantID uint) ([]models.Dispute, error)
+	SubmitEvidence(id uint) (*models.Dispute, error)
+}
+
+// CustomerService is the customer-profile surface used by CustomerHandler.
+type CustomerService interface {
+	Addresses(userID uint) ([]models.CustomerAddress, error)
+	AddAddress(userID uint, label, street, city, postalCode string, isDefault bool) (*models.CustomerAddress, error)
+	RemoveAddress(id, userID uint) error
+	PaymentMethods(userID uint) ([]models.CustomerPaymentMethod, error)
+	AddPaymentMethod(userID uint, provider, token, brand, last4 string, isDefault bool) (*models.CustomerPaymentMethod, error)
+	RemovePaymentMethod(id, userID uint) error
+	OrderHistory(userID uint) ([]models.Order, error)
+	ReservationHistory(userID uint) ([]models.Reservation, error)
+}
+
+// ImageService is the image surface used by ImageHandler.
+type ImageService interface {
+	SaveUpload(entityType string, entityID uint, filename string, src io.Reader) (*models.Image, error)
+	GetLatest(entityType string, entityID uint) (*models.Image, error)
+	VariantURLs(image *models.Image) map[string]string
+}
+
+// SearchService is the search surface used by SearchHandler.
+type SearchService interface {
+	Search(q string, includeUsers bool, limit int) (*services.SearchResults, error)
+}
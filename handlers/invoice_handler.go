@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/services"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// InvoiceHandler exposes supplier-invoice upload, review and
+// confirmation.
+type InvoiceHandler struct {
+	invoiceService    InvoiceService
+	restaurantService RestaurantService
+}
+
+// NewInvoiceHandler builds an InvoiceHandler.
+func NewInvoiceHandler(invoiceService InvoiceService, restaurantService RestaurantService) *InvoiceHandler {
+	return &InvoiceHandler{invoiceService: invoiceService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *InvoiceHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ownedInvoice resolves the :id path parameter to an invoice whose
+// restaurant the caller owns.
+func (h *InvoiceHandler) ownedInvoice(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid invoice id")
+	}
+	invoice, err := h.invoiceService.GetInvoiceByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(invoice.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return invoice.ID, nil
+}
+
+// Upload handles POST /restaurants/:id/supplier-invoices. The body is a
+// multipart form with the invoice document under "invoice".
+func (h *InvoiceHandler) Upload(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	file, err := c.FormFile("invoice")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "an invoice file is required")
+	}
+	src, err := file.Open()
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "could not read upload")
+	}
+	defer src.Close()
+
+	invoice, err := h.invoiceService.Upload(id, file.Filename, src)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusAccepted, utils.OK("invoice queued for extraction", invoice))
+}
+
+// GetInvoices handles GET /restaurants/:id/supplier-invoices.
+func (h *InvoiceHandler) GetInvoices(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	invoices, err := h.invoiceService.Invoices(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", invoices))
+}
+
+// GetInvoice handles GET /supplier-invoices/:id, lines included.
+func (h *InvoiceHandler) GetInvoice(c echo.Context) error {
+	id, err := h.ownedInvoice(c)
+	if err != nil {
+		return err
+	}
+	invoice, err := h.invoiceService.GetInvoiceByID(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", invoice))
+}
+
+// GetFile handles GET /supplier-invoices/:id/file, serving the uploaded
+// document.
+func (h *InvoiceHandler) GetFile(c echo.Context) error {
+	id, err := h.ownedInvoice(c)
+	if err != nil {
+		return err
+	}
+	path, err := h.invoiceService.InvoicePath(id)
+	if err != nil {
+		return err
+	}
+	return c.File(path)
+}
+
+// InvoiceReviewRequest is the payload for correcting an invoice's
+// extracted figures.
+type InvoiceReviewRequest struct {
+	Supplier string                      `json:"supplier"`
+	Total    float64                     `json:"total"`
+	Lines    []services.InvoiceLineInput `json:"lines"`
+}
+
+// Review handles PUT /supplier-invoices/:id.
+func (h *InvoiceHandler) Review(c echo.Context) error {
+	id, err := h.ownedInvoice(c)
+	if err != nil {
+		return err
+	}
+
+	var req InvoiceReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	invoice, err := h.invoiceService.Review(id, req.Supplier, req.Total, req.Lines)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("invoice updated", invoice))
+}
+
+// Confirm handles POST /supplier-invoices/:id/confirm.
+func (h *InvoiceHandler) Confirm(c echo.Context) error {
+	id, err := h.ownedInvoice(c)
+	if err != nil {
+		return err
+	}
+	invoice, err := h.invoiceService.Confirm(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("invoice confirmed", invoice))
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// LicenseHandler exposes license and permit document storage plus the
+// admin compliance overview.
+type LicenseHandler struct {
+	licenseService    LicenseService
+	restaurantService RestaurantService
+}
+
+// NewLicenseHandler builds a LicenseHandler.
+func NewLicenseHandler(licenseService LicenseService, restaurantService RestaurantService) *LicenseHandler {
+	return &LicenseHandler{licenseService: licenseService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *LicenseHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// UploadDocument handles POST /restaurants/:id/licenses. The body is a
+// multipart form with kind, expires_at ("2006-01-02") and the document
+// file.
+func (h *LicenseHandler) UploadDocument(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	expiresAt, err := time.Parse("2006-01-02", c.FormValue("expires_at"))
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "expires_at must look like 2006-01-02")
+	}
+	file, err := c.FormFile("document")
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "a document file is required")
+	}
+	src, err := file.Open()
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "could not read upload")
+	}
+	defer src.Close()
+
+	document, err := h.licenseService.UploadDocument(id, c.FormValue("kind"), expiresAt, file.Filename, src)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("document stored", document))
+}
+
+// GetDocuments handles GET /restaurants/:id/licenses.
+func (h *LicenseHandler) GetDocuments(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	documents, err := h.licenseService.Documents(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", documents))
+}
+
+// GetFile handles GET /licenses/:id/file, serving the stored document.
+func (h *LicenseHandler) GetFile(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid document id")
+	}
+
+	document, err := h.licenseService.GetDocumentByID(uint(id))
+	if err != nil {
+		return err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(document.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	path, err := h.licenseService.DocumentPath(document.ID)
+	if err != nil {
+		return err
+	}
+	return c.File(path)
+}
+
+// ComplianceOverview handles GET /admin/compliance. It reports every
+// restaurant's documents and their expiry status.
+func (h *LicenseHandler) ComplianceOverview(c echo.Context) error {
+	rows, err := h.licenseService.ComplianceOverview(time.Now().UTC())
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", rows))
+}
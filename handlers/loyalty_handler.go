@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// LoyaltyHandler lets customers add wallet passes and serves the pass
+// update protocol to the wallet platforms.
+type LoyaltyHandler struct {
+	loyaltyService    LoyaltyService
+	restaurantService RestaurantService
+}
+
+// NewLoyaltyHandler builds a LoyaltyHandler.
+func NewLoyaltyHandler(loyaltyService LoyaltyService, restaurantService RestaurantService) *LoyaltyHandler {
+	return &LoyaltyHandler{loyaltyService: loyaltyService, restaurantService: restaurantService}
+}
+
+// PassRequest is the payload for requesting a wallet pass.
+type PassRequest struct {
+	RestaurantID string `json:"restaurant_id"`
+	Platform     string `json:"platform"`
+}
+
+// IssuePass handles POST /me/loyalty-passes.
+func (h *LoyaltyHandler) IssuePass(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+
+	var req PassRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	restaurantID, err := h.restaurantService.ResolveID(req.RestaurantID)
+	if err != nil {
+		return err
+	}
+
+	pass, err := h.loyaltyService.IssuePass(claims.UserID, restaurantID, req.Platform)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("pass issued", pass))
+}
+
+// GetPasses handles GET /me/loyalty-passes.
+func (h *LoyaltyHandler) GetPasses(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	passes, err := h.loyaltyService.Passes(claims.UserID)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", passes))
+}
+
+// GetPass handles GET /wallet/passes/:serial, the update protocol the
+// wallet platforms poll for fresh pass state.
+func (h *LoyaltyHandler) GetPass(c echo.Context) error {
+	token := c.Request().Header.Get("X-Pass-Token")
+	if token == "" {
+		return utils.Error(c, http.StatusUnauthorized, "missing pass token")
+	}
+	state, err := h.loyaltyService.PassForPlatform(c.Param("serial"), token)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", state))
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// MarketingHandler exposes marketing platform sync endpoints.
+type MarketingHandler struct {
+	marketingService  MarketingService
+	restaurantService RestaurantService
+}
+
+// NewMarketingHandler builds a MarketingHandler.
+func NewMarketingHandler(marketingService MarketingService, restaurantService RestaurantService) *MarketingHandler {
+	return &MarketingHandler{marketingService: marketingService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *MarketingHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// MarketingAccountRequest is the payload for
+// PUT /restaurants/:id/marketing-account.
+type MarketingAccountRequest struct {
+	APIKey string `json:"api_key"`
+	ListID string `json:"list_id"`
+}
+
+// Configure handles PUT /restaurants/:id/marketing-account.
+func (h *MarketingHandler) Configure(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req MarketingAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	account, err := h.marketingService.Configure(id, req.APIKey, req.ListID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("marketing account configured", account))
+}
+
+// GetStatus handles GET /restaurants/:id/marketing-account.
+func (h *MarketingHandler) GetStatus(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	account, err := h.marketingService.Status(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", account))
+}
+
+// SyncRequest is the payload for POST /restaurants/:id/marketing-sync.
+type SyncRequest struct {
+	SegmentID uint `json:"segment_id"`
+}
+
+// Sync handles POST /restaurants/:id/marketing-sync.
+func (h *MarketingHandler) Sync(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req SyncRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	account, err := h.marketingService.SyncSegment(id, req.SegmentID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("segment synced", account))
+}
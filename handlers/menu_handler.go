@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// MenuHandler exposes menu endpoints.
+type MenuHandler struct {
+	menuService       MenuService
+	restaurantService RestaurantService
+	experimentService ExperimentService
+}
+
+// NewMenuHandler builds a MenuHandler.
+func NewMenuHandler(menuService MenuService, restaurantService RestaurantService, experimentService ExperimentService) *MenuHandler {
+	return &MenuHandler{menuService: menuService, restaurantService: restaurantService, experimentService: experimentService}
+}
+
+// MenuItemRequest is the payload for creating or updating a menu item.
+type MenuItemRequest struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Category    string  `json:"category"`
+	Price       float64 `json:"price"`
+	Cost        float64 `json:"cost"`
+	Available   bool    `json:"available"`
+}
+
+// GetMenu handles GET /restaurants/:id/menu. The menu is public.
+func (h *MenuHandler) GetMenu(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	items, err := h.menuService.GetMenuByRestaurantID(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	// A running experiment reorders the menu per visitor, so the response
+	// varies by cookie; without this a shared cache could hand one
+	// visitor's variant to everyone. Experiment failures are best-effort
+	// and serve the regular menu.
+	c.Response().Header().Add(echo.HeaderVary, "Cookie")
+	if reordered, err := h.experimentService.ApplyMenu(id, visitorID(c), items); err == nil {
+		items = reordered
+	}
+	return c.JSON(http.StatusOK, utils.OK("", items))
+}
+
+// CreateMenuItem handles POST /restaurants/:id/menu.
+func (h *MenuHandler) CreateMenuItem(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return err
+	}
+
+	var req MenuItemRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Name == "" || req.Price <= 0 {
+		return utils.Error(c, http.StatusBadRequest, "name and a positive price are required")
+	}
+
+	item, err := h.menuService.CreateMenuItem(id, req.Name, req.Description, req.Category, req.Price, req.Cost)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusCreated, utils.OK("menu item created", item))
+}
+
+// UpdateMenuItem handles PUT /menu-items/:id.
+func (h *MenuHandler) UpdateMenuItem(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid menu item id")
+	}
+
+	item, err := h.menuService.GetMenuItemByID(uint(id))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(item.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	var req MenuItemRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	updated, err := h.menuService.UpdateMenuItem(uint(id), claims.UserID, req.Name, req.Description, req.Category, req.Price, req.Cost, req.Available)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("menu item updated", updated))
+}
+
+// GetPriceHistory handles GET /menu-items/:id/price-history.
+func (h *MenuHandler) GetPriceHistory(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid menu item id")
+	}
+
+	item, err := h.menuService.GetMenuItemByID(uint(id))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(item.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	history, err := h.menuService.PriceHistory(uint(id))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", history))
+}
+
+// DeleteMenuItem handles DELETE /menu-items/:id.
+func (h *MenuHandler) DeleteMenuItem(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid menu item id")
+	}
+
+	item, err := h.menuService.GetMenuItemByID(uint(id))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(item.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	if err := h.menuService.DeleteMenuItem(uint(id)); err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("menu item deleted", nil))
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/breaker"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// MetricsHandler exposes operational metrics.
+type MetricsHandler struct {
+	db      *gorm.DB
+	breaker *breaker.Breaker
+}
+
+// NewMetricsHandler builds a MetricsHandler.
+func NewMetricsHandler(db *gorm.DB, b *breaker.Breaker) *MetricsHandler {
+	return &MetricsHandler{db: db, breaker: b}
+}
+
+// Ready handles GET /readyz. It reports 503 while the database circuit
+// breaker is open so load balancers pull the instance out of rotation.
+func (h *MetricsHandler) Ready(c echo.Context) error {
+	state := h.breaker.State()
+	if state == breaker.StateOpen {
+		return utils.Error(c, http.StatusServiceUnavailable, "database circuit breaker is open")
+	}
+	return c.JSON(http.StatusOK, utils.OK("ready", map[string]interface{}{
+		"db_breaker": state,
+	}))
+}
+
+// Metrics handles GET /metrics. It currently reports connection pool
+// stats so operators can spot connection exhaustion before it bites.
+func (h *MetricsHandler) Metrics(c echo.Context) error {
+	sqlDB, err := h.db.DB()
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	stats := sqlDB.Stats()
+
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"db_breaker": h.breaker.State(),
+		"db_retries": repositories.RetryStats(),
+		"db_pool": map[string]interface{}{
+			"max_open_connections": stats.MaxOpenConnections,
+			"open_connections":     stats.OpenConnections,
+			"in_use":               stats.InUse,
+			"idle":                 stats.Idle,
+			"wait_count":           stats.WaitCount,
+			"wait_duration_ms":     stats.WaitDuration.Milliseconds(),
+			"max_idle_closed":      stats.MaxIdleClosed,
+			"max_lifetime_closed":  stats.MaxLifetimeClosed,
+		},
+	}))
+}
@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/services"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// OrderHandler exposes order endpoints.
+type OrderHandler struct {
+	orderService      OrderService
+	restaurantService RestaurantService
+	pushService       PushService
+	experimentService ExperimentService
+}
+
+// NewOrderHandler builds an OrderHandler.
+func NewOrderHandler(orderService OrderService, restaurantService RestaurantService, pushService PushService, experimentService ExperimentService) *OrderHandler {
+	return &OrderHandler{orderService: orderService, restaurantService: restaurantService, pushService: pushService, experimentService: experimentService}
+}
+
+// CreateOrderRequest is the payload for POST /restaurants/:id/orders.
+type CreateOrderRequest struct {
+	CustomerName  string               `json:"customer_name"`
+	CustomerEmail string               `json:"customer_email"`
+	Items         []services.OrderLine `json:"items"`
+	Tip           float64              `json:"tip"`
+}
+
+// CreateOrder handles POST /restaurants/:id/orders. Ordering is public.
+func (h *OrderHandler) CreateOrder(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	var req CreateOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	// A logged-in customer gets the order attached to their history;
+	// guests order anonymously.
+	var customerID uint
+	if claims, err := utils.GetClaims(c); err == nil && claims.Role == models.RoleCustomer {
+		customerID = claims.UserID
+	}
+
+	order, err := h.orderService.CreateOrder(id, customerID, req.CustomerName, req.CustomerEmail, req.Items, req.Tip)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	// Experiment bookkeeping is best-effort; it must never fail the order.
+	_ = h.experimentService.RecordConversion(id, visitorID(c), order.Total)
+
+	if ownerID, name, err := h.restaurantService.RestaurantOwner(order.RestaurantID); err == nil {
+		h.pushService.NotifyUser(ownerID, services.PushEventOrder,
+			"New order", fmt.Sprintf("Order #%d at %s", order.ID, name),
+			map[string]string{"order_id": fmt.Sprint(order.ID)})
+	}
+	return c.JSON(http.StatusCreated, utils.OK("order created", order))
+}
+
+// GetOrders handles GET /restaurants/:id/orders.
+func (h *OrderHandler) GetOrders(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return err
+	}
+
+	orders, err := h.orderService.GetOrdersByRestaurantID(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", orders))
+}
+
+// UpdateOrderStatusRequest is the payload for PUT /orders/:id/status.
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// UpdateOrderStatus handles PUT /orders/:id/status.
+func (h *OrderHandler) UpdateOrderStatus(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid order id")
+	}
+
+	order, err := h.orderService.GetOrderByID(uint(id))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(order.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	var req UpdateOrderStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	updated, err := h.orderService.UpdateOrderStatus(uint(id), req.Status)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("order updated", updated))
+}
+
+// GetTicket handles GET /orders/:id/ticket. format=text (default)
+// returns a plain 32-column layout; format=escpos returns the same
+// ticket as an ESC/POS byte stream ready to pipe to a thermal printer.
+func (h *OrderHandler) GetTicket(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid order id")
+	}
+
+	order, err := h.orderService.GetOrderByID(uint(id))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(order.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	ticket, err := h.orderService.KitchenTicket(uint(id))
+	if err != nil {
+		return err
+	}
+
+	switch c.QueryParam("format") {
+	case "", "text":
+		return c.String(http.StatusOK, ticketText(ticket))
+	case "escpos":
+		return c.Blob(http.StatusOK, "application/octet-stream", ticketESCPOS(ticket))
+	default:
+		return utils.Error(c, http.StatusBadRequest, "unknown ticket format")
+	}
+}
+
+// ticketText lays the ticket out for a 32-column printer or screen.
+func ticketText(t *services.KitchenTicket) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ORDER #%d\n", t.OrderID)
+	if t.CustomerName != "" {
+		fmt.Fprintf(&b, "%s\n", t.CustomerName)
+	}
+	fmt.Fprintf(&b, "%s\n", t.PlacedAt.Format("2006-01-02 15:04"))
+	for _, course := range t.Courses {
+		b.WriteString(strings.Repeat("-", 32) + "\n")
+		if course.Name != "" {
+			fmt.Fprintf(&b, "%s\n", strings.ToUpper(course.Name))
+		}
+		for _, line := range course.Lines {
+			fmt.Fprintf(&b, "%dx %s\n", line.Quantity, line.Name)
+			if line.Note != "" {
+				fmt.Fprintf(&b, "   >> %s\n", line.Note)
+			}
+		}
+	}
+	b.WriteString(strings.Repeat("-", 32) + "\n")
+	return b.String()
+}
+
+// ticketESCPOS renders the ticket as raw ESC/POS commands: init,
+// double-height header, bold course names and modifiers, then a cut.
+func ticketESCPOS(t *services.KitchenTicket) []byte {
+	var b bytes.Buffer
+	b.Write([]byte{0x1b, 0x40})       // ESC @ initialize
+	b.Write([]byte{0x1d, 0x21, 0x11}) // GS ! double width+height
+	fmt.Fprintf(&b, "ORDER #%d\n", t.OrderID)
+	b.Write([]byte{0x1d, 0x21, 0x00}) // GS ! normal
+	if t.CustomerName != "" {
+		fmt.Fprintf(&b, "%s\n", t.CustomerName)
+	}
+	fmt.Fprintf(&b, "%s\n", t.PlacedAt.Format("2006-01-02 15:04"))
+	for _, course := range t.Courses {
+		if course.Name != "" {
+			b.Write([]byte{0x1b, 0x45, 0x01}) // ESC E bold on
+			fmt.Fprintf(&b, "%s\n", strings.ToUpper(course.Name))
+			b.Write([]byte{0x1b, 0x45, 0x00}) // ESC E bold off
+		}
+		for _, line := range course.Lines {
+			fmt.Fprintf(&b, "%dx %s\n", line.Quantity, line.Name)
+			if line.Note != "" {
+				b.Write([]byte{0x1b, 0x45, 0x01})
+				fmt.Fprintf(&b, "   >> %s\n", line.Note)
+				b.Write([]byte{0x1b, 0x45, 0x00})
+			}
+		}
+	}
+	b.Write([]byte{0x1d, 0x56, 0x42, 0x00}) // GS V partial cut
+	return b.Bytes()
+}
+
+// RefundRequest is the payload for POST /orders/:id/refunds. A zero or
+// omitted amount refunds everything not yet refunded.
+type RefundRequest struct {
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason"`
+}
+
+// RefundOrder handles POST /orders/:id/refunds.
+func (h *OrderHandler) RefundOrder(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid order id")
+	}
+
+	order, err := h.orderService.GetOrderByID(uint(id))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(order.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	var req RefundRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	refund, err := h.orderService.RefundOrder(uint(id), req.Amount, req.Reason)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("refund issued", refund))
+}
+
+// GetRefunds handles GET /orders/:id/refunds.
+func (h *OrderHandler) GetRefunds(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid order id")
+	}
+
+	order, err := h.orderService.GetOrderByID(uint(id))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(order.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	refunds, err := h.orderService.GetRefundsByOrderID(uint(id))
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", refunds))
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// resolveCallerID matches the :id path parameter against the caller's
+// own claims, accepting either the public UUID or the legacy numeric ID.
+// It avoids a database lookup for self-only routes; ok is false when the
+// identifier does not refer to the caller.
+func resolveCallerID(param string, claims *utils.JWTClaims) (id uint, ok bool) {
+	if n, err := strconv.ParseUint(param, 10, 32); err == nil {
+		return uint(n), uint(n) == claims.UserID
+	}
+	return claims.UserID, param == claims.PublicID
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// PrinterHandler lets owners register kitchen printers and serves the
+// job queue their printer agents drain.
+type PrinterHandler struct {
+	printerService    PrinterService
+	orderService      OrderService
+	restaurantService RestaurantService
+}
+
+// NewPrinterHandler builds a PrinterHandler.
+func NewPrinterHandler(printerService PrinterService, orderService OrderService, restaurantService RestaurantService) *PrinterHandler {
+	return &PrinterHandler{printerService: printerService, orderService: orderService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *PrinterHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// PrinterRequest is the payload for registering a printer.
+type PrinterRequest struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+}
+
+// RegisterPrinter handles POST /restaurants/:id/printers. The response
+// carries the agent key; it is shown once here and on the list, since
+// the agent next to the printer needs it.
+func (h *PrinterHandler) RegisterPrinter(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req PrinterRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	printer, err := h.printerService.RegisterPrinter(id, req.Name, req.Format)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("printer registered", printer))
+}
+
+// GetPrinters handles GET /restaurants/:id/printers.
+func (h *PrinterHandler) GetPrinters(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	printers, err := h.printerService.Printers(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", printers))
+}
+
+// GetJobs handles GET /restaurants/:id/print-jobs, the owner's view of
+// the queue: a job stuck in delivered never got its ack.
+func (h *PrinterHandler) GetJobs(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	jobs, err := h.printerService.Jobs(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", jobs))
+}
+
+// agentJob is one job as delivered to a printer agent. Payload is the
+// rendered ticket; JSON carries it base64-encoded, which spares escpos
+// bytes from any charset mangling.
+type agentJob struct {
+	JobID   uint   `json:"job_id"`
+	OrderID uint   `json:"order_id"`
+	Format  string `json:"format"`
+	Payload []byte `json:"payload"`
+}
+
+// PullJobs handles GET /agent/print-jobs. Agents poll it with their
+// printer key; each pending job arrives rendered in the printer's
+// format.
+func (h *PrinterHandler) PullJobs(c echo.Context) error {
+	key := c.Request().Header.Get("X-Printer-Key")
+	if key == "" {
+		return utils.Error(c, http.StatusUnauthorized, "missing printer key")
+	}
+	printer, jobs, err := h.printerService.PullJobs(key)
+	if err != nil {
+		return err
+	}
+
+	out := make([]agentJob, 0, len(jobs))
+	for _, job := range jobs {
+		ticket, err := h.orderService.KitchenTicket(job.OrderID)
+		if err != nil {
+			return err
+		}
+		var payload []byte
+		if printer.Format == models.PrinterFormatText {
+			payload = []byte(ticketText(ticket))
+		} else {
+			payload = ticketESCPOS(ticket)
+		}
+		out = append(out, agentJob{JobID: job.ID, OrderID: job.OrderID, Format: printer.Format, Payload: payload})
+	}
+	return c.JSON(http.StatusOK, utils.OK("", out))
+}
+
+// AckJob handles POST /agent/print-jobs/:id/ack.
+func (h *PrinterHandler) AckJob(c echo.Context) error {
+	key := c.Request().Header.Get("X-Printer-Key")
+	if key == "" {
+		return utils.Error(c, http.StatusUnauthorized, "missing printer key")
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid print job id")
+	}
+	if err := h.printerService.AckJob(key, uint(id)); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("job acknowledged", nil))
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/services"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// RecipeHandler exposes ingredients, recipes, stock and food-cost
+// reporting.
+type RecipeHandler struct {
+	recipeService     RecipeService
+	stockService      StockService
+	wasteService      WasteService
+	menuService       MenuService
+	restaurantService RestaurantService
+}
+
+// NewRecipeHandler builds a RecipeHandler.
+func NewRecipeHandler(recipeService RecipeService, stockService StockService, wasteService WasteService, menuService MenuService, restaurantService RestaurantService) *RecipeHandler {
+	return &RecipeHandler{recipeService: recipeService, stockService: stockService, wasteService: wasteService, menuService: menuService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *RecipeHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ownedMenuItem resolves the :id path parameter to a menu item whose
+// restaurant the caller owns.
+func (h *RecipeHandler) ownedMenuItem(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid menu item id")
+	}
+	item, err := h.menuService.GetMenuItemByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(item.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return item.ID, nil
+}
+
+// ownedIngredient resolves the :id path parameter to an ingredient
+// whose restaurant the caller owns.
+func (h *RecipeHandler) ownedIngredient(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid ingredient id")
+	}
+	ingredient, err := h.recipeService.GetIngredientByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(ingredient.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return ingredient.ID, nil
+}
+
+// IngredientRequest is the payload for creating or updating an
+// ingredient.
+type IngredientRequest struct {
+	Name        string  `json:"name"`
+	Unit        string  `json:"unit"`
+	CostPerUnit float64 `json:"cost_per_unit"`
+}
+
+// CreateIngredient handles POST /restaurants/:id/ingredients.
+func (h *RecipeHandler) CreateIngredient(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req IngredientRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	ingredient, err := h.recipeService.CreateIngredient(id, req.Name, req.Unit, req.CostPerUnit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("ingredient created", ingredient))
+}
+
+// GetIngredients handles GET /restaurants/:id/ingredients.
+func (h *RecipeHandler) GetIngredients(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	ingredients, err := h.recipeService.Ingredients(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", ingredients))
+}
+
+// UpdateIngredient handles PUT /ingredients/:id. A cost change
+// recalculates the food cost of every dish using the ingredient.
+func (h *RecipeHandler) UpdateIngredient(c echo.Context) error {
+	id, err := h.ownedIngredient(c)
+	if err != nil {
+		return err
+	}
+
+	var req IngredientRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	updated, err := h.recipeService.UpdateIngredient(id, req.Name, req.Unit, req.CostPerUnit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("ingredient updated", updated))
+}
+
+// StockAdjustmentRequest is the payload for a manual stock correction.
+type StockAdjustmentRequest struct {
+	Delta  float64 `json:"delta"`
+	Reason string  `json:"reason"`
+}
+
+// AdjustStock handles POST /ingredients/:id/stock-adjustments.
+func (h *RecipeHandler) AdjustStock(c echo.Context) error {
+	id, err := h.ownedIngredient(c)
+	if err != nil {
+		return err
+	}
+
+	var req StockAdjustmentRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	ingredient, err := h.stockService.Adjust(id, req.Delta, req.Reason)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("stock adjusted", ingredient))
+}
+
+// GetStockMovements handles GET /ingredients/:id/stock-movements.
+func (h *RecipeHandler) GetStockMovements(c echo.Context) error {
+	id, err := h.ownedIngredient(c)
+	if err != nil {
+		return err
+	}
+	movements, err := h.stockService.Movements(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", movements))
+}
+
+// RecipeRequest is the payload for setting a menu item's recipe.
+type RecipeRequest struct {
+	Items []services.RecipeItemInput `json:"items"`
+}
+
+// SetRecipe handles PUT /menu-items/:id/recipe.
+func (h *RecipeHandler) SetRecipe(c echo.Context) error {
+	id, err := h.ownedMenuItem(c)
+	if err != nil {
+		return err
+	}
+
+	var req RecipeRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	recipe, err := h.recipeService.SetRecipe(id, req.Items)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("recipe updated", recipe))
+}
+
+// GetRecipe handles GET /menu-items/:id/recipe.
+func (h *RecipeHandler) GetRecipe(c echo.Context) error {
+	id, err := h.ownedMenuItem(c)
+	if err != nil {
+		return err
+	}
+	recipe, err := h.recipeService.Recipe(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", recipe))
+}
+
+// GetFoodCosts handles GET /restaurants/:id/food-costs. Besides the
+// per-dish figures it totals the period's waste, which is food cost
+// without a dish to carry it.
+func (h *RecipeHandler) GetFoodCosts(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	from, to, err := reportRange(c)
+	if err != nil {
+		return err
+	}
+
+	costs, err := h.recipeService.FoodCosts(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	waste, err := h.wasteService.Report(id, from, to)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"dishes":     costs,
+		"waste_from": from.Format("2006-01-02"),
+		"waste_to":   to.Format("2006-01-02"),
+		"waste_cost": waste.Total,
+	}))
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/services"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// ReservationHandler exposes reservation endpoints.
+type ReservationHandler struct {
+	reservationService ReservationService
+	restaurantService  RestaurantService
+	pushService        PushService
+}
+
+// NewReservationHandler builds a ReservationHandler.
+func NewReservationHandler(reservationService ReservationService, restaurantService RestaurantService, pushService PushService) *ReservationHandler {
+	return &ReservationHandler{reservationService: reservationService, restaurantService: restaurantService, pushService: pushService}
+}
+
+// CreateReservationRequest is the payload for POST /restaurants/:id/reservations.
+type CreateReservationRequest struct {
+	CustomerName  string `json:"customer_name"`
+	CustomerEmail string `json:"customer_email"`
+	PartySize     int    `json:"party_size"`
+	Date          string `json:"date"`
+	Notes         string `json:"notes"`
+}
+
+// CreateReservation handles POST /restaurants/:id/reservations. Booking is
+// public: customers do not need an account.
+func (h *ReservationHandler) CreateReservation(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	var req CreateReservationRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.CustomerName == "" || req.PartySize <= 0 {
+		return utils.Error(c, http.StatusBadRequest, "customer_name and a positive party_size are required")
+	}
+	date, err := time.Parse(time.RFC3339, req.Date)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "date must be RFC3339")
+	}
+
+	// A logged-in customer gets the booking attached to their history;
+	// guests book anonymously.
+	var customerID uint
+	if claims, err := utils.GetClaims(c); err == nil && claims.Role == models.RoleCustomer {
+		customerID = claims.UserID
+	}
+
+	reservation, err := h.reservationService.CreateReservation(id, customerID, req.CustomerName, req.CustomerEmail, req.PartySize, date, req.Notes)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	if ownerID, name, err := h.restaurantService.RestaurantOwner(reservation.RestaurantID); err == nil {
+		h.pushService.NotifyUser(ownerID, services.PushEventReservation,
+			"New reservation", fmt.Sprintf("Party of %d at %s", reservation.PartySize, name),
+			map[string]string{"reservation_id": fmt.Sprint(reservation.ID)})
+	}
+	return c.JSON(http.StatusCreated, utils.OK("reservation created", reservation))
+}
+
+// Availability handles GET /restaurants/:id/availability?date=YYYY-MM-DD.
+// It is public so booking widgets can render a calendar without a token.
+func (h *ReservationHandler) Availability(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+	day := c.QueryParam("date")
+	if day == "" {
+		return utils.Error(c, http.StatusBadRequest, "a date query parameter is required")
+	}
+
+	slots, err := h.reservationService.Availability(id, day)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"date":  day,
+		"slots": slots,
+	}))
+}
+
+// GetReservations handles GET /restaurants/:id/reservations.
+func (h *ReservationHandler) GetReservations(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	restaurant, err := h.restaurantService.GetRestaurantByID(id, claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	reservations, err := h.reservationService.GetReservationsByRestaurantID(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	// Booking times are stored in UTC; owners read them in their
+	// restaurant's local time.
+	loc := restaurant.Location()
+	for i := range reservations {
+		reservations[i].Date = reservations[i].Date.In(loc)
+	}
+	return c.JSON(http.StatusOK, utils.OK("", reservations))
+}
+
+// UpdateReservationStatusRequest is the payload for PUT /reservations/:id/status.
+type UpdateReservationStatusRequest struct {
+	Status string `json:"status"`
+	// Table optionally assigns a table number while seating.
+	Table int `json:"table"`
+}
+
+// UpdateReservationStatus handles PUT /reservations/:id/status.
+func (h *ReservationHandler) UpdateReservationStatus(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid reservation id")
+	}
+
+	reservation, err := h.reservationService.GetReservationByID(uint(id))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(reservation.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	var req UpdateReservationStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	updated, err := h.reservationService.UpdateReservationStatus(uint(id), req.Status, req.Table)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("reservation updated", updated))
+}
+
+// CancelMyReservation handles DELETE /me/reservations/:id. Ownership is
+// enforced by the service; a timely cancellation refunds any deposit.
+func (h *ReservationHandler) CancelMyReservation(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid reservation id")
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	reservation, err := h.reservationService.CancelReservation(uint(id), claims.UserID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("reservation cancelled", reservation))
+}
+
+// NoShowCount handles GET /customers/no-shows?email=. The count spans
+// all restaurants: no-show behaviour is a property of the customer, and
+// any owner deciding whether to hold a table may check it.
+func (h *ReservationHandler) NoShowCount(c echo.Context) error {
+	email := c.QueryParam("email")
+	if email == "" {
+		return utils.Error(c, http.StatusBadRequest, "an email query parameter is required")
+	}
+
+	count, err := h.reservationService.NoShowCount(email)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"email":    email,
+		"no_shows": count,
+	}))
+}
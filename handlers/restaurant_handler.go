@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// RestaurantHandler exposes restaurant CRUD endpoints.
+type RestaurantHandler struct {
+	restaurantService RestaurantService
+}
+
+// NewRestaurantHandler builds a RestaurantHandler.
+func NewRestaurantHandler(restaurantService RestaurantService) *RestaurantHandler {
+	return &RestaurantHandler{restaurantService: restaurantService}
+}
+
+// CreateRestaurantRequest is the payload for POST /restaurants.
+type CreateRestaurantRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+	Address     string `json:"address"`
+	Phone       string `json:"phone"`
+	Timezone    string `json:"timezone"` // IANA zone name; empty means UTC
+}
+
+// UpdateRestaurantRequest is the payload for PUT /restaurants/:id.
+// Pointers distinguish omitted fields from explicit empty strings: an
+// absent key leaves the field alone, "" clears it.
+type UpdateRestaurantRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Address     *string `json:"address"`
+	Phone       *string `json:"phone"`
+	Timezone    *string `json:"timezone"`
+}
+
+// BookingRulesRequest is the payload for PUT /restaurants/:id/booking-rules.
+// Pointers distinguish omitted settings from explicit values.
+type BookingRulesRequest struct {
+	Seats       *int    `json:"seats"`
+	OpensAt     *string `json:"opens_at"`
+	ClosesAt    *string `json:"closes_at"`
+	TurnMinutes *int    `json:"turn_minutes"`
+	// NoShowFee is charged to a no-show's card on file; zero disables it.
+	NoShowFee *float64 `json:"no_show_fee"`
+	// Deposit rules: parties of deposit_min_party or more prepay
+	// deposit_amount, refundable until deposit_refund_hours before the
+	// booking.
+	DepositAmount      *float64 `json:"deposit_amount"`
+	DepositMinParty    *int     `json:"deposit_min_party"`
+	DepositRefundHours *int     `json:"deposit_refund_hours"`
+}
+
+// CreateRestaurant handles POST /restaurants.
+func (h *RestaurantHandler) CreateRestaurant(c echo.Context) error {
+	var req CreateRestaurantRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Name == "" {
+		return utils.Error(c, http.StatusBadRequest, "name is required")
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	restaurant, err := h.restaurantService.CreateRestaurant(claims.UserID, req.Name, req.Description, req.Address, req.Phone, req.Timezone)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("restaurant created", restaurant))
+}
+
+// GetRestaurant handles GET /restaurants/:id.
+func (h *RestaurantHandler) GetRestaurant(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	restaurant, err := h.restaurantService.GetRestaurantByID(id, claims.UserID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", restaurant))
+}
+
+// GetMyRestaurants handles GET /users/:id/restaurants.
+func (h *RestaurantHandler) GetMyRestaurants(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	id, ok := resolveCallerID(c.Param("id"), claims)
+	if !ok {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	restaurants, err := h.restaurantService.GetRestaurantsByUserID(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", restaurants))
+}
+
+// CountMyRestaurants handles GET /users/:id/restaurants/count.
+func (h *RestaurantHandler) CountMyRestaurants(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	id, ok := resolveCallerID(c.Param("id"), claims)
+	if !ok {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	count, err := h.restaurantService.CountByUserID(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{"count": count}))
+}
+
+// UpdateRestaurant handles PUT /restaurants/:id.
+func (h *RestaurantHandler) UpdateRestaurant(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	var req UpdateRestaurantRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	restaurant, err := h.restaurantService.UpdateRestaurant(id, claims.UserID, req.Name, req.Description, req.Address, req.Phone, req.Timezone)
+	if err != nil {
+		return err // ownership is enforced by the service
+	}
+	return c.JSON(http.StatusOK, utils.OK("restaurant updated", restaurant))
+}
+
+// UpdateBookingRules handles PUT /restaurants/:id/booking-rules.
+func (h *RestaurantHandler) UpdateBookingRules(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	var req BookingRulesRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	restaurant, err := h.restaurantService.UpdateBookingRules(id, claims.UserID, req.Seats, req.OpensAt, req.ClosesAt, req.TurnMinutes,
+		req.NoShowFee, req.DepositAmount, req.DepositMinParty, req.DepositRefundHours)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("booking rules updated", restaurant))
+}
+
+// DeleteRestaurant handles DELETE /restaurants/:id.
+func (h *RestaurantHandler) DeleteRestaurant(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if err := h.restaurantService.DeleteRestaurant(id, claims.UserID); err != nil {
+		return err // ownership is enforced by the service
+	}
+	return c.JSON(http.StatusOK, utils.OK("restaurant deleted", nil))
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// ReviewHandler exposes reviews and ratings publicly and lets owners
+// connect external review platforms.
+type ReviewHandler struct {
+	reviewService     ReviewService
+	restaurantService RestaurantService
+}
+
+// NewReviewHandler builds a ReviewHandler.
+func NewReviewHandler(reviewService ReviewService, restaurantService RestaurantService) *ReviewHandler {
+	return &ReviewHandler{reviewService: reviewService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *ReviewHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReviewRequest is the payload for posting a native review.
+type ReviewRequest struct {
+	Author  string `json:"author"`
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+// AddReview handles POST /restaurants/:id/reviews. It is public, like
+// booking: diners do not need an account to leave a review.
+func (h *ReviewHandler) AddReview(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	var req ReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	review, err := h.reviewService.AddReview(id, req.Author, req.Rating, req.Comment)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("review posted", review))
+}
+
+// GetReviews handles GET /restaurants/:id/reviews.
+func (h *ReviewHandler) GetReviews(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+	reviews, err := h.reviewService.Reviews(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", reviews))
+}
+
+// GetRatings handles GET /restaurants/:id/ratings.
+func (h *ReviewHandler) GetRatings(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+	summary, err := h.reviewService.Ratings(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", summary))
+}
+
+// Trends handles GET /restaurants/:id/review-trends.
+func (h *ReviewHandler) Trends(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	from, to, err := reportRange(c)
+	if err != nil {
+		return err
+	}
+	trends, err := h.reviewService.Trends(id, from, to)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"from":       from.Format("2006-01-02"),
+		"to":         to.Format("2006-01-02"),
+		"count":      trends.Count,
+		"sentiments": trends.Sentiments,
+		"topics":     trends.Topics,
+	}))
+}
+
+// ReviewAccountRequest is the payload for connecting a platform.
+type ReviewAccountRequest struct {
+	Platform   string `json:"platform"`
+	APIKey     string `json:"api_key"`
+	ExternalID string `json:"external_id"`
+}
+
+// Connect handles POST /restaurants/:id/review-accounts.
+func (h *ReviewHandler) Connect(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req ReviewAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	account, err := h.reviewService.Connect(id, req.Platform, req.APIKey, req.ExternalID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("review platform connected", account))
+}
+
+// GetAccounts handles GET /restaurants/:id/review-accounts.
+func (h *ReviewHandler) GetAccounts(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	accounts, err := h.reviewService.Accounts(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", accounts))
+}
+
+// SyncNow handles POST /restaurants/:id/review-accounts/sync, a manual
+// pull outside the daily schedule.
+func (h *ReviewHandler) SyncNow(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	synced, err := h.reviewService.SyncRestaurant(id, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("sync complete", map[string]interface{}{"synced": synced}))
+}
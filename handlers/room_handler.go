@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// RoomHandler exposes private dining room endpoints.
+type RoomHandler struct {
+	roomService       RoomService
+	restaurantService RestaurantService
+}
+
+// NewRoomHandler builds a RoomHandler.
+func NewRoomHandler(roomService RoomService, restaurantService RestaurantService) *RoomHandler {
+	return &RoomHandler{roomService: roomService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *RoomHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// CreateRoomRequest is the payload for POST /restaurants/:id/rooms.
+type CreateRoomRequest struct {
+	Name     string  `json:"name"`
+	Capacity int     `json:"capacity"`
+	MinSpend float64 `json:"min_spend"`
+	OpensAt  string  `json:"opens_at"`  // "HH:MM"
+	ClosesAt string  `json:"closes_at"` // "HH:MM"
+}
+
+// CreateRoom handles POST /restaurants/:id/rooms.
+func (h *RoomHandler) CreateRoom(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req CreateRoomRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.OpensAt == "" {
+		req.OpensAt = "12:00"
+	}
+	if req.ClosesAt == "" {
+		req.ClosesAt = "22:00"
+	}
+
+	room, err := h.roomService.CreateRoom(id, req.Name, req.Capacity, req.MinSpend, req.OpensAt, req.ClosesAt)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("room created", room))
+}
+
+// GetRooms handles GET /restaurants/:id/rooms. It is public so
+// customers can browse rooms and their minimum spend before booking.
+func (h *RoomHandler) GetRooms(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+	rooms, err := h.roomService.GetRoomsByRestaurantID(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", rooms))
+}
+
+// DeleteRoom handles DELETE /rooms/:id.
+func (h *RoomHandler) DeleteRoom(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid room id")
+	}
+
+	room, err := h.roomService.GetRoomByID(uint(id))
+	if err != nil {
+		return err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(room.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	if err := h.roomService.DeleteRoom(room.ID); err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("room deleted", nil))
+}
+
+// BookRoomRequest is the payload for POST /rooms/:id/bookings.
+type BookRoomRequest struct {
+	CustomerName  string `json:"customer_name"`
+	CustomerEmail string `json:"customer_email"`
+	PartySize     int    `json:"party_size"`
+	StartsAt      string `json:"starts_at"` // RFC3339
+	EndsAt        string `json:"ends_at"`   // RFC3339
+	Notes         string `json:"notes"`
+}
+
+// BookRoom handles POST /rooms/:id/bookings. Booking is public like
+// table reservations; a logged-in customer's token ties the booking to
+// their history.
+func (h *RoomHandler) BookRoom(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid room id")
+	}
+
+	var req BookRoomRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "starts_at must be RFC3339")
+	}
+	endsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "ends_at must be RFC3339")
+	}
+
+	var customerID uint
+	if claims, err := utils.GetClaims(c); err == nil && claims.Role == models.RoleCustomer {
+		customerID = claims.UserID
+	}
+
+	booking, err := h.roomService.BookRoom(uint(id), customerID, req.CustomerName, req.CustomerEmail, req.PartySize, startsAt, endsAt, req.Notes)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("room booked", booking))
+}
+
+// GetBookings handles GET /restaurants/:id/room-bookings.
+func (h *RoomHandler) GetBookings(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	bookings, err := h.roomService.GetBookingsByRestaurantID(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", bookings))
+}
+
+// RoomBookingStatusRequest is the payload for PUT /room-bookings/:id/status.
+type RoomBookingStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// UpdateBookingStatus handles PUT /room-bookings/:id/status.
+func (h *RoomHandler) UpdateBookingStatus(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid booking id")
+	}
+
+	booking, err := h.roomService.GetBookingByID(uint(id))
+	if err != nil {
+		return err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(booking.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	var req RoomBookingStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	updated, err := h.roomService.UpdateBookingStatus(uint(id), req.Status)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("booking updated", updated))
+}
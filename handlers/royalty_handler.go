@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// RoyaltyHandler exposes franchise royalty rules and reports.
+type RoyaltyHandler struct {
+	royaltyService    RoyaltyService
+	restaurantService RestaurantService
+}
+
+// NewRoyaltyHandler builds a RoyaltyHandler.
+func NewRoyaltyHandler(royaltyService RoyaltyService, restaurantService RestaurantService) *RoyaltyHandler {
+	return &RoyaltyHandler{royaltyService: royaltyService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *RoyaltyHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// RoyaltyRuleRequest is the payload for PUT /restaurants/:id/royalty-rule.
+type RoyaltyRuleRequest struct {
+	Pct float64 `json:"pct"`
+}
+
+// SetRule handles PUT /restaurants/:id/royalty-rule.
+func (h *RoyaltyHandler) SetRule(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req RoyaltyRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	rule, err := h.royaltyService.SetRule(id, req.Pct)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("royalty rule saved", rule))
+}
+
+// GetRule handles GET /restaurants/:id/royalty-rule.
+func (h *RoyaltyHandler) GetRule(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	rule, err := h.royaltyService.Rule(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", rule))
+}
+
+// reportRange reads the from/to query parameters, defaulting to the
+// last calendar month's worth of days.
+func reportRange(c echo.Context) (time.Time, time.Time, error) {
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	from := to.AddDate(0, -1, 0)
+	var err error
+	if param := c.QueryParam("from"); param != "" {
+		if from, err = time.Parse("2006-01-02", param); err != nil {
+			return from, to, utils.Error(c, http.StatusBadRequest, "from must look like 2006-01-02")
+		}
+	}
+	if param := c.QueryParam("to"); param != "" {
+		if to, err = time.Parse("2006-01-02", param); err != nil {
+			return from, to, utils.Error(c, http.StatusBadRequest, "to must look like 2006-01-02")
+		}
+	}
+	return from, to, nil
+}
+
+// Report handles GET /royalty-report across all of the caller's
+// locations.
+func (h *RoyaltyHandler) Report(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	from, to, err := reportRange(c)
+	if err != nil {
+		return err
+	}
+
+	rows, err := h.royaltyService.Report(claims.UserID, from, to)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"from":      from.Format("2006-01-02"),
+		"to":        to.Format("2006-01-02"),
+		"locations": rows,
+	}))
+}
+
+// ExportReport handles GET /royalty-report/export. format defaults to
+// csv.
+func (h *RoyaltyHandler) ExportReport(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	from, to, err := reportRange(c)
+	if err != nil {
+		return err
+	}
+
+	body, filename, err := h.royaltyService.ExportReport(claims.UserID, from, to, c.QueryParam("format"))
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", body)
+}
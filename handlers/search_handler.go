@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// SearchHandler exposes the global search endpoint.
+type SearchHandler struct {
+	searchService SearchService
+}
+
+// NewSearchHandler builds a SearchHandler.
+func NewSearchHandler(searchService SearchService) *SearchHandler {
+	return &SearchHandler{searchService: searchService}
+}
+
+// Search handles GET /search?q=. Results are grouped by entity type; the
+// users group is only included for admin callers.
+func (h *SearchHandler) Search(c echo.Context) error {
+	q := strings.TrimSpace(c.QueryParam("q"))
+	if len(q) < 2 {
+		return utils.Error(c, http.StatusBadRequest, "q must be at least 2 characters")
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	results, err := h.searchService.Search(q, claims.Role == "admin", 20)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", results))
+}
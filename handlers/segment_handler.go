@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// SegmentHandler exposes marketing segment endpoints.
+type SegmentHandler struct {
+	segmentService    SegmentService
+	restaurantService RestaurantService
+}
+
+// NewSegmentHandler builds a SegmentHandler.
+func NewSegmentHandler(segmentService SegmentService, restaurantService RestaurantService) *SegmentHandler {
+	return &SegmentHandler{segmentService: segmentService, restaurantService: restaurantService}
+}
+
+// SegmentRequest is the payload for POST /restaurants/:id/segments.
+type SegmentRequest struct {
+	Name       string  `json:"name"`
+	MinOrders  int     `json:"min_orders"`
+	MinSpend   float64 `json:"min_spend"`
+	WindowDays int     `json:"window_days"`
+}
+
+// CreateSegment handles POST /restaurants/:id/segments.
+func (h *SegmentHandler) CreateSegment(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return err
+	}
+
+	var req SegmentRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	segment, err := h.segmentService.CreateSegment(id, req.Name, req.MinOrders, req.MinSpend, req.WindowDays)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("segment created", segment))
+}
+
+// GetSegments handles GET /restaurants/:id/segments.
+func (h *SegmentHandler) GetSegments(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return err
+	}
+
+	segments, err := h.segmentService.Segments(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", segments))
+}
+
+// ownedSegment resolves the :id path parameter to a segment owned by
+// the caller.
+func (h *SegmentHandler) ownedSegment(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid segment id")
+	}
+
+	segment, err := h.segmentService.GetSegmentByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(segment.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return uint(id), nil
+}
+
+// GetMembers handles GET /segments/:id/members.
+func (h *SegmentHandler) GetMembers(c echo.Context) error {
+	id, err := h.ownedSegment(c)
+	if err != nil {
+		return err
+	}
+
+	members, err := h.segmentService.Members(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", members))
+}
+
+// ExportMembers handles GET /segments/:id/export.
+func (h *SegmentHandler) ExportMembers(c echo.Context) error {
+	id, err := h.ownedSegment(c)
+	if err != nil {
+		return err
+	}
+
+	body, filename, err := h.segmentService.ExportMembers(id)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", body)
+}
+
+// DeleteSegment handles DELETE /segments/:id.
+func (h *SegmentHandler) DeleteSegment(c echo.Context) error {
+	id, err := h.ownedSegment(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.segmentService.DeleteSegment(id); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("segment deleted", nil))
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// StaffHandler exposes employee, shift and payroll endpoints.
+type StaffHandler struct {
+	staffService      StaffService
+	restaurantService RestaurantService
+}
+
+// NewStaffHandler builds a StaffHandler.
+func NewStaffHandler(staffService StaffService, restaurantService RestaurantService) *StaffHandler {
+	return &StaffHandler{staffService: staffService, restaurantService: restaurantService}
+}
+
+// EmployeeRequest is the payload for POST /restaurants/:id/employees.
+type EmployeeRequest struct {
+	Name       string  `json:"name"`
+	Email      string  `json:"email"`
+	PayrollRef string  `json:"payroll_ref"`
+	HourlyRate float64 `json:"hourly_rate"`
+}
+
+// CreateEmployee handles POST /restaurants/:id/employees.
+func (h *StaffHandler) CreateEmployee(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req EmployeeRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	employee, err := h.staffService.CreateEmployee(id, req.Name, req.Email, req.PayrollRef, req.HourlyRate)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("employee created", employee))
+}
+
+// GetEmployees handles GET /restaurants/:id/employees.
+func (h *StaffHandler) GetEmployees(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	employees, err := h.staffService.Employees(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", employees))
+}
+
+// ShiftRequest is the payload for POST /restaurants/:id/shifts.
+type ShiftRequest struct {
+	EmployeeID uint      `json:"employee_id"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+}
+
+// CreateShift handles POST /restaurants/:id/shifts.
+func (h *StaffHandler) CreateShift(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req ShiftRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	shift, err := h.staffService.CreateShift(id, req.EmployeeID, req.StartsAt, req.EndsAt)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("shift created", shift))
+}
+
+// GetShifts handles GET /restaurants/:id/shifts. from/to default to the
+// coming 14 days.
+func (h *StaffHandler) GetShifts(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	from := time.Now().UTC().Truncate(24 * time.Hour)
+	to := from.AddDate(0, 0, 14)
+	if param := c.QueryParam("from"); param != "" {
+		if from, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "from must look like 2006-01-02")
+		}
+	}
+	if param := c.QueryParam("to"); param != "" {
+		if to, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "to must look like 2006-01-02")
+		}
+	}
+
+	shifts, err := h.staffService.Shifts(id, from, to)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", shifts))
+}
+
+// PayrollExport handles GET /restaurants/:id/payroll-export. from/to
+// default to the last 14 days.
+func (h *StaffHandler) PayrollExport(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -14)
+	if param := c.QueryParam("from"); param != "" {
+		if from, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "from must look like 2006-01-02")
+		}
+	}
+	if param := c.QueryParam("to"); param != "" {
+		if to, err = time.Parse("2006-01-02", param); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "to must look like 2006-01-02")
+		}
+	}
+
+	body, filename, err := h.staffService.PayrollExport(id, from, to)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", body)
+}
+
+// ownedRestaurant resolves the :id param and checks it belongs to the
+// caller.
+func (h *StaffHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// SummaryHandler exposes lightweight dashboard counters.
+type SummaryHandler struct {
+	restaurantService  RestaurantService
+	reservationService ReservationService
+}
+
+// NewSummaryHandler builds a SummaryHandler.
+func NewSummaryHandler(restaurantService RestaurantService, reservationService ReservationService) *SummaryHandler {
+	return &SummaryHandler{restaurantService: restaurantService, reservationService: reservationService}
+}
+
+// Summary handles GET /me/summary. It returns counts only, so dashboards
+// don't have to fetch full lists just to render badges.
+func (h *SummaryHandler) Summary(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+
+	restaurants, err := h.restaurantService.CountByUserID(claims.UserID)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	pendingReservations, err := h.reservationService.CountPendingByOwner(claims.UserID)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"restaurants":          restaurants,
+		"pending_reservations": pendingReservations,
+		// No notification subsystem yet; keep the field so dashboard
+		// clients have a stable shape.
+		"unread_notifications": 0,
+	}))
+}
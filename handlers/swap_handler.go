@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// SwapHandler exposes shift swap requests, their approval and the
+// schedule change history.
+type SwapHandler struct {
+	swapService       SwapService
+	staffService      StaffService
+	restaurantService RestaurantService
+}
+
+// NewSwapHandler builds a SwapHandler.
+func NewSwapHandler(swapService SwapService, staffService StaffService, restaurantService RestaurantService) *SwapHandler {
+	return &SwapHandler{swapService: swapService, staffService: staffService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *SwapHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ownedShift resolves the :id path parameter to a shift whose
+// restaurant the caller owns.
+func (h *SwapHandler) ownedShift(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid shift id")
+	}
+	shift, err := h.staffService.GetShiftByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(shift.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return shift.ID, nil
+}
+
+// ownedSwap resolves the :id path parameter to a swap request whose
+// restaurant the caller owns.
+func (h *SwapHandler) ownedSwap(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid swap request id")
+	}
+	swap, err := h.swapService.GetSwapByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(swap.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return swap.ID, nil
+}
+
+// SwapRequest is the payload for opening a swap request. A zero
+// to_employee_id asks to drop the shift.
+type SwapRequest struct {
+	ToEmployeeID uint   `json:"to_employee_id"`
+	Note         string `json:"note"`
+}
+
+// RequestSwap handles POST /shifts/:id/swap-requests.
+func (h *SwapHandler) RequestSwap(c echo.Context) error {
+	id, err := h.ownedShift(c)
+	if err != nil {
+		return err
+	}
+
+	var req SwapRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	swap, err := h.swapService.RequestSwap(id, req.ToEmployeeID, req.Note)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("swap requested", swap))
+}
+
+// GetSwaps handles GET /restaurants/:id/swap-requests.
+func (h *SwapHandler) GetSwaps(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	swaps, err := h.swapService.Swaps(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", swaps))
+}
+
+// Approve handles POST /swap-requests/:id/approve.
+func (h *SwapHandler) Approve(c echo.Context) error {
+	id, err := h.ownedSwap(c)
+	if err != nil {
+		return err
+	}
+	swap, err := h.swapService.Approve(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("swap approved", swap))
+}
+
+// Reject handles POST /swap-requests/:id/reject.
+func (h *SwapHandler) Reject(c echo.Context) error {
+	id, err := h.ownedSwap(c)
+	if err != nil {
+		return err
+	}
+	swap, err := h.swapService.Reject(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("swap rejected", swap))
+}
+
+// GetChanges handles GET /restaurants/:id/schedule-changes.
+func (h *SwapHandler) GetChanges(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	changes, err := h.swapService.Changes(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", changes))
+}
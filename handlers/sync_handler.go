@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/services"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// SyncHandler serves delta sync for POS tablets: pull changes since a
+// token, push changes queued while offline.
+type SyncHandler struct {
+	syncService       SyncService
+	restaurantService RestaurantService
+}
+
+// NewSyncHandler builds a SyncHandler.
+func NewSyncHandler(syncService SyncService, restaurantService RestaurantService) *SyncHandler {
+	return &SyncHandler{syncService: syncService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *SyncHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetChanges handles GET /restaurants/:id/sync/changes. The since query
+// parameter is the token from the previous delta; omit it on first sync.
+func (h *SyncHandler) GetChanges(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	delta, err := h.syncService.Changes(id, c.QueryParam("since"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", delta))
+}
+
+// Push handles POST /restaurants/:id/sync/push.
+func (h *SyncHandler) Push(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+
+	var req services.SyncPush
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.syncService.Push(id, claims.UserID, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("sync push processed", result))
+}
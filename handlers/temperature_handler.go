@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// TemperatureHandler exposes HACCP temperature monitoring: units,
+// readings and the exportable compliance log.
+type TemperatureHandler struct {
+	temperatureService TemperatureService
+	restaurantService  RestaurantService
+}
+
+// NewTemperatureHandler builds a TemperatureHandler.
+func NewTemperatureHandler(temperatureService TemperatureService, restaurantService RestaurantService) *TemperatureHandler {
+	return &TemperatureHandler{temperatureService: temperatureService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *TemperatureHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ownedUnit resolves the :id path parameter to a temperature unit whose
+// restaurant the caller owns.
+func (h *TemperatureHandler) ownedUnit(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid unit id")
+	}
+	unit, err := h.temperatureService.GetUnitByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(unit.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return unit.ID, nil
+}
+
+// TemperatureUnitRequest is the payload for registering a monitored
+// unit.
+type TemperatureUnitRequest struct {
+	Name string  `json:"name"`
+	MinC float64 `json:"min_c"`
+	MaxC float64 `json:"max_c"`
+}
+
+// CreateUnit handles POST /restaurants/:id/temperature-units.
+func (h *TemperatureHandler) CreateUnit(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req TemperatureUnitRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	unit, err := h.temperatureService.CreateUnit(id, req.Name, req.MinC, req.MaxC)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("temperature unit created", unit))
+}
+
+// GetUnits handles GET /restaurants/:id/temperature-units.
+func (h *TemperatureHandler) GetUnits(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	units, err := h.temperatureService.Units(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", units))
+}
+
+// TemperatureReadingRequest is the payload for a manual temperature
+// check.
+type TemperatureReadingRequest struct {
+	Celsius float64 `json:"celsius"`
+}
+
+// RecordReading handles POST /temperature-units/:id/readings.
+func (h *TemperatureHandler) RecordReading(c echo.Context) error {
+	id, err := h.ownedUnit(c)
+	if err != nil {
+		return err
+	}
+
+	var req TemperatureReadingRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	reading, err := h.temperatureService.RecordManual(id, req.Celsius)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("reading recorded", reading))
+}
+
+// GetReadings handles GET /temperature-units/:id/readings.
+func (h *TemperatureHandler) GetReadings(c echo.Context) error {
+	id, err := h.ownedUnit(c)
+	if err != nil {
+		return err
+	}
+	from, to, err := reportRange(c)
+	if err != nil {
+		return err
+	}
+	readings, err := h.temperatureService.Readings(id, from, to)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", readings))
+}
+
+// sensorReading is the payload a sensor pushes. The sensor key stands
+// in for a JWT on this public route.
+type sensorReading struct {
+	SensorKey string  `json:"sensor_key"`
+	Celsius   float64 `json:"celsius"`
+}
+
+// SensorWebhook handles POST /webhooks/sensors/temperature.
+func (h *TemperatureHandler) SensorWebhook(c echo.Context) error {
+	var req sensorReading
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.SensorKey == "" {
+		return utils.Error(c, http.StatusUnauthorized, "missing sensor key")
+	}
+
+	reading, err := h.temperatureService.RecordSensor(req.SensorKey, req.Celsius)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("reading recorded", reading))
+}
+
+// ExportLog handles GET /restaurants/:id/temperature-log/export.
+// format defaults to csv.
+func (h *TemperatureHandler) ExportLog(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	from, to, err := reportRange(c)
+	if err != nil {
+		return err
+	}
+
+	body, filename, err := h.temperatureService.ExportLog(id, from, to, c.QueryParam("format"))
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", body)
+}
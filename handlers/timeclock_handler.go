@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// TimeclockHandler exposes clock-in/clock-out punches, timesheets and
+// the variance report.
+type TimeclockHandler struct {
+	timeclockService  TimeclockService
+	staffService      StaffService
+	restaurantService RestaurantService
+}
+
+// NewTimeclockHandler builds a TimeclockHandler.
+func NewTimeclockHandler(timeclockService TimeclockService, staffService StaffService, restaurantService RestaurantService) *TimeclockHandler {
+	return &TimeclockHandler{timeclockService: timeclockService, staffService: staffService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *TimeclockHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ownedEmployee resolves the :id path parameter to an employee whose
+// restaurant the caller owns.
+func (h *TimeclockHandler) ownedEmployee(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid employee id")
+	}
+	employee, err := h.staffService.GetEmployeeByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(employee.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return employee.ID, nil
+}
+
+// PinRequest is the payload for setting an employee's time-clock PIN.
+type PinRequest struct {
+	Pin string `json:"pin"`
+}
+
+// SetPin handles PUT /employees/:id/pin.
+func (h *TimeclockHandler) SetPin(c echo.Context) error {
+	id, err := h.ownedEmployee(c)
+	if err != nil {
+		return err
+	}
+
+	var req PinRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.timeclockService.SetPin(id, req.Pin); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("PIN set", nil))
+}
+
+// ClockIn handles POST /employees/:id/clock-in.
+func (h *TimeclockHandler) ClockIn(c echo.Context) error {
+	id, err := h.ownedEmployee(c)
+	if err != nil {
+		return err
+	}
+	punch, err := h.timeclockService.ClockIn(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("clocked in", punch))
+}
+
+// ClockOut handles POST /employees/:id/clock-out.
+func (h *TimeclockHandler) ClockOut(c echo.Context) error {
+	id, err := h.ownedEmployee(c)
+	if err != nil {
+		return err
+	}
+	punch, err := h.timeclockService.ClockOut(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("clocked out", punch))
+}
+
+// PunchRequest is the payload a shared tablet sends. The coordinates
+// are only checked when the restaurant has a geofence configured.
+type PunchRequest struct {
+	Pin       string  `json:"pin"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Punch handles POST /restaurants/:id/punch. The route is public; the
+// PIN stands in for a JWT on shared tablets.
+func (h *TimeclockHandler) Punch(c echo.Context) error {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	var req PunchRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Pin == "" {
+		return utils.Error(c, http.StatusUnauthorized, "missing PIN")
+	}
+
+	punch, err := h.timeclockService.PunchByPin(id, req.Pin, req.Latitude, req.Longitude)
+	if err != nil {
+		return err
+	}
+	message := "clocked in"
+	if punch.ClockOut != nil {
+		message = "clocked out"
+	}
+	return c.JSON(http.StatusOK, utils.OK(message, punch))
+}
+
+// GeofenceRequest is the payload for configuring the punch geofence.
+type GeofenceRequest struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Meters    int     `json:"meters"`
+}
+
+// SetGeofence handles PUT /restaurants/:id/geofence.
+func (h *TimeclockHandler) SetGeofence(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req GeofenceRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	restaurant, err := h.timeclockService.SetGeofence(id, req.Latitude, req.Longitude, req.Meters)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("geofence saved", restaurant))
+}
+
+// Timesheet handles GET /restaurants/:id/timesheet.
+func (h *TimeclockHandler) Timesheet(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	from, to, err := reportRange(c)
+	if err != nil {
+		return err
+	}
+	rows, err := h.timeclockService.Timesheet(id, from, to)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"from":      from.Format("2006-01-02"),
+		"to":        to.Format("2006-01-02"),
+		"employees": rows,
+	}))
+}
+
+// Variance handles GET /restaurants/:id/timesheet-variance.
+func (h *TimeclockHandler) Variance(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	from, to, err := reportRange(c)
+	if err != nil {
+		return err
+	}
+	rows, err := h.timeclockService.Variance(id, from, to)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"from":      from.Format("2006-01-02"),
+		"to":        to.Format("2006-01-02"),
+		"employees": rows,
+	}))
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// TrainingHandler exposes employee training records and the
+// restaurant-level training status report.
+type TrainingHandler struct {
+	trainingService   TrainingService
+	staffService      StaffService
+	restaurantService RestaurantService
+}
+
+// NewTrainingHandler builds a TrainingHandler.
+func NewTrainingHandler(trainingService TrainingService, staffService StaffService, restaurantService RestaurantService) *TrainingHandler {
+	return &TrainingHandler{trainingService: trainingService, staffService: staffService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *TrainingHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ownedEmployee resolves the :id path parameter to an employee whose
+// restaurant the caller owns.
+func (h *TrainingHandler) ownedEmployee(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, utils.Error(c, http.StatusBadRequest, "invalid employee id")
+	}
+	employee, err := h.staffService.GetEmployeeByID(uint(id))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(employee.RestaurantID, claims.UserID); err != nil {
+		return 0, utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+	return employee.ID, nil
+}
+
+// TrainingRecordRequest is the payload for recording a completed
+// training. Dates use "2006-01-02"; completed_at defaults to today.
+type TrainingRecordRequest struct {
+	Name        string `json:"name"`
+	CompletedAt string `json:"completed_at"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
+// AddRecord handles POST /employees/:id/training-records.
+func (h *TrainingHandler) AddRecord(c echo.Context) error {
+	id, err := h.ownedEmployee(c)
+	if err != nil {
+		return err
+	}
+
+	var req TrainingRecordRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+	var completedAt time.Time
+	if req.CompletedAt != "" {
+		if completedAt, err = time.Parse("2006-01-02", req.CompletedAt); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "completed_at must look like 2006-01-02")
+		}
+	}
+	var expiresAt time.Time
+	if req.ExpiresAt != "" {
+		if expiresAt, err = time.Parse("2006-01-02", req.ExpiresAt); err != nil {
+			return utils.Error(c, http.StatusBadRequest, "expires_at must look like 2006-01-02")
+		}
+	}
+
+	record, err := h.trainingService.AddRecord(id, req.Name, completedAt, expiresAt)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("training recorded", record))
+}
+
+// GetRecords handles GET /employees/:id/training-records.
+func (h *TrainingHandler) GetRecords(c echo.Context) error {
+	id, err := h.ownedEmployee(c)
+	if err != nil {
+		return err
+	}
+	records, err := h.trainingService.Records(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", records))
+}
+
+// Status handles GET /restaurants/:id/training-status.
+func (h *TrainingHandler) Status(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	rows, err := h.trainingService.Status(id, time.Now().UTC())
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", rows))
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// UserHandler exposes user profile endpoints.
+type UserHandler struct {
+	userService UserService
+}
+
+// NewUserHandler builds a UserHandler.
+func NewUserHandler(userService UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+// UpdateUserRequest is the payload for PUT /users/:id. Pointers
+// distinguish omitted fields from explicit empty strings: an absent key
+// leaves the field alone, "" clears it.
+type UpdateUserRequest struct {
+	Name  *string `json:"name"`
+	Phone *string `json:"phone"`
+	// Birthday is optional; the zero time clears it.
+	Birthday *time.Time `json:"birthday"`
+}
+
+// GetUser handles GET /users/:id.
+func (h *UserHandler) GetUser(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	id, ok := resolveCallerID(c.Param("id"), claims)
+	if !ok {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	user, err := h.userService.GetUserByID(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", user))
+}
+
+// UpdateUser handles PUT /users/:id.
+func (h *UserHandler) UpdateUser(c echo.Context) error {
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	id, ok := resolveCallerID(c.Param("id"), claims)
+	if !ok {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	var req UpdateUserRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	user, err := h.userService.UpdateUser(id, req.Name, req.Phone, req.Birthday)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("user updated", user))
+}
+
+// DeleteUser handles DELETE /users/:id.
+func (h *UserHandler) DeleteUser(c echo.Context) error {
+	// Admins may delete other accounts, so the identifier needs a real
+	// lookup rather than the self-only claims match.
+	id, err := h.userService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if claims.UserID != id && claims.Role != "admin" {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	if err := h.userService.DeleteUser(id, claims.UserID); err != nil {
+		return err // last-admin and self-delete guards live in the service
+	}
+	return c.JSON(http.StatusOK, utils.OK("user deleted", nil))
+}
+
+// UpdateUserRoleRequest is the payload for PUT /admin/users/:id/role.
+type UpdateUserRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// UpdateRole handles PUT /admin/users/:id/role. Admin only; the route
+// group enforces the role.
+func (h *UserHandler) UpdateRole(c echo.Context) error {
+	id, err := h.userService.ResolveID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	var req UpdateUserRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	user, err := h.userService.UpdateRole(id, models.Role(req.Role))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("role updated", user))
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// WasteHandler exposes the waste log and its cost reporting.
+type WasteHandler struct {
+	wasteService      WasteService
+	restaurantService RestaurantService
+}
+
+// NewWasteHandler builds a WasteHandler.
+func NewWasteHandler(wasteService WasteService, restaurantService RestaurantService) *WasteHandler {
+	return &WasteHandler{wasteService: wasteService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *WasteHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// WasteEntryRequest is the payload for logging a loss.
+type WasteEntryRequest struct {
+	IngredientID uint    `json:"ingredient_id"`
+	Description  string  `json:"description"`
+	Quantity     float64 `json:"quantity"`
+	Reason       string  `json:"reason"`
+	Cost         float64 `json:"cost"`
+}
+
+// LogWaste handles POST /restaurants/:id/waste-entries.
+func (h *WasteHandler) LogWaste(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+
+	var req WasteEntryRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	entry, err := h.wasteService.LogWaste(id, req.IngredientID, req.Description, req.Quantity, req.Reason, req.Cost)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, utils.OK("waste logged", entry))
+}
+
+// GetEntries handles GET /restaurants/:id/waste-entries.
+func (h *WasteHandler) GetEntries(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	from, to, err := reportRange(c)
+	if err != nil {
+		return err
+	}
+	entries, err := h.wasteService.Entries(id, from, to)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", entries))
+}
+
+// Report handles GET /restaurants/:id/waste-report.
+func (h *WasteHandler) Report(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	from, to, err := reportRange(c)
+	if err != nil {
+		return err
+	}
+	report, err := h.wasteService.Report(id, from, to)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]interface{}{
+		"from":  from.Format("2006-01-02"),
+		"to":    to.Format("2006-01-02"),
+		"total": report.Total,
+		"days":  report.Days,
+	}))
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// WebhookHandler serves the owner's side of outbound webhooks: the
+// signing secret, the delivery log and manual replays.
+type WebhookHandler struct {
+	webhookService    WebhookService
+	restaurantService RestaurantService
+}
+
+// NewWebhookHandler builds a WebhookHandler.
+func NewWebhookHandler(webhookService WebhookService, restaurantService RestaurantService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService, restaurantService: restaurantService}
+}
+
+// ownedRestaurant resolves the :id path parameter to a restaurant owned
+// by the caller.
+func (h *WebhookHandler) ownedRestaurant(c echo.Context) (uint, error) {
+	id, err := h.restaurantService.ResolveID(c.Param("id"))
+	if err != nil {
+		return 0, err
+	}
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(id, claims.UserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetSecret handles GET /restaurants/:id/webhook-secret. The first call
+// mints the secret; the owner configures their endpoint with it to
+// verify signatures.
+func (h *WebhookHandler) GetSecret(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	secret, err := h.webhookService.Secret(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("", map[string]string{"secret": secret}))
+}
+
+// GetDeliveries handles GET /restaurants/:id/webhook-deliveries, the
+// delivery log with response codes and errors per attempt.
+func (h *WebhookHandler) GetDeliveries(c echo.Context) error {
+	id, err := h.ownedRestaurant(c)
+	if err != nil {
+		return err
+	}
+	deliveries, err := h.webhookService.Deliveries(id)
+	if err != nil {
+		return utils.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, utils.OK("", deliveries))
+}
+
+// Replay handles POST /webhook-deliveries/:id/replay.
+func (h *WebhookHandler) Replay(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.Error(c, http.StatusBadRequest, "invalid delivery id")
+	}
+
+	delivery, err := h.webhookService.GetDeliveryByID(uint(id))
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.GetClaims(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.restaurantService.GetRestaurantByID(delivery.RestaurantID, claims.UserID); err != nil {
+		return utils.Error(c, http.StatusForbidden, "forbidden")
+	}
+
+	replayed, err := h.webhookService.Replay(uint(id))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, utils.OK("delivery queued for replay", replayed))
+}
@@ -0,0 +1,87 @@
+package hashing
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	argon2idPrefix  = "$argon2id$"
+	argon2idSaltLen = 16
+	argon2idKeyLen  = 32
+)
+
+// Argon2idHasher hashes passwords with Argon2id. Hashes are stored in
+// the standard PHC string format, so the parameters used to create each
+// hash travel with it.
+type Argon2idHasher struct {
+	Memory  uint32 // in KiB
+	Time    uint32
+	Threads uint8
+}
+
+// Hash implements Hasher.
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, argon2idKeyLen)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version, h.Memory, h.Time, h.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// NeedsRehash implements Hasher. Bcrypt hashes and Argon2id hashes
+// created with weaker parameters are both due for an upgrade.
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	if !isArgon2id(hash) {
+		return true
+	}
+	memory, time, threads, _, _, err := parseArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return memory < h.Memory || time < h.Time || threads < h.Threads
+}
+
+func isArgon2id(hash string) bool {
+	return strings.HasPrefix(hash, argon2idPrefix)
+}
+
+func verifyArgon2id(password, hash string) bool {
+	memory, time, threads, salt, key, err := parseArgon2id(hash)
+	if err != nil {
+		return false
+	}
+	candidate := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(candidate, key) == 1
+}
+
+func parseArgon2id(hash string) (memory, time uint32, threads uint8, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, key
+	if len(parts) != 6 {
+		return 0, 0, 0, nil, nil, fmt.Errorf("malformed argon2id hash")
+	}
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return 0, 0, 0, nil, nil, fmt.Errorf("unsupported argon2 version")
+	}
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return 0, 0, 0, nil, nil, fmt.Errorf("malformed argon2id parameters")
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return 0, 0, 0, nil, nil, err
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return 0, 0, 0, nil, nil, err
+	}
+	return memory, time, threads, salt, key, nil
+}
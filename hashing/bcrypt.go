@@ -0,0 +1,35 @@
+package hashing
+
+import "golang.org/x/crypto/bcrypt"
+
+// BcryptHasher hashes passwords with bcrypt at a configurable cost.
+type BcryptHasher struct {
+	Cost int
+}
+
+// Hash implements Hasher.
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.cost())
+	return string(bytes), err
+}
+
+// NeedsRehash implements Hasher. Argon2id hashes and bcrypt hashes
+// below the configured cost are both due for an upgrade.
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	if isArgon2id(hash) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost < h.cost()
+}
+
+func (h *BcryptHasher) cost() int {
+	if h.Cost < bcrypt.MinCost || h.Cost > bcrypt.MaxCost {
+		return bcrypt.DefaultCost
+	}
+	return h.Cost
+}
+
+func verifyBcrypt(password, hash string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,44 @@
+package hashing
+
+import (
+	"fmt"
+
+	"github.com/marc0cl/wets-ma-bu-api/config"
+)
+
+// Hasher produces password hashes with the configured algorithm and
+// parameters. Drivers are selected via PASSWORD_HASH_DRIVER.
+type Hasher interface {
+	// Hash derives a storable hash from a plaintext password.
+	Hash(password string) (string, error)
+	// NeedsRehash reports whether a stored hash uses a different
+	// algorithm or weaker parameters than this hasher would produce,
+	// so callers can upgrade it transparently on login.
+	NeedsRehash(hash string) bool
+}
+
+// NewFromConfig builds the configured driver. bcrypt is the default.
+func NewFromConfig(cfg *config.Config) (Hasher, error) {
+	switch cfg.PasswordHashDriver {
+	case "bcrypt", "":
+		return &BcryptHasher{Cost: cfg.BcryptCost}, nil
+	case "argon2id":
+		return &Argon2idHasher{
+			Memory:  uint32(cfg.Argon2MemoryKB),
+			Time:    uint32(cfg.Argon2Time),
+			Threads: uint8(cfg.Argon2Threads),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported password hash driver %q", cfg.PasswordHashDriver)
+	}
+}
+
+// Verify checks a plaintext password against a stored hash. It accepts
+// both supported formats regardless of the active driver, so existing
+// accounts keep working after the driver or its parameters change.
+func Verify(password, hash string) bool {
+	if isArgon2id(hash) {
+		return verifyArgon2id(password, hash)
+	}
+	return verifyBcrypt(password, hash)
+}
@@ -0,0 +1,75 @@
+package hashing
+
+import "testing"
+
+// Low-cost parameters keep the tests fast; production defaults live in
+// config.
+func testBcrypt() *BcryptHasher { return &BcryptHasher{Cost: 4} }
+func testArgon2id() *Argon2idHasher {
+	return &Argon2idHasher{Memory: 1024, Time: 1, Threads: 1}
+}
+
+func TestBcryptRoundTrip(t *testing.T) {
+	hash, err := testBcrypt().Hash("s3cret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !Verify("s3cret", hash) {
+		t.Fatal("correct password rejected")
+	}
+	if Verify("wrong", hash) {
+		t.Fatal("wrong password accepted")
+	}
+}
+
+func TestArgon2idRoundTrip(t *testing.T) {
+	hash, err := testArgon2id().Hash("s3cret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !Verify("s3cret", hash) {
+		t.Fatal("correct password rejected")
+	}
+	if Verify("wrong", hash) {
+		t.Fatal("wrong password accepted")
+	}
+}
+
+func TestNeedsRehashAcrossAlgorithms(t *testing.T) {
+	bcryptHash, err := testBcrypt().Hash("s3cret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	argonHash, err := testArgon2id().Hash("s3cret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	if !testArgon2id().NeedsRehash(bcryptHash) {
+		t.Fatal("argon2id hasher should upgrade bcrypt hashes")
+	}
+	if !testBcrypt().NeedsRehash(argonHash) {
+		t.Fatal("bcrypt hasher should upgrade argon2id hashes")
+	}
+	if testArgon2id().NeedsRehash(argonHash) {
+		t.Fatal("hash at current parameters should not be rehashed")
+	}
+}
+
+func TestNeedsRehashOnWeakerParameters(t *testing.T) {
+	weakHash, err := testBcrypt().Hash("s3cret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !(&BcryptHasher{Cost: 6}).NeedsRehash(weakHash) {
+		t.Fatal("lower-cost bcrypt hash should be rehashed")
+	}
+
+	weakArgon, err := testArgon2id().Hash("s3cret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !(&Argon2idHasher{Memory: 2048, Time: 1, Threads: 1}).NeedsRehash(weakArgon) {
+		t.Fatal("lower-memory argon2id hash should be rehashed")
+	}
+}
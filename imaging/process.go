@@ -0,0 +1,97 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"golang.org/x/image/draw"
+
+	_ "image/png" // register PNG decoding
+)
+
+// Variant widths. Images narrower than a variant are re-encoded at their
+// original size rather than upscaled.
+const (
+	ThumbWidth  = 160
+	MediumWidth = 640
+	LargeWidth  = 1280
+)
+
+// Process derives thumb/medium/large variants of the image at srcPath
+// into destDir, named after baseName. Decoding and re-encoding drops all
+// metadata, so EXIF (GPS position, device serial) never reaches the
+// public variants. When the cwebp tool is installed the variants are
+// converted to WebP; otherwise they stay JPEG.
+func Process(srcPath, destDir, baseName string) (thumb, medium, large string, err error) {
+	f, err := os.Open(srcPath)
+	if err != nil {
+		return "", "", "", err
+	}
+	defer f.Close()
+
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return "", "", "", fmt.Errorf("decoding %s: %w", srcPath, err)
+	}
+
+	cwebp, _ := exec.LookPath("cwebp")
+
+	variants := []struct {
+		name  string
+		width int
+		out   *string
+	}{
+		{"thumb", ThumbWidth, &thumb},
+		{"medium", MediumWidth, &medium},
+		{"large", LargeWidth, &large},
+	}
+	for _, v := range variants {
+		path, err := writeVariant(src, destDir, baseName, v.name, v.width, cwebp)
+		if err != nil {
+			return "", "", "", err
+		}
+		*v.out = path
+	}
+	return thumb, medium, large, nil
+}
+
+func writeVariant(src image.Image, destDir, baseName, variant string, width int, cwebp string) (string, error) {
+	jpgPath := filepath.Join(destDir, fmt.Sprintf("%s-%s.jpg", baseName, variant))
+	out, err := os.Create(jpgPath)
+	if err != nil {
+		return "", err
+	}
+	if err := jpeg.Encode(out, scaleToWidth(src, width), &jpeg.Options{Quality: 85}); err != nil {
+		out.Close()
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+
+	if cwebp == "" {
+		return jpgPath, nil
+	}
+	webpPath := filepath.Join(destDir, fmt.Sprintf("%s-%s.webp", baseName, variant))
+	if err := exec.Command(cwebp, "-quiet", "-q", "80", jpgPath, "-o", webpPath).Run(); err != nil {
+		// A broken cwebp should not fail the pipeline; keep the JPEG.
+		return jpgPath, nil
+	}
+	os.Remove(jpgPath)
+	return webpPath, nil
+}
+
+func scaleToWidth(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	if bounds.Dx() <= width {
+		return src
+	}
+	height := bounds.Dy() * width / bounds.Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+	return dst
+}
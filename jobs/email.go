@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"encoding/json"
+
+	"github.com/marc0cl/wets-ma-bu-api/mailer"
+)
+
+// EmailPayload is the payload of an "email.send" job.
+type EmailPayload struct {
+	To       string                 `json:"to"`
+	Subject  string                 `json:"subject"`
+	Template string                 `json:"template"`
+	Data     map[string]interface{} `json:"data"`
+	// HTML carries a pre-rendered body for producers that apply their
+	// own templating (e.g. per-restaurant branding); it wins over
+	// Template.
+	HTML string `json:"html"`
+	Text string `json:"text"`
+}
+
+// EmailHandler returns the job handler for "email.send": it renders the
+// requested template and delivers it through the configured mailer.
+func EmailHandler(m mailer.Mailer) Handler {
+	return func(payload []byte) error {
+		var p EmailPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		html := p.HTML
+		if html == "" && p.Template != "" {
+			rendered, err := mailer.Render(p.Template, p.Data)
+			if err != nil {
+				return err
+			}
+			html = rendered
+		}
+		return m.Send(mailer.Message{To: p.To, Subject: p.Subject, HTML: html, Text: p.Text})
+	}
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/marc0cl/wets-ma-bu-api/imaging"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// ImagePayload is the payload of an "image.process" job.
+type ImagePayload struct {
+	ImageID uint `json:"image_id"`
+}
+
+// ImageHandler returns the job handler for "image.process": it derives
+// the size variants of an uploaded image and marks the row ready. The
+// row is marked failed on error so the API can report it, but the error
+// is still returned to get the worker's retry behaviour.
+func ImageHandler(images *repositories.ImageRepository, mediaDir string) Handler {
+	return func(payload []byte) error {
+		var p ImagePayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		img, err := images.GetByID(p.ImageID)
+		if err != nil {
+			return err
+		}
+
+		base := fmt.Sprintf("image-%d", img.ID)
+		thumb, medium, large, err := imaging.Process(img.OriginalPath, mediaDir, base)
+		if err != nil {
+			img.Status = models.ImageFailed
+			if saveErr := images.Update(img); saveErr != nil {
+				return saveErr
+			}
+			return err
+		}
+
+		img.ThumbPath = thumb
+		img.MediumPath = medium
+		img.LargePath = large
+		img.Status = models.ImageReady
+		return images.Update(img)
+	}
+}
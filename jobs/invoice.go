@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"encoding/json"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/ocr"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// InvoicePayload is the payload of an "invoice.extract" job.
+type InvoicePayload struct {
+	InvoiceID uint `json:"invoice_id"`
+}
+
+// InvoiceHandler returns the job handler for "invoice.extract": it runs
+// the OCR provider over the uploaded document and prefills the invoice
+// for review. The row is marked failed on error so the API can report
+// it, but the error is still returned to get the worker's retry
+// behaviour.
+func InvoiceHandler(invoices *repositories.InvoiceRepository, extractor ocr.Extractor) Handler {
+	return func(payload []byte) error {
+		var p InvoicePayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		invoice, err := invoices.GetByID(p.InvoiceID)
+		if err != nil {
+			return err
+		}
+		if invoice.Status != models.InvoicePending && invoice.Status != models.InvoiceFailed {
+			return nil // already extracted or reviewed; nothing to redo
+		}
+
+		result, err := extractor.Extract(invoice.FilePath)
+		if err != nil {
+			invoice.Status = models.InvoiceFailed
+			invoice.Lines = nil
+			if saveErr := invoices.Update(invoice); saveErr != nil {
+				return saveErr
+			}
+			return err
+		}
+
+		lines := make([]models.SupplierInvoiceLine, 0, len(result.Lines))
+		for _, line := range result.Lines {
+			lines = append(lines, models.SupplierInvoiceLine{
+				Description: line.Description,
+				Quantity:    line.Quantity,
+				UnitCost:    line.UnitCost,
+			})
+		}
+		if err := invoices.ReplaceLines(invoice.ID, lines); err != nil {
+			return err
+		}
+
+		invoice.Supplier = result.Supplier
+		invoice.Total = result.Total
+		invoice.Status = models.InvoiceExtracted
+		invoice.Lines = nil // Update must not re-save the stale preloaded lines
+		return invoices.Update(invoice)
+	}
+}
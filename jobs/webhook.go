@@ -0,0 +1,101 @@
+package jobs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// WebhookPayload is the payload of a "webhook.deliver" job.
+type WebhookPayload struct {
+	DeliveryID uint `json:"delivery_id"`
+}
+
+// webhookMaxAttempts matches the job table's retry budget, so the
+// delivery row reads failed by the time its job is parked as dead.
+const webhookMaxAttempts = 5
+
+// webhookClient bounds how long a slow endpoint can hold a worker.
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// WebhookHandler returns the job handler for "webhook.deliver": it
+// POSTs the delivery's payload to its captured URL, signed with the
+// restaurant's webhook secret in the same "t=<unix>,v1=<hex>" scheme
+// our inbound payment webhooks use. Every attempt is recorded on the
+// delivery row; a non-2xx answer is an error, so the worker retries
+// with backoff until the attempts run out.
+func WebhookHandler(webhookRepo *repositories.WebhookRepository, restaurantRepo *repositories.RestaurantRepository) Handler {
+	return func(payload []byte) error {
+		var p WebhookPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		delivery, err := webhookRepo.GetByID(p.DeliveryID)
+		if err != nil {
+			return err
+		}
+		// A replay or a racing attempt may have settled it already.
+		if delivery.Status != models.WebhookPending {
+			return nil
+		}
+		restaurant, err := restaurantRepo.GetByID(delivery.RestaurantID)
+		if err != nil {
+			return err
+		}
+
+		code, sendErr := sendWebhook(delivery, restaurant.WebhookSecret)
+		delivery.Attempts++
+		delivery.ResponseCode = code
+		if sendErr == nil {
+			now := time.Now()
+			delivery.Status = models.WebhookDelivered
+			delivery.DeliveredAt = &now
+			delivery.LastError = ""
+			return webhookRepo.Update(delivery)
+		}
+		delivery.LastError = sendErr.Error()
+		if delivery.Attempts >= webhookMaxAttempts {
+			delivery.Status = models.WebhookFailed
+		}
+		if err := webhookRepo.Update(delivery); err != nil {
+			return err
+		}
+		return sendErr
+	}
+}
+
+// sendWebhook does one signed POST and returns the response code, zero
+// when the request never got an answer.
+func sendWebhook(delivery *models.WebhookDelivery, secret string) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write([]byte(delivery.Payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Signature", fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(mac.Sum(nil))))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint answered %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
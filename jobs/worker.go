@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// Handler processes one job payload. Returning an error reschedules the
+// job with backoff until its attempts are exhausted.
+type Handler func(payload []byte) error
+
+// Worker polls the job table and dispatches jobs to registered handlers.
+type Worker struct {
+	repo     *repositories.JobRepository
+	handlers map[string]Handler
+	interval time.Duration
+}
+
+// NewWorker builds a Worker polling at the given interval.
+func NewWorker(repo *repositories.JobRepository, interval time.Duration) *Worker {
+	return &Worker{repo: repo, handlers: make(map[string]Handler), interval: interval}
+}
+
+// Register binds a handler to a job type. It must be called before Start.
+func (w *Worker) Register(jobType string, handler Handler) {
+	w.handlers[jobType] = handler
+}
+
+// Start processes jobs until ctx is cancelled.
+func (w *Worker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for w.runOne() {
+			}
+		}
+	}
+}
+
+// runOne claims and runs a single job, reporting whether one was found.
+func (w *Worker) runOne() bool {
+	job, err := w.repo.ClaimNext()
+	if err != nil {
+		log.Printf("jobs: claiming job: %v", err)
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		w.fail(job, fmt.Errorf("no handler registered for job type %q", job.Type))
+		return true
+	}
+	if err := handler([]byte(job.Payload)); err != nil {
+		w.fail(job, err)
+		return true
+	}
+	if err := w.repo.MarkDone(job.ID); err != nil {
+		log.Printf("jobs: marking job %d done: %v", job.ID, err)
+	}
+	return true
+}
+
+func (w *Worker) fail(job *models.Job, runErr error) {
+	log.Printf("jobs: job %d (%s) failed: %v", job.ID, job.Type, runErr)
+	// Exponential backoff: 30s, 60s, 120s, ...
+	backoff := 30 * time.Second << uint(job.Attempts)
+	if err := w.repo.MarkFailed(job, runErr, backoff); err != nil {
+		log.Printf("jobs: recording failure for job %d: %v", job.ID, err)
+	}
+}
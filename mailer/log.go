@@ -0,0 +1,13 @@
+package mailer
+
+import "log"
+
+// LogMailer writes emails to the process log instead of sending them.
+// It is the default driver for local development and tests.
+type LogMailer struct{}
+
+// Send implements Mailer.
+func (LogMailer) Send(msg Message) error {
+	log.Printf("mail (dev mode) to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Text)
+	return nil
+}
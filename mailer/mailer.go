@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"fmt"
+
+	"github.com/marc0cl/wets-ma-bu-api/config"
+)
+
+// Message is a rendered email ready to send.
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Mailer sends transactional email. Drivers are selected via MAIL_DRIVER.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// NewFromConfig builds the configured driver. "log" is the dev default:
+// emails are printed instead of sent.
+func NewFromConfig(cfg *config.Config) (Mailer, error) {
+	switch cfg.MailDriver {
+	case "log", "":
+		return LogMailer{}, nil
+	case "smtp":
+		return &SMTPMailer{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUser,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		}, nil
+	case "sendgrid":
+		return &SendGridMailer{APIKey: cfg.SendGridAPIKey, From: cfg.MailFrom}, nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver %q", cfg.MailDriver)
+	}
+}
@@ -0,0 +1,23 @@
+package mailer
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// Render fills the named HTML template with data. Template names are the
+// file names under mailer/templates, e.g. "welcome.html".
+func Render(name string, data interface{}) (string, error) {
+	var b strings.Builder
+	if err := templates.ExecuteTemplate(&b, name, data); err != nil {
+		return "", fmt.Errorf("rendering mail template %s: %w", name, err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,474 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	echomw "github.com/labstack/echo/v4/middleware"
+	gommonlog "github.com/labstack/gommon/log"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/marc0cl/wets-ma-bu-api/backup"
+	"github.com/marc0cl/wets-ma-bu-api/booking"
+	"github.com/marc0cl/wets-ma-bu-api/breaker"
+	"github.com/marc0cl/wets-ma-bu-api/cache"
+	"github.com/marc0cl/wets-ma-bu-api/config"
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/errreport"
+	"github.com/marc0cl/wets-ma-bu-api/events"
+	"github.com/marc0cl/wets-ma-bu-api/handlers"
+	"github.com/marc0cl/wets-ma-bu-api/hashing"
+	"github.com/marc0cl/wets-ma-bu-api/jobs"
+	"github.com/marc0cl/wets-ma-bu-api/mailer"
+	"github.com/marc0cl/wets-ma-bu-api/marketing"
+	appmw "github.com/marc0cl/wets-ma-bu-api/middleware"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/ocr"
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+	"github.com/marc0cl/wets-ma-bu-api/push"
+	"github.com/marc0cl/wets-ma-bu-api/redis"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/reviews"
+	"github.com/marc0cl/wets-ma-bu-api/routes"
+	"github.com/marc0cl/wets-ma-bu-api/searchindex"
+	"github.com/marc0cl/wets-ma-bu-api/sentiment"
+	"github.com/marc0cl/wets-ma-bu-api/services"
+	"github.com/marc0cl/wets-ma-bu-api/storage"
+	"github.com/marc0cl/wets-ma-bu-api/wallet"
+)
+
+func main() {
+	cfg := config.Load()
+	runtime := config.NewRuntime(cfg)
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := database.Migrate(db); err != nil {
+		log.Fatal(err)
+	}
+
+	backupRepo := repositories.NewBackupRepository(db)
+	var backupRunner *backup.Runner
+	if cfg.S3Bucket != "" {
+		encKey, err := hex.DecodeString(cfg.BackupEncryptionKey)
+		if err != nil {
+			log.Fatal("invalid BACKUP_ENCRYPTION_KEY: ", err)
+		}
+		store := storage.NewS3Client(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey)
+		backupRunner, err = backup.NewRunner(db, store, backupRepo, encKey)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Maintenance subcommands run and exit instead of serving.
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "seed":
+			if err := database.Seed(db); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "backup":
+			if backupRunner == nil {
+				log.Fatal("backup requires S3_BUCKET to be configured")
+			}
+			meta, err := backupRunner.Run()
+			if err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("backup complete: %s (%d bytes)", meta.Key, meta.SizeBytes)
+			return
+		case "restore":
+			if backupRunner == nil {
+				log.Fatal("restore requires S3_BUCKET to be configured")
+			}
+			if len(os.Args) < 3 {
+				log.Fatal("usage: restore <backup-key>")
+			}
+			if err := backupRunner.Restore(os.Args[2]); err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("restore complete: %s", os.Args[2])
+			return
+		}
+	}
+
+	repositories.ConfigureRetry(cfg.DBRetryMaxAttempts, time.Duration(cfg.DBRetryBaseDelayMS)*time.Millisecond)
+
+	dbBreaker := breaker.New(cfg.BreakerFailureThreshold, time.Duration(cfg.BreakerOpenTimeoutSeconds)*time.Second)
+	if err := breaker.Instrument(db, dbBreaker); err != nil {
+		log.Fatal(err)
+	}
+
+	userRepo := repositories.NewUserRepository(db)
+	restaurantRepo := repositories.NewRestaurantRepository(db)
+	menuRepo := repositories.NewMenuRepository(db)
+	reservationRepo := repositories.NewReservationRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+	outboxRepo := repositories.NewOutboxRepository(db)
+	txManager := repositories.NewTxManager(db)
+	jobRepo := repositories.NewJobRepository(db)
+	deviceTokenRepo := repositories.NewDeviceTokenRepository(db)
+	imageRepo := repositories.NewImageRepository(db)
+	customerRepo := repositories.NewCustomerRepository(db)
+	refundRepo := repositories.NewRefundRepository(db)
+	disputeRepo := repositories.NewDisputeRepository(db)
+	analyticsRepo := repositories.NewAnalyticsRepository(db)
+	accountMappingRepo := repositories.NewAccountMappingRepository(db)
+	staffRepo := repositories.NewStaffRepository(db)
+	experimentRepo := repositories.NewExperimentRepository(db)
+	segmentRepo := repositories.NewSegmentRepository(db)
+	marketingRepo := repositories.NewMarketingRepository(db)
+	consentRepo := repositories.NewConsentRepository(db)
+	birthdayRepo := repositories.NewBirthdayRepository(db)
+	couponRepo := repositories.NewCouponRepository(db)
+	eventRepo := repositories.NewEventRepository(db)
+	cateringRepo := repositories.NewCateringRepository(db)
+	roomRepo := repositories.NewRoomRepository(db)
+	royaltyRepo := repositories.NewRoyaltyRepository(db)
+	healthRepo := repositories.NewHealthRepository(db)
+	licenseRepo := repositories.NewLicenseRepository(db)
+	complianceRepo := repositories.NewComplianceRepository(db)
+	invoiceRepo := repositories.NewInvoiceRepository(db)
+	inventoryRepo := repositories.NewInventoryRepository(db)
+	wasteRepo := repositories.NewWasteRepository(db)
+	temperatureRepo := repositories.NewTemperatureRepository(db)
+	trainingRepo := repositories.NewTrainingRepository(db)
+	swapRepo := repositories.NewSwapRepository(db)
+	timeclockRepo := repositories.NewTimeclockRepository(db)
+	approvalRepo := repositories.NewApprovalRepository(db)
+	brandRepo := repositories.NewBrandRepository(db)
+	apiKeyRepo := repositories.NewAPIKeyRepository(db)
+	bookingAccountRepo := repositories.NewBookingAccountRepository(db)
+	reviewRepo := repositories.NewReviewRepository(db)
+	reviewAccountRepo := repositories.NewReviewAccountRepository(db)
+	faqRepo := repositories.NewFAQRepository(db)
+	loyaltyPassRepo := repositories.NewLoyaltyPassRepository(db)
+	printerRepo := repositories.NewPrinterRepository(db)
+	hardwareRepo := repositories.NewHardwareRepository(db)
+	webhookRepo := repositories.NewWebhookRepository(db)
+
+	appCache := cache.NewMemory()
+
+	mail, err := mailer.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	reporter, err := errreport.NewFromDSN(cfg.SentryDSN)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var searchIdx services.SearchIndex
+	if cfg.SearchDriver == "elasticsearch" {
+		searchIdx = searchindex.NewClient(cfg.ElasticURL, cfg.ElasticIndex)
+	}
+
+	hasher, err := hashing.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	paymentsProvider, err := payments.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ocrExtractor, err := ocr.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	bookingProvider, err := booking.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	reviewsProvider, err := reviews.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sentimentAnalyzer, err := sentiment.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	walletProvider, err := wallet.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	authService := services.NewAuthService(userRepo, txManager, outboxRepo, hasher, cfg.JWTSecret)
+	userService := services.NewUserService(userRepo, restaurantRepo, txManager, cfg.UserDeletePolicy)
+	restaurantService := services.NewRestaurantService(restaurantRepo, txManager, outboxRepo, appCache, searchIdx)
+	approvalService := services.NewApprovalService(approvalRepo, restaurantRepo, userRepo, jobRepo)
+	menuService := services.NewMenuService(menuRepo, appCache, searchIdx, approvalService)
+	reservationService := services.NewReservationService(reservationRepo, restaurantRepo, customerRepo, paymentsProvider)
+	stockService := services.NewStockService(inventoryRepo, restaurantRepo, userRepo, jobRepo)
+	loyaltyService := services.NewLoyaltyService(loyaltyPassRepo, orderRepo, restaurantRepo, userRepo, walletProvider)
+	printerService := services.NewPrinterService(printerRepo, restaurantRepo)
+	orderService := services.NewOrderService(orderRepo, menuRepo, refundRepo, txManager, outboxRepo, paymentsProvider, stockService, approvalService, loyaltyService, printerService, cfg.TaxRatePct)
+	// Approving an inbox entry runs the action it was holding back.
+	approvalService.Register(services.ApprovedRefundKind, func(approval *models.Approval) error {
+		_, err := orderService.ExecuteApprovedRefund(approval.Payload)
+		return err
+	})
+	approvalService.Register(services.ApprovedDiscountKind, func(approval *models.Approval) error {
+		_, err := menuService.ExecuteApprovedDiscount(approval.Payload)
+		return err
+	})
+	pushService := services.NewPushService(deviceTokenRepo, push.NewFromConfig(cfg))
+	imageService := services.NewImageService(imageRepo, jobRepo, cfg.MediaDir)
+	customerService := services.NewCustomerService(customerRepo, orderRepo, reservationRepo)
+	disputeService := services.NewDisputeService(disputeRepo, orderRepo)
+	analyticsService := services.NewAnalyticsService(analyticsRepo, restaurantRepo, menuRepo, appCache)
+	accountingService := services.NewAccountingService(analyticsRepo, accountMappingRepo)
+	staffService := services.NewStaffService(staffRepo)
+	experimentService := services.NewExperimentService(experimentRepo)
+	segmentService := services.NewSegmentService(segmentRepo)
+	marketingProvider, err := marketing.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	consentService := services.NewConsentService(consentRepo)
+	marketingService := services.NewMarketingService(marketingRepo, segmentService, consentService, marketingProvider)
+	birthdayService := services.NewBirthdayService(birthdayRepo, couponRepo, consentService, jobRepo)
+	eventService := services.NewEventService(eventRepo, customerRepo, paymentsProvider)
+	cateringService := services.NewCateringService(cateringRepo, menuRepo)
+	roomService := services.NewRoomService(roomRepo, restaurantRepo)
+	royaltyService := services.NewRoyaltyService(royaltyRepo, restaurantRepo)
+	healthService := services.NewHealthService(healthRepo, restaurantRepo, cfg.MediaDir)
+	licenseService := services.NewLicenseService(licenseRepo, restaurantRepo, userRepo, jobRepo, cfg.MediaDir)
+	complianceService := services.NewComplianceService(complianceRepo, restaurantRepo, staffRepo, userRepo, jobRepo)
+	invoiceService := services.NewInvoiceService(invoiceRepo, jobRepo, cfg.MediaDir)
+	recipeService := services.NewRecipeService(inventoryRepo, menuRepo)
+	wasteService := services.NewWasteService(wasteRepo, inventoryRepo)
+	temperatureService := services.NewTemperatureService(temperatureRepo, restaurantRepo, userRepo, jobRepo)
+	trainingService := services.NewTrainingService(trainingRepo, staffRepo, restaurantRepo, userRepo, jobRepo)
+	swapService := services.NewSwapService(swapRepo, staffRepo, jobRepo)
+	timeclockService := services.NewTimeclockService(timeclockRepo, staffRepo, restaurantRepo, hasher)
+	brandService := services.NewBrandService(brandRepo, restaurantRepo)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, restaurantRepo)
+	meteringService := services.NewMeteringService(apiKeyRepo, paymentsProvider)
+	embedService := services.NewEmbedService(restaurantRepo, menuRepo)
+	bookingSyncService := services.NewBookingSyncService(bookingAccountRepo, reservationRepo, restaurantRepo, bookingProvider)
+	reviewService := services.NewReviewService(reviewRepo, reviewAccountRepo, restaurantRepo, reviewsProvider, sentimentAnalyzer)
+	faqService := services.NewFAQService(faqRepo, restaurantRepo, menuRepo)
+	emailService := services.NewEmailService(restaurantRepo, brandService, jobRepo)
+	syncService := services.NewSyncService(menuRepo, orderRepo, reservationRepo, orderService, reservationService, menuService)
+	hardwareService := services.NewHardwareService(hardwareRepo, restaurantRepo)
+	webhookService := services.NewWebhookService(webhookRepo, restaurantRepo, brandService, jobRepo)
+
+	// Background workers run until shutdown begins.
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+	publisher, err := events.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Every outbox event reaches the broker and, when the restaurant has
+	// one configured, its webhook endpoint.
+	relay := events.NewRelay(outboxRepo, events.Fanout{publisher, webhookService}, 5*time.Second)
+	go relay.Start(bgCtx)
+	worker := jobs.NewWorker(jobRepo, 2*time.Second)
+	worker.Register("email.send", jobs.EmailHandler(mail))
+	worker.Register("image.process", jobs.ImageHandler(imageRepo, cfg.MediaDir))
+	worker.Register("invoice.extract", jobs.InvoiceHandler(invoiceRepo, ocrExtractor))
+	worker.Register("webhook.deliver", jobs.WebhookHandler(webhookRepo, restaurantRepo))
+	go worker.Start(bgCtx)
+	go birthdayService.Start(bgCtx)
+	go licenseService.Start(bgCtx)
+	go complianceService.Start(bgCtx)
+	go trainingService.Start(bgCtx)
+	go bookingSyncService.Start(bgCtx)
+	go reviewService.Start(bgCtx)
+	go meteringService.Start(bgCtx)
+
+	e := echo.New()
+	e.HideBanner = true
+	e.HTTPErrorHandler = handlers.HTTPErrorHandler
+	applyLogLevel := func(settings *config.Reloadable) {
+		e.Logger.SetLevel(logLevelFor(settings.LogLevel))
+	}
+	applyLogLevel(runtime.Current())
+	runtime.OnReload(applyLogLevel)
+	// Bound what a single connection can cost us; Echo's zero-value
+	// defaults would leave all of these unlimited.
+	e.Server.ReadTimeout = time.Duration(cfg.ServerReadTimeoutSeconds) * time.Second
+	e.Server.ReadHeaderTimeout = time.Duration(cfg.ServerReadHeaderTimeoutSeconds) * time.Second
+	e.Server.WriteTimeout = time.Duration(cfg.ServerWriteTimeoutSeconds) * time.Second
+	e.Server.IdleTimeout = time.Duration(cfg.ServerIdleTimeoutSeconds) * time.Second
+	e.Server.MaxHeaderBytes = cfg.ServerMaxHeaderBytes
+	e.TLSServer.ReadTimeout = e.Server.ReadTimeout
+	e.TLSServer.ReadHeaderTimeout = e.Server.ReadHeaderTimeout
+	e.TLSServer.WriteTimeout = e.Server.WriteTimeout
+	e.TLSServer.IdleTimeout = e.Server.IdleTimeout
+	e.TLSServer.MaxHeaderBytes = cfg.ServerMaxHeaderBytes
+	e.DisableHTTP2 = !cfg.HTTP2Enabled
+	e.Pre(appmw.AutoHEAD())
+	e.Use(echomw.RequestID())
+	e.Use(echomw.LoggerWithConfig(echomw.LoggerConfig{
+		// Include the request ID so log lines can be correlated with the
+		// request_id field returned in error responses.
+		Format: `{"time":"${time_rfc3339}","id":"${id}","remote_ip":"${remote_ip}",` +
+			`"method":"${method}","uri":"${uri}","status":${status},"error":"${error}",` +
+			`"latency_human":"${latency_human}"}` + "\n",
+	}))
+	e.Use(echomw.Recover())
+	e.Use(appmw.ErrorReport(reporter))
+	tlsEnabled := cfg.AutoTLSEnabled || (cfg.TLSCertFile != "" && cfg.TLSKeyFile != "")
+	e.Use(appmw.SecureHeaders(tlsEnabled))
+	e.Use(appmw.CORS(appmw.CORSOptions{
+		AllowOrigins:     cfg.CORSAllowOrigins,
+		AllowMethods:     cfg.CORSAllowMethods,
+		AllowHeaders:     cfg.CORSAllowHeaders,
+		AllowCredentials: cfg.CORSAllowCredentials,
+	}))
+	e.Use(appmw.CircuitBreaker(dbBreaker))
+	var redisClient *redis.Client
+	if cfg.RedisAddr != "" {
+		redisClient = redis.NewClient(cfg.RedisAddr, cfg.RedisPassword)
+	}
+	e.Use(appmw.RateLimit(runtime, cfg.JWTSecret, redisClient))
+	e.Use(appmw.Timeout(time.Duration(cfg.RequestTimeoutSeconds)*time.Second, nil))
+	// No routes are deprecated right now; add entries here when phasing
+	// one out.
+	deprecations := appmw.NewDeprecationRegistry(nil)
+	e.Use(deprecations.Middleware())
+	e.Use(appmw.CacheControl(map[string]string{
+		// Public menus may be cached briefly; everything else stays no-store.
+		"/restaurants/:id/menu": "public, max-age=60",
+		// Embed feeds sit on third-party pages, so let shared caches
+		// hold them a little longer.
+		"/embed/:slug/menu.json": "public, max-age=300",
+		"/embed/:slug/menu.html": "public, max-age=300",
+		"/embed/:slug/oembed":    "public, max-age=3600",
+		// Crawlers poll the structured feed; an hour of staleness is fine.
+		"/embed/:slug/restaurant.jsonld": "public, max-age=3600",
+	}))
+
+	e.Static("/media", cfg.MediaDir)
+
+	routes.Setup(e, &routes.Handlers{
+		Auth:        handlers.NewAuthHandler(authService),
+		User:        handlers.NewUserHandler(userService),
+		Restaurant:  handlers.NewRestaurantHandler(restaurantService),
+		Menu:        handlers.NewMenuHandler(menuService, restaurantService, experimentService),
+		Reservation: handlers.NewReservationHandler(reservationService, restaurantService, pushService),
+		Order:       handlers.NewOrderHandler(orderService, restaurantService, pushService, experimentService),
+		Summary:     handlers.NewSummaryHandler(restaurantService, reservationService),
+		Admin:       handlers.NewAdminHandler(restaurantService, deprecations, jobRepo, backupRunner, backupRepo, runtime),
+		Search:      handlers.NewSearchHandler(services.NewSearchService(restaurantRepo, menuRepo, userRepo, searchIdx)),
+		Metrics:     handlers.NewMetricsHandler(db, dbBreaker),
+		Device:      handlers.NewDeviceHandler(pushService),
+		Image:       handlers.NewImageHandler(imageService, restaurantService, menuService),
+		Customer:    handlers.NewCustomerHandler(customerService),
+		Dispute:     handlers.NewDisputeHandler(disputeService, orderService, restaurantService, pushService, cfg.PaymentsWebhookSecret),
+		Analytics:   handlers.NewAnalyticsHandler(analyticsService, restaurantService),
+		Accounting:  handlers.NewAccountingHandler(accountingService, restaurantService),
+		Staff:       handlers.NewStaffHandler(staffService, restaurantService),
+		Experiment:  handlers.NewExperimentHandler(experimentService, restaurantService),
+		Segment:     handlers.NewSegmentHandler(segmentService, restaurantService),
+		Marketing:   handlers.NewMarketingHandler(marketingService, restaurantService),
+		Consent:     handlers.NewConsentHandler(consentService),
+		Birthday:    handlers.NewBirthdayHandler(birthdayService, restaurantService),
+		Event:       handlers.NewEventHandler(eventService, restaurantService),
+		Catering:    handlers.NewCateringHandler(cateringService, restaurantService),
+		Room:        handlers.NewRoomHandler(roomService, restaurantService),
+		Royalty:     handlers.NewRoyaltyHandler(royaltyService, restaurantService),
+		Health:      handlers.NewHealthHandler(healthService, restaurantService),
+		License:     handlers.NewLicenseHandler(licenseService, restaurantService),
+		Compliance:  handlers.NewComplianceHandler(complianceService, restaurantService),
+		Invoice:     handlers.NewInvoiceHandler(invoiceService, restaurantService),
+		Recipe:      handlers.NewRecipeHandler(recipeService, stockService, wasteService, menuService, restaurantService),
+		Waste:       handlers.NewWasteHandler(wasteService, restaurantService),
+		Temperature: handlers.NewTemperatureHandler(temperatureService, restaurantService),
+		Training:    handlers.NewTrainingHandler(trainingService, staffService, restaurantService),
+		Swap:        handlers.NewSwapHandler(swapService, staffService, restaurantService),
+		Timeclock:   handlers.NewTimeclockHandler(timeclockService, staffService, restaurantService),
+		Approval:    handlers.NewApprovalHandler(approvalService, restaurantService),
+		Brand:       handlers.NewBrandHandler(brandService, restaurantService),
+		APIKey:      handlers.NewAPIKeyHandler(apiKeyService, menuService, reservationService, restaurantService),
+		Embed:       handlers.NewEmbedHandler(embedService),
+		BookingSync: handlers.NewBookingSyncHandler(bookingSyncService, restaurantService),
+		Review:      handlers.NewReviewHandler(reviewService, restaurantService),
+		FAQ:         handlers.NewFAQHandler(faqService, restaurantService),
+		Loyalty:     handlers.NewLoyaltyHandler(loyaltyService, restaurantService),
+		Email:       handlers.NewEmailHandler(emailService, restaurantService),
+		Printer:     handlers.NewPrinterHandler(printerService, orderService, restaurantService),
+		Sync:        handlers.NewSyncHandler(syncService, restaurantService),
+		Hardware:    handlers.NewHardwareHandler(hardwareService, restaurantService),
+		Webhook:     handlers.NewWebhookHandler(webhookService, restaurantService),
+	}, cfg.JWTSecret)
+
+	// Start serving and drain gracefully on SIGINT/SIGTERM so in-flight
+	// requests survive deploys.
+	go func() {
+		var err error
+		switch {
+		case cfg.AutoTLSEnabled:
+			e.AutoTLSManager.Cache = autocert.DirCache(cfg.AutoTLSCacheDir)
+			if cfg.AutoTLSHosts != "" {
+				e.AutoTLSManager.HostPolicy = autocert.HostWhitelist(strings.Split(cfg.AutoTLSHosts, ",")...)
+			}
+			err = e.StartAutoTLS(":" + cfg.Port)
+		case tlsEnabled:
+			err = e.StartTLS(":"+cfg.Port, cfg.TLSCertFile, cfg.TLSKeyFile)
+		default:
+			err = e.Start(":" + cfg.Port)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	// SIGHUP re-reads the environment and applies reloadable settings.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			runtime.Reload()
+			log.Println("configuration reloaded on SIGHUP")
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+	bgCancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeoutSeconds)*time.Second)
+	defer cancel()
+	if err := e.Shutdown(ctx); err != nil {
+		log.Printf("forced shutdown: %v", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
+
+// logLevelFor maps a config log level name onto the echo logger's.
+func logLevelFor(name string) gommonlog.Lvl {
+	switch name {
+	case "debug":
+		return gommonlog.DEBUG
+	case "warn":
+		return gommonlog.WARN
+	case "error":
+		return gommonlog.ERROR
+	default:
+		return gommonlog.INFO
+	}
+}
@@ -0,0 +1,86 @@
+package marketing
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MailchimpClient upserts contacts through Mailchimp's REST API. Like
+// the other outbound clients in this codebase it is a thin hand-rolled
+// wrapper rather than the full vendor SDK.
+type MailchimpClient struct {
+	baseURL string // overrides the key-derived datacenter when set (tests)
+	client  *http.Client
+}
+
+// NewMailchimpClient builds a MailchimpClient.
+func NewMailchimpClient() *MailchimpClient {
+	return &MailchimpClient{client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// apiBase derives the API host from the key: Mailchimp keys end in the
+// datacenter they live on, e.g. "...-us6".
+func (m *MailchimpClient) apiBase(apiKey string) (string, error) {
+	if m.baseURL != "" {
+		return m.baseURL, nil
+	}
+	_, dc, ok := strings.Cut(apiKey, "-")
+	if !ok || dc == "" {
+		return "", fmt.Errorf("mailchimp api key has no datacenter suffix")
+	}
+	return fmt.Sprintf("https://%s.api.mailchimp.com", dc), nil
+}
+
+// SyncContacts implements Provider. Members are upserted one by one via
+// PUT, which Mailchimp treats as create-or-update; the first failure
+// stops the sync and reports how far it got.
+func (m *MailchimpClient) SyncContacts(apiKey, listID string, contacts []Contact) (int, error) {
+	base, err := m.apiBase(apiKey)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, contact := range contacts {
+		payload, err := json.Marshal(map[string]interface{}{
+			"email_address": contact.Email,
+			"status_if_new": "subscribed",
+			"merge_fields":  map[string]string{"FNAME": contact.Name},
+			"tags":          contact.Tags,
+		})
+		if err != nil {
+			return i, err
+		}
+
+		// Mailchimp addresses members by the md5 of the lowercased email.
+		hash := fmt.Sprintf("%x", md5.Sum([]byte(strings.ToLower(contact.Email))))
+		url := fmt.Sprintf("%s/3.0/lists/%s/members/%s", base, listID, hash)
+		req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(payload))
+		if err != nil {
+			return i, err
+		}
+		req.SetBasicAuth("anystring", apiKey)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := m.client.Do(req)
+		if err != nil {
+			return i, fmt.Errorf("mailchimp sync: %w", err)
+		}
+		var body struct {
+			Detail string `json:"detail"`
+		}
+		decodeErr := json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			if decodeErr == nil && body.Detail != "" {
+				return i, fmt.Errorf("mailchimp sync: %s", body.Detail)
+			}
+			return i, fmt.Errorf("mailchimp sync: status %d", resp.StatusCode)
+		}
+	}
+	return len(contacts), nil
+}
@@ -0,0 +1,47 @@
+package marketing
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/marc0cl/wets-ma-bu-api/config"
+)
+
+// Contact is one subscriber pushed to the marketing platform.
+type Contact struct {
+	Email string
+	Name  string
+	Tags  []string
+}
+
+// Provider pushes contacts to an external marketing platform. Unlike
+// the other outbound drivers, credentials are per restaurant and travel
+// with each call instead of living in the client.
+type Provider interface {
+	// SyncContacts upserts contacts into the audience identified by
+	// listID and returns how many were accepted.
+	SyncContacts(apiKey, listID string, contacts []Contact) (int, error)
+}
+
+// LogProvider logs syncs instead of executing them; it is the dev
+// default.
+type LogProvider struct{}
+
+// SyncContacts implements Provider.
+func (LogProvider) SyncContacts(apiKey, listID string, contacts []Contact) (int, error) {
+	log.Printf("marketing (dev mode) sync list=%s contacts=%d", listID, len(contacts))
+	return len(contacts), nil
+}
+
+// NewFromConfig builds the configured driver. "log" is the dev default:
+// syncs are printed instead of executed.
+func NewFromConfig(cfg *config.Config) (Provider, error) {
+	switch cfg.MarketingDriver {
+	case "log", "":
+		return LogProvider{}, nil
+	case "mailchimp":
+		return NewMailchimpClient(), nil
+	default:
+		return nil, fmt.Errorf("unsupported marketing driver %q", cfg.MarketingDriver)
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AutoHEAD serves HEAD requests through the matching GET handler while
+// discarding the body. Register it with e.Pre so the method is rewritten
+// before routing.
+func AutoHEAD() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Method == http.MethodHead {
+				c.Request().Method = http.MethodGet
+				c.Response().Writer = headWriter{c.Response().Writer}
+			}
+			return next(c)
+		}
+	}
+}
+
+// headWriter swallows the body but keeps headers and status, so HEAD
+// responses mirror their GET counterparts without transferring data.
+type headWriter struct {
+	http.ResponseWriter
+}
+
+func (w headWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// CacheControl sets a Cache-Control header per registered route. Routes
+// without an explicit policy default to no-store, so private data is never
+// cached by intermediaries.
+func CacheControl(policies map[string]string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			policy, ok := policies[c.Path()]
+			if !ok {
+				policy = "no-store"
+			}
+			c.Response().Header().Set("Cache-Control", policy)
+			return next(c)
+		}
+	}
+}
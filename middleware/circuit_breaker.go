@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/breaker"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// CircuitBreaker rejects requests with 503 while the database breaker is
+// open, so callers fail fast instead of queueing behind a dead database.
+// Health and metrics routes stay reachable so operators can see the
+// breaker state during an outage.
+func CircuitBreaker(b *breaker.Breaker) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if b == nil {
+				return next(c)
+			}
+			switch c.Path() {
+			case "/readyz", "/metrics":
+				return next(c)
+			}
+			if !b.Allow() {
+				return utils.Error(c, http.StatusServiceUnavailable, "database unavailable, try again shortly")
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	echomw "github.com/labstack/echo/v4/middleware"
+)
+
+// CORSOptions configures the CORS middleware. Origins, methods and
+// headers are comma-separated lists from the environment; origins may
+// use wildcard subdomains (https://*.example.com), which Echo matches
+// natively.
+type CORSOptions struct {
+	AllowOrigins     string
+	AllowMethods     string
+	AllowHeaders     string
+	AllowCredentials bool
+}
+
+// CORS returns the CORS middleware used by the API. Browsers reject
+// AllowCredentials combined with a literal "*" origin, so credentials
+// are dropped in that case rather than producing responses no browser
+// will accept.
+func CORS(opts CORSOptions) echo.MiddlewareFunc {
+	origins := splitList(opts.AllowOrigins)
+	if len(origins) == 0 {
+		origins = []string{"*"}
+	}
+
+	credentials := opts.AllowCredentials
+	for _, o := range origins {
+		if o == "*" {
+			credentials = false
+			break
+		}
+	}
+
+	return echomw.CORSWithConfig(echomw.CORSConfig{
+		AllowOrigins:     origins,
+		AllowMethods:     splitList(opts.AllowMethods),
+		AllowHeaders:     splitList(opts.AllowHeaders),
+		AllowCredentials: credentials,
+	})
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
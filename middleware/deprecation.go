@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DeprecatedRoute describes why and until when an old route is kept.
+type DeprecatedRoute struct {
+	// Sunset is when the route will be removed; zero means undecided.
+	Sunset  time.Time
+	Message string
+}
+
+// DeprecationRegistry marks routes as deprecated and counts how often
+// they are still hit, so maintainers know when removal is safe.
+type DeprecationRegistry struct {
+	mu     sync.Mutex
+	routes map[string]DeprecatedRoute
+	counts map[string]int64
+}
+
+// NewDeprecationRegistry builds a registry over the given route map,
+// keyed by the registered route path (e.g. "/restaurants/:id").
+func NewDeprecationRegistry(routes map[string]DeprecatedRoute) *DeprecationRegistry {
+	if routes == nil {
+		routes = map[string]DeprecatedRoute{}
+	}
+	return &DeprecationRegistry{routes: routes, counts: map[string]int64{}}
+}
+
+// Middleware adds Deprecation/Sunset/Warning headers on deprecated routes
+// and records each hit.
+func (r *DeprecationRegistry) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			route, ok := r.routes[c.Path()]
+			if !ok {
+				return next(c)
+			}
+
+			r.mu.Lock()
+			r.counts[c.Path()]++
+			r.mu.Unlock()
+
+			h := c.Response().Header()
+			h.Set("Deprecation", "true")
+			if !route.Sunset.IsZero() {
+				h.Set("Sunset", route.Sunset.UTC().Format(time.RFC1123))
+			}
+			if route.Message != "" {
+				h.Set("Warning", fmt.Sprintf("299 - %q", route.Message))
+			}
+			return next(c)
+		}
+	}
+}
+
+// Usage returns a copy of the per-route hit counters.
+func (r *DeprecationRegistry) Usage() map[string]int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]int64, len(r.counts))
+	for k, v := range r.counts {
+		out[k] = v
+	}
+	return out
+}
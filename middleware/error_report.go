@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/errreport"
+	"github.com/marc0cl/wets-ma-bu-api/services"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// ErrorReport ships panics and 5xx responses to the configured error
+// sink with route, user and request-ID context. Register it inside
+// (after) the Recover middleware so panics pass through here first.
+func ErrorReport(reporter errreport.Reporter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					reporter.Report(eventFor(c, fmt.Sprintf("panic: %v", r), string(debug.Stack())))
+					panic(r) // let the Recover middleware produce the response
+				}
+			}()
+
+			err = next(c)
+			if err != nil {
+				// Typed service errors are client failures (4xx) by
+				// definition; only untyped errors and 5xx HTTPErrors are
+				// worth a report.
+				var svcErr *services.Error
+				if errors.As(err, &svcErr) {
+					return err
+				}
+				if he, ok := err.(*echo.HTTPError); !ok || he.Code >= http.StatusInternalServerError {
+					reporter.Report(eventFor(c, err.Error(), ""))
+				}
+				return err
+			}
+			if c.Response().Status >= http.StatusInternalServerError {
+				reporter.Report(eventFor(c, fmt.Sprintf("response status %d", c.Response().Status), ""))
+			}
+			return nil
+		}
+	}
+}
+
+func eventFor(c echo.Context, message, stack string) errreport.Event {
+	event := errreport.Event{
+		Message:   message,
+		Route:     c.Path(),
+		Method:    c.Request().Method,
+		RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+		Stack:     stack,
+	}
+	// Attach the user when a token has been parsed; anonymous requests
+	// simply omit it.
+	if claims, err := utils.GetClaims(c); err == nil {
+		event.UserID = claims.UserID
+	}
+	return event
+}
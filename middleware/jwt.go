@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// JWT validates the Authorization header and stores the parsed claims
+// in the context for utils.GetClaims.
+func JWT(secret string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get("Authorization")
+			if !strings.HasPrefix(header, "Bearer ") {
+				return utils.Error(c, http.StatusUnauthorized, "missing or malformed token")
+			}
+			claims, ok := parseToken(strings.TrimPrefix(header, "Bearer "), secret)
+			if !ok {
+				return utils.Error(c, http.StatusUnauthorized, "invalid or expired token")
+			}
+			utils.SetClaims(c, claims)
+			return next(c)
+		}
+	}
+}
+
+// OptionalJWT stores the parsed claims when a valid token is present but
+// lets the request through either way. Public endpoints use it so a
+// logged-in customer can be attached to what they create while guests
+// remain welcome.
+func OptionalJWT(secret string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if header := c.Request().Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+				if claims, ok := parseToken(strings.TrimPrefix(header, "Bearer "), secret); ok {
+					utils.SetClaims(c, claims)
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+// parseToken validates a bearer token and returns its claims.
+func parseToken(tokenString, secret string) (*utils.JWTClaims, bool) {
+	token, err := jwt.ParseWithClaims(tokenString, &utils.JWTClaims{}, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(secret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, false
+	}
+	claims, ok := token.Claims.(*utils.JWTClaims)
+	return claims, ok
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+
+	"github.com/marc0cl/wets-ma-bu-api/config"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/redis"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+type rateVisitor struct {
+	limiter  *rate.Limiter
+	rpm      int
+	lastSeen time.Time
+}
+
+type rateLimiterStore struct {
+	mu       sync.Mutex
+	visitors map[string]*rateVisitor
+}
+
+func (s *rateLimiterStore) get(key string, rpm int) *rate.Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.visitors[key]
+	if !ok {
+		v = &rateVisitor{limiter: rate.NewLimiter(rate.Limit(rpm)/60, rpm), rpm: rpm}
+		s.visitors[key] = v
+	}
+	// Quotas are hot-reloadable; refresh the limiter if they changed.
+	if v.rpm != rpm {
+		v.limiter.SetLimit(rate.Limit(rpm) / 60)
+		v.limiter.SetBurst(rpm)
+		v.rpm = rpm
+	}
+	v.lastSeen = time.Now()
+	return v.limiter
+}
+
+func (s *rateLimiterStore) cleanup(maxAge time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, v := range s.visitors {
+		if time.Since(v.lastSeen) > maxAge {
+			delete(s.visitors, key)
+		}
+	}
+}
+
+// RateLimit returns a middleware that throttles requests per caller. The
+// caller is identified by the JWT user ID when a valid token is present,
+// by the X-API-Key header for key-based clients, and by remote IP as a
+// last resort, so one noisy user cannot exhaust a shared NAT's quota.
+// Quotas come from the runtime snapshot, so a config reload takes effect
+// without a restart.
+//
+// With a Redis client the counters are shared across replicas (a
+// one-minute window per caller); when Redis is down or not configured,
+// each replica falls back to its local token buckets, which over-admits
+// by at most the replica count rather than failing requests.
+func RateLimit(runtime *config.Runtime, jwtSecret string, remote *redis.Client) echo.MiddlewareFunc {
+	store := &rateLimiterStore{visitors: make(map[string]*rateVisitor)}
+	go func() {
+		for range time.Tick(5 * time.Minute) {
+			store.cleanup(10 * time.Minute)
+		}
+	}()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			settings := runtime.Current()
+			if !settings.RateLimitEnabled {
+				return next(c)
+			}
+
+			key, role := callerIdentity(c, jwtSecret)
+			rpm := settings.RateLimitDefaultRPM
+			switch role {
+			case models.RoleOwner:
+				rpm = settings.RateLimitOwnerRPM
+			case models.RoleAdmin:
+				rpm = settings.RateLimitAdminRPM
+			}
+
+			allowed, remaining, ok := redisAllow(remote, key, rpm)
+			if !ok {
+				limiter := store.get(key, rpm)
+				allowed = limiter.Allow()
+				remaining = int(limiter.Tokens())
+			}
+			if remaining < 0 {
+				remaining = 0
+			}
+
+			c.Response().Header().Set("RateLimit-Limit", fmt.Sprintf("%d", rpm))
+			c.Response().Header().Set("RateLimit-Reset", "60")
+			if !allowed {
+				c.Response().Header().Set("RateLimit-Remaining", "0")
+				return utils.Error(c, http.StatusTooManyRequests, "rate limit exceeded")
+			}
+			c.Response().Header().Set("RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+			return next(c)
+		}
+	}
+}
+
+// redisAllow counts the request against a shared per-minute window in
+// Redis. ok is false when Redis is not configured or unreachable, in
+// which case the caller should use the local limiter instead.
+func redisAllow(remote *redis.Client, key string, rpm int) (allowed bool, remaining int, ok bool) {
+	if remote == nil {
+		return false, 0, false
+	}
+	window := time.Now().Unix() / 60
+	counter := fmt.Sprintf("ratelimit:%s:%d", key, window)
+	n, err := remote.Incr(counter)
+	if err != nil {
+		log.Printf("rate limit: redis unavailable, using local limiter: %v", err)
+		return false, 0, false
+	}
+	if n == 1 {
+		// Keep the key past the window's end so late requests still see
+		// it, then let it expire on its own.
+		if err := remote.Expire(counter, 120); err != nil {
+			log.Printf("rate limit: setting window expiry: %v", err)
+		}
+	}
+	return n <= int64(rpm), rpm - int(n), true
+}
+
+// callerIdentity derives the rate-limit bucket key and role for a request
+// without failing it: an invalid token simply downgrades to IP keying and
+// is rejected later by the JWT middleware where it matters.
+func callerIdentity(c echo.Context, jwtSecret string) (key string, role models.Role) {
+	if header := c.Request().Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		tokenString := strings.TrimPrefix(header, "Bearer ")
+		claims := &utils.JWTClaims{}
+		token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, jwt.ErrSignatureInvalid
+			}
+			return []byte(jwtSecret), nil
+		})
+		if err == nil && token.Valid {
+			return fmt.Sprintf("user:%d", claims.UserID), claims.Role
+		}
+	}
+	if apiKey := c.Request().Header.Get("X-API-Key"); apiKey != "" {
+		return "key:" + apiKey, "api_key"
+	}
+	return "ip:" + c.RealIP(), ""
+}
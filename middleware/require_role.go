@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// RequireRole rejects requests whose token does not carry one of the
+// allowed roles. It must run after the JWT middleware.
+func RequireRole(roles ...models.Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, err := utils.GetClaims(c)
+			if err != nil {
+				return err
+			}
+			for _, role := range roles {
+				if claims.Role == role {
+					return next(c)
+				}
+			}
+			return utils.Error(c, http.StatusForbidden, "insufficient permissions")
+		}
+	}
+}
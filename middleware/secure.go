@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	echomw "github.com/labstack/echo/v4/middleware"
+)
+
+// SecureHeaders sets the usual browser hardening headers. HSTS is only
+// emitted when the server itself terminates TLS; sending it over plain
+// HTTP behind a proxy would be a no-op at best and lock out plain-HTTP
+// deployments at worst.
+func SecureHeaders(tlsEnabled bool) echo.MiddlewareFunc {
+	cfg := echomw.SecureConfig{
+		XSSProtection:      "1; mode=block",
+		ContentTypeNosniff: "nosniff",
+		XFrameOptions:      "DENY",
+	}
+	if tlsEnabled {
+		cfg.HSTSMaxAge = 31536000 // one year
+		cfg.HSTSPreloadEnabled = false
+	}
+	return echomw.SecureWithConfig(cfg)
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// Timeout caps how long a request may run. The request context is
+// cancelled when the deadline passes, so context-aware work downstream is
+// abandoned instead of piling up behind a slow query. Per-route overrides
+// take precedence over the default.
+func Timeout(def time.Duration, perRoute map[string]time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			d := def
+			if override, ok := perRoute[c.Path()]; ok {
+				d = override
+			}
+			if d <= 0 {
+				return next(c)
+			}
+
+			ctx, cancel := context.WithTimeout(c.Request().Context(), d)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			err := next(c)
+			if ctx.Err() == context.DeadlineExceeded && !c.Response().Committed {
+				return utils.Error(c, http.StatusGatewayTimeout, "request timed out")
+			}
+			return err
+		}
+	}
+}
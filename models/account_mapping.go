@@ -0,0 +1,16 @@
+package models
+
+import "gorm.io/gorm"
+
+// AccountMapping maps a restaurant's revenue streams onto the chart of
+// accounts of its bookkeeping system, so accounting exports post to the
+// right ledgers. Empty fields fall back to conventional account names.
+type AccountMapping struct {
+	gorm.Model
+	RestaurantID   uint   `gorm:"uniqueIndex;not null" json:"restaurant_id"`
+	CashAccount    string `gorm:"size:100" json:"cash_account"`
+	SalesAccount   string `gorm:"size:100" json:"sales_account"`
+	TaxAccount     string `gorm:"size:100" json:"tax_account"`
+	TipsAccount    string `gorm:"size:100" json:"tips_account"`
+	RefundsAccount string `gorm:"size:100" json:"refunds_account"`
+}
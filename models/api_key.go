@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// API plan tiers. The plan sets the key's monthly request quota and
+// which widget features it may call.
+const (
+	PlanFree       = "free"
+	PlanPro        = "pro"
+	PlanEnterprise = "enterprise"
+)
+
+// APIKey is a publishable key scoped to one restaurant's public data,
+// meant to be embedded in the owner's own website. It grants nothing an
+// anonymous visitor could not already see; the allow-list and per-key
+// rate limit exist so a leaked key is only useful from the owner's
+// domains and only at a bounded pace.
+type APIKey struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"index;not null" json:"-"`
+	Name         string `gorm:"size:100;not null" json:"name"`
+	Key          string `gorm:"size:40;uniqueIndex" json:"key"`
+	Plan         string `gorm:"size:20;default:free" json:"plan"`
+
+	// AllowedOrigins is a comma-separated list of origins the key may
+	// be used from (e.g. "https://example.com"); a "*." host prefix
+	// matches subdomains. Empty allows any origin.
+	AllowedOrigins string `gorm:"size:512" json:"allowed_origins"`
+
+	// RateLimitPerMin caps requests per minute for this key.
+	RateLimitPerMin int `gorm:"default:60" json:"rate_limit_per_min"`
+
+	// BillingRef is the billing provider's subscription item for this
+	// key (Stripe metered billing). Empty keys are not metered to the
+	// provider; the usage table still counts them.
+	BillingRef string `gorm:"size:100" json:"billing_ref"`
+
+	// RevokedAt, once set, makes the key permanently unusable.
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// BeforeCreate assigns the publishable key value.
+func (k *APIKey) BeforeCreate(*gorm.DB) error {
+	if k.Key == "" {
+		k.Key = "pk_" + NewUUID()
+	}
+	return nil
+}
+
+// APIKeyUsage counts one key's requests in one calendar month. Period
+// is "YYYY-MM"; a fresh row each month is the quota reset. Reported
+// trails Count: the difference is what the billing push has not yet
+// metered to the provider.
+type APIKeyUsage struct {
+	gorm.Model
+	APIKeyID uint   `gorm:"not null;uniqueIndex:idx_api_key_usages_key_period" json:"api_key_id"`
+	Period   string `gorm:"size:7;not null;uniqueIndex:idx_api_key_usages_key_period" json:"period"`
+	Count    int64  `gorm:"default:0" json:"count"`
+	Reported int64  `gorm:"default:0" json:"reported"`
+}
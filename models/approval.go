@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Approval statuses.
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+)
+
+// Approval is one action waiting on a manager's decision — a refund
+// above the threshold, a large discount, and whatever else registers
+// itself. Kind and SubjectID identify the gated action; Payload carries
+// what the executor needs to perform it once approved.
+type Approval struct {
+	gorm.Model
+	RestaurantID uint       `gorm:"not null;index" json:"restaurant_id"`
+	Kind         string     `gorm:"size:50;not null;index" json:"kind"`
+	SubjectID    uint       `gorm:"not null" json:"subject_id"`
+	Summary      string     `gorm:"size:255;not null" json:"summary"`
+	Amount       float64    `json:"amount"`
+	Payload      string     `gorm:"type:text" json:"-"`
+	Status       string     `gorm:"size:20;not null;default:pending;index" json:"status"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+}
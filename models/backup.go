@@ -0,0 +1,21 @@
+package models
+
+import "gorm.io/gorm"
+
+// Backup statuses.
+const (
+	BackupPending   = "pending"
+	BackupCompleted = "completed"
+	BackupFailed    = "failed"
+)
+
+// Backup tracks one data dump uploaded to object storage, so operators
+// can see what exists without listing the bucket.
+type Backup struct {
+	gorm.Model
+	Key       string `gorm:"size:255;not null;uniqueIndex" json:"key"`
+	SizeBytes int64  `json:"size_bytes"`
+	Encrypted bool   `json:"encrypted"`
+	Status    string `gorm:"size:20;default:pending" json:"status"`
+	LastError string `gorm:"type:text" json:"last_error,omitempty"`
+}
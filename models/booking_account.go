@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Booking platforms a restaurant can connect.
+const (
+	BookingPlatformOpenTable = "opentable"
+	BookingPlatformTheFork   = "thefork"
+)
+
+// BookingAccount holds a restaurant's credentials for one external
+// booking platform and the outcome of its most recent sync. The API key
+// never leaves the server.
+type BookingAccount struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"not null;index;uniqueIndex:idx_booking_accounts_restaurant_platform" json:"restaurant_id"`
+	Platform     string `gorm:"size:20;not null;uniqueIndex:idx_booking_accounts_restaurant_platform" json:"platform"`
+	APIKey       string `gorm:"size:255" json:"-"`
+	// ExternalVenueID is the platform's id for this restaurant.
+	ExternalVenueID string `gorm:"size:100" json:"external_venue_id"`
+	// WebhookKey authenticates pushes from the platform; it travels in
+	// the webhook payload the way a sensor's key does.
+	WebhookKey string `gorm:"size:36;uniqueIndex" json:"webhook_key"`
+
+	LastSyncAt     *time.Time `json:"last_sync_at"`
+	LastSyncStatus string     `gorm:"size:20" json:"last_sync_status"`
+	LastSyncError  string     `gorm:"size:255" json:"last_sync_error"`
+}
+
+// BeforeCreate assigns the webhook key.
+func (a *BookingAccount) BeforeCreate(*gorm.DB) error {
+	if a.WebhookKey == "" {
+		a.WebhookKey = NewUUID()
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package models
+
+import "gorm.io/gorm"
+
+// Brand groups an owner's restaurants under shared branding. Its
+// settings are defaults: a member restaurant inherits whatever it does
+// not override.
+type Brand struct {
+	gorm.Model
+	UserID        uint   `gorm:"not null;index" json:"-"`
+	Name          string `gorm:"size:150;not null" json:"name"`
+	LogoURL       string `gorm:"size:255" json:"logo_url"`
+	WebhookURL    string `gorm:"size:255" json:"webhook_url"`
+	EmailTemplate string `gorm:"type:text" json:"email_template"`
+}
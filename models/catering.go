@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Catering request statuses, forming the owner's pipeline: inquiries
+// arrive as new, get a quote attached, and are then confirmed or
+// declined.
+const (
+	CateringNew       = "new"
+	CateringQuoted    = "quoted"
+	CateringConfirmed = "confirmed"
+	CateringDeclined  = "declined"
+)
+
+// CateringRequest is a structured catering inquiry sent to a
+// restaurant, together with the quote the owner builds for it.
+type CateringRequest struct {
+	gorm.Model
+	RestaurantID  uint      `gorm:"index;not null" json:"restaurant_id"`
+	CustomerName  string    `gorm:"size:100;not null" json:"customer_name"`
+	CustomerEmail string    `gorm:"size:255;not null" json:"customer_email"`
+	EventDate     time.Time `gorm:"not null" json:"event_date"`
+	Headcount     int       `gorm:"not null" json:"headcount"`
+	// Budget is what the customer said they want to spend; zero means
+	// they did not say.
+	Budget      float64             `json:"budget"`
+	Preferences string              `gorm:"type:text" json:"preferences"`
+	Status      string              `gorm:"size:20;default:new;index" json:"status"`
+	QuoteTotal  float64             `json:"quote_total"`
+	Lines       []CateringQuoteLine `json:"lines"`
+}
+
+// CateringQuoteLine is one menu item on a catering quote. Name and
+// UnitPrice are snapshots taken when the quote was built, so later menu
+// edits do not silently change an outstanding quote.
+type CateringQuoteLine struct {
+	gorm.Model
+	CateringRequestID uint    `gorm:"index;not null" json:"catering_request_id"`
+	MenuItemID        uint    `gorm:"not null" json:"menu_item_id"`
+	Name              string  `gorm:"size:150;not null" json:"name"`
+	UnitPrice         float64 `gorm:"not null" json:"unit_price"`
+	Quantity          int     `gorm:"not null" json:"quantity"`
+	Subtotal          float64 `gorm:"not null" json:"subtotal"`
+}
+
+// ValidCateringStatus reports whether status is a known catering
+// request status.
+func ValidCateringStatus(status string) bool {
+	switch status {
+	case CateringNew, CateringQuoted, CateringConfirmed, CateringDeclined:
+		return true
+	}
+	return false
+}
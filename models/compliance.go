@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ComplianceItem is a recurring obligation a restaurant must keep on
+// top of — insurance renewals, fire-safety checks, pest-control visits.
+// License documents cover paperwork with a file attached; items cover
+// everything else that just has to happen on time.
+type ComplianceItem struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"index;not null" json:"restaurant_id"`
+	Name         string `gorm:"size:150;not null" json:"name"`
+	Description  string `gorm:"type:text" json:"description"`
+	// EmployeeID is the assignee; zero means the owner handles it.
+	EmployeeID uint      `gorm:"index" json:"employee_id,omitempty"`
+	DueAt      time.Time `gorm:"not null;index" json:"due_at"`
+	// RecurDays rolls DueAt forward on completion; zero makes the item
+	// one-off.
+	RecurDays int  `gorm:"default:0" json:"recur_days"`
+	Done      bool `gorm:"default:false" json:"done"`
+	// EscalatedAt records the overdue escalation, sent once per due date.
+	EscalatedAt *time.Time             `json:"-"`
+	Completions []ComplianceCompletion `json:"completions,omitempty"`
+}
+
+// ComplianceCompletion is one sign-off of an item: who did it, when,
+// and anything worth noting for the next audit.
+type ComplianceCompletion struct {
+	gorm.Model
+	ComplianceItemID uint      `gorm:"index;not null" json:"-"`
+	EmployeeID       uint      `json:"employee_id,omitempty"`
+	Notes            string    `gorm:"type:text" json:"notes"`
+	CompletedAt      time.Time `gorm:"not null" json:"completed_at"`
+}
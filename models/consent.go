@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Marketing channels a customer can consent to. Transactional messages
+// (order confirmations, reservation updates) are not gated by consent;
+// these cover marketing contact only.
+const (
+	ConsentChannelEmail = "email"
+	ConsentChannelSMS   = "sms"
+	ConsentChannelPush  = "push"
+)
+
+// Consent records a customer's explicit opt-in or opt-out for one
+// marketing channel, keyed by email so guests without accounts are
+// covered too. GrantedAt and RevokedAt keep the compliance trail; the
+// current state is Granted. No row means no consent.
+type Consent struct {
+	gorm.Model
+	CustomerEmail string     `gorm:"size:255;uniqueIndex:idx_consent_channel;not null" json:"customer_email"`
+	Channel       string     `gorm:"size:20;uniqueIndex:idx_consent_channel;not null" json:"channel"`
+	Granted       bool       `json:"granted"`
+	GrantedAt     *time.Time `json:"granted_at"`
+	RevokedAt     *time.Time `json:"revoked_at"`
+	// Source notes where the decision came from (signup form,
+	// unsubscribe link, support request) for the compliance trail.
+	Source string `gorm:"size:100" json:"source"`
+}
+
+// ValidConsentChannel reports whether channel is a known marketing
+// channel.
+func ValidConsentChannel(channel string) bool {
+	switch channel {
+	case ConsentChannelEmail, ConsentChannelSMS, ConsentChannelPush:
+		return true
+	}
+	return false
+}
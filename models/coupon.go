@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Coupon sources.
+const (
+	CouponSourceBirthday = "birthday"
+)
+
+// Coupon is a discount issued to a customer, keyed by email so guests
+// are covered too. Redemption happens at the till for now; RedeemedAt
+// exists so a code can only be honoured once when that moves online.
+type Coupon struct {
+	gorm.Model
+	Code          string     `gorm:"size:20;uniqueIndex;not null" json:"code"`
+	RestaurantID  uint       `gorm:"index;not null" json:"restaurant_id"`
+	CustomerEmail string     `gorm:"size:255;index;not null" json:"-"`
+	DiscountPct   int        `json:"discount_pct"`
+	Source        string     `gorm:"size:40" json:"source"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	RedeemedAt    *time.Time `json:"redeemed_at"`
+}
+
+// BirthdayTemplate configures a restaurant's birthday greeting. Body
+// may use the {{name}}, {{code}} and {{discount}} placeholders.
+type BirthdayTemplate struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"uniqueIndex;not null" json:"restaurant_id"`
+	Enabled      bool   `json:"enabled"`
+	Subject      string `gorm:"size:200" json:"subject"`
+	Body         string `gorm:"type:text" json:"body"`
+	DiscountPct  int    `json:"discount_pct"`
+	ValidDays    int    `json:"valid_days"`
+}
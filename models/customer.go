@@ -0,0 +1,27 @@
+package models
+
+import "gorm.io/gorm"
+
+// CustomerAddress is a delivery address saved on a customer account.
+type CustomerAddress struct {
+	gorm.Model
+	UserID     uint   `gorm:"index;not null" json:"-"`
+	Label      string `gorm:"size:50" json:"label"` // "home", "work", ...
+	Street     string `gorm:"size:255;not null" json:"street"`
+	City       string `gorm:"size:100;not null" json:"city"`
+	PostalCode string `gorm:"size:20" json:"postal_code"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+// CustomerPaymentMethod is a tokenized payment method saved on a
+// customer account. Only the provider's token plus display metadata are
+// stored; card numbers never reach this system.
+type CustomerPaymentMethod struct {
+	gorm.Model
+	UserID    uint   `gorm:"index;not null" json:"-"`
+	Provider  string `gorm:"size:50;not null" json:"provider"`
+	Token     string `gorm:"size:255;not null" json:"-"`
+	Brand     string `gorm:"size:30" json:"brand"`
+	Last4     string `gorm:"size:4" json:"last4"`
+	IsDefault bool   `json:"is_default"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "gorm.io/gorm"
+
+// DeviceToken is a mobile push token registered by a user's device.
+// Per-event preferences control which notifications reach the device.
+type DeviceToken struct {
+	gorm.Model
+	UserID             uint   `gorm:"index;not null" json:"user_id"`
+	Token              string `gorm:"size:512;uniqueIndex;not null" json:"token"`
+	Platform           string `gorm:"size:10;not null" json:"platform"` // "fcm" or "apns"
+	NotifyOrders       bool   `gorm:"default:true" json:"notify_orders"`
+	NotifyReservations bool   `gorm:"default:true" json:"notify_reservations"`
+}
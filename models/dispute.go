@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Dispute statuses, following the provider's lifecycle.
+const (
+	DisputeNeedsResponse = "needs_response"
+	DisputeUnderReview   = "under_review"
+	DisputeWon           = "won"
+	DisputeLost          = "lost"
+)
+
+// Dispute is a chargeback raised against an order's payment. Records
+// are created and updated from provider webhooks; owners respond by
+// submitting evidence through the provider dashboard and marking the
+// dispute here.
+type Dispute struct {
+	gorm.Model
+	OrderID     uint    `gorm:"index;not null" json:"order_id"`
+	ProviderRef string  `gorm:"size:100;uniqueIndex" json:"provider_ref"`
+	Amount      float64 `json:"amount"`
+	Reason      string  `gorm:"size:100" json:"reason"`
+	Status      string  `gorm:"size:30;default:needs_response" json:"status"`
+	// EvidenceDueBy is the provider's deadline for submitting evidence.
+	EvidenceDueBy       *time.Time `json:"evidence_due_by,omitempty"`
+	EvidenceSubmittedAt *time.Time `json:"evidence_submitted_at,omitempty"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Event is a ticketed happening at a restaurant — a wine tasting, live
+// music night and the like. Capacity is managed per tier.
+type Event struct {
+	gorm.Model
+	RestaurantID uint        `gorm:"index;not null" json:"restaurant_id"`
+	Name         string      `gorm:"size:150;not null" json:"name"`
+	Description  string      `gorm:"type:text" json:"description"`
+	StartsAt     time.Time   `gorm:"not null;index" json:"starts_at"`
+	Tiers        []EventTier `json:"tiers"`
+}
+
+// EventTier is one ticket class of an event (general, VIP, ...) with
+// its own price and capacity.
+type EventTier struct {
+	gorm.Model
+	EventID  uint    `gorm:"index;not null" json:"event_id"`
+	Name     string  `gorm:"size:50;not null" json:"name"`
+	Price    float64 `gorm:"not null" json:"price"`
+	Capacity int     `gorm:"not null" json:"capacity"`
+}
+
+// Ticket is one sold admission. Code is the value the ticket's QR
+// encodes; staff scan it at the door and the check-in endpoint stamps
+// CheckedInAt, so a code admits only once.
+type Ticket struct {
+	gorm.Model
+	EventID       uint       `gorm:"index;not null" json:"event_id"`
+	TierID        uint       `gorm:"index;not null" json:"tier_id"`
+	Code          string     `gorm:"size:36;uniqueIndex;not null" json:"code"`
+	CustomerID    uint       `gorm:"index;not null" json:"-"`
+	CustomerEmail string     `gorm:"size:255" json:"customer_email"`
+	Price         float64    `json:"price"`
+	ChargeRef     string     `gorm:"size:255" json:"-"`
+	CheckedInAt   *time.Time `json:"checked_in_at"`
+}
+
+// BeforeCreate assigns the ticket code.
+func (t *Ticket) BeforeCreate(*gorm.DB) error {
+	if t.Code == "" {
+		t.Code = NewUUID()
+	}
+	return nil
+}
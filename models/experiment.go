@@ -0,0 +1,55 @@
+package models
+
+import "gorm.io/gorm"
+
+// Experiment statuses. Only one running experiment per restaurant is
+// applied to the public menu at a time.
+const (
+	ExperimentDraft   = "draft"
+	ExperimentRunning = "running"
+	ExperimentStopped = "stopped"
+)
+
+// Experiment is an A/B test on how a restaurant's menu is presented.
+// Visitors are assigned to a variant deterministically, so the same
+// visitor always sees the same menu while the experiment runs.
+type Experiment struct {
+	gorm.Model
+	RestaurantID uint                `gorm:"index;not null" json:"restaurant_id"`
+	Name         string              `gorm:"size:100;not null" json:"name"`
+	Status       string              `gorm:"size:20;default:draft" json:"status"`
+	Variants     []ExperimentVariant `json:"variants"`
+}
+
+// ExperimentVariant is one arm of an experiment. ItemOrder holds the
+// menu item ids pinned to the top of the menu, comma-separated and in
+// order; items not listed keep their usual position after them. An
+// empty ItemOrder leaves the menu untouched (the control arm).
+type ExperimentVariant struct {
+	gorm.Model
+	ExperimentID uint   `gorm:"index;not null" json:"experiment_id"`
+	Name         string `gorm:"size:50;not null" json:"name"`
+	ItemOrder    string `gorm:"type:text" json:"item_order"`
+}
+
+// ExperimentExposure records that a visitor saw a variant, and whether
+// they went on to order. One row per visitor per experiment; replays of
+// the same visitor do not inflate the denominator.
+type ExperimentExposure struct {
+	gorm.Model
+	ExperimentID uint    `gorm:"uniqueIndex:idx_exposure_visitor;not null" json:"experiment_id"`
+	VariantID    uint    `gorm:"index;not null" json:"variant_id"`
+	VisitorID    string  `gorm:"size:64;uniqueIndex:idx_exposure_visitor;not null" json:"visitor_id"`
+	Converted    bool    `json:"converted"`
+	OrderValue   float64 `json:"order_value"`
+}
+
+// ValidExperimentStatus reports whether status is a known experiment
+// status.
+func ValidExperimentStatus(status string) bool {
+	switch status {
+	case ExperimentDraft, ExperimentRunning, ExperimentStopped:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+// FAQEntry is one owner-written question and answer used to power chat
+// widgets on the restaurant's website.
+type FAQEntry struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"index;not null" json:"restaurant_id"`
+	Question     string `gorm:"size:255;not null" json:"question"`
+	Answer       string `gorm:"type:text;not null" json:"answer"`
+}
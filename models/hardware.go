@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Hardware device kinds.
+const (
+	DeviceTablet  = "tablet"
+	DeviceKDS     = "kds"
+	DevicePrinter = "printer"
+)
+
+// HardwareDevice is a piece of restaurant hardware — a POS tablet, a
+// kitchen display screen or a printer's controller. The device
+// authenticates with Token and heartbeats to fetch its configuration;
+// LastSeenAt is its last heartbeat, so the dashboard can flag a device
+// that went quiet. Deactivating a device keeps the row but tells the
+// hardware to lock itself on its next heartbeat.
+type HardwareDevice struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"index;not null" json:"restaurant_id"`
+	Name         string `gorm:"size:100;not null" json:"name"`
+	Kind         string `gorm:"size:20;not null" json:"kind"`
+	Token        string `gorm:"size:36;uniqueIndex" json:"token"`
+	Active       bool   `gorm:"default:true" json:"active"`
+	// Config is an owner-edited JSON blob the device interprets itself
+	// (printer margins, KDS station filters); the API only checks it
+	// parses.
+	Config     string     `gorm:"type:text" json:"config"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+}
+
+// BeforeCreate assigns the device token.
+func (d *HardwareDevice) BeforeCreate(tx *gorm.DB) error {
+	if d.Token == "" {
+		d.Token = NewUUID()
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HealthInspection records one visit from the health authority: when it
+// happened, the score awarded and any notes, with the official report
+// attached as a file.
+type HealthInspection struct {
+	gorm.Model
+	RestaurantID uint      `gorm:"index;not null" json:"restaurant_id"`
+	Date         time.Time `gorm:"not null" json:"date"`
+	// Score is on the authority's 0-100 scale.
+	Score int    `gorm:"not null" json:"score"`
+	Notes string `gorm:"type:text" json:"notes"`
+	// ReportPath is the on-disk path of the attached report; empty when
+	// none was uploaded. Served through its own endpoint, never exposed
+	// directly.
+	ReportPath string `gorm:"size:255" json:"-"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "gorm.io/gorm"
+
+// Image processing statuses.
+const (
+	ImagePending = "pending"
+	ImageReady   = "ready"
+	ImageFailed  = "failed"
+)
+
+// Image entity types.
+const (
+	ImageEntityRestaurant = "restaurant"
+	ImageEntityMenuItem   = "menu_item"
+)
+
+// Image is an uploaded photo attached to a restaurant or menu item. The
+// original is stored as-is; a background job derives the variants, so
+// they stay empty until Status is ready.
+type Image struct {
+	gorm.Model
+	EntityType   string `gorm:"size:20;not null;index:idx_images_entity" json:"entity_type"`
+	EntityID     uint   `gorm:"not null;index:idx_images_entity" json:"entity_id"`
+	OriginalPath string `gorm:"size:255;not null" json:"-"`
+	ThumbPath    string `gorm:"size:255" json:"-"`
+	MediumPath   string `gorm:"size:255" json:"-"`
+	LargePath    string `gorm:"size:255" json:"-"`
+	Status       string `gorm:"size:20;default:pending" json:"status"`
+}
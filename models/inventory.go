@@ -0,0 +1,40 @@
+package models
+
+import "gorm.io/gorm"
+
+// Ingredient is one inventory item a restaurant cooks with, priced per
+// unit so recipes can be costed.
+type Ingredient struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"index;not null" json:"restaurant_id"`
+	Name         string `gorm:"size:100;not null" json:"name"`
+	// Unit is the measure CostPerUnit refers to, e.g. "kg", "l", "unit".
+	Unit        string  `gorm:"size:20;not null" json:"unit"`
+	CostPerUnit float64 `json:"cost_per_unit"`
+	// Stock is the quantity on hand, in Unit. Completed orders deplete
+	// it through recipes; it can go negative when the counts drift,
+	// which is what the low-stock alert flags.
+	Stock float64 `gorm:"default:0" json:"stock"`
+}
+
+// StockMovement is one change to an ingredient's stock: a depletion
+// from a completed order or a manual adjustment.
+type StockMovement struct {
+	gorm.Model
+	IngredientID uint    `gorm:"index;not null" json:"ingredient_id"`
+	Delta        float64 `gorm:"not null" json:"delta"`
+	Reason       string  `gorm:"size:100" json:"reason"`
+	// OrderID links automatic depletions to their order; zero for
+	// manual adjustments.
+	OrderID uint `gorm:"index" json:"order_id,omitempty"`
+}
+
+// RecipeItem links one ingredient into a menu item's recipe with the
+// quantity a single serving uses.
+type RecipeItem struct {
+	gorm.Model
+	MenuItemID   uint       `gorm:"index;not null;uniqueIndex:idx_recipe_menu_ingredient" json:"-"`
+	IngredientID uint       `gorm:"not null;uniqueIndex:idx_recipe_menu_ingredient" json:"ingredient_id"`
+	Quantity     float64    `gorm:"not null" json:"quantity"`
+	Ingredient   Ingredient `json:"ingredient"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Job statuses.
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobDead    = "dead"
+)
+
+// Job is a unit of asynchronous work (email sending, webhook delivery,
+// exports). Jobs are persisted so they survive restarts, retried with
+// backoff, and parked as dead after too many failures.
+type Job struct {
+	gorm.Model
+	Type        string    `gorm:"size:100;not null;index" json:"type"`
+	Payload     string    `gorm:"type:text" json:"payload"`
+	Status      string    `gorm:"size:20;default:pending;index" json:"status"`
+	Attempts    int       `gorm:"default:0" json:"attempts"`
+	MaxAttempts int       `gorm:"default:5" json:"max_attempts"`
+	RunAt       time.Time `gorm:"index" json:"run_at"`
+	LastError   string    `gorm:"type:text" json:"last_error"`
+}
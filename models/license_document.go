@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LicenseDocument is a restaurant's regulatory paperwork — a liquor
+// license, food handling permit and the like — stored with its expiry
+// date so renewals can be chased before the document lapses.
+type LicenseDocument struct {
+	gorm.Model
+	RestaurantID uint `gorm:"index;not null" json:"restaurant_id"`
+	// Kind names the obligation, e.g. "liquor_license" or "food_permit".
+	Kind      string    `gorm:"size:50;not null" json:"kind"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	// FilePath is the on-disk path of the uploaded document. Served
+	// through its own endpoint, never exposed directly.
+	FilePath string `gorm:"size:255" json:"-"`
+	// RemindedAt records when the expiry reminder went out, so the daily
+	// run sends it once. A renewal is a new document, not an update.
+	RemindedAt *time.Time `json:"-"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "gorm.io/gorm"
+
+// Wallet platforms we issue loyalty passes for.
+const (
+	WalletPlatformApple  = "apple"
+	WalletPlatformGoogle = "google"
+)
+
+// LoyaltyPass is a wallet pass issued to a customer for one restaurant.
+// Serial identifies the pass to the platform; AuthToken is what the
+// platform presents when it fetches the latest pass state, so it never
+// leaves the update protocol. Balance is the points last pushed.
+type LoyaltyPass struct {
+	gorm.Model
+	UserID       uint   `gorm:"index;not null;uniqueIndex:idx_loyalty_passes_user_restaurant_platform" json:"-"`
+	RestaurantID uint   `gorm:"not null;uniqueIndex:idx_loyalty_passes_user_restaurant_platform" json:"restaurant_id"`
+	Platform     string `gorm:"size:10;not null;uniqueIndex:idx_loyalty_passes_user_restaurant_platform" json:"platform"`
+	Serial       string `gorm:"size:36;uniqueIndex" json:"serial"`
+	AuthToken    string `gorm:"size:36" json:"-"`
+	Balance      int    `json:"balance"`
+	PassURL      string `gorm:"size:255" json:"pass_url"`
+}
+
+// BeforeCreate assigns the serial and the update-protocol token.
+func (p *LoyaltyPass) BeforeCreate(tx *gorm.DB) error {
+	if p.Serial == "" {
+		p.Serial = NewUUID()
+	}
+	if p.AuthToken == "" {
+		p.AuthToken = NewUUID()
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Marketing sync outcomes.
+const (
+	MarketingSyncOK     = "ok"
+	MarketingSyncFailed = "failed"
+)
+
+// MarketingAccount holds a restaurant's credentials for the marketing
+// platform and the outcome of its most recent contact sync. The API key
+// never leaves the server.
+type MarketingAccount struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"uniqueIndex;not null" json:"restaurant_id"`
+	APIKey       string `gorm:"size:255" json:"-"`
+	ListID       string `gorm:"size:100" json:"list_id"`
+
+	LastSyncAt     *time.Time `json:"last_sync_at"`
+	LastSyncStatus string     `gorm:"size:20" json:"last_sync_status"`
+	LastSyncError  string     `gorm:"size:255" json:"last_sync_error"`
+	LastSyncCount  int        `json:"last_sync_count"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "gorm.io/gorm"
+
+// MenuItem is a dish or drink offered by a restaurant.
+type MenuItem struct {
+	gorm.Model
+	RestaurantID uint    `gorm:"index;not null" json:"restaurant_id"`
+	Name         string  `gorm:"size:150;not null;index" json:"name"`
+	Description  string  `gorm:"type:text" json:"description"`
+	Price        float64 `gorm:"not null" json:"price"`
+	// Cost is what the dish costs to make; menu engineering reports
+	// derive margins from it. Zero means not yet costed.
+	Cost      float64 `json:"cost"`
+	Category  string  `gorm:"size:50" json:"category"`
+	Available bool    `gorm:"default:true" json:"available"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "gorm.io/gorm"
+
+// Order statuses.
+const (
+	OrderPending   = "pending"
+	OrderAccepted  = "accepted"
+	OrderPreparing = "preparing"
+	OrderReady     = "ready"
+	OrderCompleted = "completed"
+	OrderCancelled = "cancelled"
+)
+
+// Order is a food order placed against a restaurant.
+type Order struct {
+	gorm.Model
+	RestaurantID uint `gorm:"index;not null" json:"restaurant_id"`
+	// CustomerID links the order to a customer account when one was
+	// logged in at checkout; zero means a guest order.
+	CustomerID    uint    `gorm:"index" json:"-"`
+	CustomerName  string  `gorm:"size:100" json:"customer_name"`
+	CustomerEmail string  `gorm:"size:255" json:"customer_email"`
+	Status        string  `gorm:"size:20;default:pending" json:"status"`
+	Total         float64 `json:"total"`
+	Tip           float64 `json:"tip"`
+	// Tax is the portion of Total that is tax; menu prices include it,
+	// the column exists so revenue reports can break it out.
+	Tax float64 `json:"tax"`
+	// PaymentRef is the charge reference at the payment provider; empty
+	// for orders paid outside the platform.
+	PaymentRef string      `gorm:"size:100" json:"-"`
+	Items      []OrderItem `json:"items"`
+}
+
+// OrderItem is a single line of an order. Note carries the diner's
+// modifiers ("sin cebolla"); kitchen tickets print it highlighted.
+type OrderItem struct {
+	gorm.Model
+	OrderID    uint    `gorm:"index;not null" json:"order_id"`
+	MenuItemID uint    `gorm:"index;not null" json:"menu_item_id"`
+	Quantity   int     `gorm:"not null" json:"quantity"`
+	Price      float64 `gorm:"not null" json:"price"`
+	Note       string  `gorm:"size:255" json:"note"`
+}
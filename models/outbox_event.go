@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutboxEvent is a domain event written in the same transaction as the
+// state change that produced it. A background relay publishes unpublished
+// rows, guaranteeing the event is not lost if the broker is down.
+type OutboxEvent struct {
+	gorm.Model
+	EventType   string     `gorm:"size:100;not null;index" json:"event_type"`
+	Payload     string     `gorm:"type:text;not null" json:"payload"`
+	Attempts    int        `gorm:"default:0" json:"attempts"`
+	PublishedAt *time.Time `gorm:"index" json:"published_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "gorm.io/gorm"
+
+// PriceChange records one re-pricing of a menu item: who changed it,
+// when, and between which prices. Order lines snapshot the price at
+// order time, so past orders and revenue stay on historical prices.
+type PriceChange struct {
+	gorm.Model
+	MenuItemID uint    `gorm:"index;not null" json:"menu_item_id"`
+	OldPrice   float64 `json:"old_price"`
+	NewPrice   float64 `json:"new_price"`
+	ChangedBy  uint    `json:"changed_by"`
+}
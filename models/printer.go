@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Printer formats.
+const (
+	PrinterFormatText   = "text"
+	PrinterFormatESCPOS = "escpos"
+)
+
+// Print job statuses: a job is pending until an agent fetches it,
+// delivered until the agent confirms it printed, then acked.
+const (
+	PrintJobPending   = "pending"
+	PrintJobDelivered = "delivered"
+	PrintJobAcked     = "acked"
+)
+
+// Printer is a kitchen printer registered to a restaurant. The agent
+// process next to the printer authenticates with Key and polls for
+// jobs; LastSeenAt is its last poll, so the dashboard can flag a
+// printer that went quiet.
+type Printer struct {
+	gorm.Model
+	RestaurantID uint       `gorm:"index;not null" json:"restaurant_id"`
+	Name         string     `gorm:"size:100;not null" json:"name"`
+	Format       string     `gorm:"size:10;default:escpos" json:"format"`
+	Key          string     `gorm:"size:36;uniqueIndex" json:"key"`
+	LastSeenAt   *time.Time `json:"last_seen_at"`
+}
+
+// BeforeCreate assigns the agent key.
+func (p *Printer) BeforeCreate(tx *gorm.DB) error {
+	if p.Key == "" {
+		p.Key = NewUUID()
+	}
+	return nil
+}
+
+// PrintJob is one ticket queued for one printer. The rendered payload
+// is produced at delivery time from the order, so a re-fetched job
+// always prints current data.
+type PrintJob struct {
+	gorm.Model
+	RestaurantID uint       `gorm:"index;not null" json:"restaurant_id"`
+	PrinterID    uint       `gorm:"index;not null" json:"printer_id"`
+	OrderID      uint       `gorm:"index;not null" json:"order_id"`
+	Status       string     `gorm:"size:10;default:pending" json:"status"`
+	DeliveredAt  *time.Time `json:"delivered_at"`
+	AckedAt      *time.Time `json:"acked_at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PrivateRoom is a private dining room of a restaurant. It is booked
+// through its own flow rather than the table reservation calendar:
+// rooms have their own opening hours and carry a minimum spend instead
+// of competing for the restaurant's seats.
+type PrivateRoom struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"index;not null" json:"restaurant_id"`
+	Name         string `gorm:"size:100;not null" json:"name"`
+	Capacity     int    `gorm:"not null" json:"capacity"`
+	// MinSpend is the minimum the party commits to spending; zero means
+	// no minimum.
+	MinSpend float64 `json:"min_spend"`
+	// OpensAt/ClosesAt are local "HH:MM" times bounding when the room
+	// can be booked, independent of the restaurant's dining hours.
+	OpensAt  string `gorm:"size:5;default:12:00" json:"opens_at"`
+	ClosesAt string `gorm:"size:5;default:22:00" json:"closes_at"`
+}
+
+// RoomBooking is one booking of a private room for a time window.
+// MinSpend is snapshotted from the room at booking time so later rule
+// changes do not alter an agreed booking.
+type RoomBooking struct {
+	gorm.Model
+	RoomID       uint `gorm:"index;not null" json:"room_id"`
+	RestaurantID uint `gorm:"index;not null" json:"restaurant_id"`
+	// CustomerID links the booking to a customer account when one was
+	// logged in; zero means a guest booking.
+	CustomerID    uint      `gorm:"index" json:"-"`
+	CustomerName  string    `gorm:"size:100;not null" json:"customer_name"`
+	CustomerEmail string    `gorm:"size:255" json:"customer_email"`
+	PartySize     int       `gorm:"not null" json:"party_size"`
+	StartsAt      time.Time `gorm:"not null;index" json:"starts_at"`
+	EndsAt        time.Time `gorm:"not null" json:"ends_at"`
+	Status        string    `gorm:"size:20;default:pending" json:"status"`
+	MinSpend      float64   `json:"min_spend"`
+	Notes         string    `gorm:"type:text" json:"notes"`
+}
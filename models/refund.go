@@ -0,0 +1,30 @@
+package models
+
+import "gorm.io/gorm"
+
+// Refund reason codes accepted by the refund endpoint.
+const (
+	RefundReasonCustomer  = "requested_by_customer"
+	RefundReasonIssue     = "order_issue"
+	RefundReasonDuplicate = "duplicate"
+	RefundReasonOther     = "other"
+)
+
+// Refund is money returned against an order. Partial refunds are
+// allowed; the sum of a single order's refunds never exceeds its total.
+type Refund struct {
+	gorm.Model
+	OrderID     uint    `gorm:"index;not null" json:"order_id"`
+	Amount      float64 `gorm:"not null" json:"amount"`
+	Reason      string  `gorm:"size:40;not null" json:"reason"`
+	ProviderRef string  `gorm:"size:100" json:"provider_ref"`
+}
+
+// ValidRefundReason reports whether reason is a known reason code.
+func ValidRefundReason(reason string) bool {
+	switch reason {
+	case RefundReasonCustomer, RefundReasonIssue, RefundReasonDuplicate, RefundReasonOther:
+		return true
+	}
+	return false
+}
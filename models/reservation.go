@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Reservation statuses. Seated and completed bracket the party's actual
+// time at the table; the timestamps they stamp feed turn-time analytics.
+// No-show is terminal like cancelled but counts against the customer.
+const (
+	ReservationPending   = "pending"
+	ReservationConfirmed = "confirmed"
+	ReservationSeated    = "seated"
+	ReservationCompleted = "completed"
+	ReservationCancelled = "cancelled"
+	ReservationNoShow    = "no_show"
+)
+
+// ReservationSourceDirect marks a booking made through our own API;
+// any other Source value names the external platform that sent it.
+const ReservationSourceDirect = "direct"
+
+// Reservation is a table booking made against a restaurant.
+type Reservation struct {
+	gorm.Model
+	RestaurantID uint `gorm:"index;not null" json:"restaurant_id"`
+	// Source attributes the booking to where it was made; ExternalRef
+	// is the sending platform's id for it, used to dedupe syncs.
+	Source      string `gorm:"size:20;default:direct" json:"source"`
+	ExternalRef string `gorm:"size:100;index" json:"-"`
+	// CustomerID links the booking to a customer account when one was
+	// logged in; zero means a guest booking.
+	CustomerID    uint      `gorm:"index" json:"-"`
+	CustomerName  string    `gorm:"size:100;not null" json:"customer_name"`
+	CustomerEmail string    `gorm:"size:255" json:"customer_email"`
+	PartySize     int       `gorm:"not null" json:"party_size"`
+	Date          time.Time `gorm:"not null" json:"date"`
+	Status        string    `gorm:"size:20;default:pending" json:"status"`
+	Notes         string    `gorm:"type:text" json:"notes"`
+	// TableNumber is the table the party was seated at; zero means not
+	// assigned. (Not named Table: that is a reserved word in MySQL.)
+	// SeatedAt and DepartedAt are stamped by the seated and completed
+	// status transitions.
+	TableNumber int        `gorm:"index" json:"table"`
+	SeatedAt    *time.Time `json:"seated_at"`
+	DepartedAt  *time.Time `json:"departed_at"`
+	// NoShowFee is the deposit charged when the party did not show, and
+	// NoShowChargeRef is the provider's id for that charge. Both stay
+	// zero when the restaurant has no fee or the charge could not run.
+	NoShowFee       float64 `json:"no_show_fee,omitempty"`
+	NoShowChargeRef string  `gorm:"size:255" json:"-"`
+	// DepositAmount is the prepayment collected while booking, with the
+	// provider's ids for the charge and, after a timely cancellation,
+	// the refund.
+	DepositAmount    float64 `json:"deposit_amount,omitempty"`
+	DepositChargeRef string  `gorm:"size:255" json:"-"`
+	DepositRefundRef string  `gorm:"size:255" json:"-"`
+}
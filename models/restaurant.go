@@ -0,0 +1,118 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Restaurant is a restaurant managed by an owner account. Like User, it
+// carries a PublicID for the API while the numeric primary key stays
+// internal.
+type Restaurant struct {
+	ID        uint           `gorm:"primarykey" json:"-"`
+	PublicID  string         `gorm:"size:36;uniqueIndex" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	Name      string         `gorm:"size:150;not null;index;uniqueIndex:idx_restaurants_owner_name" json:"name"`
+	// Slug is the stable URL-safe name used by public embed feeds.
+	// Uniqueness is enforced in BeforeCreate rather than by index so
+	// rows predating the column can keep an empty value.
+	Slug        string `gorm:"size:110;index" json:"slug"`
+	Description string `gorm:"type:text" json:"description"`
+	Address     string `gorm:"size:255" json:"address"`
+	Phone       string `gorm:"size:30" json:"phone"`
+	Status      string `gorm:"size:20;default:active" json:"status"`
+	Timezone    string `gorm:"size:64;default:UTC" json:"timezone"`
+	UserID      uint   `gorm:"index;not null;uniqueIndex:idx_restaurants_owner_name" json:"-"`
+
+	// Booking rules driving the availability calendar. Seats is the
+	// total concurrent capacity; OpensAt/ClosesAt are local "HH:MM"
+	// times; TurnMinutes is how long a party occupies its seats.
+	// NoShowFee, when positive, is charged to a no-show customer's
+	// card on file.
+	Seats       int     `gorm:"default:30" json:"seats"`
+	OpensAt     string  `gorm:"size:5;default:12:00" json:"opens_at"`
+	ClosesAt    string  `gorm:"size:5;default:22:00" json:"closes_at"`
+	TurnMinutes int     `gorm:"default:90" json:"turn_minutes"`
+	NoShowFee   float64 `gorm:"default:0" json:"no_show_fee"`
+
+	// Deposit rules for prepaid bookings. Parties of DepositMinParty or
+	// more prepay DepositAmount when booking; cancelling at least
+	// DepositRefundHours before the booking refunds it. A zero
+	// DepositMinParty or DepositAmount disables deposits.
+	DepositAmount      float64 `gorm:"default:0" json:"deposit_amount"`
+	DepositMinParty    int     `gorm:"default:0" json:"deposit_min_party"`
+	DepositRefundHours int     `gorm:"default:24" json:"deposit_refund_hours"`
+
+	// ShowHealthScore opts the restaurant into a public badge exposing
+	// its latest health inspection score.
+	ShowHealthScore bool `gorm:"default:false" json:"show_health_score"`
+
+	// Brand membership and per-location overrides. A zero BrandID
+	// means the restaurant stands alone; empty override fields
+	// inherit the brand's values.
+	BrandID       uint   `gorm:"index;default:0" json:"brand_id"`
+	LogoURL       string `gorm:"size:255" json:"logo_url"`
+	WebhookURL    string `gorm:"size:255" json:"webhook_url"`
+	EmailTemplate string `gorm:"type:text" json:"email_template"`
+	// WebhookSecret signs outbound webhook deliveries. It is minted
+	// lazily — on the first dispatch or when the owner asks for it — so
+	// rows predating the column need no backfill.
+	WebhookSecret string `gorm:"size:36" json:"-"`
+
+	// Geofence for clock-in punches from shared tablets. A zero
+	// GeofenceMeters disables the check.
+	Latitude       float64 `gorm:"default:0" json:"latitude"`
+	Longitude      float64 `gorm:"default:0" json:"longitude"`
+	GeofenceMeters int     `gorm:"default:0" json:"geofence_meters"`
+}
+
+// Location returns the restaurant's IANA timezone, falling back to UTC
+// if the stored name no longer resolves (e.g. a trimmed tzdata).
+func (r *Restaurant) Location() *time.Location {
+	if loc, err := time.LoadLocation(r.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// BeforeCreate assigns the public identifier and a unique slug.
+func (r *Restaurant) BeforeCreate(tx *gorm.DB) error {
+	if r.PublicID == "" {
+		r.PublicID = NewUUID()
+	}
+	if r.Slug == "" {
+		r.Slug = Slugify(r.Name)
+		var taken int64
+		if err := tx.Model(&Restaurant{}).Where("slug = ?", r.Slug).Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			r.Slug = r.Slug + "-" + NewUUID()[:8]
+		}
+	}
+	return nil
+}
+
+// Slugify lowers a name into a URL-safe slug: letters and digits stay,
+// everything else collapses into single hyphens.
+func Slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+			hyphen = false
+		default:
+			if b.Len() > 0 && !hyphen {
+				b.WriteByte('-')
+				hyphen = true
+			}
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
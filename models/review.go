@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReviewSourceNative marks a review posted through our own API; any
+// other Source value names the platform it was pulled from.
+const ReviewSourceNative = "native"
+
+// Review is one diner review of a restaurant, whether posted here or
+// pulled from an external platform.
+type Review struct {
+	gorm.Model
+	RestaurantID uint `gorm:"index;not null" json:"restaurant_id"`
+	// Source attributes the review; ExternalRef is the platform's id
+	// for it, used to dedupe repeated pulls.
+	Source      string    `gorm:"size:20;default:native" json:"source"`
+	ExternalRef string    `gorm:"size:100;index" json:"-"`
+	Author      string    `gorm:"size:100" json:"author"`
+	Rating      int       `gorm:"not null" json:"rating"`
+	Comment     string    `gorm:"type:text" json:"comment"`
+	PostedAt    time.Time `gorm:"index" json:"posted_at"`
+
+	// Sentiment and Topics are stamped by the analyzer when the review
+	// is stored; Topics is comma-separated (service, food, price).
+	Sentiment string `gorm:"size:10" json:"sentiment"`
+	Topics    string `gorm:"size:100" json:"topics"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Review platforms a restaurant can connect.
+const (
+	ReviewPlatformGoogle = "google"
+	ReviewPlatformYelp   = "yelp"
+)
+
+// ReviewAccount holds a restaurant's credentials for one external
+// review platform and the outcome of its most recent pull. The API key
+// never leaves the server.
+type ReviewAccount struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"not null;index;uniqueIndex:idx_review_accounts_restaurant_platform" json:"restaurant_id"`
+	Platform     string `gorm:"size:20;not null;uniqueIndex:idx_review_accounts_restaurant_platform" json:"platform"`
+	APIKey       string `gorm:"size:255" json:"-"`
+	// ExternalID is the platform's id for this restaurant's listing.
+	ExternalID string `gorm:"size:100" json:"external_id"`
+
+	LastSyncAt     *time.Time `json:"last_sync_at"`
+	LastSyncStatus string     `gorm:"size:20" json:"last_sync_status"`
+	LastSyncError  string     `gorm:"size:255" json:"last_sync_error"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// Role is the set of account roles. The users table carries a matching
+// check constraint, so an invalid value is rejected at the database as
+// well as in validation.
+type Role string
+
+const (
+	RoleOwner    Role = "owner"
+	RoleAdmin    Role = "admin"
+	RoleCustomer Role = "customer"
+)
+
+// Valid reports whether r is one of the known roles.
+func (r Role) Valid() bool {
+	return r == RoleOwner || r == RoleAdmin || r == RoleCustomer
+}
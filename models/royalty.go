@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+// RoyaltyRule sets the royalty a franchised location owes its group:
+// a percentage of the location's net sales. Locations without a rule
+// are treated as company-owned and excluded from royalty reports.
+type RoyaltyRule struct {
+	gorm.Model
+	RestaurantID uint    `gorm:"uniqueIndex;not null" json:"restaurant_id"`
+	Pct          float64 `gorm:"not null" json:"pct"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "gorm.io/gorm"
+
+// Segment defines a marketing audience by rules over order history:
+// customers who placed at least MinOrders orders and spent at least
+// MinSpend within the last WindowDays days. A zero MinOrders or
+// MinSpend leaves that criterion out.
+type Segment struct {
+	gorm.Model
+	RestaurantID uint    `gorm:"index;not null" json:"restaurant_id"`
+	Name         string  `gorm:"size:100;not null" json:"name"`
+	MinOrders    int     `json:"min_orders"`
+	MinSpend     float64 `json:"min_spend"`
+	WindowDays   int     `json:"window_days"`
+}
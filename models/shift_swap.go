@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Swap request statuses.
+const (
+	SwapPending  = "pending"
+	SwapApproved = "approved"
+	SwapRejected = "rejected"
+)
+
+// ShiftSwap is a request to hand a shift to a colleague, or to drop it
+// when no colleague is named. The schedule only changes once a manager
+// approves.
+type ShiftSwap struct {
+	gorm.Model
+	RestaurantID   uint       `gorm:"not null;index" json:"restaurant_id"`
+	ShiftID        uint       `gorm:"not null;index" json:"shift_id"`
+	FromEmployeeID uint       `gorm:"not null" json:"from_employee_id"`
+	ToEmployeeID   uint       `json:"to_employee_id"` // zero = drop request
+	Note           string     `gorm:"size:255" json:"note"`
+	Status         string     `gorm:"size:20;not null;default:pending;index" json:"status"`
+	DecidedAt      *time.Time `json:"decided_at,omitempty"`
+}
+
+// ShiftChange is one line of a restaurant's schedule change history,
+// written in the same transaction as the change it describes.
+type ShiftChange struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"not null;index" json:"restaurant_id"`
+	ShiftID      uint   `gorm:"not null;index" json:"shift_id"`
+	Detail       string `gorm:"size:255;not null" json:"detail"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Employee is a person on a restaurant's payroll. PayrollRef carries
+// the employee's identifier in the external payroll system, so exports
+// can be matched without relying on names.
+type Employee struct {
+	gorm.Model
+	RestaurantID uint    `gorm:"index;not null" json:"restaurant_id"`
+	Name         string  `gorm:"size:100;not null" json:"name"`
+	Email        string  `gorm:"size:255" json:"email"`
+	PayrollRef   string  `gorm:"size:100" json:"payroll_ref"`
+	HourlyRate   float64 `json:"hourly_rate"`
+	// PinHash stores the employee's hashed time-clock PIN for punches
+	// from shared tablets. Empty means no PIN is set.
+	PinHash string `gorm:"size:255" json:"-"`
+}
+
+// Shift is a scheduled block of work for one employee.
+type Shift struct {
+	gorm.Model
+	RestaurantID uint      `gorm:"index;not null" json:"restaurant_id"`
+	EmployeeID   uint      `gorm:"index;not null" json:"employee_id"`
+	StartsAt     time.Time `gorm:"not null" json:"starts_at"`
+	EndsAt       time.Time `gorm:"not null" json:"ends_at"`
+}
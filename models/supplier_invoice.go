@@ -0,0 +1,38 @@
+package models
+
+import "gorm.io/gorm"
+
+// Supplier invoice statuses. An uploaded invoice is pending until the
+// extraction job runs, extracted while awaiting review, and confirmed
+// once a person has signed off on the figures.
+const (
+	InvoicePending   = "pending"
+	InvoiceExtracted = "extracted"
+	InvoiceConfirmed = "confirmed"
+	InvoiceFailed    = "failed"
+)
+
+// SupplierInvoice is a vendor invoice uploaded as a document. The OCR
+// provider prefills the supplier, total and lines; nothing is trusted
+// until someone reviews and confirms them.
+type SupplierInvoice struct {
+	gorm.Model
+	RestaurantID uint    `gorm:"index;not null" json:"restaurant_id"`
+	Supplier     string  `gorm:"size:150" json:"supplier"`
+	Total        float64 `json:"total"`
+	Status       string  `gorm:"size:20;default:pending;index" json:"status"`
+	// FilePath is the on-disk path of the uploaded document. Served
+	// through its own endpoint, never exposed directly.
+	FilePath string                `gorm:"size:255" json:"-"`
+	Lines    []SupplierInvoiceLine `json:"lines,omitempty"`
+}
+
+// SupplierInvoiceLine is one purchase line on an invoice, either
+// extracted or entered during review.
+type SupplierInvoiceLine struct {
+	gorm.Model
+	SupplierInvoiceID uint    `gorm:"index;not null" json:"-"`
+	Description       string  `gorm:"size:255;not null" json:"description"`
+	Quantity          float64 `json:"quantity"`
+	UnitCost          float64 `json:"unit_cost"`
+}
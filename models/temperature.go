@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TemperatureUnit is a monitored fridge, freezer or holding cabinet
+// with the safe range its HACCP plan prescribes.
+type TemperatureUnit struct {
+	gorm.Model
+	RestaurantID uint    `gorm:"not null;index" json:"restaurant_id"`
+	Name         string  `gorm:"size:100;not null" json:"name"`
+	MinC         float64 `json:"min_c"`
+	MaxC         float64 `json:"max_c"`
+	// SensorKey authenticates readings pushed by a sensor. Units are
+	// only ever returned to the restaurant's owner, who configures
+	// the sensor with it.
+	SensorKey string `gorm:"size:36;uniqueIndex" json:"sensor_key"`
+	// AlertedAt marks an open out-of-range alert so repeated bad
+	// readings do not repeat the email. Cleared when the unit
+	// recovers.
+	AlertedAt *time.Time `json:"-"`
+}
+
+// BeforeCreate assigns the sensor key.
+func (u *TemperatureUnit) BeforeCreate(tx *gorm.DB) error {
+	if u.SensorKey == "" {
+		u.SensorKey = NewUUID()
+	}
+	return nil
+}
+
+// Reading sources.
+const (
+	ReadingManual = "manual"
+	ReadingSensor = "sensor"
+)
+
+// TemperatureReading is one temperature check, taken by hand or pushed
+// by a sensor. OutOfRange is fixed at recording time against the range
+// the unit had then, so later range edits do not rewrite the log.
+type TemperatureReading struct {
+	gorm.Model
+	TemperatureUnitID uint    `gorm:"not null;index" json:"temperature_unit_id"`
+	Celsius           float64 `json:"celsius"`
+	Source            string  `gorm:"size:20;not null" json:"source"`
+	OutOfRange        bool    `json:"out_of_range"`
+}
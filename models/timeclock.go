@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TimePunch is one clock-in, and the matching clock-out once it
+// happens. A nil ClockOut means the employee is still on the clock.
+type TimePunch struct {
+	gorm.Model
+	RestaurantID uint       `gorm:"not null;index" json:"restaurant_id"`
+	EmployeeID   uint       `gorm:"not null;index" json:"employee_id"`
+	ClockIn      time.Time  `gorm:"not null" json:"clock_in"`
+	ClockOut     *time.Time `json:"clock_out,omitempty"`
+}
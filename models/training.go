@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TrainingRecord is one completed training or certification for an
+// employee — a food handler card, allergy training and the like. A
+// renewal is a new record, not an update, so the history stays intact.
+type TrainingRecord struct {
+	gorm.Model
+	EmployeeID  uint      `gorm:"not null;index" json:"employee_id"`
+	Name        string    `gorm:"size:150;not null" json:"name"`
+	CompletedAt time.Time `json:"completed_at"`
+	ExpiresAt   time.Time `gorm:"index" json:"expires_at"`
+	// RemindedAt marks that the renewal reminder went out, so it is
+	// only sent once per record.
+	RemindedAt *time.Time `json:"-"`
+}
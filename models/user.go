@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// User represents an account on the platform. Restaurant owners register
+// through /auth/register and manage their restaurants with the same account.
+// The numeric primary key stays internal; the API identifies users by
+// PublicID so account volume cannot be inferred from sequential IDs.
+type User struct {
+	ID        uint           `gorm:"primarykey" json:"-"`
+	PublicID  string         `gorm:"size:36;uniqueIndex" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	Name      string         `gorm:"size:100;not null" json:"name"`
+	// Emails are lowercased before storage (utils.NormalizeEmail), and
+	// MySQL's default utf8mb4 collation compares case-insensitively, so
+	// the unique index rejects User@x.com vs user@x.com on both counts.
+	Email    string `gorm:"size:255;uniqueIndex;not null" json:"email"`
+	Password string `gorm:"size:255;not null" json:"-"`
+	Phone    string `gorm:"size:30" json:"phone"`
+	Role     Role   `gorm:"size:20;default:owner;check:role IN ('owner','admin','customer')" json:"role"`
+	// Birthday is optional and only the month and day are ever used
+	// (birthday greetings); customers may give any year they like.
+	Birthday *time.Time `json:"birthday"`
+}
+
+// BeforeCreate assigns the public identifier.
+func (u *User) BeforeCreate(*gorm.DB) error {
+	if u.PublicID == "" {
+		u.PublicID = NewUUID()
+	}
+	return nil
+}
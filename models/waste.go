@@ -0,0 +1,18 @@
+package models
+
+import "gorm.io/gorm"
+
+// WasteEntry is one logged loss: spoiled stock, a dropped plate, a
+// mis-fired order. Entries carry their own cost so waste shows up in
+// food-cost reporting even when it never touched the inventory.
+type WasteEntry struct {
+	gorm.Model
+	RestaurantID uint `gorm:"index;not null" json:"restaurant_id"`
+	// IngredientID links the loss to an inventory item; zero for
+	// free-form entries like prepared dishes.
+	IngredientID uint    `gorm:"index" json:"ingredient_id,omitempty"`
+	Description  string  `gorm:"size:150;not null" json:"description"`
+	Quantity     float64 `gorm:"not null" json:"quantity"`
+	Reason       string  `gorm:"size:100;not null" json:"reason"`
+	Cost         float64 `json:"cost"`
+}
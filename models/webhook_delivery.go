@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhook delivery statuses: a delivery is pending until its job lands
+// a 2xx, delivered after one, and failed once the retries ran out.
+// Failed deliveries stay replayable from the dashboard.
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookFailed    = "failed"
+)
+
+// WebhookDelivery is one domain event on its way to a restaurant's
+// webhook endpoint. The row doubles as the delivery log: every attempt
+// updates Attempts, ResponseCode and LastError, so the dashboard can
+// show exactly what the endpoint answered.
+type WebhookDelivery struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"index;not null" json:"restaurant_id"`
+	EventType    string `gorm:"size:100;not null" json:"event_type"`
+	// URL is captured at dispatch time so later config changes do not
+	// rewrite the log.
+	URL          string     `gorm:"size:255;not null" json:"url"`
+	Payload      string     `gorm:"type:text" json:"payload"`
+	Status       string     `gorm:"size:20;default:pending;index" json:"status"`
+	Attempts     int        `gorm:"default:0" json:"attempts"`
+	ResponseCode int        `gorm:"default:0" json:"response_code"`
+	LastError    string     `gorm:"type:text" json:"last_error"`
+	DeliveredAt  *time.Time `json:"delivered_at"`
+}
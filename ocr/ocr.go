@@ -0,0 +1,50 @@
+package ocr
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/marc0cl/wets-ma-bu-api/config"
+)
+
+// Line is one line item read off an invoice document.
+type Line struct {
+	Description string
+	Quantity    float64
+	UnitCost    float64
+}
+
+// Result is the structured data a provider extracted from an invoice.
+type Result struct {
+	Supplier string
+	Total    float64
+	Lines    []Line
+}
+
+// Extractor reads structured data out of an uploaded invoice document.
+type Extractor interface {
+	Extract(path string) (*Result, error)
+}
+
+// LogExtractor logs extractions instead of performing them; it is the
+// dev default. Invoices come back empty and go straight to manual
+// review.
+type LogExtractor struct{}
+
+// Extract implements Extractor.
+func (LogExtractor) Extract(path string) (*Result, error) {
+	log.Printf("ocr (dev mode) extract path=%s", path)
+	return &Result{}, nil
+}
+
+// NewFromConfig builds the configured driver. "log" is the dev default:
+// nothing is prefilled and every invoice is reviewed by hand. A hosted
+// OCR service plugs in here as another driver.
+func NewFromConfig(cfg *config.Config) (Extractor, error) {
+	switch cfg.OCRDriver {
+	case "log", "":
+		return LogExtractor{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported ocr driver %q", cfg.OCRDriver)
+	}
+}
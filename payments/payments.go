@@ -0,0 +1,60 @@
+package payments
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/config"
+)
+
+// Provider executes money movements against the payment provider.
+// Drivers are selected via PAYMENTS_DRIVER.
+type Provider interface {
+	// Refund returns amount (in cents) of the charge referenced by
+	// paymentRef to the customer and returns the provider's id for the
+	// refund.
+	Refund(paymentRef string, amountCents int64, reason string) (string, error)
+	// Charge debits amount (in cents) from the stored payment method
+	// referenced by methodRef and returns the provider's id for the
+	// charge.
+	Charge(methodRef string, amountCents int64, description string) (string, error)
+	// ReportUsage pushes a metered-billing quantity onto the
+	// subscription item referenced by subscriptionItemRef.
+	ReportUsage(subscriptionItemRef string, quantity int64, at time.Time) error
+}
+
+// LogProvider logs refunds instead of executing them; it is the dev
+// default.
+type LogProvider struct{}
+
+// Refund implements Provider.
+func (LogProvider) Refund(paymentRef string, amountCents int64, reason string) (string, error) {
+	log.Printf("payments (dev mode) refund ref=%s amount_cents=%d reason=%s", paymentRef, amountCents, reason)
+	return fmt.Sprintf("dev-refund-%s", paymentRef), nil
+}
+
+// Charge implements Provider.
+func (LogProvider) Charge(methodRef string, amountCents int64, description string) (string, error) {
+	log.Printf("payments (dev mode) charge method=%s amount_cents=%d description=%s", methodRef, amountCents, description)
+	return fmt.Sprintf("dev-charge-%s", methodRef), nil
+}
+
+// ReportUsage implements Provider.
+func (LogProvider) ReportUsage(subscriptionItemRef string, quantity int64, at time.Time) error {
+	log.Printf("payments (dev mode) usage item=%s quantity=%d at=%s", subscriptionItemRef, quantity, at.Format(time.RFC3339))
+	return nil
+}
+
+// NewFromConfig builds the configured driver. "log" is the dev default:
+// refunds are printed instead of executed.
+func NewFromConfig(cfg *config.Config) (Provider, error) {
+	switch cfg.PaymentsDriver {
+	case "log", "":
+		return LogProvider{}, nil
+	case "stripe":
+		return NewStripeClient(cfg.StripeAPIKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported payments driver %q", cfg.PaymentsDriver)
+	}
+}
@@ -0,0 +1,150 @@
+package payments
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StripeClient executes refunds through Stripe's REST API. Like the
+// other outbound clients in this codebase it is a thin hand-rolled
+// wrapper rather than the full vendor SDK.
+type StripeClient struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+// NewStripeClient builds a StripeClient.
+func NewStripeClient(apiKey string) *StripeClient {
+	return &StripeClient{
+		apiKey:  apiKey,
+		baseURL: "https://api.stripe.com",
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Charge implements Provider.
+func (s *StripeClient) Charge(methodRef string, amountCents int64, description string) (string, error) {
+	if methodRef == "" {
+		return "", fmt.Errorf("customer has no payment method on file")
+	}
+
+	form := url.Values{}
+	form.Set("payment_method", methodRef)
+	form.Set("amount", strconv.FormatInt(amountCents, 10))
+	form.Set("currency", "clp")
+	form.Set("confirm", "true")
+	form.Set("off_session", "true")
+	form.Set("description", description)
+
+	req, err := http.NewRequest(http.MethodPost, s.baseURL+"/v1/payment_intents", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("stripe charge: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		ID    string `json:"id"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("stripe charge: decoding response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("stripe charge: %s", body.Error.Message)
+	}
+	return body.ID, nil
+}
+
+// ReportUsage implements Provider via Stripe's metered-billing usage
+// records. Action is "increment", so repeated reports for the same
+// period add up instead of overwriting each other.
+func (s *StripeClient) ReportUsage(subscriptionItemRef string, quantity int64, at time.Time) error {
+	if subscriptionItemRef == "" {
+		return fmt.Errorf("key has no subscription item reference")
+	}
+
+	form := url.Values{}
+	form.Set("quantity", strconv.FormatInt(quantity, 10))
+	form.Set("timestamp", strconv.FormatInt(at.Unix(), 10))
+	form.Set("action", "increment")
+
+	req, err := http.NewRequest(http.MethodPost, s.baseURL+"/v1/subscription_items/"+url.PathEscape(subscriptionItemRef)+"/usage_records", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe usage record: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("stripe usage record: decoding response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("stripe usage record: %s", body.Error.Message)
+	}
+	return nil
+}
+
+// Refund implements Provider.
+func (s *StripeClient) Refund(paymentRef string, amountCents int64, reason string) (string, error) {
+	if paymentRef == "" {
+		return "", fmt.Errorf("order has no payment reference")
+	}
+
+	form := url.Values{}
+	form.Set("payment_intent", paymentRef)
+	form.Set("amount", strconv.FormatInt(amountCents, 10))
+	form.Set("metadata[reason]", reason)
+
+	req, err := http.NewRequest(http.MethodPost, s.baseURL+"/v1/refunds", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("stripe refund: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		ID    string `json:"id"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("stripe refund: decoding response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("stripe refund: %s", body.Error.Message)
+	}
+	return body.ID, nil
+}
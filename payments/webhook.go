@@ -0,0 +1,60 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// webhookTolerance bounds how old a signed webhook may be; anything
+// older is treated as a replay.
+const webhookTolerance = 5 * time.Minute
+
+// VerifyWebhookSignature checks a provider webhook signature header of
+// the form "t=<unix>,v1=<hex hmac-sha256 of t.payload>" (Stripe's
+// scheme). An empty secret disables verification, which suits local
+// development only.
+func VerifyWebhookSignature(secret string, payload []byte, header string) bool {
+	if secret == "" {
+		return true
+	}
+
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sigs = append(sigs, value)
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := time.Since(time.Unix(unix, 0))
+	if age > webhookTolerance || age < -webhookTolerance {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	want := hex.EncodeToString(mac.Sum(nil))
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), []byte(want)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package push
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/config"
+)
+
+// Sender delivers a push notification to a single device.
+type Sender interface {
+	Send(token, platform, title, body string, data map[string]string) error
+}
+
+// LogSender logs notifications instead of sending them; it is the dev
+// default.
+type LogSender struct{}
+
+// Send implements Sender.
+func (LogSender) Send(token, platform, title, body string, data map[string]string) error {
+	log.Printf("push (dev mode) platform=%s token=%s title=%q body=%q", platform, token, title, body)
+	return nil
+}
+
+// FCMSender delivers through Firebase Cloud Messaging. APNs devices are
+// reached via FCM as well, which is how our mobile apps are configured.
+type FCMSender struct {
+	ServerKey string
+	BaseURL   string // overridable for tests
+}
+
+// Send implements Sender.
+func (s *FCMSender) Send(token, platform, title, body string, data map[string]string) error {
+	base := s.BaseURL
+	if base == "" {
+		base = "https://fcm.googleapis.com"
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"to":           token,
+		"notification": map[string]string{"title": title, "body": body},
+		"data":         data,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, base+"/fcm/send", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "key="+s.ServerKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("fcm returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// NewFromConfig builds the configured Sender.
+func NewFromConfig(cfg *config.Config) Sender {
+	if cfg.PushDriver == "fcm" && cfg.FCMServerKey != "" {
+		return &FCMSender{ServerKey: cfg.FCMServerKey}
+	}
+	return LogSender{}
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// AccountMappingRepository provides database access for chart-of-account
+// mappings.
+type AccountMappingRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountMappingRepository builds an AccountMappingRepository.
+func NewAccountMappingRepository(db *gorm.DB) *AccountMappingRepository {
+	return &AccountMappingRepository{db: db}
+}
+
+// Get returns the mapping for the given restaurant.
+func (r *AccountMappingRepository) Get(restaurantID uint) (*models.AccountMapping, error) {
+	var mapping models.AccountMapping
+	if err := r.db.Where("restaurant_id = ?", restaurantID).First(&mapping).Error; err != nil {
+		return nil, getError("account mapping", err)
+	}
+	return &mapping, nil
+}
+
+// Save inserts or updates the mapping; each restaurant has at most one.
+func (r *AccountMappingRepository) Save(mapping *models.AccountMapping) error {
+	return withRetry(func() error {
+		return r.db.Save(mapping).Error
+	})
+}
@@ -0,0 +1,198 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// AnalyticsRepository runs the aggregate queries behind the owner
+// dashboards. Grouping happens in SQL so heavy ranges never stream
+// whole tables into the API.
+type AnalyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepository builds an AnalyticsRepository.
+func NewAnalyticsRepository(db *gorm.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// RevenueRow is one time bucket of the revenue aggregate.
+type RevenueRow struct {
+	Period string
+	Orders int64
+	Gross  float64
+	Tips   float64
+	Tax    float64
+}
+
+// periodExpr returns the SQL expression that buckets column into the
+// given granularity. MySQL and sqlite spell date formatting differently.
+func (r *AnalyticsRepository) periodExpr(granularity, column string) string {
+	if r.db.Dialector.Name() == "mysql" {
+		switch granularity {
+		case "week":
+			return fmt.Sprintf("DATE_FORMAT(%s, '%%x-W%%v')", column)
+		case "month":
+			return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m')", column)
+		default:
+			return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-%%d')", column)
+		}
+	}
+	switch granularity {
+	case "week":
+		return fmt.Sprintf("strftime('%%Y-W%%W', %s)", column)
+	case "month":
+		return fmt.Sprintf("strftime('%%Y-%%m', %s)", column)
+	default:
+		return fmt.Sprintf("strftime('%%Y-%%m-%%d', %s)", column)
+	}
+}
+
+// RevenueByPeriod aggregates a restaurant's non-cancelled orders into
+// time buckets, gross of refunds.
+func (r *AnalyticsRepository) RevenueByPeriod(restaurantID uint, granularity string, from, to time.Time) ([]RevenueRow, error) {
+	expr := r.periodExpr(granularity, "created_at")
+	var rows []RevenueRow
+	err := r.db.Model(&models.Order{}).
+		Select(expr+" AS period, COUNT(*) AS orders, COALESCE(SUM(total + tip), 0) AS gross, COALESCE(SUM(tip), 0) AS tips, COALESCE(SUM(tax), 0) AS tax").
+		Where("restaurant_id = ? AND status <> ? AND created_at >= ? AND created_at < ?",
+			restaurantID, models.OrderCancelled, from, to).
+		Group("period").Order("period").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// HourCount is an aggregate count for one UTC hour. Bucket looks like
+// "2006-01-02 15"; callers shift it into the restaurant's timezone.
+type HourCount struct {
+	Bucket string
+	Count  int64
+}
+
+// hourExpr returns the SQL expression that buckets column into UTC
+// date-hours.
+func (r *AnalyticsRepository) hourExpr(column string) string {
+	if r.db.Dialector.Name() == "mysql" {
+		return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-%%d %%H')", column)
+	}
+	return fmt.Sprintf("strftime('%%Y-%%m-%%d %%H', %s)", column)
+}
+
+// OrderCountsByHour counts a restaurant's non-cancelled orders per UTC
+// hour of creation.
+func (r *AnalyticsRepository) OrderCountsByHour(restaurantID uint, from, to time.Time) ([]HourCount, error) {
+	var rows []HourCount
+	err := r.db.Model(&models.Order{}).
+		Select(r.hourExpr("created_at")+" AS bucket, COUNT(*) AS count").
+		Where("restaurant_id = ? AND status <> ? AND created_at >= ? AND created_at < ?",
+			restaurantID, models.OrderCancelled, from, to).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ReservationCountsByHour counts a restaurant's non-cancelled
+// reservations per UTC hour of the reserved date.
+func (r *AnalyticsRepository) ReservationCountsByHour(restaurantID uint, from, to time.Time) ([]HourCount, error) {
+	var rows []HourCount
+	err := r.db.Model(&models.Reservation{}).
+		Select(r.hourExpr("date")+" AS bucket, COUNT(*) AS count").
+		Where("restaurant_id = ? AND status <> ? AND date >= ? AND date < ?",
+			restaurantID, models.ReservationCancelled, from, to).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ItemSalesRow is aggregate sales for one menu item.
+type ItemSalesRow struct {
+	MenuItemID uint
+	Units      int64
+	Revenue    float64
+}
+
+// ItemSales aggregates units sold and revenue per menu item across a
+// restaurant's non-cancelled orders.
+func (r *AnalyticsRepository) ItemSales(restaurantID uint, from, to time.Time) ([]ItemSalesRow, error) {
+	var rows []ItemSalesRow
+	err := r.db.Model(&models.OrderItem{}).
+		Select("order_items.menu_item_id AS menu_item_id, COALESCE(SUM(order_items.quantity), 0) AS units, COALESCE(SUM(order_items.quantity * order_items.price), 0) AS revenue").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.restaurant_id = ? AND orders.status <> ? AND orders.deleted_at IS NULL AND orders.created_at >= ? AND orders.created_at < ?",
+			restaurantID, models.OrderCancelled, from, to).
+		Group("order_items.menu_item_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// RefundsByPeriod aggregates a restaurant's refunds into the same time
+// buckets, keyed by period.
+func (r *AnalyticsRepository) RefundsByPeriod(restaurantID uint, granularity string, from, to time.Time) (map[string]float64, error) {
+	expr := r.periodExpr(granularity, "refunds.created_at")
+	var rows []struct {
+		Period string
+		Amount float64
+	}
+	err := r.db.Model(&models.Refund{}).
+		Select(expr+" AS period, COALESCE(SUM(refunds.amount), 0) AS amount").
+		Joins("JOIN orders ON orders.id = refunds.order_id").
+		Where("orders.restaurant_id = ? AND refunds.created_at >= ? AND refunds.created_at < ?",
+			restaurantID, from, to).
+		Group("period").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	refunds := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		refunds[row.Period] = row.Amount
+	}
+	return refunds, nil
+}
+
+// TableTurnRow aggregates completed table turns for one table number.
+type TableTurnRow struct {
+	TableNumber int
+	Turns       int64
+	AvgMinutes  float64
+}
+
+// TableTurnTimes averages the seated-to-departed span of reservations
+// per table, for turns that began inside [from, to). Only reservations
+// with both timestamps count; table 0 collects parties never assigned a
+// table.
+func (r *AnalyticsRepository) TableTurnTimes(restaurantID uint, from, to time.Time) ([]TableTurnRow, error) {
+	minutesExpr := "(julianday(departed_at) - julianday(seated_at)) * 1440"
+	if r.db.Dialector.Name() == "mysql" {
+		minutesExpr = "TIMESTAMPDIFF(MINUTE, seated_at, departed_at)"
+	}
+	var rows []TableTurnRow
+	err := r.db.Model(&models.Reservation{}).
+		Select(fmt.Sprintf("table_number, COUNT(*) AS turns, AVG(%s) AS avg_minutes", minutesExpr)).
+		Where("restaurant_id = ? AND seated_at IS NOT NULL AND departed_at IS NOT NULL AND seated_at >= ? AND seated_at < ?",
+			restaurantID, from, to).
+		Group("table_number").
+		Order("table_number").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// APIKeyRepository provides database access for publishable API keys.
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository builds an APIKeyRepository.
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create inserts a new key.
+func (r *APIKeyRepository) Create(key *models.APIKey) error {
+	return withRetry(func() error {
+		return r.db.Create(key).Error
+	})
+}
+
+// GetByID returns the key with the given id.
+func (r *APIKeyRepository) GetByID(id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.First(&key, id).Error; err != nil {
+		return nil, getError("api key", err)
+	}
+	return &key, nil
+}
+
+// GetByKey returns the key with the given publishable value.
+func (r *APIKeyRepository) GetByKey(value string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.Where("`key` = ?", value).First(&key).Error
+	if err != nil {
+		return nil, getError("api key", err)
+	}
+	return &key, nil
+}
+
+// GetByRestaurantID returns a restaurant's keys, newest first.
+func (r *APIKeyRepository) GetByRestaurantID(restaurantID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.Where("restaurant_id = ?", restaurantID).
+		Order("created_at DESC").Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Update saves changes to a key.
+func (r *APIKeyRepository) Update(key *models.APIKey) error {
+	return withRetry(func() error {
+		return r.db.Save(key).Error
+	})
+}
+
+// IncrementUsage counts one request against the key for the given
+// period and returns the new total. The increment runs in SQL so
+// concurrent requests never lose counts; at month rollover two first
+// requests may race to insert the period's row, and the loser of the
+// unique index falls back to incrementing the winner's.
+func (r *APIKeyRepository) IncrementUsage(keyID uint, period string) (int64, error) {
+	err := withRetry(func() error {
+		res := r.db.Model(&models.APIKeyUsage{}).
+			Where("api_key_id = ? AND period = ?", keyID, period).
+			UpdateColumn("count", gorm.Expr("count + 1"))
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			return nil
+		}
+		usage := models.APIKeyUsage{APIKeyID: keyID, Period: period, Count: 1}
+		if err := r.db.Create(&usage).Error; err == nil {
+			return nil
+		}
+		return r.db.Model(&models.APIKeyUsage{}).
+			Where("api_key_id = ? AND period = ?", keyID, period).
+			UpdateColumn("count", gorm.Expr("count + 1")).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return r.GetUsage(keyID, period)
+}
+
+// GetUsage returns the key's request count for the given period, zero
+// when the key has not been used in it.
+func (r *APIKeyRepository) GetUsage(keyID uint, period string) (int64, error) {
+	var usage models.APIKeyUsage
+	err := r.db.Where("api_key_id = ? AND period = ?", keyID, period).First(&usage).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return usage.Count, nil
+}
+
+// KeyUsageRow is one key's request count in one period, with enough of
+// the key to label a report line.
+type KeyUsageRow struct {
+	APIKeyID uint   `json:"api_key_id"`
+	Name     string `json:"name"`
+	Plan     string `json:"plan"`
+	Period   string `json:"period"`
+	Count    int64  `json:"count"`
+}
+
+// UsageByRestaurantID returns every usage row of the restaurant's keys,
+// newest period first.
+func (r *APIKeyRepository) UsageByRestaurantID(restaurantID uint) ([]KeyUsageRow, error) {
+	var rows []KeyUsageRow
+	err := r.db.Table("api_key_usages").
+		Select("api_key_usages.api_key_id AS api_key_id, api_keys.name AS name, api_keys.plan AS plan, api_key_usages.period AS period, api_key_usages.count AS count").
+		Joins("JOIN api_keys ON api_keys.id = api_key_usages.api_key_id").
+		Where("api_keys.restaurant_id = ?", restaurantID).
+		Order("api_key_usages.period DESC, api_keys.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// MeteredUsageRow is one usage row with unmetered requests on a key
+// that has a billing reference.
+type MeteredUsageRow struct {
+	UsageID    uint
+	BillingRef string
+	Count      int64
+	Reported   int64
+}
+
+// UnreportedUsage returns the usage rows whose count has moved past
+// what was last pushed to the billing provider.
+func (r *APIKeyRepository) UnreportedUsage() ([]MeteredUsageRow, error) {
+	var rows []MeteredUsageRow
+	err := r.db.Table("api_key_usages").
+		Select("api_key_usages.id AS usage_id, api_keys.billing_ref AS billing_ref, api_key_usages.count AS count, api_key_usages.reported AS reported").
+		Joins("JOIN api_keys ON api_keys.id = api_key_usages.api_key_id").
+		Where("api_keys.billing_ref <> '' AND api_key_usages.count > api_key_usages.reported").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// MarkReported records how far the billing push has metered a usage
+// row.
+func (r *APIKeyRepository) MarkReported(usageID uint, reported int64) error {
+	return withRetry(func() error {
+		return r.db.Model(&models.APIKeyUsage{}).Where("id = ?", usageID).
+			Update("reported", reported).Error
+	})
+}
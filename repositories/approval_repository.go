@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// ApprovalRepository provides database access for approval requests.
+type ApprovalRepository struct {
+	db *gorm.DB
+}
+
+// NewApprovalRepository builds an ApprovalRepository.
+func NewApprovalRepository(db *gorm.DB) *ApprovalRepository {
+	return &ApprovalRepository{db: db}
+}
+
+// Create inserts a new approval request.
+func (r *ApprovalRepository) Create(approval *models.Approval) error {
+	return withRetry(func() error {
+		return r.db.Create(approval).Error
+	})
+}
+
+// GetByID returns the approval with the given id.
+func (r *ApprovalRepository) GetByID(id uint) (*models.Approval, error) {
+	var approval models.Approval
+	if err := r.db.First(&approval, id).Error; err != nil {
+		return nil, getError("approval", err)
+	}
+	return &approval, nil
+}
+
+// GetPendingByRestaurantID returns a restaurant's undecided approvals,
+// oldest first — the order an inbox is worked through.
+func (r *ApprovalRepository) GetPendingByRestaurantID(restaurantID uint) ([]models.Approval, error) {
+	var approvals []models.Approval
+	err := r.db.Where("restaurant_id = ? AND status = ?", restaurantID, models.ApprovalPending).
+		Order("created_at ASC").Find(&approvals).Error
+	if err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+// FindPending returns the undecided approval for the given action, if
+// one exists.
+func (r *ApprovalRepository) FindPending(kind string, subjectID uint) (*models.Approval, error) {
+	var approval models.Approval
+	err := r.db.Where("kind = ? AND subject_id = ? AND status = ?", kind, subjectID, models.ApprovalPending).
+		First(&approval).Error
+	if err != nil {
+		return nil, getError("approval", err)
+	}
+	return &approval, nil
+}
+
+// Update saves changes to an approval.
+func (r *ApprovalRepository) Update(approval *models.Approval) error {
+	return withRetry(func() error {
+		return r.db.Save(approval).Error
+	})
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// BackupRepository provides database access for backup metadata.
+type BackupRepository struct {
+	db *gorm.DB
+}
+
+// NewBackupRepository builds a BackupRepository.
+func NewBackupRepository(db *gorm.DB) *BackupRepository {
+	return &BackupRepository{db: db}
+}
+
+// Create inserts a new backup record.
+func (r *BackupRepository) Create(backup *models.Backup) error {
+	return withRetry(func() error {
+		return r.db.Create(backup).Error
+	})
+}
+
+// Update saves the given backup record.
+func (r *BackupRepository) Update(backup *models.Backup) error {
+	return withRetry(func() error {
+		return r.db.Save(backup).Error
+	})
+}
+
+// GetByKey returns the backup record with the given object key.
+func (r *BackupRepository) GetByKey(key string) (*models.Backup, error) {
+	var backup models.Backup
+	if err := r.db.Where("key = ?", key).First(&backup).Error; err != nil {
+		return nil, getError("backup", err)
+	}
+	return &backup, nil
+}
+
+// List returns the most recent backup records.
+func (r *BackupRepository) List(limit int) ([]models.Backup, error) {
+	var backups []models.Backup
+	err := r.db.Order("id desc").Limit(limit).Find(&backups).Error
+	return backups, err
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// BirthdayRepository provides database access for birthday greeting
+// templates and the lookups the daily run needs.
+type BirthdayRepository struct {
+	db *gorm.DB
+}
+
+// NewBirthdayRepository builds a BirthdayRepository.
+func NewBirthdayRepository(db *gorm.DB) *BirthdayRepository {
+	return &BirthdayRepository{db: db}
+}
+
+// GetTemplate returns a restaurant's birthday template.
+func (r *BirthdayRepository) GetTemplate(restaurantID uint) (*models.BirthdayTemplate, error) {
+	var template models.BirthdayTemplate
+	if err := r.db.Where("restaurant_id = ?", restaurantID).First(&template).Error; err != nil {
+		return nil, getError("birthday template", err)
+	}
+	return &template, nil
+}
+
+// SaveTemplate inserts or updates a birthday template.
+func (r *BirthdayRepository) SaveTemplate(template *models.BirthdayTemplate) error {
+	return withRetry(func() error {
+		return r.db.Save(template).Error
+	})
+}
+
+// UsersWithBirthdayOn returns customers whose birthday falls on the
+// given month and day, whatever year they gave.
+func (r *BirthdayRepository) UsersWithBirthdayOn(month, day int) ([]models.User, error) {
+	expr := "strftime('%m-%d', birthday)"
+	if r.db.Dialector.Name() == "mysql" {
+		expr = "DATE_FORMAT(birthday, '%m-%d')"
+	}
+	var users []models.User
+	err := r.db.Where(fmt.Sprintf("role = ? AND birthday IS NOT NULL AND %s = ?", expr),
+		models.RoleCustomer, fmt.Sprintf("%02d-%02d", month, day)).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// CustomerRestaurantIDs returns the restaurants an email has ordered
+// from — the ones with a relationship worth a greeting.
+func (r *BirthdayRepository) CustomerRestaurantIDs(email string) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Order{}).
+		Distinct("restaurant_id").
+		Where("customer_email = ? AND status <> ?", email, models.OrderCancelled).
+		Pluck("restaurant_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
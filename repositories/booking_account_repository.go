@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// BookingAccountRepository provides database access for external
+// booking platform accounts.
+type BookingAccountRepository struct {
+	db *gorm.DB
+}
+
+// NewBookingAccountRepository builds a BookingAccountRepository.
+func NewBookingAccountRepository(db *gorm.DB) *BookingAccountRepository {
+	return &BookingAccountRepository{db: db}
+}
+
+// Create inserts a new account.
+func (r *BookingAccountRepository) Create(account *models.BookingAccount) error {
+	return withRetry(func() error {
+		return r.db.Create(account).Error
+	})
+}
+
+// GetByRestaurantAndPlatform returns the restaurant's account on the
+// given platform.
+func (r *BookingAccountRepository) GetByRestaurantAndPlatform(restaurantID uint, platform string) (*models.BookingAccount, error) {
+	var account models.BookingAccount
+	err := r.db.Where("restaurant_id = ? AND platform = ?", restaurantID, platform).First(&account).Error
+	if err != nil {
+		return nil, getError("booking account", err)
+	}
+	return &account, nil
+}
+
+// GetByWebhookKey returns the account the given webhook key belongs to.
+func (r *BookingAccountRepository) GetByWebhookKey(key string) (*models.BookingAccount, error) {
+	var account models.BookingAccount
+	if err := r.db.Where("webhook_key = ?", key).First(&account).Error; err != nil {
+		return nil, getError("booking account", err)
+	}
+	return &account, nil
+}
+
+// GetByRestaurantID returns a restaurant's connected platforms.
+func (r *BookingAccountRepository) GetByRestaurantID(restaurantID uint) ([]models.BookingAccount, error) {
+	var accounts []models.BookingAccount
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("platform ASC").Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// List returns every connected account, for the periodic sync.
+func (r *BookingAccountRepository) List() ([]models.BookingAccount, error) {
+	var accounts []models.BookingAccount
+	if err := r.db.Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// Update saves changes to an account.
+func (r *BookingAccountRepository) Update(account *models.BookingAccount) error {
+	return withRetry(func() error {
+		return r.db.Save(account).Error
+	})
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// BrandRepository provides database access for brands.
+type BrandRepository struct {
+	db *gorm.DB
+}
+
+// NewBrandRepository builds a BrandRepository.
+func NewBrandRepository(db *gorm.DB) *BrandRepository {
+	return &BrandRepository{db: db}
+}
+
+// Create inserts a new brand.
+func (r *BrandRepository) Create(brand *models.Brand) error {
+	return withRetry(func() error {
+		return r.db.Create(brand).Error
+	})
+}
+
+// GetByID returns the brand with the given id.
+func (r *BrandRepository) GetByID(id uint) (*models.Brand, error) {
+	var brand models.Brand
+	if err := r.db.First(&brand, id).Error; err != nil {
+		return nil, getError("brand", err)
+	}
+	return &brand, nil
+}
+
+// GetByUserID returns an owner's brands, by name.
+func (r *BrandRepository) GetByUserID(userID uint) ([]models.Brand, error) {
+	var brands []models.Brand
+	err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&brands).Error
+	if err != nil {
+		return nil, err
+	}
+	return brands, nil
+}
+
+// Update saves changes to a brand.
+func (r *BrandRepository) Update(brand *models.Brand) error {
+	return withRetry(func() error {
+		return r.db.Save(brand).Error
+	})
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// CateringRepository provides database access for catering requests and
+// their quote lines.
+type CateringRepository struct {
+	db *gorm.DB
+}
+
+// NewCateringRepository builds a CateringRepository.
+func NewCateringRepository(db *gorm.DB) *CateringRepository {
+	return &CateringRepository{db: db}
+}
+
+// Create inserts a new catering request.
+func (r *CateringRepository) Create(request *models.CateringRequest) error {
+	return withRetry(func() error {
+		return r.db.Create(request).Error
+	})
+}
+
+// GetByID returns the catering request with the given id, quote lines
+// included.
+func (r *CateringRepository) GetByID(id uint) (*models.CateringRequest, error) {
+	var request models.CateringRequest
+	if err := r.db.Preload("Lines").First(&request, id).Error; err != nil {
+		return nil, getError("catering request", err)
+	}
+	return &request, nil
+}
+
+// GetByRestaurantID returns a restaurant's catering requests, newest
+// first.
+func (r *CateringRepository) GetByRestaurantID(restaurantID uint) ([]models.CateringRequest, error) {
+	var requests []models.CateringRequest
+	err := r.db.Preload("Lines").
+		Where("restaurant_id = ?", restaurantID).
+		Order("created_at DESC").
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// Update saves changes to an existing catering request.
+func (r *CateringRepository) Update(request *models.CateringRequest) error {
+	return withRetry(func() error {
+		return r.db.Save(request).Error
+	})
+}
+
+// ReplaceLines swaps the quote lines of a request for a new set, so
+// re-quoting does not accumulate stale lines.
+func (r *CateringRepository) ReplaceLines(requestID uint, lines []models.CateringQuoteLine) error {
+	return withRetry(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Where("catering_request_id = ?", requestID).
+				Delete(&models.CateringQuoteLine{}).Error; err != nil {
+				return err
+			}
+			for i := range lines {
+				lines[i].CateringRequestID = requestID
+			}
+			return tx.Create(&lines).Error
+		})
+	})
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// ComplianceRepository provides database access for compliance items
+// and their completion records.
+type ComplianceRepository struct {
+	db *gorm.DB
+}
+
+// NewComplianceRepository builds a ComplianceRepository.
+func NewComplianceRepository(db *gorm.DB) *ComplianceRepository {
+	return &ComplianceRepository{db: db}
+}
+
+// Create inserts a new compliance item.
+func (r *ComplianceRepository) Create(item *models.ComplianceItem) error {
+	return withRetry(func() error {
+		return r.db.Create(item).Error
+	})
+}
+
+// GetByID returns the item with the given id, completions included.
+func (r *ComplianceRepository) GetByID(id uint) (*models.ComplianceItem, error) {
+	var item models.ComplianceItem
+	if err := r.db.Preload("Completions").First(&item, id).Error; err != nil {
+		return nil, getError("compliance item", err)
+	}
+	return &item, nil
+}
+
+// GetByRestaurantID returns a restaurant's items, soonest due first.
+func (r *ComplianceRepository) GetByRestaurantID(restaurantID uint) ([]models.ComplianceItem, error) {
+	var items []models.ComplianceItem
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("due_at ASC").Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update saves changes to an item.
+func (r *ComplianceRepository) Update(item *models.ComplianceItem) error {
+	return withRetry(func() error {
+		return r.db.Save(item).Error
+	})
+}
+
+// CreateCompletion inserts a completion record.
+func (r *ComplianceRepository) CreateCompletion(completion *models.ComplianceCompletion) error {
+	return withRetry(func() error {
+		return r.db.Create(completion).Error
+	})
+}
+
+// OverdueUnescalated returns open items past their due date that have
+// not been escalated yet.
+func (r *ComplianceRepository) OverdueUnescalated(now time.Time) ([]models.ComplianceItem, error) {
+	var items []models.ComplianceItem
+	err := r.db.Where("due_at < ? AND done = ? AND escalated_at IS NULL", now, false).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// ConsentRepository provides database access for marketing consents.
+type ConsentRepository struct {
+	db *gorm.DB
+}
+
+// NewConsentRepository builds a ConsentRepository.
+func NewConsentRepository(db *gorm.DB) *ConsentRepository {
+	return &ConsentRepository{db: db}
+}
+
+// Get returns the consent record for an email and channel.
+func (r *ConsentRepository) Get(email, channel string) (*models.Consent, error) {
+	var consent models.Consent
+	err := r.db.Where("customer_email = ? AND channel = ?", email, channel).
+		First(&consent).Error
+	if err != nil {
+		return nil, getError("consent", err)
+	}
+	return &consent, nil
+}
+
+// GetByEmail returns all of an email's consent records.
+func (r *ConsentRepository) GetByEmail(email string) ([]models.Consent, error) {
+	var consents []models.Consent
+	if err := r.db.Where("customer_email = ?", email).Find(&consents).Error; err != nil {
+		return nil, err
+	}
+	return consents, nil
+}
+
+// Save inserts or updates a consent record.
+func (r *ConsentRepository) Save(consent *models.Consent) error {
+	return withRetry(func() error {
+		return r.db.Save(consent).Error
+	})
+}
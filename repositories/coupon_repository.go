@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// CouponRepository provides database access for coupons.
+type CouponRepository struct {
+	db *gorm.DB
+}
+
+// NewCouponRepository builds a CouponRepository.
+func NewCouponRepository(db *gorm.DB) *CouponRepository {
+	return &CouponRepository{db: db}
+}
+
+// Create inserts a new coupon.
+func (r *CouponRepository) Create(coupon *models.Coupon) error {
+	return withRetry(func() error {
+		return r.db.Create(coupon).Error
+	})
+}
+
+// GetByEmail returns a customer's coupons, newest first.
+func (r *CouponRepository) GetByEmail(email string) ([]models.Coupon, error) {
+	var coupons []models.Coupon
+	err := r.db.Where("customer_email = ?", email).
+		Order("created_at DESC").
+		Find(&coupons).Error
+	if err != nil {
+		return nil, err
+	}
+	return coupons, nil
+}
+
+// CountBySource counts coupons a restaurant issued to an email from the
+// given source since a point in time — used to issue birthday rewards
+// at most once a year.
+func (r *CouponRepository) CountBySource(restaurantID uint, email, source string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Coupon{}).
+		Where("restaurant_id = ? AND customer_email = ? AND source = ? AND created_at >= ?",
+			restaurantID, email, source, since).
+		Count(&count).Error
+	return count, err
+}
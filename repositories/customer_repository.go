@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// CustomerRepository provides database access for customer profile data
+// (saved addresses and tokenized payment methods).
+type CustomerRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomerRepository builds a CustomerRepository.
+func NewCustomerRepository(db *gorm.DB) *CustomerRepository {
+	return &CustomerRepository{db: db}
+}
+
+// ListAddresses returns all saved addresses of the given customer.
+func (r *CustomerRepository) ListAddresses(userID uint) ([]models.CustomerAddress, error) {
+	var addresses []models.CustomerAddress
+	if err := r.db.Where("user_id = ?", userID).Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// CreateAddress inserts a new saved address.
+func (r *CustomerRepository) CreateAddress(address *models.CustomerAddress) error {
+	return withRetry(func() error {
+		return r.db.Create(address).Error
+	})
+}
+
+// DeleteAddress soft-deletes an address, scoped to its owner so one
+// customer cannot delete another's.
+func (r *CustomerRepository) DeleteAddress(id, userID uint) error {
+	return withRetry(func() error {
+		return r.db.Where("user_id = ?", userID).Delete(&models.CustomerAddress{}, id).Error
+	})
+}
+
+// ClearDefaultAddress unsets the default flag on every address of the
+// given customer, ahead of marking a new one.
+func (r *CustomerRepository) ClearDefaultAddress(userID uint) error {
+	return r.db.Model(&models.CustomerAddress{}).
+		Where("user_id = ?", userID).Update("is_default", false).Error
+}
+
+// ListPaymentMethods returns all saved payment methods of the given customer.
+func (r *CustomerRepository) ListPaymentMethods(userID uint) ([]models.CustomerPaymentMethod, error) {
+	var methods []models.CustomerPaymentMethod
+	if err := r.db.Where("user_id = ?", userID).Find(&methods).Error; err != nil {
+		return nil, err
+	}
+	return methods, nil
+}
+
+// CreatePaymentMethod inserts a new tokenized payment method.
+func (r *CustomerRepository) CreatePaymentMethod(method *models.CustomerPaymentMethod) error {
+	return withRetry(func() error {
+		return r.db.Create(method).Error
+	})
+}
+
+// DeletePaymentMethod soft-deletes a payment method, scoped to its owner.
+func (r *CustomerRepository) DeletePaymentMethod(id, userID uint) error {
+	return withRetry(func() error {
+		return r.db.Where("user_id = ?", userID).Delete(&models.CustomerPaymentMethod{}, id).Error
+	})
+}
+
+// ClearDefaultPaymentMethod unsets the default flag on every payment
+// method of the given customer, ahead of marking a new one.
+func (r *CustomerRepository) ClearDefaultPaymentMethod(userID uint) error {
+	return r.db.Model(&models.CustomerPaymentMethod{}).
+		Where("user_id = ?", userID).Update("is_default", false).Error
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// DeviceTokenRepository provides database access for push device tokens.
+type DeviceTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewDeviceTokenRepository builds a DeviceTokenRepository.
+func NewDeviceTokenRepository(db *gorm.DB) *DeviceTokenRepository {
+	return &DeviceTokenRepository{db: db}
+}
+
+// Upsert registers a token, updating the owner and preferences if the
+// token already exists (devices get reassigned between accounts).
+func (r *DeviceTokenRepository) Upsert(token *models.DeviceToken) error {
+	return withRetry(func() error {
+		return r.db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "token"}},
+			DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "notify_orders", "notify_reservations"}),
+		}).Create(token).Error
+	})
+}
+
+// DeleteByToken removes a registration.
+func (r *DeviceTokenRepository) DeleteByToken(userID uint, token string) error {
+	return withRetry(func() error {
+		return r.db.Where("user_id = ? AND token = ?", userID, token).
+			Delete(&models.DeviceToken{}).Error
+	})
+}
+
+// GetByUserID returns all device tokens of a user.
+func (r *DeviceTokenRepository) GetByUserID(userID uint) ([]models.DeviceToken, error) {
+	var tokens []models.DeviceToken
+	err := r.db.Where("user_id = ?", userID).Find(&tokens).Error
+	return tokens, err
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// DisputeRepository provides database access for payment disputes.
+type DisputeRepository struct {
+	db *gorm.DB
+}
+
+// NewDisputeRepository builds a DisputeRepository.
+func NewDisputeRepository(db *gorm.DB) *DisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+// Create inserts a new dispute.
+func (r *DisputeRepository) Create(dispute *models.Dispute) error {
+	return withRetry(func() error {
+		return r.db.Create(dispute).Error
+	})
+}
+
+// Update saves an existing dispute.
+func (r *DisputeRepository) Update(dispute *models.Dispute) error {
+	return withRetry(func() error {
+		return r.db.Save(dispute).Error
+	})
+}
+
+// GetByID returns the dispute with the given id.
+func (r *DisputeRepository) GetByID(id uint) (*models.Dispute, error) {
+	var dispute models.Dispute
+	if err := r.db.First(&dispute, id).Error; err != nil {
+		return nil, getError("dispute", err)
+	}
+	return &dispute, nil
+}
+
+// GetByProviderRef returns the dispute with the given provider
+// reference.
+func (r *DisputeRepository) GetByProviderRef(providerRef string) (*models.Dispute, error) {
+	var dispute models.Dispute
+	if err := r.db.Where("provider_ref = ?", providerRef).First(&dispute).Error; err != nil {
+		return nil, getError("dispute", err)
+	}
+	return &dispute, nil
+}
+
+// GetByRestaurantID returns all disputes against a restaurant's orders,
+// newest first.
+func (r *DisputeRepository) GetByRestaurantID(restaurantID uint) ([]models.Dispute, error) {
+	var disputes []models.Dispute
+	err := r.db.Joins("JOIN orders ON orders.id = disputes.order_id").
+		Where("orders.restaurant_id = ?", restaurantID).
+		Order("disputes.created_at DESC").
+		Find(&disputes).Error
+	if err != nil {
+		return nil, err
+	}
+	return disputes, nil
+}
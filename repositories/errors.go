@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Sentinels distinguishing the two ways a lookup can fail. Services
+// check ErrNotFound with errors.Is; anything wrapping ErrInternal is a
+// real database failure and must not be presented as a 404.
+var (
+	ErrNotFound = errors.New("not found")
+	ErrInternal = errors.New("database error")
+)
+
+// getError classifies a failed lookup: a missing row wraps ErrNotFound
+// with the entity name, anything else (lost connection, bad SQL) wraps
+// ErrInternal and keeps the driver error in the message.
+func getError(entity string, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%s %w", entity, ErrNotFound)
+	}
+	return fmt.Errorf("%w: loading %s: %v", ErrInternal, entity, err)
+}
+
+// IsDuplicateKey reports whether err is a unique-constraint violation.
+// Matched by driver message because neither the mysql driver used through
+// gorm nor sqlite expose a portable sentinel for it.
+func IsDuplicateKey(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "Error 1062") || // MySQL duplicate entry
+		strings.Contains(msg, "Duplicate entry") ||
+		strings.Contains(msg, "UNIQUE constraint failed") // sqlite
+}
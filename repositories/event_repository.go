@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// EventRepository provides database access for events, their ticket
+// tiers and sold tickets.
+type EventRepository struct {
+	db *gorm.DB
+}
+
+// NewEventRepository builds an EventRepository.
+func NewEventRepository(db *gorm.DB) *EventRepository {
+	return &EventRepository{db: db}
+}
+
+// Create inserts a new event together with its tiers.
+func (r *EventRepository) Create(event *models.Event) error {
+	return withRetry(func() error {
+		return r.db.Create(event).Error
+	})
+}
+
+// GetByID returns the event with the given id, tiers included.
+func (r *EventRepository) GetByID(id uint) (*models.Event, error) {
+	var event models.Event
+	if err := r.db.Preload("Tiers").First(&event, id).Error; err != nil {
+		return nil, getError("event", err)
+	}
+	return &event, nil
+}
+
+// GetByRestaurantID returns a restaurant's events, soonest first.
+func (r *EventRepository) GetByRestaurantID(restaurantID uint) ([]models.Event, error) {
+	var events []models.Event
+	err := r.db.Preload("Tiers").
+		Where("restaurant_id = ?", restaurantID).
+		Order("starts_at ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// CreateTicket inserts a sold ticket.
+func (r *EventRepository) CreateTicket(ticket *models.Ticket) error {
+	return withRetry(func() error {
+		return r.db.Create(ticket).Error
+	})
+}
+
+// UpdateTicket saves changes to an existing ticket.
+func (r *EventRepository) UpdateTicket(ticket *models.Ticket) error {
+	return withRetry(func() error {
+		return r.db.Save(ticket).Error
+	})
+}
+
+// GetTicketByCode returns the ticket with the given code.
+func (r *EventRepository) GetTicketByCode(code string) (*models.Ticket, error) {
+	var ticket models.Ticket
+	if err := r.db.Where("code = ?", code).First(&ticket).Error; err != nil {
+		return nil, getError("ticket", err)
+	}
+	return &ticket, nil
+}
+
+// GetTicketsByEventID returns all tickets sold for an event.
+func (r *EventRepository) GetTicketsByEventID(eventID uint) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	if err := r.db.Where("event_id = ?", eventID).Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// GetTicketsByCustomerID returns a customer's tickets, newest first.
+func (r *EventRepository) GetTicketsByCustomerID(customerID uint) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	if err := r.db.Where("customer_id = ?", customerID).Order("created_at DESC").Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// CountTicketsByTierID returns how many tickets a tier has sold.
+func (r *EventRepository) CountTicketsByTierID(tierID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Ticket{}).Where("tier_id = ?", tierID).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// ExperimentRepository provides database access for menu experiments,
+// their variants and visitor exposures.
+type ExperimentRepository struct {
+	db *gorm.DB
+}
+
+// NewExperimentRepository builds an ExperimentRepository.
+func NewExperimentRepository(db *gorm.DB) *ExperimentRepository {
+	return &ExperimentRepository{db: db}
+}
+
+// Create inserts a new experiment together with its variants.
+func (r *ExperimentRepository) Create(experiment *models.Experiment) error {
+	return withRetry(func() error {
+		return r.db.Create(experiment).Error
+	})
+}
+
+// Update saves changes to an existing experiment.
+func (r *ExperimentRepository) Update(experiment *models.Experiment) error {
+	return withRetry(func() error {
+		return r.db.Save(experiment).Error
+	})
+}
+
+// GetByID returns the experiment with the given id, variants included.
+func (r *ExperimentRepository) GetByID(id uint) (*models.Experiment, error) {
+	var experiment models.Experiment
+	if err := r.db.Preload("Variants").First(&experiment, id).Error; err != nil {
+		return nil, getError("experiment", err)
+	}
+	return &experiment, nil
+}
+
+// GetByRestaurantID returns a restaurant's experiments, newest first.
+func (r *ExperimentRepository) GetByRestaurantID(restaurantID uint) ([]models.Experiment, error) {
+	var experiments []models.Experiment
+	err := r.db.Preload("Variants").
+		Where("restaurant_id = ?", restaurantID).
+		Order("created_at DESC").
+		Find(&experiments).Error
+	if err != nil {
+		return nil, err
+	}
+	return experiments, nil
+}
+
+// GetRunningByRestaurantID returns the restaurant's running experiment,
+// if any.
+func (r *ExperimentRepository) GetRunningByRestaurantID(restaurantID uint) (*models.Experiment, error) {
+	var experiment models.Experiment
+	err := r.db.Preload("Variants").
+		Where("restaurant_id = ? AND status = ?", restaurantID, models.ExperimentRunning).
+		First(&experiment).Error
+	if err != nil {
+		return nil, getError("experiment", err)
+	}
+	return &experiment, nil
+}
+
+// CreateExposure records a visitor's first sight of a variant. Repeat
+// visits hit the existing row and are not duplicated.
+func (r *ExperimentRepository) CreateExposure(exposure *models.ExperimentExposure) error {
+	return withRetry(func() error {
+		return r.db.Where("experiment_id = ? AND visitor_id = ?", exposure.ExperimentID, exposure.VisitorID).
+			FirstOrCreate(exposure).Error
+	})
+}
+
+// GetExposure returns a visitor's exposure to an experiment.
+func (r *ExperimentRepository) GetExposure(experimentID uint, visitorID string) (*models.ExperimentExposure, error) {
+	var exposure models.ExperimentExposure
+	err := r.db.Where("experiment_id = ? AND visitor_id = ?", experimentID, visitorID).
+		First(&exposure).Error
+	if err != nil {
+		return nil, getError("exposure", err)
+	}
+	return &exposure, nil
+}
+
+// UpdateExposure saves changes to an exposure.
+func (r *ExperimentRepository) UpdateExposure(exposure *models.ExperimentExposure) error {
+	return withRetry(func() error {
+		return r.db.Save(exposure).Error
+	})
+}
+
+// ExposureStatsRow aggregates an experiment's exposures for one variant.
+type ExposureStatsRow struct {
+	VariantID   uint
+	Exposures   int64
+	Conversions int64
+	OrderValue  float64
+}
+
+// ExposureStats returns per-variant exposure, conversion and order
+// value totals for an experiment.
+func (r *ExperimentRepository) ExposureStats(experimentID uint) ([]ExposureStatsRow, error) {
+	var rows []ExposureStatsRow
+	err := r.db.Model(&models.ExperimentExposure{}).
+		Select("variant_id, COUNT(*) AS exposures, "+
+			"SUM(CASE WHEN converted THEN 1 ELSE 0 END) AS conversions, "+
+			"COALESCE(SUM(order_value), 0) AS order_value").
+		Where("experiment_id = ?", experimentID).
+		Group("variant_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
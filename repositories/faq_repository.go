@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// FAQRepository provides database access for FAQ entries.
+type FAQRepository struct {
+	db *gorm.DB
+}
+
+// NewFAQRepository builds a FAQRepository.
+func NewFAQRepository(db *gorm.DB) *FAQRepository {
+	return &FAQRepository{db: db}
+}
+
+// Create inserts a new entry.
+func (r *FAQRepository) Create(entry *models.FAQEntry) error {
+	return withRetry(func() error {
+		return r.db.Create(entry).Error
+	})
+}
+
+// GetByID returns the entry with the given id.
+func (r *FAQRepository) GetByID(id uint) (*models.FAQEntry, error) {
+	var entry models.FAQEntry
+	if err := r.db.First(&entry, id).Error; err != nil {
+		return nil, getError("faq entry", err)
+	}
+	return &entry, nil
+}
+
+// GetByRestaurantID returns a restaurant's entries, oldest first.
+func (r *FAQRepository) GetByRestaurantID(restaurantID uint) ([]models.FAQEntry, error) {
+	var entries []models.FAQEntry
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("id ASC").Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Delete removes an entry.
+func (r *FAQRepository) Delete(id uint) error {
+	return withRetry(func() error {
+		return r.db.Delete(&models.FAQEntry{}, id).Error
+	})
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// HardwareRepository provides database access for hardware devices.
+type HardwareRepository struct {
+	db *gorm.DB
+}
+
+// NewHardwareRepository builds a HardwareRepository.
+func NewHardwareRepository(db *gorm.DB) *HardwareRepository {
+	return &HardwareRepository{db: db}
+}
+
+// Create inserts a new hardware device.
+func (r *HardwareRepository) Create(device *models.HardwareDevice) error {
+	return withRetry(func() error {
+		return r.db.Create(device).Error
+	})
+}
+
+// GetByID returns the hardware device with the given id.
+func (r *HardwareRepository) GetByID(id uint) (*models.HardwareDevice, error) {
+	var device models.HardwareDevice
+	if err := r.db.First(&device, id).Error; err != nil {
+		return nil, getError("device", err)
+	}
+	return &device, nil
+}
+
+// GetByToken returns the hardware device with the given token.
+func (r *HardwareRepository) GetByToken(token string) (*models.HardwareDevice, error) {
+	var device models.HardwareDevice
+	if err := r.db.Where("token = ?", token).First(&device).Error; err != nil {
+		return nil, getError("device", err)
+	}
+	return &device, nil
+}
+
+// GetByRestaurantID returns all hardware devices of the given
+// restaurant.
+func (r *HardwareRepository) GetByRestaurantID(restaurantID uint) ([]models.HardwareDevice, error) {
+	var devices []models.HardwareDevice
+	if err := r.db.Where("restaurant_id = ?", restaurantID).Find(&devices).Error; err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// Update saves the given hardware device.
+func (r *HardwareRepository) Update(device *models.HardwareDevice) error {
+	return withRetry(func() error {
+		return r.db.Save(device).Error
+	})
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// HealthRepository provides database access for health inspection
+// records.
+type HealthRepository struct {
+	db *gorm.DB
+}
+
+// NewHealthRepository builds a HealthRepository.
+func NewHealthRepository(db *gorm.DB) *HealthRepository {
+	return &HealthRepository{db: db}
+}
+
+// Create inserts a new inspection record.
+func (r *HealthRepository) Create(inspection *models.HealthInspection) error {
+	return withRetry(func() error {
+		return r.db.Create(inspection).Error
+	})
+}
+
+// GetByID returns the inspection with the given id.
+func (r *HealthRepository) GetByID(id uint) (*models.HealthInspection, error) {
+	var inspection models.HealthInspection
+	if err := r.db.First(&inspection, id).Error; err != nil {
+		return nil, getError("health inspection", err)
+	}
+	return &inspection, nil
+}
+
+// GetByRestaurantID returns a restaurant's inspections, most recent
+// visit first.
+func (r *HealthRepository) GetByRestaurantID(restaurantID uint) ([]models.HealthInspection, error) {
+	var inspections []models.HealthInspection
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("date DESC").Find(&inspections).Error
+	if err != nil {
+		return nil, err
+	}
+	return inspections, nil
+}
+
+// GetLatestByRestaurantID returns a restaurant's most recent
+// inspection.
+func (r *HealthRepository) GetLatestByRestaurantID(restaurantID uint) (*models.HealthInspection, error) {
+	var inspection models.HealthInspection
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("date DESC").First(&inspection).Error
+	if err != nil {
+		return nil, getError("health inspection", err)
+	}
+	return &inspection, nil
+}
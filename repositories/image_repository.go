@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// ImageRepository provides database access for uploaded images.
+type ImageRepository struct {
+	db *gorm.DB
+}
+
+// NewImageRepository builds an ImageRepository.
+func NewImageRepository(db *gorm.DB) *ImageRepository {
+	return &ImageRepository{db: db}
+}
+
+// Create inserts a new image.
+func (r *ImageRepository) Create(image *models.Image) error {
+	return withRetry(func() error {
+		return r.db.Create(image).Error
+	})
+}
+
+// GetByID returns the image with the given id.
+func (r *ImageRepository) GetByID(id uint) (*models.Image, error) {
+	var image models.Image
+	if err := r.db.First(&image, id).Error; err != nil {
+		return nil, getError("image", err)
+	}
+	return &image, nil
+}
+
+// GetLatestByEntity returns the most recent image of an entity.
+func (r *ImageRepository) GetLatestByEntity(entityType string, entityID uint) (*models.Image, error) {
+	var image models.Image
+	err := r.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("id desc").First(&image).Error
+	if err != nil {
+		return nil, getError("image", err)
+	}
+	return &image, nil
+}
+
+// Update saves the given image.
+func (r *ImageRepository) Update(image *models.Image) error {
+	return withRetry(func() error {
+		return r.db.Save(image).Error
+	})
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// InventoryRepository provides database access for ingredients and
+// recipes.
+type InventoryRepository struct {
+	db *gorm.DB
+}
+
+// NewInventoryRepository builds an InventoryRepository.
+func NewInventoryRepository(db *gorm.DB) *InventoryRepository {
+	return &InventoryRepository{db: db}
+}
+
+// CreateIngredient inserts a new ingredient.
+func (r *InventoryRepository) CreateIngredient(ingredient *models.Ingredient) error {
+	return withRetry(func() error {
+		return r.db.Create(ingredient).Error
+	})
+}
+
+// GetIngredientByID returns the ingredient with the given id.
+func (r *InventoryRepository) GetIngredientByID(id uint) (*models.Ingredient, error) {
+	var ingredient models.Ingredient
+	if err := r.db.First(&ingredient, id).Error; err != nil {
+		return nil, getError("ingredient", err)
+	}
+	return &ingredient, nil
+}
+
+// GetIngredientsByRestaurantID returns a restaurant's ingredients,
+// alphabetically.
+func (r *InventoryRepository) GetIngredientsByRestaurantID(restaurantID uint) ([]models.Ingredient, error) {
+	var ingredients []models.Ingredient
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("name ASC").Find(&ingredients).Error
+	if err != nil {
+		return nil, err
+	}
+	return ingredients, nil
+}
+
+// UpdateIngredient saves changes to an ingredient.
+func (r *InventoryRepository) UpdateIngredient(ingredient *models.Ingredient) error {
+	return withRetry(func() error {
+		return r.db.Save(ingredient).Error
+	})
+}
+
+// GetRecipe returns a menu item's recipe with ingredients loaded.
+func (r *InventoryRepository) GetRecipe(menuItemID uint) ([]models.RecipeItem, error) {
+	var items []models.RecipeItem
+	err := r.db.Preload("Ingredient").Where("menu_item_id = ?", menuItemID).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ReplaceRecipe swaps a menu item's recipe for a fresh set of lines in
+// one transaction.
+func (r *InventoryRepository) ReplaceRecipe(menuItemID uint, items []models.RecipeItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("menu_item_id = ?", menuItemID).Delete(&models.RecipeItem{}).Error
+		if err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].MenuItemID = menuItemID
+			if err := tx.Create(&items[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AdjustStock records a stock movement and applies its delta to the
+// ingredient in one transaction.
+func (r *InventoryRepository) AdjustStock(movement *models.StockMovement) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(movement).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Ingredient{}).Where("id = ?", movement.IngredientID).
+			UpdateColumn("stock", gorm.Expr("stock + ?", movement.Delta)).Error
+	})
+}
+
+// GetMovementsByIngredientID returns an ingredient's stock movements,
+// newest first.
+func (r *InventoryRepository) GetMovementsByIngredientID(ingredientID uint) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+	err := r.db.Where("ingredient_id = ?", ingredientID).Order("created_at DESC").Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// MenuItemIDsUsingIngredient returns the menu items whose recipes use
+// the given ingredient.
+func (r *InventoryRepository) MenuItemIDsUsingIngredient(ingredientID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.RecipeItem{}).Where("ingredient_id = ?", ingredientID).Pluck("menu_item_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// InvoiceRepository provides database access for supplier invoices.
+type InvoiceRepository struct {
+	db *gorm.DB
+}
+
+// NewInvoiceRepository builds an InvoiceRepository.
+func NewInvoiceRepository(db *gorm.DB) *InvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+// Create inserts a new invoice record.
+func (r *InvoiceRepository) Create(invoice *models.SupplierInvoice) error {
+	return withRetry(func() error {
+		return r.db.Create(invoice).Error
+	})
+}
+
+// GetByID returns the invoice with the given id, lines included.
+func (r *InvoiceRepository) GetByID(id uint) (*models.SupplierInvoice, error) {
+	var invoice models.SupplierInvoice
+	if err := r.db.Preload("Lines").First(&invoice, id).Error; err != nil {
+		return nil, getError("supplier invoice", err)
+	}
+	return &invoice, nil
+}
+
+// GetByRestaurantID returns a restaurant's invoices, newest first.
+func (r *InvoiceRepository) GetByRestaurantID(restaurantID uint) ([]models.SupplierInvoice, error) {
+	var invoices []models.SupplierInvoice
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("created_at DESC").Find(&invoices).Error
+	if err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// Update saves changes to an invoice record.
+func (r *InvoiceRepository) Update(invoice *models.SupplierInvoice) error {
+	return withRetry(func() error {
+		return r.db.Save(invoice).Error
+	})
+}
+
+// ReplaceLines swaps an invoice's lines for a fresh set in one
+// transaction, so re-extraction or review edits do not accumulate stale
+// lines.
+func (r *InvoiceRepository) ReplaceLines(invoiceID uint, lines []models.SupplierInvoiceLine) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("supplier_invoice_id = ?", invoiceID).Delete(&models.SupplierInvoiceLine{}).Error
+		if err != nil {
+			return err
+		}
+		for i := range lines {
+			lines[i].SupplierInvoiceID = invoiceID
+			if err := tx.Create(&lines[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// JobRepository provides database access for background jobs.
+type JobRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRepository builds a JobRepository.
+func NewJobRepository(db *gorm.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+// Enqueue inserts a new pending job.
+func (r *JobRepository) Enqueue(job *models.Job) error {
+	if job.Status == "" {
+		job.Status = models.JobPending
+	}
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = 5
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	return withRetry(func() error {
+		return r.db.Create(job).Error
+	})
+}
+
+// ClaimNext atomically takes the oldest due pending job and marks it
+// running. It returns nil, nil when the queue is empty.
+func (r *JobRepository) ClaimNext() (*models.Job, error) {
+	var job models.Job
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("status = ? AND run_at <= ?", models.JobPending, time.Now()).
+			Order("run_at").First(&job).Error; err != nil {
+			return err
+		}
+		return tx.Model(&job).Update("status", models.JobRunning).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// MarkDone records a successful run.
+func (r *JobRepository) MarkDone(id uint) error {
+	return withRetry(func() error {
+		return r.db.Model(&models.Job{}).Where("id = ?", id).
+			Update("status", models.JobDone).Error
+	})
+}
+
+// MarkFailed records a failed run: the job is rescheduled with backoff,
+// or parked as dead once its attempts are exhausted.
+func (r *JobRepository) MarkFailed(job *models.Job, runErr error, backoff time.Duration) error {
+	job.Attempts++
+	job.LastError = runErr.Error()
+	if job.Attempts >= job.MaxAttempts {
+		job.Status = models.JobDead
+	} else {
+		job.Status = models.JobPending
+		job.RunAt = time.Now().Add(backoff)
+	}
+	return withRetry(func() error {
+		return r.db.Save(job).Error
+	})
+}
+
+// Stats returns job counts grouped by status.
+func (r *JobRepository) Stats() (map[string]int64, error) {
+	type row struct {
+		Status string
+		Count  int64
+	}
+	var rows []row
+	if err := r.db.Model(&models.Job{}).Select("status, count(*) as count").
+		Group("status").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	stats := make(map[string]int64, len(rows))
+	for _, r := range rows {
+		stats[r.Status] = r.Count
+	}
+	return stats, nil
+}
+
+// ListDead returns the most recent dead jobs for inspection.
+func (r *JobRepository) ListDead(limit int) ([]models.Job, error) {
+	var dead []models.Job
+	err := r.db.Where("status = ?", models.JobDead).
+		Order("updated_at desc").Limit(limit).Find(&dead).Error
+	return dead, err
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// LicenseRepository provides database access for license and permit
+// documents.
+type LicenseRepository struct {
+	db *gorm.DB
+}
+
+// NewLicenseRepository builds a LicenseRepository.
+func NewLicenseRepository(db *gorm.DB) *LicenseRepository {
+	return &LicenseRepository{db: db}
+}
+
+// Create inserts a new document record.
+func (r *LicenseRepository) Create(document *models.LicenseDocument) error {
+	return withRetry(func() error {
+		return r.db.Create(document).Error
+	})
+}
+
+// GetByID returns the document with the given id.
+func (r *LicenseRepository) GetByID(id uint) (*models.LicenseDocument, error) {
+	var document models.LicenseDocument
+	if err := r.db.First(&document, id).Error; err != nil {
+		return nil, getError("license document", err)
+	}
+	return &document, nil
+}
+
+// GetByRestaurantID returns a restaurant's documents, soonest to expire
+// first.
+func (r *LicenseRepository) GetByRestaurantID(restaurantID uint) ([]models.LicenseDocument, error) {
+	var documents []models.LicenseDocument
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("expires_at ASC").Find(&documents).Error
+	if err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// Update saves changes to a document record.
+func (r *LicenseRepository) Update(document *models.LicenseDocument) error {
+	return withRetry(func() error {
+		return r.db.Save(document).Error
+	})
+}
+
+// ExpiringBefore returns documents that expire before the cutoff and
+// have not been reminded about yet.
+func (r *LicenseRepository) ExpiringBefore(cutoff time.Time) ([]models.LicenseDocument, error) {
+	var documents []models.LicenseDocument
+	err := r.db.Where("expires_at < ? AND reminded_at IS NULL", cutoff).Find(&documents).Error
+	if err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
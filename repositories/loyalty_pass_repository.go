@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// LoyaltyPassRepository provides database access for loyalty passes.
+type LoyaltyPassRepository struct {
+	db *gorm.DB
+}
+
+// NewLoyaltyPassRepository builds a LoyaltyPassRepository.
+func NewLoyaltyPassRepository(db *gorm.DB) *LoyaltyPassRepository {
+	return &LoyaltyPassRepository{db: db}
+}
+
+// Create inserts a new pass.
+func (r *LoyaltyPassRepository) Create(pass *models.LoyaltyPass) error {
+	return withRetry(func() error {
+		return r.db.Create(pass).Error
+	})
+}
+
+// GetBySerial returns the pass with the given serial.
+func (r *LoyaltyPassRepository) GetBySerial(serial string) (*models.LoyaltyPass, error) {
+	var pass models.LoyaltyPass
+	if err := r.db.Where("serial = ?", serial).First(&pass).Error; err != nil {
+		return nil, getError("loyalty pass", err)
+	}
+	return &pass, nil
+}
+
+// GetByUserAndRestaurantAndPlatform returns one user's pass for a
+// restaurant on a platform.
+func (r *LoyaltyPassRepository) GetByUserAndRestaurantAndPlatform(userID, restaurantID uint, platform string) (*models.LoyaltyPass, error) {
+	var pass models.LoyaltyPass
+	err := r.db.Where("user_id = ? AND restaurant_id = ? AND platform = ?", userID, restaurantID, platform).First(&pass).Error
+	if err != nil {
+		return nil, getError("loyalty pass", err)
+	}
+	return &pass, nil
+}
+
+// GetByUserID returns all of a user's passes.
+func (r *LoyaltyPassRepository) GetByUserID(userID uint) ([]models.LoyaltyPass, error) {
+	var passes []models.LoyaltyPass
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&passes).Error
+	if err != nil {
+		return nil, err
+	}
+	return passes, nil
+}
+
+// GetByUserAndRestaurant returns a user's passes for one restaurant.
+func (r *LoyaltyPassRepository) GetByUserAndRestaurant(userID, restaurantID uint) ([]models.LoyaltyPass, error) {
+	var passes []models.LoyaltyPass
+	err := r.db.Where("user_id = ? AND restaurant_id = ?", userID, restaurantID).Order("id ASC").Find(&passes).Error
+	if err != nil {
+		return nil, err
+	}
+	return passes, nil
+}
+
+// Update persists changes to a pass.
+func (r *LoyaltyPassRepository) Update(pass *models.LoyaltyPass) error {
+	return withRetry(func() error {
+		return r.db.Save(pass).Error
+	})
+}
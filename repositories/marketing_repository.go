@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// MarketingRepository provides database access for marketing platform
+// accounts.
+type MarketingRepository struct {
+	db *gorm.DB
+}
+
+// NewMarketingRepository builds a MarketingRepository.
+func NewMarketingRepository(db *gorm.DB) *MarketingRepository {
+	return &MarketingRepository{db: db}
+}
+
+// GetAccount returns a restaurant's marketing account.
+func (r *MarketingRepository) GetAccount(restaurantID uint) (*models.MarketingAccount, error) {
+	var account models.MarketingAccount
+	if err := r.db.Where("restaurant_id = ?", restaurantID).First(&account).Error; err != nil {
+		return nil, getError("marketing account", err)
+	}
+	return &account, nil
+}
+
+// SaveAccount inserts or updates a marketing account.
+func (r *MarketingRepository) SaveAccount(account *models.MarketingAccount) error {
+	return withRetry(func() error {
+		return r.db.Save(account).Error
+	})
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// MenuRepository provides database access for menu items.
+type MenuRepository struct {
+	db *gorm.DB
+}
+
+// NewMenuRepository builds a MenuRepository.
+func NewMenuRepository(db *gorm.DB) *MenuRepository {
+	return &MenuRepository{db: db}
+}
+
+// Create inserts a new menu item.
+func (r *MenuRepository) Create(item *models.MenuItem) error {
+	return withRetry(func() error {
+		return r.db.Create(item).Error
+	})
+}
+
+// GetByID returns the menu item with the given id.
+func (r *MenuRepository) GetByID(id uint) (*models.MenuItem, error) {
+	var item models.MenuItem
+	if err := r.db.First(&item, id).Error; err != nil {
+		return nil, getError("menu item", err)
+	}
+	return &item, nil
+}
+
+// GetByRestaurantID returns all menu items of the given restaurant.
+func (r *MenuRepository) GetByRestaurantID(restaurantID uint) ([]models.MenuItem, error) {
+	var items []models.MenuItem
+	if err := r.db.Where("restaurant_id = ?", restaurantID).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetUpdatedSince returns the restaurant's menu items changed after the
+// given time, oldest change first. Delta sync pages through it.
+func (r *MenuRepository) GetUpdatedSince(restaurantID uint, since time.Time) ([]models.MenuItem, error) {
+	var items []models.MenuItem
+	err := r.db.Where("restaurant_id = ? AND updated_at > ?", restaurantID, since).
+		Order("updated_at ASC").Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update saves the given menu item.
+func (r *MenuRepository) Update(item *models.MenuItem) error {
+	return withRetry(func() error {
+		return r.db.Save(item).Error
+	})
+}
+
+// Delete soft-deletes the menu item with the given id.
+func (r *MenuRepository) Delete(id uint) error {
+	return withRetry(func() error {
+		return r.db.Delete(&models.MenuItem{}, id).Error
+	})
+}
+
+// CreatePriceChange records one re-pricing of a menu item.
+func (r *MenuRepository) CreatePriceChange(change *models.PriceChange) error {
+	return withRetry(func() error {
+		return r.db.Create(change).Error
+	})
+}
+
+// GetPriceHistory returns a menu item's price changes, newest first.
+func (r *MenuRepository) GetPriceHistory(menuItemID uint) ([]models.PriceChange, error) {
+	var changes []models.PriceChange
+	err := r.db.Where("menu_item_id = ?", menuItemID).
+		Order("created_at DESC").
+		Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// Search returns menu items whose name or description matches q.
+func (r *MenuRepository) Search(q string, limit int) ([]models.MenuItem, error) {
+	var items []models.MenuItem
+	like := "%" + q + "%"
+	err := r.db.Where("name LIKE ? OR description LIKE ?", like, like).
+		Limit(limit).Find(&items).Error
+	return items, err
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// OrderRepository provides database access for orders.
+type OrderRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderRepository builds an OrderRepository.
+func NewOrderRepository(db *gorm.DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+// Create inserts a new order with its items.
+func (r *OrderRepository) Create(order *models.Order) error {
+	return withRetry(func() error {
+		return r.db.Create(order).Error
+	})
+}
+
+// GetByID returns the order with the given id, items included.
+func (r *OrderRepository) GetByID(id uint) (*models.Order, error) {
+	var order models.Order
+	if err := r.db.Preload("Items").First(&order, id).Error; err != nil {
+		return nil, getError("order", err)
+	}
+	return &order, nil
+}
+
+// GetByPaymentRef returns the order with the given payment-provider
+// charge reference.
+func (r *OrderRepository) GetByPaymentRef(paymentRef string) (*models.Order, error) {
+	var order models.Order
+	if err := r.db.Where("payment_ref = ?", paymentRef).First(&order).Error; err != nil {
+		return nil, getError("order", err)
+	}
+	return &order, nil
+}
+
+// GetByRestaurantID returns all orders of the given restaurant.
+func (r *OrderRepository) GetByRestaurantID(restaurantID uint) ([]models.Order, error) {
+	var orders []models.Order
+	if err := r.db.Preload("Items").Where("restaurant_id = ?", restaurantID).Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetByCustomerID returns all orders placed by the given customer
+// account, newest first.
+func (r *OrderRepository) GetByCustomerID(customerID uint) ([]models.Order, error) {
+	var orders []models.Order
+	if err := r.db.Preload("Items").Where("customer_id = ?", customerID).Order("created_at DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetUpdatedSince returns the restaurant's orders changed after the
+// given time, oldest change first, items included.
+func (r *OrderRepository) GetUpdatedSince(restaurantID uint, since time.Time) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.db.Preload("Items").Where("restaurant_id = ? AND updated_at > ?", restaurantID, since).
+		Order("updated_at ASC").Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// CompletedTotalByCustomer sums what the customer has spent on
+// completed orders at the restaurant. Loyalty balances derive from it.
+func (r *OrderRepository) CompletedTotalByCustomer(restaurantID, customerID uint) (float64, error) {
+	var total float64
+	err := r.db.Model(&models.Order{}).
+		Where("restaurant_id = ? AND customer_id = ? AND status = ?", restaurantID, customerID, models.OrderCompleted).
+		Select("COALESCE(SUM(total), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// Update saves the given order.
+func (r *OrderRepository) Update(order *models.Order) error {
+	return withRetry(func() error {
+		return r.db.Save(order).Error
+	})
+}
+
+// CreateInTx inserts a new order with its items inside the caller's
+// transaction.
+func (r *OrderRepository) CreateInTx(tx *gorm.DB, order *models.Order) error {
+	return tx.Create(order).Error
+}
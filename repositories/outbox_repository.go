@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// OutboxRepository provides database access for outbox events.
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository builds an OutboxRepository.
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// CreateInTx inserts an event inside the caller's transaction, so the
+// event is only persisted if the surrounding state change commits.
+func (r *OutboxRepository) CreateInTx(tx *gorm.DB, event *models.OutboxEvent) error {
+	return tx.Create(event).Error
+}
+
+// GetUnpublished returns up to limit events that have not been published
+// and have not exhausted their delivery attempts.
+func (r *OutboxRepository) GetUnpublished(limit, maxAttempts int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.db.Where("published_at IS NULL AND attempts < ?", maxAttempts).
+		Order("id").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// MarkPublished stamps an event as delivered.
+func (r *OutboxRepository) MarkPublished(id uint) error {
+	now := time.Now()
+	return withRetry(func() error {
+		return r.db.Model(&models.OutboxEvent{}).Where("id = ?", id).
+			Update("published_at", &now).Error
+	})
+}
+
+// IncrementAttempts records a failed delivery attempt.
+func (r *OutboxRepository) IncrementAttempts(id uint) error {
+	return withRetry(func() error {
+		return r.db.Model(&models.OutboxEvent{}).Where("id = ?", id).
+			Update("attempts", gorm.Expr("attempts + 1")).Error
+	})
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// PrinterRepository provides database access for printers and their
+// print jobs.
+type PrinterRepository struct {
+	db *gorm.DB
+}
+
+// NewPrinterRepository builds a PrinterRepository.
+func NewPrinterRepository(db *gorm.DB) *PrinterRepository {
+	return &PrinterRepository{db: db}
+}
+
+// Create inserts a new printer.
+func (r *PrinterRepository) Create(printer *models.Printer) error {
+	return withRetry(func() error {
+		return r.db.Create(printer).Error
+	})
+}
+
+// GetByKey returns the printer holding the given agent key.
+func (r *PrinterRepository) GetByKey(key string) (*models.Printer, error) {
+	var printer models.Printer
+	if err := r.db.Where("key = ?", key).First(&printer).Error; err != nil {
+		return nil, getError("printer", err)
+	}
+	return &printer, nil
+}
+
+// GetByRestaurantID returns a restaurant's printers.
+func (r *PrinterRepository) GetByRestaurantID(restaurantID uint) ([]models.Printer, error) {
+	var printers []models.Printer
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("id ASC").Find(&printers).Error
+	if err != nil {
+		return nil, err
+	}
+	return printers, nil
+}
+
+// Update persists changes to a printer.
+func (r *PrinterRepository) Update(printer *models.Printer) error {
+	return withRetry(func() error {
+		return r.db.Save(printer).Error
+	})
+}
+
+// CreateJob inserts a new print job.
+func (r *PrinterRepository) CreateJob(job *models.PrintJob) error {
+	return withRetry(func() error {
+		return r.db.Create(job).Error
+	})
+}
+
+// GetJobByID returns the print job with the given id.
+func (r *PrinterRepository) GetJobByID(id uint) (*models.PrintJob, error) {
+	var job models.PrintJob
+	if err := r.db.First(&job, id).Error; err != nil {
+		return nil, getError("print job", err)
+	}
+	return &job, nil
+}
+
+// GetPendingJobs returns a printer's undelivered jobs, oldest first.
+func (r *PrinterRepository) GetPendingJobs(printerID uint) ([]models.PrintJob, error) {
+	var jobs []models.PrintJob
+	err := r.db.Where("printer_id = ? AND status = ?", printerID, models.PrintJobPending).
+		Order("id ASC").Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// GetJobsByRestaurantID returns a restaurant's print jobs, newest
+// first.
+func (r *PrinterRepository) GetJobsByRestaurantID(restaurantID uint) ([]models.PrintJob, error) {
+	var jobs []models.PrintJob
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("id DESC").Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// UpdateJob persists changes to a print job.
+func (r *PrinterRepository) UpdateJob(job *models.PrintJob) error {
+	return withRetry(func() error {
+		return r.db.Save(job).Error
+	})
+}
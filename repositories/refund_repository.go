@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// RefundRepository provides database access for refunds.
+type RefundRepository struct {
+	db *gorm.DB
+}
+
+// NewRefundRepository builds a RefundRepository.
+func NewRefundRepository(db *gorm.DB) *RefundRepository {
+	return &RefundRepository{db: db}
+}
+
+// Create inserts a new refund.
+func (r *RefundRepository) Create(refund *models.Refund) error {
+	return withRetry(func() error {
+		return r.db.Create(refund).Error
+	})
+}
+
+// CreateInTx inserts a new refund inside an existing transaction.
+func (r *RefundRepository) CreateInTx(tx *gorm.DB, refund *models.Refund) error {
+	return tx.Create(refund).Error
+}
+
+// GetByOrderID returns all refunds issued against the given order.
+func (r *RefundRepository) GetByOrderID(orderID uint) ([]models.Refund, error) {
+	var refunds []models.Refund
+	if err := r.db.Where("order_id = ?", orderID).Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// TotalByOrderID returns the amount already refunded against the given
+// order.
+func (r *RefundRepository) TotalByOrderID(orderID uint) (float64, error) {
+	var total float64
+	err := r.db.Model(&models.Refund{}).Where("order_id = ?", orderID).
+		Select("COALESCE(SUM(amount), 0)").Scan(&total).Error
+	return total, err
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// ReservationRepository provides database access for reservations.
+type ReservationRepository struct {
+	db *gorm.DB
+}
+
+// NewReservationRepository builds a ReservationRepository.
+func NewReservationRepository(db *gorm.DB) *ReservationRepository {
+	return &ReservationRepository{db: db}
+}
+
+// Create inserts a new reservation.
+func (r *ReservationRepository) Create(reservation *models.Reservation) error {
+	return withRetry(func() error {
+		return r.db.Create(reservation).Error
+	})
+}
+
+// GetByID returns the reservation with the given id.
+func (r *ReservationRepository) GetByID(id uint) (*models.Reservation, error) {
+	var reservation models.Reservation
+	if err := r.db.First(&reservation, id).Error; err != nil {
+		return nil, getError("reservation", err)
+	}
+	return &reservation, nil
+}
+
+// GetByRestaurantID returns all reservations of the given restaurant.
+func (r *ReservationRepository) GetByRestaurantID(restaurantID uint) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	if err := r.db.Where("restaurant_id = ?", restaurantID).Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// GetByRestaurantAndRange returns the restaurant's reservations whose
+// date falls inside [from, to).
+func (r *ReservationRepository) GetByRestaurantAndRange(restaurantID uint, from, to time.Time) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	if err := r.db.Where("restaurant_id = ? AND date >= ? AND date < ?", restaurantID, from, to).
+		Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// GetBySourceRef returns the restaurant's reservation imported from the
+// given platform under the given external reference.
+func (r *ReservationRepository) GetBySourceRef(restaurantID uint, source, ref string) (*models.Reservation, error) {
+	var reservation models.Reservation
+	err := r.db.Where("restaurant_id = ? AND source = ? AND external_ref = ?", restaurantID, source, ref).
+		First(&reservation).Error
+	if err != nil {
+		return nil, getError("reservation", err)
+	}
+	return &reservation, nil
+}
+
+// GetDirectCreatedSince returns the restaurant's direct bookings created
+// after the given time, oldest first, for mirroring onto platforms.
+func (r *ReservationRepository) GetDirectCreatedSince(restaurantID uint, since time.Time) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := r.db.Where("restaurant_id = ? AND source = ? AND created_at > ?", restaurantID, models.ReservationSourceDirect, since).
+		Order("created_at ASC").Find(&reservations).Error
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// GetByCustomerID returns all reservations made by the given customer
+// account, newest first.
+func (r *ReservationRepository) GetByCustomerID(customerID uint) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	if err := r.db.Where("customer_id = ?", customerID).Order("date DESC").Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// GetUpdatedSince returns the restaurant's reservations changed after
+// the given time, oldest change first.
+func (r *ReservationRepository) GetUpdatedSince(restaurantID uint, since time.Time) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := r.db.Where("restaurant_id = ? AND updated_at > ?", restaurantID, since).
+		Order("updated_at ASC").Find(&reservations).Error
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// Update saves the given reservation.
+func (r *ReservationRepository) Update(reservation *models.Reservation) error {
+	return withRetry(func() error {
+		return r.db.Save(reservation).Error
+	})
+}
+
+// Delete soft-deletes the reservation with the given id.
+func (r *ReservationRepository) Delete(id uint) error {
+	return withRetry(func() error {
+		return r.db.Delete(&models.Reservation{}, id).Error
+	})
+}
+
+// CountNoShowsByEmail returns how many times the given customer email
+// has been marked as a no-show, across all restaurants.
+func (r *ReservationRepository) CountNoShowsByEmail(customerEmail string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Reservation{}).
+		Where("customer_email = ? AND status = ?", customerEmail, models.ReservationNoShow).
+		Count(&count).Error
+	return count, err
+}
+
+// CountPendingByOwner returns how many pending reservations exist across
+// all restaurants owned by the given user.
+func (r *ReservationRepository) CountPendingByOwner(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Reservation{}).
+		Joins("JOIN restaurants ON restaurants.id = reservations.restaurant_id").
+		Where("restaurants.user_id = ? AND reservations.status = ?", userID, models.ReservationPending).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// RestaurantRepository provides database access for restaurants.
+type RestaurantRepository struct {
+	db *gorm.DB
+}
+
+// NewRestaurantRepository builds a RestaurantRepository.
+func NewRestaurantRepository(db *gorm.DB) *RestaurantRepository {
+	return &RestaurantRepository{db: db}
+}
+
+// Create inserts a new restaurant.
+func (r *RestaurantRepository) Create(restaurant *models.Restaurant) error {
+	return withRetry(func() error {
+		return r.db.Create(restaurant).Error
+	})
+}
+
+// CreateInTx inserts a new restaurant inside the caller's transaction.
+func (r *RestaurantRepository) CreateInTx(tx *gorm.DB, restaurant *models.Restaurant) error {
+	return tx.Create(restaurant).Error
+}
+
+// GetByID returns the restaurant with the given id.
+func (r *RestaurantRepository) GetByID(id uint) (*models.Restaurant, error) {
+	var restaurant models.Restaurant
+	if err := r.db.First(&restaurant, id).Error; err != nil {
+		return nil, getError("restaurant", err)
+	}
+	return &restaurant, nil
+}
+
+// GetByPublicID returns the restaurant with the given public UUID.
+func (r *RestaurantRepository) GetByPublicID(publicID string) (*models.Restaurant, error) {
+	var restaurant models.Restaurant
+	if err := r.db.Where("public_id = ?", publicID).First(&restaurant).Error; err != nil {
+		return nil, getError("restaurant", err)
+	}
+	return &restaurant, nil
+}
+
+// GetBySlug returns the restaurant with the given embed slug.
+func (r *RestaurantRepository) GetBySlug(slug string) (*models.Restaurant, error) {
+	var restaurant models.Restaurant
+	if err := r.db.Where("slug = ?", slug).First(&restaurant).Error; err != nil {
+		return nil, getError("restaurant", err)
+	}
+	return &restaurant, nil
+}
+
+// GetByUserID returns all restaurants owned by the given user.
+func (r *RestaurantRepository) GetByUserID(userID uint) ([]models.Restaurant, error) {
+	var restaurants []models.Restaurant
+	if err := r.db.Where("user_id = ?", userID).Find(&restaurants).Error; err != nil {
+		return nil, err
+	}
+	return restaurants, nil
+}
+
+// DeleteByUserIDInTx soft-deletes every restaurant owned by the given
+// user inside the caller's transaction.
+func (r *RestaurantRepository) DeleteByUserIDInTx(tx *gorm.DB, userID uint) error {
+	return tx.Where("user_id = ?", userID).Delete(&models.Restaurant{}).Error
+}
+
+// List returns every restaurant on the platform.
+func (r *RestaurantRepository) List() ([]models.Restaurant, error) {
+	var restaurants []models.Restaurant
+	if err := r.db.Find(&restaurants).Error; err != nil {
+		return nil, err
+	}
+	return restaurants, nil
+}
+
+// Update saves the given restaurant.
+func (r *RestaurantRepository) Update(restaurant *models.Restaurant) error {
+	return withRetry(func() error {
+		return r.db.Save(restaurant).Error
+	})
+}
+
+// Delete soft-deletes the restaurant with the given id.
+func (r *RestaurantRepository) Delete(id uint) error {
+	return withRetry(func() error {
+		return r.db.Delete(&models.Restaurant{}, id).Error
+	})
+}
+
+// CountByUserID returns how many restaurants the given user owns.
+func (r *RestaurantRepository) CountByUserID(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Restaurant{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// RestaurantFilter narrows and pages the admin restaurant listing.
+type RestaurantFilter struct {
+	Status  string
+	Owner   string // matches owner name or email
+	Page    int
+	PerPage int
+}
+
+// ListPaged returns a page of restaurants matching the filter plus the
+// total number of matches.
+func (r *RestaurantRepository) ListPaged(f RestaurantFilter) ([]models.Restaurant, int64, error) {
+	query := r.db.Model(&models.Restaurant{})
+	if f.Status != "" {
+		query = query.Where("restaurants.status = ?", f.Status)
+	}
+	if f.Owner != "" {
+		like := "%" + f.Owner + "%"
+		query = query.Joins("JOIN users ON users.id = restaurants.user_id").
+			Where("users.name LIKE ? OR users.email LIKE ?", like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.PerPage < 1 || f.PerPage > 100 {
+		f.PerPage = 20
+	}
+
+	var restaurants []models.Restaurant
+	err := query.Offset((f.Page - 1) * f.PerPage).Limit(f.PerPage).Find(&restaurants).Error
+	return restaurants, total, err
+}
+
+// Search returns restaurants whose name or description matches q.
+func (r *RestaurantRepository) Search(q string, limit int) ([]models.Restaurant, error) {
+	var restaurants []models.Restaurant
+	like := "%" + q + "%"
+	err := r.db.Where("name LIKE ? OR description LIKE ?", like, like).
+		Limit(limit).Find(&restaurants).Error
+	return restaurants, err
+}
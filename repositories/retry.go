@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"database/sql/driver"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Retry policy for transient database failures. Writes and transactions
+// are retried on MySQL deadlocks (1213), lock wait timeouts (1205) and
+// connection-level errors. Reads are deliberately not wrapped:
+// database/sql already re-runs a statement that landed on a stale pooled
+// connection, and deadlocks only bite operations that take locks.
+var (
+	retryMaxAttempts = 3
+	retryBaseDelay   = 50 * time.Millisecond
+
+	retryAttempts  atomic.Int64 // operations that were retried at least once
+	retryTotal     atomic.Int64 // individual retries performed
+	retryExhausted atomic.Int64 // operations that failed after all attempts
+)
+
+// ConfigureRetry sets the retry policy. Call it once at startup, before
+// any repository is used.
+func ConfigureRetry(maxAttempts int, baseDelay time.Duration) {
+	if maxAttempts >= 1 {
+		retryMaxAttempts = maxAttempts
+	}
+	if baseDelay > 0 {
+		retryBaseDelay = baseDelay
+	}
+}
+
+// RetryStats returns retry counters for the metrics endpoint.
+func RetryStats() map[string]int64 {
+	return map[string]int64{
+		"operations_retried": retryAttempts.Load(),
+		"retries_total":      retryTotal.Load(),
+		"retries_exhausted":  retryExhausted.Load(),
+	}
+}
+
+// withRetry runs op, retrying transient failures with jittered
+// exponential backoff. Non-transient errors are returned immediately.
+func withRetry(op func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = op()
+		if err == nil || !isTransientDBError(err) {
+			return err
+		}
+		if attempt >= retryMaxAttempts {
+			retryExhausted.Add(1)
+			return err
+		}
+		if attempt == 1 {
+			retryAttempts.Add(1)
+		}
+		retryTotal.Add(1)
+
+		// Full jitter in [delay, 2*delay), doubling per attempt, so
+		// competing transactions do not re-deadlock in lockstep.
+		delay := retryBaseDelay << (attempt - 1)
+		time.Sleep(delay + time.Duration(rand.Int63n(int64(delay))))
+	}
+}
+
+func isTransientDBError(err error) bool {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "Error 1213") || // deadlock found
+		strings.Contains(msg, "Error 1205") || // lock wait timeout
+		strings.Contains(msg, "deadlock") ||
+		strings.Contains(msg, "invalid connection") ||
+		strings.Contains(msg, "bad connection") ||
+		strings.Contains(msg, "connection refused") ||
+		strings.Contains(msg, "broken pipe")
+}
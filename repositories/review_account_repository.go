@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// ReviewAccountRepository provides database access for external review
+// platform accounts.
+type ReviewAccountRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewAccountRepository builds a ReviewAccountRepository.
+func NewReviewAccountRepository(db *gorm.DB) *ReviewAccountRepository {
+	return &ReviewAccountRepository{db: db}
+}
+
+// Create inserts a new account.
+func (r *ReviewAccountRepository) Create(account *models.ReviewAccount) error {
+	return withRetry(func() error {
+		return r.db.Create(account).Error
+	})
+}
+
+// GetByRestaurantAndPlatform returns the restaurant's account on the
+// given platform.
+func (r *ReviewAccountRepository) GetByRestaurantAndPlatform(restaurantID uint, platform string) (*models.ReviewAccount, error) {
+	var account models.ReviewAccount
+	err := r.db.Where("restaurant_id = ? AND platform = ?", restaurantID, platform).First(&account).Error
+	if err != nil {
+		return nil, getError("review account", err)
+	}
+	return &account, nil
+}
+
+// GetByRestaurantID returns a restaurant's connected platforms.
+func (r *ReviewAccountRepository) GetByRestaurantID(restaurantID uint) ([]models.ReviewAccount, error) {
+	var accounts []models.ReviewAccount
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("platform ASC").Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// List returns every connected account, for the periodic pull.
+func (r *ReviewAccountRepository) List() ([]models.ReviewAccount, error) {
+	var accounts []models.ReviewAccount
+	if err := r.db.Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// Update saves changes to an account.
+func (r *ReviewAccountRepository) Update(account *models.ReviewAccount) error {
+	return withRetry(func() error {
+		return r.db.Save(account).Error
+	})
+}
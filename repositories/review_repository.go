@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// ReviewRepository provides database access for reviews.
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository builds a ReviewRepository.
+func NewReviewRepository(db *gorm.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+// Create inserts a new review.
+func (r *ReviewRepository) Create(review *models.Review) error {
+	return withRetry(func() error {
+		return r.db.Create(review).Error
+	})
+}
+
+// GetBySourceRef returns the restaurant's review imported from the
+// given platform under the given external reference.
+func (r *ReviewRepository) GetBySourceRef(restaurantID uint, source, ref string) (*models.Review, error) {
+	var review models.Review
+	err := r.db.Where("restaurant_id = ? AND source = ? AND external_ref = ?", restaurantID, source, ref).
+		First(&review).Error
+	if err != nil {
+		return nil, getError("review", err)
+	}
+	return &review, nil
+}
+
+// GetByRestaurantID returns a restaurant's reviews, newest first.
+func (r *ReviewRepository) GetByRestaurantID(restaurantID uint) ([]models.Review, error) {
+	var reviews []models.Review
+	err := r.db.Where("restaurant_id = ?", restaurantID).
+		Order("posted_at DESC").Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// GetByRestaurantAndRange returns the restaurant's reviews posted
+// inside [from, to), oldest first.
+func (r *ReviewRepository) GetByRestaurantAndRange(restaurantID uint, from, to time.Time) ([]models.Review, error) {
+	var reviews []models.Review
+	err := r.db.Where("restaurant_id = ? AND posted_at >= ? AND posted_at < ?", restaurantID, from, to).
+		Order("posted_at ASC").Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// Update saves changes to a review.
+func (r *ReviewRepository) Update(review *models.Review) error {
+	return withRetry(func() error {
+		return r.db.Save(review).Error
+	})
+}
+
+// ReviewSourceStats is one source's slice of a restaurant's rating.
+type ReviewSourceStats struct {
+	Source  string  `json:"source"`
+	Count   int64   `json:"count"`
+	Average float64 `json:"average"`
+}
+
+// RatingsBySource aggregates a restaurant's reviews per source.
+func (r *ReviewRepository) RatingsBySource(restaurantID uint) ([]ReviewSourceStats, error) {
+	var stats []ReviewSourceStats
+	err := r.db.Model(&models.Review{}).
+		Select("source, COUNT(*) AS count, AVG(rating) AS average").
+		Where("restaurant_id = ?", restaurantID).
+		Group("source").Order("source ASC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// RoomRepository provides database access for private dining rooms and
+// their bookings.
+type RoomRepository struct {
+	db *gorm.DB
+}
+
+// NewRoomRepository builds a RoomRepository.
+func NewRoomRepository(db *gorm.DB) *RoomRepository {
+	return &RoomRepository{db: db}
+}
+
+// CreateRoom inserts a new private room.
+func (r *RoomRepository) CreateRoom(room *models.PrivateRoom) error {
+	return withRetry(func() error {
+		return r.db.Create(room).Error
+	})
+}
+
+// GetRoomByID returns the room with the given id.
+func (r *RoomRepository) GetRoomByID(id uint) (*models.PrivateRoom, error) {
+	var room models.PrivateRoom
+	if err := r.db.First(&room, id).Error; err != nil {
+		return nil, getError("private room", err)
+	}
+	return &room, nil
+}
+
+// GetRoomsByRestaurantID returns a restaurant's private rooms.
+func (r *RoomRepository) GetRoomsByRestaurantID(restaurantID uint) ([]models.PrivateRoom, error) {
+	var rooms []models.PrivateRoom
+	if err := r.db.Where("restaurant_id = ?", restaurantID).Find(&rooms).Error; err != nil {
+		return nil, err
+	}
+	return rooms, nil
+}
+
+// UpdateRoom saves changes to an existing room.
+func (r *RoomRepository) UpdateRoom(room *models.PrivateRoom) error {
+	return withRetry(func() error {
+		return r.db.Save(room).Error
+	})
+}
+
+// DeleteRoom soft-deletes the room with the given id.
+func (r *RoomRepository) DeleteRoom(id uint) error {
+	return withRetry(func() error {
+		return r.db.Delete(&models.PrivateRoom{}, id).Error
+	})
+}
+
+// CreateBooking inserts a new room booking.
+func (r *RoomRepository) CreateBooking(booking *models.RoomBooking) error {
+	return withRetry(func() error {
+		return r.db.Create(booking).Error
+	})
+}
+
+// GetBookingByID returns the booking with the given id.
+func (r *RoomRepository) GetBookingByID(id uint) (*models.RoomBooking, error) {
+	var booking models.RoomBooking
+	if err := r.db.First(&booking, id).Error; err != nil {
+		return nil, getError("room booking", err)
+	}
+	return &booking, nil
+}
+
+// GetBookingsByRoomAndRange returns the room's bookings overlapping
+// [from, to).
+func (r *RoomRepository) GetBookingsByRoomAndRange(roomID uint, from, to time.Time) ([]models.RoomBooking, error) {
+	var bookings []models.RoomBooking
+	err := r.db.Where("room_id = ? AND starts_at < ? AND ends_at > ?", roomID, to, from).
+		Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// GetBookingsByRestaurantID returns all room bookings of a restaurant,
+// soonest first.
+func (r *RoomRepository) GetBookingsByRestaurantID(restaurantID uint) ([]models.RoomBooking, error) {
+	var bookings []models.RoomBooking
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("starts_at ASC").Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// UpdateBooking saves changes to an existing booking.
+func (r *RoomRepository) UpdateBooking(booking *models.RoomBooking) error {
+	return withRetry(func() error {
+		return r.db.Save(booking).Error
+	})
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// RoyaltyRepository provides database access for royalty rules and the
+// sales figures royalty reports are computed from.
+type RoyaltyRepository struct {
+	db *gorm.DB
+}
+
+// NewRoyaltyRepository builds a RoyaltyRepository.
+func NewRoyaltyRepository(db *gorm.DB) *RoyaltyRepository {
+	return &RoyaltyRepository{db: db}
+}
+
+// GetRule returns the royalty rule of the given restaurant.
+func (r *RoyaltyRepository) GetRule(restaurantID uint) (*models.RoyaltyRule, error) {
+	var rule models.RoyaltyRule
+	if err := r.db.Where("restaurant_id = ?", restaurantID).First(&rule).Error; err != nil {
+		return nil, getError("royalty rule", err)
+	}
+	return &rule, nil
+}
+
+// SaveRule inserts or updates a restaurant's royalty rule.
+func (r *RoyaltyRepository) SaveRule(rule *models.RoyaltyRule) error {
+	return withRetry(func() error {
+		return r.db.Save(rule).Error
+	})
+}
+
+// NetSales returns a restaurant's sales net of refunds inside
+// [from, to): the total of non-cancelled orders minus the refunds
+// issued against them.
+func (r *RoyaltyRepository) NetSales(restaurantID uint, from, to time.Time) (float64, error) {
+	var gross float64
+	err := r.db.Model(&models.Order{}).
+		Select("COALESCE(SUM(total), 0)").
+		Where("restaurant_id = ? AND status <> ? AND created_at >= ? AND created_at < ?",
+			restaurantID, models.OrderCancelled, from, to).
+		Scan(&gross).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var refunded float64
+	err = r.db.Model(&models.Refund{}).
+		Select("COALESCE(SUM(refunds.amount), 0)").
+		Joins("JOIN orders ON orders.id = refunds.order_id").
+		Where("orders.restaurant_id = ? AND refunds.created_at >= ? AND refunds.created_at < ?",
+			restaurantID, from, to).
+		Scan(&refunded).Error
+	if err != nil {
+		return 0, err
+	}
+	return gross - refunded, nil
+}
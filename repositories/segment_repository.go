@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// SegmentRepository provides database access for marketing segments and
+// the order aggregates they are evaluated against.
+type SegmentRepository struct {
+	db *gorm.DB
+}
+
+// NewSegmentRepository builds a SegmentRepository.
+func NewSegmentRepository(db *gorm.DB) *SegmentRepository {
+	return &SegmentRepository{db: db}
+}
+
+// Create inserts a new segment.
+func (r *SegmentRepository) Create(segment *models.Segment) error {
+	return withRetry(func() error {
+		return r.db.Create(segment).Error
+	})
+}
+
+// GetByID returns the segment with the given id.
+func (r *SegmentRepository) GetByID(id uint) (*models.Segment, error) {
+	var segment models.Segment
+	if err := r.db.First(&segment, id).Error; err != nil {
+		return nil, getError("segment", err)
+	}
+	return &segment, nil
+}
+
+// GetByRestaurantID returns a restaurant's segments.
+func (r *SegmentRepository) GetByRestaurantID(restaurantID uint) ([]models.Segment, error) {
+	var segments []models.Segment
+	if err := r.db.Where("restaurant_id = ?", restaurantID).Find(&segments).Error; err != nil {
+		return nil, err
+	}
+	return segments, nil
+}
+
+// Delete soft-deletes the segment with the given id.
+func (r *SegmentRepository) Delete(id uint) error {
+	return withRetry(func() error {
+		return r.db.Delete(&models.Segment{}, id).Error
+	})
+}
+
+// CustomerStatsRow aggregates one customer's order history, keyed by
+// email since guests have no account. LastOrderAt is a "2006-01-02"
+// day, formatted in SQL so both dialects scan it the same way.
+type CustomerStatsRow struct {
+	CustomerEmail string
+	CustomerName  string
+	Orders        int64
+	Spend         float64
+	LastOrderAt   string
+}
+
+// CustomerStats returns per-customer order counts and spend for a
+// restaurant since the given time. Cancelled orders and orders placed
+// without an email (which no campaign could reach anyway) are left out.
+func (r *SegmentRepository) CustomerStats(restaurantID uint, since time.Time) ([]CustomerStatsRow, error) {
+	dayExpr := "strftime('%Y-%m-%d', MAX(created_at))"
+	if r.db.Dialector.Name() == "mysql" {
+		dayExpr = "DATE_FORMAT(MAX(created_at), '%Y-%m-%d')"
+	}
+	var rows []CustomerStatsRow
+	err := r.db.Model(&models.Order{}).
+		Select(fmt.Sprintf("customer_email, MAX(customer_name) AS customer_name, "+
+			"COUNT(*) AS orders, COALESCE(SUM(total), 0) AS spend, "+
+			"%s AS last_order_at", dayExpr)).
+		Where("restaurant_id = ? AND status <> ? AND customer_email <> '' AND created_at >= ?",
+			restaurantID, models.OrderCancelled, since).
+		Group("customer_email").
+		Order("spend DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// StaffRepository provides database access for employees and their
+// shifts.
+type StaffRepository struct {
+	db *gorm.DB
+}
+
+// NewStaffRepository builds a StaffRepository.
+func NewStaffRepository(db *gorm.DB) *StaffRepository {
+	return &StaffRepository{db: db}
+}
+
+// CreateEmployee inserts a new employee.
+func (r *StaffRepository) CreateEmployee(employee *models.Employee) error {
+	return withRetry(func() error {
+		return r.db.Create(employee).Error
+	})
+}
+
+// GetEmployeeByID returns the employee with the given id.
+func (r *StaffRepository) GetEmployeeByID(id uint) (*models.Employee, error) {
+	var employee models.Employee
+	if err := r.db.First(&employee, id).Error; err != nil {
+		return nil, getError("employee", err)
+	}
+	return &employee, nil
+}
+
+// UpdateEmployee saves changes to an employee.
+func (r *StaffRepository) UpdateEmployee(employee *models.Employee) error {
+	return withRetry(func() error {
+		return r.db.Save(employee).Error
+	})
+}
+
+// GetEmployeesByRestaurantID returns a restaurant's employees.
+func (r *StaffRepository) GetEmployeesByRestaurantID(restaurantID uint) ([]models.Employee, error) {
+	var employees []models.Employee
+	if err := r.db.Where("restaurant_id = ?", restaurantID).Find(&employees).Error; err != nil {
+		return nil, err
+	}
+	return employees, nil
+}
+
+// CreateShift inserts a new shift.
+func (r *StaffRepository) CreateShift(shift *models.Shift) error {
+	return withRetry(func() error {
+		return r.db.Create(shift).Error
+	})
+}
+
+// GetShiftByID returns the shift with the given id.
+func (r *StaffRepository) GetShiftByID(id uint) (*models.Shift, error) {
+	var shift models.Shift
+	if err := r.db.First(&shift, id).Error; err != nil {
+		return nil, getError("shift", err)
+	}
+	return &shift, nil
+}
+
+// GetShiftsByRange returns a restaurant's shifts overlapping
+// [from, to), earliest first.
+func (r *StaffRepository) GetShiftsByRange(restaurantID uint, from, to time.Time) ([]models.Shift, error) {
+	var shifts []models.Shift
+	err := r.db.Where("restaurant_id = ? AND starts_at < ? AND ends_at > ?", restaurantID, to, from).
+		Order("starts_at").
+		Find(&shifts).Error
+	if err != nil {
+		return nil, err
+	}
+	return shifts, nil
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// SwapRepository provides database access for shift swap requests and
+// the schedule change history.
+type SwapRepository struct {
+	db *gorm.DB
+}
+
+// NewSwapRepository builds a SwapRepository.
+func NewSwapRepository(db *gorm.DB) *SwapRepository {
+	return &SwapRepository{db: db}
+}
+
+// Create inserts a new swap request.
+func (r *SwapRepository) Create(swap *models.ShiftSwap) error {
+	return withRetry(func() error {
+		return r.db.Create(swap).Error
+	})
+}
+
+// GetByID returns the swap request with the given id.
+func (r *SwapRepository) GetByID(id uint) (*models.ShiftSwap, error) {
+	var swap models.ShiftSwap
+	if err := r.db.First(&swap, id).Error; err != nil {
+		return nil, getError("swap request", err)
+	}
+	return &swap, nil
+}
+
+// GetByRestaurantID returns a restaurant's swap requests, newest
+// first.
+func (r *SwapRepository) GetByRestaurantID(restaurantID uint) ([]models.ShiftSwap, error) {
+	var swaps []models.ShiftSwap
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("created_at DESC").Find(&swaps).Error
+	if err != nil {
+		return nil, err
+	}
+	return swaps, nil
+}
+
+// Update saves changes to a swap request.
+func (r *SwapRepository) Update(swap *models.ShiftSwap) error {
+	return withRetry(func() error {
+		return r.db.Save(swap).Error
+	})
+}
+
+// Apply commits an approved swap in one transaction: the request's new
+// status, the shift's reassignment (or removal for a drop) and the
+// history entry land together or not at all.
+func (r *SwapRepository) Apply(swap *models.ShiftSwap, shift *models.Shift, dropShift bool, change *models.ShiftChange) error {
+	return withRetry(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(swap).Error; err != nil {
+				return err
+			}
+			if dropShift {
+				if err := tx.Delete(shift).Error; err != nil {
+					return err
+				}
+			} else if err := tx.Save(shift).Error; err != nil {
+				return err
+			}
+			return tx.Create(change).Error
+		})
+	})
+}
+
+// GetChangesByRestaurantID returns a restaurant's schedule change
+// history, newest first.
+func (r *SwapRepository) GetChangesByRestaurantID(restaurantID uint) ([]models.ShiftChange, error) {
+	var changes []models.ShiftChange
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("created_at DESC").Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// TemperatureRepository provides database access for monitored units
+// and their temperature readings.
+type TemperatureRepository struct {
+	db *gorm.DB
+}
+
+// NewTemperatureRepository builds a TemperatureRepository.
+func NewTemperatureRepository(db *gorm.DB) *TemperatureRepository {
+	return &TemperatureRepository{db: db}
+}
+
+// CreateUnit inserts a new monitored unit.
+func (r *TemperatureRepository) CreateUnit(unit *models.TemperatureUnit) error {
+	return withRetry(func() error {
+		return r.db.Create(unit).Error
+	})
+}
+
+// GetUnitByID returns the unit with the given id.
+func (r *TemperatureRepository) GetUnitByID(id uint) (*models.TemperatureUnit, error) {
+	var unit models.TemperatureUnit
+	if err := r.db.First(&unit, id).Error; err != nil {
+		return nil, getError("temperature unit", err)
+	}
+	return &unit, nil
+}
+
+// GetUnitBySensorKey returns the unit a sensor pushes readings for.
+func (r *TemperatureRepository) GetUnitBySensorKey(key string) (*models.TemperatureUnit, error) {
+	var unit models.TemperatureUnit
+	if err := r.db.Where("sensor_key = ?", key).First(&unit).Error; err != nil {
+		return nil, getError("temperature unit", err)
+	}
+	return &unit, nil
+}
+
+// GetUnitsByRestaurantID returns a restaurant's units, by name.
+func (r *TemperatureRepository) GetUnitsByRestaurantID(restaurantID uint) ([]models.TemperatureUnit, error) {
+	var units []models.TemperatureUnit
+	err := r.db.Where("restaurant_id = ?", restaurantID).Order("name ASC").Find(&units).Error
+	if err != nil {
+		return nil, err
+	}
+	return units, nil
+}
+
+// UpdateUnit saves changes to a unit.
+func (r *TemperatureRepository) UpdateUnit(unit *models.TemperatureUnit) error {
+	return withRetry(func() error {
+		return r.db.Save(unit).Error
+	})
+}
+
+// CreateReading inserts a new temperature reading.
+func (r *TemperatureRepository) CreateReading(reading *models.TemperatureReading) error {
+	return withRetry(func() error {
+		return r.db.Create(reading).Error
+	})
+}
+
+// GetReadingsByUnitID returns a unit's readings in the range, oldest
+// first — the order an inspector reads the log.
+func (r *TemperatureRepository) GetReadingsByUnitID(unitID uint, from, to time.Time) ([]models.TemperatureReading, error) {
+	var readings []models.TemperatureReading
+	err := r.db.Where("temperature_unit_id = ? AND created_at >= ? AND created_at < ?", unitID, from, to).
+		Order("created_at ASC").Find(&readings).Error
+	if err != nil {
+		return nil, err
+	}
+	return readings, nil
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// TimeclockRepository provides database access for time punches.
+type TimeclockRepository struct {
+	db *gorm.DB
+}
+
+// NewTimeclockRepository builds a TimeclockRepository.
+func NewTimeclockRepository(db *gorm.DB) *TimeclockRepository {
+	return &TimeclockRepository{db: db}
+}
+
+// Create inserts a new punch.
+func (r *TimeclockRepository) Create(punch *models.TimePunch) error {
+	return withRetry(func() error {
+		return r.db.Create(punch).Error
+	})
+}
+
+// Update saves changes to a punch.
+func (r *TimeclockRepository) Update(punch *models.TimePunch) error {
+	return withRetry(func() error {
+		return r.db.Save(punch).Error
+	})
+}
+
+// GetOpenPunch returns the employee's punch without a clock-out, if
+// any.
+func (r *TimeclockRepository) GetOpenPunch(employeeID uint) (*models.TimePunch, error) {
+	var punch models.TimePunch
+	err := r.db.Where("employee_id = ? AND clock_out IS NULL", employeeID).First(&punch).Error
+	if err != nil {
+		return nil, getError("time punch", err)
+	}
+	return &punch, nil
+}
+
+// GetByRestaurantID returns a restaurant's punches clocking in during
+// the range, earliest first.
+func (r *TimeclockRepository) GetByRestaurantID(restaurantID uint, from, to time.Time) ([]models.TimePunch, error) {
+	var punches []models.TimePunch
+	err := r.db.Where("restaurant_id = ? AND clock_in >= ? AND clock_in < ?", restaurantID, from, to).
+		Order("clock_in ASC").Find(&punches).Error
+	if err != nil {
+		return nil, err
+	}
+	return punches, nil
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// TrainingRepository provides database access for employee training
+// records.
+type TrainingRepository struct {
+	db *gorm.DB
+}
+
+// NewTrainingRepository builds a TrainingRepository.
+func NewTrainingRepository(db *gorm.DB) *TrainingRepository {
+	return &TrainingRepository{db: db}
+}
+
+// Create inserts a new training record.
+func (r *TrainingRepository) Create(record *models.TrainingRecord) error {
+	return withRetry(func() error {
+		return r.db.Create(record).Error
+	})
+}
+
+// GetByEmployeeID returns an employee's training records, soonest to
+// expire first.
+func (r *TrainingRepository) GetByEmployeeID(employeeID uint) ([]models.TrainingRecord, error) {
+	var records []models.TrainingRecord
+	err := r.db.Where("employee_id = ?", employeeID).Order("expires_at ASC").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Update saves changes to a training record.
+func (r *TrainingRepository) Update(record *models.TrainingRecord) error {
+	return withRetry(func() error {
+		return r.db.Save(record).Error
+	})
+}
+
+// ExpiringBefore returns records expiring before the cutoff whose
+// reminder has not gone out yet.
+func (r *TrainingRepository) ExpiringBefore(cutoff time.Time) ([]models.TrainingRecord, error) {
+	var records []models.TrainingRecord
+	err := r.db.Where("expires_at < ? AND reminded_at IS NULL", cutoff).Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import "gorm.io/gorm"
+
+// TxManager runs multiple repository operations in a single database
+// transaction. Returning an error from fn rolls everything back.
+type TxManager struct {
+	db *gorm.DB
+}
+
+// NewTxManager builds a TxManager.
+func NewTxManager(db *gorm.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// Transaction executes fn inside a transaction. Repositories participate
+// by being rebound with WithTx on the *gorm.DB passed to fn. A deadlocked
+// transaction has been rolled back in full, so it is safe to re-run fn.
+func (m *TxManager) Transaction(fn func(tx *gorm.DB) error) error {
+	return withRetry(func() error {
+		return m.db.Transaction(fn)
+	})
+}
+
+// WithTx returns a copy of the repository bound to the given transaction.
+func (r *UserRepository) WithTx(tx *gorm.DB) *UserRepository {
+	return &UserRepository{db: tx}
+}
+
+// WithTx returns a copy of the repository bound to the given transaction.
+func (r *RestaurantRepository) WithTx(tx *gorm.DB) *RestaurantRepository {
+	return &RestaurantRepository{db: tx}
+}
+
+// WithTx returns a copy of the repository bound to the given transaction.
+func (r *MenuRepository) WithTx(tx *gorm.DB) *MenuRepository {
+	return &MenuRepository{db: tx}
+}
+
+// WithTx returns a copy of the repository bound to the given transaction.
+func (r *ReservationRepository) WithTx(tx *gorm.DB) *ReservationRepository {
+	return &ReservationRepository{db: tx}
+}
+
+// WithTx returns a copy of the repository bound to the given transaction.
+func (r *OrderRepository) WithTx(tx *gorm.DB) *OrderRepository {
+	return &OrderRepository{db: tx}
+}
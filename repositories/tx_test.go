@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+func TestTransactionRollsBackOnError(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	restaurantRepo := NewRestaurantRepository(db)
+	manager := NewTxManager(db)
+
+	failed := errors.New("boom")
+	err = manager.Transaction(func(tx *gorm.DB) error {
+		if err := restaurantRepo.WithTx(tx).Create(&models.Restaurant{Name: "Ghost", UserID: 1}); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("expected transaction error, got %v", err)
+	}
+
+	var count int64
+	if err := db.Model(&models.Restaurant{}).Count(&count).Error; err != nil {
+		t.Fatalf("counting: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected rollback, found %d restaurants", count)
+	}
+}
+
+func TestTransactionCommits(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	restaurantRepo := NewRestaurantRepository(db)
+	menuRepo := NewMenuRepository(db)
+	manager := NewTxManager(db)
+
+	err = manager.Transaction(func(tx *gorm.DB) error {
+		restaurant := &models.Restaurant{Name: "Real", UserID: 1}
+		if err := restaurantRepo.WithTx(tx).Create(restaurant); err != nil {
+			return err
+		}
+		return menuRepo.WithTx(tx).Create(&models.MenuItem{RestaurantID: restaurant.ID, Name: "Cazuela", Price: 6500})
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+
+	var count int64
+	if err := db.Model(&models.MenuItem{}).Count(&count).Error; err != nil {
+		t.Fatalf("counting: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected committed menu item, found %d", count)
+	}
+}
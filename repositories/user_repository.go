@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// UserRepository provides database access for users.
+type UserRepository struct {
+	db *gorm.DB
+}
+
+// NewUserRepository builds a UserRepository.
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Create inserts a new user.
+func (r *UserRepository) Create(user *models.User) error {
+	return withRetry(func() error {
+		return r.db.Create(user).Error
+	})
+}
+
+// CreateInTx inserts a new user inside the caller's transaction.
+func (r *UserRepository) CreateInTx(tx *gorm.DB, user *models.User) error {
+	return tx.Create(user).Error
+}
+
+// GetByID returns the user with the given id.
+func (r *UserRepository) GetByID(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, getError("user", err)
+	}
+	return &user, nil
+}
+
+// GetByPublicID returns the user with the given public UUID.
+func (r *UserRepository) GetByPublicID(publicID string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("public_id = ?", publicID).First(&user).Error; err != nil {
+		return nil, getError("user", err)
+	}
+	return &user, nil
+}
+
+// GetByEmail returns the user with the given email.
+func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, getError("user", err)
+	}
+	return &user, nil
+}
+
+// Update saves the given user.
+func (r *UserRepository) Update(user *models.User) error {
+	return withRetry(func() error {
+		return r.db.Save(user).Error
+	})
+}
+
+// Delete soft-deletes the user with the given id.
+func (r *UserRepository) Delete(id uint) error {
+	return withRetry(func() error {
+		return r.db.Delete(&models.User{}, id).Error
+	})
+}
+
+// DeleteInTx soft-deletes the user inside the caller's transaction.
+func (r *UserRepository) DeleteInTx(tx *gorm.DB, id uint) error {
+	return tx.Delete(&models.User{}, id).Error
+}
+
+// CountAdmins returns how many active accounts hold the admin role.
+func (r *UserRepository) CountAdmins() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("role = ?", "admin").Count(&count).Error
+	return count, err
+}
+
+// Search returns users whose name or email matches q.
+func (r *UserRepository) Search(q string, limit int) ([]models.User, error) {
+	var users []models.User
+	like := "%" + q + "%"
+	err := r.db.Where("name LIKE ? OR email LIKE ?", like, like).
+		Limit(limit).Find(&users).Error
+	return users, err
+}
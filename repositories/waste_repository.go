@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// WasteRepository provides database access for waste log entries.
+type WasteRepository struct {
+	db *gorm.DB
+}
+
+// NewWasteRepository builds a WasteRepository.
+func NewWasteRepository(db *gorm.DB) *WasteRepository {
+	return &WasteRepository{db: db}
+}
+
+// Create inserts a new waste entry.
+func (r *WasteRepository) Create(entry *models.WasteEntry) error {
+	return withRetry(func() error {
+		return r.db.Create(entry).Error
+	})
+}
+
+// GetByRestaurantID returns a restaurant's waste entries in the range,
+// newest first.
+func (r *WasteRepository) GetByRestaurantID(restaurantID uint, from, to time.Time) ([]models.WasteEntry, error) {
+	var entries []models.WasteEntry
+	err := r.db.Where("restaurant_id = ? AND created_at >= ? AND created_at < ?", restaurantID, from, to).
+		Order("created_at DESC").Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// WasteDay is one day's total waste cost.
+type WasteDay struct {
+	Day  string  `json:"day"`
+	Cost float64 `json:"cost"`
+}
+
+// CostByDay returns the restaurant's waste cost per day in the range.
+// Days without entries are absent.
+func (r *WasteRepository) CostByDay(restaurantID uint, from, to time.Time) ([]WasteDay, error) {
+	var days []WasteDay
+	err := r.db.Model(&models.WasteEntry{}).
+		Select("DATE(created_at) AS day, COALESCE(SUM(cost), 0) AS cost").
+		Where("restaurant_id = ? AND created_at >= ? AND created_at < ?", restaurantID, from, to).
+		Group("DATE(created_at)").Order("day ASC").Scan(&days).Error
+	if err != nil {
+		return nil, err
+	}
+	return days, nil
+}
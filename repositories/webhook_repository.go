@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// WebhookRepository provides database access for webhook deliveries.
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository builds a WebhookRepository.
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// Create inserts a new webhook delivery.
+func (r *WebhookRepository) Create(delivery *models.WebhookDelivery) error {
+	return withRetry(func() error {
+		return r.db.Create(delivery).Error
+	})
+}
+
+// GetByID returns the webhook delivery with the given id.
+func (r *WebhookRepository) GetByID(id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := r.db.First(&delivery, id).Error; err != nil {
+		return nil, getError("webhook delivery", err)
+	}
+	return &delivery, nil
+}
+
+// GetByRestaurantID returns the restaurant's webhook deliveries, newest
+// first.
+func (r *WebhookRepository) GetByRestaurantID(restaurantID uint) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.Where("restaurant_id = ?", restaurantID).
+		Order("id DESC").Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Update saves the given webhook delivery.
+func (r *WebhookRepository) Update(delivery *models.WebhookDelivery) error {
+	return withRetry(func() error {
+		return r.db.Save(delivery).Error
+	})
+}
@@ -0,0 +1,48 @@
+package reviews
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/config"
+)
+
+// ExternalReview is one review as an external platform describes it.
+type ExternalReview struct {
+	Ref      string
+	Author   string
+	Rating   int
+	Comment  string
+	PostedAt time.Time
+}
+
+// Provider pulls reviews from an external platform. Credentials are per
+// restaurant and travel with each call; the platform name picks the
+// wire format inside multi-platform drivers.
+type Provider interface {
+	// FetchReviews returns the listing's reviews posted or edited
+	// since the given time.
+	FetchReviews(platform, apiKey, externalID string, since time.Time) ([]ExternalReview, error)
+}
+
+// LogProvider logs pulls instead of executing them; it is the dev
+// default.
+type LogProvider struct{}
+
+// FetchReviews implements Provider.
+func (LogProvider) FetchReviews(platform, apiKey, externalID string, since time.Time) ([]ExternalReview, error) {
+	log.Printf("reviews (dev mode) fetch platform=%s listing=%s since=%s", platform, externalID, since.Format(time.RFC3339))
+	return nil, nil
+}
+
+// NewFromConfig builds the configured driver. "log" is the dev default:
+// pulls are printed instead of executed.
+func NewFromConfig(cfg *config.Config) (Provider, error) {
+	switch cfg.ReviewsDriver {
+	case "log", "":
+		return LogProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported reviews driver %q", cfg.ReviewsDriver)
+	}
+}
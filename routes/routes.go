@@ -0,0 +1,353 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/handlers"
+	"github.com/marc0cl/wets-ma-bu-api/middleware"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// Handlers bundles every handler the router needs.
+type Handlers struct {
+	Auth        *handlers.AuthHandler
+	User        *handlers.UserHandler
+	Restaurant  *handlers.RestaurantHandler
+	Menu        *handlers.MenuHandler
+	Reservation *handlers.ReservationHandler
+	Order       *handlers.OrderHandler
+	Summary     *handlers.SummaryHandler
+	Admin       *handlers.AdminHandler
+	Search      *handlers.SearchHandler
+	Metrics     *handlers.MetricsHandler
+	Device      *handlers.DeviceHandler
+	Image       *handlers.ImageHandler
+	Customer    *handlers.CustomerHandler
+	Dispute     *handlers.DisputeHandler
+	Analytics   *handlers.AnalyticsHandler
+	Accounting  *handlers.AccountingHandler
+	Staff       *handlers.StaffHandler
+	Experiment  *handlers.ExperimentHandler
+	Segment     *handlers.SegmentHandler
+	Marketing   *handlers.MarketingHandler
+	Consent     *handlers.ConsentHandler
+	Birthday    *handlers.BirthdayHandler
+	Event       *handlers.EventHandler
+	Catering    *handlers.CateringHandler
+	Room        *handlers.RoomHandler
+	Royalty     *handlers.RoyaltyHandler
+	Health      *handlers.HealthHandler
+	License     *handlers.LicenseHandler
+	Compliance  *handlers.ComplianceHandler
+	Invoice     *handlers.InvoiceHandler
+	Recipe      *handlers.RecipeHandler
+	Waste       *handlers.WasteHandler
+	Temperature *handlers.TemperatureHandler
+	Training    *handlers.TrainingHandler
+	Swap        *handlers.SwapHandler
+	Timeclock   *handlers.TimeclockHandler
+	Approval    *handlers.ApprovalHandler
+	Brand       *handlers.BrandHandler
+	APIKey      *handlers.APIKeyHandler
+	Embed       *handlers.EmbedHandler
+	BookingSync *handlers.BookingSyncHandler
+	Review      *handlers.ReviewHandler
+	FAQ         *handlers.FAQHandler
+	Loyalty     *handlers.LoyaltyHandler
+	Email       *handlers.EmailHandler
+	Printer     *handlers.PrinterHandler
+	Sync        *handlers.SyncHandler
+	Hardware    *handlers.HardwareHandler
+	Webhook     *handlers.WebhookHandler
+}
+
+// Setup registers every route of the API.
+func Setup(e *echo.Echo, h *Handlers, jwtSecret string) {
+	// Public routes.
+	e.GET("/metrics", h.Metrics.Metrics)
+	e.GET("/readyz", h.Metrics.Ready)
+	e.POST("/auth/register", h.Auth.Register)
+	e.POST("/auth/register/customer", h.Auth.RegisterCustomer)
+	e.POST("/auth/login", h.Auth.Login)
+	e.GET("/restaurants/:id/menu", h.Menu.GetMenu)
+	e.GET("/restaurants/:id/availability", h.Reservation.Availability)
+	e.GET("/restaurants/:id/image", h.Image.GetRestaurantImage)
+	e.GET("/menu-items/:id/image", h.Image.GetMenuItemImage)
+	// Booking and ordering stay public for guests; a logged-in customer's
+	// token, when present, ties what they create to their history.
+	e.POST("/restaurants/:id/reservations", h.Reservation.CreateReservation, middleware.OptionalJWT(jwtSecret))
+	e.POST("/restaurants/:id/orders", h.Order.CreateOrder, middleware.OptionalJWT(jwtSecret))
+	e.GET("/restaurants/:id/rooms", h.Room.GetRooms)
+	e.POST("/rooms/:id/bookings", h.Room.BookRoom, middleware.OptionalJWT(jwtSecret))
+	e.GET("/restaurants/:id/events", h.Event.GetEvents)
+	e.POST("/restaurants/:id/catering-requests", h.Catering.CreateInquiry)
+	// Only restaurants that opted in expose a badge.
+	e.GET("/restaurants/:id/health-badge", h.Health.GetBadge)
+	// Shared tablets punch the clock with an employee PIN, not a JWT.
+	e.POST("/restaurants/:id/punch", h.Timeclock.Punch)
+	// Unsubscribe links from campaigns must work without a login.
+	e.POST("/marketing/opt-out", h.Consent.OptOut)
+	// Provider webhooks authenticate with a signature, not a JWT.
+	e.POST("/webhooks/payments/disputes", h.Dispute.Webhook)
+	// Temperature sensors authenticate with their per-unit key.
+	e.POST("/webhooks/sensors/temperature", h.Temperature.SensorWebhook)
+	// Booking platforms authenticate with their account's webhook key,
+	// not a JWT.
+	e.POST("/webhooks/bookings", h.BookingSync.Webhook)
+	// Embedded widgets authenticate with a publishable key, not a JWT.
+	e.GET("/widget/menu", h.APIKey.WidgetMenu)
+	e.GET("/widget/profile", h.APIKey.WidgetProfile)
+	e.GET("/widget/availability", h.APIKey.WidgetAvailability)
+	// Reviews and ratings are public; diners post without an account.
+	e.POST("/restaurants/:id/reviews", h.Review.AddReview)
+	e.GET("/restaurants/:id/reviews", h.Review.GetReviews)
+	e.GET("/restaurants/:id/ratings", h.Review.GetRatings)
+	// Printer agents authenticate with their printer's key, not a JWT.
+	e.GET("/agent/print-jobs", h.Printer.PullJobs)
+	e.POST("/agent/print-jobs/:id/ack", h.Printer.AckJob)
+	// Hardware devices authenticate with their device token, not a JWT.
+	e.POST("/devices/heartbeat", h.Hardware.Heartbeat)
+	// Wallet platforms authenticate with the pass's token, not a JWT.
+	e.GET("/wallet/passes/:serial", h.Loyalty.GetPass)
+	// Chat widgets ask on behalf of anonymous visitors.
+	e.POST("/restaurants/:id/ask", h.FAQ.Ask)
+	// The embed feed is keyless: it only carries the public menu.
+	e.GET("/embed/:slug/menu.json", h.Embed.MenuJSON)
+	e.GET("/embed/:slug/menu.html", h.Embed.MenuHTML)
+	e.GET("/embed/:slug/oembed", h.Embed.OEmbed)
+	e.GET("/embed/:slug/restaurant.jsonld", h.Embed.JSONLD)
+
+	// Authenticated routes.
+	auth := e.Group("", middleware.JWT(jwtSecret))
+
+	auth.GET("/users/:id", h.User.GetUser)
+	auth.PUT("/users/:id", h.User.UpdateUser)
+	auth.DELETE("/users/:id", h.User.DeleteUser)
+	auth.GET("/users/:id/restaurants", h.Restaurant.GetMyRestaurants)
+	auth.GET("/users/:id/restaurants/count", h.Restaurant.CountMyRestaurants)
+	auth.GET("/me/summary", h.Summary.Summary)
+	auth.GET("/search", h.Search.Search)
+	auth.POST("/me/devices", h.Device.RegisterDevice)
+	auth.DELETE("/me/devices/:token", h.Device.UnregisterDevice)
+	// Buying a ticket needs an account with a saved card, but not a
+	// specific role.
+	auth.POST("/events/:id/tickets", h.Event.PurchaseTicket)
+
+	// Restaurant management is for owners (and admins); customers never
+	// reach these even with a valid token.
+	owner := e.Group("", middleware.JWT(jwtSecret), middleware.RequireRole(models.RoleOwner, models.RoleAdmin))
+
+	owner.POST("/restaurants", h.Restaurant.CreateRestaurant)
+	owner.GET("/restaurants/:id", h.Restaurant.GetRestaurant)
+	owner.PUT("/restaurants/:id", h.Restaurant.UpdateRestaurant)
+	owner.PUT("/restaurants/:id/booking-rules", h.Restaurant.UpdateBookingRules)
+	owner.DELETE("/restaurants/:id", h.Restaurant.DeleteRestaurant)
+
+	owner.POST("/restaurants/:id/image", h.Image.UploadRestaurantImage)
+	owner.POST("/menu-items/:id/image", h.Image.UploadMenuItemImage)
+
+	owner.POST("/restaurants/:id/menu", h.Menu.CreateMenuItem)
+	owner.PUT("/menu-items/:id", h.Menu.UpdateMenuItem)
+	owner.GET("/menu-items/:id/price-history", h.Menu.GetPriceHistory)
+	owner.DELETE("/menu-items/:id", h.Menu.DeleteMenuItem)
+
+	owner.GET("/restaurants/:id/reservations", h.Reservation.GetReservations)
+	owner.PUT("/reservations/:id/status", h.Reservation.UpdateReservationStatus)
+	owner.GET("/customers/no-shows", h.Reservation.NoShowCount)
+
+	owner.GET("/restaurants/:id/orders", h.Order.GetOrders)
+	owner.PUT("/orders/:id/status", h.Order.UpdateOrderStatus)
+	owner.GET("/orders/:id/ticket", h.Order.GetTicket)
+	owner.POST("/orders/:id/refunds", h.Order.RefundOrder)
+	owner.GET("/orders/:id/refunds", h.Order.GetRefunds)
+
+	owner.GET("/restaurants/:id/disputes", h.Dispute.GetDisputes)
+	owner.GET("/restaurants/:id/revenue", h.Analytics.Revenue)
+	owner.GET("/restaurants/:id/busy-hours", h.Analytics.BusyHours)
+	owner.GET("/restaurants/:id/menu-engineering", h.Analytics.MenuEngineering)
+	owner.GET("/restaurants/:id/turn-times", h.Analytics.TurnTimes)
+
+	owner.GET("/restaurants/:id/account-mappings", h.Accounting.GetMappings)
+	owner.PUT("/restaurants/:id/account-mappings", h.Accounting.UpdateMappings)
+	owner.GET("/restaurants/:id/accounting-export", h.Accounting.Export)
+
+	owner.POST("/restaurants/:id/employees", h.Staff.CreateEmployee)
+	owner.GET("/restaurants/:id/employees", h.Staff.GetEmployees)
+	owner.POST("/restaurants/:id/shifts", h.Staff.CreateShift)
+	owner.GET("/restaurants/:id/shifts", h.Staff.GetShifts)
+	owner.GET("/restaurants/:id/payroll-export", h.Staff.PayrollExport)
+	owner.PUT("/disputes/:id/evidence", h.Dispute.SubmitEvidence)
+
+	owner.POST("/restaurants/:id/experiments", h.Experiment.CreateExperiment)
+	owner.GET("/restaurants/:id/experiments", h.Experiment.GetExperiments)
+	owner.PUT("/experiments/:id/status", h.Experiment.SetStatus)
+	owner.GET("/experiments/:id/results", h.Experiment.GetResults)
+
+	owner.POST("/restaurants/:id/segments", h.Segment.CreateSegment)
+	owner.GET("/restaurants/:id/segments", h.Segment.GetSegments)
+	owner.GET("/segments/:id/members", h.Segment.GetMembers)
+	owner.GET("/segments/:id/export", h.Segment.ExportMembers)
+	owner.DELETE("/segments/:id", h.Segment.DeleteSegment)
+
+	owner.PUT("/restaurants/:id/marketing-account", h.Marketing.Configure)
+	owner.GET("/restaurants/:id/marketing-account", h.Marketing.GetStatus)
+	owner.POST("/restaurants/:id/marketing-sync", h.Marketing.Sync)
+
+	owner.GET("/restaurants/:id/birthday-template", h.Birthday.GetTemplate)
+	owner.PUT("/restaurants/:id/birthday-template", h.Birthday.UpdateTemplate)
+
+	owner.POST("/restaurants/:id/events", h.Event.CreateEvent)
+	owner.GET("/events/:id/tickets", h.Event.GetTickets)
+	owner.POST("/events/:id/check-in", h.Event.CheckIn)
+
+	owner.GET("/restaurants/:id/catering-requests", h.Catering.GetRequests)
+	owner.PUT("/catering-requests/:id/quote", h.Catering.Quote)
+	owner.PUT("/catering-requests/:id/status", h.Catering.SetStatus)
+
+	owner.PUT("/restaurants/:id/royalty-rule", h.Royalty.SetRule)
+	owner.GET("/restaurants/:id/royalty-rule", h.Royalty.GetRule)
+	owner.GET("/royalty-report", h.Royalty.Report)
+	owner.GET("/royalty-report/export", h.Royalty.ExportReport)
+
+	owner.POST("/restaurants/:id/inspections", h.Health.CreateInspection)
+	owner.GET("/restaurants/:id/inspections", h.Health.GetInspections)
+	owner.GET("/inspections/:id/report", h.Health.GetReport)
+	owner.PUT("/restaurants/:id/health-badge", h.Health.SetBadge)
+
+	owner.POST("/restaurants/:id/licenses", h.License.UploadDocument)
+	owner.GET("/restaurants/:id/licenses", h.License.GetDocuments)
+	owner.GET("/licenses/:id/file", h.License.GetFile)
+
+	owner.POST("/restaurants/:id/compliance-items", h.Compliance.CreateItem)
+	owner.GET("/restaurants/:id/compliance-items", h.Compliance.GetItems)
+	owner.GET("/compliance-items/:id", h.Compliance.GetItem)
+	owner.POST("/compliance-items/:id/completions", h.Compliance.Complete)
+
+	owner.POST("/restaurants/:id/supplier-invoices", h.Invoice.Upload)
+	owner.GET("/restaurants/:id/supplier-invoices", h.Invoice.GetInvoices)
+	owner.GET("/supplier-invoices/:id", h.Invoice.GetInvoice)
+	owner.GET("/supplier-invoices/:id/file", h.Invoice.GetFile)
+	owner.PUT("/supplier-invoices/:id", h.Invoice.Review)
+	owner.POST("/supplier-invoices/:id/confirm", h.Invoice.Confirm)
+
+	owner.POST("/restaurants/:id/ingredients", h.Recipe.CreateIngredient)
+	owner.GET("/restaurants/:id/ingredients", h.Recipe.GetIngredients)
+	owner.PUT("/ingredients/:id", h.Recipe.UpdateIngredient)
+	owner.POST("/ingredients/:id/stock-adjustments", h.Recipe.AdjustStock)
+	owner.GET("/ingredients/:id/stock-movements", h.Recipe.GetStockMovements)
+	owner.PUT("/menu-items/:id/recipe", h.Recipe.SetRecipe)
+	owner.GET("/menu-items/:id/recipe", h.Recipe.GetRecipe)
+	owner.GET("/restaurants/:id/food-costs", h.Recipe.GetFoodCosts)
+	owner.POST("/restaurants/:id/waste-entries", h.Waste.LogWaste)
+	owner.GET("/restaurants/:id/waste-entries", h.Waste.GetEntries)
+	owner.GET("/restaurants/:id/waste-report", h.Waste.Report)
+	owner.POST("/restaurants/:id/temperature-units", h.Temperature.CreateUnit)
+	owner.GET("/restaurants/:id/temperature-units", h.Temperature.GetUnits)
+	owner.POST("/temperature-units/:id/readings", h.Temperature.RecordReading)
+	owner.GET("/temperature-units/:id/readings", h.Temperature.GetReadings)
+	owner.GET("/restaurants/:id/temperature-log/export", h.Temperature.ExportLog)
+	owner.POST("/employees/:id/training-records", h.Training.AddRecord)
+	owner.GET("/employees/:id/training-records", h.Training.GetRecords)
+	owner.GET("/restaurants/:id/training-status", h.Training.Status)
+	owner.POST("/shifts/:id/swap-requests", h.Swap.RequestSwap)
+	owner.GET("/restaurants/:id/swap-requests", h.Swap.GetSwaps)
+	owner.POST("/swap-requests/:id/approve", h.Swap.Approve)
+	owner.POST("/swap-requests/:id/reject", h.Swap.Reject)
+	owner.GET("/restaurants/:id/schedule-changes", h.Swap.GetChanges)
+	owner.PUT("/employees/:id/pin", h.Timeclock.SetPin)
+	owner.POST("/employees/:id/clock-in", h.Timeclock.ClockIn)
+	owner.POST("/employees/:id/clock-out", h.Timeclock.ClockOut)
+	owner.PUT("/restaurants/:id/geofence", h.Timeclock.SetGeofence)
+	owner.GET("/restaurants/:id/timesheet", h.Timeclock.Timesheet)
+	owner.GET("/restaurants/:id/timesheet-variance", h.Timeclock.Variance)
+	owner.GET("/restaurants/:id/approvals", h.Approval.Inbox)
+	owner.POST("/approvals/:id/approve", h.Approval.Approve)
+	owner.POST("/approvals/:id/reject", h.Approval.Reject)
+	owner.POST("/brands", h.Brand.CreateBrand)
+	owner.GET("/brands", h.Brand.GetBrands)
+	owner.PUT("/brands/:id", h.Brand.UpdateBrand)
+	owner.PUT("/restaurants/:id/brand", h.Brand.AssignRestaurant)
+	owner.PUT("/restaurants/:id/brand-overrides", h.Brand.SetOverrides)
+	owner.GET("/restaurants/:id/brand-settings", h.Brand.GetSettings)
+	owner.POST("/restaurants/:id/api-keys", h.APIKey.IssueKey)
+	owner.GET("/restaurants/:id/api-keys", h.APIKey.GetKeys)
+	owner.POST("/api-keys/:id/revoke", h.APIKey.RevokeKey)
+	owner.GET("/api-keys/:id/usage", h.APIKey.GetUsage)
+	owner.GET("/restaurants/:id/api-usage", h.APIKey.GetUsageReport)
+	owner.POST("/restaurants/:id/booking-accounts", h.BookingSync.Connect)
+	owner.GET("/restaurants/:id/booking-accounts", h.BookingSync.GetAccounts)
+	owner.POST("/restaurants/:id/booking-accounts/sync", h.BookingSync.SyncNow)
+	owner.POST("/restaurants/:id/review-accounts", h.Review.Connect)
+	owner.GET("/restaurants/:id/review-accounts", h.Review.GetAccounts)
+	owner.POST("/restaurants/:id/review-accounts/sync", h.Review.SyncNow)
+	owner.GET("/restaurants/:id/review-trends", h.Review.Trends)
+	owner.POST("/restaurants/:id/faqs", h.FAQ.AddEntry)
+	owner.GET("/restaurants/:id/faqs", h.FAQ.GetEntries)
+	owner.DELETE("/faqs/:id", h.FAQ.DeleteEntry)
+	owner.GET("/restaurants/:id/email-templates", h.Email.GetTemplates)
+	owner.GET("/restaurants/:id/email-preview", h.Email.Preview)
+	owner.POST("/restaurants/:id/email-test", h.Email.TestSend)
+	owner.POST("/restaurants/:id/printers", h.Printer.RegisterPrinter)
+	owner.GET("/restaurants/:id/printers", h.Printer.GetPrinters)
+	owner.GET("/restaurants/:id/print-jobs", h.Printer.GetJobs)
+	owner.GET("/restaurants/:id/sync/changes", h.Sync.GetChanges)
+	owner.POST("/restaurants/:id/sync/push", h.Sync.Push)
+	owner.POST("/restaurants/:id/devices", h.Hardware.RegisterDevice)
+	owner.GET("/restaurants/:id/devices", h.Hardware.GetDevices)
+	owner.PUT("/devices/:id", h.Hardware.UpdateDevice)
+	owner.GET("/restaurants/:id/webhook-secret", h.Webhook.GetSecret)
+	owner.GET("/restaurants/:id/webhook-deliveries", h.Webhook.GetDeliveries)
+	owner.POST("/webhook-deliveries/:id/replay", h.Webhook.Replay)
+
+	owner.POST("/restaurants/:id/rooms", h.Room.CreateRoom)
+	owner.DELETE("/rooms/:id", h.Room.DeleteRoom)
+	owner.GET("/restaurants/:id/room-bookings", h.Room.GetBookings)
+	owner.PUT("/room-bookings/:id/status", h.Room.UpdateBookingStatus)
+
+	// Customer profile and history.
+	customer := e.Group("/me", middleware.JWT(jwtSecret), middleware.RequireRole(models.RoleCustomer))
+	customer.GET("/addresses", h.Customer.ListAddresses)
+	customer.POST("/addresses", h.Customer.CreateAddress)
+	customer.DELETE("/addresses/:id", h.Customer.DeleteAddress)
+	customer.GET("/payment-methods", h.Customer.ListPaymentMethods)
+	customer.POST("/payment-methods", h.Customer.CreatePaymentMethod)
+	customer.DELETE("/payment-methods/:id", h.Customer.DeletePaymentMethod)
+	customer.GET("/orders", h.Customer.OrderHistory)
+	customer.GET("/reservations", h.Customer.ReservationHistory)
+	customer.DELETE("/reservations/:id", h.Reservation.CancelMyReservation)
+	customer.GET("/coupons", h.Birthday.GetMyCoupons)
+	customer.GET("/tickets", h.Event.GetMyTickets)
+	customer.GET("/consents", h.Consent.GetConsents)
+	customer.PUT("/consents", h.Consent.UpdateConsent)
+	customer.POST("/loyalty-passes", h.Loyalty.IssuePass)
+	customer.GET("/loyalty-passes", h.Loyalty.GetPasses)
+
+	// Profiling endpoints, admin only: exposing pprof unauthenticated
+	// leaks heap contents and enables CPU-burn attacks.
+	debug := e.Group("/debug/pprof", middleware.JWT(jwtSecret), middleware.RequireRole(models.RoleAdmin))
+	debug.GET("", echo.WrapHandler(http.HandlerFunc(pprof.Index)))
+	debug.GET("/cmdline", echo.WrapHandler(http.HandlerFunc(pprof.Cmdline)))
+	debug.GET("/profile", echo.WrapHandler(http.HandlerFunc(pprof.Profile)))
+	debug.GET("/symbol", echo.WrapHandler(http.HandlerFunc(pprof.Symbol)))
+	debug.GET("/trace", echo.WrapHandler(http.HandlerFunc(pprof.Trace)))
+	debug.GET("/heap", echo.WrapHandler(pprof.Handler("heap")))
+	debug.GET("/goroutine", echo.WrapHandler(pprof.Handler("goroutine")))
+	debug.GET("/block", echo.WrapHandler(pprof.Handler("block")))
+	debug.GET("/mutex", echo.WrapHandler(pprof.Handler("mutex")))
+	debug.GET("/allocs", echo.WrapHandler(pprof.Handler("allocs")))
+
+	// Platform administration.
+	admin := e.Group("/admin", middleware.JWT(jwtSecret), middleware.RequireRole(models.RoleAdmin))
+	admin.GET("/restaurants", h.Admin.ListRestaurants)
+	admin.PUT("/users/:id/role", h.User.UpdateRole)
+	admin.GET("/deprecations", h.Admin.DeprecationUsage)
+	admin.GET("/compliance", h.License.ComplianceOverview)
+	admin.GET("/jobs", h.Admin.JobQueueHealth)
+	admin.POST("/backups", h.Admin.CreateBackup)
+	admin.GET("/backups", h.Admin.ListBackups)
+	admin.GET("/config", h.Admin.RuntimeConfig)
+	admin.POST("/config/reload", h.Admin.ReloadConfig)
+}
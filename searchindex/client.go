@@ -0,0 +1,155 @@
+package searchindex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// Client talks to an Elasticsearch/OpenSearch cluster over its JSON HTTP
+// API — a thin client, like our SendGrid and FCM ones, instead of the
+// official SDK. Restaurants and menu items share one index and are told
+// apart by the kind field.
+type Client struct {
+	baseURL string
+	index   string
+	client  *http.Client
+}
+
+// NewClient builds a Client for the given cluster URL and index name.
+func NewClient(baseURL, index string) *Client {
+	return &Client{
+		baseURL: baseURL,
+		index:   index,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// document is the shape stored in the index.
+type document struct {
+	Kind         string `json:"kind"`
+	ID           uint   `json:"id"`
+	RestaurantID uint   `json:"restaurant_id,omitempty"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Category     string `json:"category,omitempty"`
+}
+
+// IndexRestaurant creates or refreshes a restaurant document.
+func (c *Client) IndexRestaurant(r *models.Restaurant) error {
+	return c.put(fmt.Sprintf("restaurant-%d", r.ID), document{
+		Kind:        "restaurant",
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+	})
+}
+
+// RemoveRestaurant deletes a restaurant document.
+func (c *Client) RemoveRestaurant(id uint) error {
+	return c.delete(fmt.Sprintf("restaurant-%d", id))
+}
+
+// IndexMenuItem creates or refreshes a menu item document.
+func (c *Client) IndexMenuItem(item *models.MenuItem) error {
+	return c.put(fmt.Sprintf("menu-item-%d", item.ID), document{
+		Kind:         "menu_item",
+		ID:           item.ID,
+		RestaurantID: item.RestaurantID,
+		Name:         item.Name,
+		Description:  item.Description,
+		Category:     item.Category,
+	})
+}
+
+// RemoveMenuItem deletes a menu item document.
+func (c *Client) RemoveMenuItem(id uint) error {
+	return c.delete(fmt.Sprintf("menu-item-%d", id))
+}
+
+// SearchIDs returns the ids of documents of the given kind matching q,
+// best first. Fuzziness gives the typo tolerance a plain SQL LIKE lacks.
+func (c *Client) SearchIDs(kind, q string, limit int) ([]uint, error) {
+	body := map[string]interface{}{
+		"size":    limit,
+		"_source": []string{"id"},
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": map[string]interface{}{
+					"term": map[string]string{"kind": kind},
+				},
+				"must": map[string]interface{}{
+					"multi_match": map[string]interface{}{
+						"query":     q,
+						"fields":    []string{"name^2", "description", "category"},
+						"fuzziness": "AUTO",
+					},
+				},
+			},
+		},
+	}
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				Source struct {
+					ID uint `json:"id"`
+				} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := c.do(http.MethodPost, "/"+c.index+"/_search", body, &result); err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		ids = append(ids, hit.Source.ID)
+	}
+	return ids, nil
+}
+
+func (c *Client) put(docID string, doc document) error {
+	return c.do(http.MethodPut, "/"+c.index+"/_doc/"+docID, doc, nil)
+}
+
+func (c *Client) delete(docID string) error {
+	return c.do(http.MethodDelete, "/"+c.index+"/_doc/"+docID, nil, nil)
+}
+
+func (c *Client) do(method, path string, body, out interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Deleting a document that was never indexed is not a failure.
+	if resp.StatusCode >= 300 && !(method == http.MethodDelete && resp.StatusCode == http.StatusNotFound) {
+		return fmt.Errorf("elasticsearch returned status %d for %s %s", resp.StatusCode, method, path)
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
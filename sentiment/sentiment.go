@@ -0,0 +1,104 @@
+package sentiment
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marc0cl/wets-ma-bu-api/config"
+)
+
+// Sentiment labels an analyzer can assign.
+const (
+	Positive = "positive"
+	Neutral  = "neutral"
+	Negative = "negative"
+)
+
+// Topics an analyzer can tag. They are deliberately coarse: three
+// buckets an owner can act on beat a taxonomy nobody reads.
+const (
+	TopicService = "service"
+	TopicFood    = "food"
+	TopicPrice   = "price"
+)
+
+// Result is one review's analysis.
+type Result struct {
+	Sentiment string
+	Topics    []string
+}
+
+// Analyzer tags review text with sentiment and topics. A hosted
+// language model plugs in as another driver; the keyword analyzer is
+// the local default and needs no network.
+type Analyzer interface {
+	Analyze(text string, rating int) (Result, error)
+}
+
+// KeywordAnalyzer is a small lexicon-based analyzer. The star rating
+// decides sentiment when present — diners say more with stars than
+// words — and the lexicon fills in for text-only sources and topics.
+type KeywordAnalyzer struct{}
+
+var (
+	positiveWords = []string{"excelente", "rico", "delicious", "great", "impecable", "perfecto", "amazing", "bueno"}
+	negativeWords = []string{"malo", "lento", "frio", "cold", "terrible", "awful", "sucio", "caro", "bad", "slow"}
+
+	topicWords = map[string][]string{
+		TopicService: {"servicio", "atencion", "mesero", "garzon", "service", "waiter", "staff", "lento", "slow", "wait"},
+		TopicFood:    {"comida", "plato", "food", "dish", "rico", "delicious", "frio", "cold", "sabor", "taste"},
+		TopicPrice:   {"precio", "caro", "barato", "price", "expensive", "cheap", "vale", "worth"},
+	}
+)
+
+// Analyze implements Analyzer.
+func (KeywordAnalyzer) Analyze(text string, rating int) (Result, error) {
+	result := Result{Sentiment: Neutral}
+	switch {
+	case rating >= 4:
+		result.Sentiment = Positive
+	case rating > 0 && rating <= 2:
+		result.Sentiment = Negative
+	}
+
+	lower := strings.ToLower(text)
+	if rating == 0 {
+		score := 0
+		for _, w := range positiveWords {
+			if strings.Contains(lower, w) {
+				score++
+			}
+		}
+		for _, w := range negativeWords {
+			if strings.Contains(lower, w) {
+				score--
+			}
+		}
+		if score > 0 {
+			result.Sentiment = Positive
+		} else if score < 0 {
+			result.Sentiment = Negative
+		}
+	}
+
+	for _, topic := range []string{TopicFood, TopicPrice, TopicService} {
+		for _, w := range topicWords[topic] {
+			if strings.Contains(lower, w) {
+				result.Topics = append(result.Topics, topic)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// NewFromConfig builds the configured analyzer. "keyword" is the
+// default: local, deterministic and free.
+func NewFromConfig(cfg *config.Config) (Analyzer, error) {
+	switch cfg.SentimentDriver {
+	case "keyword", "":
+		return KeywordAnalyzer{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported sentiment driver %q", cfg.SentimentDriver)
+	}
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// Default account names used when a restaurant has not mapped its chart
+// of accounts; they match the stock QuickBooks/Xero charts.
+const (
+	defaultCashAccount    = "Undeposited Funds"
+	defaultSalesAccount   = "Sales"
+	defaultTaxAccount     = "Sales Tax Payable"
+	defaultTipsAccount    = "Tips Payable"
+	defaultRefundsAccount = "Refunds and Allowances"
+)
+
+// AccountingService exports daily sales journals for external
+// bookkeeping systems.
+type AccountingService struct {
+	analyticsRepo AnalyticsRepo
+	mappingRepo   AccountMappingRepo
+}
+
+// NewAccountingService builds an AccountingService.
+func NewAccountingService(analyticsRepo AnalyticsRepo, mappingRepo AccountMappingRepo) *AccountingService {
+	return &AccountingService{analyticsRepo: analyticsRepo, mappingRepo: mappingRepo}
+}
+
+// Mappings returns the restaurant's chart-of-account mapping, with
+// defaults filled in for anything left unmapped.
+func (s *AccountingService) Mappings(restaurantID uint) (*models.AccountMapping, error) {
+	mapping, err := s.mappingRepo.Get(restaurantID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		mapping = &models.AccountMapping{RestaurantID: restaurantID}
+	} else if err != nil {
+		return nil, err
+	}
+	if mapping.CashAccount == "" {
+		mapping.CashAccount = defaultCashAccount
+	}
+	if mapping.SalesAccount == "" {
+		mapping.SalesAccount = defaultSalesAccount
+	}
+	if mapping.TaxAccount == "" {
+		mapping.TaxAccount = defaultTaxAccount
+	}
+	if mapping.TipsAccount == "" {
+		mapping.TipsAccount = defaultTipsAccount
+	}
+	if mapping.RefundsAccount == "" {
+		mapping.RefundsAccount = defaultRefundsAccount
+	}
+	return mapping, nil
+}
+
+// UpdateMappings sets the restaurant's chart-of-account mapping. Nil
+// fields keep their current value.
+func (s *AccountingService) UpdateMappings(restaurantID uint, cash, sales, tax, tips, refunds *string) (*models.AccountMapping, error) {
+	mapping, err := s.mappingRepo.Get(restaurantID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		mapping = &models.AccountMapping{RestaurantID: restaurantID}
+	} else if err != nil {
+		return nil, err
+	}
+	if cash != nil {
+		mapping.CashAccount = *cash
+	}
+	if sales != nil {
+		mapping.SalesAccount = *sales
+	}
+	if tax != nil {
+		mapping.TaxAccount = *tax
+	}
+	if tips != nil {
+		mapping.TipsAccount = *tips
+	}
+	if refunds != nil {
+		mapping.RefundsAccount = *refunds
+	}
+	if err := s.mappingRepo.Save(mapping); err != nil {
+		return nil, err
+	}
+	return s.Mappings(restaurantID)
+}
+
+// journalEntry is one debit or credit line of the daily sales journal.
+type journalEntry struct {
+	Date    string
+	Account string
+	Memo    string
+	Debit   float64
+	Credit  float64
+}
+
+// journal builds the double-entry lines for the range: per day, cash is
+// debited for the gross take and sales, tax and tips are credited;
+// refunds reverse cash against the refunds account.
+func (s *AccountingService) journal(restaurantID uint, from, to time.Time) ([]journalEntry, error) {
+	mapping, err := s.Mappings(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.analyticsRepo.RevenueByPeriod(restaurantID, "day", from, to)
+	if err != nil {
+		return nil, err
+	}
+	refunds, err := s.analyticsRepo.RefundsByPeriod(restaurantID, "day", from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []journalEntry
+	for _, row := range rows {
+		sales := row.Gross - row.Tax - row.Tips
+		entries = append(entries,
+			journalEntry{row.Period, mapping.CashAccount, "Daily sales", row.Gross, 0},
+			journalEntry{row.Period, mapping.SalesAccount, "Daily sales", 0, sales},
+			journalEntry{row.Period, mapping.TaxAccount, "Sales tax", 0, row.Tax},
+			journalEntry{row.Period, mapping.TipsAccount, "Tips collected", 0, row.Tips},
+		)
+		if refunded := refunds[row.Period]; refunded > 0 {
+			entries = append(entries,
+				journalEntry{row.Period, mapping.RefundsAccount, "Refunds", refunded, 0},
+				journalEntry{row.Period, mapping.CashAccount, "Refunds", 0, refunded},
+			)
+			delete(refunds, row.Period)
+		}
+	}
+	// Refunds on days without sales still need to post.
+	for period, refunded := range refunds {
+		entries = append(entries,
+			journalEntry{period, mapping.RefundsAccount, "Refunds", refunded, 0},
+			journalEntry{period, mapping.CashAccount, "Refunds", 0, refunded},
+		)
+	}
+	return entries, nil
+}
+
+// Export renders the daily sales journal between from (inclusive) and
+// to (exclusive). format is "csv" (Xero-style journal import) or "iif"
+// (QuickBooks desktop).
+func (s *AccountingService) Export(restaurantID uint, from, to time.Time, format string) ([]byte, string, error) {
+	if !from.Before(to) {
+		return nil, "", Validation("invalid_range", "from must be before to")
+	}
+	entries, err := s.journal(restaurantID, from, to)
+	if err != nil {
+		return nil, "", err
+	}
+
+	stamp := from.Format("20060102") + "-" + to.Format("20060102")
+	switch format {
+	case "csv", "":
+		return renderJournalCSV(entries), fmt.Sprintf("journal-%s.csv", stamp), nil
+	case "iif":
+		return renderJournalIIF(entries), fmt.Sprintf("journal-%s.iif", stamp), nil
+	default:
+		return nil, "", Validation("invalid_format", "format must be csv or iif")
+	}
+}
+
+func renderJournalCSV(entries []journalEntry) []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"Date", "Account", "Description", "Debit", "Credit"})
+	for _, e := range entries {
+		w.Write([]string{e.Date, e.Account, e.Memo, money(e.Debit), money(e.Credit)})
+	}
+	w.Flush()
+	return buf.Bytes()
+}
+
+// renderJournalIIF writes QuickBooks' tab-separated IIF format: one
+// GENERAL JOURNAL transaction per line group, debits positive and
+// credits negative.
+func renderJournalIIF(entries []journalEntry) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("!TRNS\tTRNSTYPE\tDATE\tACCNT\tAMOUNT\tMEMO\n")
+	buf.WriteString("!SPL\tTRNSTYPE\tDATE\tACCNT\tAMOUNT\tMEMO\n")
+	buf.WriteString("!ENDTRNS\n")
+	row := "TRNS"
+	lastDate := ""
+	for _, e := range entries {
+		if e.Date != lastDate {
+			if lastDate != "" {
+				buf.WriteString("ENDTRNS\n")
+			}
+			row = "TRNS"
+			lastDate = e.Date
+		}
+		amount := e.Debit - e.Credit
+		fmt.Fprintf(&buf, "%s\tGENERAL JOURNAL\t%s\t%s\t%s\t%s\n",
+			row, e.Date, e.Account, money(amount), e.Memo)
+		row = "SPL"
+	}
+	if lastDate != "" {
+		buf.WriteString("ENDTRNS\n")
+	}
+	return buf.Bytes()
+}
+
+func money(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
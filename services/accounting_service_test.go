@@ -0,0 +1,78 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestExportBalancesDailyJournal(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	order := models.Order{RestaurantID: 1, Total: 10000, Tip: 500, Tax: 1596.64, Status: models.OrderCompleted}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("creating order: %v", err)
+	}
+	refund := models.Refund{OrderID: order.ID, Amount: 1000, Reason: models.RefundReasonIssue}
+	if err := db.Create(&refund).Error; err != nil {
+		t.Fatalf("creating refund: %v", err)
+	}
+
+	svc := NewAccountingService(
+		repositories.NewAnalyticsRepository(db),
+		repositories.NewAccountMappingRepository(db),
+	)
+
+	sales := "400 - Food Sales"
+	if _, err := svc.UpdateMappings(1, nil, &sales, nil, nil, nil); err != nil {
+		t.Fatalf("UpdateMappings: %v", err)
+	}
+
+	from := time.Now().UTC().AddDate(0, 0, -1)
+	to := time.Now().UTC().AddDate(0, 0, 1)
+	body, filename, err := svc.Export(1, from, to, "csv")
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if !strings.HasSuffix(filename, ".csv") {
+		t.Fatalf("unexpected filename %q", filename)
+	}
+
+	out := string(body)
+	// Cash is debited for the gross take; the custom sales account is
+	// credited net of tax and tips; the refund reverses cash.
+	for _, want := range []string{
+		"Undeposited Funds,Daily sales,10500.00,0.00",
+		"400 - Food Sales,Daily sales,0.00,8403.36",
+		"Sales Tax Payable,Sales tax,0.00,1596.64",
+		"Tips Payable,Tips collected,0.00,500.00",
+		"Refunds and Allowances,Refunds,1000.00,0.00",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("export missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestExportRejectsUnknownFormat(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+	svc := NewAccountingService(
+		repositories.NewAnalyticsRepository(db),
+		repositories.NewAccountMappingRepository(db),
+	)
+
+	from := time.Now().UTC().AddDate(0, 0, -1)
+	if _, _, err := svc.Export(1, from, time.Now().UTC(), "xlsx"); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/cache"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// revenueCacheTTL bounds how stale a cached revenue report may be.
+const revenueCacheTTL = 5 * time.Minute
+
+// AnalyticsService computes the aggregate reports behind the owner
+// dashboards.
+type AnalyticsService struct {
+	analyticsRepo  AnalyticsRepo
+	restaurantRepo RestaurantRepo
+	menuRepo       MenuRepo
+	cache          cache.Cache
+}
+
+// NewAnalyticsService builds an AnalyticsService.
+func NewAnalyticsService(analyticsRepo AnalyticsRepo, restaurantRepo RestaurantRepo, menuRepo MenuRepo, c cache.Cache) *AnalyticsService {
+	return &AnalyticsService{analyticsRepo: analyticsRepo, restaurantRepo: restaurantRepo, menuRepo: menuRepo, cache: c}
+}
+
+// RevenueBucket is one time bucket of the revenue report. Gross is
+// order totals plus tips; Net subtracts refunds and the tax included in
+// menu prices.
+type RevenueBucket struct {
+	Period  string  `json:"period"`
+	Orders  int64   `json:"orders"`
+	Gross   float64 `json:"gross"`
+	Refunds float64 `json:"refunds"`
+	Tips    float64 `json:"tips"`
+	Tax     float64 `json:"tax"`
+	Net     float64 `json:"net"`
+}
+
+// Revenue aggregates a restaurant's revenue between from (inclusive)
+// and to (exclusive) at the given granularity (day, week or month).
+// Reports are cached briefly because wide ranges scan a lot of rows.
+func (s *AnalyticsService) Revenue(restaurantID uint, granularity string, from, to time.Time) ([]RevenueBucket, error) {
+	switch granularity {
+	case "day", "week", "month":
+	default:
+		return nil, Validation("invalid_granularity", "granularity must be day, week or month")
+	}
+	if !from.Before(to) {
+		return nil, Validation("invalid_range", "from must be before to")
+	}
+
+	key := cache.RevenueKey(restaurantID, granularity,
+		from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if cached, ok := s.cache.Get(key); ok {
+		return cached.([]RevenueBucket), nil
+	}
+
+	rows, err := s.analyticsRepo.RevenueByPeriod(restaurantID, granularity, from, to)
+	if err != nil {
+		return nil, err
+	}
+	refunds, err := s.analyticsRepo.RefundsByPeriod(restaurantID, granularity, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]RevenueBucket, 0, len(rows))
+	for _, row := range rows {
+		refunded := refunds[row.Period]
+		buckets = append(buckets, RevenueBucket{
+			Period:  row.Period,
+			Orders:  row.Orders,
+			Gross:   row.Gross,
+			Refunds: refunded,
+			Tips:    row.Tips,
+			Tax:     row.Tax,
+			Net:     row.Gross - refunded - row.Tax,
+		})
+	}
+	s.cache.Set(key, buckets, revenueCacheTTL)
+	return buckets, nil
+}
+
+// Heatmap is order and reservation density by weekday and hour, in the
+// restaurant's timezone. Matrices are indexed [weekday][hour] with
+// weekday 0 = Sunday, matching time.Weekday.
+type Heatmap struct {
+	Timezone     string       `json:"timezone"`
+	Orders       [7][24]int64 `json:"orders"`
+	Reservations [7][24]int64 `json:"reservations"`
+}
+
+// BusyHours aggregates when a restaurant's orders come in and its
+// reservations are seated, between from (inclusive) and to (exclusive).
+// Counting happens in SQL per UTC hour; each hour is then shifted into
+// the restaurant's timezone, so weekday boundaries land where locals
+// expect them.
+func (s *AnalyticsService) BusyHours(restaurantID uint, from, to time.Time) (*Heatmap, error) {
+	if !from.Before(to) {
+		return nil, Validation("invalid_range", "from must be before to")
+	}
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	loc := restaurant.Location()
+
+	key := cache.BusyHoursKey(restaurantID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if cached, ok := s.cache.Get(key); ok {
+		return cached.(*Heatmap), nil
+	}
+
+	heatmap := &Heatmap{Timezone: restaurant.Timezone}
+	orders, err := s.analyticsRepo.OrderCountsByHour(restaurantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillHeatmap(&heatmap.Orders, orders, loc); err != nil {
+		return nil, err
+	}
+	reservations, err := s.analyticsRepo.ReservationCountsByHour(restaurantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillHeatmap(&heatmap.Reservations, reservations, loc); err != nil {
+		return nil, err
+	}
+
+	s.cache.Set(key, heatmap, revenueCacheTTL)
+	return heatmap, nil
+}
+
+// Menu engineering classes. Popularity crosses with margin: stars sell
+// well and earn well, plowhorses sell well on thin margins, puzzles
+// earn well but rarely sell, dogs do neither.
+const (
+	MenuClassStar      = "star"
+	MenuClassPlowhorse = "plowhorse"
+	MenuClassPuzzle    = "puzzle"
+	MenuClassDog       = "dog"
+)
+
+// MenuEngineeringItem is one dish of the menu engineering report.
+type MenuEngineeringItem struct {
+	MenuItemID uint    `json:"menu_item_id"`
+	Name       string  `json:"name"`
+	Price      float64 `json:"price"`
+	Cost       float64 `json:"cost"`
+	Margin     float64 `json:"margin"`
+	Units      int64   `json:"units"`
+	Revenue    float64 `json:"revenue"`
+	Profit     float64 `json:"profit"`
+	Class      string  `json:"class"`
+}
+
+// MenuEngineering combines each dish's sales volume with its margin and
+// classifies it. A dish is popular when it reaches 70% of the equal
+// share of units sold; its margin is high when it beats the sales-
+// weighted average margin.
+func (s *AnalyticsService) MenuEngineering(restaurantID uint, from, to time.Time) ([]MenuEngineeringItem, error) {
+	if !from.Before(to) {
+		return nil, Validation("invalid_range", "from must be before to")
+	}
+	items, err := s.menuRepo.GetByRestaurantID(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return []MenuEngineeringItem{}, nil
+	}
+	rows, err := s.analyticsRepo.ItemSales(restaurantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	sales := make(map[uint]repositories.ItemSalesRow, len(rows))
+	var totalUnits int64
+	var totalProfit float64
+	for _, row := range rows {
+		sales[row.MenuItemID] = row
+		totalUnits += row.Units
+	}
+	for _, item := range items {
+		totalProfit += (item.Price - item.Cost) * float64(sales[item.ID].Units)
+	}
+
+	popularityThreshold := 0.7 * float64(totalUnits) / float64(len(items))
+	var avgMargin float64
+	if totalUnits > 0 {
+		avgMargin = totalProfit / float64(totalUnits)
+	}
+
+	report := make([]MenuEngineeringItem, 0, len(items))
+	for _, item := range items {
+		row := sales[item.ID]
+		margin := item.Price - item.Cost
+		popular := totalUnits > 0 && float64(row.Units) >= popularityThreshold
+		highMargin := margin >= avgMargin
+		class := MenuClassDog
+		switch {
+		case popular && highMargin:
+			class = MenuClassStar
+		case popular:
+			class = MenuClassPlowhorse
+		case highMargin:
+			class = MenuClassPuzzle
+		}
+		report = append(report, MenuEngineeringItem{
+			MenuItemID: item.ID,
+			Name:       item.Name,
+			Price:      item.Price,
+			Cost:       item.Cost,
+			Margin:     margin,
+			Units:      row.Units,
+			Revenue:    row.Revenue,
+			Profit:     margin * float64(row.Units),
+			Class:      class,
+		})
+	}
+	return report, nil
+}
+
+// fillHeatmap accumulates UTC hour buckets into a weekday×hour matrix
+// in the given location.
+func fillHeatmap(matrix *[7][24]int64, counts []repositories.HourCount, loc *time.Location) error {
+	for _, count := range counts {
+		bucket, err := time.ParseInLocation("2006-01-02 15", count.Bucket, time.UTC)
+		if err != nil {
+			return err
+		}
+		local := bucket.In(loc)
+		matrix[local.Weekday()][local.Hour()] += count.Count
+	}
+	return nil
+}
+
+// TableTurnTime is the average turn for one table number; table 0
+// collects parties never assigned a table.
+type TableTurnTime struct {
+	Table      int     `json:"table"`
+	Turns      int64   `json:"turns"`
+	AvgMinutes float64 `json:"avg_minutes"`
+}
+
+// TurnTimeReport summarises how long parties hold tables.
+type TurnTimeReport struct {
+	Turns      int64           `json:"turns"`
+	AvgMinutes float64         `json:"avg_minutes"`
+	Tables     []TableTurnTime `json:"tables"`
+}
+
+// TurnTimes reports average table turn times per restaurant and per
+// table for turns seated inside [from, to). Only reservations that went
+// through both the seated and completed transitions contribute.
+func (s *AnalyticsService) TurnTimes(restaurantID uint, from, to time.Time) (*TurnTimeReport, error) {
+	if !from.Before(to) {
+		return nil, Validation("invalid_range", "from must be before to")
+	}
+	rows, err := s.analyticsRepo.TableTurnTimes(restaurantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TurnTimeReport{Tables: make([]TableTurnTime, 0, len(rows))}
+	var weighted float64
+	for _, row := range rows {
+		report.Tables = append(report.Tables, TableTurnTime{
+			Table:      row.TableNumber,
+			Turns:      row.Turns,
+			AvgMinutes: row.AvgMinutes,
+		})
+		report.Turns += row.Turns
+		weighted += row.AvgMinutes * float64(row.Turns)
+	}
+	if report.Turns > 0 {
+		report.AvgMinutes = weighted / float64(report.Turns)
+	}
+	return report, nil
+}
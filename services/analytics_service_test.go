@@ -0,0 +1,195 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/cache"
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestRevenueBucketsOrdersAndRefunds(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	orders := []models.Order{
+		{RestaurantID: 1, Total: 10000, Tip: 1000, Tax: 1596.64, Status: models.OrderCompleted},
+		{RestaurantID: 1, Total: 5000, Tax: 798.32, Status: models.OrderCompleted},
+		// Cancelled orders never count as revenue.
+		{RestaurantID: 1, Total: 9999, Status: models.OrderCancelled},
+		// Neither do other restaurants' orders.
+		{RestaurantID: 2, Total: 7777, Status: models.OrderCompleted},
+	}
+	for i := range orders {
+		if err := db.Create(&orders[i]).Error; err != nil {
+			t.Fatalf("creating order: %v", err)
+		}
+	}
+	refund := models.Refund{OrderID: orders[0].ID, Amount: 2000, Reason: models.RefundReasonIssue}
+	if err := db.Create(&refund).Error; err != nil {
+		t.Fatalf("creating refund: %v", err)
+	}
+
+	svc := NewAnalyticsService(repositories.NewAnalyticsRepository(db), repositories.NewRestaurantRepository(db), repositories.NewMenuRepository(db), cache.NewMemory())
+
+	from := time.Now().UTC().AddDate(0, 0, -1)
+	to := time.Now().UTC().AddDate(0, 0, 1)
+	buckets, err := svc.Revenue(1, "day", from, to)
+	if err != nil {
+		t.Fatalf("Revenue: %v", err)
+	}
+	if len(buckets) != 1 {
+		t.Fatalf("expected one bucket, got %d: %+v", len(buckets), buckets)
+	}
+	b := buckets[0]
+	if b.Orders != 2 || b.Gross != 16000 || b.Refunds != 2000 || b.Tips != 1000 {
+		t.Fatalf("unexpected bucket: %+v", b)
+	}
+	if want := 16000 - 2000 - b.Tax; b.Net != want {
+		t.Fatalf("expected net %v, got %v", want, b.Net)
+	}
+}
+
+func TestRevenueRejectsUnknownGranularity(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+	svc := NewAnalyticsService(repositories.NewAnalyticsRepository(db), repositories.NewRestaurantRepository(db), repositories.NewMenuRepository(db), cache.NewMemory())
+
+	from := time.Now().UTC().AddDate(0, 0, -1)
+	if _, err := svc.Revenue(1, "hour", from, time.Now().UTC()); err == nil {
+		t.Fatal("expected an error for an unknown granularity")
+	}
+}
+
+func TestBusyHoursShiftsIntoRestaurantTimezone(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	// Santiago is UTC-4 on this date, so an order at 02:00 UTC Tuesday
+	// lands on Monday 22:00 local time.
+	restaurant := models.Restaurant{Name: "Testaurant", UserID: 1, Timezone: "America/Santiago"}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("creating restaurant: %v", err)
+	}
+	created := time.Date(2026, 6, 2, 2, 0, 0, 0, time.UTC)
+	order := models.Order{RestaurantID: restaurant.ID, Total: 1000, Status: models.OrderCompleted}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("creating order: %v", err)
+	}
+	if err := db.Model(&order).Update("created_at", created).Error; err != nil {
+		t.Fatalf("backdating order: %v", err)
+	}
+
+	svc := NewAnalyticsService(repositories.NewAnalyticsRepository(db), repositories.NewRestaurantRepository(db), repositories.NewMenuRepository(db), cache.NewMemory())
+
+	heatmap, err := svc.BusyHours(restaurant.ID, created.AddDate(0, 0, -1), created.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("BusyHours: %v", err)
+	}
+	if got := heatmap.Orders[time.Monday][22]; got != 1 {
+		t.Fatalf("expected the order on Monday 22:00 local, got %+v", heatmap.Orders)
+	}
+}
+
+func TestMenuEngineeringClassifiesDishes(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	menuSvc := NewMenuService(repositories.NewMenuRepository(db), cache.NewMemory(), nil, nil)
+	star, err := menuSvc.CreateMenuItem(1, "Pastel de choclo", "", "mains", 9000, 2000)
+	if err != nil {
+		t.Fatalf("CreateMenuItem: %v", err)
+	}
+	dog, err := menuSvc.CreateMenuItem(1, "Ensalada simple", "", "starters", 3000, 2500)
+	if err != nil {
+		t.Fatalf("CreateMenuItem: %v", err)
+	}
+
+	// The star sells nine units at a fat margin; the dog sells one at a
+	// thin one.
+	order := models.Order{RestaurantID: 1, Status: models.OrderCompleted, Items: []models.OrderItem{
+		{MenuItemID: star.ID, Quantity: 9, Price: star.Price},
+		{MenuItemID: dog.ID, Quantity: 1, Price: dog.Price},
+	}}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("creating order: %v", err)
+	}
+
+	svc := NewAnalyticsService(repositories.NewAnalyticsRepository(db), repositories.NewRestaurantRepository(db), repositories.NewMenuRepository(db), cache.NewMemory())
+
+	report, err := svc.MenuEngineering(1, time.Now().UTC().AddDate(0, 0, -1), time.Now().UTC().AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("MenuEngineering: %v", err)
+	}
+	classes := make(map[uint]string)
+	for _, item := range report {
+		classes[item.MenuItemID] = item.Class
+	}
+	if classes[star.ID] != MenuClassStar {
+		t.Fatalf("expected a star, got %+v", report)
+	}
+	if classes[dog.ID] != MenuClassDog {
+		t.Fatalf("expected a dog, got %+v", report)
+	}
+}
+
+func TestTurnTimesAveragePerTableAndOverall(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	seatAt := func(table int, start time.Time, minutes int) models.Reservation {
+		end := start.Add(time.Duration(minutes) * time.Minute)
+		return models.Reservation{
+			RestaurantID: 1, CustomerName: "x", PartySize: 2, Date: start,
+			Status: models.ReservationCompleted, TableNumber: table,
+			SeatedAt: &start, DepartedAt: &end,
+		}
+	}
+	base := time.Now().UTC().AddDate(0, 0, -1)
+	reservations := []models.Reservation{
+		seatAt(1, base, 60),
+		seatAt(1, base.Add(2*time.Hour), 90),
+		seatAt(2, base, 30),
+		// Never seated: must not count.
+		{RestaurantID: 1, CustomerName: "x", PartySize: 2, Date: base, Status: models.ReservationCancelled},
+	}
+	for i := range reservations {
+		if err := db.Create(&reservations[i]).Error; err != nil {
+			t.Fatalf("creating reservation: %v", err)
+		}
+	}
+
+	svc := NewAnalyticsService(repositories.NewAnalyticsRepository(db), repositories.NewRestaurantRepository(db), repositories.NewMenuRepository(db), cache.NewMemory())
+
+	report, err := svc.TurnTimes(1, base.AddDate(0, 0, -1), base.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("TurnTimes: %v", err)
+	}
+	if report.Turns != 3 {
+		t.Fatalf("expected 3 turns, got %d", report.Turns)
+	}
+	if got := report.AvgMinutes; got < 59.9 || got > 60.1 {
+		t.Fatalf("expected overall average of 60 minutes, got %.2f", got)
+	}
+	if len(report.Tables) != 2 {
+		t.Fatalf("expected per-table rows for 2 tables, got %+v", report.Tables)
+	}
+	if report.Tables[0].Table != 1 || report.Tables[0].Turns != 2 || report.Tables[0].AvgMinutes < 74.9 || report.Tables[0].AvgMinutes > 75.1 {
+		t.Fatalf("unexpected table 1 row: %+v", report.Tables[0])
+	}
+	if report.Tables[1].Table != 2 || report.Tables[1].AvgMinutes < 29.9 || report.Tables[1].AvgMinutes > 30.1 {
+		t.Fatalf("unexpected table 2 row: %+v", report.Tables[1])
+	}
+}
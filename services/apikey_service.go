@@ -0,0 +1,304 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// defaultKeyRPM is the per-key rate limit applied when the owner does
+// not pick one.
+const defaultKeyRPM = 60
+
+// planQuotas is each plan's monthly request quota; zero means
+// unlimited. The rate limit bounds the pace, the quota bounds the
+// volume.
+var planQuotas = map[string]int64{
+	models.PlanFree:       10000,
+	models.PlanPro:        100000,
+	models.PlanEnterprise: 0,
+}
+
+// planFeatures is the widget features each plan unlocks. Availability
+// lookups hit the reservation calendar on every call, so they start at
+// pro.
+var planFeatures = map[string]map[string]bool{
+	models.PlanFree:       {"menu": true, "profile": true},
+	models.PlanPro:        {"menu": true, "profile": true, "availability": true},
+	models.PlanEnterprise: {"menu": true, "profile": true, "availability": true},
+}
+
+// APIKeyService issues publishable keys for embedding a restaurant's
+// public data and authorizes widget requests made with them.
+type APIKeyService struct {
+	apiKeyRepo     APIKeyRepo
+	restaurantRepo RestaurantRepo
+
+	// limiters holds a token bucket per key id. Buckets are local to
+	// the process, which over-admits by at most the replica count —
+	// the same trade-off the global rate limiter makes without Redis.
+	mu       sync.Mutex
+	limiters map[uint]*rate.Limiter
+}
+
+// NewAPIKeyService builds an APIKeyService.
+func NewAPIKeyService(apiKeyRepo APIKeyRepo, restaurantRepo RestaurantRepo) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo:     apiKeyRepo,
+		restaurantRepo: restaurantRepo,
+		limiters:       make(map[uint]*rate.Limiter),
+	}
+}
+
+// IssueKey creates a publishable key for the restaurant. An empty plan
+// means free; billingRef, when set, is the billing provider's
+// subscription item the key's usage is metered onto.
+func (s *APIKeyService) IssueKey(restaurantID uint, name, plan, allowedOrigins, billingRef string, rateLimitPerMin int) (*models.APIKey, error) {
+	name = utils.SanitizeText(name)
+	if name == "" {
+		return nil, Validation("missing_name", "a key name is required")
+	}
+	if plan == "" {
+		plan = models.PlanFree
+	}
+	if _, ok := planQuotas[plan]; !ok {
+		return nil, Validation("invalid_plan", "plan must be free, pro or enterprise")
+	}
+	if rateLimitPerMin < 0 {
+		return nil, Validation("invalid_rate_limit", "the rate limit cannot be negative")
+	}
+	if rateLimitPerMin == 0 {
+		rateLimitPerMin = defaultKeyRPM
+	}
+
+	key := &models.APIKey{
+		RestaurantID:    restaurantID,
+		Name:            name,
+		Plan:            plan,
+		AllowedOrigins:  normalizeOrigins(allowedOrigins),
+		BillingRef:      strings.TrimSpace(billingRef),
+		RateLimitPerMin: rateLimitPerMin,
+	}
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Keys returns a restaurant's keys.
+func (s *APIKeyService) Keys(restaurantID uint) ([]models.APIKey, error) {
+	return s.apiKeyRepo.GetByRestaurantID(restaurantID)
+}
+
+// GetKeyByID returns a single key.
+func (s *APIKeyService) GetKeyByID(id uint) (*models.APIKey, error) {
+	key, err := s.apiKeyRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "key_not_found", "API key not found")
+	}
+	return key, nil
+}
+
+// RevokeKey makes a key permanently unusable.
+func (s *APIKeyService) RevokeKey(id uint) (*models.APIKey, error) {
+	key, err := s.GetKeyByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt == nil {
+		now := time.Now().UTC()
+		key.RevokedAt = &now
+		if err := s.apiKeyRepo.Update(key); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// Authorize validates a publishable key for a widget request from the
+// given origin. Invalid and revoked keys get the same answer so probing
+// reveals nothing.
+func (s *APIKeyService) Authorize(value, origin string) (*models.APIKey, error) {
+	if value == "" {
+		return nil, Forbidden("invalid_key", "invalid API key")
+	}
+	key, err := s.apiKeyRepo.GetByKey(value)
+	if err != nil {
+		return nil, fromRepo(err, "invalid_key", "invalid API key")
+	}
+	if key.RevokedAt != nil {
+		return nil, Forbidden("invalid_key", "invalid API key")
+	}
+	if !originAllowed(key.AllowedOrigins, origin) {
+		return nil, Forbidden("origin_not_allowed", "this key cannot be used from this origin")
+	}
+	return key, nil
+}
+
+// Allow counts a request against the key's per-minute limit and reports
+// whether it fits.
+func (s *APIKeyService) Allow(key *models.APIKey) bool {
+	s.mu.Lock()
+	limiter, ok := s.limiters[key.ID]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(key.RateLimitPerMin)/60, key.RateLimitPerMin)
+		s.limiters[key.ID] = limiter
+	}
+	s.mu.Unlock()
+	return limiter.Allow()
+}
+
+// QuotaState is where a key stands against its plan's monthly quota.
+// A zero Quota means unlimited, and Remaining is meaningless then.
+type QuotaState struct {
+	Plan      string `json:"plan"`
+	Period    string `json:"period"`
+	Used      int64  `json:"used"`
+	Quota     int64  `json:"quota"`
+	Remaining int64  `json:"remaining"`
+}
+
+// quotaPeriod is the current calendar month, the quota's reset cycle.
+func quotaPeriod() string {
+	return time.Now().UTC().Format("2006-01")
+}
+
+// Consume counts one request against the key's monthly quota and
+// reports where it stands. The caller checks Exhausted to refuse the
+// request.
+func (s *APIKeyService) Consume(key *models.APIKey) (*QuotaState, error) {
+	period := quotaPeriod()
+	used, err := s.apiKeyRepo.IncrementUsage(key.ID, period)
+	if err != nil {
+		return nil, err
+	}
+	return s.quotaState(key, period, used), nil
+}
+
+// Exhausted reports whether the state is over its plan's quota.
+func (q *QuotaState) Exhausted() bool {
+	return q.Quota > 0 && q.Used > q.Quota
+}
+
+// RequireFeature checks that the key's plan unlocks the named feature.
+func (s *APIKeyService) RequireFeature(key *models.APIKey, feature string) error {
+	if !planFeatures[key.Plan][feature] {
+		return Forbidden("plan_required", "this feature is not included in the key's plan")
+	}
+	return nil
+}
+
+// Usage returns the key's standing this month without consuming
+// anything; it backs the owner's usage endpoint.
+func (s *APIKeyService) Usage(id uint) (*QuotaState, error) {
+	key, err := s.GetKeyByID(id)
+	if err != nil {
+		return nil, err
+	}
+	period := quotaPeriod()
+	used, err := s.apiKeyRepo.GetUsage(key.ID, period)
+	if err != nil {
+		return nil, err
+	}
+	return s.quotaState(key, period, used), nil
+}
+
+// UsageReport returns every usage row of the restaurant's keys, newest
+// period first: the owner's month-by-month view across the fleet.
+func (s *APIKeyService) UsageReport(restaurantID uint) ([]repositories.KeyUsageRow, error) {
+	return s.apiKeyRepo.UsageByRestaurantID(restaurantID)
+}
+
+// quotaState assembles the reported standing for a key.
+func (s *APIKeyService) quotaState(key *models.APIKey, period string, used int64) *QuotaState {
+	quota := planQuotas[key.Plan]
+	remaining := quota - used
+	if remaining < 0 || quota == 0 {
+		remaining = 0
+	}
+	return &QuotaState{Plan: key.Plan, Period: period, Used: used, Quota: quota, Remaining: remaining}
+}
+
+// WidgetProfile is the slice of a restaurant's profile a widget may
+// show: the front-of-house basics, nothing operational.
+type WidgetProfile struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Address     string `json:"address"`
+	Phone       string `json:"phone"`
+	Timezone    string `json:"timezone"`
+	OpensAt     string `json:"opens_at"`
+	ClosesAt    string `json:"closes_at"`
+}
+
+// Profile returns the public profile for a widget to render.
+func (s *APIKeyService) Profile(restaurantID uint) (*WidgetProfile, error) {
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	return &WidgetProfile{
+		Name:        restaurant.Name,
+		Description: restaurant.Description,
+		Address:     restaurant.Address,
+		Phone:       restaurant.Phone,
+		Timezone:    restaurant.Timezone,
+		OpensAt:     restaurant.OpensAt,
+		ClosesAt:    restaurant.ClosesAt,
+	}, nil
+}
+
+// normalizeOrigins trims the allow-list down to clean comma-separated
+// entries without trailing slashes.
+func normalizeOrigins(list string) string {
+	var out []string
+	for _, part := range strings.Split(list, ",") {
+		if part = strings.TrimSuffix(strings.TrimSpace(part), "/"); part != "" {
+			out = append(out, part)
+		}
+	}
+	return strings.Join(out, ",")
+}
+
+// originAllowed reports whether the request origin matches the key's
+// allow-list. An empty list allows everything; a "*." host prefix in an
+// entry matches any subdomain of that domain.
+func originAllowed(list, origin string) bool {
+	if list == "" {
+		return true
+	}
+	origin = strings.TrimSuffix(strings.TrimSpace(origin), "/")
+	if origin == "" {
+		return false
+	}
+	for _, entry := range strings.Split(list, ",") {
+		if entry == origin {
+			return true
+		}
+		if host, ok := wildcardHost(entry); ok {
+			u, err := url.Parse(origin)
+			if err == nil && strings.HasPrefix(entry, u.Scheme+"://") &&
+				(u.Hostname() == host || strings.HasSuffix(u.Hostname(), "."+host)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wildcardHost extracts the bare domain from an entry like
+// "https://*.example.com".
+func wildcardHost(entry string) (string, bool) {
+	_, rest, found := strings.Cut(entry, "://*.")
+	if !found || rest == "" {
+		return "", false
+	}
+	return rest, true
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestAPIKeyAuthorization(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Kay", Email: "kay@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Widgets", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewAPIKeyService(repositories.NewAPIKeyRepository(db), repositories.NewRestaurantRepository(db))
+
+	key, err := svc.IssueKey(restaurant.ID, "website", "", "https://example.com/, https://*.widgets.example.com", "", 0)
+	if err != nil {
+		t.Fatalf("issue key: %v", err)
+	}
+	if !strings.HasPrefix(key.Key, "pk_") {
+		t.Fatalf("expected a publishable key value, got %q", key.Key)
+	}
+	if key.RateLimitPerMin != defaultKeyRPM {
+		t.Fatalf("expected the default rate limit, got %d", key.RateLimitPerMin)
+	}
+	if _, err := svc.IssueKey(restaurant.ID, " ", "", "", "", 0); err == nil {
+		t.Fatal("expected a nameless key to be rejected")
+	}
+
+	// Allowed origins pass, including wildcard subdomains; others fail.
+	if _, err := svc.Authorize(key.Key, "https://example.com"); err != nil {
+		t.Fatalf("exact origin: %v", err)
+	}
+	if _, err := svc.Authorize(key.Key, "https://menu.widgets.example.com"); err != nil {
+		t.Fatalf("wildcard origin: %v", err)
+	}
+	if _, err := svc.Authorize(key.Key, "https://evil.example.net"); err == nil {
+		t.Fatal("expected a foreign origin to be rejected")
+	}
+	if _, err := svc.Authorize("pk_nope", "https://example.com"); err == nil {
+		t.Fatal("expected an unknown key to be rejected")
+	}
+
+	// An open key works from anywhere until revoked.
+	open, err := svc.IssueKey(restaurant.ID, "open", "", "", "", 10)
+	if err != nil {
+		t.Fatalf("issue open key: %v", err)
+	}
+	if _, err := svc.Authorize(open.Key, "https://anywhere.example.org"); err != nil {
+		t.Fatalf("open key: %v", err)
+	}
+	if _, err := svc.RevokeKey(open.ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := svc.Authorize(open.Key, "https://anywhere.example.org"); err == nil {
+		t.Fatal("expected a revoked key to be rejected")
+	}
+}
+
+func TestAPIKeyRateLimit(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Lim", Email: "lim@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Limited", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewAPIKeyService(repositories.NewAPIKeyRepository(db), repositories.NewRestaurantRepository(db))
+	key, err := svc.IssueKey(restaurant.ID, "tight", "", "", "", 2)
+	if err != nil {
+		t.Fatalf("issue key: %v", err)
+	}
+
+	if !svc.Allow(key) || !svc.Allow(key) {
+		t.Fatal("expected the first two requests to fit the burst")
+	}
+	if svc.Allow(key) {
+		t.Fatal("expected the third request in the same instant to be throttled")
+	}
+}
+
+func TestAPIKeyPlanQuotaAndFeatures(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Pla", Email: "pla@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Planes", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewAPIKeyService(repositories.NewAPIKeyRepository(db), repositories.NewRestaurantRepository(db))
+
+	if _, err := svc.IssueKey(restaurant.ID, "bad", "platinum", "", "", 0); err == nil {
+		t.Fatal("expected an unknown plan to be rejected")
+	}
+	free, err := svc.IssueKey(restaurant.ID, "site", "", "", "", 0)
+	if err != nil {
+		t.Fatalf("issue free key: %v", err)
+	}
+	if free.Plan != models.PlanFree {
+		t.Fatalf("expected an omitted plan to default to free, got %q", free.Plan)
+	}
+
+	// The free plan stops at the basics; pro unlocks availability.
+	if err := svc.RequireFeature(free, "menu"); err != nil {
+		t.Fatalf("free menu access: %v", err)
+	}
+	if err := svc.RequireFeature(free, "availability"); err == nil {
+		t.Fatal("expected the free plan to lack availability")
+	}
+	pro, err := svc.IssueKey(restaurant.ID, "app", models.PlanPro, "", "", 0)
+	if err != nil {
+		t.Fatalf("issue pro key: %v", err)
+	}
+	if err := svc.RequireFeature(pro, "availability"); err != nil {
+		t.Fatalf("pro availability access: %v", err)
+	}
+
+	// Consumption counts up and the usage endpoint reads the same row.
+	state, err := svc.Consume(free)
+	if err != nil {
+		t.Fatalf("consume: %v", err)
+	}
+	if state.Used != 1 || state.Quota != planQuotas[models.PlanFree] || state.Remaining != state.Quota-1 {
+		t.Fatalf("expected one consumed request, got %+v", state)
+	}
+	if state, err = svc.Consume(free); err != nil || state.Used != 2 {
+		t.Fatalf("expected the second request counted, got %+v (%v)", state, err)
+	}
+	usage, err := svc.Usage(free.ID)
+	if err != nil {
+		t.Fatalf("usage: %v", err)
+	}
+	if usage.Used != 2 || usage.Exhausted() {
+		t.Fatalf("expected two used and headroom left, got %+v", usage)
+	}
+
+	// Enterprise has no quota: consumption never exhausts it.
+	ent, err := svc.IssueKey(restaurant.ID, "chain", models.PlanEnterprise, "", "", 0)
+	if err != nil {
+		t.Fatalf("issue enterprise key: %v", err)
+	}
+	state, err = svc.Consume(ent)
+	if err != nil {
+		t.Fatalf("consume enterprise: %v", err)
+	}
+	if state.Quota != 0 || state.Exhausted() {
+		t.Fatalf("expected an unlimited plan, got %+v", state)
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// ApprovalExecutor performs a gated action once its approval is
+// granted. A returned error leaves the approval pending so the manager
+// can retry.
+type ApprovalExecutor func(approval *models.Approval) error
+
+// ApprovalService is the generic manager-approval subsystem. Domains
+// file requests for actions that exceed their thresholds and register
+// an executor per kind; approving a request runs its executor, and the
+// pending ones form the manager's inbox.
+type ApprovalService struct {
+	approvalRepo   ApprovalRepo
+	restaurantRepo RestaurantRepo
+	userRepo       UserRepo
+	jobs           JobEnqueuer
+	executors      map[string]ApprovalExecutor
+}
+
+// NewApprovalService builds an ApprovalService.
+func NewApprovalService(approvalRepo ApprovalRepo, restaurantRepo RestaurantRepo, userRepo UserRepo, jobs JobEnqueuer) *ApprovalService {
+	return &ApprovalService{
+		approvalRepo:   approvalRepo,
+		restaurantRepo: restaurantRepo,
+		userRepo:       userRepo,
+		jobs:           jobs,
+		executors:      make(map[string]ApprovalExecutor),
+	}
+}
+
+// Register binds the executor that runs kind's actions once approved.
+// Registration happens at wiring time, before requests flow.
+func (s *ApprovalService) Register(kind string, executor ApprovalExecutor) {
+	s.executors[kind] = executor
+}
+
+// Request files an action for approval and notifies the restaurant's
+// owner. A second request for the same undecided action is rejected
+// rather than duplicated.
+func (s *ApprovalService) Request(restaurantID uint, kind string, subjectID uint, summary string, amount float64, payload string) (*models.Approval, error) {
+	if _, err := s.approvalRepo.FindPending(kind, subjectID); err == nil {
+		return nil, Conflict("approval_pending", "the action is already awaiting approval")
+	}
+
+	approval := &models.Approval{
+		RestaurantID: restaurantID,
+		Kind:         kind,
+		SubjectID:    subjectID,
+		Summary:      summary,
+		Amount:       amount,
+		Payload:      payload,
+		Status:       models.ApprovalPending,
+	}
+	if err := s.approvalRepo.Create(approval); err != nil {
+		return nil, err
+	}
+	if err := s.notifyApprover(approval); err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+// notifyApprover queues the new request's email to the restaurant's
+// owner.
+func (s *ApprovalService) notifyApprover(approval *models.Approval) error {
+	restaurant, err := s.restaurantRepo.GetByID(approval.RestaurantID)
+	if err != nil {
+		return err
+	}
+	owner, err := s.userRepo.GetByID(restaurant.UserID)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"to":      owner.Email,
+		"subject": fmt.Sprintf("%s: approval needed", restaurant.Name),
+		"text":    fmt.Sprintf("Waiting on your decision: %s. Review it in the approvals inbox.", approval.Summary),
+	})
+	if err != nil {
+		return err
+	}
+	return s.jobs.Enqueue(&models.Job{Type: "email.send", Payload: string(payload)})
+}
+
+// GetApprovalByID returns one approval request.
+func (s *ApprovalService) GetApprovalByID(id uint) (*models.Approval, error) {
+	approval, err := s.approvalRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "approval_not_found", "approval not found")
+	}
+	return approval, nil
+}
+
+// Inbox returns a restaurant's undecided approvals, oldest first.
+func (s *ApprovalService) Inbox(restaurantID uint) ([]models.Approval, error) {
+	return s.approvalRepo.GetPendingByRestaurantID(restaurantID)
+}
+
+// Approve grants a pending request and runs its executor. If the
+// executor fails, the request stays pending and the error surfaces to
+// the manager.
+func (s *ApprovalService) Approve(id uint) (*models.Approval, error) {
+	approval, err := s.GetApprovalByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if approval.Status != models.ApprovalPending {
+		return nil, Conflict("already_decided", "the approval has already been decided")
+	}
+
+	if executor, ok := s.executors[approval.Kind]; ok {
+		if err := executor(approval); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	approval.Status = models.ApprovalApproved
+	approval.DecidedAt = &now
+	if err := s.approvalRepo.Update(approval); err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+// Reject declines a pending request without running anything.
+func (s *ApprovalService) Reject(id uint) (*models.Approval, error) {
+	approval, err := s.GetApprovalByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if approval.Status != models.ApprovalPending {
+		return nil, Conflict("already_decided", "the approval has already been decided")
+	}
+
+	now := time.Now().UTC()
+	approval.Status = models.ApprovalRejected
+	approval.DecidedAt = &now
+	if err := s.approvalRepo.Update(approval); err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
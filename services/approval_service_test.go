@@ -0,0 +1,160 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/cache"
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestLargeRefundWaitsForApproval(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Abe", Email: "abe@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Approvals", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	approvalSvc := NewApprovalService(repositories.NewApprovalRepository(db), repositories.NewRestaurantRepository(db), repositories.NewUserRepository(db), repositories.NewJobRepository(db))
+	menuSvc := NewMenuService(repositories.NewMenuRepository(db), cache.NewMemory(), nil, nil)
+	orderSvc := NewOrderService(
+		repositories.NewOrderRepository(db),
+		repositories.NewMenuRepository(db),
+		repositories.NewRefundRepository(db),
+		repositories.NewTxManager(db),
+		repositories.NewOutboxRepository(db),
+		payments.LogProvider{},
+		nil,
+		approvalSvc,
+		nil,
+		nil,
+		19,
+	)
+	approvalSvc.Register(ApprovedRefundKind, func(approval *models.Approval) error {
+		_, err := orderSvc.ExecuteApprovedRefund(approval.Payload)
+		return err
+	})
+
+	dish, err := menuSvc.CreateMenuItem(restaurant.ID, "Banquete", "", "mains", 80000, 0)
+	if err != nil {
+		t.Fatalf("create menu item: %v", err)
+	}
+	order, err := orderSvc.CreateOrder(restaurant.ID, 0, "Rico", "rico@example.com", []OrderLine{{MenuItemID: dish.ID, Quantity: 1}}, 0)
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+
+	if _, err := orderSvc.RefundOrder(order.ID, 0, models.RefundReasonIssue); err == nil {
+		t.Fatal("expected a refund above the threshold to need approval")
+	}
+
+	inbox, err := approvalSvc.Inbox(restaurant.ID)
+	if err != nil {
+		t.Fatalf("inbox: %v", err)
+	}
+	if len(inbox) != 1 || inbox[0].Kind != ApprovedRefundKind {
+		t.Fatalf("expected one refund approval in the inbox, got %+v", inbox)
+	}
+
+	// A repeat request does not pile up a second entry.
+	if _, err := orderSvc.RefundOrder(order.ID, 0, models.RefundReasonIssue); err == nil {
+		t.Fatal("expected the repeat request to be rejected while one is pending")
+	}
+	if inbox, err = approvalSvc.Inbox(restaurant.ID); err != nil || len(inbox) != 1 {
+		t.Fatalf("expected the inbox to stay at one entry, got %+v (%v)", inbox, err)
+	}
+
+	approved, err := approvalSvc.Approve(inbox[0].ID)
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if approved.Status != models.ApprovalApproved || approved.DecidedAt == nil {
+		t.Fatalf("expected a decided approval, got %+v", approved)
+	}
+	refunds, err := orderSvc.GetRefundsByOrderID(order.ID)
+	if err != nil {
+		t.Fatalf("load refunds: %v", err)
+	}
+	if len(refunds) != 1 || refunds[0].Amount != order.Total {
+		t.Fatalf("expected the approved refund to be executed in full, got %+v", refunds)
+	}
+
+	if _, err := approvalSvc.Approve(approved.ID); err == nil {
+		t.Fatal("expected re-approving a decided approval to fail")
+	}
+}
+
+func TestLargeDiscountWaitsForApproval(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Dana", Email: "dana@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Discounts", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	approvalSvc := NewApprovalService(repositories.NewApprovalRepository(db), repositories.NewRestaurantRepository(db), repositories.NewUserRepository(db), repositories.NewJobRepository(db))
+	menuSvc := NewMenuService(repositories.NewMenuRepository(db), cache.NewMemory(), nil, approvalSvc)
+	approvalSvc.Register(ApprovedDiscountKind, func(approval *models.Approval) error {
+		_, err := menuSvc.ExecuteApprovedDiscount(approval.Payload)
+		return err
+	})
+
+	dish, err := menuSvc.CreateMenuItem(restaurant.ID, "Cazuela", "", "mains", 10000, 0)
+	if err != nil {
+		t.Fatalf("create menu item: %v", err)
+	}
+
+	// A small cut goes straight through; a half-price one waits.
+	if _, err := menuSvc.UpdateMenuItem(dish.ID, owner.ID, "", "", "", 9000, 0, true); err != nil {
+		t.Fatalf("small price cut: %v", err)
+	}
+	if _, err := menuSvc.UpdateMenuItem(dish.ID, owner.ID, "", "", "", 4500, 0, true); err == nil {
+		t.Fatal("expected a half-price cut to need approval")
+	}
+	current, err := menuSvc.GetMenuItemByID(dish.ID)
+	if err != nil {
+		t.Fatalf("reload item: %v", err)
+	}
+	if current.Price != 9000 {
+		t.Fatalf("expected the price to hold at 9000 while pending, got %v", current.Price)
+	}
+
+	inbox, err := approvalSvc.Inbox(restaurant.ID)
+	if err != nil || len(inbox) != 1 {
+		t.Fatalf("expected one discount approval, got %+v (%v)", inbox, err)
+	}
+	if _, err := approvalSvc.Approve(inbox[0].ID); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	current, err = menuSvc.GetMenuItemByID(dish.ID)
+	if err != nil {
+		t.Fatalf("reload item: %v", err)
+	}
+	if current.Price != 4500 {
+		t.Fatalf("expected the approved price to apply, got %v", current.Price)
+	}
+	history, err := menuSvc.PriceHistory(dish.ID)
+	if err != nil {
+		t.Fatalf("price history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected both re-pricings in the history, got %+v", history)
+	}
+}
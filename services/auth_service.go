@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"log"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/events"
+	"github.com/marc0cl/wets-ma-bu-api/hashing"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// AuthService implements registration and login.
+type AuthService struct {
+	userRepo  UserRepo
+	tx        TxRunner
+	outbox    OutboxWriter
+	hasher    hashing.Hasher
+	jwtSecret string
+}
+
+// NewAuthService builds an AuthService.
+func NewAuthService(userRepo UserRepo, tx TxRunner, outbox OutboxWriter, hasher hashing.Hasher, jwtSecret string) *AuthService {
+	return &AuthService{userRepo: userRepo, tx: tx, outbox: outbox, hasher: hasher, jwtSecret: jwtSecret}
+}
+
+// Register creates a new owner account.
+func (s *AuthService) Register(name, email, password, phone string) (*models.User, error) {
+	return s.register(name, email, password, phone, models.RoleOwner)
+}
+
+// RegisterCustomer creates a new customer account. Customers order and
+// book tables; they cannot manage restaurants.
+func (s *AuthService) RegisterCustomer(name, email, password, phone string) (*models.User, error) {
+	return s.register(name, email, password, phone, models.RoleCustomer)
+}
+
+// register creates an account with the given role. Uniqueness of the
+// email is enforced by the database constraint rather than a
+// read-then-write check, which two concurrent registrations could both
+// pass; the loser of the race gets the same conflict as a plain
+// duplicate.
+func (s *AuthService) register(name, email, password, phone string, role models.Role) (*models.User, error) {
+	email = utils.NormalizeEmail(email)
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Name:     utils.SanitizeText(name),
+		Email:    email,
+		Password: hash,
+		Phone:    phone,
+		Role:     role,
+	}
+	// The account and its registered event commit together.
+	err = s.tx.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.CreateInTx(tx, user); err != nil {
+			return err
+		}
+		event, err := events.NewOutboxEvent(events.EventUserRegistered, map[string]interface{}{
+			"user_id": user.ID,
+			"email":   user.Email,
+		})
+		if err != nil {
+			return err
+		}
+		return s.outbox.CreateInTx(tx, event)
+	})
+	if err != nil {
+		if repositories.IsDuplicateKey(err) {
+			return nil, ErrEmailTaken
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// Login verifies the credentials and returns a signed token.
+func (s *AuthService) Login(email, password string) (string, *models.User, error) {
+	user, err := s.userRepo.GetByEmail(utils.NormalizeEmail(email))
+	if err != nil {
+		return "", nil, errors.New("invalid credentials")
+	}
+	if !hashing.Verify(password, user.Password) {
+		return "", nil, errors.New("invalid credentials")
+	}
+
+	// Upgrade hashes created with an older algorithm or weaker
+	// parameters while we still hold the plaintext. A failed rehash
+	// must not break an otherwise valid login.
+	if s.hasher.NeedsRehash(user.Password) {
+		if hash, err := s.hasher.Hash(password); err == nil {
+			user.Password = hash
+			if err := s.userRepo.Update(user); err != nil {
+				log.Printf("auth: could not rehash password for user %d: %v", user.ID, err)
+			}
+		}
+	}
+
+	token, err := utils.GenerateToken(user.ID, user.PublicID, user.Email, user.Role, s.jwtSecret)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, user, nil
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// Default birthday template, used until a restaurant customises it.
+const (
+	defaultBirthdaySubject     = "Happy birthday, {{name}}!"
+	defaultBirthdayBody        = "Happy birthday, {{name}}! Here is {{discount}}% off your next order on us. Use code {{code}}."
+	defaultBirthdayDiscountPct = 10
+	defaultBirthdayValidDays   = 30
+)
+
+// BirthdayService issues birthday coupons and greetings. A daily run
+// finds customers whose birthday it is, and for every restaurant they
+// have ordered from with greetings enabled, issues one coupon per year
+// and queues the greeting email. Customers who have not opted in to
+// marketing email are skipped entirely.
+type BirthdayService struct {
+	birthdayRepo BirthdayRepo
+	couponRepo   CouponRepo
+	consents     consentChecker
+	jobs         JobEnqueuer
+}
+
+// NewBirthdayService builds a BirthdayService.
+func NewBirthdayService(birthdayRepo BirthdayRepo, couponRepo CouponRepo, consents consentChecker, jobs JobEnqueuer) *BirthdayService {
+	return &BirthdayService{birthdayRepo: birthdayRepo, couponRepo: couponRepo, consents: consents, jobs: jobs}
+}
+
+// Template returns a restaurant's birthday template, with defaults
+// filled in for anything left unset. Greetings start disabled.
+func (s *BirthdayService) Template(restaurantID uint) (*models.BirthdayTemplate, error) {
+	template, err := s.birthdayRepo.GetTemplate(restaurantID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		template = &models.BirthdayTemplate{RestaurantID: restaurantID}
+	} else if err != nil {
+		return nil, err
+	}
+	fillTemplateDefaults(template)
+	return template, nil
+}
+
+// UpdateTemplate stores a restaurant's birthday template.
+func (s *BirthdayService) UpdateTemplate(restaurantID uint, enabled bool, subject, body string, discountPct, validDays int) (*models.BirthdayTemplate, error) {
+	if discountPct < 0 || discountPct > 100 {
+		return nil, Validation("invalid_discount", "discount must be between 0 and 100 percent")
+	}
+	if validDays < 0 {
+		return nil, Validation("invalid_valid_days", "validity cannot be negative")
+	}
+	template, err := s.birthdayRepo.GetTemplate(restaurantID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		template = &models.BirthdayTemplate{RestaurantID: restaurantID}
+	} else if err != nil {
+		return nil, err
+	}
+	template.Enabled = enabled
+	template.Subject = utils.SanitizeText(subject)
+	template.Body = utils.SanitizeText(body)
+	template.DiscountPct = discountPct
+	template.ValidDays = validDays
+	fillTemplateDefaults(template)
+	if err := s.birthdayRepo.SaveTemplate(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// Coupons returns a customer's coupons, newest first.
+func (s *BirthdayService) Coupons(email string) ([]models.Coupon, error) {
+	return s.couponRepo.GetByEmail(utils.NormalizeEmail(email))
+}
+
+// RunOnce issues the rewards due on the given day and returns how many
+// coupons went out. Per-customer failures are logged and skipped so one
+// bad record cannot hold up everyone else's birthday.
+func (s *BirthdayService) RunOnce(now time.Time) (int, error) {
+	users, err := s.birthdayRepo.UsersWithBirthdayOn(int(now.Month()), now.Day())
+	if err != nil {
+		return 0, err
+	}
+
+	issued := 0
+	yearStart := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	for _, user := range users {
+		allowed, err := s.consents.Allowed(user.Email, models.ConsentChannelEmail)
+		if err != nil {
+			return issued, err
+		}
+		if !allowed {
+			continue
+		}
+		restaurantIDs, err := s.birthdayRepo.CustomerRestaurantIDs(user.Email)
+		if err != nil {
+			return issued, err
+		}
+		for _, restaurantID := range restaurantIDs {
+			ok, err := s.issueReward(restaurantID, &user, now, yearStart)
+			if err != nil {
+				log.Printf("birthdays: restaurant %d, %s: %v", restaurantID, user.Email, err)
+				continue
+			}
+			if ok {
+				issued++
+			}
+		}
+	}
+	return issued, nil
+}
+
+// issueReward issues one restaurant's reward to one customer, reporting
+// whether a coupon actually went out.
+func (s *BirthdayService) issueReward(restaurantID uint, user *models.User, now, yearStart time.Time) (bool, error) {
+	template, err := s.Template(restaurantID)
+	if err != nil {
+		return false, err
+	}
+	if !template.Enabled {
+		return false, nil
+	}
+	count, err := s.couponRepo.CountBySource(restaurantID, user.Email, models.CouponSourceBirthday, yearStart)
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil // already greeted this year
+	}
+
+	coupon := &models.Coupon{
+		Code:          "BDAY-" + strings.ToUpper(strings.ReplaceAll(models.NewUUID(), "-", "")[:8]),
+		RestaurantID:  restaurantID,
+		CustomerEmail: user.Email,
+		DiscountPct:   template.DiscountPct,
+		Source:        models.CouponSourceBirthday,
+		ExpiresAt:     now.UTC().AddDate(0, 0, template.ValidDays),
+	}
+	if err := s.couponRepo.Create(coupon); err != nil {
+		return false, err
+	}
+
+	replacer := strings.NewReplacer(
+		"{{name}}", user.Name,
+		"{{code}}", coupon.Code,
+		"{{discount}}", strconv.Itoa(template.DiscountPct),
+	)
+	payload, err := json.Marshal(map[string]interface{}{
+		"to":      user.Email,
+		"subject": replacer.Replace(template.Subject),
+		"text":    replacer.Replace(template.Body),
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := s.jobs.Enqueue(&models.Job{Type: "email.send", Payload: string(payload)}); err != nil {
+		return false, fmt.Errorf("queueing greeting: %w", err)
+	}
+	return true, nil
+}
+
+// Start runs the daily issue loop until ctx is cancelled. It checks
+// hourly and runs once per calendar day, so restarts during the day do
+// not re-issue (RunOnce is idempotent anyway).
+func (s *BirthdayService) Start(ctx context.Context) {
+	run := func(now time.Time) {
+		issued, err := s.RunOnce(now)
+		if err != nil {
+			log.Printf("birthdays: daily run: %v", err)
+			return
+		}
+		if issued > 0 {
+			log.Printf("birthdays: issued %d reward(s)", issued)
+		}
+	}
+	now := time.Now().UTC()
+	run(now)
+	lastDay := now.Format("2006-01-02")
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now().UTC()
+			if day := now.Format("2006-01-02"); day != lastDay {
+				run(now)
+				lastDay = day
+			}
+		}
+	}
+}
+
+func fillTemplateDefaults(t *models.BirthdayTemplate) {
+	if t.Subject == "" {
+		t.Subject = defaultBirthdaySubject
+	}
+	if t.Body == "" {
+		t.Body = defaultBirthdayBody
+	}
+	if t.DiscountPct == 0 {
+		t.DiscountPct = defaultBirthdayDiscountPct
+	}
+	if t.ValidDays == 0 {
+		t.ValidDays = defaultBirthdayValidDays
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestRunOnceIssuesBirthdayRewardOncePerYear(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	now := time.Date(2026, time.August, 29, 9, 0, 0, 0, time.UTC)
+	birthday := time.Date(1990, time.August, 29, 0, 0, 0, 0, time.UTC)
+	optedIn := models.User{Name: "Ana", Email: "ana@example.com", Password: "x", Role: models.RoleCustomer, Birthday: &birthday}
+	optedOut := models.User{Name: "Ben", Email: "ben@example.com", Password: "x", Role: models.RoleCustomer, Birthday: &birthday}
+	for _, u := range []*models.User{&optedIn, &optedOut} {
+		if err := db.Create(u).Error; err != nil {
+			t.Fatalf("creating user: %v", err)
+		}
+		if err := db.Create(&models.Order{RestaurantID: 1, CustomerEmail: u.Email, Total: 9000}).Error; err != nil {
+			t.Fatalf("creating order: %v", err)
+		}
+	}
+
+	consentService := NewConsentService(repositories.NewConsentRepository(db))
+	if _, err := consentService.Set("ana@example.com", models.ConsentChannelEmail, true, "test"); err != nil {
+		t.Fatalf("Set consent: %v", err)
+	}
+
+	couponRepo := repositories.NewCouponRepository(db)
+	jobRepo := repositories.NewJobRepository(db)
+	svc := NewBirthdayService(repositories.NewBirthdayRepository(db), couponRepo, consentService, jobRepo)
+
+	// Nothing goes out while greetings are disabled (the default).
+	issued, err := svc.RunOnce(now)
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if issued != 0 {
+		t.Fatalf("expected no rewards while disabled, got %d", issued)
+	}
+
+	if _, err := svc.UpdateTemplate(1, true, "", "", 15, 30); err != nil {
+		t.Fatalf("UpdateTemplate: %v", err)
+	}
+	issued, err = svc.RunOnce(now)
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if issued != 1 {
+		t.Fatalf("expected one reward (Ben opted out), got %d", issued)
+	}
+
+	coupons, err := svc.Coupons("ana@example.com")
+	if err != nil {
+		t.Fatalf("Coupons: %v", err)
+	}
+	if len(coupons) != 1 || coupons[0].DiscountPct != 15 || coupons[0].Source != models.CouponSourceBirthday {
+		t.Fatalf("unexpected coupons: %+v", coupons)
+	}
+	if coupons[0].ExpiresAt.Before(now.AddDate(0, 0, 29)) {
+		t.Fatalf("expected ~30 days of validity, got %v", coupons[0].ExpiresAt)
+	}
+
+	var jobs int64
+	if err := db.Model(&models.Job{}).Where("type = ?", "email.send").Count(&jobs).Error; err != nil {
+		t.Fatalf("counting jobs: %v", err)
+	}
+	if jobs != 1 {
+		t.Fatalf("expected one greeting email job, got %d", jobs)
+	}
+
+	// Re-running the same day (or later the same year) must not double up.
+	issued, err = svc.RunOnce(now)
+	if err != nil {
+		t.Fatalf("RunOnce (repeat): %v", err)
+	}
+	if issued != 0 {
+		t.Fatalf("expected no repeat rewards, got %d", issued)
+	}
+}
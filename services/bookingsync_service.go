@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/booking"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// bookingSyncInterval is how often connected platforms are polled.
+// Webhooks land bookings immediately; the poll catches whatever they
+// miss.
+const bookingSyncInterval = 15 * time.Minute
+
+// bookingBackfillWindow bounds the first fetch of a freshly connected
+// account so it does not pull the venue's entire history.
+const bookingBackfillWindow = 7 * 24 * time.Hour
+
+// BookingSyncService keeps reservations in step with external booking
+// platforms: their bookings are imported with source attribution, ours
+// are mirrored back so the platform's availability stays honest.
+type BookingSyncService struct {
+	bookingAccountRepo BookingAccountRepo
+	reservationRepo    ReservationRepo
+	restaurantRepo     RestaurantRepo
+	provider           booking.Provider
+}
+
+// NewBookingSyncService builds a BookingSyncService.
+func NewBookingSyncService(bookingAccountRepo BookingAccountRepo, reservationRepo ReservationRepo, restaurantRepo RestaurantRepo, provider booking.Provider) *BookingSyncService {
+	return &BookingSyncService{
+		bookingAccountRepo: bookingAccountRepo,
+		reservationRepo:    reservationRepo,
+		restaurantRepo:     restaurantRepo,
+		provider:           provider,
+	}
+}
+
+// Connect links a restaurant to a platform, or refreshes the
+// credentials of an existing link.
+func (s *BookingSyncService) Connect(restaurantID uint, platform, apiKey, venueID string) (*models.BookingAccount, error) {
+	switch platform {
+	case models.BookingPlatformOpenTable, models.BookingPlatformTheFork:
+	default:
+		return nil, Validation("invalid_platform", "platform must be opentable or thefork")
+	}
+	if apiKey == "" || venueID == "" {
+		return nil, Validation("missing_credentials", "an API key and venue id are required")
+	}
+
+	account, err := s.bookingAccountRepo.GetByRestaurantAndPlatform(restaurantID, platform)
+	if err != nil {
+		account = &models.BookingAccount{RestaurantID: restaurantID, Platform: platform, APIKey: apiKey, ExternalVenueID: venueID}
+		if err := s.bookingAccountRepo.Create(account); err != nil {
+			return nil, err
+		}
+		return account, nil
+	}
+	account.APIKey = apiKey
+	account.ExternalVenueID = venueID
+	if err := s.bookingAccountRepo.Update(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// Accounts returns a restaurant's connected platforms.
+func (s *BookingSyncService) Accounts(restaurantID uint) ([]models.BookingAccount, error) {
+	return s.bookingAccountRepo.GetByRestaurantID(restaurantID)
+}
+
+// SyncRestaurant runs one sync pass over a restaurant's accounts and
+// returns how many bookings were imported or updated.
+func (s *BookingSyncService) SyncRestaurant(restaurantID uint, now time.Time) (int, error) {
+	accounts, err := s.bookingAccountRepo.GetByRestaurantID(restaurantID)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for i := range accounts {
+		n, err := s.syncAccount(&accounts[i], now)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// RunOnce syncs every connected account. Per-account failures are
+// recorded on the account and do not stop the rest.
+func (s *BookingSyncService) RunOnce(now time.Time) (int, error) {
+	accounts, err := s.bookingAccountRepo.List()
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for i := range accounts {
+		n, err := s.syncAccount(&accounts[i], now)
+		total += n
+		if err != nil {
+			log.Printf("booking sync: %s account %d: %v", accounts[i].Platform, accounts[i].ID, err)
+		}
+	}
+	return total, nil
+}
+
+// syncAccount pulls the platform's changes and mirrors our direct
+// bookings back, then stamps the outcome on the account.
+func (s *BookingSyncService) syncAccount(account *models.BookingAccount, now time.Time) (int, error) {
+	since := now.Add(-bookingBackfillWindow)
+	if account.LastSyncAt != nil {
+		since = *account.LastSyncAt
+	}
+
+	synced := 0
+	externals, err := s.provider.FetchReservations(account.Platform, account.APIKey, account.ExternalVenueID, since)
+	if err == nil {
+		for _, ext := range externals {
+			if _, importErr := s.importExternal(account, ext); importErr != nil {
+				err = importErr
+				break
+			}
+			synced++
+		}
+	}
+	if err == nil {
+		err = s.pushDirect(account, since)
+	}
+
+	account.LastSyncAt = &now
+	if err != nil {
+		account.LastSyncStatus = "error"
+		account.LastSyncError = err.Error()
+	} else {
+		account.LastSyncStatus = "ok"
+		account.LastSyncError = ""
+	}
+	if updateErr := s.bookingAccountRepo.Update(account); updateErr != nil && err == nil {
+		err = updateErr
+	}
+	return synced, err
+}
+
+// pushDirect mirrors direct bookings created since the last sync onto
+// the platform.
+func (s *BookingSyncService) pushDirect(account *models.BookingAccount, since time.Time) error {
+	reservations, err := s.reservationRepo.GetDirectCreatedSince(account.RestaurantID, since)
+	if err != nil {
+		return err
+	}
+	for _, res := range reservations {
+		ext := booking.ExternalReservation{
+			Ref:           fmt.Sprintf("wets-%d", res.ID),
+			CustomerName:  res.CustomerName,
+			CustomerEmail: res.CustomerEmail,
+			PartySize:     res.PartySize,
+			Date:          res.Date,
+			Status:        res.Status,
+		}
+		if err := s.provider.PushReservation(account.Platform, account.APIKey, account.ExternalVenueID, ext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportWebhook lands one booking pushed by a platform. The webhook key
+// identifies the account the way a sensor's key identifies its unit.
+func (s *BookingSyncService) ImportWebhook(webhookKey string, ext booking.ExternalReservation) (*models.Reservation, error) {
+	account, err := s.bookingAccountRepo.GetByWebhookKey(webhookKey)
+	if err != nil {
+		return nil, fromRepo(err, "account_not_found", "unknown webhook key")
+	}
+	return s.importExternal(account, ext)
+}
+
+// importExternal upserts one platform booking. New bookings that no
+// longer fit the room come in pending instead of confirmed, so the
+// front of house decides the overbooking rather than the sync.
+func (s *BookingSyncService) importExternal(account *models.BookingAccount, ext booking.ExternalReservation) (*models.Reservation, error) {
+	if ext.Ref == "" {
+		return nil, Validation("missing_ref", "an external booking reference is required")
+	}
+	if ext.PartySize <= 0 {
+		return nil, Validation("invalid_party_size", "party size must be positive")
+	}
+
+	existing, err := s.reservationRepo.GetBySourceRef(account.RestaurantID, account.Platform, ext.Ref)
+	if err == nil {
+		if ext.Status == models.ReservationCancelled {
+			existing.Status = models.ReservationCancelled
+		}
+		existing.CustomerName = ext.CustomerName
+		existing.CustomerEmail = ext.CustomerEmail
+		existing.PartySize = ext.PartySize
+		existing.Date = ext.Date.UTC()
+		if err := s.reservationRepo.Update(existing); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	if ext.Status == models.ReservationCancelled {
+		// A cancellation for a booking we never saw is nothing to keep.
+		return nil, NotFound("reservation_not_found", "reservation not found")
+	}
+
+	status := models.ReservationConfirmed
+	fits, err := s.fits(account.RestaurantID, ext.Date, ext.PartySize)
+	if err != nil {
+		return nil, err
+	}
+	if !fits {
+		status = models.ReservationPending
+	}
+
+	reservation := &models.Reservation{
+		RestaurantID:  account.RestaurantID,
+		Source:        account.Platform,
+		ExternalRef:   ext.Ref,
+		CustomerName:  ext.CustomerName,
+		CustomerEmail: ext.CustomerEmail,
+		PartySize:     ext.PartySize,
+		Date:          ext.Date.UTC(),
+		Status:        status,
+	}
+	if err := s.reservationRepo.Create(reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// fits reports whether a party still fits the room for a full turn
+// around the given time, against everything already on the books.
+func (s *BookingSyncService) fits(restaurantID uint, date time.Time, partySize int) (bool, error) {
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return false, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	turn := time.Duration(restaurant.TurnMinutes) * time.Minute
+	if turn <= 0 {
+		turn = 90 * time.Minute
+	}
+
+	start := date.UTC()
+	reservations, err := s.reservationRepo.GetByRestaurantAndRange(restaurantID, start.Add(-turn), start.Add(turn))
+	if err != nil {
+		return false, err
+	}
+	booked := 0
+	for _, res := range reservations {
+		if res.Status == models.ReservationCancelled {
+			continue
+		}
+		if res.Date.Before(start.Add(turn)) && res.Date.Add(turn).After(start) {
+			booked += res.PartySize
+		}
+	}
+	return booked+partySize <= restaurant.Seats, nil
+}
+
+// Start runs the periodic sync until the context is cancelled.
+func (s *BookingSyncService) Start(ctx context.Context) {
+	run := func(now time.Time) {
+		if n, err := s.RunOnce(now); err != nil {
+			log.Printf("booking sync: %v", err)
+		} else if n > 0 {
+			log.Printf("booking sync: imported %d booking(s)", n)
+		}
+	}
+	run(time.Now().UTC())
+
+	ticker := time.NewTicker(bookingSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run(time.Now().UTC())
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/booking"
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// fakeBookingProvider serves a scripted fetch and records pushes.
+type fakeBookingProvider struct {
+	fetched []booking.ExternalReservation
+	pushed  []booking.ExternalReservation
+}
+
+func (f *fakeBookingProvider) FetchReservations(platform, apiKey, venueID string, since time.Time) ([]booking.ExternalReservation, error) {
+	return f.fetched, nil
+}
+
+func (f *fakeBookingProvider) PushReservation(platform, apiKey, venueID string, res booking.ExternalReservation) error {
+	f.pushed = append(f.pushed, res)
+	return nil
+}
+
+func TestBookingSyncImportsAndMirrors(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Sync", Email: "sync@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Dos Canales", UserID: owner.ID, Seats: 10}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	provider := &fakeBookingProvider{}
+	svc := NewBookingSyncService(repositories.NewBookingAccountRepository(db), repositories.NewReservationRepository(db), repositories.NewRestaurantRepository(db), provider)
+
+	account, err := svc.Connect(restaurant.ID, models.BookingPlatformOpenTable, "secret", "venue-9")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if account.WebhookKey == "" {
+		t.Fatal("expected the account to get a webhook key")
+	}
+	if _, err := svc.Connect(restaurant.ID, "resy", "k", "v"); err == nil {
+		t.Fatal("expected an unknown platform to be rejected")
+	}
+
+	// A direct booking made before the sync gets mirrored out.
+	direct := &models.Reservation{RestaurantID: restaurant.ID, CustomerName: "Local", PartySize: 2, Date: time.Now().UTC().Add(48 * time.Hour), Status: models.ReservationConfirmed}
+	if err := db.Create(direct).Error; err != nil {
+		t.Fatalf("create direct booking: %v", err)
+	}
+
+	when := time.Now().UTC().Add(72 * time.Hour).Truncate(time.Hour)
+	provider.fetched = []booking.ExternalReservation{
+		{Ref: "OT-1", CustomerName: "Visitante", CustomerEmail: "v@example.com", PartySize: 4, Date: when, Status: "confirmed"},
+	}
+	synced, err := svc.SyncRestaurant(restaurant.ID, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if synced != 1 {
+		t.Fatalf("expected one imported booking, got %d", synced)
+	}
+	if len(provider.pushed) != 1 || provider.pushed[0].CustomerName != "Local" {
+		t.Fatalf("expected the direct booking to be pushed, got %+v", provider.pushed)
+	}
+
+	imported, err := repositories.NewReservationRepository(db).GetBySourceRef(restaurant.ID, models.BookingPlatformOpenTable, "OT-1")
+	if err != nil {
+		t.Fatalf("load imported booking: %v", err)
+	}
+	if imported.Status != models.ReservationConfirmed || imported.Source != models.BookingPlatformOpenTable {
+		t.Fatalf("expected a confirmed attributed booking, got %+v", imported)
+	}
+
+	// The same reference syncs as an update, not a duplicate.
+	provider.fetched[0].PartySize = 5
+	if _, err := svc.SyncRestaurant(restaurant.ID, time.Now().UTC()); err != nil {
+		t.Fatalf("resync: %v", err)
+	}
+	var count int64
+	if err := db.Model(&models.Reservation{}).Where("external_ref = ?", "OT-1").Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("expected one row for OT-1, got %d (%v)", count, err)
+	}
+
+	// A platform cancellation lands through the webhook.
+	if _, err := svc.ImportWebhook(account.WebhookKey, booking.ExternalReservation{Ref: "OT-1", CustomerName: "Visitante", PartySize: 5, Date: when, Status: models.ReservationCancelled}); err != nil {
+		t.Fatalf("webhook cancel: %v", err)
+	}
+	imported, err = repositories.NewReservationRepository(db).GetBySourceRef(restaurant.ID, models.BookingPlatformOpenTable, "OT-1")
+	if err != nil {
+		t.Fatalf("reload booking: %v", err)
+	}
+	if imported.Status != models.ReservationCancelled {
+		t.Fatalf("expected the cancellation to apply, got %s", imported.Status)
+	}
+
+	if _, err := svc.ImportWebhook("bad-key", booking.ExternalReservation{Ref: "OT-2", PartySize: 2, Date: when, Status: "confirmed"}); err == nil {
+		t.Fatal("expected an unknown webhook key to be rejected")
+	}
+}
+
+func TestBookingSyncMarksOverbookedImportsPending(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Full", Email: "full@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Chico", UserID: owner.ID, Seats: 4}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	when := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	existing := &models.Reservation{RestaurantID: restaurant.ID, CustomerName: "Primero", PartySize: 3, Date: when, Status: models.ReservationConfirmed}
+	if err := db.Create(existing).Error; err != nil {
+		t.Fatalf("create existing booking: %v", err)
+	}
+
+	provider := &fakeBookingProvider{}
+	svc := NewBookingSyncService(repositories.NewBookingAccountRepository(db), repositories.NewReservationRepository(db), repositories.NewRestaurantRepository(db), provider)
+	account, err := svc.Connect(restaurant.ID, models.BookingPlatformTheFork, "secret", "venue-2")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	// Two more seats do not fit a four-seat room with three taken.
+	imported, err := svc.ImportWebhook(account.WebhookKey, booking.ExternalReservation{Ref: "TF-1", CustomerName: "Tarde", PartySize: 2, Date: when, Status: "confirmed"})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if imported.Status != models.ReservationPending {
+		t.Fatalf("expected the overbooked import to come in pending, got %s", imported.Status)
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// BrandService groups an owner's restaurants under brands and resolves
+// each location's effective branding: its own overrides first, the
+// brand's defaults for the rest.
+type BrandService struct {
+	brandRepo      BrandRepo
+	restaurantRepo RestaurantRepo
+}
+
+// NewBrandService builds a BrandService.
+func NewBrandService(brandRepo BrandRepo, restaurantRepo RestaurantRepo) *BrandService {
+	return &BrandService{brandRepo: brandRepo, restaurantRepo: restaurantRepo}
+}
+
+// CreateBrand creates a brand for the owner.
+func (s *BrandService) CreateBrand(userID uint, name, logoURL, webhookURL, emailTemplate string) (*models.Brand, error) {
+	name = utils.SanitizeText(name)
+	if name == "" {
+		return nil, Validation("missing_name", "a brand name is required")
+	}
+
+	brand := &models.Brand{
+		UserID:        userID,
+		Name:          name,
+		LogoURL:       logoURL,
+		WebhookURL:    webhookURL,
+		EmailTemplate: emailTemplate,
+	}
+	if err := s.brandRepo.Create(brand); err != nil {
+		return nil, err
+	}
+	return brand, nil
+}
+
+// Brands returns an owner's brands.
+func (s *BrandService) Brands(userID uint) ([]models.Brand, error) {
+	return s.brandRepo.GetByUserID(userID)
+}
+
+// ownedBrand returns the brand if the caller owns it.
+func (s *BrandService) ownedBrand(id, callerID uint) (*models.Brand, error) {
+	brand, err := s.brandRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "brand_not_found", "brand not found")
+	}
+	if brand.UserID != callerID {
+		return nil, Forbidden("forbidden", "forbidden")
+	}
+	return brand, nil
+}
+
+// UpdateBrand saves brand-level settings. Empty strings clear a
+// setting back to nothing rather than being skipped — a brand default
+// can be removed.
+func (s *BrandService) UpdateBrand(id, callerID uint, name, logoURL, webhookURL, emailTemplate string) (*models.Brand, error) {
+	brand, err := s.ownedBrand(id, callerID)
+	if err != nil {
+		return nil, err
+	}
+	if name = utils.SanitizeText(name); name != "" {
+		brand.Name = name
+	}
+	brand.LogoURL = logoURL
+	brand.WebhookURL = webhookURL
+	brand.EmailTemplate = emailTemplate
+	if err := s.brandRepo.Update(brand); err != nil {
+		return nil, err
+	}
+	return brand, nil
+}
+
+// AssignRestaurant puts a restaurant under a brand, or takes it out
+// with a zero brandID. Both must belong to the caller.
+func (s *BrandService) AssignRestaurant(restaurantID, brandID, callerID uint) (*models.Restaurant, error) {
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	if restaurant.UserID != callerID {
+		return nil, Forbidden("forbidden", "forbidden")
+	}
+	if brandID != 0 {
+		if _, err := s.ownedBrand(brandID, callerID); err != nil {
+			return nil, err
+		}
+	}
+
+	restaurant.BrandID = brandID
+	if err := s.restaurantRepo.Update(restaurant); err != nil {
+		return nil, err
+	}
+	return restaurant, nil
+}
+
+// SetOverrides saves a restaurant's branding overrides. Empty strings
+// fall back to the brand's defaults.
+func (s *BrandService) SetOverrides(restaurantID, callerID uint, logoURL, webhookURL, emailTemplate string) (*models.Restaurant, error) {
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	if restaurant.UserID != callerID {
+		return nil, Forbidden("forbidden", "forbidden")
+	}
+
+	restaurant.LogoURL = logoURL
+	restaurant.WebhookURL = webhookURL
+	restaurant.EmailTemplate = emailTemplate
+	if err := s.restaurantRepo.Update(restaurant); err != nil {
+		return nil, err
+	}
+	return restaurant, nil
+}
+
+// BrandSettings is a restaurant's effective branding after
+// inheritance.
+type BrandSettings struct {
+	BrandID       uint   `json:"brand_id,omitempty"`
+	BrandName     string `json:"brand_name,omitempty"`
+	LogoURL       string `json:"logo_url"`
+	WebhookURL    string `json:"webhook_url"`
+	EmailTemplate string `json:"email_template"`
+}
+
+// EffectiveSettings resolves a restaurant's branding: the location's
+// own values where set, the brand's defaults everywhere else.
+func (s *BrandService) EffectiveSettings(restaurantID uint) (*BrandSettings, error) {
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+
+	settings := &BrandSettings{
+		LogoURL:       restaurant.LogoURL,
+		WebhookURL:    restaurant.WebhookURL,
+		EmailTemplate: restaurant.EmailTemplate,
+	}
+	if restaurant.BrandID == 0 {
+		return settings, nil
+	}
+	brand, err := s.brandRepo.GetByID(restaurant.BrandID)
+	if err != nil {
+		return nil, fromRepo(err, "brand_not_found", "brand not found")
+	}
+	settings.BrandID = brand.ID
+	settings.BrandName = brand.Name
+	if settings.LogoURL == "" {
+		settings.LogoURL = brand.LogoURL
+	}
+	if settings.WebhookURL == "" {
+		settings.WebhookURL = brand.WebhookURL
+	}
+	if settings.EmailTemplate == "" {
+		settings.EmailTemplate = brand.EmailTemplate
+	}
+	return settings, nil
+}
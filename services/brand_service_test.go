@@ -0,0 +1,117 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestBrandSettingsInheritAndOverride(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Bo", Email: "bo@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Sucursal Centro", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewBrandService(repositories.NewBrandRepository(db), repositories.NewRestaurantRepository(db))
+
+	brand, err := svc.CreateBrand(owner.ID, "Fuego", "https://cdn.example.com/fuego.png", "https://hooks.example.com/fuego", "Hola {{name}}")
+	if err != nil {
+		t.Fatalf("create brand: %v", err)
+	}
+	if _, err := svc.CreateBrand(owner.ID, "  ", "", "", ""); err == nil {
+		t.Fatal("expected a nameless brand to be rejected")
+	}
+
+	// Standalone restaurants resolve to their own (empty) settings.
+	settings, err := svc.EffectiveSettings(restaurant.ID)
+	if err != nil {
+		t.Fatalf("standalone settings: %v", err)
+	}
+	if settings.BrandID != 0 || settings.LogoURL != "" {
+		t.Fatalf("expected empty standalone settings, got %+v", settings)
+	}
+
+	if _, err := svc.AssignRestaurant(restaurant.ID, brand.ID, owner.ID); err != nil {
+		t.Fatalf("assign brand: %v", err)
+	}
+	settings, err = svc.EffectiveSettings(restaurant.ID)
+	if err != nil {
+		t.Fatalf("inherited settings: %v", err)
+	}
+	if settings.BrandName != "Fuego" || settings.LogoURL != "https://cdn.example.com/fuego.png" || settings.EmailTemplate != "Hola {{name}}" {
+		t.Fatalf("expected the brand's defaults, got %+v", settings)
+	}
+
+	// A location override wins over the brand for that field only.
+	if _, err := svc.SetOverrides(restaurant.ID, owner.ID, "https://cdn.example.com/centro.png", "", ""); err != nil {
+		t.Fatalf("set overrides: %v", err)
+	}
+	settings, err = svc.EffectiveSettings(restaurant.ID)
+	if err != nil {
+		t.Fatalf("overridden settings: %v", err)
+	}
+	if settings.LogoURL != "https://cdn.example.com/centro.png" {
+		t.Fatalf("expected the location's logo, got %v", settings.LogoURL)
+	}
+	if settings.WebhookURL != "https://hooks.example.com/fuego" {
+		t.Fatalf("expected the brand's webhook to survive, got %v", settings.WebhookURL)
+	}
+
+	// A zero brand id takes the restaurant back out.
+	if _, err := svc.AssignRestaurant(restaurant.ID, 0, owner.ID); err != nil {
+		t.Fatalf("clear brand: %v", err)
+	}
+	settings, err = svc.EffectiveSettings(restaurant.ID)
+	if err != nil {
+		t.Fatalf("cleared settings: %v", err)
+	}
+	if settings.BrandID != 0 || settings.WebhookURL != "" {
+		t.Fatalf("expected the brand's defaults to stop applying, got %+v", settings)
+	}
+}
+
+func TestBrandOwnershipIsEnforced(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Ana", Email: "ana@example.com", Password: "x", Role: models.RoleOwner}
+	rival := &models.User{Name: "Eve", Email: "eve@example.com", Password: "x", Role: models.RoleOwner}
+	for _, u := range []*models.User{owner, rival} {
+		if err := db.Create(u).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+	restaurant := &models.Restaurant{Name: "Propio", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewBrandService(repositories.NewBrandRepository(db), repositories.NewRestaurantRepository(db))
+	brand, err := svc.CreateBrand(rival.ID, "Ajeno", "", "", "")
+	if err != nil {
+		t.Fatalf("create brand: %v", err)
+	}
+
+	if _, err := svc.AssignRestaurant(restaurant.ID, brand.ID, owner.ID); err == nil {
+		t.Fatal("expected joining someone else's brand to be rejected")
+	}
+	if _, err := svc.UpdateBrand(brand.ID, owner.ID, "Mio", "", "", ""); err == nil {
+		t.Fatal("expected updating someone else's brand to be rejected")
+	}
+	if _, err := svc.SetOverrides(restaurant.ID, rival.ID, "x", "", ""); err == nil {
+		t.Fatal("expected overriding someone else's restaurant to be rejected")
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// CateringService implements the catering inquiry pipeline: structured
+// inquiries from customers, quotes built from menu items, and
+// confirmation by the owner.
+type CateringService struct {
+	cateringRepo CateringRepo
+	menuRepo     MenuRepo
+}
+
+// NewCateringService builds a CateringService.
+func NewCateringService(cateringRepo CateringRepo, menuRepo MenuRepo) *CateringService {
+	return &CateringService{cateringRepo: cateringRepo, menuRepo: menuRepo}
+}
+
+// CreateInquiry records a catering inquiry against a restaurant. Budget
+// is optional; zero means the customer did not state one.
+func (s *CateringService) CreateInquiry(restaurantID uint, name, email string, eventDate time.Time, headcount int, budget float64, preferences string) (*models.CateringRequest, error) {
+	if name == "" || email == "" {
+		return nil, Validation("missing_contact", "a name and email are required")
+	}
+	if eventDate.Before(time.Now()) {
+		return nil, Validation("invalid_event_date", "the event date must be in the future")
+	}
+	if headcount <= 0 {
+		return nil, Validation("invalid_headcount", "headcount must be positive")
+	}
+	if budget < 0 {
+		return nil, Validation("invalid_budget", "budget cannot be negative")
+	}
+
+	request := &models.CateringRequest{
+		RestaurantID:  restaurantID,
+		CustomerName:  utils.SanitizeText(name),
+		CustomerEmail: utils.NormalizeEmail(email),
+		EventDate:     eventDate.UTC(),
+		Headcount:     headcount,
+		Budget:        budget,
+		Preferences:   utils.SanitizeText(preferences),
+		Status:        models.CateringNew,
+	}
+	if err := s.cateringRepo.Create(request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// GetRequestByID returns the catering request with the given id.
+func (s *CateringService) GetRequestByID(id uint) (*models.CateringRequest, error) {
+	request, err := s.cateringRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "catering_request_not_found", "catering request not found")
+	}
+	return request, nil
+}
+
+// GetRequestsByRestaurantID returns a restaurant's catering requests,
+// newest first.
+func (s *CateringService) GetRequestsByRestaurantID(restaurantID uint) ([]models.CateringRequest, error) {
+	return s.cateringRepo.GetByRestaurantID(restaurantID)
+}
+
+// QuoteLineInput is one menu item on a quote being built.
+type QuoteLineInput struct {
+	MenuItemID uint `json:"menu_item_id"`
+	Quantity   int  `json:"quantity"`
+}
+
+// Quote attaches a quote built from the restaurant's menu items to a
+// request and moves it to quoted. Names and prices are snapshotted onto
+// the quote lines; re-quoting replaces the previous lines. Confirmed
+// requests cannot be re-quoted.
+func (s *CateringService) Quote(id uint, lines []QuoteLineInput) (*models.CateringRequest, error) {
+	request, err := s.cateringRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "catering_request_not_found", "catering request not found")
+	}
+	if request.Status == models.CateringConfirmed {
+		return nil, Conflict("already_confirmed", "a confirmed request cannot be re-quoted")
+	}
+	if len(lines) == 0 {
+		return nil, Validation("quote_needs_lines", "a quote needs at least one line")
+	}
+
+	var quoteLines []models.CateringQuoteLine
+	var total float64
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return nil, Validation("invalid_quantity", "quantities must be positive")
+		}
+		item, err := s.menuRepo.GetByID(line.MenuItemID)
+		if err != nil {
+			return nil, fromRepo(err, "menu_item_not_found", "menu item not found")
+		}
+		if item.RestaurantID != request.RestaurantID {
+			return nil, Validation("foreign_menu_item", "menu item belongs to another restaurant")
+		}
+		subtotal := item.Price * float64(line.Quantity)
+		quoteLines = append(quoteLines, models.CateringQuoteLine{
+			MenuItemID: item.ID,
+			Name:       item.Name,
+			UnitPrice:  item.Price,
+			Quantity:   line.Quantity,
+			Subtotal:   subtotal,
+		})
+		total += subtotal
+	}
+
+	if err := s.cateringRepo.ReplaceLines(request.ID, quoteLines); err != nil {
+		return nil, err
+	}
+	request.Lines = nil // Update must not re-save the stale preloaded lines
+	request.Status = models.CateringQuoted
+	request.QuoteTotal = total
+	if err := s.cateringRepo.Update(request); err != nil {
+		return nil, err
+	}
+	return s.GetRequestByID(request.ID)
+}
+
+// SetStatus moves a catering request through the pipeline. Confirming
+// requires a quote to exist first; declining is allowed at any point.
+func (s *CateringService) SetStatus(id uint, status string) (*models.CateringRequest, error) {
+	if !models.ValidCateringStatus(status) {
+		return nil, Validation("invalid_status", "unknown catering status")
+	}
+	request, err := s.cateringRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "catering_request_not_found", "catering request not found")
+	}
+	if status == models.CateringConfirmed && request.Status != models.CateringQuoted {
+		return nil, Conflict("not_quoted", "only a quoted request can be confirmed")
+	}
+	request.Status = status
+	if err := s.cateringRepo.Update(request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestCateringPipelineQuotesFromMenuItems(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	restaurant := models.Restaurant{Name: "Testaurant", UserID: 1}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("creating restaurant: %v", err)
+	}
+	empanadas := models.MenuItem{RestaurantID: restaurant.ID, Name: "Empanadas", Price: 2500, Available: true}
+	if err := db.Create(&empanadas).Error; err != nil {
+		t.Fatalf("creating menu item: %v", err)
+	}
+	other := models.Restaurant{Name: "Other", UserID: 2}
+	if err := db.Create(&other).Error; err != nil {
+		t.Fatalf("creating other restaurant: %v", err)
+	}
+	foreign := models.MenuItem{RestaurantID: other.ID, Name: "Ceviche", Price: 9000, Available: true}
+	if err := db.Create(&foreign).Error; err != nil {
+		t.Fatalf("creating foreign menu item: %v", err)
+	}
+
+	svc := NewCateringService(repositories.NewCateringRepository(db), repositories.NewMenuRepository(db))
+
+	request, err := svc.CreateInquiry(restaurant.ID, "Ana", "ana@example.com",
+		time.Now().Add(30*24*time.Hour), 40, 120000, "vegetarian options please")
+	if err != nil {
+		t.Fatalf("CreateInquiry: %v", err)
+	}
+	if request.Status != models.CateringNew {
+		t.Fatalf("expected a new inquiry, got status %s", request.Status)
+	}
+
+	// Confirming before a quote exists is rejected.
+	if _, err := svc.SetStatus(request.ID, models.CateringConfirmed); err == nil {
+		t.Fatal("expected confirming an unquoted request to fail")
+	}
+
+	// Items of another restaurant cannot be quoted.
+	if _, err := svc.Quote(request.ID, []QuoteLineInput{{MenuItemID: foreign.ID, Quantity: 10}}); err == nil {
+		t.Fatal("expected quoting a foreign menu item to fail")
+	}
+
+	quoted, err := svc.Quote(request.ID, []QuoteLineInput{{MenuItemID: empanadas.ID, Quantity: 40}})
+	if err != nil {
+		t.Fatalf("Quote: %v", err)
+	}
+	if quoted.Status != models.CateringQuoted || quoted.QuoteTotal != 100000 {
+		t.Fatalf("expected a quoted request totalling 100000, got status %s total %.2f", quoted.Status, quoted.QuoteTotal)
+	}
+	if len(quoted.Lines) != 1 || quoted.Lines[0].UnitPrice != 2500 {
+		t.Fatalf("expected one snapshotted line at 2500, got %+v", quoted.Lines)
+	}
+
+	// Re-quoting replaces the lines instead of stacking them.
+	quoted, err = svc.Quote(request.ID, []QuoteLineInput{{MenuItemID: empanadas.ID, Quantity: 50}})
+	if err != nil {
+		t.Fatalf("Quote (again): %v", err)
+	}
+	if len(quoted.Lines) != 1 || quoted.QuoteTotal != 125000 {
+		t.Fatalf("expected the new quote to replace the old, got %d lines total %.2f", len(quoted.Lines), quoted.QuoteTotal)
+	}
+
+	confirmed, err := svc.SetStatus(request.ID, models.CateringConfirmed)
+	if err != nil {
+		t.Fatalf("SetStatus: %v", err)
+	}
+	if confirmed.Status != models.CateringConfirmed {
+		t.Fatalf("expected a confirmed request, got %s", confirmed.Status)
+	}
+	if _, err := svc.Quote(request.ID, []QuoteLineInput{{MenuItemID: empanadas.ID, Quantity: 1}}); err == nil {
+		t.Fatal("expected re-quoting a confirmed request to fail")
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// ComplianceService tracks arbitrary recurring obligations per
+// restaurant and escalates the ones that slip past their due date.
+type ComplianceService struct {
+	complianceRepo ComplianceRepo
+	restaurantRepo RestaurantRepo
+	staffRepo      StaffRepo
+	userRepo       UserRepo
+	jobs           JobEnqueuer
+}
+
+// NewComplianceService builds a ComplianceService.
+func NewComplianceService(complianceRepo ComplianceRepo, restaurantRepo RestaurantRepo, staffRepo StaffRepo, userRepo UserRepo, jobs JobEnqueuer) *ComplianceService {
+	return &ComplianceService{complianceRepo: complianceRepo, restaurantRepo: restaurantRepo, staffRepo: staffRepo, userRepo: userRepo, jobs: jobs}
+}
+
+// CreateItem registers a new obligation. A zero employeeID leaves it
+// with the owner; a positive recurDays makes it recur that many days
+// after each completion.
+func (s *ComplianceService) CreateItem(restaurantID uint, name, description string, employeeID uint, dueAt time.Time, recurDays int) (*models.ComplianceItem, error) {
+	name = utils.SanitizeText(name)
+	if name == "" {
+		return nil, Validation("missing_name", "the item name is required")
+	}
+	if dueAt.IsZero() {
+		return nil, Validation("missing_due_date", "a due date is required")
+	}
+	if recurDays < 0 {
+		return nil, Validation("invalid_recurrence", "recurrence cannot be negative")
+	}
+	if employeeID != 0 {
+		employee, err := s.staffRepo.GetEmployeeByID(employeeID)
+		if err != nil {
+			return nil, fromRepo(err, "employee_not_found", "assignee not found")
+		}
+		if employee.RestaurantID != restaurantID {
+			return nil, Validation("foreign_employee", "the assignee works for another restaurant")
+		}
+	}
+
+	item := &models.ComplianceItem{
+		RestaurantID: restaurantID,
+		Name:         name,
+		Description:  utils.SanitizeText(description),
+		EmployeeID:   employeeID,
+		DueAt:        dueAt.UTC(),
+		RecurDays:    recurDays,
+	}
+	if err := s.complianceRepo.Create(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// GetItemByID returns the item with the given id, completions included.
+func (s *ComplianceService) GetItemByID(id uint) (*models.ComplianceItem, error) {
+	item, err := s.complianceRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "item_not_found", "compliance item not found")
+	}
+	return item, nil
+}
+
+// Items returns a restaurant's obligations, soonest due first.
+func (s *ComplianceService) Items(restaurantID uint) ([]models.ComplianceItem, error) {
+	return s.complianceRepo.GetByRestaurantID(restaurantID)
+}
+
+// Complete signs off an item. Recurring items roll their due date
+// forward and reopen; one-off items close for good.
+func (s *ComplianceService) Complete(itemID, employeeID uint, notes string, now time.Time) (*models.ComplianceItem, error) {
+	item, err := s.complianceRepo.GetByID(itemID)
+	if err != nil {
+		return nil, fromRepo(err, "item_not_found", "compliance item not found")
+	}
+	if item.Done {
+		return nil, Conflict("already_done", "this item is already completed")
+	}
+
+	completion := &models.ComplianceCompletion{
+		ComplianceItemID: item.ID,
+		EmployeeID:       employeeID,
+		Notes:            utils.SanitizeText(notes),
+		CompletedAt:      now.UTC(),
+	}
+	if err := s.complianceRepo.CreateCompletion(completion); err != nil {
+		return nil, err
+	}
+
+	if item.RecurDays > 0 {
+		item.DueAt = now.UTC().AddDate(0, 0, item.RecurDays)
+	} else {
+		item.Done = true
+	}
+	item.EscalatedAt = nil
+	item.Completions = nil // Update must not re-save the stale preloaded completions
+	if err := s.complianceRepo.Update(item); err != nil {
+		return nil, err
+	}
+	return s.GetItemByID(item.ID)
+}
+
+// RunOnce escalates the items overdue on the given day and returns how
+// many notifications went out. Per-item failures are logged and skipped
+// so one bad record cannot hold up the rest.
+func (s *ComplianceService) RunOnce(now time.Time) (int, error) {
+	items, err := s.complianceRepo.OverdueUnescalated(now.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range items {
+		if err := s.escalate(&items[i], now); err != nil {
+			log.Printf("compliance: item %d: %v", items[i].ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// escalate queues one item's overdue notification to the restaurant's
+// owner and marks the item as escalated.
+func (s *ComplianceService) escalate(item *models.ComplianceItem, now time.Time) error {
+	restaurant, err := s.restaurantRepo.GetByID(item.RestaurantID)
+	if err != nil {
+		return err
+	}
+	owner, err := s.userRepo.GetByID(restaurant.UserID)
+	if err != nil {
+		return err
+	}
+
+	assignee := "the owner"
+	if item.EmployeeID != 0 {
+		if employee, err := s.staffRepo.GetEmployeeByID(item.EmployeeID); err == nil {
+			assignee = employee.Name
+		}
+	}
+	due := item.DueAt.Format("2006-01-02")
+	payload, err := json.Marshal(map[string]interface{}{
+		"to":      owner.Email,
+		"subject": fmt.Sprintf("%s: %s is overdue", restaurant.Name, item.Name),
+		"text":    fmt.Sprintf("%s at %s was due on %s (assigned to %s) and has not been signed off. Complete it or reschedule it to stop this escalation.", item.Name, restaurant.Name, due, assignee),
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.jobs.Enqueue(&models.Job{Type: "email.send", Payload: string(payload)}); err != nil {
+		return fmt.Errorf("queueing escalation: %w", err)
+	}
+
+	escalatedAt := now.UTC()
+	item.EscalatedAt = &escalatedAt
+	return s.complianceRepo.Update(item)
+}
+
+// Start runs the daily escalation loop until ctx is cancelled. It
+// checks hourly and runs once per calendar day, same as the birthday
+// and license loops.
+func (s *ComplianceService) Start(ctx context.Context) {
+	run := func(now time.Time) {
+		sent, err := s.RunOnce(now)
+		if err != nil {
+			log.Printf("compliance: daily run: %v", err)
+			return
+		}
+		if sent > 0 {
+			log.Printf("compliance: escalated %d overdue item(s)", sent)
+		}
+	}
+	now := time.Now().UTC()
+	run(now)
+	lastDay := now.Format("2006-01-02")
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now().UTC()
+			if day := now.Format("2006-01-02"); day != lastDay {
+				run(now)
+				lastDay = day
+			}
+		}
+	}
+}
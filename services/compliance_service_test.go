@@ -0,0 +1,147 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func newComplianceServiceForTest(t *testing.T) (*ComplianceService, *models.Restaurant, *gorm.DB) {
+	t.Helper()
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Olga", Email: "olga@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Obliged", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewComplianceService(
+		repositories.NewComplianceRepository(db),
+		repositories.NewRestaurantRepository(db),
+		repositories.NewStaffRepository(db),
+		repositories.NewUserRepository(db),
+		repositories.NewJobRepository(db),
+	)
+	return svc, restaurant, db
+}
+
+func TestRecurringItemRollsForwardOnCompletion(t *testing.T) {
+	svc, restaurant, _ := newComplianceServiceForTest(t)
+	now := time.Date(2026, 8, 29, 9, 0, 0, 0, time.UTC)
+
+	item, err := svc.CreateItem(restaurant.ID, "Pest control visit", "monthly service contract", 0, now.AddDate(0, 0, 3), 30)
+	if err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+
+	item, err = svc.Complete(item.ID, 0, "no findings", now)
+	if err != nil {
+		t.Fatalf("complete item: %v", err)
+	}
+	if item.Done {
+		t.Fatal("a recurring item must reopen after completion")
+	}
+	if want := now.AddDate(0, 0, 30); !item.DueAt.Equal(want) {
+		t.Fatalf("expected next due date %v, got %v", want, item.DueAt)
+	}
+	if len(item.Completions) != 1 || item.Completions[0].Notes != "no findings" {
+		t.Fatalf("expected one completion record, got %+v", item.Completions)
+	}
+
+	oneOff, err := svc.CreateItem(restaurant.ID, "Renew insurance", "", 0, now.AddDate(0, 0, 7), 0)
+	if err != nil {
+		t.Fatalf("create one-off item: %v", err)
+	}
+	oneOff, err = svc.Complete(oneOff.ID, 0, "", now)
+	if err != nil {
+		t.Fatalf("complete one-off item: %v", err)
+	}
+	if !oneOff.Done {
+		t.Fatal("a one-off item must close after completion")
+	}
+	if _, err := svc.Complete(oneOff.ID, 0, "", now); err == nil {
+		t.Fatal("expected a closed item to reject another sign-off")
+	}
+}
+
+func TestOverdueItemsEscalateOnce(t *testing.T) {
+	svc, restaurant, db := newComplianceServiceForTest(t)
+	now := time.Date(2026, 8, 29, 9, 0, 0, 0, time.UTC)
+
+	employee := &models.Employee{RestaurantID: restaurant.ID, Name: "Kim"}
+	if err := db.Create(employee).Error; err != nil {
+		t.Fatalf("create employee: %v", err)
+	}
+
+	overdue, err := svc.CreateItem(restaurant.ID, "Fire extinguisher check", "", employee.ID, now.AddDate(0, 0, -2), 90)
+	if err != nil {
+		t.Fatalf("create overdue item: %v", err)
+	}
+	if _, err := svc.CreateItem(restaurant.ID, "Hood cleaning", "", 0, now.AddDate(0, 0, 5), 90); err != nil {
+		t.Fatalf("create future item: %v", err)
+	}
+
+	sent, err := svc.RunOnce(now)
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if sent != 1 {
+		t.Fatalf("expected one escalation, got %d", sent)
+	}
+
+	var jobs []models.Job
+	if err := db.Where("type = ?", "email.send").Find(&jobs).Error; err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	if len(jobs) != 1 || !strings.Contains(jobs[0].Payload, "Kim") {
+		t.Fatalf("expected one escalation naming the assignee, got %+v", jobs)
+	}
+
+	// The next day's run must not escalate the same item again.
+	sent, err = svc.RunOnce(now.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("RunOnce again: %v", err)
+	}
+	if sent != 0 {
+		t.Fatalf("expected no repeat escalation, got %d", sent)
+	}
+
+	// Completing the item clears the escalation for the next due date.
+	item, err := svc.Complete(overdue.ID, employee.ID, "", now)
+	if err != nil {
+		t.Fatalf("complete item: %v", err)
+	}
+	if item.EscalatedAt != nil {
+		t.Fatal("expected completion to clear the escalation mark")
+	}
+}
+
+func TestCreateItemRejectsForeignAssignee(t *testing.T) {
+	svc, restaurant, db := newComplianceServiceForTest(t)
+
+	other := &models.Employee{RestaurantID: restaurant.ID + 1, Name: "Sam"}
+	if err := db.Create(other).Error; err != nil {
+		t.Fatalf("create employee: %v", err)
+	}
+
+	due := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := svc.CreateItem(restaurant.ID, "Grease trap service", "", other.ID, due, 0); err == nil {
+		t.Fatal("expected an assignee from another restaurant to be rejected")
+	}
+	if _, err := svc.CreateItem(restaurant.ID, "", "", 0, due, 0); err == nil {
+		t.Fatal("expected a missing name to be rejected")
+	}
+}
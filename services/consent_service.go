@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// ConsentService tracks marketing consent per customer and channel.
+// Every marketing send path must check Allowed before contacting a
+// customer; transactional messages are exempt.
+type ConsentService struct {
+	consentRepo ConsentRepo
+}
+
+// NewConsentService builds a ConsentService.
+func NewConsentService(consentRepo ConsentRepo) *ConsentService {
+	return &ConsentService{consentRepo: consentRepo}
+}
+
+// Set records an explicit opt-in or opt-out, stamping the time of the
+// decision for the compliance trail.
+func (s *ConsentService) Set(email, channel string, granted bool, source string) (*models.Consent, error) {
+	email = utils.NormalizeEmail(email)
+	if email == "" {
+		return nil, Validation("email_required", "email is required")
+	}
+	if !models.ValidConsentChannel(channel) {
+		return nil, Validation("invalid_channel", "unknown consent channel")
+	}
+
+	consent, err := s.consentRepo.Get(email, channel)
+	if errors.Is(err, repositories.ErrNotFound) {
+		consent = &models.Consent{CustomerEmail: email, Channel: channel}
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	consent.Granted = granted
+	consent.Source = utils.SanitizeText(source)
+	if granted {
+		consent.GrantedAt = &now
+	} else {
+		consent.RevokedAt = &now
+	}
+	if err := s.consentRepo.Save(consent); err != nil {
+		return nil, err
+	}
+	return consent, nil
+}
+
+// Consents returns all of a customer's consent records.
+func (s *ConsentService) Consents(email string) ([]models.Consent, error) {
+	return s.consentRepo.GetByEmail(utils.NormalizeEmail(email))
+}
+
+// Allowed reports whether a marketing message may be sent to email over
+// channel. Consent is opt-in: no record means no.
+func (s *ConsentService) Allowed(email, channel string) (bool, error) {
+	consent, err := s.consentRepo.Get(utils.NormalizeEmail(email), channel)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return consent.Granted, nil
+}
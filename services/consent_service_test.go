@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestConsentIsOptInPerChannel(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+	svc := NewConsentService(repositories.NewConsentRepository(db))
+
+	// No record means no consent.
+	allowed, err := svc.Allowed("Ana@Example.com", models.ConsentChannelEmail)
+	if err != nil {
+		t.Fatalf("Allowed: %v", err)
+	}
+	if allowed {
+		t.Fatal("expected no consent without an explicit opt-in")
+	}
+
+	// Opting in to one channel grants that channel only, and lookups are
+	// case-insensitive on the email.
+	consent, err := svc.Set("Ana@Example.com", models.ConsentChannelEmail, true, "signup form")
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if consent.GrantedAt == nil {
+		t.Fatal("expected an opt-in timestamp")
+	}
+	if allowed, _ := svc.Allowed("ana@example.com", models.ConsentChannelEmail); !allowed {
+		t.Fatal("expected email consent after opt-in")
+	}
+	if allowed, _ := svc.Allowed("ana@example.com", models.ConsentChannelSMS); allowed {
+		t.Fatal("email opt-in must not leak into sms")
+	}
+
+	// Opting out flips the state but keeps both timestamps.
+	revoked, err := svc.Set("ana@example.com", models.ConsentChannelEmail, false, "unsubscribe link")
+	if err != nil {
+		t.Fatalf("Set (revoke): %v", err)
+	}
+	if revoked.Granted || revoked.RevokedAt == nil || revoked.GrantedAt == nil {
+		t.Fatalf("unexpected consent after revoke: %+v", revoked)
+	}
+	if allowed, _ := svc.Allowed("ana@example.com", models.ConsentChannelEmail); allowed {
+		t.Fatal("expected no consent after opt-out")
+	}
+
+	if _, err := svc.Set("ana@example.com", "fax", true, ""); err == nil {
+		t.Fatal("expected an unknown channel to be rejected")
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// CustomerService implements customer profile management: saved
+// addresses, tokenized payment methods and order/reservation history.
+type CustomerService struct {
+	customerRepo    CustomerRepo
+	orderRepo       OrderRepo
+	reservationRepo ReservationRepo
+}
+
+// NewCustomerService builds a CustomerService.
+func NewCustomerService(customerRepo CustomerRepo, orderRepo OrderRepo, reservationRepo ReservationRepo) *CustomerService {
+	return &CustomerService{customerRepo: customerRepo, orderRepo: orderRepo, reservationRepo: reservationRepo}
+}
+
+// Addresses returns the customer's saved addresses.
+func (s *CustomerService) Addresses(userID uint) ([]models.CustomerAddress, error) {
+	return s.customerRepo.ListAddresses(userID)
+}
+
+// AddAddress saves a new address. Marking it default unmarks any
+// previous default.
+func (s *CustomerService) AddAddress(userID uint, label, street, city, postalCode string, isDefault bool) (*models.CustomerAddress, error) {
+	if street == "" || city == "" {
+		return nil, Validation("address_required", "street and city are required")
+	}
+	if isDefault {
+		if err := s.customerRepo.ClearDefaultAddress(userID); err != nil {
+			return nil, err
+		}
+	}
+	address := &models.CustomerAddress{
+		UserID:     userID,
+		Label:      utils.SanitizeText(label),
+		Street:     utils.SanitizeText(street),
+		City:       utils.SanitizeText(city),
+		PostalCode: utils.SanitizeText(postalCode),
+		IsDefault:  isDefault,
+	}
+	if err := s.customerRepo.CreateAddress(address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// RemoveAddress deletes one of the customer's addresses.
+func (s *CustomerService) RemoveAddress(id, userID uint) error {
+	return s.customerRepo.DeleteAddress(id, userID)
+}
+
+// PaymentMethods returns the customer's saved payment methods.
+func (s *CustomerService) PaymentMethods(userID uint) ([]models.CustomerPaymentMethod, error) {
+	return s.customerRepo.ListPaymentMethods(userID)
+}
+
+// AddPaymentMethod saves a tokenized payment method. The token comes
+// from the payment provider; raw card data is never accepted here.
+func (s *CustomerService) AddPaymentMethod(userID uint, provider, token, brand, last4 string, isDefault bool) (*models.CustomerPaymentMethod, error) {
+	if provider == "" || token == "" {
+		return nil, Validation("payment_method_required", "provider and token are required")
+	}
+	if isDefault {
+		if err := s.customerRepo.ClearDefaultPaymentMethod(userID); err != nil {
+			return nil, err
+		}
+	}
+	method := &models.CustomerPaymentMethod{
+		UserID:    userID,
+		Provider:  utils.SanitizeText(provider),
+		Token:     token,
+		Brand:     utils.SanitizeText(brand),
+		Last4:     last4,
+		IsDefault: isDefault,
+	}
+	if err := s.customerRepo.CreatePaymentMethod(method); err != nil {
+		return nil, err
+	}
+	return method, nil
+}
+
+// RemovePaymentMethod deletes one of the customer's payment methods.
+func (s *CustomerService) RemovePaymentMethod(id, userID uint) error {
+	return s.customerRepo.DeletePaymentMethod(id, userID)
+}
+
+// OrderHistory returns the orders placed while logged in to this
+// account, newest first.
+func (s *CustomerService) OrderHistory(userID uint) ([]models.Order, error) {
+	return s.orderRepo.GetByCustomerID(userID)
+}
+
+// ReservationHistory returns the bookings made while logged in to this
+// account, newest first.
+func (s *CustomerService) ReservationHistory(userID uint) ([]models.Reservation, error) {
+	return s.reservationRepo.GetByCustomerID(userID)
+}
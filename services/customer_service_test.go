@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func newCustomerServiceForTest(t *testing.T) (*CustomerService, *models.User) {
+	t.Helper()
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	customer := models.User{Name: "Carla", Email: "carla@example.com", Role: models.RoleCustomer}
+	if err := db.Create(&customer).Error; err != nil {
+		t.Fatalf("creating customer: %v", err)
+	}
+
+	svc := NewCustomerService(
+		repositories.NewCustomerRepository(db),
+		repositories.NewOrderRepository(db),
+		repositories.NewReservationRepository(db),
+	)
+	return svc, &customer
+}
+
+func TestAddAddressReplacesDefault(t *testing.T) {
+	svc, customer := newCustomerServiceForTest(t)
+
+	if _, err := svc.AddAddress(customer.ID, "home", "Calle 1", "Santiago", "8320000", true); err != nil {
+		t.Fatalf("AddAddress: %v", err)
+	}
+	if _, err := svc.AddAddress(customer.ID, "work", "Calle 2", "Santiago", "8320001", true); err != nil {
+		t.Fatalf("AddAddress: %v", err)
+	}
+
+	addresses, err := svc.Addresses(customer.ID)
+	if err != nil {
+		t.Fatalf("Addresses: %v", err)
+	}
+	if len(addresses) != 2 {
+		t.Fatalf("expected 2 addresses, got %d", len(addresses))
+	}
+	defaults := 0
+	for _, a := range addresses {
+		if a.IsDefault {
+			defaults++
+			if a.Label != "work" {
+				t.Fatalf("expected the newest address to be default, got %q", a.Label)
+			}
+		}
+	}
+	if defaults != 1 {
+		t.Fatalf("expected exactly one default address, got %d", defaults)
+	}
+}
+
+func TestAddAddressRequiresStreetAndCity(t *testing.T) {
+	svc, customer := newCustomerServiceForTest(t)
+
+	_, err := svc.AddAddress(customer.ID, "home", "", "Santiago", "", false)
+	var svcErr *Error
+	if !errors.As(err, &svcErr) || svcErr.Kind != KindValidation {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// DisputeService tracks chargebacks raised against order payments.
+type DisputeService struct {
+	disputeRepo DisputeRepo
+	orderRepo   OrderRepo
+}
+
+// NewDisputeService builds a DisputeService.
+func NewDisputeService(disputeRepo DisputeRepo, orderRepo OrderRepo) *DisputeService {
+	return &DisputeService{disputeRepo: disputeRepo, orderRepo: orderRepo}
+}
+
+// Ingest records a dispute webhook. The first event for a provider
+// reference creates the dispute; later events for the same reference
+// update its status, so replayed and out-of-order deliveries are safe.
+func (s *DisputeService) Ingest(providerRef, paymentRef string, amount float64, reason, status string, evidenceDueBy *time.Time) (*models.Dispute, error) {
+	if providerRef == "" {
+		return nil, Validation("invalid_dispute", "dispute has no provider reference")
+	}
+	order, err := s.orderRepo.GetByPaymentRef(paymentRef)
+	if err != nil {
+		return nil, fromRepo(err, "order_not_found", "no order matches the disputed payment")
+	}
+	if status == "" {
+		status = models.DisputeNeedsResponse
+	}
+
+	dispute, err := s.disputeRepo.GetByProviderRef(providerRef)
+	if errors.Is(err, repositories.ErrNotFound) {
+		dispute = &models.Dispute{
+			OrderID:       order.ID,
+			ProviderRef:   providerRef,
+			Amount:        amount,
+			Reason:        reason,
+			Status:        status,
+			EvidenceDueBy: evidenceDueBy,
+		}
+		if err := s.disputeRepo.Create(dispute); err != nil {
+			return nil, err
+		}
+		return dispute, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dispute.Status = status
+	if amount != 0 {
+		dispute.Amount = amount
+	}
+	if reason != "" {
+		dispute.Reason = reason
+	}
+	if evidenceDueBy != nil {
+		dispute.EvidenceDueBy = evidenceDueBy
+	}
+	if err := s.disputeRepo.Update(dispute); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// GetDisputeByID returns the dispute with the given id.
+func (s *DisputeService) GetDisputeByID(id uint) (*models.Dispute, error) {
+	dispute, err := s.disputeRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "dispute_not_found", "dispute not found")
+	}
+	return dispute, nil
+}
+
+// Disputes returns all disputes against a restaurant's orders.
+func (s *DisputeService) Disputes(restaurantID uint) ([]models.Dispute, error) {
+	return s.disputeRepo.GetByRestaurantID(restaurantID)
+}
+
+// SubmitEvidence marks the dispute's evidence as submitted (through the
+// provider's dashboard) and moves it under review.
+func (s *DisputeService) SubmitEvidence(id uint) (*models.Dispute, error) {
+	dispute, err := s.GetDisputeByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.EvidenceSubmittedAt != nil {
+		return nil, Conflict("evidence_already_submitted", "evidence was already submitted")
+	}
+	now := time.Now().UTC()
+	dispute.EvidenceSubmittedAt = &now
+	dispute.Status = models.DisputeUnderReview
+	if err := s.disputeRepo.Update(dispute); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestIngestUpsertsByProviderRef(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	order := models.Order{RestaurantID: 1, Total: 12000, PaymentRef: "pi_123"}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("creating order: %v", err)
+	}
+
+	svc := NewDisputeService(repositories.NewDisputeRepository(db), repositories.NewOrderRepository(db))
+
+	dispute, err := svc.Ingest("dp_1", "pi_123", 12000, "fraudulent", "", nil)
+	if err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+	if dispute.OrderID != order.ID || dispute.Status != models.DisputeNeedsResponse {
+		t.Fatalf("unexpected dispute: %+v", dispute)
+	}
+
+	// A second delivery for the same reference updates the record
+	// instead of duplicating it.
+	if _, err := svc.Ingest("dp_1", "pi_123", 12000, "", models.DisputeLost, nil); err != nil {
+		t.Fatalf("Ingest (replay): %v", err)
+	}
+
+	var count int64
+	if err := db.Model(&models.Dispute{}).Count(&count).Error; err != nil {
+		t.Fatalf("counting disputes: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected one dispute, got %d", count)
+	}
+	updated, err := svc.GetDisputeByID(dispute.ID)
+	if err != nil {
+		t.Fatalf("GetDisputeByID: %v", err)
+	}
+	if updated.Status != models.DisputeLost || updated.Reason != "fraudulent" {
+		t.Fatalf("unexpected dispute after replay: %+v", updated)
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/marc0cl/wets-ma-bu-api/mailer"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// brandSettingsResolver is the slice of BrandService emails need:
+// resolving a restaurant's effective branding.
+type brandSettingsResolver interface {
+	EffectiveSettings(restaurantID uint) (*BrandSettings, error)
+}
+
+// emailTemplate describes one transactional template: the embedded file
+// it renders, its default subject, and sample data for previews and
+// test sends.
+type emailTemplate struct {
+	file    string
+	subject string
+	sample  map[string]interface{}
+}
+
+// emailTemplates are the transactional templates owners can preview and
+// send. Keys are what the API accepts as a template name.
+var emailTemplates = map[string]emailTemplate{
+	"receipt": {
+		file:    "receipt.html",
+		subject: "Your receipt",
+		sample:  map[string]interface{}{"CustomerName": "Ana", "OrderID": 1042, "Total": "18.500"},
+	},
+	"reservation": {
+		file:    "reservation.html",
+		subject: "Your reservation is confirmed",
+		sample:  map[string]interface{}{"CustomerName": "Ana", "PartySize": 4, "Date": "2026-09-12 20:00"},
+	},
+	"marketing": {
+		file:    "marketing.html",
+		subject: "News from us",
+		sample:  map[string]interface{}{"Headline": "Spring menu", "Body": "Our new seasonal dishes are here."},
+	},
+}
+
+// EmailService renders transactional email with a restaurant's branding
+// applied: the effective logo on every template, and the effective
+// custom wrapper — a Go template with a {{.Content}} slot — around it.
+type EmailService struct {
+	restaurantRepo RestaurantRepo
+	brands         brandSettingsResolver
+	jobs           JobEnqueuer
+}
+
+// NewEmailService builds an EmailService.
+func NewEmailService(restaurantRepo RestaurantRepo, brands brandSettingsResolver, jobs JobEnqueuer) *EmailService {
+	return &EmailService{restaurantRepo: restaurantRepo, brands: brands, jobs: jobs}
+}
+
+// TemplateNames lists the templates owners can preview and send.
+func (s *EmailService) TemplateNames() []string {
+	return []string{"marketing", "receipt", "reservation"}
+}
+
+// Render fills the named template with data plus the restaurant's
+// branding and returns the subject and HTML body.
+func (s *EmailService) Render(restaurantID uint, name string, data map[string]interface{}) (string, string, error) {
+	spec, ok := emailTemplates[name]
+	if !ok {
+		return "", "", Validation("unknown_template", "unknown email template")
+	}
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return "", "", fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	settings, err := s.brands.EffectiveSettings(restaurantID)
+	if err != nil {
+		return "", "", err
+	}
+
+	merged := map[string]interface{}{
+		"Restaurant": restaurant.Name,
+		"Logo":       settings.LogoURL,
+	}
+	for k, v := range data {
+		merged[k] = v
+	}
+	html, err := mailer.Render(spec.file, merged)
+	if err != nil {
+		return "", "", err
+	}
+	if settings.EmailTemplate != "" {
+		html, err = wrap(settings.EmailTemplate, restaurant.Name, settings.LogoURL, html)
+		if err != nil {
+			return "", "", Validation("invalid_wrapper", fmt.Sprintf("branding template: %v", err))
+		}
+	}
+	return spec.subject, html, nil
+}
+
+// Preview renders the named template with sample data.
+func (s *EmailService) Preview(restaurantID uint, name string) (string, error) {
+	spec, ok := emailTemplates[name]
+	if !ok {
+		return "", Validation("unknown_template", "unknown email template")
+	}
+	_, html, err := s.Render(restaurantID, name, spec.sample)
+	return html, err
+}
+
+// TestSend renders the named template with sample data and queues it to
+// the given address, subject prefixed so it cannot be mistaken for the
+// real thing.
+func (s *EmailService) TestSend(restaurantID uint, name, to string) error {
+	if to == "" {
+		return Validation("missing_recipient", "a recipient is required")
+	}
+	spec, ok := emailTemplates[name]
+	if !ok {
+		return Validation("unknown_template", "unknown email template")
+	}
+	subject, html, err := s.Render(restaurantID, name, spec.sample)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"to":      to,
+		"subject": "[test] " + subject,
+		"html":    html,
+	})
+	if err != nil {
+		return err
+	}
+	return s.jobs.Enqueue(&models.Job{Type: "email.send", Payload: string(payload)})
+}
+
+// wrap runs the owner's branding wrapper around rendered content. The
+// wrapper sees {{.Restaurant}}, {{.Logo}} and the {{.Content}} slot.
+func wrap(wrapper, restaurant, logo, content string) (string, error) {
+	tpl, err := template.New("wrapper").Parse(wrapper)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	err = tpl.Execute(&b, map[string]interface{}{
+		"Restaurant": restaurant,
+		"Logo":       logo,
+		"Content":    template.HTML(content),
+	})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
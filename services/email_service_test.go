@@ -0,0 +1,83 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestEmailRenderingAppliesBranding(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Mail", Email: "mail@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Correo", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	restaurantRepo := repositories.NewRestaurantRepository(db)
+	brandSvc := NewBrandService(repositories.NewBrandRepository(db), restaurantRepo)
+	jobRepo := repositories.NewJobRepository(db)
+	svc := NewEmailService(restaurantRepo, brandSvc, jobRepo)
+
+	// Branding comes from the brand and flows into every template.
+	brand, err := brandSvc.CreateBrand(owner.ID, "Grupo Correo", "https://cdn.example/logo.png", "", `<div class="brand">{{.Content}}</div>`)
+	if err != nil {
+		t.Fatalf("create brand: %v", err)
+	}
+	if _, err := brandSvc.AssignRestaurant(restaurant.ID, brand.ID, owner.ID); err != nil {
+		t.Fatalf("assign brand: %v", err)
+	}
+
+	html, err := svc.Preview(restaurant.ID, "receipt")
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	if !strings.Contains(html, "https://cdn.example/logo.png") {
+		t.Fatalf("expected the brand logo in the preview, got %q", html)
+	}
+	if !strings.Contains(html, `<div class="brand">`) || !strings.Contains(html, "Correo") {
+		t.Fatalf("expected the wrapper around branded content, got %q", html)
+	}
+
+	if _, err := svc.Preview(restaurant.ID, "invoice"); err == nil {
+		t.Fatal("expected an unknown template to be rejected")
+	}
+
+	// A test send queues a pre-rendered email, clearly marked.
+	if err := svc.TestSend(restaurant.ID, "reservation", "mail@example.com"); err != nil {
+		t.Fatalf("test send: %v", err)
+	}
+	if err := svc.TestSend(restaurant.ID, "reservation", ""); err == nil {
+		t.Fatal("expected a missing recipient to be rejected")
+	}
+
+	var job models.Job
+	if err := db.Where("type = ?", "email.send").First(&job).Error; err != nil {
+		t.Fatalf("load queued job: %v", err)
+	}
+	var payload struct {
+		To      string `json:"to"`
+		Subject string `json:"subject"`
+		HTML    string `json:"html"`
+	}
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.To != "mail@example.com" || !strings.HasPrefix(payload.Subject, "[test] ") {
+		t.Fatalf("expected a marked test email, got %+v", payload)
+	}
+	if !strings.Contains(payload.HTML, `<div class="brand">`) {
+		t.Fatalf("expected the branded body in the payload, got %q", payload.HTML)
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// EmbedService builds the public menu feed served to third-party
+// websites. The feed only ever carries what an anonymous visitor could
+// see: available items at their current prices.
+type EmbedService struct {
+	restaurantRepo RestaurantRepo
+	menuRepo       MenuRepo
+}
+
+// NewEmbedService builds an EmbedService.
+func NewEmbedService(restaurantRepo RestaurantRepo, menuRepo MenuRepo) *EmbedService {
+	return &EmbedService{restaurantRepo: restaurantRepo, menuRepo: menuRepo}
+}
+
+// EmbedItem is one dish in the feed.
+type EmbedItem struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+}
+
+// EmbedCategory groups the feed's items the way the menu displays them.
+type EmbedCategory struct {
+	Name  string      `json:"name"`
+	Items []EmbedItem `json:"items"`
+}
+
+// EmbedMenu is the embeddable menu feed for one restaurant. UpdatedAt
+// is the newest change across the menu, so callers can derive cache
+// validators from it.
+type EmbedMenu struct {
+	Restaurant string          `json:"restaurant"`
+	Slug       string          `json:"slug"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	Categories []EmbedCategory `json:"categories"`
+}
+
+// Menu resolves a slug into the feed. Unavailable items are left out
+// rather than flagged: a visitor's menu has no greyed-out rows.
+func (s *EmbedService) Menu(slug string) (*EmbedMenu, error) {
+	if slug == "" {
+		return nil, NotFound("restaurant_not_found", "restaurant not found")
+	}
+	restaurant, err := s.restaurantRepo.GetBySlug(slug)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	items, err := s.menuRepo.GetByRestaurantID(restaurant.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &EmbedMenu{
+		Restaurant: restaurant.Name,
+		Slug:       restaurant.Slug,
+		UpdatedAt:  restaurant.UpdatedAt,
+	}
+	byCategory := make(map[string][]EmbedItem)
+	for _, item := range items {
+		if !item.Available {
+			continue
+		}
+		byCategory[item.Category] = append(byCategory[item.Category], EmbedItem{
+			Name:        item.Name,
+			Description: item.Description,
+			Price:       item.Price,
+		})
+		if item.UpdatedAt.After(feed.UpdatedAt) {
+			feed.UpdatedAt = item.UpdatedAt
+		}
+	}
+
+	names := make([]string, 0, len(byCategory))
+	for name := range byCategory {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		feed.Categories = append(feed.Categories, EmbedCategory{Name: name, Items: byCategory[name]})
+	}
+	return feed, nil
+}
+
+// Restaurant resolves a slug to the restaurant for the oEmbed card.
+func (s *EmbedService) Restaurant(slug string) (*models.Restaurant, error) {
+	if slug == "" {
+		return nil, NotFound("restaurant_not_found", "restaurant not found")
+	}
+	restaurant, err := s.restaurantRepo.GetBySlug(slug)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	return restaurant, nil
+}
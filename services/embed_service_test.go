@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestEmbedMenuFeed(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Em", Email: "em@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "La Cocina Ñata", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+	if restaurant.Slug != "la-cocina-ata" {
+		t.Fatalf("expected a slug derived from the name, got %q", restaurant.Slug)
+	}
+
+	// A second restaurant with a colliding name gets a distinct slug.
+	other := &models.Restaurant{Name: "La cocina ñata!", UserID: owner.ID}
+	if err := db.Create(other).Error; err != nil {
+		t.Fatalf("create second restaurant: %v", err)
+	}
+	if other.Slug == restaurant.Slug || other.Slug == "" {
+		t.Fatalf("expected a unique slug, got %q and %q", restaurant.Slug, other.Slug)
+	}
+
+	items := []models.MenuItem{
+		{RestaurantID: restaurant.ID, Name: "Pastel", Category: "mains", Price: 9000, Available: true},
+		{RestaurantID: restaurant.ID, Name: "Sopaipilla", Category: "starters", Price: 1500, Available: true},
+		{RestaurantID: restaurant.ID, Name: "Fuera de carta", Category: "mains", Price: 12000},
+	}
+	for i := range items {
+		if err := db.Create(&items[i]).Error; err != nil {
+			t.Fatalf("create item: %v", err)
+		}
+	}
+	// The column defaults to true, so flip the 86'd dish explicitly.
+	if err := db.Model(&items[2]).Update("available", false).Error; err != nil {
+		t.Fatalf("mark unavailable: %v", err)
+	}
+
+	svc := NewEmbedService(repositories.NewRestaurantRepository(db), repositories.NewMenuRepository(db))
+	feed, err := svc.Menu(restaurant.Slug)
+	if err != nil {
+		t.Fatalf("menu feed: %v", err)
+	}
+	if feed.Restaurant != "La Cocina Ñata" || len(feed.Categories) != 2 {
+		t.Fatalf("expected two categories, got %+v", feed)
+	}
+	// Categories are alphabetical; the unavailable dish is left out.
+	if feed.Categories[0].Name != "mains" || len(feed.Categories[0].Items) != 1 {
+		t.Fatalf("expected only the available main, got %+v", feed.Categories[0])
+	}
+	if feed.Categories[1].Items[0].Name != "Sopaipilla" {
+		t.Fatalf("expected the starter, got %+v", feed.Categories[1])
+	}
+
+	if _, err := svc.Menu("no-such-place"); err == nil {
+		t.Fatal("expected an unknown slug to 404")
+	}
+	if _, err := svc.Menu(""); err == nil {
+		t.Fatal("expected an empty slug to 404")
+	}
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// Kind classifies a service error so the HTTP layer can choose a status
+// code without inspecting messages.
+type Kind int
+
+// The taxonomy of client-facing failures services can express. Anything
+// else is treated as an internal error by the HTTP layer.
+const (
+	KindNotFound Kind = iota + 1
+	KindConflict
+	KindForbidden
+	KindValidation
+)
+
+// Error is a typed service error carrying a machine-readable code and a
+// human-readable message. Handlers return it as-is; the central HTTP
+// error handler maps Kind to a status and Code into the envelope.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// NotFound builds a KindNotFound error.
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// Conflict builds a KindConflict error.
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// Forbidden builds a KindForbidden error.
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// Validation builds a KindValidation error.
+func Validation(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+// fromRepo translates a repository lookup error into the taxonomy: a
+// missing row becomes a typed NotFound, while real database failures
+// pass through unchanged and surface as 500s instead of being masked
+// as 404s.
+func fromRepo(err error, code, message string) error {
+	if errors.Is(err, repositories.ErrNotFound) {
+		return NotFound(code, message)
+	}
+	return err
+}
+
+// Sentinel errors shared across services. Handlers may match them with
+// errors.Is, but usually just return them for the central mapping.
+var (
+	// ErrEmailTaken is returned by Register when the email address is
+	// already associated with an account.
+	ErrEmailTaken = Conflict("email_taken", "email already registered")
+
+	// ErrLastAdmin is returned when deleting or demoting an admin would
+	// leave the platform with no admin accounts at all.
+	ErrLastAdmin = Conflict("last_admin", "cannot remove the last admin account")
+
+	// ErrSelfDelete is returned when an admin tries to delete their own
+	// account; another admin has to do it.
+	ErrSelfDelete = Forbidden("self_delete", "admins cannot delete their own account")
+
+	// ErrOwnsRestaurants is returned under the "block" delete policy when
+	// a user who still owns restaurants is deleted.
+	ErrOwnsRestaurants = Conflict("owns_restaurants", "user still owns restaurants; transfer or delete them first")
+)
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// EventService implements ticketed restaurant events: tiered ticket
+// sales and door check-in.
+type EventService struct {
+	eventRepo    EventRepo
+	customerRepo CustomerRepo
+	payments     payments.Provider
+}
+
+// NewEventService builds an EventService.
+func NewEventService(eventRepo EventRepo, customerRepo CustomerRepo, provider payments.Provider) *EventService {
+	return &EventService{eventRepo: eventRepo, customerRepo: customerRepo, payments: provider}
+}
+
+// TierInput describes one ticket tier of a new event.
+type TierInput struct {
+	Name     string  `json:"name"`
+	Price    float64 `json:"price"`
+	Capacity int     `json:"capacity"`
+}
+
+// CreateEvent creates an event with its ticket tiers.
+func (s *EventService) CreateEvent(restaurantID uint, name, description string, startsAt time.Time, tiers []TierInput) (*models.Event, error) {
+	if name == "" {
+		return nil, Validation("missing_name", "an event name is required")
+	}
+	if startsAt.Before(time.Now()) {
+		return nil, Validation("invalid_starts_at", "the event must start in the future")
+	}
+	if len(tiers) == 0 {
+		return nil, Validation("event_needs_tiers", "an event needs at least one ticket tier")
+	}
+
+	event := &models.Event{
+		RestaurantID: restaurantID,
+		Name:         utils.SanitizeText(name),
+		Description:  utils.SanitizeText(description),
+		StartsAt:     startsAt.UTC(),
+	}
+	for _, tier := range tiers {
+		if tier.Name == "" {
+			return nil, Validation("missing_tier_name", "every tier needs a name")
+		}
+		if tier.Price < 0 {
+			return nil, Validation("invalid_tier_price", "tier prices cannot be negative")
+		}
+		if tier.Capacity <= 0 {
+			return nil, Validation("invalid_tier_capacity", "tier capacities must be positive")
+		}
+		event.Tiers = append(event.Tiers, models.EventTier{
+			Name:     utils.SanitizeText(tier.Name),
+			Price:    tier.Price,
+			Capacity: tier.Capacity,
+		})
+	}
+	if err := s.eventRepo.Create(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// GetEventByID returns the event with the given id, tiers included.
+func (s *EventService) GetEventByID(id uint) (*models.Event, error) {
+	event, err := s.eventRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "event_not_found", "event not found")
+	}
+	return event, nil
+}
+
+// GetEventsByRestaurantID returns a restaurant's events, soonest first.
+func (s *EventService) GetEventsByRestaurantID(restaurantID uint) ([]models.Event, error) {
+	return s.eventRepo.GetByRestaurantID(restaurantID)
+}
+
+// PurchaseTicket sells one ticket of the given tier to a customer,
+// charging their card on file. The returned ticket carries the code its
+// QR encodes.
+func (s *EventService) PurchaseTicket(eventID, tierID, customerID uint, customerEmail string) (*models.Ticket, error) {
+	event, err := s.eventRepo.GetByID(eventID)
+	if err != nil {
+		return nil, fromRepo(err, "event_not_found", "event not found")
+	}
+	if time.Now().After(event.StartsAt) {
+		return nil, Validation("event_started", "tickets are no longer on sale")
+	}
+
+	var tier *models.EventTier
+	for i := range event.Tiers {
+		if event.Tiers[i].ID == tierID {
+			tier = &event.Tiers[i]
+			break
+		}
+	}
+	if tier == nil {
+		return nil, NotFound("tier_not_found", "ticket tier not found")
+	}
+
+	sold, err := s.eventRepo.CountTicketsByTierID(tier.ID)
+	if err != nil {
+		return nil, err
+	}
+	if sold >= int64(tier.Capacity) {
+		return nil, Conflict("tier_sold_out", "this tier is sold out")
+	}
+
+	ticket := &models.Ticket{
+		EventID:       event.ID,
+		TierID:        tier.ID,
+		CustomerID:    customerID,
+		CustomerEmail: customerEmail,
+		Price:         tier.Price,
+	}
+	if tier.Price > 0 {
+		methods, err := s.customerRepo.ListPaymentMethods(customerID)
+		if err != nil {
+			return nil, err
+		}
+		if len(methods) == 0 {
+			return nil, Validation("payment_method_required", "save a payment method to buy tickets")
+		}
+		ref, err := s.payments.Charge(defaultPaymentMethod(methods).Token,
+			int64(math.Round(tier.Price*100)),
+			fmt.Sprintf("ticket for %s (%s)", event.Name, tier.Name))
+		if err != nil {
+			return nil, Conflict("charge_failed", "the ticket charge was declined")
+		}
+		ticket.ChargeRef = ref
+	}
+
+	// The charge has already moved at this point, so a storage failure
+	// surfaces loudly for the operator to resolve.
+	if err := s.eventRepo.CreateTicket(ticket); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// Tickets returns all tickets sold for an event.
+func (s *EventService) Tickets(eventID uint) ([]models.Ticket, error) {
+	return s.eventRepo.GetTicketsByEventID(eventID)
+}
+
+// MyTickets returns a customer's tickets, newest first.
+func (s *EventService) MyTickets(customerID uint) ([]models.Ticket, error) {
+	return s.eventRepo.GetTicketsByCustomerID(customerID)
+}
+
+// CheckIn admits the ticket with the given code at the door, stamping
+// the check-in time so the same code cannot be admitted twice. The code
+// must belong to the given event; a valid code for another event reads
+// as not found rather than leaking where it is valid.
+func (s *EventService) CheckIn(eventID uint, code string) (*models.Ticket, error) {
+	ticket, err := s.eventRepo.GetTicketByCode(code)
+	if err != nil {
+		return nil, fromRepo(err, "ticket_not_found", "ticket not found")
+	}
+	if ticket.EventID != eventID {
+		return nil, NotFound("ticket_not_found", "ticket not found")
+	}
+	if ticket.CheckedInAt != nil {
+		return nil, Conflict("already_checked_in", "this ticket has already been used")
+	}
+	now := time.Now().UTC()
+	ticket.CheckedInAt = &now
+	if err := s.eventRepo.UpdateTicket(ticket); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func newEventServiceForTest(t *testing.T) (*EventService, *models.Event, *models.User) {
+	t.Helper()
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	restaurant := models.Restaurant{Name: "Testaurant", UserID: 1}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("creating restaurant: %v", err)
+	}
+	customer := models.User{Name: "Ana", Email: "ana@example.com", Role: models.RoleCustomer}
+	if err := db.Create(&customer).Error; err != nil {
+		t.Fatalf("creating customer: %v", err)
+	}
+	card := models.CustomerPaymentMethod{UserID: customer.ID, Provider: "stripe", Token: "pm_123", IsDefault: true}
+	if err := db.Create(&card).Error; err != nil {
+		t.Fatalf("creating payment method: %v", err)
+	}
+
+	svc := NewEventService(
+		repositories.NewEventRepository(db),
+		repositories.NewCustomerRepository(db),
+		payments.LogProvider{},
+	)
+	event, err := svc.CreateEvent(restaurant.ID, "Wine Tasting", "six pours", time.Now().Add(48*time.Hour), []TierInput{
+		{Name: "General", Price: 15000, Capacity: 2},
+		{Name: "VIP", Price: 30000, Capacity: 1},
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	return svc, event, &customer
+}
+
+func TestPurchaseTicketRespectsTierCapacity(t *testing.T) {
+	svc, event, customer := newEventServiceForTest(t)
+
+	vip := event.Tiers[1]
+	first, err := svc.PurchaseTicket(event.ID, vip.ID, customer.ID, customer.Email)
+	if err != nil {
+		t.Fatalf("PurchaseTicket: %v", err)
+	}
+	if first.Code == "" || first.ChargeRef == "" {
+		t.Fatalf("expected a code and charge reference, got %+v", first)
+	}
+	if first.Price != 30000 {
+		t.Fatalf("expected the VIP price on the ticket, got %.2f", first.Price)
+	}
+
+	// The VIP tier has a capacity of one.
+	if _, err := svc.PurchaseTicket(event.ID, vip.ID, customer.ID, customer.Email); err == nil {
+		t.Fatal("expected the second VIP purchase to be rejected")
+	}
+
+	// General still has room.
+	if _, err := svc.PurchaseTicket(event.ID, event.Tiers[0].ID, customer.ID, customer.Email); err != nil {
+		t.Fatalf("PurchaseTicket (general): %v", err)
+	}
+
+	tickets, err := svc.MyTickets(customer.ID)
+	if err != nil {
+		t.Fatalf("MyTickets: %v", err)
+	}
+	if len(tickets) != 2 {
+		t.Fatalf("expected 2 tickets, got %d", len(tickets))
+	}
+}
+
+func TestCheckInAdmitsEachTicketOnce(t *testing.T) {
+	svc, event, customer := newEventServiceForTest(t)
+
+	ticket, err := svc.PurchaseTicket(event.ID, event.Tiers[0].ID, customer.ID, customer.Email)
+	if err != nil {
+		t.Fatalf("PurchaseTicket: %v", err)
+	}
+
+	admitted, err := svc.CheckIn(event.ID, ticket.Code)
+	if err != nil {
+		t.Fatalf("CheckIn: %v", err)
+	}
+	if admitted.CheckedInAt == nil {
+		t.Fatal("expected a check-in timestamp")
+	}
+
+	if _, err := svc.CheckIn(event.ID, ticket.Code); err == nil {
+		t.Fatal("expected a second scan of the same code to be rejected")
+	}
+	if _, err := svc.CheckIn(event.ID, "not-a-code"); err == nil {
+		t.Fatal("expected an unknown code to be rejected")
+	}
+	// A valid code for a different event reads as not found.
+	if _, err := svc.CheckIn(event.ID+1, ticket.Code); err == nil {
+		t.Fatal("expected a code from another event to be rejected")
+	}
+}
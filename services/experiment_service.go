@@ -0,0 +1,270 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// ExperimentService implements A/B testing of menu presentation.
+type ExperimentService struct {
+	experimentRepo ExperimentRepo
+}
+
+// NewExperimentService builds an ExperimentService.
+func NewExperimentService(experimentRepo ExperimentRepo) *ExperimentService {
+	return &ExperimentService{experimentRepo: experimentRepo}
+}
+
+// VariantInput describes one experiment arm at creation time. ItemOrder
+// lists the menu item ids pinned to the top of the menu; leave it empty
+// for the control arm.
+type VariantInput struct {
+	Name      string `json:"name"`
+	ItemOrder []uint `json:"item_order"`
+}
+
+// CreateExperiment creates a draft experiment with its variants.
+func (s *ExperimentService) CreateExperiment(restaurantID uint, name string, variants []VariantInput) (*models.Experiment, error) {
+	if name == "" {
+		return nil, Validation("name_required", "experiment name is required")
+	}
+	if len(variants) < 2 {
+		return nil, Validation("experiment_needs_variants", "an experiment needs at least two variants")
+	}
+	experiment := &models.Experiment{
+		RestaurantID: restaurantID,
+		Name:         utils.SanitizeText(name),
+		Status:       models.ExperimentDraft,
+	}
+	for _, v := range variants {
+		if v.Name == "" {
+			return nil, Validation("variant_name_required", "every variant needs a name")
+		}
+		experiment.Variants = append(experiment.Variants, models.ExperimentVariant{
+			Name:      utils.SanitizeText(v.Name),
+			ItemOrder: joinItemOrder(v.ItemOrder),
+		})
+	}
+	if err := s.experimentRepo.Create(experiment); err != nil {
+		return nil, err
+	}
+	return experiment, nil
+}
+
+// GetExperimentByID returns the experiment with the given id.
+func (s *ExperimentService) GetExperimentByID(id uint) (*models.Experiment, error) {
+	experiment, err := s.experimentRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "experiment_not_found", "experiment not found")
+	}
+	return experiment, nil
+}
+
+// Experiments returns a restaurant's experiments, newest first.
+func (s *ExperimentService) Experiments(restaurantID uint) ([]models.Experiment, error) {
+	return s.experimentRepo.GetByRestaurantID(restaurantID)
+}
+
+// SetStatus moves an experiment between draft, running and stopped. At
+// most one experiment per restaurant runs at a time, so starting a
+// second one is rejected rather than silently splitting traffic.
+func (s *ExperimentService) SetStatus(id uint, status string) (*models.Experiment, error) {
+	if !models.ValidExperimentStatus(status) {
+		return nil, Validation("invalid_experiment_status", "unknown experiment status")
+	}
+	experiment, err := s.experimentRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "experiment_not_found", "experiment not found")
+	}
+	if status == models.ExperimentRunning && experiment.Status != models.ExperimentRunning {
+		running, err := s.experimentRepo.GetRunningByRestaurantID(experiment.RestaurantID)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return nil, err
+		}
+		if err == nil && running.ID != experiment.ID {
+			return nil, Conflict("experiment_already_running", "another experiment is already running for this restaurant")
+		}
+	}
+	experiment.Status = status
+	if err := s.experimentRepo.Update(experiment); err != nil {
+		return nil, err
+	}
+	return experiment, nil
+}
+
+// ApplyMenu reorders a menu for the visitor's assigned variant of the
+// restaurant's running experiment, recording the exposure. With no
+// running experiment (or no visitor id) the menu passes through
+// unchanged.
+func (s *ExperimentService) ApplyMenu(restaurantID uint, visitorID string, items []models.MenuItem) ([]models.MenuItem, error) {
+	if visitorID == "" {
+		return items, nil
+	}
+	experiment, err := s.experimentRepo.GetRunningByRestaurantID(restaurantID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return items, nil
+	}
+	if err != nil {
+		return items, err
+	}
+	variant := assignVariant(experiment, visitorID)
+	if variant == nil {
+		return items, nil
+	}
+	exposure := &models.ExperimentExposure{
+		ExperimentID: experiment.ID,
+		VariantID:    variant.ID,
+		VisitorID:    visitorID,
+	}
+	if err := s.experimentRepo.CreateExposure(exposure); err != nil {
+		return items, err
+	}
+	return reorderMenu(items, parseItemOrder(variant.ItemOrder)), nil
+}
+
+// RecordConversion marks the visitor's exposure to the restaurant's
+// running experiment as converted and adds the order's value to it. A
+// visitor who was never exposed (or a restaurant with no running
+// experiment) is a no-op.
+func (s *ExperimentService) RecordConversion(restaurantID uint, visitorID string, orderValue float64) error {
+	if visitorID == "" {
+		return nil
+	}
+	experiment, err := s.experimentRepo.GetRunningByRestaurantID(restaurantID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	exposure, err := s.experimentRepo.GetExposure(experiment.ID, visitorID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	exposure.Converted = true
+	exposure.OrderValue += orderValue
+	return s.experimentRepo.UpdateExposure(exposure)
+}
+
+// VariantResult compares one experiment arm against the others.
+type VariantResult struct {
+	VariantID      uint    `json:"variant_id"`
+	Name           string  `json:"name"`
+	Exposures      int64   `json:"exposures"`
+	Conversions    int64   `json:"conversions"`
+	ConversionRate float64 `json:"conversion_rate"`
+	AvgOrderValue  float64 `json:"avg_order_value"`
+}
+
+// Results returns per-variant exposure and conversion figures for an
+// experiment. Variants nobody has seen yet still appear, with zeroes.
+func (s *ExperimentService) Results(id uint) ([]VariantResult, error) {
+	experiment, err := s.experimentRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "experiment_not_found", "experiment not found")
+	}
+	stats, err := s.experimentRepo.ExposureStats(id)
+	if err != nil {
+		return nil, err
+	}
+	byVariant := make(map[uint]repositories.ExposureStatsRow, len(stats))
+	for _, row := range stats {
+		byVariant[row.VariantID] = row
+	}
+
+	results := make([]VariantResult, 0, len(experiment.Variants))
+	for _, variant := range experiment.Variants {
+		row := byVariant[variant.ID]
+		result := VariantResult{
+			VariantID:   variant.ID,
+			Name:        variant.Name,
+			Exposures:   row.Exposures,
+			Conversions: row.Conversions,
+		}
+		if row.Exposures > 0 {
+			result.ConversionRate = float64(row.Conversions) / float64(row.Exposures)
+		}
+		if row.Conversions > 0 {
+			result.AvgOrderValue = row.OrderValue / float64(row.Conversions)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// assignVariant deterministically maps a visitor onto one of the
+// experiment's variants: the same visitor always lands on the same arm,
+// and a good hash spreads traffic evenly between them.
+func assignVariant(experiment *models.Experiment, visitorID string) *models.ExperimentVariant {
+	if len(experiment.Variants) == 0 {
+		return nil
+	}
+	variants := make([]models.ExperimentVariant, len(experiment.Variants))
+	copy(variants, experiment.Variants)
+	sort.Slice(variants, func(i, j int) bool { return variants[i].ID < variants[j].ID })
+
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%d:%s", experiment.ID, visitorID)
+	return &variants[int(h.Sum32())%len(variants)]
+}
+
+// reorderMenu returns a copy of items with the pinned ids first, in
+// pinned order, and everything else after in its original order.
+func reorderMenu(items []models.MenuItem, pinned []uint) []models.MenuItem {
+	if len(pinned) == 0 {
+		return items
+	}
+	byID := make(map[uint]int, len(items))
+	for i := range items {
+		byID[items[i].ID] = i
+	}
+	used := make(map[uint]bool, len(pinned))
+	reordered := make([]models.MenuItem, 0, len(items))
+	for _, id := range pinned {
+		if i, ok := byID[id]; ok && !used[id] {
+			reordered = append(reordered, items[i])
+			used[id] = true
+		}
+	}
+	for i := range items {
+		if !used[items[i].ID] {
+			reordered = append(reordered, items[i])
+		}
+	}
+	return reordered
+}
+
+// joinItemOrder serializes pinned item ids for storage.
+func joinItemOrder(ids []uint) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseItemOrder is the inverse of joinItemOrder; malformed entries are
+// skipped rather than failing the whole menu.
+func parseItemOrder(s string) []uint {
+	if s == "" {
+		return nil
+	}
+	var ids []uint
+	for _, part := range strings.Split(s, ",") {
+		if n, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32); err == nil {
+			ids = append(ids, uint(n))
+		}
+	}
+	return ids
+}
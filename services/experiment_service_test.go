@@ -0,0 +1,133 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func newRunningExperiment(t *testing.T) (*ExperimentService, *models.Experiment) {
+	t.Helper()
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+	svc := NewExperimentService(repositories.NewExperimentRepository(db))
+
+	experiment, err := svc.CreateExperiment(1, "dessert placement", []VariantInput{
+		{Name: "control"},
+		{Name: "desserts first", ItemOrder: []uint{3, 2}},
+	})
+	if err != nil {
+		t.Fatalf("CreateExperiment: %v", err)
+	}
+	if _, err := svc.SetStatus(experiment.ID, models.ExperimentRunning); err != nil {
+		t.Fatalf("SetStatus: %v", err)
+	}
+	return svc, experiment
+}
+
+func TestApplyMenuAssignsVisitorsDeterministically(t *testing.T) {
+	svc, experiment := newRunningExperiment(t)
+
+	menu := []models.MenuItem{{}, {}, {}}
+	for i := range menu {
+		menu[i].ID = uint(i + 1)
+	}
+
+	// The same visitor must see the same ordering on every visit, and
+	// repeat visits must not add exposures.
+	first, err := svc.ApplyMenu(1, "visitor-a", menu)
+	if err != nil {
+		t.Fatalf("ApplyMenu: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		again, err := svc.ApplyMenu(1, "visitor-a", menu)
+		if err != nil {
+			t.Fatalf("ApplyMenu (repeat): %v", err)
+		}
+		for j := range first {
+			if again[j].ID != first[j].ID {
+				t.Fatalf("ordering changed between visits: %v then %v", first, again)
+			}
+		}
+	}
+	results, err := svc.Results(experiment.ID)
+	if err != nil {
+		t.Fatalf("Results: %v", err)
+	}
+	var exposures int64
+	for _, r := range results {
+		exposures += r.Exposures
+	}
+	if exposures != 1 {
+		t.Fatalf("expected one exposure for a repeat visitor, got %d", exposures)
+	}
+}
+
+func TestResultsCompareConversionPerVariant(t *testing.T) {
+	svc, experiment := newRunningExperiment(t)
+
+	menu := []models.MenuItem{{}, {}, {}}
+	for i := range menu {
+		menu[i].ID = uint(i + 1)
+	}
+	visitors := []string{"v1", "v2", "v3", "v4", "v5", "v6"}
+	for _, v := range visitors {
+		if _, err := svc.ApplyMenu(1, v, menu); err != nil {
+			t.Fatalf("ApplyMenu(%s): %v", v, err)
+		}
+	}
+	// Two visitors order; one of them twice.
+	if err := svc.RecordConversion(1, "v1", 8000); err != nil {
+		t.Fatalf("RecordConversion: %v", err)
+	}
+	if err := svc.RecordConversion(1, "v2", 5000); err != nil {
+		t.Fatalf("RecordConversion: %v", err)
+	}
+	if err := svc.RecordConversion(1, "v2", 3000); err != nil {
+		t.Fatalf("RecordConversion: %v", err)
+	}
+	// A visitor who never saw the menu does not count.
+	if err := svc.RecordConversion(1, "stranger", 9000); err != nil {
+		t.Fatalf("RecordConversion (unexposed): %v", err)
+	}
+
+	results, err := svc.Results(experiment.ID)
+	if err != nil {
+		t.Fatalf("Results: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected results for both variants, got %d", len(results))
+	}
+	var exposures, conversions int64
+	var value float64
+	for _, r := range results {
+		exposures += r.Exposures
+		conversions += r.Conversions
+		value += float64(r.Conversions) * r.AvgOrderValue
+	}
+	if exposures != int64(len(visitors)) {
+		t.Fatalf("expected %d exposures, got %d", len(visitors), exposures)
+	}
+	if conversions != 2 {
+		t.Fatalf("expected 2 converted visitors, got %d", conversions)
+	}
+	if value != 16000 {
+		t.Fatalf("expected 16000 of attributed order value, got %.2f", value)
+	}
+}
+
+func TestSetStatusRejectsSecondRunningExperiment(t *testing.T) {
+	svc, _ := newRunningExperiment(t)
+
+	second, err := svc.CreateExperiment(1, "another", []VariantInput{{Name: "a"}, {Name: "b"}})
+	if err != nil {
+		t.Fatalf("CreateExperiment: %v", err)
+	}
+	if _, err := svc.SetStatus(second.ID, models.ExperimentRunning); err == nil {
+		t.Fatal("expected starting a second experiment to fail")
+	}
+}
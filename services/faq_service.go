@@ -0,0 +1,159 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// FAQService manages a restaurant's FAQ entries and answers visitor
+// questions from them, from the opening hours and from the menu.
+type FAQService struct {
+	faqRepo        FAQRepo
+	restaurantRepo RestaurantRepo
+	menuRepo       MenuRepo
+}
+
+// NewFAQService builds a FAQService.
+func NewFAQService(faqRepo FAQRepo, restaurantRepo RestaurantRepo, menuRepo MenuRepo) *FAQService {
+	return &FAQService{faqRepo: faqRepo, restaurantRepo: restaurantRepo, menuRepo: menuRepo}
+}
+
+// AddEntry creates a FAQ entry.
+func (s *FAQService) AddEntry(restaurantID uint, question, answer string) (*models.FAQEntry, error) {
+	question = utils.SanitizeText(question)
+	answer = utils.SanitizeText(answer)
+	if question == "" || answer == "" {
+		return nil, Validation("missing_fields", "a question and an answer are required")
+	}
+
+	entry := &models.FAQEntry{RestaurantID: restaurantID, Question: question, Answer: answer}
+	if err := s.faqRepo.Create(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Entries returns a restaurant's FAQ entries.
+func (s *FAQService) Entries(restaurantID uint) ([]models.FAQEntry, error) {
+	return s.faqRepo.GetByRestaurantID(restaurantID)
+}
+
+// GetEntryByID returns a single FAQ entry.
+func (s *FAQService) GetEntryByID(id uint) (*models.FAQEntry, error) {
+	entry, err := s.faqRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "entry_not_found", "FAQ entry not found")
+	}
+	return entry, nil
+}
+
+// DeleteEntry removes a FAQ entry.
+func (s *FAQService) DeleteEntry(id uint) error {
+	if _, err := s.GetEntryByID(id); err != nil {
+		return err
+	}
+	return s.faqRepo.Delete(id)
+}
+
+// ChatAnswer is the reply to one visitor question. Source says what
+// answered it: "faq", "hours", "menu", or "none" when nothing matched.
+type ChatAnswer struct {
+	Answer string `json:"answer"`
+	Source string `json:"source"`
+}
+
+// hourWords are the cues that a question is about opening times.
+var hourWords = []string{"hora", "horario", "abre", "cierra", "abierto", "hours", "open", "close", "when"}
+
+// Ask answers a visitor question. FAQ entries are tried first — they
+// are the owner's own words — then opening hours, then the menu.
+func (s *FAQService) Ask(restaurantID uint, question string) (*ChatAnswer, error) {
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	tokens := tokenize(question)
+	if len(tokens) == 0 {
+		return nil, Validation("missing_question", "a question is required")
+	}
+
+	entries, err := s.faqRepo.GetByRestaurantID(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	best, bestScore := (*models.FAQEntry)(nil), 0
+	for i := range entries {
+		if score := overlap(tokens, tokenize(entries[i].Question)); score > bestScore {
+			best, bestScore = &entries[i], score
+		}
+	}
+	if best != nil {
+		return &ChatAnswer{Answer: best.Answer, Source: "faq"}, nil
+	}
+
+	for _, token := range tokens {
+		for _, w := range hourWords {
+			if token == w {
+				return &ChatAnswer{
+					Answer: fmt.Sprintf("We are open from %s to %s.", restaurant.OpensAt, restaurant.ClosesAt),
+					Source: "hours",
+				}, nil
+			}
+		}
+	}
+
+	items, err := s.menuRepo.GetByRestaurantID(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if !items[i].Available {
+			continue
+		}
+		if overlap(tokens, tokenize(items[i].Name)) > 0 {
+			return &ChatAnswer{
+				Answer: fmt.Sprintf("Yes, we serve %s for %.0f.", items[i].Name, items[i].Price),
+				Source: "menu",
+			}, nil
+		}
+	}
+
+	answer := "Sorry, I don't know that one."
+	if restaurant.Phone != "" {
+		answer = fmt.Sprintf("Sorry, I don't know that one. You can call us at %s.", restaurant.Phone)
+	}
+	return &ChatAnswer{Answer: answer, Source: "none"}, nil
+}
+
+// tokenize splits a question into lowercase words, dropping the short
+// glue words that match everything.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !((r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'))
+	})
+	var tokens []string
+	for _, f := range fields {
+		if len(f) >= 3 {
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
+
+// overlap counts how many tokens the two sets share.
+func overlap(a, b []string) int {
+	set := make(map[string]bool, len(b))
+	for _, t := range b {
+		set[t] = true
+	}
+	n := 0
+	for _, t := range a {
+		if set[t] {
+			n++
+		}
+	}
+	return n
+}
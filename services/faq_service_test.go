@@ -0,0 +1,109 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestAskAnswersFromFAQHoursAndMenu(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Faq", Email: "faq@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Preguntas", UserID: owner.ID, Phone: "+56911112222"}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+	item := &models.MenuItem{RestaurantID: restaurant.ID, Name: "Pastel de choclo", Price: 9500}
+	if err := db.Create(item).Error; err != nil {
+		t.Fatalf("create menu item: %v", err)
+	}
+
+	svc := NewFAQService(repositories.NewFAQRepository(db), repositories.NewRestaurantRepository(db), repositories.NewMenuRepository(db))
+
+	if _, err := svc.AddEntry(restaurant.ID, "Tienen estacionamiento?", "Si, detras del local."); err != nil {
+		t.Fatalf("add entry: %v", err)
+	}
+	if _, err := svc.AddEntry(restaurant.ID, "", "huerfana"); err == nil {
+		t.Fatal("expected an entry without a question to be rejected")
+	}
+
+	// The owner's own FAQ wins over everything else.
+	answer, err := svc.Ask(restaurant.ID, "hay estacionamiento cerca?")
+	if err != nil {
+		t.Fatalf("ask faq: %v", err)
+	}
+	if answer.Source != "faq" || answer.Answer != "Si, detras del local." {
+		t.Fatalf("expected the FAQ answer, got %+v", answer)
+	}
+
+	// No FAQ mentions hours, so the schedule answers.
+	answer, err = svc.Ask(restaurant.ID, "a que hora abren?")
+	if err != nil {
+		t.Fatalf("ask hours: %v", err)
+	}
+	if answer.Source != "hours" || !strings.Contains(answer.Answer, "12:00") || !strings.Contains(answer.Answer, "22:00") {
+		t.Fatalf("expected the opening hours, got %+v", answer)
+	}
+
+	// A dish name falls through to the menu, with its price.
+	answer, err = svc.Ask(restaurant.ID, "venden pastel de choclo?")
+	if err != nil {
+		t.Fatalf("ask menu: %v", err)
+	}
+	if answer.Source != "menu" || !strings.Contains(answer.Answer, "9500") {
+		t.Fatalf("expected the menu answer with a price, got %+v", answer)
+	}
+
+	// Anything else falls back to the phone number.
+	answer, err = svc.Ask(restaurant.ID, "aceptan mascotas grandes?")
+	if err != nil {
+		t.Fatalf("ask fallback: %v", err)
+	}
+	if answer.Source != "none" || !strings.Contains(answer.Answer, restaurant.Phone) {
+		t.Fatalf("expected the fallback with the phone, got %+v", answer)
+	}
+}
+
+func TestAskSkipsUnavailableDishes(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Ochenta", Email: "ochenta@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Sin Stock", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+	item := &models.MenuItem{RestaurantID: restaurant.ID, Name: "Cazuela", Price: 8000}
+	if err := db.Create(item).Error; err != nil {
+		t.Fatalf("create menu item: %v", err)
+	}
+	// The column defaults to true, so flip the 86'd dish explicitly.
+	if err := db.Model(item).Update("available", false).Error; err != nil {
+		t.Fatalf("mark unavailable: %v", err)
+	}
+
+	svc := NewFAQService(repositories.NewFAQRepository(db), repositories.NewRestaurantRepository(db), repositories.NewMenuRepository(db))
+
+	answer, err := svc.Ask(restaurant.ID, "tienen cazuela hoy?")
+	if err != nil {
+		t.Fatalf("ask: %v", err)
+	}
+	if answer.Source != "none" {
+		t.Fatalf("expected an 86'd dish to not be offered, got %+v", answer)
+	}
+}
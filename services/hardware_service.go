@@ -0,0 +1,115 @@
+package services
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// deviceKinds is what a restaurant can register as hardware.
+var deviceKinds = map[string]bool{
+	models.DeviceTablet:  true,
+	models.DeviceKDS:     true,
+	models.DevicePrinter: true,
+}
+
+// HardwareService manages a restaurant's hardware fleet: tablets, KDS
+// screens and printers register here, heartbeat for their configuration
+// and can be deactivated remotely.
+type HardwareService struct {
+	hardwareRepo   HardwareRepo
+	restaurantRepo RestaurantRepo
+}
+
+// NewHardwareService builds a HardwareService.
+func NewHardwareService(hardwareRepo HardwareRepo, restaurantRepo RestaurantRepo) *HardwareService {
+	return &HardwareService{hardwareRepo: hardwareRepo, restaurantRepo: restaurantRepo}
+}
+
+// RegisterDevice enrolls a device for the restaurant and mints its
+// token. The owner types the token into the device once; everything
+// after that rides on heartbeats.
+func (s *HardwareService) RegisterDevice(restaurantID uint, name, kind, config string) (*models.HardwareDevice, error) {
+	if _, err := s.restaurantRepo.GetByID(restaurantID); err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	name = utils.SanitizeText(name)
+	if name == "" {
+		return nil, Validation("name_required", "device name is required")
+	}
+	if !deviceKinds[kind] {
+		return nil, Validation("invalid_kind", "device kind must be tablet, kds or printer")
+	}
+	if config != "" && !json.Valid([]byte(config)) {
+		return nil, Validation("invalid_config", "device config must be valid JSON")
+	}
+
+	device := &models.HardwareDevice{
+		RestaurantID: restaurantID,
+		Name:         name,
+		Kind:         kind,
+		Active:       true,
+		Config:       config,
+	}
+	if err := s.hardwareRepo.Create(device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// Devices returns the restaurant's hardware fleet.
+func (s *HardwareService) Devices(restaurantID uint) ([]models.HardwareDevice, error) {
+	return s.hardwareRepo.GetByRestaurantID(restaurantID)
+}
+
+// GetDeviceByID returns one device; handlers use it for ownership
+// checks.
+func (s *HardwareService) GetDeviceByID(id uint) (*models.HardwareDevice, error) {
+	device, err := s.hardwareRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "device_not_found", "device not found")
+	}
+	return device, nil
+}
+
+// UpdateDevice changes a device's active flag and configuration. Nil
+// fields are left untouched, so deactivating does not clobber the
+// config.
+func (s *HardwareService) UpdateDevice(id uint, active *bool, config *string) (*models.HardwareDevice, error) {
+	device, err := s.hardwareRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "device_not_found", "device not found")
+	}
+	if active != nil {
+		device.Active = *active
+	}
+	if config != nil {
+		if *config != "" && !json.Valid([]byte(*config)) {
+			return nil, Validation("invalid_config", "device config must be valid JSON")
+		}
+		device.Config = *config
+	}
+	if err := s.hardwareRepo.Update(device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// Heartbeat is the device's poll: it stamps LastSeenAt and returns the
+// device's current state. A deactivated device still gets an answer —
+// the active flag in it is how the hardware learns to lock itself. A
+// wrong token reads as not found, so probing reveals nothing.
+func (s *HardwareService) Heartbeat(token string) (*models.HardwareDevice, error) {
+	device, err := s.hardwareRepo.GetByToken(token)
+	if err != nil {
+		return nil, fromRepo(err, "device_not_found", "device not found")
+	}
+	now := time.Now()
+	device.LastSeenAt = &now
+	if err := s.hardwareRepo.Update(device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
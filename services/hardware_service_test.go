@@ -0,0 +1,82 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestHardwareDeviceLifecycle(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	restaurant := models.Restaurant{Name: "Ferretería", UserID: 1}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewHardwareService(repositories.NewHardwareRepository(db), repositories.NewRestaurantRepository(db))
+
+	if _, err := svc.RegisterDevice(restaurant.ID, "Caja 1", "toaster", ""); err == nil {
+		t.Fatal("expected an unknown kind to be rejected")
+	}
+	if _, err := svc.RegisterDevice(restaurant.ID, "Caja 1", models.DeviceTablet, "{not json"); err == nil {
+		t.Fatal("expected a malformed config to be rejected")
+	}
+	device, err := svc.RegisterDevice(restaurant.ID, "Caja 1", models.DeviceTablet, `{"station":"front"}`)
+	if err != nil {
+		t.Fatalf("register device: %v", err)
+	}
+	if device.Token == "" || !device.Active {
+		t.Fatalf("expected an active, tokened device, got %+v", device)
+	}
+
+	// The heartbeat stamps the device and hands back its state.
+	if _, err := svc.Heartbeat("wrong-token"); err == nil {
+		t.Fatal("expected an unknown token to be rejected")
+	}
+	seen, err := svc.Heartbeat(device.Token)
+	if err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	if seen.LastSeenAt == nil || seen.Config != `{"station":"front"}` {
+		t.Fatalf("expected a stamped device with its config, got %+v", seen)
+	}
+
+	// Deactivation reaches the device through its next heartbeat.
+	off := false
+	if _, err := svc.UpdateDevice(device.ID, &off, nil); err != nil {
+		t.Fatalf("deactivate device: %v", err)
+	}
+	seen, err = svc.Heartbeat(device.Token)
+	if err != nil {
+		t.Fatalf("heartbeat after deactivation: %v", err)
+	}
+	if seen.Active {
+		t.Fatal("expected the heartbeat to report the device deactivated")
+	}
+	if seen.Config != `{"station":"front"}` {
+		t.Fatalf("expected deactivation to leave the config alone, got %q", seen.Config)
+	}
+
+	// A config push rides the same update path.
+	cfg := `{"station":"bar"}`
+	if _, err := svc.UpdateDevice(device.ID, nil, &cfg); err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	badCfg := "{nope"
+	if _, err := svc.UpdateDevice(device.ID, nil, &badCfg); err == nil {
+		t.Fatal("expected a malformed config push to be rejected")
+	}
+	seen, err = svc.Heartbeat(device.Token)
+	if err != nil {
+		t.Fatalf("heartbeat after config push: %v", err)
+	}
+	if seen.Config != cfg {
+		t.Fatalf("expected the new config, got %q", seen.Config)
+	}
+}
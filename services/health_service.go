@@ -0,0 +1,140 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// HealthService tracks health inspection records and the optional
+// public score badge derived from them.
+type HealthService struct {
+	healthRepo     HealthRepo
+	restaurantRepo RestaurantRepo
+	mediaDir       string
+}
+
+// NewHealthService builds a HealthService storing report files in
+// mediaDir.
+func NewHealthService(healthRepo HealthRepo, restaurantRepo RestaurantRepo, mediaDir string) *HealthService {
+	return &HealthService{healthRepo: healthRepo, restaurantRepo: restaurantRepo, mediaDir: mediaDir}
+}
+
+// CreateInspection records an inspection visit. report is the official
+// report file and may be nil when none is attached.
+func (s *HealthService) CreateInspection(restaurantID uint, date time.Time, score int, notes, filename string, report io.Reader) (*models.HealthInspection, error) {
+	if date.IsZero() {
+		return nil, Validation("missing_date", "an inspection date is required")
+	}
+	if score < 0 || score > 100 {
+		return nil, Validation("invalid_score", "score must be between 0 and 100")
+	}
+
+	inspection := &models.HealthInspection{
+		RestaurantID: restaurantID,
+		Date:         date.UTC(),
+		Score:        score,
+		Notes:        utils.SanitizeText(notes),
+	}
+
+	if report != nil {
+		ext := strings.ToLower(filepath.Ext(filename))
+		if ext != ".pdf" && ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+			return nil, Validation("invalid_report", "only PDF and image reports are supported")
+		}
+		if err := os.MkdirAll(s.mediaDir, 0o755); err != nil {
+			return nil, err
+		}
+		name := fmt.Sprintf("inspection-%d-%d%s", restaurantID, time.Now().UnixNano(), ext)
+		path := filepath.Join(s.mediaDir, name)
+		dst, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(dst, report); err != nil {
+			dst.Close()
+			os.Remove(path)
+			return nil, err
+		}
+		if err := dst.Close(); err != nil {
+			return nil, err
+		}
+		inspection.ReportPath = path
+	}
+
+	if err := s.healthRepo.Create(inspection); err != nil {
+		if inspection.ReportPath != "" {
+			os.Remove(inspection.ReportPath)
+		}
+		return nil, err
+	}
+	return inspection, nil
+}
+
+// GetInspectionByID returns the inspection with the given id.
+func (s *HealthService) GetInspectionByID(id uint) (*models.HealthInspection, error) {
+	inspection, err := s.healthRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "inspection_not_found", "health inspection not found")
+	}
+	return inspection, nil
+}
+
+// Inspections returns a restaurant's inspection history, most recent
+// visit first.
+func (s *HealthService) Inspections(restaurantID uint) ([]models.HealthInspection, error) {
+	return s.healthRepo.GetByRestaurantID(restaurantID)
+}
+
+// ReportPath returns the on-disk path of an inspection's attached
+// report.
+func (s *HealthService) ReportPath(id uint) (string, error) {
+	inspection, err := s.healthRepo.GetByID(id)
+	if err != nil {
+		return "", fromRepo(err, "inspection_not_found", "health inspection not found")
+	}
+	if inspection.ReportPath == "" {
+		return "", NotFound("report_not_found", "this inspection has no attached report")
+	}
+	return inspection.ReportPath, nil
+}
+
+// SetBadge opts the restaurant in or out of the public score badge.
+func (s *HealthService) SetBadge(restaurantID uint, enabled bool) error {
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	restaurant.ShowHealthScore = enabled
+	return s.restaurantRepo.Update(restaurant)
+}
+
+// HealthBadge is the public shape of a restaurant's latest inspection.
+type HealthBadge struct {
+	Score int    `json:"score"`
+	Date  string `json:"date"` // "2006-01-02"
+}
+
+// Badge returns the public badge of a restaurant: the latest inspection
+// score, but only when the restaurant opted in and has at least one
+// inspection on record.
+func (s *HealthService) Badge(restaurantID uint) (*HealthBadge, error) {
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	if !restaurant.ShowHealthScore {
+		return nil, NotFound("badge_not_available", "this restaurant does not publish its health score")
+	}
+	inspection, err := s.healthRepo.GetLatestByRestaurantID(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "badge_not_available", "this restaurant does not publish its health score")
+	}
+	return &HealthBadge{Score: inspection.Score, Date: inspection.Date.Format("2006-01-02")}, nil
+}
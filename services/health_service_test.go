@@ -0,0 +1,97 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func newHealthServiceForTest(t *testing.T) (*HealthService, *models.Restaurant) {
+	t.Helper()
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	restaurant := &models.Restaurant{Name: "Inspected", UserID: 1}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewHealthService(repositories.NewHealthRepository(db), repositories.NewRestaurantRepository(db), t.TempDir())
+	return svc, restaurant
+}
+
+func TestHealthBadgeIsOptInAndShowsLatestScore(t *testing.T) {
+	svc, restaurant := newHealthServiceForTest(t)
+
+	// No badge before the restaurant opts in, even with inspections on
+	// record.
+	older := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	if _, err := svc.CreateInspection(restaurant.ID, older, 82, "minor findings", "", nil); err != nil {
+		t.Fatalf("create inspection: %v", err)
+	}
+	if _, err := svc.Badge(restaurant.ID); err == nil {
+		t.Fatal("expected no badge before opting in")
+	}
+
+	if err := svc.SetBadge(restaurant.ID, true); err != nil {
+		t.Fatalf("enable badge: %v", err)
+	}
+	newer := time.Date(2026, 7, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := svc.CreateInspection(restaurant.ID, newer, 95, "all clear", "", nil); err != nil {
+		t.Fatalf("create inspection: %v", err)
+	}
+
+	badge, err := svc.Badge(restaurant.ID)
+	if err != nil {
+		t.Fatalf("badge: %v", err)
+	}
+	if badge.Score != 95 || badge.Date != "2026-07-02" {
+		t.Fatalf("expected latest inspection on badge, got %+v", badge)
+	}
+
+	if err := svc.SetBadge(restaurant.ID, false); err != nil {
+		t.Fatalf("disable badge: %v", err)
+	}
+	if _, err := svc.Badge(restaurant.ID); err == nil {
+		t.Fatal("expected no badge after opting out")
+	}
+}
+
+func TestCreateInspectionValidatesScoreAndReport(t *testing.T) {
+	svc, restaurant := newHealthServiceForTest(t)
+	date := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := svc.CreateInspection(restaurant.ID, date, 140, "", "", nil); err == nil {
+		t.Fatal("expected an out-of-range score to be rejected")
+	}
+	if _, err := svc.CreateInspection(restaurant.ID, date, 90, "", "report.exe", strings.NewReader("x")); err == nil {
+		t.Fatal("expected an unsupported report type to be rejected")
+	}
+
+	inspection, err := svc.CreateInspection(restaurant.ID, date, 90, "clean", "report.pdf", strings.NewReader("%PDF-1.4"))
+	if err != nil {
+		t.Fatalf("create inspection with report: %v", err)
+	}
+	path, err := svc.ReportPath(inspection.ID)
+	if err != nil {
+		t.Fatalf("report path: %v", err)
+	}
+	if !strings.HasSuffix(path, ".pdf") {
+		t.Fatalf("expected a .pdf report path, got %q", path)
+	}
+
+	// An inspection without an attachment has no report to serve.
+	bare, err := svc.CreateInspection(restaurant.ID, date.AddDate(0, 1, 0), 88, "", "", nil)
+	if err != nil {
+		t.Fatalf("create bare inspection: %v", err)
+	}
+	if _, err := svc.ReportPath(bare.ID); err == nil {
+		t.Fatal("expected a missing report to be reported as not found")
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// ImageRepo is the persistence surface for uploaded images.
+type ImageRepo interface {
+	Create(image *models.Image) error
+	GetByID(id uint) (*models.Image, error)
+	GetLatestByEntity(entityType string, entityID uint) (*models.Image, error)
+	Update(image *models.Image) error
+}
+
+// JobEnqueuer queues background work.
+type JobEnqueuer interface {
+	Enqueue(job *models.Job) error
+}
+
+// ImageService stores uploads and queues their processing.
+type ImageService struct {
+	images   ImageRepo
+	jobs     JobEnqueuer
+	mediaDir string
+}
+
+// NewImageService builds an ImageService writing into mediaDir.
+func NewImageService(images ImageRepo, jobs JobEnqueuer, mediaDir string) *ImageService {
+	return &ImageService{images: images, jobs: jobs, mediaDir: mediaDir}
+}
+
+// SaveUpload stores the original upload and queues variant generation.
+// The returned image is still pending; variants appear once the job runs.
+func (s *ImageService) SaveUpload(entityType string, entityID uint, filename string, src io.Reader) (*models.Image, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		return nil, errors.New("only JPEG and PNG uploads are supported")
+	}
+
+	if err := os.MkdirAll(s.mediaDir, 0o755); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("original-%s-%d-%d%s", entityType, entityID, time.Now().UnixNano(), ext)
+	path := filepath.Join(s.mediaDir, name)
+	dst, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(path)
+		return nil, err
+	}
+	if err := dst.Close(); err != nil {
+		return nil, err
+	}
+
+	image := &models.Image{
+		EntityType:   entityType,
+		EntityID:     entityID,
+		OriginalPath: path,
+		Status:       models.ImagePending,
+	}
+	if err := s.images.Create(image); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	payload, err := json.Marshal(map[string]uint{"image_id": image.ID})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.jobs.Enqueue(&models.Job{Type: "image.process", Payload: string(payload)}); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// GetLatest returns the most recent image of an entity.
+func (s *ImageService) GetLatest(entityType string, entityID uint) (*models.Image, error) {
+	image, err := s.images.GetLatestByEntity(entityType, entityID)
+	if err != nil {
+		return nil, fromRepo(err, "image_not_found", "image not found")
+	}
+	return image, nil
+}
+
+// VariantURLs maps variant names to public URLs for a processed image.
+// Pending or failed images only expose their status.
+func (s *ImageService) VariantURLs(image *models.Image) map[string]string {
+	urls := make(map[string]string)
+	for name, path := range map[string]string{
+		"thumb":  image.ThumbPath,
+		"medium": image.MediumPath,
+		"large":  image.LargePath,
+	} {
+		if path != "" {
+			urls[name] = "/media/" + filepath.Base(path)
+		}
+	}
+	return urls
+}
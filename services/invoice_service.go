@@ -0,0 +1,179 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// InvoiceService stores supplier invoices and runs them through
+// extraction and manual review before their figures are trusted.
+type InvoiceService struct {
+	invoiceRepo InvoiceRepo
+	jobs        JobEnqueuer
+	mediaDir    string
+}
+
+// NewInvoiceService builds an InvoiceService storing documents in
+// mediaDir.
+func NewInvoiceService(invoiceRepo InvoiceRepo, jobs JobEnqueuer, mediaDir string) *InvoiceService {
+	return &InvoiceService{invoiceRepo: invoiceRepo, jobs: jobs, mediaDir: mediaDir}
+}
+
+// Upload stores an invoice document and queues extraction. The returned
+// invoice is still pending; lines appear once the job runs.
+func (s *InvoiceService) Upload(restaurantID uint, filename string, file io.Reader) (*models.SupplierInvoice, error) {
+	if file == nil {
+		return nil, Validation("missing_file", "the invoice file is required")
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext != ".pdf" && ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		return nil, Validation("invalid_invoice", "only PDF and image invoices are supported")
+	}
+
+	if err := os.MkdirAll(s.mediaDir, 0o755); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("invoice-%d-%d%s", restaurantID, time.Now().UnixNano(), ext)
+	path := filepath.Join(s.mediaDir, name)
+	dst, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(dst, file); err != nil {
+		dst.Close()
+		os.Remove(path)
+		return nil, err
+	}
+	if err := dst.Close(); err != nil {
+		return nil, err
+	}
+
+	invoice := &models.SupplierInvoice{
+		RestaurantID: restaurantID,
+		Status:       models.InvoicePending,
+		FilePath:     path,
+	}
+	if err := s.invoiceRepo.Create(invoice); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	payload, err := json.Marshal(map[string]uint{"invoice_id": invoice.ID})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.jobs.Enqueue(&models.Job{Type: "invoice.extract", Payload: string(payload)}); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// GetInvoiceByID returns the invoice with the given id, lines included.
+func (s *InvoiceService) GetInvoiceByID(id uint) (*models.SupplierInvoice, error) {
+	invoice, err := s.invoiceRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "invoice_not_found", "supplier invoice not found")
+	}
+	return invoice, nil
+}
+
+// Invoices returns a restaurant's invoices, newest first.
+func (s *InvoiceService) Invoices(restaurantID uint) ([]models.SupplierInvoice, error) {
+	return s.invoiceRepo.GetByRestaurantID(restaurantID)
+}
+
+// InvoicePath returns the on-disk path of an invoice's document.
+func (s *InvoiceService) InvoicePath(id uint) (string, error) {
+	invoice, err := s.invoiceRepo.GetByID(id)
+	if err != nil {
+		return "", fromRepo(err, "invoice_not_found", "supplier invoice not found")
+	}
+	return invoice.FilePath, nil
+}
+
+// InvoiceLineInput is one reviewed purchase line.
+type InvoiceLineInput struct {
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	UnitCost    float64 `json:"unit_cost"`
+}
+
+// Review replaces an invoice's figures with the reviewer's corrections.
+// It also rescues invoices whose extraction failed: a manual review
+// supersedes whatever the provider did or did not read.
+func (s *InvoiceService) Review(id uint, supplier string, total float64, lines []InvoiceLineInput) (*models.SupplierInvoice, error) {
+	invoice, err := s.invoiceRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "invoice_not_found", "supplier invoice not found")
+	}
+	if invoice.Status == models.InvoiceConfirmed {
+		return nil, Conflict("already_confirmed", "this invoice is already confirmed")
+	}
+	if total < 0 {
+		return nil, Validation("invalid_total", "the total cannot be negative")
+	}
+
+	replacement := make([]models.SupplierInvoiceLine, 0, len(lines))
+	for _, line := range lines {
+		description := utils.SanitizeText(line.Description)
+		if description == "" {
+			return nil, Validation("invalid_line", "every line needs a description")
+		}
+		if line.Quantity <= 0 {
+			return nil, Validation("invalid_line", "line quantities must be positive")
+		}
+		if line.UnitCost < 0 {
+			return nil, Validation("invalid_line", "line costs cannot be negative")
+		}
+		replacement = append(replacement, models.SupplierInvoiceLine{
+			Description: description,
+			Quantity:    line.Quantity,
+			UnitCost:    line.UnitCost,
+		})
+	}
+	if err := s.invoiceRepo.ReplaceLines(invoice.ID, replacement); err != nil {
+		return nil, err
+	}
+
+	invoice.Supplier = utils.SanitizeText(supplier)
+	invoice.Total = total
+	invoice.Status = models.InvoiceExtracted
+	invoice.Lines = nil // Update must not re-save the stale preloaded lines
+	if err := s.invoiceRepo.Update(invoice); err != nil {
+		return nil, err
+	}
+	return s.GetInvoiceByID(invoice.ID)
+}
+
+// Confirm signs off an invoice's reviewed figures.
+func (s *InvoiceService) Confirm(id uint) (*models.SupplierInvoice, error) {
+	invoice, err := s.invoiceRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "invoice_not_found", "supplier invoice not found")
+	}
+	switch invoice.Status {
+	case models.InvoiceConfirmed:
+		return nil, Conflict("already_confirmed", "this invoice is already confirmed")
+	case models.InvoiceExtracted:
+	default:
+		return nil, Conflict("not_reviewed", "extraction has not finished; review the invoice first")
+	}
+	if len(invoice.Lines) == 0 {
+		return nil, Validation("no_lines", "an invoice needs at least one line before confirmation")
+	}
+
+	invoice.Status = models.InvoiceConfirmed
+	invoice.Lines = nil // Update must not re-save the stale preloaded lines
+	if err := s.invoiceRepo.Update(invoice); err != nil {
+		return nil, err
+	}
+	return s.GetInvoiceByID(invoice.ID)
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/jobs"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/ocr"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func newInvoiceServiceForTest(t *testing.T) (*InvoiceService, *models.Restaurant, *gorm.DB) {
+	t.Helper()
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	restaurant := &models.Restaurant{Name: "Supplied", UserID: 1}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewInvoiceService(repositories.NewInvoiceRepository(db), repositories.NewJobRepository(db), t.TempDir())
+	return svc, restaurant, db
+}
+
+func TestUploadQueuesExtraction(t *testing.T) {
+	svc, restaurant, db := newInvoiceServiceForTest(t)
+
+	if _, err := svc.Upload(restaurant.ID, "invoice.docx", strings.NewReader("x")); err == nil {
+		t.Fatal("expected an unsupported file type to be rejected")
+	}
+
+	invoice, err := svc.Upload(restaurant.ID, "invoice.pdf", strings.NewReader("%PDF-1.4"))
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if invoice.Status != models.InvoicePending {
+		t.Fatalf("expected a pending invoice, got %q", invoice.Status)
+	}
+
+	var job models.Job
+	if err := db.Where("type = ?", "invoice.extract").First(&job).Error; err != nil {
+		t.Fatalf("expected an extraction job: %v", err)
+	}
+	var payload jobs.InvoicePayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.InvoiceID != invoice.ID {
+		t.Fatalf("expected job for invoice %d, got %d", invoice.ID, payload.InvoiceID)
+	}
+}
+
+func TestReviewAndConfirmPipeline(t *testing.T) {
+	svc, restaurant, db := newInvoiceServiceForTest(t)
+
+	invoice, err := svc.Upload(restaurant.ID, "invoice.pdf", strings.NewReader("%PDF-1.4"))
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+
+	// A pending invoice cannot be confirmed yet.
+	if _, err := svc.Confirm(invoice.ID); err == nil {
+		t.Fatal("expected confirmation to require extraction first")
+	}
+
+	// Run the extraction job the way the worker would. The dev extractor
+	// prefills nothing, leaving everything to manual review.
+	handler := jobs.InvoiceHandler(repositories.NewInvoiceRepository(db), ocr.LogExtractor{})
+	payload, _ := json.Marshal(jobs.InvoicePayload{InvoiceID: invoice.ID})
+	if err := handler(payload); err != nil {
+		t.Fatalf("extraction job: %v", err)
+	}
+
+	// An extracted invoice without lines still cannot be confirmed.
+	if _, err := svc.Confirm(invoice.ID); err == nil {
+		t.Fatal("expected confirmation to require at least one line")
+	}
+
+	invoice, err = svc.Review(invoice.ID, "Distribuidora Sur", 41500, []InvoiceLineInput{
+		{Description: "Flour 25kg", Quantity: 2, UnitCost: 12000},
+		{Description: "Olive oil 5L", Quantity: 1, UnitCost: 17500},
+	})
+	if err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	if invoice.Status != models.InvoiceExtracted || len(invoice.Lines) != 2 {
+		t.Fatalf("expected a reviewed invoice with two lines, got %+v", invoice)
+	}
+
+	// Re-reviewing replaces the lines instead of stacking them.
+	invoice, err = svc.Review(invoice.ID, "Distribuidora Sur", 24000, []InvoiceLineInput{
+		{Description: "Flour 25kg", Quantity: 2, UnitCost: 12000},
+	})
+	if err != nil {
+		t.Fatalf("re-review: %v", err)
+	}
+	if len(invoice.Lines) != 1 || invoice.Total != 24000 {
+		t.Fatalf("expected the replaced line set, got %+v", invoice)
+	}
+
+	invoice, err = svc.Confirm(invoice.ID)
+	if err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	if invoice.Status != models.InvoiceConfirmed {
+		t.Fatalf("expected a confirmed invoice, got %q", invoice.Status)
+	}
+
+	if _, err := svc.Review(invoice.ID, "x", 1, nil); err == nil {
+		t.Fatal("expected a confirmed invoice to reject further edits")
+	}
+	if _, err := svc.Confirm(invoice.ID); err == nil {
+		t.Fatal("expected double confirmation to be rejected")
+	}
+}
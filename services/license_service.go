@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// licenseReminderDays is how far before expiry the renewal reminder
+// goes out.
+const licenseReminderDays = 30
+
+// LicenseService stores license and permit documents per restaurant and
+// chases owners before they expire.
+type LicenseService struct {
+	licenseRepo    LicenseRepo
+	restaurantRepo RestaurantRepo
+	userRepo       UserRepo
+	jobs           JobEnqueuer
+	mediaDir       string
+}
+
+// NewLicenseService builds a LicenseService storing document files in
+// mediaDir.
+func NewLicenseService(licenseRepo LicenseRepo, restaurantRepo RestaurantRepo, userRepo UserRepo, jobs JobEnqueuer, mediaDir string) *LicenseService {
+	return &LicenseService{licenseRepo: licenseRepo, restaurantRepo: restaurantRepo, userRepo: userRepo, jobs: jobs, mediaDir: mediaDir}
+}
+
+// UploadDocument stores a new license or permit document.
+func (s *LicenseService) UploadDocument(restaurantID uint, kind string, expiresAt time.Time, filename string, file io.Reader) (*models.LicenseDocument, error) {
+	kind = utils.SanitizeText(kind)
+	if kind == "" {
+		return nil, Validation("missing_kind", "the document kind is required")
+	}
+	if expiresAt.IsZero() {
+		return nil, Validation("missing_expiry", "an expiry date is required")
+	}
+	if file == nil {
+		return nil, Validation("missing_file", "the document file is required")
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext != ".pdf" && ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		return nil, Validation("invalid_document", "only PDF and image documents are supported")
+	}
+
+	if err := os.MkdirAll(s.mediaDir, 0o755); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("license-%d-%d%s", restaurantID, time.Now().UnixNano(), ext)
+	path := filepath.Join(s.mediaDir, name)
+	dst, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(dst, file); err != nil {
+		dst.Close()
+		os.Remove(path)
+		return nil, err
+	}
+	if err := dst.Close(); err != nil {
+		return nil, err
+	}
+
+	document := &models.LicenseDocument{
+		RestaurantID: restaurantID,
+		Kind:         kind,
+		ExpiresAt:    expiresAt.UTC(),
+		FilePath:     path,
+	}
+	if err := s.licenseRepo.Create(document); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return document, nil
+}
+
+// GetDocumentByID returns the document with the given id.
+func (s *LicenseService) GetDocumentByID(id uint) (*models.LicenseDocument, error) {
+	document, err := s.licenseRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "document_not_found", "license document not found")
+	}
+	return document, nil
+}
+
+// Documents returns a restaurant's documents, soonest to expire first.
+func (s *LicenseService) Documents(restaurantID uint) ([]models.LicenseDocument, error) {
+	return s.licenseRepo.GetByRestaurantID(restaurantID)
+}
+
+// DocumentPath returns the on-disk path of a document's file.
+func (s *LicenseService) DocumentPath(id uint) (string, error) {
+	document, err := s.licenseRepo.GetByID(id)
+	if err != nil {
+		return "", fromRepo(err, "document_not_found", "license document not found")
+	}
+	return document.FilePath, nil
+}
+
+// RunOnce sends the expiry reminders due on the given day and returns
+// how many went out. Per-document failures are logged and skipped so one
+// bad record cannot hold up the rest.
+func (s *LicenseService) RunOnce(now time.Time) (int, error) {
+	cutoff := now.UTC().AddDate(0, 0, licenseReminderDays)
+	documents, err := s.licenseRepo.ExpiringBefore(cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range documents {
+		if err := s.remind(&documents[i], now); err != nil {
+			log.Printf("licenses: document %d: %v", documents[i].ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// remind queues one document's expiry reminder to the restaurant's
+// owner and marks the document as reminded.
+func (s *LicenseService) remind(document *models.LicenseDocument, now time.Time) error {
+	restaurant, err := s.restaurantRepo.GetByID(document.RestaurantID)
+	if err != nil {
+		return err
+	}
+	owner, err := s.userRepo.GetByID(restaurant.UserID)
+	if err != nil {
+		return err
+	}
+
+	expiry := document.ExpiresAt.Format("2006-01-02")
+	payload, err := json.Marshal(map[string]interface{}{
+		"to":      owner.Email,
+		"subject": fmt.Sprintf("%s: %s expires on %s", restaurant.Name, document.Kind, expiry),
+		"text":    fmt.Sprintf("The %s on file for %s expires on %s. Upload the renewed document before then to stay compliant.", document.Kind, restaurant.Name, expiry),
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.jobs.Enqueue(&models.Job{Type: "email.send", Payload: string(payload)}); err != nil {
+		return fmt.Errorf("queueing reminder: %w", err)
+	}
+
+	remindedAt := now.UTC()
+	document.RemindedAt = &remindedAt
+	return s.licenseRepo.Update(document)
+}
+
+// Start runs the daily reminder loop until ctx is cancelled. It checks
+// hourly and runs once per calendar day, same as the birthday loop.
+func (s *LicenseService) Start(ctx context.Context) {
+	run := func(now time.Time) {
+		sent, err := s.RunOnce(now)
+		if err != nil {
+			log.Printf("licenses: daily run: %v", err)
+			return
+		}
+		if sent > 0 {
+			log.Printf("licenses: sent %d expiry reminder(s)", sent)
+		}
+	}
+	now := time.Now().UTC()
+	run(now)
+	lastDay := now.Format("2006-01-02")
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now().UTC()
+			if day := now.Format("2006-01-02"); day != lastDay {
+				run(now)
+				lastDay = day
+			}
+		}
+	}
+}
+
+// Compliance document statuses as shown on the admin overview.
+const (
+	LicenseValid    = "valid"
+	LicenseExpiring = "expiring"
+	LicenseExpired  = "expired"
+	LicenseMissing  = "missing"
+)
+
+// ComplianceRow is one line of the admin compliance overview.
+type ComplianceRow struct {
+	RestaurantID   string `json:"restaurant_id"`
+	RestaurantName string `json:"restaurant_name"`
+	Kind           string `json:"kind,omitempty"`
+	ExpiresAt      string `json:"expires_at,omitempty"` // "2006-01-02"
+	Status         string `json:"status"`
+}
+
+// ComplianceOverview reports every restaurant's documents and their
+// expiry status. Restaurants with no documents on file get a single
+// "missing" row so they do not disappear from the report.
+func (s *LicenseService) ComplianceOverview(now time.Time) ([]ComplianceRow, error) {
+	restaurants, err := s.restaurantRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := now.UTC().AddDate(0, 0, licenseReminderDays)
+	rows := make([]ComplianceRow, 0, len(restaurants))
+	for i := range restaurants {
+		restaurant := &restaurants[i]
+		documents, err := s.licenseRepo.GetByRestaurantID(restaurant.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(documents) == 0 {
+			rows = append(rows, ComplianceRow{
+				RestaurantID:   restaurant.PublicID,
+				RestaurantName: restaurant.Name,
+				Status:         LicenseMissing,
+			})
+			continue
+		}
+		for _, document := range documents {
+			status := LicenseValid
+			switch {
+			case document.ExpiresAt.Before(now):
+				status = LicenseExpired
+			case document.ExpiresAt.Before(cutoff):
+				status = LicenseExpiring
+			}
+			rows = append(rows, ComplianceRow{
+				RestaurantID:   restaurant.PublicID,
+				RestaurantName: restaurant.Name,
+				Kind:           document.Kind,
+				ExpiresAt:      document.ExpiresAt.Format("2006-01-02"),
+				Status:         status,
+			})
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func newLicenseServiceForTest(t *testing.T) (*LicenseService, *models.Restaurant, *gorm.DB) {
+	t.Helper()
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Olga", Email: "olga@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Licensed", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewLicenseService(
+		repositories.NewLicenseRepository(db),
+		repositories.NewRestaurantRepository(db),
+		repositories.NewUserRepository(db),
+		repositories.NewJobRepository(db),
+		t.TempDir(),
+	)
+	return svc, restaurant, db
+}
+
+func TestExpiryReminderGoesOutOncePerDocument(t *testing.T) {
+	svc, restaurant, db := newLicenseServiceForTest(t)
+	now := time.Date(2026, 8, 29, 9, 0, 0, 0, time.UTC)
+
+	// One document inside the reminder window, one comfortably outside.
+	expiring := now.AddDate(0, 0, 10)
+	if _, err := svc.UploadDocument(restaurant.ID, "liquor_license", expiring, "license.pdf", strings.NewReader("%PDF-1.4")); err != nil {
+		t.Fatalf("upload expiring document: %v", err)
+	}
+	if _, err := svc.UploadDocument(restaurant.ID, "food_permit", now.AddDate(1, 0, 0), "permit.pdf", strings.NewReader("%PDF-1.4")); err != nil {
+		t.Fatalf("upload valid document: %v", err)
+	}
+
+	sent, err := svc.RunOnce(now)
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if sent != 1 {
+		t.Fatalf("expected one reminder, got %d", sent)
+	}
+
+	var jobs []models.Job
+	if err := db.Where("type = ?", "email.send").Find(&jobs).Error; err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	if len(jobs) != 1 || !strings.Contains(jobs[0].Payload, "olga@example.com") {
+		t.Fatalf("expected one reminder email to the owner, got %+v", jobs)
+	}
+
+	// The next day's run must not nag about the same document again.
+	sent, err = svc.RunOnce(now.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("RunOnce again: %v", err)
+	}
+	if sent != 0 {
+		t.Fatalf("expected no repeat reminder, got %d", sent)
+	}
+}
+
+func TestComplianceOverviewFlagsExpiryAndMissingDocuments(t *testing.T) {
+	svc, restaurant, db := newLicenseServiceForTest(t)
+	now := time.Date(2026, 8, 29, 9, 0, 0, 0, time.UTC)
+
+	bare := &models.Restaurant{Name: "Paperless", UserID: restaurant.UserID}
+	if err := db.Create(bare).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	if _, err := svc.UploadDocument(restaurant.ID, "liquor_license", now.AddDate(0, 0, -5), "old.pdf", strings.NewReader("%PDF-1.4")); err != nil {
+		t.Fatalf("upload expired document: %v", err)
+	}
+	if _, err := svc.UploadDocument(restaurant.ID, "food_permit", now.AddDate(0, 0, 14), "soon.pdf", strings.NewReader("%PDF-1.4")); err != nil {
+		t.Fatalf("upload expiring document: %v", err)
+	}
+
+	rows, err := svc.ComplianceOverview(now)
+	if err != nil {
+		t.Fatalf("ComplianceOverview: %v", err)
+	}
+	statuses := map[string]string{}
+	for _, row := range rows {
+		key := row.RestaurantName + "/" + row.Kind
+		statuses[key] = row.Status
+	}
+	if statuses["Licensed/liquor_license"] != LicenseExpired {
+		t.Fatalf("expected expired liquor license, got %v", statuses)
+	}
+	if statuses["Licensed/food_permit"] != LicenseExpiring {
+		t.Fatalf("expected expiring food permit, got %v", statuses)
+	}
+	if statuses["Paperless/"] != LicenseMissing {
+		t.Fatalf("expected missing row for restaurant without documents, got %v", statuses)
+	}
+}
+
+func TestUploadDocumentValidatesKindAndFileType(t *testing.T) {
+	svc, restaurant, _ := newLicenseServiceForTest(t)
+	expiry := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := svc.UploadDocument(restaurant.ID, "", expiry, "license.pdf", strings.NewReader("x")); err == nil {
+		t.Fatal("expected a missing kind to be rejected")
+	}
+	if _, err := svc.UploadDocument(restaurant.ID, "liquor_license", expiry, "license.exe", strings.NewReader("x")); err == nil {
+		t.Fatal("expected an unsupported file type to be rejected")
+	}
+	if _, err := svc.UploadDocument(restaurant.ID, "liquor_license", expiry, "license.pdf", nil); err == nil {
+		t.Fatal("expected a missing file to be rejected")
+	}
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"log"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/wallet"
+)
+
+// loyaltyPesosPerPoint converts completed-order spend into points: one
+// point per full thousand pesos. The balance is always derived from
+// order history rather than stored, so it cannot drift.
+const loyaltyPesosPerPoint = 1000
+
+// LoyaltyService issues wallet passes carrying a customer's loyalty
+// balance and pushes updates to the wallet platforms when it changes.
+type LoyaltyService struct {
+	passRepo       LoyaltyPassRepo
+	orderRepo      OrderRepo
+	restaurantRepo RestaurantRepo
+	userRepo       UserRepo
+	provider       wallet.Provider
+}
+
+// NewLoyaltyService builds a LoyaltyService.
+func NewLoyaltyService(passRepo LoyaltyPassRepo, orderRepo OrderRepo, restaurantRepo RestaurantRepo, userRepo UserRepo, provider wallet.Provider) *LoyaltyService {
+	return &LoyaltyService{passRepo: passRepo, orderRepo: orderRepo, restaurantRepo: restaurantRepo, userRepo: userRepo, provider: provider}
+}
+
+// Balance computes a customer's points at a restaurant from their
+// completed orders.
+func (s *LoyaltyService) Balance(userID, restaurantID uint) (int, error) {
+	total, err := s.orderRepo.CompletedTotalByCustomer(restaurantID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return int(total) / loyaltyPesosPerPoint, nil
+}
+
+// IssuePass creates (or re-issues) a customer's pass for a restaurant
+// on one wallet platform and registers it with the platform.
+func (s *LoyaltyService) IssuePass(userID, restaurantID uint, platform string) (*models.LoyaltyPass, error) {
+	if platform != models.WalletPlatformApple && platform != models.WalletPlatformGoogle {
+		return nil, Validation("invalid_platform", "unknown wallet platform")
+	}
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fromRepo(err, "user_not_found", "user not found")
+	}
+	balance, err := s.Balance(userID, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	pass, err := s.passRepo.GetByUserAndRestaurantAndPlatform(userID, restaurantID, platform)
+	if err != nil {
+		pass = &models.LoyaltyPass{UserID: userID, RestaurantID: restaurantID, Platform: platform}
+		if err := s.passRepo.Create(pass); err != nil {
+			return nil, err
+		}
+	}
+	pass.Balance = balance
+
+	url, err := s.provider.IssuePass(wallet.Pass{
+		Serial:       pass.Serial,
+		Platform:     pass.Platform,
+		Restaurant:   restaurant.Name,
+		CustomerName: user.Name,
+		Balance:      balance,
+	})
+	if err != nil {
+		return nil, err
+	}
+	pass.PassURL = url
+	if err := s.passRepo.Update(pass); err != nil {
+		return nil, err
+	}
+	return pass, nil
+}
+
+// Passes returns a customer's passes.
+func (s *LoyaltyService) Passes(userID uint) ([]models.LoyaltyPass, error) {
+	return s.passRepo.GetByUserID(userID)
+}
+
+// PassState is what a wallet platform fetches through the update
+// protocol: the pass fields it renders.
+type PassState struct {
+	Serial     string `json:"serial"`
+	Restaurant string `json:"restaurant"`
+	Balance    int    `json:"balance"`
+}
+
+// PassForPlatform returns the latest state of a pass to a wallet
+// platform holding its auth token. A wrong token reads as a missing
+// pass so probing reveals nothing.
+func (s *LoyaltyService) PassForPlatform(serial, authToken string) (*PassState, error) {
+	pass, err := s.passRepo.GetBySerial(serial)
+	if err != nil {
+		return nil, fromRepo(err, "pass_not_found", "pass not found")
+	}
+	if authToken == "" || pass.AuthToken != authToken {
+		return nil, NotFound("pass_not_found", "pass not found")
+	}
+	restaurant, err := s.restaurantRepo.GetByID(pass.RestaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	balance, err := s.Balance(pass.UserID, pass.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if balance != pass.Balance {
+		pass.Balance = balance
+		if err := s.passRepo.Update(pass); err != nil {
+			return nil, err
+		}
+	}
+	return &PassState{Serial: pass.Serial, Restaurant: restaurant.Name, Balance: balance}, nil
+}
+
+// RefreshForOrder recomputes the ordering customer's balance and pushes
+// it to every pass they hold at the restaurant. Guest orders carry no
+// account, so there is nothing to refresh.
+func (s *LoyaltyService) RefreshForOrder(order *models.Order) error {
+	if order.CustomerID == 0 {
+		return nil
+	}
+	passes, err := s.passRepo.GetByUserAndRestaurant(order.CustomerID, order.RestaurantID)
+	if err != nil {
+		return err
+	}
+	if len(passes) == 0 {
+		return nil
+	}
+	restaurant, err := s.restaurantRepo.GetByID(order.RestaurantID)
+	if err != nil {
+		return err
+	}
+	balance, err := s.Balance(order.CustomerID, order.RestaurantID)
+	if err != nil {
+		return err
+	}
+	for i := range passes {
+		pass := &passes[i]
+		if pass.Balance != balance {
+			pass.Balance = balance
+			if err := s.passRepo.Update(pass); err != nil {
+				return err
+			}
+		}
+		if err := s.provider.PushUpdate(wallet.Pass{
+			Serial:     pass.Serial,
+			Platform:   pass.Platform,
+			Restaurant: restaurant.Name,
+			Balance:    balance,
+		}); err != nil {
+			// One platform being down must not block the others.
+			log.Printf("loyalty: pushing pass %s: %v", pass.Serial, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/cache"
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/wallet"
+)
+
+// fakeWalletProvider records pass traffic.
+type fakeWalletProvider struct {
+	issued []wallet.Pass
+	pushed []wallet.Pass
+}
+
+func (f *fakeWalletProvider) IssuePass(pass wallet.Pass) (string, error) {
+	f.issued = append(f.issued, pass)
+	return "https://wallet.example/" + pass.Serial, nil
+}
+
+func (f *fakeWalletProvider) PushUpdate(pass wallet.Pass) error {
+	f.pushed = append(f.pushed, pass)
+	return nil
+}
+
+func TestLoyaltyPassTracksCompletedOrders(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Loy", Email: "loy@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Puntos", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+	customer := &models.User{Name: "Clara", Email: "clara@example.com", Password: "x", Role: models.RoleCustomer}
+	if err := db.Create(customer).Error; err != nil {
+		t.Fatalf("create customer: %v", err)
+	}
+
+	menuSvc := NewMenuService(repositories.NewMenuRepository(db), cache.NewMemory(), nil, nil)
+	item, err := menuSvc.CreateMenuItem(restaurant.ID, "Lomo a lo pobre", "", "mains", 12500, 0)
+	if err != nil {
+		t.Fatalf("create menu item: %v", err)
+	}
+
+	provider := &fakeWalletProvider{}
+	loyaltySvc := NewLoyaltyService(repositories.NewLoyaltyPassRepository(db), repositories.NewOrderRepository(db), repositories.NewRestaurantRepository(db), repositories.NewUserRepository(db), provider)
+	orderSvc := NewOrderService(
+		repositories.NewOrderRepository(db),
+		repositories.NewMenuRepository(db),
+		repositories.NewRefundRepository(db),
+		repositories.NewTxManager(db),
+		repositories.NewOutboxRepository(db),
+		payments.LogProvider{},
+		nil,
+		nil,
+		loyaltySvc,
+		nil,
+		19,
+	)
+
+	if _, err := loyaltySvc.IssuePass(customer.ID, restaurant.ID, "samsung"); err == nil {
+		t.Fatal("expected an unknown platform to be rejected")
+	}
+	pass, err := loyaltySvc.IssuePass(customer.ID, restaurant.ID, models.WalletPlatformApple)
+	if err != nil {
+		t.Fatalf("issue pass: %v", err)
+	}
+	if pass.Serial == "" || pass.PassURL == "" || pass.Balance != 0 {
+		t.Fatalf("expected a fresh zero-balance pass with a URL, got %+v", pass)
+	}
+
+	// Completing an order earns one point per thousand pesos and pushes
+	// the new balance to the installed pass.
+	order, err := orderSvc.CreateOrder(restaurant.ID, customer.ID, "Clara", "clara@example.com", []OrderLine{{MenuItemID: item.ID, Quantity: 2}}, 0)
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	if _, err := orderSvc.UpdateOrderStatus(order.ID, models.OrderCompleted); err != nil {
+		t.Fatalf("complete order: %v", err)
+	}
+	if len(provider.pushed) != 1 || provider.pushed[0].Balance != 25 {
+		t.Fatalf("expected one push carrying 25 points, got %+v", provider.pushed)
+	}
+
+	// The update protocol serves the same balance, and only to a caller
+	// holding the pass token.
+	stored, err := repositories.NewLoyaltyPassRepository(db).GetBySerial(pass.Serial)
+	if err != nil {
+		t.Fatalf("reload pass: %v", err)
+	}
+	state, err := loyaltySvc.PassForPlatform(pass.Serial, stored.AuthToken)
+	if err != nil {
+		t.Fatalf("fetch pass state: %v", err)
+	}
+	if state.Balance != 25 || state.Restaurant != "Puntos" {
+		t.Fatalf("expected 25 points on the pass, got %+v", state)
+	}
+	if _, err := loyaltySvc.PassForPlatform(pass.Serial, "wrong"); err == nil {
+		t.Fatal("expected a wrong token to be rejected")
+	}
+
+	// Re-issuing on the same platform reuses the pass with the live
+	// balance instead of minting a second serial.
+	again, err := loyaltySvc.IssuePass(customer.ID, restaurant.ID, models.WalletPlatformApple)
+	if err != nil {
+		t.Fatalf("reissue pass: %v", err)
+	}
+	if again.Serial != pass.Serial || again.Balance != 25 {
+		t.Fatalf("expected the same pass at 25 points, got %+v", again)
+	}
+}
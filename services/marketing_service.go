@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/marketing"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// segmentSource is the slice of SegmentService that contact syncing
+// needs.
+type segmentSource interface {
+	GetSegmentByID(id uint) (*models.Segment, error)
+	Members(id uint) ([]SegmentMember, error)
+}
+
+// consentChecker is the slice of ConsentService that marketing send
+// paths need.
+type consentChecker interface {
+	Allowed(email, channel string) (bool, error)
+}
+
+// MarketingService pushes customer contacts to the configured marketing
+// platform.
+type MarketingService struct {
+	marketingRepo MarketingRepo
+	segments      segmentSource
+	consents      consentChecker
+	provider      marketing.Provider
+}
+
+// NewMarketingService builds a MarketingService.
+func NewMarketingService(marketingRepo MarketingRepo, segments segmentSource, consents consentChecker, provider marketing.Provider) *MarketingService {
+	return &MarketingService{marketingRepo: marketingRepo, segments: segments, consents: consents, provider: provider}
+}
+
+// Configure stores a restaurant's marketing platform credentials,
+// replacing any previous ones.
+func (s *MarketingService) Configure(restaurantID uint, apiKey, listID string) (*models.MarketingAccount, error) {
+	if apiKey == "" || listID == "" {
+		return nil, Validation("invalid_marketing_account", "api key and list id are required")
+	}
+	account, err := s.marketingRepo.GetAccount(restaurantID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		account = &models.MarketingAccount{RestaurantID: restaurantID}
+	} else if err != nil {
+		return nil, err
+	}
+	account.APIKey = apiKey
+	account.ListID = listID
+	if err := s.marketingRepo.SaveAccount(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// Status returns a restaurant's marketing account, including the
+// outcome of its last sync.
+func (s *MarketingService) Status(restaurantID uint) (*models.MarketingAccount, error) {
+	account, err := s.marketingRepo.GetAccount(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "marketing_not_configured", "marketing account not configured")
+	}
+	return account, nil
+}
+
+// SyncSegment pushes a segment's members to the marketing platform,
+// tagged with the segment name. The outcome — success or failure — is
+// recorded on the account either way, so status reporting never goes
+// stale silently.
+func (s *MarketingService) SyncSegment(restaurantID, segmentID uint) (*models.MarketingAccount, error) {
+	account, err := s.marketingRepo.GetAccount(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "marketing_not_configured", "marketing account not configured")
+	}
+	segment, err := s.segments.GetSegmentByID(segmentID)
+	if err != nil {
+		return nil, err
+	}
+	if segment.RestaurantID != restaurantID {
+		return nil, NotFound("segment_not_found", "segment not found")
+	}
+	members, err := s.segments.Members(segmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only customers who explicitly opted in to marketing email leave
+	// the platform; everyone else is silently dropped from the sync.
+	contacts := make([]marketing.Contact, 0, len(members))
+	for _, m := range members {
+		allowed, err := s.consents.Allowed(m.Email, models.ConsentChannelEmail)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			continue
+		}
+		contacts = append(contacts, marketing.Contact{Email: m.Email, Name: m.Name, Tags: []string{segment.Name}})
+	}
+
+	synced, syncErr := s.provider.SyncContacts(account.APIKey, account.ListID, contacts)
+	now := time.Now().UTC()
+	account.LastSyncAt = &now
+	account.LastSyncCount = synced
+	if syncErr != nil {
+		account.LastSyncStatus = models.MarketingSyncFailed
+		account.LastSyncError = syncErr.Error()
+	} else {
+		account.LastSyncStatus = models.MarketingSyncOK
+		account.LastSyncError = ""
+	}
+	if err := s.marketingRepo.SaveAccount(account); err != nil {
+		return nil, err
+	}
+	if syncErr != nil {
+		return nil, Conflict("sync_failed", syncErr.Error())
+	}
+	return account, nil
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/marketing"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// stubMarketingProvider records what it was asked to sync.
+type stubMarketingProvider struct {
+	contacts []marketing.Contact
+	err      error
+}
+
+func (p *stubMarketingProvider) SyncContacts(apiKey, listID string, contacts []marketing.Contact) (int, error) {
+	p.contacts = contacts
+	if p.err != nil {
+		return 0, p.err
+	}
+	return len(contacts), nil
+}
+
+func TestSyncSegmentRecordsOutcome(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	orders := []models.Order{
+		{RestaurantID: 1, CustomerName: "Ana", CustomerEmail: "ana@example.com", Total: 9000},
+		{RestaurantID: 1, CustomerName: "Ben", CustomerEmail: "ben@example.com", Total: 7000},
+	}
+	for i := range orders {
+		if err := db.Create(&orders[i]).Error; err != nil {
+			t.Fatalf("creating order: %v", err)
+		}
+	}
+
+	segmentService := NewSegmentService(repositories.NewSegmentRepository(db))
+	segment, err := segmentService.CreateSegment(1, "regulars", 1, 0, 90)
+	if err != nil {
+		t.Fatalf("CreateSegment: %v", err)
+	}
+
+	// Only Ana has opted in to marketing email; Ben must never be synced.
+	consentService := NewConsentService(repositories.NewConsentRepository(db))
+	if _, err := consentService.Set("ana@example.com", models.ConsentChannelEmail, true, "test"); err != nil {
+		t.Fatalf("Set consent: %v", err)
+	}
+
+	provider := &stubMarketingProvider{}
+	svc := NewMarketingService(repositories.NewMarketingRepository(db), segmentService, consentService, provider)
+
+	// Syncing without credentials must fail cleanly.
+	if _, err := svc.SyncSegment(1, segment.ID); err == nil {
+		t.Fatal("expected sync without an account to fail")
+	}
+
+	if _, err := svc.Configure(1, "key-us6", "list-1"); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	account, err := svc.SyncSegment(1, segment.ID)
+	if err != nil {
+		t.Fatalf("SyncSegment: %v", err)
+	}
+	if account.LastSyncStatus != models.MarketingSyncOK || account.LastSyncCount != 1 || account.LastSyncAt == nil {
+		t.Fatalf("unexpected account after sync: %+v", account)
+	}
+	if len(provider.contacts) != 1 || provider.contacts[0].Email != "ana@example.com" {
+		t.Fatalf("unexpected contacts: %+v", provider.contacts)
+	}
+	if len(provider.contacts[0].Tags) != 1 || provider.contacts[0].Tags[0] != "regulars" {
+		t.Fatalf("expected the segment name as tag, got %+v", provider.contacts[0].Tags)
+	}
+
+	// A provider failure is surfaced and recorded on the account.
+	provider.err = errors.New("list does not exist")
+	if _, err := svc.SyncSegment(1, segment.ID); err == nil {
+		t.Fatal("expected a failing provider to surface an error")
+	}
+	status, err := svc.Status(1)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.LastSyncStatus != models.MarketingSyncFailed || status.LastSyncError == "" {
+		t.Fatalf("unexpected account after failed sync: %+v", status)
+	}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/cache"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// menuCacheTTL bounds staleness for cached menus; writes invalidate
+// eagerly so the TTL is only a safety net.
+const menuCacheTTL = 5 * time.Minute
+
+// discountApprovalPct is the price cut, as a percentage of the current
+// price, above which a manager must approve the re-pricing.
+const discountApprovalPct = 30
+
+// ApprovedDiscountKind names discount approvals in the approvals
+// subsystem.
+const ApprovedDiscountKind = "menu.discount"
+
+// MenuService implements menu management.
+type MenuService struct {
+	menuRepo  MenuRepo
+	cache     cache.Cache
+	index     SearchIndex       // nil when full-text search is off
+	approvals approvalRequester // nil disables the discount gate
+}
+
+// NewMenuService builds a MenuService.
+func NewMenuService(menuRepo MenuRepo, c cache.Cache, index SearchIndex, approvals approvalRequester) *MenuService {
+	return &MenuService{menuRepo: menuRepo, cache: c, index: index, approvals: approvals}
+}
+
+// syncIndex mirrors a write into the search index; failures are logged,
+// not surfaced.
+func (s *MenuService) syncIndex(op func() error) {
+	if s.index == nil {
+		return
+	}
+	if err := op(); err != nil {
+		log.Printf("search index: %v", err)
+	}
+}
+
+// CreateMenuItem creates a menu item for the given restaurant.
+func (s *MenuService) CreateMenuItem(restaurantID uint, name, description, category string, price, cost float64) (*models.MenuItem, error) {
+	item := &models.MenuItem{
+		RestaurantID: restaurantID,
+		Name:         utils.SanitizeText(name),
+		Description:  utils.SanitizeText(description),
+		Category:     utils.SanitizeText(category),
+		Price:        price,
+		Cost:         cost,
+		Available:    true,
+	}
+	if err := s.menuRepo.Create(item); err != nil {
+		return nil, err
+	}
+	s.cache.Delete(cache.MenuKey(restaurantID))
+	s.syncIndex(func() error { return s.index.IndexMenuItem(item) })
+	return item, nil
+}
+
+// GetMenuItemByID returns the menu item with the given id.
+func (s *MenuService) GetMenuItemByID(id uint) (*models.MenuItem, error) {
+	item, err := s.menuRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "menu_item_not_found", "menu item not found")
+	}
+	return item, nil
+}
+
+// GetMenuByRestaurantID returns the full menu of a restaurant, served
+// from cache when possible.
+func (s *MenuService) GetMenuByRestaurantID(restaurantID uint) ([]models.MenuItem, error) {
+	key := cache.MenuKey(restaurantID)
+	if cached, ok := s.cache.Get(key); ok {
+		if items, ok := cached.([]models.MenuItem); ok {
+			return items, nil
+		}
+	}
+
+	items, err := s.menuRepo.GetByRestaurantID(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(key, items, menuCacheTTL)
+	return items, nil
+}
+
+// UpdateMenuItem updates the fields of an existing menu item. Empty
+// fields are left untouched. Price changes are recorded against the
+// calling user.
+func (s *MenuService) UpdateMenuItem(id, callerID uint, name, description, category string, price, cost float64, available bool) (*models.MenuItem, error) {
+	item, err := s.menuRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "menu_item_not_found", "menu item not found")
+	}
+	if name != "" {
+		item.Name = utils.SanitizeText(name)
+	}
+	if description != "" {
+		item.Description = utils.SanitizeText(description)
+	}
+	if category != "" {
+		item.Category = utils.SanitizeText(category)
+	}
+	oldPrice := item.Price
+	if price > 0 {
+		if s.approvals != nil && oldPrice > 0 && price < oldPrice*(1-discountApprovalPct/100.0) {
+			payload, err := json.Marshal(discountApprovalPayload{MenuItemID: item.ID, Price: price, ChangedBy: callerID})
+			if err != nil {
+				return nil, err
+			}
+			summary := fmt.Sprintf("reprice %s from %.0f to %.0f", item.Name, oldPrice, price)
+			if _, err := s.approvals.Request(item.RestaurantID, ApprovedDiscountKind, item.ID, summary, oldPrice-price, string(payload)); err != nil {
+				return nil, err
+			}
+			return nil, Conflict("approval_required", "discounts this large need a manager's approval")
+		}
+		item.Price = price
+	}
+	if cost > 0 {
+		item.Cost = cost
+	}
+	item.Available = available
+	if err := s.menuRepo.Update(item); err != nil {
+		return nil, err
+	}
+	if item.Price != oldPrice {
+		// History is best-effort bookkeeping; a failed insert must not
+		// roll back the re-pricing itself.
+		if err := s.menuRepo.CreatePriceChange(&models.PriceChange{
+			MenuItemID: item.ID,
+			OldPrice:   oldPrice,
+			NewPrice:   item.Price,
+			ChangedBy:  callerID,
+		}); err != nil {
+			log.Printf("menu: recording price change for item %d: %v", item.ID, err)
+		}
+	}
+	s.cache.Delete(cache.MenuKey(item.RestaurantID))
+	s.syncIndex(func() error { return s.index.IndexMenuItem(item) })
+	return item, nil
+}
+
+// discountApprovalPayload is what the discount executor needs once the
+// approval is granted.
+type discountApprovalPayload struct {
+	MenuItemID uint    `json:"menu_item_id"`
+	Price      float64 `json:"price"`
+	ChangedBy  uint    `json:"changed_by"`
+}
+
+// ExecuteApprovedDiscount applies a price cut whose approval was
+// granted, recording the change in the price history like any other
+// re-pricing.
+func (s *MenuService) ExecuteApprovedDiscount(payload string) (*models.MenuItem, error) {
+	var req discountApprovalPayload
+	if err := json.Unmarshal([]byte(payload), &req); err != nil {
+		return nil, err
+	}
+	item, err := s.menuRepo.GetByID(req.MenuItemID)
+	if err != nil {
+		return nil, fromRepo(err, "menu_item_not_found", "menu item not found")
+	}
+
+	oldPrice := item.Price
+	item.Price = req.Price
+	if err := s.menuRepo.Update(item); err != nil {
+		return nil, err
+	}
+	if item.Price != oldPrice {
+		if err := s.menuRepo.CreatePriceChange(&models.PriceChange{
+			MenuItemID: item.ID,
+			OldPrice:   oldPrice,
+			NewPrice:   item.Price,
+			ChangedBy:  req.ChangedBy,
+		}); err != nil {
+			log.Printf("menu: recording price change for item %d: %v", item.ID, err)
+		}
+	}
+	s.cache.Delete(cache.MenuKey(item.RestaurantID))
+	s.syncIndex(func() error { return s.index.IndexMenuItem(item) })
+	return item, nil
+}
+
+// PriceHistory returns a menu item's recorded price changes, newest
+// first.
+func (s *MenuService) PriceHistory(id uint) ([]models.PriceChange, error) {
+	if _, err := s.menuRepo.GetByID(id); err != nil {
+		return nil, fromRepo(err, "menu_item_not_found", "menu item not found")
+	}
+	return s.menuRepo.GetPriceHistory(id)
+}
+
+// DeleteMenuItem soft-deletes the menu item with the given id.
+func (s *MenuService) DeleteMenuItem(id uint) error {
+	item, err := s.menuRepo.GetByID(id)
+	if err != nil {
+		return fromRepo(err, "menu_item_not_found", "menu item not found")
+	}
+	if err := s.menuRepo.Delete(id); err != nil {
+		return err
+	}
+	s.cache.Delete(cache.MenuKey(item.RestaurantID))
+	s.syncIndex(func() error { return s.index.RemoveMenuItem(id) })
+	return nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/cache"
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func newMenuServiceForTest(t *testing.T) (*MenuService, uint) {
+	t.Helper()
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+	restaurant := models.Restaurant{Name: "Testaurant", UserID: 1}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("creating restaurant: %v", err)
+	}
+	return NewMenuService(repositories.NewMenuRepository(db), cache.NewMemory(), nil, nil), restaurant.ID
+}
+
+func TestMenuCacheInvalidatedOnUpdate(t *testing.T) {
+	svc, restaurantID := newMenuServiceForTest(t)
+
+	item, err := svc.CreateMenuItem(restaurantID, "Empanada", "", "starters", 2500, 0)
+	if err != nil {
+		t.Fatalf("CreateMenuItem: %v", err)
+	}
+
+	// Prime the cache.
+	if _, err := svc.GetMenuByRestaurantID(restaurantID); err != nil {
+		t.Fatalf("GetMenuByRestaurantID: %v", err)
+	}
+
+	if _, err := svc.UpdateMenuItem(item.ID, 1, "Empanada de pino", "", "", 2800, 0, true); err != nil {
+		t.Fatalf("UpdateMenuItem: %v", err)
+	}
+
+	menu, err := svc.GetMenuByRestaurantID(restaurantID)
+	if err != nil {
+		t.Fatalf("GetMenuByRestaurantID after update: %v", err)
+	}
+	if len(menu) != 1 || menu[0].Name != "Empanada de pino" || menu[0].Price != 2800 {
+		t.Fatalf("stale menu served after update: %+v", menu)
+	}
+}
+
+func TestMenuCacheInvalidatedOnDelete(t *testing.T) {
+	svc, restaurantID := newMenuServiceForTest(t)
+
+	item, err := svc.CreateMenuItem(restaurantID, "Sopaipilla", "", "starters", 800, 0)
+	if err != nil {
+		t.Fatalf("CreateMenuItem: %v", err)
+	}
+
+	if _, err := svc.GetMenuByRestaurantID(restaurantID); err != nil {
+		t.Fatalf("GetMenuByRestaurantID: %v", err)
+	}
+
+	if err := svc.DeleteMenuItem(item.ID); err != nil {
+		t.Fatalf("DeleteMenuItem: %v", err)
+	}
+
+	menu, err := svc.GetMenuByRestaurantID(restaurantID)
+	if err != nil {
+		t.Fatalf("GetMenuByRestaurantID after delete: %v", err)
+	}
+	if len(menu) != 0 {
+		t.Fatalf("deleted item still served from cache: %+v", menu)
+	}
+}
+
+func TestUpdateMenuItemRecordsPriceHistory(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+	svc := NewMenuService(repositories.NewMenuRepository(db), cache.NewMemory(), nil, nil)
+
+	item, err := svc.CreateMenuItem(1, "Empanada", "", "starters", 2500, 0)
+	if err != nil {
+		t.Fatalf("CreateMenuItem: %v", err)
+	}
+
+	// A re-pricing is recorded; an update that keeps the price is not.
+	if _, err := svc.UpdateMenuItem(item.ID, 42, "", "", "", 2800, 0, true); err != nil {
+		t.Fatalf("UpdateMenuItem: %v", err)
+	}
+	if _, err := svc.UpdateMenuItem(item.ID, 42, "Empanada de queso", "", "", 0, 0, true); err != nil {
+		t.Fatalf("UpdateMenuItem: %v", err)
+	}
+
+	history, err := svc.PriceHistory(item.ID)
+	if err != nil {
+		t.Fatalf("PriceHistory: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("expected one price change, got %d: %+v", len(history), history)
+	}
+	change := history[0]
+	if change.OldPrice != 2500 || change.NewPrice != 2800 || change.ChangedBy != 42 {
+		t.Fatalf("unexpected price change: %+v", change)
+	}
+}
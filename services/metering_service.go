@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+)
+
+// MeteringService pushes per-key API usage to the billing provider on a
+// schedule (Stripe metered billing). The usage table keeps the full
+// counts; only the delta since the last push crosses the wire, so a
+// missed run just rides along with the next one.
+type MeteringService struct {
+	apiKeyRepo APIKeyRepo
+	provider   payments.Provider
+}
+
+// NewMeteringService builds a MeteringService.
+func NewMeteringService(apiKeyRepo APIKeyRepo, provider payments.Provider) *MeteringService {
+	return &MeteringService{apiKeyRepo: apiKeyRepo, provider: provider}
+}
+
+// RunOnce pushes every unmetered delta and returns how many rows were
+// reported. A failed push leaves its row unmarked, so the delta is
+// retried on the next run.
+func (s *MeteringService) RunOnce(now time.Time) (int, error) {
+	rows, err := s.apiKeyRepo.UnreportedUsage()
+	if err != nil {
+		return 0, err
+	}
+
+	reported := 0
+	for _, row := range rows {
+		if err := s.provider.ReportUsage(row.BillingRef, row.Count-row.Reported, now); err != nil {
+			log.Printf("metering: reporting usage row %d: %v", row.UsageID, err)
+			continue
+		}
+		if err := s.apiKeyRepo.MarkReported(row.UsageID, row.Count); err != nil {
+			// The provider took the delta but we failed to record it, so
+			// the next run pushes it again. The loud log is the
+			// operator's cue to correct the double count upstream.
+			log.Printf("metering: marking usage row %d reported: %v", row.UsageID, err)
+			continue
+		}
+		reported++
+	}
+	return reported, nil
+}
+
+// Start pushes usage hourly until ctx is cancelled.
+func (s *MeteringService) Start(ctx context.Context) {
+	run := func(now time.Time) {
+		reported, err := s.RunOnce(now)
+		if err != nil {
+			log.Printf("metering: hourly push: %v", err)
+			return
+		}
+		if reported > 0 {
+			log.Printf("metering: pushed %d usage row(s) to billing", reported)
+		}
+	}
+	run(time.Now().UTC())
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run(time.Now().UTC())
+		}
+	}
+}
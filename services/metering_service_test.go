@@ -0,0 +1,98 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// recordingProvider captures ReportUsage calls so the test can see what
+// the push sent.
+type recordingProvider struct {
+	refs       []string
+	quantities []int64
+}
+
+func (p *recordingProvider) Refund(paymentRef string, amountCents int64, reason string) (string, error) {
+	return "re_test", nil
+}
+
+func (p *recordingProvider) Charge(methodRef string, amountCents int64, description string) (string, error) {
+	return "ch_test", nil
+}
+
+func (p *recordingProvider) ReportUsage(subscriptionItemRef string, quantity int64, at time.Time) error {
+	p.refs = append(p.refs, subscriptionItemRef)
+	p.quantities = append(p.quantities, quantity)
+	return nil
+}
+
+func TestMeteringPushesOnlyUnreportedDeltas(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connecting test database: %v", err)
+	}
+
+	apiKeyRepo := repositories.NewAPIKeyRepository(db)
+	restaurantRepo := repositories.NewRestaurantRepository(db)
+	keyService := NewAPIKeyService(apiKeyRepo, restaurantRepo)
+	provider := &recordingProvider{}
+	metering := NewMeteringService(apiKeyRepo, provider)
+
+	billed, err := keyService.IssueKey(1, "billed widget", models.PlanPro, "", "si_123", 0)
+	if err != nil {
+		t.Fatalf("issuing billed key: %v", err)
+	}
+	free, err := keyService.IssueKey(1, "free widget", "", "", "", 0)
+	if err != nil {
+		t.Fatalf("issuing free key: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := keyService.Consume(billed); err != nil {
+			t.Fatalf("consuming billed key: %v", err)
+		}
+	}
+	if _, err := keyService.Consume(free); err != nil {
+		t.Fatalf("consuming free key: %v", err)
+	}
+
+	// Only the key with a billing ref is metered, and the whole count
+	// goes out as one delta.
+	reported, err := metering.RunOnce(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("running metering: %v", err)
+	}
+	if reported != 1 {
+		t.Fatalf("reported %d rows, want 1", reported)
+	}
+	if len(provider.refs) != 1 || provider.refs[0] != "si_123" {
+		t.Fatalf("provider saw refs %v, want [si_123]", provider.refs)
+	}
+	if provider.quantities[0] != 2 {
+		t.Fatalf("pushed quantity %d, want 2", provider.quantities[0])
+	}
+
+	// Nothing new happened, so a second run pushes nothing.
+	reported, err = metering.RunOnce(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("re-running metering: %v", err)
+	}
+	if reported != 0 {
+		t.Fatalf("second run reported %d rows, want 0", reported)
+	}
+
+	// Another request produces a one-request delta, not the full count.
+	if _, err := keyService.Consume(billed); err != nil {
+		t.Fatalf("consuming billed key again: %v", err)
+	}
+	if _, err := metering.RunOnce(time.Now().UTC()); err != nil {
+		t.Fatalf("running metering after new usage: %v", err)
+	}
+	if got := provider.quantities[len(provider.quantities)-1]; got != 1 {
+		t.Fatalf("delta push sent %d, want 1", got)
+	}
+}
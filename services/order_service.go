@@ -0,0 +1,356 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/events"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// stockDepleter is the slice of StockService orders need: walking a
+// completed order's recipes and depleting ingredient stock.
+type stockDepleter interface {
+	DepleteForOrder(order *models.Order) error
+}
+
+// approvalRequester is the slice of ApprovalService orders need: filing
+// a refund that exceeds the threshold for a manager's decision.
+type approvalRequester interface {
+	Request(restaurantID uint, kind string, subjectID uint, summary string, amount float64, payload string) (*models.Approval, error)
+}
+
+// passUpdater is the slice of LoyaltyService orders need: refreshing
+// wallet passes once a completed order changes the loyalty balance.
+type passUpdater interface {
+	RefreshForOrder(order *models.Order) error
+}
+
+// printDispatcher is the slice of PrinterService orders need: queueing
+// kitchen tickets once an order is accepted.
+type printDispatcher interface {
+	DispatchOrder(order *models.Order) error
+}
+
+// refundApprovalThreshold is the refund amount above which a manager
+// must approve before the provider moves any money.
+const refundApprovalThreshold = 50000
+
+// OrderService implements order management.
+type OrderService struct {
+	orderRepo  OrderRepo
+	menuRepo   MenuRepo
+	refundRepo RefundRepo
+	tx         TxRunner
+	outbox     OutboxWriter
+	payments   payments.Provider
+	stock      stockDepleter
+	approvals  approvalRequester
+	passes     passUpdater
+	printers   printDispatcher
+	taxRatePct int
+}
+
+// NewOrderService builds an OrderService.
+func NewOrderService(orderRepo OrderRepo, menuRepo MenuRepo, refundRepo RefundRepo, tx TxRunner, outbox OutboxWriter, provider payments.Provider, stock stockDepleter, approvals approvalRequester, passes passUpdater, printers printDispatcher, taxRatePct int) *OrderService {
+	return &OrderService{orderRepo: orderRepo, menuRepo: menuRepo, refundRepo: refundRepo, tx: tx, outbox: outbox, payments: provider, stock: stock, approvals: approvals, passes: passes, printers: printers, taxRatePct: taxRatePct}
+}
+
+// OrderLine is a requested quantity of a menu item. Note is the diner's
+// free-text modifier for the kitchen.
+type OrderLine struct {
+	MenuItemID uint   `json:"menu_item_id"`
+	Quantity   int    `json:"quantity"`
+	Note       string `json:"note"`
+}
+
+// CreateOrder places an order against a restaurant, pricing each line
+// from the current menu. customerID links the order to a logged-in
+// customer account; zero means a guest order.
+func (s *OrderService) CreateOrder(restaurantID, customerID uint, customerName, customerEmail string, lines []OrderLine, tip float64) (*models.Order, error) {
+	if len(lines) == 0 {
+		return nil, errors.New("order has no items")
+	}
+	if tip < 0 {
+		return nil, Validation("invalid_tip", "tip cannot be negative")
+	}
+
+	order := &models.Order{
+		RestaurantID:  restaurantID,
+		CustomerID:    customerID,
+		CustomerName:  utils.SanitizeText(customerName),
+		CustomerEmail: customerEmail,
+		Status:        models.OrderPending,
+	}
+	for _, line := range lines {
+		item, err := s.menuRepo.GetByID(line.MenuItemID)
+		if err != nil {
+			return nil, fromRepo(err, "menu_item_not_found", "menu item not found")
+		}
+		if item.RestaurantID != restaurantID {
+			return nil, errors.New("menu item does not belong to restaurant")
+		}
+		order.Items = append(order.Items, models.OrderItem{
+			MenuItemID: item.ID,
+			Quantity:   line.Quantity,
+			Price:      item.Price,
+			Note:       utils.SanitizeText(line.Note),
+		})
+		order.Total += item.Price * float64(line.Quantity)
+	}
+	order.Tip = tip
+	// Menu prices include tax; record the included portion so revenue
+	// reports can break it out.
+	order.Tax = order.Total * float64(s.taxRatePct) / float64(100+s.taxRatePct)
+
+	// The order and its outbox event must commit together: if either
+	// fails, neither is visible.
+	err := s.tx.Transaction(func(tx *gorm.DB) error {
+		if err := s.orderRepo.CreateInTx(tx, order); err != nil {
+			return err
+		}
+		event, err := events.NewOutboxEvent(events.EventOrderPlaced, map[string]interface{}{
+			"order_id":      order.ID,
+			"restaurant_id": order.RestaurantID,
+			"total":         order.Total,
+		})
+		if err != nil {
+			return err
+		}
+		return s.outbox.CreateInTx(tx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// GetOrderByID returns the order with the given id.
+func (s *OrderService) GetOrderByID(id uint) (*models.Order, error) {
+	order, err := s.orderRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "order_not_found", "order not found")
+	}
+	return order, nil
+}
+
+// GetOrdersByRestaurantID returns all orders of a restaurant.
+func (s *OrderService) GetOrdersByRestaurantID(restaurantID uint) ([]models.Order, error) {
+	return s.orderRepo.GetByRestaurantID(restaurantID)
+}
+
+// TicketLine is one dish on a kitchen ticket.
+type TicketLine struct {
+	Quantity int    `json:"quantity"`
+	Name     string `json:"name"`
+	Note     string `json:"note,omitempty"`
+}
+
+// TicketCourse groups a ticket's lines by the menu category they came
+// from, which is how kitchens fire them.
+type TicketCourse struct {
+	Name  string       `json:"name"`
+	Lines []TicketLine `json:"lines"`
+}
+
+// KitchenTicket is an order laid out for the kitchen: courses in menu
+// order, quantities and modifiers, no prices.
+type KitchenTicket struct {
+	OrderID      uint           `json:"order_id"`
+	CustomerName string         `json:"customer_name"`
+	PlacedAt     time.Time      `json:"placed_at"`
+	Courses      []TicketCourse `json:"courses"`
+}
+
+// KitchenTicket builds the ticket for an order. Lines group by the menu
+// item's category; a dish deleted from the menu since ordering still
+// prints, under an empty course.
+func (s *OrderService) KitchenTicket(id uint) (*KitchenTicket, error) {
+	order, err := s.orderRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "order_not_found", "order not found")
+	}
+
+	byCourse := make(map[string][]TicketLine)
+	for _, line := range order.Items {
+		name := fmt.Sprintf("item #%d", line.MenuItemID)
+		course := ""
+		if item, err := s.menuRepo.GetByID(line.MenuItemID); err == nil {
+			name = item.Name
+			course = item.Category
+		}
+		byCourse[course] = append(byCourse[course], TicketLine{
+			Quantity: line.Quantity,
+			Name:     name,
+			Note:     line.Note,
+		})
+	}
+
+	ticket := &KitchenTicket{
+		OrderID:      order.ID,
+		CustomerName: order.CustomerName,
+		PlacedAt:     order.CreatedAt,
+	}
+	courses := make([]string, 0, len(byCourse))
+	for course := range byCourse {
+		courses = append(courses, course)
+	}
+	sort.Strings(courses)
+	for _, course := range courses {
+		ticket.Courses = append(ticket.Courses, TicketCourse{Name: course, Lines: byCourse[course]})
+	}
+	return ticket, nil
+}
+
+// ApprovedRefundKind names refund approvals in the approvals
+// subsystem.
+const ApprovedRefundKind = "order.refund"
+
+// refundApprovalPayload is what the refund executor needs once the
+// approval is granted.
+type refundApprovalPayload struct {
+	OrderID uint    `json:"order_id"`
+	Amount  float64 `json:"amount"`
+	Reason  string  `json:"reason"`
+}
+
+// ExecuteApprovedRefund performs a refund whose approval was granted.
+// It re-validates the amounts — the order may have been refunded
+// through other means while the approval sat in the inbox.
+func (s *OrderService) ExecuteApprovedRefund(payload string) (*models.Refund, error) {
+	var req refundApprovalPayload
+	if err := json.Unmarshal([]byte(payload), &req); err != nil {
+		return nil, err
+	}
+	return s.refundOrder(req.OrderID, req.Amount, req.Reason, true)
+}
+
+// RefundOrder returns money against an order through the payment
+// provider. amount is the amount to refund; zero means everything not
+// yet refunded. Cumulative refunds never exceed the order total.
+func (s *OrderService) RefundOrder(id uint, amount float64, reason string) (*models.Refund, error) {
+	return s.refundOrder(id, amount, reason, false)
+}
+
+// refundOrder validates and performs a refund. approved marks refunds
+// coming back from a granted approval, which skip the threshold gate.
+func (s *OrderService) refundOrder(id uint, amount float64, reason string, approved bool) (*models.Refund, error) {
+	if !models.ValidRefundReason(reason) {
+		return nil, Validation("invalid_refund_reason", "unknown refund reason")
+	}
+	order, err := s.orderRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "order_not_found", "order not found")
+	}
+
+	refunded, err := s.refundRepo.TotalByOrderID(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	remaining := order.Total - refunded
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount <= 0 {
+		return nil, Validation("invalid_refund_amount", "nothing left to refund")
+	}
+	if amount > remaining {
+		return nil, Validation("refund_exceeds_total", "refund exceeds the amount paid")
+	}
+
+	if !approved && s.approvals != nil && amount > refundApprovalThreshold {
+		payload, err := json.Marshal(refundApprovalPayload{OrderID: order.ID, Amount: amount, Reason: reason})
+		if err != nil {
+			return nil, err
+		}
+		summary := fmt.Sprintf("refund %.0f against order %d (%s)", amount, order.ID, reason)
+		if _, err := s.approvals.Request(order.RestaurantID, ApprovedRefundKind, order.ID, summary, amount, string(payload)); err != nil {
+			return nil, err
+		}
+		return nil, Conflict("approval_required", "refunds this large need a manager's approval")
+	}
+
+	providerRef, err := s.payments.Refund(order.PaymentRef, int64(math.Round(amount*100)), reason)
+	if err != nil {
+		return nil, err
+	}
+
+	refund := &models.Refund{
+		OrderID:     order.ID,
+		Amount:      amount,
+		Reason:      reason,
+		ProviderRef: providerRef,
+	}
+	// The provider has already moved the money at this point, so the
+	// record and its outbox event commit together or the request fails
+	// loudly for the operator to retry.
+	err = s.tx.Transaction(func(tx *gorm.DB) error {
+		if err := s.refundRepo.CreateInTx(tx, refund); err != nil {
+			return err
+		}
+		event, err := events.NewOutboxEvent(events.EventOrderRefunded, map[string]interface{}{
+			"order_id":      order.ID,
+			"restaurant_id": order.RestaurantID,
+			"amount":        amount,
+			"reason":        reason,
+		})
+		if err != nil {
+			return err
+		}
+		return s.outbox.CreateInTx(tx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// GetRefundsByOrderID returns the refunds issued against an order.
+func (s *OrderService) GetRefundsByOrderID(orderID uint) ([]models.Refund, error) {
+	return s.refundRepo.GetByOrderID(orderID)
+}
+
+// UpdateOrderStatus moves an order to a new status.
+func (s *OrderService) UpdateOrderStatus(id uint, status string) (*models.Order, error) {
+	order, err := s.orderRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "order_not_found", "order not found")
+	}
+	completing := status == models.OrderCompleted && order.Status != models.OrderCompleted
+	accepting := status == models.OrderAccepted && order.Status != models.OrderAccepted
+	order.Status = status
+	if err := s.orderRepo.Update(order); err != nil {
+		return nil, err
+	}
+	// Depletion is best-effort: a stock bookkeeping failure must not
+	// block the order from completing.
+	if completing && s.stock != nil {
+		if err := s.stock.DepleteForOrder(order); err != nil {
+			log.Printf("orders: depleting stock for order %d: %v", order.ID, err)
+		}
+	}
+	// Accepting an order fires its kitchen ticket; a printer hiccup
+	// must not block the acceptance either.
+	if accepting && s.printers != nil {
+		if err := s.printers.DispatchOrder(order); err != nil {
+			log.Printf("orders: dispatching print jobs for order %d: %v", order.ID, err)
+		}
+	}
+	// Likewise for wallet passes: a stale balance is better than a
+	// stuck order.
+	if completing && s.passes != nil {
+		if err := s.passes.RefreshForOrder(order); err != nil {
+			log.Printf("orders: refreshing loyalty passes for order %d: %v", order.ID, err)
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/cache"
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func newOrderServiceForTest(t *testing.T) (*OrderService, *models.Order) {
+	t.Helper()
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	restaurant := models.Restaurant{Name: "Testaurant", UserID: 1}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("creating restaurant: %v", err)
+	}
+
+	menuSvc := NewMenuService(repositories.NewMenuRepository(db), cache.NewMemory(), nil, nil)
+	item, err := menuSvc.CreateMenuItem(restaurant.ID, "Pastel de choclo", "", "mains", 7500, 0)
+	if err != nil {
+		t.Fatalf("CreateMenuItem: %v", err)
+	}
+
+	svc := NewOrderService(
+		repositories.NewOrderRepository(db),
+		repositories.NewMenuRepository(db),
+		repositories.NewRefundRepository(db),
+		repositories.NewTxManager(db),
+		repositories.NewOutboxRepository(db),
+		payments.LogProvider{},
+		nil,
+		nil,
+		nil,
+		nil,
+		19,
+	)
+
+	order, err := svc.CreateOrder(restaurant.ID, 0, "Pedro", "pedro@example.com", []OrderLine{{MenuItemID: item.ID, Quantity: 2}}, 0)
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	return svc, order
+}
+
+func TestCreateOrderWritesOutboxEventAtomically(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	restaurant := models.Restaurant{Name: "Testaurant", UserID: 1}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("creating restaurant: %v", err)
+	}
+
+	menuSvc := NewMenuService(repositories.NewMenuRepository(db), cache.NewMemory(), nil, nil)
+	item, err := menuSvc.CreateMenuItem(restaurant.ID, "Pastel de choclo", "", "mains", 7500, 0)
+	if err != nil {
+		t.Fatalf("CreateMenuItem: %v", err)
+	}
+
+	svc := NewOrderService(
+		repositories.NewOrderRepository(db),
+		repositories.NewMenuRepository(db),
+		repositories.NewRefundRepository(db),
+		repositories.NewTxManager(db),
+		repositories.NewOutboxRepository(db),
+		payments.LogProvider{},
+		nil,
+		nil,
+		nil,
+		nil,
+		19,
+	)
+
+	order, err := svc.CreateOrder(restaurant.ID, 0, "Pedro", "pedro@example.com", []OrderLine{{MenuItemID: item.ID, Quantity: 2}}, 0)
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	if order.Total != 15000 {
+		t.Fatalf("expected total 15000, got %v", order.Total)
+	}
+
+	var events []models.OutboxEvent
+	if err := db.Find(&events).Error; err != nil {
+		t.Fatalf("loading outbox: %v", err)
+	}
+	if len(events) != 1 || events[0].EventType != "order.created" {
+		t.Fatalf("expected one order.created outbox event, got %+v", events)
+	}
+	if events[0].PublishedAt != nil {
+		t.Fatalf("event should start unpublished")
+	}
+}
+
+func TestRefundOrderCapsCumulativeRefunds(t *testing.T) {
+	svc, order := newOrderServiceForTest(t)
+
+	// Partial refund of a third of the order.
+	refund, err := svc.RefundOrder(order.ID, 5000, models.RefundReasonIssue)
+	if err != nil {
+		t.Fatalf("RefundOrder: %v", err)
+	}
+	if refund.Amount != 5000 {
+		t.Fatalf("expected refund of 5000, got %v", refund.Amount)
+	}
+
+	// A zero amount refunds whatever is left.
+	refund, err = svc.RefundOrder(order.ID, 0, models.RefundReasonCustomer)
+	if err != nil {
+		t.Fatalf("RefundOrder: %v", err)
+	}
+	if refund.Amount != 10000 {
+		t.Fatalf("expected refund of 10000, got %v", refund.Amount)
+	}
+
+	// The order is now fully refunded.
+	if _, err := svc.RefundOrder(order.ID, 1, models.RefundReasonOther); err == nil {
+		t.Fatal("expected an error refunding past the order total")
+	}
+}
+
+func TestRefundOrderRejectsUnknownReason(t *testing.T) {
+	svc, order := newOrderServiceForTest(t)
+
+	_, err := svc.RefundOrder(order.ID, 1000, "because")
+	var svcErr *Error
+	if !errors.As(err, &svcErr) || svcErr.Kind != KindValidation {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+}
+
+func TestKitchenTicketGroupsByCourse(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	restaurant := models.Restaurant{Name: "Cocina", UserID: 1}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("creating restaurant: %v", err)
+	}
+
+	menuSvc := NewMenuService(repositories.NewMenuRepository(db), cache.NewMemory(), nil, nil)
+	empanada, err := menuSvc.CreateMenuItem(restaurant.ID, "Empanada", "", "starters", 3000, 0)
+	if err != nil {
+		t.Fatalf("CreateMenuItem: %v", err)
+	}
+	lomo, err := menuSvc.CreateMenuItem(restaurant.ID, "Lomo a lo pobre", "", "mains", 12500, 0)
+	if err != nil {
+		t.Fatalf("CreateMenuItem: %v", err)
+	}
+
+	svc := NewOrderService(
+		repositories.NewOrderRepository(db),
+		repositories.NewMenuRepository(db),
+		repositories.NewRefundRepository(db),
+		repositories.NewTxManager(db),
+		repositories.NewOutboxRepository(db),
+		payments.LogProvider{},
+		nil,
+		nil,
+		nil,
+		nil,
+		19,
+	)
+
+	order, err := svc.CreateOrder(restaurant.ID, 0, "Pedro", "pedro@example.com", []OrderLine{
+		{MenuItemID: lomo.ID, Quantity: 1, Note: "sin cebolla"},
+		{MenuItemID: empanada.ID, Quantity: 2},
+	}, 0)
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+
+	ticket, err := svc.KitchenTicket(order.ID)
+	if err != nil {
+		t.Fatalf("KitchenTicket: %v", err)
+	}
+	if ticket.OrderID != order.ID || ticket.CustomerName != "Pedro" {
+		t.Fatalf("expected the order header on the ticket, got %+v", ticket)
+	}
+	if len(ticket.Courses) != 2 || ticket.Courses[0].Name != "mains" || ticket.Courses[1].Name != "starters" {
+		t.Fatalf("expected two courses, got %+v", ticket.Courses)
+	}
+	mains := ticket.Courses[0].Lines
+	if len(mains) != 1 || mains[0].Quantity != 1 || mains[0].Note != "sin cebolla" {
+		t.Fatalf("expected the modifier on the lomo line, got %+v", mains)
+	}
+	starters := ticket.Courses[1].Lines
+	if len(starters) != 1 || starters[0].Quantity != 2 || starters[0].Name != "Empanada" {
+		t.Fatalf("expected two empanadas, got %+v", starters)
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// PrinterService manages kitchen printers and the print-job queue their
+// agents drain. Jobs are enqueued when an order is accepted and tracked
+// through delivery and the agent's ack.
+type PrinterService struct {
+	printerRepo    PrinterRepo
+	restaurantRepo RestaurantRepo
+}
+
+// NewPrinterService builds a PrinterService.
+func NewPrinterService(printerRepo PrinterRepo, restaurantRepo RestaurantRepo) *PrinterService {
+	return &PrinterService{printerRepo: printerRepo, restaurantRepo: restaurantRepo}
+}
+
+// RegisterPrinter creates a printer and mints its agent key.
+func (s *PrinterService) RegisterPrinter(restaurantID uint, name, format string) (*models.Printer, error) {
+	name = utils.SanitizeText(name)
+	if name == "" {
+		return nil, Validation("missing_name", "a printer name is required")
+	}
+	switch format {
+	case "":
+		format = models.PrinterFormatESCPOS
+	case models.PrinterFormatText, models.PrinterFormatESCPOS:
+	default:
+		return nil, Validation("invalid_format", "unknown printer format")
+	}
+	if _, err := s.restaurantRepo.GetByID(restaurantID); err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+
+	printer := &models.Printer{RestaurantID: restaurantID, Name: name, Format: format}
+	if err := s.printerRepo.Create(printer); err != nil {
+		return nil, err
+	}
+	return printer, nil
+}
+
+// Printers returns a restaurant's printers.
+func (s *PrinterService) Printers(restaurantID uint) ([]models.Printer, error) {
+	return s.printerRepo.GetByRestaurantID(restaurantID)
+}
+
+// Jobs returns a restaurant's print jobs, newest first.
+func (s *PrinterService) Jobs(restaurantID uint) ([]models.PrintJob, error) {
+	return s.printerRepo.GetJobsByRestaurantID(restaurantID)
+}
+
+// DispatchOrder queues the order's ticket on every printer of its
+// restaurant. A restaurant without printers dispatches nothing.
+func (s *PrinterService) DispatchOrder(order *models.Order) error {
+	printers, err := s.printerRepo.GetByRestaurantID(order.RestaurantID)
+	if err != nil {
+		return err
+	}
+	for _, printer := range printers {
+		job := &models.PrintJob{
+			RestaurantID: order.RestaurantID,
+			PrinterID:    printer.ID,
+			OrderID:      order.ID,
+			Status:       models.PrintJobPending,
+		}
+		if err := s.printerRepo.CreateJob(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PullJobs authenticates an agent by its printer key, stamps the poll,
+// and hands over the pending jobs marked delivered. A job the agent
+// never acks stays visible as delivered, which is the dashboard's cue
+// to reprint.
+func (s *PrinterService) PullJobs(key string) (*models.Printer, []models.PrintJob, error) {
+	printer, err := s.printerRepo.GetByKey(key)
+	if err != nil {
+		return nil, nil, fromRepo(err, "printer_not_found", "printer not found")
+	}
+	now := time.Now().UTC()
+	printer.LastSeenAt = &now
+	if err := s.printerRepo.Update(printer); err != nil {
+		return nil, nil, err
+	}
+
+	jobs, err := s.printerRepo.GetPendingJobs(printer.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range jobs {
+		jobs[i].Status = models.PrintJobDelivered
+		jobs[i].DeliveredAt = &now
+		if err := s.printerRepo.UpdateJob(&jobs[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return printer, jobs, nil
+}
+
+// AckJob records the agent's confirmation that a job printed.
+func (s *PrinterService) AckJob(key string, jobID uint) error {
+	printer, err := s.printerRepo.GetByKey(key)
+	if err != nil {
+		return fromRepo(err, "printer_not_found", "printer not found")
+	}
+	job, err := s.printerRepo.GetJobByID(jobID)
+	if err != nil {
+		return fromRepo(err, "job_not_found", "print job not found")
+	}
+	if job.PrinterID != printer.ID {
+		return Forbidden("forbidden", "forbidden")
+	}
+	now := time.Now().UTC()
+	job.Status = models.PrintJobAcked
+	job.AckedAt = &now
+	return s.printerRepo.UpdateJob(job)
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/cache"
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestPrintJobsFlowFromAcceptanceToAck(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	restaurant := models.Restaurant{Name: "Imprenta", UserID: 1}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	menuSvc := NewMenuService(repositories.NewMenuRepository(db), cache.NewMemory(), nil, nil)
+	item, err := menuSvc.CreateMenuItem(restaurant.ID, "Cazuela", "", "mains", 8000, 0)
+	if err != nil {
+		t.Fatalf("create menu item: %v", err)
+	}
+
+	printerSvc := NewPrinterService(repositories.NewPrinterRepository(db), repositories.NewRestaurantRepository(db))
+	orderSvc := NewOrderService(
+		repositories.NewOrderRepository(db),
+		repositories.NewMenuRepository(db),
+		repositories.NewRefundRepository(db),
+		repositories.NewTxManager(db),
+		repositories.NewOutboxRepository(db),
+		payments.LogProvider{},
+		nil,
+		nil,
+		nil,
+		printerSvc,
+		19,
+	)
+
+	if _, err := printerSvc.RegisterPrinter(restaurant.ID, "Cocina", "dotmatrix"); err == nil {
+		t.Fatal("expected an unknown format to be rejected")
+	}
+	printer, err := printerSvc.RegisterPrinter(restaurant.ID, "Cocina", "")
+	if err != nil {
+		t.Fatalf("register printer: %v", err)
+	}
+	if printer.Key == "" || printer.Format != models.PrinterFormatESCPOS {
+		t.Fatalf("expected a keyed escpos printer by default, got %+v", printer)
+	}
+
+	// Accepting the order queues a job; merely creating it does not.
+	order, err := orderSvc.CreateOrder(restaurant.ID, 0, "Pedro", "pedro@example.com", []OrderLine{{MenuItemID: item.ID, Quantity: 1}}, 0)
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	if jobs, _ := printerSvc.Jobs(restaurant.ID); len(jobs) != 0 {
+		t.Fatalf("expected no jobs before acceptance, got %d", len(jobs))
+	}
+	if _, err := orderSvc.UpdateOrderStatus(order.ID, models.OrderAccepted); err != nil {
+		t.Fatalf("accept order: %v", err)
+	}
+
+	// The agent's poll delivers the job and stamps the printer.
+	if _, _, err := printerSvc.PullJobs("wrong-key"); err == nil {
+		t.Fatal("expected an unknown printer key to be rejected")
+	}
+	seen, jobs, err := printerSvc.PullJobs(printer.Key)
+	if err != nil {
+		t.Fatalf("pull jobs: %v", err)
+	}
+	if seen.LastSeenAt == nil {
+		t.Fatal("expected the poll to stamp the printer")
+	}
+	if len(jobs) != 1 || jobs[0].OrderID != order.ID || jobs[0].Status != models.PrintJobDelivered {
+		t.Fatalf("expected one delivered job for the order, got %+v", jobs)
+	}
+
+	// A second poll is empty: delivered jobs are not re-sent.
+	if _, again, _ := printerSvc.PullJobs(printer.Key); len(again) != 0 {
+		t.Fatalf("expected no jobs on the second poll, got %d", len(again))
+	}
+
+	if err := printerSvc.AckJob(printer.Key, jobs[0].ID); err != nil {
+		t.Fatalf("ack job: %v", err)
+	}
+	all, err := printerSvc.Jobs(restaurant.ID)
+	if err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	if len(all) != 1 || all[0].Status != models.PrintJobAcked || all[0].AckedAt == nil {
+		t.Fatalf("expected the job acked, got %+v", all)
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"log"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/push"
+)
+
+// Push event categories, matched against device preferences.
+const (
+	PushEventOrder       = "order"
+	PushEventReservation = "reservation"
+)
+
+// DeviceTokenRepo is the persistence surface for device tokens.
+type DeviceTokenRepo interface {
+	Upsert(token *models.DeviceToken) error
+	DeleteByToken(userID uint, token string) error
+	GetByUserID(userID uint) ([]models.DeviceToken, error)
+}
+
+// PushService registers devices and fans notifications out to them.
+type PushService struct {
+	tokenRepo DeviceTokenRepo
+	sender    push.Sender
+}
+
+// NewPushService builds a PushService.
+func NewPushService(tokenRepo DeviceTokenRepo, sender push.Sender) *PushService {
+	return &PushService{tokenRepo: tokenRepo, sender: sender}
+}
+
+// RegisterDevice stores (or refreshes) a device token for the user.
+func (s *PushService) RegisterDevice(userID uint, token, platform string, notifyOrders, notifyReservations bool) (*models.DeviceToken, error) {
+	device := &models.DeviceToken{
+		UserID:             userID,
+		Token:              token,
+		Platform:           platform,
+		NotifyOrders:       notifyOrders,
+		NotifyReservations: notifyReservations,
+	}
+	if err := s.tokenRepo.Upsert(device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// UnregisterDevice removes a device token for the user.
+func (s *PushService) UnregisterDevice(userID uint, token string) error {
+	return s.tokenRepo.DeleteByToken(userID, token)
+}
+
+// NotifyUser pushes to every device of the user that opted into the
+// event category. Delivery failures are logged, never surfaced: a push
+// must not fail the request that triggered it.
+func (s *PushService) NotifyUser(userID uint, event, title, body string, data map[string]string) {
+	devices, err := s.tokenRepo.GetByUserID(userID)
+	if err != nil {
+		log.Printf("push: loading devices for user %d: %v", userID, err)
+		return
+	}
+	for _, device := range devices {
+		if event == PushEventOrder && !device.NotifyOrders {
+			continue
+		}
+		if event == PushEventReservation && !device.NotifyReservations {
+			continue
+		}
+		if err := s.sender.Send(device.Token, device.Platform, title, body, data); err != nil {
+			log.Printf("push: sending to device %d: %v", device.ID, err)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"log"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// RecipeService links menu items to priced ingredients and keeps each
+// dish's food cost in step with ingredient prices.
+type RecipeService struct {
+	inventoryRepo InventoryRepo
+	menuRepo      MenuRepo
+}
+
+// NewRecipeService builds a RecipeService.
+func NewRecipeService(inventoryRepo InventoryRepo, menuRepo MenuRepo) *RecipeService {
+	return &RecipeService{inventoryRepo: inventoryRepo, menuRepo: menuRepo}
+}
+
+// CreateIngredient registers an inventory item.
+func (s *RecipeService) CreateIngredient(restaurantID uint, name, unit string, costPerUnit float64) (*models.Ingredient, error) {
+	name = utils.SanitizeText(name)
+	if name == "" {
+		return nil, Validation("missing_name", "the ingredient name is required")
+	}
+	unit = utils.SanitizeText(unit)
+	if unit == "" {
+		return nil, Validation("missing_unit", "the ingredient unit is required")
+	}
+	if costPerUnit < 0 {
+		return nil, Validation("invalid_cost", "the cost per unit cannot be negative")
+	}
+
+	ingredient := &models.Ingredient{
+		RestaurantID: restaurantID,
+		Name:         name,
+		Unit:         unit,
+		CostPerUnit:  costPerUnit,
+	}
+	if err := s.inventoryRepo.CreateIngredient(ingredient); err != nil {
+		return nil, err
+	}
+	return ingredient, nil
+}
+
+// GetIngredientByID returns the ingredient with the given id.
+func (s *RecipeService) GetIngredientByID(id uint) (*models.Ingredient, error) {
+	ingredient, err := s.inventoryRepo.GetIngredientByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "ingredient_not_found", "ingredient not found")
+	}
+	return ingredient, nil
+}
+
+// Ingredients returns a restaurant's ingredients, alphabetically.
+func (s *RecipeService) Ingredients(restaurantID uint) ([]models.Ingredient, error) {
+	return s.inventoryRepo.GetIngredientsByRestaurantID(restaurantID)
+}
+
+// UpdateIngredient saves new details for an ingredient. A changed cost
+// ripples into the food cost of every dish whose recipe uses it.
+func (s *RecipeService) UpdateIngredient(id uint, name, unit string, costPerUnit float64) (*models.Ingredient, error) {
+	ingredient, err := s.inventoryRepo.GetIngredientByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "ingredient_not_found", "ingredient not found")
+	}
+	name = utils.SanitizeText(name)
+	if name == "" {
+		return nil, Validation("missing_name", "the ingredient name is required")
+	}
+	unit = utils.SanitizeText(unit)
+	if unit == "" {
+		return nil, Validation("missing_unit", "the ingredient unit is required")
+	}
+	if costPerUnit < 0 {
+		return nil, Validation("invalid_cost", "the cost per unit cannot be negative")
+	}
+
+	costChanged := ingredient.CostPerUnit != costPerUnit
+	ingredient.Name = name
+	ingredient.Unit = unit
+	ingredient.CostPerUnit = costPerUnit
+	if err := s.inventoryRepo.UpdateIngredient(ingredient); err != nil {
+		return nil, err
+	}
+
+	if costChanged {
+		menuItemIDs, err := s.inventoryRepo.MenuItemIDsUsingIngredient(ingredient.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, menuItemID := range menuItemIDs {
+			// Recosting is best-effort per dish; one broken recipe must
+			// not block the price change itself.
+			if err := s.recost(menuItemID); err != nil {
+				log.Printf("recipes: recosting menu item %d: %v", menuItemID, err)
+			}
+		}
+	}
+	return ingredient, nil
+}
+
+// RecipeItemInput is one recipe line as submitted by the owner.
+type RecipeItemInput struct {
+	IngredientID uint    `json:"ingredient_id"`
+	Quantity     float64 `json:"quantity"`
+}
+
+// SetRecipe replaces a menu item's recipe and recalculates its food
+// cost. Every ingredient must belong to the dish's restaurant.
+func (s *RecipeService) SetRecipe(menuItemID uint, inputs []RecipeItemInput) (*FoodCost, error) {
+	menuItem, err := s.menuRepo.GetByID(menuItemID)
+	if err != nil {
+		return nil, fromRepo(err, "menu_item_not_found", "menu item not found")
+	}
+
+	items := make([]models.RecipeItem, 0, len(inputs))
+	for _, input := range inputs {
+		if input.Quantity <= 0 {
+			return nil, Validation("invalid_quantity", "recipe quantities must be positive")
+		}
+		ingredient, err := s.inventoryRepo.GetIngredientByID(input.IngredientID)
+		if err != nil {
+			return nil, fromRepo(err, "ingredient_not_found", "ingredient not found")
+		}
+		if ingredient.RestaurantID != menuItem.RestaurantID {
+			return nil, Validation("foreign_ingredient", "an ingredient belongs to another restaurant")
+		}
+		items = append(items, models.RecipeItem{IngredientID: input.IngredientID, Quantity: input.Quantity})
+	}
+
+	if err := s.inventoryRepo.ReplaceRecipe(menuItemID, items); err != nil {
+		return nil, err
+	}
+	if err := s.recost(menuItemID); err != nil {
+		return nil, err
+	}
+	return s.Recipe(menuItemID)
+}
+
+// FoodCost is a dish's recipe with the derived cost and margin.
+type FoodCost struct {
+	MenuItemID uint                `json:"menu_item_id"`
+	Name       string              `json:"name"`
+	Price      float64             `json:"price"`
+	FoodCost   float64             `json:"food_cost"`
+	MarginPct  float64             `json:"margin_pct"`
+	Items      []models.RecipeItem `json:"items,omitempty"`
+}
+
+// Recipe returns a menu item's recipe with its food cost and margin.
+func (s *RecipeService) Recipe(menuItemID uint) (*FoodCost, error) {
+	menuItem, err := s.menuRepo.GetByID(menuItemID)
+	if err != nil {
+		return nil, fromRepo(err, "menu_item_not_found", "menu item not found")
+	}
+	items, err := s.inventoryRepo.GetRecipe(menuItemID)
+	if err != nil {
+		return nil, err
+	}
+	cost := recipeCost(items)
+	return &FoodCost{
+		MenuItemID: menuItem.ID,
+		Name:       menuItem.Name,
+		Price:      menuItem.Price,
+		FoodCost:   cost,
+		MarginPct:  marginPct(menuItem.Price, cost),
+		Items:      items,
+	}, nil
+}
+
+// FoodCosts reports cost and margin for every dish on a restaurant's
+// menu that has a recipe.
+func (s *RecipeService) FoodCosts(restaurantID uint) ([]FoodCost, error) {
+	menuItems, err := s.menuRepo.GetByRestaurantID(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	costs := make([]FoodCost, 0, len(menuItems))
+	for i := range menuItems {
+		menuItem := &menuItems[i]
+		items, err := s.inventoryRepo.GetRecipe(menuItem.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			continue
+		}
+		cost := recipeCost(items)
+		costs = append(costs, FoodCost{
+			MenuItemID: menuItem.ID,
+			Name:       menuItem.Name,
+			Price:      menuItem.Price,
+			FoodCost:   cost,
+			MarginPct:  marginPct(menuItem.Price, cost),
+		})
+	}
+	return costs, nil
+}
+
+// recost recalculates a dish's food cost from its recipe and stores it
+// on the menu item, where menu engineering already reads it.
+func (s *RecipeService) recost(menuItemID uint) error {
+	menuItem, err := s.menuRepo.GetByID(menuItemID)
+	if err != nil {
+		return err
+	}
+	items, err := s.inventoryRepo.GetRecipe(menuItemID)
+	if err != nil {
+		return err
+	}
+	menuItem.Cost = recipeCost(items)
+	return s.menuRepo.Update(menuItem)
+}
+
+func recipeCost(items []models.RecipeItem) float64 {
+	total := 0.0
+	for _, item := range items {
+		total += item.Quantity * item.Ingredient.CostPerUnit
+	}
+	return total
+}
+
+func marginPct(price, cost float64) float64 {
+	if price <= 0 {
+		return 0
+	}
+	return (price - cost) / price * 100
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func newRecipeServiceForTest(t *testing.T) (*RecipeService, *models.Restaurant, *gorm.DB) {
+	t.Helper()
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	restaurant := &models.Restaurant{Name: "Costed", UserID: 1}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewRecipeService(repositories.NewInventoryRepository(db), repositories.NewMenuRepository(db))
+	return svc, restaurant, db
+}
+
+func TestRecipeDerivesFoodCostAndMargin(t *testing.T) {
+	svc, restaurant, db := newRecipeServiceForTest(t)
+
+	dish := &models.MenuItem{RestaurantID: restaurant.ID, Name: "Pasta al pesto", Price: 12000}
+	if err := db.Create(dish).Error; err != nil {
+		t.Fatalf("create menu item: %v", err)
+	}
+
+	pasta, err := svc.CreateIngredient(restaurant.ID, "Pasta", "kg", 2000)
+	if err != nil {
+		t.Fatalf("create ingredient: %v", err)
+	}
+	pesto, err := svc.CreateIngredient(restaurant.ID, "Pesto", "kg", 8000)
+	if err != nil {
+		t.Fatalf("create ingredient: %v", err)
+	}
+
+	recipe, err := svc.SetRecipe(dish.ID, []RecipeItemInput{
+		{IngredientID: pasta.ID, Quantity: 0.2},
+		{IngredientID: pesto.ID, Quantity: 0.05},
+	})
+	if err != nil {
+		t.Fatalf("set recipe: %v", err)
+	}
+	// 0.2kg * 2000 + 0.05kg * 8000 = 800.
+	if recipe.FoodCost != 800 {
+		t.Fatalf("expected a food cost of 800, got %v", recipe.FoodCost)
+	}
+	if recipe.MarginPct <= 93 || recipe.MarginPct >= 94 {
+		t.Fatalf("expected a margin around 93.3%%, got %v", recipe.MarginPct)
+	}
+
+	// The cost lands on the menu item, where menu engineering reads it.
+	var stored models.MenuItem
+	if err := db.First(&stored, dish.ID).Error; err != nil {
+		t.Fatalf("reload menu item: %v", err)
+	}
+	if stored.Cost != 800 {
+		t.Fatalf("expected the menu item cost to be 800, got %v", stored.Cost)
+	}
+}
+
+func TestIngredientPriceChangeRecostsDishes(t *testing.T) {
+	svc, restaurant, db := newRecipeServiceForTest(t)
+
+	dish := &models.MenuItem{RestaurantID: restaurant.ID, Name: "Tortilla", Price: 6000}
+	if err := db.Create(dish).Error; err != nil {
+		t.Fatalf("create menu item: %v", err)
+	}
+	eggs, err := svc.CreateIngredient(restaurant.ID, "Eggs", "unit", 200)
+	if err != nil {
+		t.Fatalf("create ingredient: %v", err)
+	}
+	if _, err := svc.SetRecipe(dish.ID, []RecipeItemInput{{IngredientID: eggs.ID, Quantity: 4}}); err != nil {
+		t.Fatalf("set recipe: %v", err)
+	}
+
+	if _, err := svc.UpdateIngredient(eggs.ID, "Eggs", "unit", 300); err != nil {
+		t.Fatalf("update ingredient: %v", err)
+	}
+
+	var stored models.MenuItem
+	if err := db.First(&stored, dish.ID).Error; err != nil {
+		t.Fatalf("reload menu item: %v", err)
+	}
+	if stored.Cost != 1200 {
+		t.Fatalf("expected the dish recosted to 1200, got %v", stored.Cost)
+	}
+
+	costs, err := svc.FoodCosts(restaurant.ID)
+	if err != nil {
+		t.Fatalf("food costs: %v", err)
+	}
+	if len(costs) != 1 || costs[0].FoodCost != 1200 {
+		t.Fatalf("expected one costed dish at 1200, got %+v", costs)
+	}
+}
+
+func TestSetRecipeRejectsForeignIngredients(t *testing.T) {
+	svc, restaurant, db := newRecipeServiceForTest(t)
+
+	dish := &models.MenuItem{RestaurantID: restaurant.ID, Name: "Salad", Price: 5000}
+	if err := db.Create(dish).Error; err != nil {
+		t.Fatalf("create menu item: %v", err)
+	}
+	foreign := &models.Ingredient{RestaurantID: restaurant.ID + 1, Name: "Lettuce", Unit: "kg", CostPerUnit: 1500}
+	if err := db.Create(foreign).Error; err != nil {
+		t.Fatalf("create ingredient: %v", err)
+	}
+
+	if _, err := svc.SetRecipe(dish.ID, []RecipeItemInput{{IngredientID: foreign.ID, Quantity: 0.1}}); err == nil {
+		t.Fatal("expected an ingredient from another restaurant to be rejected")
+	}
+	if _, err := svc.SetRecipe(dish.ID, []RecipeItemInput{{IngredientID: foreign.ID, Quantity: 0}}); err == nil {
+		t.Fatal("expected a zero quantity to be rejected")
+	}
+}
@@ -0,0 +1,460 @@
+package services
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// The services depend on these repository interfaces rather than the
+// concrete gorm-backed types, so they can be unit-tested against fakes.
+// The types in the repositories package satisfy them.
+
+// UserRepo is the persistence surface for users.
+type UserRepo interface {
+	Create(user *models.User) error
+	CreateInTx(tx *gorm.DB, user *models.User) error
+	GetByID(id uint) (*models.User, error)
+	GetByPublicID(publicID string) (*models.User, error)
+	GetByEmail(email string) (*models.User, error)
+	Update(user *models.User) error
+	Delete(id uint) error
+	DeleteInTx(tx *gorm.DB, id uint) error
+	CountAdmins() (int64, error)
+	Search(q string, limit int) ([]models.User, error)
+}
+
+// RestaurantRepo is the persistence surface for restaurants.
+type RestaurantRepo interface {
+	Create(restaurant *models.Restaurant) error
+	CreateInTx(tx *gorm.DB, restaurant *models.Restaurant) error
+	GetByID(id uint) (*models.Restaurant, error)
+	GetByPublicID(publicID string) (*models.Restaurant, error)
+	GetBySlug(slug string) (*models.Restaurant, error)
+	GetByUserID(userID uint) ([]models.Restaurant, error)
+	List() ([]models.Restaurant, error)
+	ListPaged(f repositories.RestaurantFilter) ([]models.Restaurant, int64, error)
+	Update(restaurant *models.Restaurant) error
+	Delete(id uint) error
+	DeleteByUserIDInTx(tx *gorm.DB, userID uint) error
+	CountByUserID(userID uint) (int64, error)
+	Search(q string, limit int) ([]models.Restaurant, error)
+}
+
+// MenuRepo is the persistence surface for menu items.
+type MenuRepo interface {
+	Create(item *models.MenuItem) error
+	GetByID(id uint) (*models.MenuItem, error)
+	GetByRestaurantID(restaurantID uint) ([]models.MenuItem, error)
+	Update(item *models.MenuItem) error
+	Delete(id uint) error
+	CreatePriceChange(change *models.PriceChange) error
+	GetPriceHistory(menuItemID uint) ([]models.PriceChange, error)
+	Search(q string, limit int) ([]models.MenuItem, error)
+	GetUpdatedSince(restaurantID uint, since time.Time) ([]models.MenuItem, error)
+}
+
+// ReservationRepo is the persistence surface for reservations.
+type ReservationRepo interface {
+	Create(reservation *models.Reservation) error
+	GetByID(id uint) (*models.Reservation, error)
+	GetByRestaurantID(restaurantID uint) ([]models.Reservation, error)
+	GetByRestaurantAndRange(restaurantID uint, from, to time.Time) ([]models.Reservation, error)
+	GetByCustomerID(customerID uint) ([]models.Reservation, error)
+	GetBySourceRef(restaurantID uint, source, ref string) (*models.Reservation, error)
+	GetDirectCreatedSince(restaurantID uint, since time.Time) ([]models.Reservation, error)
+	GetUpdatedSince(restaurantID uint, since time.Time) ([]models.Reservation, error)
+	Update(reservation *models.Reservation) error
+	Delete(id uint) error
+	CountPendingByOwner(userID uint) (int64, error)
+	CountNoShowsByEmail(customerEmail string) (int64, error)
+}
+
+// OrderRepo is the persistence surface for orders.
+type OrderRepo interface {
+	Create(order *models.Order) error
+	GetByID(id uint) (*models.Order, error)
+	GetByPaymentRef(paymentRef string) (*models.Order, error)
+	GetByRestaurantID(restaurantID uint) ([]models.Order, error)
+	GetByCustomerID(customerID uint) ([]models.Order, error)
+	CompletedTotalByCustomer(restaurantID, customerID uint) (float64, error)
+	GetUpdatedSince(restaurantID uint, since time.Time) ([]models.Order, error)
+	Update(order *models.Order) error
+	CreateInTx(tx *gorm.DB, order *models.Order) error
+}
+
+// RefundRepo is the persistence surface for refunds.
+type RefundRepo interface {
+	Create(refund *models.Refund) error
+	CreateInTx(tx *gorm.DB, refund *models.Refund) error
+	GetByOrderID(orderID uint) ([]models.Refund, error)
+	TotalByOrderID(orderID uint) (float64, error)
+}
+
+// AnalyticsRepo is the aggregate-query surface behind the dashboards.
+type AnalyticsRepo interface {
+	RevenueByPeriod(restaurantID uint, granularity string, from, to time.Time) ([]repositories.RevenueRow, error)
+	RefundsByPeriod(restaurantID uint, granularity string, from, to time.Time) (map[string]float64, error)
+	OrderCountsByHour(restaurantID uint, from, to time.Time) ([]repositories.HourCount, error)
+	ReservationCountsByHour(restaurantID uint, from, to time.Time) ([]repositories.HourCount, error)
+	ItemSales(restaurantID uint, from, to time.Time) ([]repositories.ItemSalesRow, error)
+	TableTurnTimes(restaurantID uint, from, to time.Time) ([]repositories.TableTurnRow, error)
+}
+
+// AccountMappingRepo is the persistence surface for chart-of-account
+// mappings.
+type AccountMappingRepo interface {
+	Get(restaurantID uint) (*models.AccountMapping, error)
+	Save(mapping *models.AccountMapping) error
+}
+
+// DisputeRepo is the persistence surface for payment disputes.
+type DisputeRepo interface {
+	Create(dispute *models.Dispute) error
+	Update(dispute *models.Dispute) error
+	GetByID(id uint) (*models.Dispute, error)
+	GetByProviderRef(providerRef string) (*models.Dispute, error)
+	GetByRestaurantID(restaurantID uint) ([]models.Dispute, error)
+}
+
+// CustomerRepo is the persistence surface for customer profile data.
+type CustomerRepo interface {
+	ListAddresses(userID uint) ([]models.CustomerAddress, error)
+	CreateAddress(address *models.CustomerAddress) error
+	DeleteAddress(id, userID uint) error
+	ClearDefaultAddress(userID uint) error
+	ListPaymentMethods(userID uint) ([]models.CustomerPaymentMethod, error)
+	CreatePaymentMethod(method *models.CustomerPaymentMethod) error
+	DeletePaymentMethod(id, userID uint) error
+	ClearDefaultPaymentMethod(userID uint) error
+}
+
+// StaffRepo is the persistence surface for employees and shifts.
+type StaffRepo interface {
+	CreateEmployee(employee *models.Employee) error
+	GetEmployeeByID(id uint) (*models.Employee, error)
+	UpdateEmployee(employee *models.Employee) error
+	GetEmployeesByRestaurantID(restaurantID uint) ([]models.Employee, error)
+	CreateShift(shift *models.Shift) error
+	GetShiftByID(id uint) (*models.Shift, error)
+	GetShiftsByRange(restaurantID uint, from, to time.Time) ([]models.Shift, error)
+}
+
+// ExperimentRepo is the persistence surface for menu experiments and
+// visitor exposures.
+type ExperimentRepo interface {
+	Create(experiment *models.Experiment) error
+	Update(experiment *models.Experiment) error
+	GetByID(id uint) (*models.Experiment, error)
+	GetByRestaurantID(restaurantID uint) ([]models.Experiment, error)
+	GetRunningByRestaurantID(restaurantID uint) (*models.Experiment, error)
+	CreateExposure(exposure *models.ExperimentExposure) error
+	GetExposure(experimentID uint, visitorID string) (*models.ExperimentExposure, error)
+	UpdateExposure(exposure *models.ExperimentExposure) error
+	ExposureStats(experimentID uint) ([]repositories.ExposureStatsRow, error)
+}
+
+// SegmentRepo is the persistence surface for marketing segments.
+type SegmentRepo interface {
+	Create(segment *models.Segment) error
+	GetByID(id uint) (*models.Segment, error)
+	GetByRestaurantID(restaurantID uint) ([]models.Segment, error)
+	Delete(id uint) error
+	CustomerStats(restaurantID uint, since time.Time) ([]repositories.CustomerStatsRow, error)
+}
+
+// MarketingRepo is the persistence surface for marketing platform
+// accounts.
+type MarketingRepo interface {
+	GetAccount(restaurantID uint) (*models.MarketingAccount, error)
+	SaveAccount(account *models.MarketingAccount) error
+}
+
+// ConsentRepo is the persistence surface for marketing consents.
+type ConsentRepo interface {
+	Get(email, channel string) (*models.Consent, error)
+	GetByEmail(email string) ([]models.Consent, error)
+	Save(consent *models.Consent) error
+}
+
+// BirthdayRepo is the persistence surface for birthday templates and
+// the daily run's lookups.
+type BirthdayRepo interface {
+	GetTemplate(restaurantID uint) (*models.BirthdayTemplate, error)
+	SaveTemplate(template *models.BirthdayTemplate) error
+	UsersWithBirthdayOn(month, day int) ([]models.User, error)
+	CustomerRestaurantIDs(email string) ([]uint, error)
+}
+
+// CouponRepo is the persistence surface for coupons.
+type CouponRepo interface {
+	Create(coupon *models.Coupon) error
+	GetByEmail(email string) ([]models.Coupon, error)
+	CountBySource(restaurantID uint, email, source string, since time.Time) (int64, error)
+}
+
+// EventRepo is the persistence surface for events and their tickets.
+type EventRepo interface {
+	Create(event *models.Event) error
+	GetByID(id uint) (*models.Event, error)
+	GetByRestaurantID(restaurantID uint) ([]models.Event, error)
+	CreateTicket(ticket *models.Ticket) error
+	UpdateTicket(ticket *models.Ticket) error
+	GetTicketByCode(code string) (*models.Ticket, error)
+	GetTicketsByEventID(eventID uint) ([]models.Ticket, error)
+	GetTicketsByCustomerID(customerID uint) ([]models.Ticket, error)
+	CountTicketsByTierID(tierID uint) (int64, error)
+}
+
+// CateringRepo is the persistence surface for catering requests.
+type CateringRepo interface {
+	Create(request *models.CateringRequest) error
+	GetByID(id uint) (*models.CateringRequest, error)
+	GetByRestaurantID(restaurantID uint) ([]models.CateringRequest, error)
+	Update(request *models.CateringRequest) error
+	ReplaceLines(requestID uint, lines []models.CateringQuoteLine) error
+}
+
+// RoomRepo is the persistence surface for private dining rooms and
+// their bookings.
+type RoomRepo interface {
+	CreateRoom(room *models.PrivateRoom) error
+	GetRoomByID(id uint) (*models.PrivateRoom, error)
+	GetRoomsByRestaurantID(restaurantID uint) ([]models.PrivateRoom, error)
+	UpdateRoom(room *models.PrivateRoom) error
+	DeleteRoom(id uint) error
+	CreateBooking(booking *models.RoomBooking) error
+	GetBookingByID(id uint) (*models.RoomBooking, error)
+	GetBookingsByRoomAndRange(roomID uint, from, to time.Time) ([]models.RoomBooking, error)
+	GetBookingsByRestaurantID(restaurantID uint) ([]models.RoomBooking, error)
+	UpdateBooking(booking *models.RoomBooking) error
+}
+
+// RoyaltyRepo is the persistence surface for royalty rules and the net
+// sales figures royalty reports read.
+type RoyaltyRepo interface {
+	GetRule(restaurantID uint) (*models.RoyaltyRule, error)
+	SaveRule(rule *models.RoyaltyRule) error
+	NetSales(restaurantID uint, from, to time.Time) (float64, error)
+}
+
+// HealthRepo is the persistence surface for health inspection records.
+type HealthRepo interface {
+	Create(inspection *models.HealthInspection) error
+	GetByID(id uint) (*models.HealthInspection, error)
+	GetByRestaurantID(restaurantID uint) ([]models.HealthInspection, error)
+	GetLatestByRestaurantID(restaurantID uint) (*models.HealthInspection, error)
+}
+
+// LicenseRepo is the persistence surface for license and permit
+// documents.
+type LicenseRepo interface {
+	Create(document *models.LicenseDocument) error
+	GetByID(id uint) (*models.LicenseDocument, error)
+	GetByRestaurantID(restaurantID uint) ([]models.LicenseDocument, error)
+	Update(document *models.LicenseDocument) error
+	ExpiringBefore(cutoff time.Time) ([]models.LicenseDocument, error)
+}
+
+// ComplianceRepo is the persistence surface for compliance items and
+// their completion records.
+type ComplianceRepo interface {
+	Create(item *models.ComplianceItem) error
+	GetByID(id uint) (*models.ComplianceItem, error)
+	GetByRestaurantID(restaurantID uint) ([]models.ComplianceItem, error)
+	Update(item *models.ComplianceItem) error
+	CreateCompletion(completion *models.ComplianceCompletion) error
+	OverdueUnescalated(now time.Time) ([]models.ComplianceItem, error)
+}
+
+// InvoiceRepo is the persistence surface for supplier invoices.
+type InvoiceRepo interface {
+	Create(invoice *models.SupplierInvoice) error
+	GetByID(id uint) (*models.SupplierInvoice, error)
+	GetByRestaurantID(restaurantID uint) ([]models.SupplierInvoice, error)
+	Update(invoice *models.SupplierInvoice) error
+	ReplaceLines(invoiceID uint, lines []models.SupplierInvoiceLine) error
+}
+
+// InventoryRepo is the persistence surface for ingredients and recipes.
+type InventoryRepo interface {
+	CreateIngredient(ingredient *models.Ingredient) error
+	GetIngredientByID(id uint) (*models.Ingredient, error)
+	GetIngredientsByRestaurantID(restaurantID uint) ([]models.Ingredient, error)
+	UpdateIngredient(ingredient *models.Ingredient) error
+	GetRecipe(menuItemID uint) ([]models.RecipeItem, error)
+	ReplaceRecipe(menuItemID uint, items []models.RecipeItem) error
+	MenuItemIDsUsingIngredient(ingredientID uint) ([]uint, error)
+	AdjustStock(movement *models.StockMovement) error
+	GetMovementsByIngredientID(ingredientID uint) ([]models.StockMovement, error)
+}
+
+// WasteRepo is the persistence surface for waste log entries.
+type WasteRepo interface {
+	Create(entry *models.WasteEntry) error
+	GetByRestaurantID(restaurantID uint, from, to time.Time) ([]models.WasteEntry, error)
+	CostByDay(restaurantID uint, from, to time.Time) ([]repositories.WasteDay, error)
+}
+
+// TemperatureRepo is the persistence surface for monitored units and
+// their readings.
+type TemperatureRepo interface {
+	CreateUnit(unit *models.TemperatureUnit) error
+	GetUnitByID(id uint) (*models.TemperatureUnit, error)
+	GetUnitBySensorKey(key string) (*models.TemperatureUnit, error)
+	GetUnitsByRestaurantID(restaurantID uint) ([]models.TemperatureUnit, error)
+	UpdateUnit(unit *models.TemperatureUnit) error
+	CreateReading(reading *models.TemperatureReading) error
+	GetReadingsByUnitID(unitID uint, from, to time.Time) ([]models.TemperatureReading, error)
+}
+
+// TrainingRepo is the persistence surface for employee training
+// records.
+type TrainingRepo interface {
+	Create(record *models.TrainingRecord) error
+	GetByEmployeeID(employeeID uint) ([]models.TrainingRecord, error)
+	Update(record *models.TrainingRecord) error
+	ExpiringBefore(cutoff time.Time) ([]models.TrainingRecord, error)
+}
+
+// SwapRepo is the persistence surface for shift swap requests and the
+// schedule change history.
+type SwapRepo interface {
+	Create(swap *models.ShiftSwap) error
+	GetByID(id uint) (*models.ShiftSwap, error)
+	GetByRestaurantID(restaurantID uint) ([]models.ShiftSwap, error)
+	Update(swap *models.ShiftSwap) error
+	Apply(swap *models.ShiftSwap, shift *models.Shift, dropShift bool, change *models.ShiftChange) error
+	GetChangesByRestaurantID(restaurantID uint) ([]models.ShiftChange, error)
+}
+
+// TimeclockRepo is the persistence surface for time punches.
+type TimeclockRepo interface {
+	Create(punch *models.TimePunch) error
+	Update(punch *models.TimePunch) error
+	GetOpenPunch(employeeID uint) (*models.TimePunch, error)
+	GetByRestaurantID(restaurantID uint, from, to time.Time) ([]models.TimePunch, error)
+}
+
+// ApprovalRepo is the persistence surface for approval requests.
+type ApprovalRepo interface {
+	Create(approval *models.Approval) error
+	GetByID(id uint) (*models.Approval, error)
+	GetPendingByRestaurantID(restaurantID uint) ([]models.Approval, error)
+	FindPending(kind string, subjectID uint) (*models.Approval, error)
+	Update(approval *models.Approval) error
+}
+
+// BrandRepo is the persistence surface for brands.
+type BrandRepo interface {
+	Create(brand *models.Brand) error
+	GetByID(id uint) (*models.Brand, error)
+	GetByUserID(userID uint) ([]models.Brand, error)
+	Update(brand *models.Brand) error
+}
+
+// APIKeyRepo is the persistence surface for publishable API keys.
+type APIKeyRepo interface {
+	Create(key *models.APIKey) error
+	GetByID(id uint) (*models.APIKey, error)
+	GetByKey(value string) (*models.APIKey, error)
+	GetByRestaurantID(restaurantID uint) ([]models.APIKey, error)
+	Update(key *models.APIKey) error
+	IncrementUsage(keyID uint, period string) (int64, error)
+	GetUsage(keyID uint, period string) (int64, error)
+	UsageByRestaurantID(restaurantID uint) ([]repositories.KeyUsageRow, error)
+	UnreportedUsage() ([]repositories.MeteredUsageRow, error)
+	MarkReported(usageID uint, reported int64) error
+}
+
+// BookingAccountRepo is the persistence surface for external booking
+// platform accounts.
+type BookingAccountRepo interface {
+	Create(account *models.BookingAccount) error
+	GetByRestaurantAndPlatform(restaurantID uint, platform string) (*models.BookingAccount, error)
+	GetByWebhookKey(key string) (*models.BookingAccount, error)
+	GetByRestaurantID(restaurantID uint) ([]models.BookingAccount, error)
+	List() ([]models.BookingAccount, error)
+	Update(account *models.BookingAccount) error
+}
+
+// ReviewRepo is the persistence surface for reviews.
+type ReviewRepo interface {
+	Create(review *models.Review) error
+	GetBySourceRef(restaurantID uint, source, ref string) (*models.Review, error)
+	GetByRestaurantID(restaurantID uint) ([]models.Review, error)
+	GetByRestaurantAndRange(restaurantID uint, from, to time.Time) ([]models.Review, error)
+	Update(review *models.Review) error
+	RatingsBySource(restaurantID uint) ([]repositories.ReviewSourceStats, error)
+}
+
+// ReviewAccountRepo is the persistence surface for external review
+// platform accounts.
+type ReviewAccountRepo interface {
+	Create(account *models.ReviewAccount) error
+	GetByRestaurantAndPlatform(restaurantID uint, platform string) (*models.ReviewAccount, error)
+	GetByRestaurantID(restaurantID uint) ([]models.ReviewAccount, error)
+	List() ([]models.ReviewAccount, error)
+	Update(account *models.ReviewAccount) error
+}
+
+// FAQRepo is the persistence surface for FAQ entries.
+type FAQRepo interface {
+	Create(entry *models.FAQEntry) error
+	GetByID(id uint) (*models.FAQEntry, error)
+	GetByRestaurantID(restaurantID uint) ([]models.FAQEntry, error)
+	Delete(id uint) error
+}
+
+// LoyaltyPassRepo is the persistence surface for loyalty passes.
+type LoyaltyPassRepo interface {
+	Create(pass *models.LoyaltyPass) error
+	GetBySerial(serial string) (*models.LoyaltyPass, error)
+	GetByUserAndRestaurantAndPlatform(userID, restaurantID uint, platform string) (*models.LoyaltyPass, error)
+	GetByUserID(userID uint) ([]models.LoyaltyPass, error)
+	GetByUserAndRestaurant(userID, restaurantID uint) ([]models.LoyaltyPass, error)
+	Update(pass *models.LoyaltyPass) error
+}
+
+// PrinterRepo is the persistence surface for printers and print jobs.
+type PrinterRepo interface {
+	Create(printer *models.Printer) error
+	GetByKey(key string) (*models.Printer, error)
+	GetByRestaurantID(restaurantID uint) ([]models.Printer, error)
+	Update(printer *models.Printer) error
+	CreateJob(job *models.PrintJob) error
+	GetJobByID(id uint) (*models.PrintJob, error)
+	GetPendingJobs(printerID uint) ([]models.PrintJob, error)
+	GetJobsByRestaurantID(restaurantID uint) ([]models.PrintJob, error)
+	UpdateJob(job *models.PrintJob) error
+}
+
+// HardwareRepo is the persistence surface for hardware devices.
+type HardwareRepo interface {
+	Create(device *models.HardwareDevice) error
+	GetByID(id uint) (*models.HardwareDevice, error)
+	GetByToken(token string) (*models.HardwareDevice, error)
+	GetByRestaurantID(restaurantID uint) ([]models.HardwareDevice, error)
+	Update(device *models.HardwareDevice) error
+}
+
+// WebhookRepo is the persistence surface for webhook deliveries.
+type WebhookRepo interface {
+	Create(delivery *models.WebhookDelivery) error
+	GetByID(id uint) (*models.WebhookDelivery, error)
+	GetByRestaurantID(restaurantID uint) ([]models.WebhookDelivery, error)
+	Update(delivery *models.WebhookDelivery) error
+}
+
+// TxRunner runs a function inside a single database transaction.
+type TxRunner interface {
+	Transaction(fn func(tx *gorm.DB) error) error
+}
+
+// OutboxWriter persists domain events inside the caller's transaction.
+type OutboxWriter interface {
+	CreateInTx(tx *gorm.DB, event *models.OutboxEvent) error
+}
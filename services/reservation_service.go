@@ -0,0 +1,302 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// ReservationService implements reservation management.
+type ReservationService struct {
+	reservationRepo ReservationRepo
+	restaurantRepo  RestaurantRepo
+	customerRepo    CustomerRepo
+	payments        payments.Provider
+}
+
+// NewReservationService builds a ReservationService.
+func NewReservationService(reservationRepo ReservationRepo, restaurantRepo RestaurantRepo, customerRepo CustomerRepo, provider payments.Provider) *ReservationService {
+	return &ReservationService{
+		reservationRepo: reservationRepo,
+		restaurantRepo:  restaurantRepo,
+		customerRepo:    customerRepo,
+		payments:        provider,
+	}
+}
+
+// CreateReservation books a table at the given restaurant. customerID
+// links the booking to a logged-in customer account; zero means a guest
+// booking. When the restaurant's deposit rules apply to the party size,
+// the deposit is charged to the customer's card on file before the
+// booking is recorded.
+func (s *ReservationService) CreateReservation(restaurantID, customerID uint, customerName, customerEmail string, partySize int, date time.Time, notes string) (*models.Reservation, error) {
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+
+	reservation := &models.Reservation{
+		RestaurantID:  restaurantID,
+		CustomerID:    customerID,
+		CustomerName:  utils.SanitizeText(customerName),
+		CustomerEmail: customerEmail,
+		PartySize:     partySize,
+		// Stored in UTC regardless of the offset the client sent; the
+		// restaurant's timezone is applied when rendering.
+		Date:   date.UTC(),
+		Status: models.ReservationPending,
+		Notes:  utils.SanitizeText(notes),
+	}
+
+	if restaurant.DepositAmount > 0 && restaurant.DepositMinParty > 0 && partySize >= restaurant.DepositMinParty {
+		if customerID == 0 {
+			return nil, Validation("deposit_required",
+				"this party size requires a deposit; book from an account with a saved payment method")
+		}
+		methods, err := s.customerRepo.ListPaymentMethods(customerID)
+		if err != nil {
+			return nil, err
+		}
+		if len(methods) == 0 {
+			return nil, Validation("deposit_required",
+				"this party size requires a deposit; save a payment method first")
+		}
+		ref, err := s.payments.Charge(defaultPaymentMethod(methods).Token,
+			int64(math.Round(restaurant.DepositAmount*100)),
+			fmt.Sprintf("booking deposit at %s", restaurant.Name))
+		if err != nil {
+			return nil, Conflict("deposit_failed", "the deposit charge was declined")
+		}
+		reservation.DepositAmount = restaurant.DepositAmount
+		reservation.DepositChargeRef = ref
+	}
+
+	// The deposit, when one was taken, has already moved at this point,
+	// so a storage failure surfaces loudly for the operator to resolve.
+	if err := s.reservationRepo.Create(reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// defaultPaymentMethod picks the customer's default card, falling back
+// to the first saved one.
+func defaultPaymentMethod(methods []models.CustomerPaymentMethod) models.CustomerPaymentMethod {
+	for _, m := range methods {
+		if m.IsDefault {
+			return m
+		}
+	}
+	return methods[0]
+}
+
+// GetReservationByID returns the reservation with the given id.
+func (s *ReservationService) GetReservationByID(id uint) (*models.Reservation, error) {
+	reservation, err := s.reservationRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "reservation_not_found", "reservation not found")
+	}
+	return reservation, nil
+}
+
+// GetReservationsByRestaurantID returns all reservations of a restaurant.
+func (s *ReservationService) GetReservationsByRestaurantID(restaurantID uint) ([]models.Reservation, error) {
+	return s.reservationRepo.GetByRestaurantID(restaurantID)
+}
+
+// UpdateReservationStatus moves a reservation to a new status, stamping
+// the seating and departure times the first time the seated and
+// completed transitions happen. A positive table assigns the party to
+// that table number. Marking a no-show charges the restaurant's no-show
+// fee to the customer's card on file when one exists.
+func (s *ReservationService) UpdateReservationStatus(id uint, status string, table int) (*models.Reservation, error) {
+	reservation, err := s.reservationRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "reservation_not_found", "reservation not found")
+	}
+	reservation.Status = status
+	if table > 0 {
+		reservation.TableNumber = table
+	}
+	now := time.Now().UTC()
+	switch status {
+	case models.ReservationSeated:
+		if reservation.SeatedAt == nil {
+			reservation.SeatedAt = &now
+		}
+	case models.ReservationCompleted:
+		if reservation.DepartedAt == nil {
+			reservation.DepartedAt = &now
+		}
+	case models.ReservationNoShow:
+		// Best effort: a declined card must not block recording the
+		// no-show itself.
+		s.chargeNoShowFee(reservation)
+	case models.ReservationCancelled:
+		s.refundDepositIfTimely(reservation)
+	}
+	if err := s.reservationRepo.Update(reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// CancelReservation cancels a customer's own booking, refunding any
+// deposit when the cancellation is timely.
+func (s *ReservationService) CancelReservation(id, customerID uint) (*models.Reservation, error) {
+	reservation, err := s.reservationRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "reservation_not_found", "reservation not found")
+	}
+	if reservation.CustomerID == 0 || reservation.CustomerID != customerID {
+		return nil, Forbidden("forbidden", "forbidden")
+	}
+	if reservation.Status == models.ReservationCancelled {
+		return reservation, nil
+	}
+	reservation.Status = models.ReservationCancelled
+	s.refundDepositIfTimely(reservation)
+	if err := s.reservationRepo.Update(reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// refundDepositIfTimely refunds the booking deposit when the
+// cancellation lands at least the restaurant's refund window before the
+// booking time. Late cancellations keep the deposit; a provider failure
+// is logged so the operator can refund manually.
+func (s *ReservationService) refundDepositIfTimely(reservation *models.Reservation) {
+	if reservation.DepositChargeRef == "" || reservation.DepositRefundRef != "" {
+		return
+	}
+	restaurant, err := s.restaurantRepo.GetByID(reservation.RestaurantID)
+	if err != nil {
+		return
+	}
+	window := time.Duration(restaurant.DepositRefundHours) * time.Hour
+	if time.Until(reservation.Date) < window {
+		return
+	}
+	ref, err := s.payments.Refund(reservation.DepositChargeRef,
+		int64(math.Round(reservation.DepositAmount*100)), "reservation cancelled in time")
+	if err != nil {
+		log.Printf("reservations: refunding deposit for reservation %d: %v", reservation.ID, err)
+		return
+	}
+	reservation.DepositRefundRef = ref
+}
+
+// chargeNoShowFee charges the restaurant's no-show fee to the booking
+// customer's card on file, recording the amount and provider reference
+// on the reservation. Nothing happens when the restaurant has no fee,
+// the booking was a guest's, the customer has no saved card, or the fee
+// was already charged.
+func (s *ReservationService) chargeNoShowFee(reservation *models.Reservation) {
+	if reservation.NoShowChargeRef != "" || reservation.CustomerID == 0 {
+		return
+	}
+	restaurant, err := s.restaurantRepo.GetByID(reservation.RestaurantID)
+	if err != nil || restaurant.NoShowFee <= 0 {
+		return
+	}
+	methods, err := s.customerRepo.ListPaymentMethods(reservation.CustomerID)
+	if err != nil || len(methods) == 0 {
+		return
+	}
+	ref, err := s.payments.Charge(defaultPaymentMethod(methods).Token, int64(math.Round(restaurant.NoShowFee*100)),
+		fmt.Sprintf("no-show fee for reservation %d at %s", reservation.ID, restaurant.Name))
+	if err != nil {
+		log.Printf("reservations: charging no-show fee for reservation %d: %v", reservation.ID, err)
+		return
+	}
+	reservation.NoShowFee = restaurant.NoShowFee
+	reservation.NoShowChargeRef = ref
+}
+
+// NoShowCount returns how many times the given customer email has been
+// marked as a no-show, across all restaurants. Repeat offenders show up
+// regardless of where they failed to appear.
+func (s *ReservationService) NoShowCount(customerEmail string) (int64, error) {
+	if customerEmail == "" {
+		return 0, Validation("missing_email", "an email is required")
+	}
+	return s.reservationRepo.CountNoShowsByEmail(utils.NormalizeEmail(customerEmail))
+}
+
+// AvailabilitySlot is one bookable time on the availability calendar.
+// Time is local to the restaurant's timezone.
+type AvailabilitySlot struct {
+	Time      string `json:"time"` // "18:30"
+	SeatsLeft int    `json:"seats_left"`
+}
+
+// Availability computes the open booking slots of a restaurant for one
+// day, from its booking rules (seats, opening hours, turn time) and the
+// reservations already on the books. day is "YYYY-MM-DD" in the
+// restaurant's local timezone; slots are offered every half hour and a
+// slot appears only while seats remain for a full turn.
+func (s *ReservationService) Availability(restaurantID uint, day string) ([]AvailabilitySlot, error) {
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+
+	loc := restaurant.Location()
+	date, err := time.ParseInLocation("2006-01-02", day, loc)
+	if err != nil {
+		return nil, Validation("invalid_date", "date must be YYYY-MM-DD")
+	}
+	opens, err := time.Parse("15:04", restaurant.OpensAt)
+	if err != nil {
+		return nil, Validation("invalid_booking_rules", "restaurant has no valid opening time")
+	}
+	closes, err := time.Parse("15:04", restaurant.ClosesAt)
+	if err != nil {
+		return nil, Validation("invalid_booking_rules", "restaurant has no valid closing time")
+	}
+
+	turn := time.Duration(restaurant.TurnMinutes) * time.Minute
+	if turn <= 0 {
+		turn = 90 * time.Minute
+	}
+	open := date.Add(time.Duration(opens.Hour())*time.Hour + time.Duration(opens.Minute())*time.Minute)
+	close := date.Add(time.Duration(closes.Hour())*time.Hour + time.Duration(closes.Minute())*time.Minute)
+
+	// One query covers every reservation that can overlap the day's
+	// slots: anything seated up to a turn before opening still holds
+	// seats when the first slot starts.
+	reservations, err := s.reservationRepo.GetByRestaurantAndRange(restaurantID, open.Add(-turn).UTC(), close.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	slots := []AvailabilitySlot{}
+	for start := open; !start.Add(turn).After(close); start = start.Add(30 * time.Minute) {
+		booked := 0
+		for _, res := range reservations {
+			if res.Status == models.ReservationCancelled {
+				continue
+			}
+			seated := res.Date.In(loc)
+			if seated.Before(start.Add(turn)) && seated.Add(turn).After(start) {
+				booked += res.PartySize
+			}
+		}
+		if left := restaurant.Seats - booked; left > 0 {
+			slots = append(slots, AvailabilitySlot{Time: start.Format("15:04"), SeatsLeft: left})
+		}
+	}
+	return slots, nil
+}
+
+// CountPendingByOwner returns how many pending reservations exist across
+// all restaurants owned by the given user.
+func (s *ReservationService) CountPendingByOwner(userID uint) (int64, error) {
+	return s.reservationRepo.CountPendingByOwner(userID)
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func newReservationServiceForTest(t *testing.T) (*ReservationService, *models.Restaurant, *gorm.DB) {
+	t.Helper()
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	owner := models.User{Name: "Ana", Email: "ana@example.com", Role: models.RoleOwner}
+	if err := db.Create(&owner).Error; err != nil {
+		t.Fatalf("creating owner: %v", err)
+	}
+	restaurant := models.Restaurant{
+		Name:        "Testaurant",
+		UserID:      owner.ID,
+		Timezone:    "UTC",
+		Seats:       10,
+		OpensAt:     "18:00",
+		ClosesAt:    "20:00",
+		TurnMinutes: 60,
+	}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("creating restaurant: %v", err)
+	}
+
+	svc := NewReservationService(
+		repositories.NewReservationRepository(db),
+		repositories.NewRestaurantRepository(db),
+		repositories.NewCustomerRepository(db),
+		payments.LogProvider{},
+	)
+	return svc, &restaurant, db
+}
+
+func TestAvailabilitySubtractsOverlappingReservations(t *testing.T) {
+	svc, restaurant, _ := newReservationServiceForTest(t)
+
+	// 18:00-20:00 with a 60-minute turn yields 18:00, 18:30 and 19:00.
+	slots, err := svc.Availability(restaurant.ID, "2026-09-01")
+	if err != nil {
+		t.Fatalf("Availability: %v", err)
+	}
+	if len(slots) != 3 {
+		t.Fatalf("expected 3 slots, got %d: %+v", len(slots), slots)
+	}
+	for _, slot := range slots {
+		if slot.SeatsLeft != 10 {
+			t.Fatalf("expected 10 seats left at %s, got %d", slot.Time, slot.SeatsLeft)
+		}
+	}
+
+	// A party of six seated at 18:30 occupies seats until 19:30,
+	// overlapping every slot of the evening.
+	seated := time.Date(2026, 9, 1, 18, 30, 0, 0, time.UTC)
+	if _, err := svc.CreateReservation(restaurant.ID, 0, "Pedro", "pedro@example.com", 6, seated, ""); err != nil {
+		t.Fatalf("CreateReservation: %v", err)
+	}
+
+	slots, err = svc.Availability(restaurant.ID, "2026-09-01")
+	if err != nil {
+		t.Fatalf("Availability: %v", err)
+	}
+	if len(slots) != 3 {
+		t.Fatalf("expected 3 slots, got %d: %+v", len(slots), slots)
+	}
+	for _, slot := range slots {
+		if slot.SeatsLeft != 4 {
+			t.Fatalf("expected 4 seats left at %s, got %d", slot.Time, slot.SeatsLeft)
+		}
+	}
+}
+
+func TestAvailabilityRejectsBadDate(t *testing.T) {
+	svc, restaurant, _ := newReservationServiceForTest(t)
+
+	if _, err := svc.Availability(restaurant.ID, "first of june"); err == nil {
+		t.Fatal("expected an error for a malformed date")
+	}
+}
+
+func TestNoShowChargesFeeAndCounts(t *testing.T) {
+	svc, restaurant, db := newReservationServiceForTest(t)
+
+	restaurant.NoShowFee = 5000
+	if err := db.Save(restaurant).Error; err != nil {
+		t.Fatalf("saving restaurant: %v", err)
+	}
+	customer := models.User{Name: "Pedro", Email: "pedro@example.com", Role: models.RoleCustomer}
+	if err := db.Create(&customer).Error; err != nil {
+		t.Fatalf("creating customer: %v", err)
+	}
+	card := models.CustomerPaymentMethod{UserID: customer.ID, Provider: "stripe", Token: "pm_123", IsDefault: true}
+	if err := db.Create(&card).Error; err != nil {
+		t.Fatalf("creating payment method: %v", err)
+	}
+
+	date := time.Date(2026, 9, 1, 19, 0, 0, 0, time.UTC)
+	reservation, err := svc.CreateReservation(restaurant.ID, customer.ID, "Pedro", "pedro@example.com", 2, date, "")
+	if err != nil {
+		t.Fatalf("CreateReservation: %v", err)
+	}
+
+	updated, err := svc.UpdateReservationStatus(reservation.ID, models.ReservationNoShow, 0)
+	if err != nil {
+		t.Fatalf("UpdateReservationStatus: %v", err)
+	}
+	if updated.Status != models.ReservationNoShow {
+		t.Fatalf("expected status no_show, got %s", updated.Status)
+	}
+	if updated.NoShowFee != 5000 {
+		t.Fatalf("expected a 5000 no-show fee, got %.2f", updated.NoShowFee)
+	}
+	if updated.NoShowChargeRef == "" {
+		t.Fatal("expected a charge reference on the reservation")
+	}
+
+	// Marking the no-show again must not charge the card twice.
+	ref := updated.NoShowChargeRef
+	again, err := svc.UpdateReservationStatus(reservation.ID, models.ReservationNoShow, 0)
+	if err != nil {
+		t.Fatalf("UpdateReservationStatus (again): %v", err)
+	}
+	if again.NoShowChargeRef != ref {
+		t.Fatalf("expected the original charge reference %s, got %s", ref, again.NoShowChargeRef)
+	}
+
+	count, err := svc.NoShowCount("pedro@example.com")
+	if err != nil {
+		t.Fatalf("NoShowCount: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 no-show, got %d", count)
+	}
+}
+
+func TestDepositChargedAndRefundedOnTimelyCancellation(t *testing.T) {
+	svc, restaurant, db := newReservationServiceForTest(t)
+
+	restaurant.DepositAmount = 10000
+	restaurant.DepositMinParty = 6
+	restaurant.DepositRefundHours = 24
+	if err := db.Save(restaurant).Error; err != nil {
+		t.Fatalf("saving restaurant: %v", err)
+	}
+	customer := models.User{Name: "Sofia", Email: "sofia@example.com", Role: models.RoleCustomer}
+	if err := db.Create(&customer).Error; err != nil {
+		t.Fatalf("creating customer: %v", err)
+	}
+	card := models.CustomerPaymentMethod{UserID: customer.ID, Provider: "stripe", Token: "pm_456", IsDefault: true}
+	if err := db.Create(&card).Error; err != nil {
+		t.Fatalf("creating payment method: %v", err)
+	}
+
+	// A small party books without any deposit.
+	small, err := svc.CreateReservation(restaurant.ID, customer.ID, "Sofia", "sofia@example.com", 2,
+		time.Now().UTC().Add(72*time.Hour), "")
+	if err != nil {
+		t.Fatalf("CreateReservation (small party): %v", err)
+	}
+	if small.DepositChargeRef != "" {
+		t.Fatalf("expected no deposit for a small party, got charge %q", small.DepositChargeRef)
+	}
+
+	// A guest cannot book a large party at all: there is no card to
+	// charge the deposit to.
+	if _, err := svc.CreateReservation(restaurant.ID, 0, "Guest", "guest@example.com", 8,
+		time.Now().UTC().Add(72*time.Hour), ""); err == nil {
+		t.Fatal("expected a guest's large-party booking to be rejected")
+	}
+
+	// A large party prepays, and a timely cancellation refunds it.
+	large, err := svc.CreateReservation(restaurant.ID, customer.ID, "Sofia", "sofia@example.com", 8,
+		time.Now().UTC().Add(72*time.Hour), "")
+	if err != nil {
+		t.Fatalf("CreateReservation (large party): %v", err)
+	}
+	if large.DepositAmount != 10000 || large.DepositChargeRef == "" {
+		t.Fatalf("expected a 10000 deposit charge, got amount %.2f ref %q", large.DepositAmount, large.DepositChargeRef)
+	}
+	cancelled, err := svc.CancelReservation(large.ID, customer.ID)
+	if err != nil {
+		t.Fatalf("CancelReservation: %v", err)
+	}
+	if cancelled.Status != models.ReservationCancelled || cancelled.DepositRefundRef == "" {
+		t.Fatalf("expected a refunded cancellation, got status %s refund %q", cancelled.Status, cancelled.DepositRefundRef)
+	}
+
+	// Cancelling inside the refund window keeps the deposit.
+	late, err := svc.CreateReservation(restaurant.ID, customer.ID, "Sofia", "sofia@example.com", 8,
+		time.Now().UTC().Add(2*time.Hour), "")
+	if err != nil {
+		t.Fatalf("CreateReservation (late): %v", err)
+	}
+	cancelled, err = svc.CancelReservation(late.ID, customer.ID)
+	if err != nil {
+		t.Fatalf("CancelReservation (late): %v", err)
+	}
+	if cancelled.DepositRefundRef != "" {
+		t.Fatalf("expected no refund for a late cancellation, got %q", cancelled.DepositRefundRef)
+	}
+
+	// Only the booking customer can cancel.
+	other := models.User{Name: "Ben", Email: "ben@example.com", Role: models.RoleCustomer}
+	if err := db.Create(&other).Error; err != nil {
+		t.Fatalf("creating other customer: %v", err)
+	}
+	if _, err := svc.CancelReservation(small.ID, other.ID); err == nil {
+		t.Fatal("expected cancelling someone else's reservation to fail")
+	}
+}
+
+func TestNoShowWithoutCardStillRecorded(t *testing.T) {
+	svc, restaurant, _ := newReservationServiceForTest(t)
+
+	// A guest booking has no card on file; the no-show must still stick.
+	date := time.Date(2026, 9, 1, 19, 0, 0, 0, time.UTC)
+	reservation, err := svc.CreateReservation(restaurant.ID, 0, "Walk In", "walkin@example.com", 2, date, "")
+	if err != nil {
+		t.Fatalf("CreateReservation: %v", err)
+	}
+
+	updated, err := svc.UpdateReservationStatus(reservation.ID, models.ReservationNoShow, 0)
+	if err != nil {
+		t.Fatalf("UpdateReservationStatus: %v", err)
+	}
+	if updated.Status != models.ReservationNoShow {
+		t.Fatalf("expected status no_show, got %s", updated.Status)
+	}
+	if updated.NoShowFee != 0 || updated.NoShowChargeRef != "" {
+		t.Fatalf("expected no charge for a guest booking, got fee %.2f ref %q", updated.NoShowFee, updated.NoShowChargeRef)
+	}
+}
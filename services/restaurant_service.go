@@ -0,0 +1,324 @@
+package services
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/cache"
+	"github.com/marc0cl/wets-ma-bu-api/events"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// RestaurantService implements restaurant management.
+type RestaurantService struct {
+	restaurantRepo RestaurantRepo
+	tx             TxRunner
+	outbox         OutboxWriter
+	cache          cache.Cache
+	index          SearchIndex // nil when full-text search is off
+}
+
+// NewRestaurantService builds a RestaurantService.
+func NewRestaurantService(restaurantRepo RestaurantRepo, tx TxRunner, outbox OutboxWriter, c cache.Cache, index SearchIndex) *RestaurantService {
+	return &RestaurantService{restaurantRepo: restaurantRepo, tx: tx, outbox: outbox, cache: c, index: index}
+}
+
+// syncIndex mirrors a write into the search index. Failures are logged,
+// not surfaced: the database remains the source of truth and search
+// degrades to it anyway.
+func (s *RestaurantService) syncIndex(op func() error) {
+	if s.index == nil {
+		return
+	}
+	if err := op(); err != nil {
+		log.Printf("search index: %v", err)
+	}
+}
+
+// CreateRestaurant creates a restaurant owned by the given user. An
+// empty timezone defaults to UTC; anything else must be a valid IANA
+// zone name.
+func (s *RestaurantService) CreateRestaurant(userID uint, name, description, address, phone, timezone string) (*models.Restaurant, error) {
+	timezone, err := normalizeTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+	restaurant := &models.Restaurant{
+		Name:        utils.SanitizeText(name),
+		Description: utils.SanitizeText(description),
+		Address:     utils.SanitizeText(address),
+		Phone:       utils.SanitizeText(phone),
+		Timezone:    timezone,
+		UserID:      userID,
+	}
+	// The restaurant and its created event commit together.
+	err = s.tx.Transaction(func(tx *gorm.DB) error {
+		if err := s.restaurantRepo.CreateInTx(tx, restaurant); err != nil {
+			return err
+		}
+		event, err := events.NewOutboxEvent(events.EventRestaurantCreated, map[string]interface{}{
+			"restaurant_id": restaurant.ID,
+			"user_id":       restaurant.UserID,
+			"name":          restaurant.Name,
+		})
+		if err != nil {
+			return err
+		}
+		return s.outbox.CreateInTx(tx, event)
+	})
+	if err != nil {
+		if repositories.IsDuplicateKey(err) {
+			return nil, s.duplicateNameError(userID, restaurant.Name)
+		}
+		return nil, err
+	}
+	s.syncIndex(func() error { return s.index.IndexRestaurant(restaurant) })
+	return restaurant, nil
+}
+
+// duplicateNameError builds the 409 returned when an owner reuses a
+// restaurant name, with a "did you mean" hint listing their existing
+// names that are close to the rejected one.
+func (s *RestaurantService) duplicateNameError(userID uint, name string) *Error {
+	message := "you already have a restaurant with this name"
+	if existing, err := s.restaurantRepo.GetByUserID(userID); err == nil {
+		var hints []string
+		for _, r := range existing {
+			if strings.EqualFold(r.Name, name) || levenshtein(strings.ToLower(r.Name), strings.ToLower(name)) <= 2 {
+				hints = append(hints, r.Name)
+			}
+		}
+		if len(hints) > 0 {
+			message += "; did you mean: " + strings.Join(hints, ", ")
+		}
+	}
+	return Conflict("duplicate_restaurant_name", message)
+}
+
+// levenshtein returns the edit distance between a and b, used only for
+// the short "did you mean" lists so the quadratic cost is irrelevant.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(curr[j-1]+1, prev[j]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// normalizeTimezone validates an IANA zone name, mapping the empty
+// string to UTC.
+func normalizeTimezone(timezone string) (string, error) {
+	if timezone == "" {
+		return "UTC", nil
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return "", Validation("invalid_timezone", "timezone must be a valid IANA zone name")
+	}
+	return timezone, nil
+}
+
+// ResolveID maps a path identifier to the internal primary key. Public
+// UUIDs are looked up; bare integers are still accepted so pre-UUID
+// clients keep working.
+func (s *RestaurantService) ResolveID(param string) (uint, error) {
+	if n, err := strconv.ParseUint(param, 10, 32); err == nil {
+		return uint(n), nil
+	}
+	restaurant, err := s.restaurantRepo.GetByPublicID(param)
+	if err != nil {
+		return 0, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	return restaurant.ID, nil
+}
+
+// GetRestaurantByID returns a restaurant after checking it belongs to userID.
+func (s *RestaurantService) GetRestaurantByID(id, userID uint) (*models.Restaurant, error) {
+	restaurant, err := s.restaurantRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	if restaurant.UserID != userID {
+		// Another owner's restaurant stays indistinguishable from a
+		// missing one.
+		return nil, NotFound("restaurant_not_found", "restaurant not found")
+	}
+	return restaurant, nil
+}
+
+// RestaurantOwner returns the owning user ID and name of a restaurant,
+// for notifying owners about public actions (orders, reservations). It
+// deliberately exposes nothing else, unlike the old unchecked getter.
+func (s *RestaurantService) RestaurantOwner(id uint) (ownerID uint, name string, err error) {
+	restaurant, err := s.restaurantRepo.GetByID(id)
+	if err != nil {
+		return 0, "", fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	return restaurant.UserID, restaurant.Name, nil
+}
+
+// GetRestaurantsByUserID returns all restaurants owned by the given user.
+func (s *RestaurantService) GetRestaurantsByUserID(userID uint) ([]models.Restaurant, error) {
+	return s.restaurantRepo.GetByUserID(userID)
+}
+
+// UpdateRestaurant updates the fields of an existing restaurant after
+// verifying callerID owns it, in a single fetch. Nil pointers mean "not
+// provided"; a pointer to the empty string clears the field, so a
+// description or phone can be removed. The name cannot be cleared and
+// the timezone resets to UTC when cleared.
+func (s *RestaurantService) UpdateRestaurant(id, callerID uint, name, description, address, phone, timezone *string) (*models.Restaurant, error) {
+	restaurant, err := s.restaurantRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	if restaurant.UserID != callerID {
+		return nil, Forbidden("forbidden", "forbidden")
+	}
+	if name != nil {
+		if *name == "" {
+			return nil, Validation("name_required", "name cannot be empty")
+		}
+		restaurant.Name = utils.SanitizeText(*name)
+	}
+	if description != nil {
+		restaurant.Description = utils.SanitizeText(*description)
+	}
+	if address != nil {
+		restaurant.Address = utils.SanitizeText(*address)
+	}
+	if phone != nil {
+		restaurant.Phone = utils.SanitizeText(*phone)
+	}
+	if timezone != nil {
+		normalized, err := normalizeTimezone(*timezone)
+		if err != nil {
+			return nil, err
+		}
+		restaurant.Timezone = normalized
+	}
+	if err := s.restaurantRepo.Update(restaurant); err != nil {
+		if repositories.IsDuplicateKey(err) {
+			return nil, s.duplicateNameError(callerID, restaurant.Name)
+		}
+		return nil, err
+	}
+	s.cache.Delete(cache.RestaurantKey(id))
+	s.syncIndex(func() error { return s.index.IndexRestaurant(restaurant) })
+	return restaurant, nil
+}
+
+// UpdateBookingRules adjusts the settings the availability calendar is
+// computed from, after verifying callerID owns the restaurant. Nil
+// pointers leave a setting untouched.
+func (s *RestaurantService) UpdateBookingRules(id, callerID uint, seats *int, opensAt, closesAt *string, turnMinutes *int, noShowFee, depositAmount *float64, depositMinParty, depositRefundHours *int) (*models.Restaurant, error) {
+	restaurant, err := s.restaurantRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	if restaurant.UserID != callerID {
+		return nil, Forbidden("forbidden", "forbidden")
+	}
+	if seats != nil {
+		if *seats <= 0 {
+			return nil, Validation("invalid_seats", "seats must be positive")
+		}
+		restaurant.Seats = *seats
+	}
+	if opensAt != nil {
+		if _, err := time.Parse("15:04", *opensAt); err != nil {
+			return nil, Validation("invalid_time", "opens_at must be HH:MM")
+		}
+		restaurant.OpensAt = *opensAt
+	}
+	if closesAt != nil {
+		if _, err := time.Parse("15:04", *closesAt); err != nil {
+			return nil, Validation("invalid_time", "closes_at must be HH:MM")
+		}
+		restaurant.ClosesAt = *closesAt
+	}
+	if turnMinutes != nil {
+		if *turnMinutes < 15 || *turnMinutes > 8*60 {
+			return nil, Validation("invalid_turn", "turn_minutes must be between 15 and 480")
+		}
+		restaurant.TurnMinutes = *turnMinutes
+	}
+	if noShowFee != nil {
+		if *noShowFee < 0 {
+			return nil, Validation("invalid_no_show_fee", "no_show_fee cannot be negative")
+		}
+		restaurant.NoShowFee = *noShowFee
+	}
+	if depositAmount != nil {
+		if *depositAmount < 0 {
+			return nil, Validation("invalid_deposit", "deposit_amount cannot be negative")
+		}
+		restaurant.DepositAmount = *depositAmount
+	}
+	if depositMinParty != nil {
+		if *depositMinParty < 0 {
+			return nil, Validation("invalid_deposit", "deposit_min_party cannot be negative")
+		}
+		restaurant.DepositMinParty = *depositMinParty
+	}
+	if depositRefundHours != nil {
+		if *depositRefundHours < 0 {
+			return nil, Validation("invalid_deposit", "deposit_refund_hours cannot be negative")
+		}
+		restaurant.DepositRefundHours = *depositRefundHours
+	}
+	if err := s.restaurantRepo.Update(restaurant); err != nil {
+		return nil, err
+	}
+	s.cache.Delete(cache.RestaurantKey(id))
+	return restaurant, nil
+}
+
+// DeleteRestaurant soft-deletes the restaurant with the given id after
+// verifying callerID owns it.
+func (s *RestaurantService) DeleteRestaurant(id, callerID uint) error {
+	restaurant, err := s.restaurantRepo.GetByID(id)
+	if err != nil {
+		return fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	if restaurant.UserID != callerID {
+		return Forbidden("forbidden", "forbidden")
+	}
+	if err := s.restaurantRepo.Delete(id); err != nil {
+		return err
+	}
+	// A deleted restaurant must not keep serving its cached menu.
+	s.cache.Delete(cache.RestaurantKey(id))
+	s.cache.Delete(cache.MenuKey(id))
+	s.syncIndex(func() error { return s.index.RemoveRestaurant(id) })
+	return nil
+}
+
+// CountByUserID returns how many restaurants the given user owns.
+func (s *RestaurantService) CountByUserID(userID uint) (int64, error) {
+	return s.restaurantRepo.CountByUserID(userID)
+}
+
+// ListRestaurants returns a filtered page of all restaurants. Admin only;
+// the handler enforces the role.
+func (s *RestaurantService) ListRestaurants(filter repositories.RestaurantFilter) ([]models.Restaurant, int64, error) {
+	return s.restaurantRepo.ListPaged(filter)
+}
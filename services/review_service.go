@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/reviews"
+	"github.com/marc0cl/wets-ma-bu-api/sentiment"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// reviewBackfillWindow bounds the first pull of a freshly connected
+// account so it does not fetch the listing's entire history.
+const reviewBackfillWindow = 90 * 24 * time.Hour
+
+// ReviewService stores native reviews, pulls external ones in beside
+// them, and aggregates both into ratings with a per-source breakdown.
+type ReviewService struct {
+	reviewRepo        ReviewRepo
+	reviewAccountRepo ReviewAccountRepo
+	restaurantRepo    RestaurantRepo
+	provider          reviews.Provider
+	analyzer          sentiment.Analyzer
+}
+
+// NewReviewService builds a ReviewService.
+func NewReviewService(reviewRepo ReviewRepo, reviewAccountRepo ReviewAccountRepo, restaurantRepo RestaurantRepo, provider reviews.Provider, analyzer sentiment.Analyzer) *ReviewService {
+	return &ReviewService{
+		reviewRepo:        reviewRepo,
+		reviewAccountRepo: reviewAccountRepo,
+		restaurantRepo:    restaurantRepo,
+		provider:          provider,
+		analyzer:          analyzer,
+	}
+}
+
+// analyze stamps sentiment and topics onto a review. Analysis is
+// best-effort: a failing analyzer leaves the review untagged rather
+// than unsaved.
+func (s *ReviewService) analyze(review *models.Review) {
+	if s.analyzer == nil {
+		return
+	}
+	result, err := s.analyzer.Analyze(review.Comment, review.Rating)
+	if err != nil {
+		log.Printf("reviews: analyzing review: %v", err)
+		return
+	}
+	review.Sentiment = result.Sentiment
+	review.Topics = strings.Join(result.Topics, ",")
+}
+
+// AddReview posts a native review.
+func (s *ReviewService) AddReview(restaurantID uint, author string, rating int, comment string) (*models.Review, error) {
+	if _, err := s.restaurantRepo.GetByID(restaurantID); err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	author = utils.SanitizeText(author)
+	if author == "" {
+		return nil, Validation("missing_author", "a name is required")
+	}
+	if rating < 1 || rating > 5 {
+		return nil, Validation("invalid_rating", "rating must be between 1 and 5")
+	}
+
+	review := &models.Review{
+		RestaurantID: restaurantID,
+		Source:       models.ReviewSourceNative,
+		Author:       author,
+		Rating:       rating,
+		Comment:      utils.SanitizeText(comment),
+		PostedAt:     time.Now().UTC(),
+	}
+	s.analyze(review)
+	if err := s.reviewRepo.Create(review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// Reviews returns a restaurant's reviews across every source, newest
+// first.
+func (s *ReviewService) Reviews(restaurantID uint) ([]models.Review, error) {
+	return s.reviewRepo.GetByRestaurantID(restaurantID)
+}
+
+// RatingSummary is a restaurant's aggregated rating with its per-source
+// breakdown.
+type RatingSummary struct {
+	Count   int64                            `json:"count"`
+	Average float64                          `json:"average"`
+	Sources []repositories.ReviewSourceStats `json:"sources"`
+}
+
+// Ratings aggregates a restaurant's reviews. The overall average weighs
+// every review equally, not every source.
+func (s *ReviewService) Ratings(restaurantID uint) (*RatingSummary, error) {
+	stats, err := s.reviewRepo.RatingsBySource(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	summary := &RatingSummary{Sources: stats}
+	var sum float64
+	for _, st := range stats {
+		summary.Count += st.Count
+		sum += st.Average * float64(st.Count)
+	}
+	if summary.Count > 0 {
+		summary.Average = sum / float64(summary.Count)
+	}
+	return summary, nil
+}
+
+// TopicTrend is one topic's slice of a window's reviews.
+type TopicTrend struct {
+	Topic         string  `json:"topic"`
+	Count         int     `json:"count"`
+	AverageRating float64 `json:"average_rating"`
+	NegativeCount int     `json:"negative_count"`
+}
+
+// ReviewTrends is what moved a restaurant's ratings over a window:
+// sentiment counts and the topics driving them.
+type ReviewTrends struct {
+	Count      int            `json:"count"`
+	Sentiments map[string]int `json:"sentiments"`
+	Topics     []TopicTrend   `json:"topics"`
+}
+
+// Trends aggregates analyzed reviews over a window. Topics are sorted
+// by negative mentions first: the list reads as "what to fix".
+func (s *ReviewService) Trends(restaurantID uint, from, to time.Time) (*ReviewTrends, error) {
+	all, err := s.reviewRepo.GetByRestaurantAndRange(restaurantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	trends := &ReviewTrends{Count: len(all), Sentiments: make(map[string]int)}
+	type acc struct {
+		count, negative int
+		stars           int
+	}
+	byTopic := make(map[string]*acc)
+	for _, review := range all {
+		if review.Sentiment != "" {
+			trends.Sentiments[review.Sentiment]++
+		}
+		for _, topic := range strings.Split(review.Topics, ",") {
+			if topic == "" {
+				continue
+			}
+			a, ok := byTopic[topic]
+			if !ok {
+				a = &acc{}
+				byTopic[topic] = a
+			}
+			a.count++
+			a.stars += review.Rating
+			if review.Sentiment == sentiment.Negative {
+				a.negative++
+			}
+		}
+	}
+
+	for topic, a := range byTopic {
+		trends.Topics = append(trends.Topics, TopicTrend{
+			Topic:         topic,
+			Count:         a.count,
+			AverageRating: float64(a.stars) / float64(a.count),
+			NegativeCount: a.negative,
+		})
+	}
+	sort.Slice(trends.Topics, func(i, j int) bool {
+		if trends.Topics[i].NegativeCount != trends.Topics[j].NegativeCount {
+			return trends.Topics[i].NegativeCount > trends.Topics[j].NegativeCount
+		}
+		return trends.Topics[i].Topic < trends.Topics[j].Topic
+	})
+	return trends, nil
+}
+
+// Connect links a restaurant to a review platform, or refreshes the
+// credentials of an existing link.
+func (s *ReviewService) Connect(restaurantID uint, platform, apiKey, externalID string) (*models.ReviewAccount, error) {
+	switch platform {
+	case models.ReviewPlatformGoogle, models.ReviewPlatformYelp:
+	default:
+		return nil, Validation("invalid_platform", "platform must be google or yelp")
+	}
+	if apiKey == "" || externalID == "" {
+		return nil, Validation("missing_credentials", "an API key and listing id are required")
+	}
+
+	account, err := s.reviewAccountRepo.GetByRestaurantAndPlatform(restaurantID, platform)
+	if err != nil {
+		account = &models.ReviewAccount{RestaurantID: restaurantID, Platform: platform, APIKey: apiKey, ExternalID: externalID}
+		if err := s.reviewAccountRepo.Create(account); err != nil {
+			return nil, err
+		}
+		return account, nil
+	}
+	account.APIKey = apiKey
+	account.ExternalID = externalID
+	if err := s.reviewAccountRepo.Update(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// Accounts returns a restaurant's connected review platforms.
+func (s *ReviewService) Accounts(restaurantID uint) ([]models.ReviewAccount, error) {
+	return s.reviewAccountRepo.GetByRestaurantID(restaurantID)
+}
+
+// SyncRestaurant runs one pull over a restaurant's accounts and returns
+// how many reviews were imported or updated.
+func (s *ReviewService) SyncRestaurant(restaurantID uint, now time.Time) (int, error) {
+	accounts, err := s.reviewAccountRepo.GetByRestaurantID(restaurantID)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for i := range accounts {
+		n, err := s.syncAccount(&accounts[i], now)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// RunOnce pulls every connected account. Per-account failures are
+// recorded on the account and do not stop the rest.
+func (s *ReviewService) RunOnce(now time.Time) (int, error) {
+	accounts, err := s.reviewAccountRepo.List()
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for i := range accounts {
+		n, err := s.syncAccount(&accounts[i], now)
+		total += n
+		if err != nil {
+			log.Printf("reviews: %s account %d: %v", accounts[i].Platform, accounts[i].ID, err)
+		}
+	}
+	return total, nil
+}
+
+// syncAccount pulls one platform's reviews and stamps the outcome on
+// the account.
+func (s *ReviewService) syncAccount(account *models.ReviewAccount, now time.Time) (int, error) {
+	since := now.Add(-reviewBackfillWindow)
+	if account.LastSyncAt != nil {
+		since = *account.LastSyncAt
+	}
+
+	synced := 0
+	externals, err := s.provider.FetchReviews(account.Platform, account.APIKey, account.ExternalID, since)
+	if err == nil {
+		for _, ext := range externals {
+			if importErr := s.importExternal(account, ext); importErr != nil {
+				err = importErr
+				break
+			}
+			synced++
+		}
+	}
+
+	account.LastSyncAt = &now
+	if err != nil {
+		account.LastSyncStatus = "error"
+		account.LastSyncError = err.Error()
+	} else {
+		account.LastSyncStatus = "ok"
+		account.LastSyncError = ""
+	}
+	if updateErr := s.reviewAccountRepo.Update(account); updateErr != nil && err == nil {
+		err = updateErr
+	}
+	return synced, err
+}
+
+// importExternal upserts one platform review: edits on the platform
+// update our copy rather than duplicating it.
+func (s *ReviewService) importExternal(account *models.ReviewAccount, ext reviews.ExternalReview) error {
+	if ext.Ref == "" {
+		return Validation("missing_ref", "an external review reference is required")
+	}
+	if ext.Rating < 1 || ext.Rating > 5 {
+		return Validation("invalid_rating", "rating must be between 1 and 5")
+	}
+
+	existing, err := s.reviewRepo.GetBySourceRef(account.RestaurantID, account.Platform, ext.Ref)
+	if err == nil {
+		existing.Author = ext.Author
+		existing.Rating = ext.Rating
+		existing.Comment = ext.Comment
+		s.analyze(existing)
+		return s.reviewRepo.Update(existing)
+	}
+
+	review := &models.Review{
+		RestaurantID: account.RestaurantID,
+		Source:       account.Platform,
+		ExternalRef:  ext.Ref,
+		Author:       ext.Author,
+		Rating:       ext.Rating,
+		Comment:      ext.Comment,
+		PostedAt:     ext.PostedAt.UTC(),
+	}
+	s.analyze(review)
+	return s.reviewRepo.Create(review)
+}
+
+// Start runs the daily pull until the context is cancelled.
+func (s *ReviewService) Start(ctx context.Context) {
+	run := func(now time.Time) {
+		if n, err := s.RunOnce(now); err != nil {
+			log.Printf("reviews: daily pull: %v", err)
+		} else if n > 0 {
+			log.Printf("reviews: imported %d review(s)", n)
+		}
+	}
+	now := time.Now().UTC()
+	run(now)
+	lastDay := now.Format("2006-01-02")
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now().UTC()
+			if day := now.Format("2006-01-02"); day != lastDay {
+				run(now)
+				lastDay = day
+			}
+		}
+	}
+}
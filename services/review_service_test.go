@@ -0,0 +1,151 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/reviews"
+	"github.com/marc0cl/wets-ma-bu-api/sentiment"
+)
+
+// fakeReviewProvider serves a scripted pull.
+type fakeReviewProvider struct {
+	fetched []reviews.ExternalReview
+}
+
+func (f *fakeReviewProvider) FetchReviews(platform, apiKey, externalID string, since time.Time) ([]reviews.ExternalReview, error) {
+	return f.fetched, nil
+}
+
+func TestReviewsAggregateAcrossSources(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Rev", Email: "rev@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Estrellas", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	provider := &fakeReviewProvider{}
+	svc := NewReviewService(repositories.NewReviewRepository(db), repositories.NewReviewAccountRepository(db), repositories.NewRestaurantRepository(db), provider, sentiment.KeywordAnalyzer{})
+
+	if _, err := svc.AddReview(restaurant.ID, "Vecino", 5, "Impecable"); err != nil {
+		t.Fatalf("native review: %v", err)
+	}
+	if _, err := svc.AddReview(restaurant.ID, "Vecina", 3, ""); err != nil {
+		t.Fatalf("second native review: %v", err)
+	}
+	if _, err := svc.AddReview(restaurant.ID, "", 4, "sin nombre"); err == nil {
+		t.Fatal("expected an anonymous review to be rejected")
+	}
+	if _, err := svc.AddReview(restaurant.ID, "Seis", 6, ""); err == nil {
+		t.Fatal("expected an out-of-range rating to be rejected")
+	}
+
+	if _, err := svc.Connect(restaurant.ID, models.ReviewPlatformGoogle, "secret", "place-1"); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if _, err := svc.Connect(restaurant.ID, "tripadvisor", "k", "v"); err == nil {
+		t.Fatal("expected an unknown platform to be rejected")
+	}
+
+	provider.fetched = []reviews.ExternalReview{
+		{Ref: "g-1", Author: "Turista", Rating: 4, Comment: "Rico", PostedAt: time.Now().UTC().Add(-24 * time.Hour)},
+		{Ref: "g-2", Author: "Viajero", Rating: 2, Comment: "Lento", PostedAt: time.Now().UTC().Add(-48 * time.Hour)},
+	}
+	synced, err := svc.SyncRestaurant(restaurant.ID, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if synced != 2 {
+		t.Fatalf("expected two imported reviews, got %d", synced)
+	}
+
+	// An edited review re-imports as an update, not a duplicate.
+	provider.fetched = provider.fetched[:1]
+	provider.fetched[0].Rating = 5
+	if _, err := svc.SyncRestaurant(restaurant.ID, time.Now().UTC()); err != nil {
+		t.Fatalf("resync: %v", err)
+	}
+	all, err := svc.Reviews(restaurant.ID)
+	if err != nil {
+		t.Fatalf("list reviews: %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("expected four reviews in total, got %d", len(all))
+	}
+
+	summary, err := svc.Ratings(restaurant.ID)
+	if err != nil {
+		t.Fatalf("ratings: %v", err)
+	}
+	// 5+3 native and 5+2 from google: fifteen stars over four reviews.
+	if summary.Count != 4 || summary.Average != 3.75 {
+		t.Fatalf("expected an overall 3.75 over four reviews, got %+v", summary)
+	}
+	if len(summary.Sources) != 2 {
+		t.Fatalf("expected a two-source breakdown, got %+v", summary.Sources)
+	}
+	if summary.Sources[0].Source != models.ReviewPlatformGoogle || summary.Sources[0].Average != 3.5 {
+		t.Fatalf("expected google at 3.5, got %+v", summary.Sources[0])
+	}
+	if summary.Sources[1].Source != models.ReviewSourceNative || summary.Sources[1].Average != 4 {
+		t.Fatalf("expected native at 4.0, got %+v", summary.Sources[1])
+	}
+}
+
+func TestReviewTrendsSurfaceTopicsAndSentiment(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Tre", Email: "tre@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Tendencias", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewReviewService(repositories.NewReviewRepository(db), repositories.NewReviewAccountRepository(db), repositories.NewRestaurantRepository(db), &fakeReviewProvider{}, sentiment.KeywordAnalyzer{})
+
+	posted, err := svc.AddReview(restaurant.ID, "Ana", 5, "La comida estaba rica")
+	if err != nil {
+		t.Fatalf("positive review: %v", err)
+	}
+	if posted.Sentiment != sentiment.Positive || posted.Topics != sentiment.TopicFood {
+		t.Fatalf("expected a positive food review, got %+v", posted)
+	}
+	if _, err := svc.AddReview(restaurant.ID, "Beto", 2, "Servicio lento y caro"); err != nil {
+		t.Fatalf("negative review: %v", err)
+	}
+	if _, err := svc.AddReview(restaurant.ID, "Caro", 1, "El plato llego frio"); err != nil {
+		t.Fatalf("second negative review: %v", err)
+	}
+
+	now := time.Now().UTC()
+	trends, err := svc.Trends(restaurant.ID, now.AddDate(0, -1, 0), now.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("trends: %v", err)
+	}
+	if trends.Count != 3 || trends.Sentiments[sentiment.Positive] != 1 || trends.Sentiments[sentiment.Negative] != 2 {
+		t.Fatalf("expected one positive and two negative, got %+v", trends)
+	}
+	if len(trends.Topics) == 0 || trends.Topics[0].Topic != sentiment.TopicFood {
+		t.Fatalf("expected food to lead the complaints, got %+v", trends.Topics)
+	}
+	if trends.Topics[0].NegativeCount != 1 || trends.Topics[0].Count != 2 {
+		t.Fatalf("expected two food mentions with one negative, got %+v", trends.Topics[0])
+	}
+}
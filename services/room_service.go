@@ -0,0 +1,185 @@
+package services
+
+import (
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// RoomService implements private dining rooms: a bookable resource with
+// its own hours and minimum-spend rules, separate from the table
+// reservation calendar.
+type RoomService struct {
+	roomRepo       RoomRepo
+	restaurantRepo RestaurantRepo
+}
+
+// NewRoomService builds a RoomService.
+func NewRoomService(roomRepo RoomRepo, restaurantRepo RestaurantRepo) *RoomService {
+	return &RoomService{roomRepo: roomRepo, restaurantRepo: restaurantRepo}
+}
+
+// CreateRoom adds a private room to a restaurant.
+func (s *RoomService) CreateRoom(restaurantID uint, name string, capacity int, minSpend float64, opensAt, closesAt string) (*models.PrivateRoom, error) {
+	if name == "" {
+		return nil, Validation("missing_name", "a room name is required")
+	}
+	if capacity <= 0 {
+		return nil, Validation("invalid_capacity", "capacity must be positive")
+	}
+	if minSpend < 0 {
+		return nil, Validation("invalid_min_spend", "min_spend cannot be negative")
+	}
+	if _, err := time.Parse("15:04", opensAt); err != nil {
+		return nil, Validation("invalid_time", "opens_at must be HH:MM")
+	}
+	if _, err := time.Parse("15:04", closesAt); err != nil {
+		return nil, Validation("invalid_time", "closes_at must be HH:MM")
+	}
+
+	room := &models.PrivateRoom{
+		RestaurantID: restaurantID,
+		Name:         utils.SanitizeText(name),
+		Capacity:     capacity,
+		MinSpend:     minSpend,
+		OpensAt:      opensAt,
+		ClosesAt:     closesAt,
+	}
+	if err := s.roomRepo.CreateRoom(room); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// GetRoomByID returns the room with the given id.
+func (s *RoomService) GetRoomByID(id uint) (*models.PrivateRoom, error) {
+	room, err := s.roomRepo.GetRoomByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "room_not_found", "private room not found")
+	}
+	return room, nil
+}
+
+// GetRoomsByRestaurantID returns a restaurant's private rooms.
+func (s *RoomService) GetRoomsByRestaurantID(restaurantID uint) ([]models.PrivateRoom, error) {
+	return s.roomRepo.GetRoomsByRestaurantID(restaurantID)
+}
+
+// DeleteRoom removes a private room. Existing bookings keep their
+// snapshot of the room's terms.
+func (s *RoomService) DeleteRoom(id uint) error {
+	return s.roomRepo.DeleteRoom(id)
+}
+
+// BookRoom books a private room for a time window. The window must fall
+// inside the room's own opening hours (in the restaurant's timezone)
+// and not overlap another non-cancelled booking; the party must fit the
+// room. The room's minimum spend is snapshotted onto the booking.
+func (s *RoomService) BookRoom(roomID, customerID uint, customerName, customerEmail string, partySize int, startsAt, endsAt time.Time, notes string) (*models.RoomBooking, error) {
+	room, err := s.roomRepo.GetRoomByID(roomID)
+	if err != nil {
+		return nil, fromRepo(err, "room_not_found", "private room not found")
+	}
+	if customerName == "" || partySize <= 0 {
+		return nil, Validation("invalid_booking", "a customer name and positive party size are required")
+	}
+	if partySize > room.Capacity {
+		return nil, Validation("party_too_large", "the party does not fit this room")
+	}
+	if !endsAt.After(startsAt) {
+		return nil, Validation("invalid_window", "the booking must end after it starts")
+	}
+
+	restaurant, err := s.restaurantRepo.GetByID(room.RestaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	if !s.withinRoomHours(room, restaurant, startsAt, endsAt) {
+		return nil, Validation("outside_room_hours", "the booking falls outside the room's hours")
+	}
+
+	overlapping, err := s.roomRepo.GetBookingsByRoomAndRange(roomID, startsAt.UTC(), endsAt.UTC())
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range overlapping {
+		if other.Status != models.ReservationCancelled {
+			return nil, Conflict("room_unavailable", "the room is already booked for that time")
+		}
+	}
+
+	booking := &models.RoomBooking{
+		RoomID:        room.ID,
+		RestaurantID:  room.RestaurantID,
+		CustomerID:    customerID,
+		CustomerName:  utils.SanitizeText(customerName),
+		CustomerEmail: customerEmail,
+		PartySize:     partySize,
+		StartsAt:      startsAt.UTC(),
+		EndsAt:        endsAt.UTC(),
+		Status:        models.ReservationPending,
+		MinSpend:      room.MinSpend,
+		Notes:         utils.SanitizeText(notes),
+	}
+	if err := s.roomRepo.CreateBooking(booking); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// withinRoomHours reports whether the window lies inside the room's
+// opening hours on a single local day.
+func (s *RoomService) withinRoomHours(room *models.PrivateRoom, restaurant *models.Restaurant, startsAt, endsAt time.Time) bool {
+	opens, err := time.Parse("15:04", room.OpensAt)
+	if err != nil {
+		return false
+	}
+	closes, err := time.Parse("15:04", room.ClosesAt)
+	if err != nil {
+		return false
+	}
+	loc := restaurant.Location()
+	start := startsAt.In(loc)
+	end := endsAt.In(loc)
+	if start.Year() != end.Year() || start.YearDay() != end.YearDay() {
+		return false
+	}
+	startMin := start.Hour()*60 + start.Minute()
+	endMin := end.Hour()*60 + end.Minute()
+	return startMin >= opens.Hour()*60+opens.Minute() && endMin <= closes.Hour()*60+closes.Minute()
+}
+
+// GetBookingByID returns the booking with the given id.
+func (s *RoomService) GetBookingByID(id uint) (*models.RoomBooking, error) {
+	booking, err := s.roomRepo.GetBookingByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "booking_not_found", "room booking not found")
+	}
+	return booking, nil
+}
+
+// GetBookingsByRestaurantID returns all room bookings of a restaurant,
+// soonest first.
+func (s *RoomService) GetBookingsByRestaurantID(restaurantID uint) ([]models.RoomBooking, error) {
+	return s.roomRepo.GetBookingsByRestaurantID(restaurantID)
+}
+
+// UpdateBookingStatus moves a room booking between pending, confirmed
+// and cancelled.
+func (s *RoomService) UpdateBookingStatus(id uint, status string) (*models.RoomBooking, error) {
+	switch status {
+	case models.ReservationPending, models.ReservationConfirmed, models.ReservationCancelled:
+	default:
+		return nil, Validation("invalid_status", "unknown booking status")
+	}
+	booking, err := s.roomRepo.GetBookingByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "booking_not_found", "room booking not found")
+	}
+	booking.Status = status
+	if err := s.roomRepo.UpdateBooking(booking); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
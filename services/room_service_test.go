@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestBookRoomEnforcesHoursCapacityAndOverlap(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	restaurant := models.Restaurant{Name: "Testaurant", UserID: 1, Timezone: "UTC"}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("creating restaurant: %v", err)
+	}
+
+	svc := NewRoomService(repositories.NewRoomRepository(db), repositories.NewRestaurantRepository(db))
+
+	room, err := svc.CreateRoom(restaurant.ID, "The Cellar", 12, 200000, "18:00", "23:00")
+	if err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+
+	day := time.Date(2026, 9, 10, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+
+	// The party must fit the room.
+	if _, err := svc.BookRoom(room.ID, 0, "Ana", "ana@example.com", 20, at(19), at(22), ""); err == nil {
+		t.Fatal("expected an oversized party to be rejected")
+	}
+	// The window must fall inside the room's hours.
+	if _, err := svc.BookRoom(room.ID, 0, "Ana", "ana@example.com", 10, at(16), at(19), ""); err == nil {
+		t.Fatal("expected a booking outside room hours to be rejected")
+	}
+
+	booking, err := svc.BookRoom(room.ID, 0, "Ana", "ana@example.com", 10, at(19), at(22), "birthday dinner")
+	if err != nil {
+		t.Fatalf("BookRoom: %v", err)
+	}
+	if booking.MinSpend != 200000 {
+		t.Fatalf("expected the room's minimum spend on the booking, got %.2f", booking.MinSpend)
+	}
+
+	// An overlapping window is rejected until the first booking is
+	// cancelled.
+	if _, err := svc.BookRoom(room.ID, 0, "Ben", "ben@example.com", 6, at(21), at(23), ""); err == nil {
+		t.Fatal("expected an overlapping booking to be rejected")
+	}
+	if _, err := svc.UpdateBookingStatus(booking.ID, models.ReservationCancelled); err != nil {
+		t.Fatalf("UpdateBookingStatus: %v", err)
+	}
+	if _, err := svc.BookRoom(room.ID, 0, "Ben", "ben@example.com", 6, at(21), at(23), ""); err != nil {
+		t.Fatalf("BookRoom after cancellation: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+// RoyaltyService computes what franchised locations owe their group:
+// a per-location percentage of net sales over a reporting period.
+type RoyaltyService struct {
+	royaltyRepo    RoyaltyRepo
+	restaurantRepo RestaurantRepo
+}
+
+// NewRoyaltyService builds a RoyaltyService.
+func NewRoyaltyService(royaltyRepo RoyaltyRepo, restaurantRepo RestaurantRepo) *RoyaltyService {
+	return &RoyaltyService{royaltyRepo: royaltyRepo, restaurantRepo: restaurantRepo}
+}
+
+// SetRule sets the royalty percentage of a location.
+func (s *RoyaltyService) SetRule(restaurantID uint, pct float64) (*models.RoyaltyRule, error) {
+	if pct < 0 || pct > 100 {
+		return nil, Validation("invalid_pct", "pct must be between 0 and 100")
+	}
+	rule, err := s.royaltyRepo.GetRule(restaurantID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		rule = &models.RoyaltyRule{RestaurantID: restaurantID}
+	} else if err != nil {
+		return nil, err
+	}
+	rule.Pct = pct
+	if err := s.royaltyRepo.SaveRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Rule returns the royalty rule of a location.
+func (s *RoyaltyService) Rule(restaurantID uint) (*models.RoyaltyRule, error) {
+	rule, err := s.royaltyRepo.GetRule(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "royalty_rule_not_found", "no royalty rule configured")
+	}
+	return rule, nil
+}
+
+// RoyaltyRow is one location on a royalty report.
+type RoyaltyRow struct {
+	RestaurantID uint    `json:"restaurant_id"`
+	Name         string  `json:"name"`
+	NetSales     float64 `json:"net_sales"`
+	Pct          float64 `json:"pct"`
+	Owed         float64 `json:"owed"`
+}
+
+// Report computes the royalties owed by each of the owner's franchised
+// locations for [from, to). Locations without a royalty rule are
+// company-owned and skipped.
+func (s *RoyaltyService) Report(ownerID uint, from, to time.Time) ([]RoyaltyRow, error) {
+	if !from.Before(to) {
+		return nil, Validation("invalid_range", "from must be before to")
+	}
+	restaurants, err := s.restaurantRepo.GetByUserID(ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []RoyaltyRow{}
+	for _, restaurant := range restaurants {
+		rule, err := s.royaltyRepo.GetRule(restaurant.ID)
+		if errors.Is(err, repositories.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sales, err := s.royaltyRepo.NetSales(restaurant.ID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, RoyaltyRow{
+			RestaurantID: restaurant.ID,
+			Name:         restaurant.Name,
+			NetSales:     sales,
+			Pct:          rule.Pct,
+			Owed:         sales * rule.Pct / 100,
+		})
+	}
+	return rows, nil
+}
+
+// ExportReport renders the royalty report as a downloadable file.
+// format is csv or empty for csv.
+func (s *RoyaltyService) ExportReport(ownerID uint, from, to time.Time, format string) ([]byte, string, error) {
+	if format != "" && format != "csv" {
+		return nil, "", Validation("invalid_format", "format must be csv")
+	}
+	rows, err := s.Report(ownerID, from, to)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"restaurant", "net_sales", "pct", "royalty_owed"})
+	for _, row := range rows {
+		w.Write([]string{row.Name, money(row.NetSales), money(row.Pct), money(row.Owed)})
+	}
+	w.Flush()
+
+	stamp := from.Format("20060102") + "-" + to.Format("20060102")
+	return buf.Bytes(), fmt.Sprintf("royalties-%s.csv", stamp), nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestRoyaltyReportComputesPctOfNetSales(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	owner := models.User{Name: "Ana", Email: "ana@example.com", Role: models.RoleOwner}
+	if err := db.Create(&owner).Error; err != nil {
+		t.Fatalf("creating owner: %v", err)
+	}
+	franchised := models.Restaurant{Name: "Franchised", UserID: owner.ID}
+	companyOwned := models.Restaurant{Name: "Company Owned", UserID: owner.ID}
+	for _, r := range []*models.Restaurant{&franchised, &companyOwned} {
+		if err := db.Create(r).Error; err != nil {
+			t.Fatalf("creating restaurant: %v", err)
+		}
+	}
+
+	// 100000 in completed sales, 20000 cancelled (ignored), 10000
+	// refunded: net sales of 90000.
+	orders := []models.Order{
+		{RestaurantID: franchised.ID, CustomerName: "Ben", Total: 60000, Status: models.OrderCompleted},
+		{RestaurantID: franchised.ID, CustomerName: "Ben", Total: 40000, Status: models.OrderCompleted},
+		{RestaurantID: franchised.ID, CustomerName: "Ben", Total: 20000, Status: models.OrderCancelled},
+		{RestaurantID: companyOwned.ID, CustomerName: "Ben", Total: 99999, Status: models.OrderCompleted},
+	}
+	for i := range orders {
+		if err := db.Create(&orders[i]).Error; err != nil {
+			t.Fatalf("creating order: %v", err)
+		}
+	}
+	refund := models.Refund{OrderID: orders[0].ID, Amount: 10000, Reason: models.RefundReasonCustomer}
+	if err := db.Create(&refund).Error; err != nil {
+		t.Fatalf("creating refund: %v", err)
+	}
+
+	svc := NewRoyaltyService(repositories.NewRoyaltyRepository(db), repositories.NewRestaurantRepository(db))
+
+	if _, err := svc.SetRule(franchised.ID, 6); err != nil {
+		t.Fatalf("SetRule: %v", err)
+	}
+	if _, err := svc.SetRule(franchised.ID, 110); err == nil {
+		t.Fatal("expected an out-of-range percentage to be rejected")
+	}
+
+	from := time.Now().UTC().AddDate(0, 0, -1)
+	to := time.Now().UTC().AddDate(0, 0, 1)
+	rows, err := svc.Report(owner.ID, from, to)
+	if err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	// Only the franchised location has a rule.
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 location on the report, got %d: %+v", len(rows), rows)
+	}
+	if rows[0].NetSales != 90000 || rows[0].Owed != 5400 {
+		t.Fatalf("expected net sales 90000 owing 5400, got %.2f owing %.2f", rows[0].NetSales, rows[0].Owed)
+	}
+
+	body, filename, err := svc.ExportReport(owner.ID, from, to, "csv")
+	if err != nil {
+		t.Fatalf("ExportReport: %v", err)
+	}
+	if !strings.HasPrefix(filename, "royalties-") {
+		t.Fatalf("unexpected filename %s", filename)
+	}
+	if !strings.Contains(string(body), "Franchised,90000.00,6.00,5400.00") {
+		t.Fatalf("unexpected CSV body:\n%s", body)
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"log"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// SearchIndex is the full-text index surface used for searching and kept
+// in sync by the restaurant and menu services. The searchindex package
+// provides the Elasticsearch-backed implementation.
+type SearchIndex interface {
+	IndexRestaurant(r *models.Restaurant) error
+	RemoveRestaurant(id uint) error
+	IndexMenuItem(item *models.MenuItem) error
+	RemoveMenuItem(id uint) error
+	SearchIDs(kind, q string, limit int) ([]uint, error)
+}
+
+// SearchService implements cross-entity search.
+type SearchService struct {
+	restaurantRepo RestaurantRepo
+	menuRepo       MenuRepo
+	userRepo       UserRepo
+	index          SearchIndex // nil means database search only
+}
+
+// NewSearchService builds a SearchService.
+func NewSearchService(restaurantRepo RestaurantRepo, menuRepo MenuRepo, userRepo UserRepo, index SearchIndex) *SearchService {
+	return &SearchService{restaurantRepo: restaurantRepo, menuRepo: menuRepo, userRepo: userRepo, index: index}
+}
+
+// SearchResults groups matches by entity type. Users is only populated
+// for admin callers.
+type SearchResults struct {
+	Restaurants []models.Restaurant `json:"restaurants"`
+	MenuItems   []models.MenuItem   `json:"menu_items"`
+	Users       []models.User       `json:"users,omitempty"`
+}
+
+// Search runs q against restaurants, menu items and (for admins) users.
+// When a full-text index is configured it is tried first; a failing or
+// unreachable cluster degrades to the LIKE-based database search rather
+// than breaking the endpoint. Users are never indexed and always come
+// from the database.
+func (s *SearchService) Search(q string, includeUsers bool, limit int) (*SearchResults, error) {
+	results := &SearchResults{}
+
+	var err error
+	if s.index != nil {
+		results.Restaurants, results.MenuItems, err = s.searchIndexed(q, limit)
+		if err != nil {
+			log.Printf("search: index query failed, falling back to database: %v", err)
+		}
+	}
+	if s.index == nil || err != nil {
+		if results.Restaurants, err = s.restaurantRepo.Search(q, limit); err != nil {
+			return nil, err
+		}
+		if results.MenuItems, err = s.menuRepo.Search(q, limit); err != nil {
+			return nil, err
+		}
+	}
+
+	if includeUsers {
+		if results.Users, err = s.userRepo.Search(q, limit); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// searchIndexed resolves index hits back to database rows, keeping the
+// index's relevance order. Rows deleted since indexing are skipped.
+func (s *SearchService) searchIndexed(q string, limit int) ([]models.Restaurant, []models.MenuItem, error) {
+	restaurantIDs, err := s.index.SearchIDs("restaurant", q, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	itemIDs, err := s.index.SearchIDs("menu_item", q, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	restaurants := make([]models.Restaurant, 0, len(restaurantIDs))
+	for _, id := range restaurantIDs {
+		if r, err := s.restaurantRepo.GetByID(id); err == nil {
+			restaurants = append(restaurants, *r)
+		}
+	}
+	items := make([]models.MenuItem, 0, len(itemIDs))
+	for _, id := range itemIDs {
+		if item, err := s.menuRepo.GetByID(id); err == nil {
+			items = append(items, *item)
+		}
+	}
+	return restaurants, items, nil
+}
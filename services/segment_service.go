@@ -0,0 +1,148 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// defaultSegmentWindowDays is the look-back used when a segment does not
+// set its own window.
+const defaultSegmentWindowDays = 90
+
+// SegmentService implements customer segmentation for marketing.
+// Segments are rules, not snapshots: membership is evaluated against
+// order history at read time, so a list is always current.
+type SegmentService struct {
+	segmentRepo SegmentRepo
+}
+
+// NewSegmentService builds a SegmentService.
+func NewSegmentService(segmentRepo SegmentRepo) *SegmentService {
+	return &SegmentService{segmentRepo: segmentRepo}
+}
+
+// CreateSegment creates a segment. At least one of minOrders and
+// minSpend must be set, or the segment would match every customer.
+func (s *SegmentService) CreateSegment(restaurantID uint, name string, minOrders int, minSpend float64, windowDays int) (*models.Segment, error) {
+	if name == "" {
+		return nil, Validation("name_required", "segment name is required")
+	}
+	if minOrders < 0 || minSpend < 0 || windowDays < 0 {
+		return nil, Validation("invalid_segment", "segment criteria cannot be negative")
+	}
+	if minOrders == 0 && minSpend == 0 {
+		return nil, Validation("segment_needs_criteria", "set a minimum order count or spend")
+	}
+	if windowDays == 0 {
+		windowDays = defaultSegmentWindowDays
+	}
+	segment := &models.Segment{
+		RestaurantID: restaurantID,
+		Name:         utils.SanitizeText(name),
+		MinOrders:    minOrders,
+		MinSpend:     minSpend,
+		WindowDays:   windowDays,
+	}
+	if err := s.segmentRepo.Create(segment); err != nil {
+		return nil, err
+	}
+	return segment, nil
+}
+
+// GetSegmentByID returns the segment with the given id.
+func (s *SegmentService) GetSegmentByID(id uint) (*models.Segment, error) {
+	segment, err := s.segmentRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "segment_not_found", "segment not found")
+	}
+	return segment, nil
+}
+
+// Segments returns a restaurant's segments.
+func (s *SegmentService) Segments(restaurantID uint) ([]models.Segment, error) {
+	return s.segmentRepo.GetByRestaurantID(restaurantID)
+}
+
+// DeleteSegment removes a segment. Member data is untouched; it only
+// ever existed as a query.
+func (s *SegmentService) DeleteSegment(id uint) error {
+	if _, err := s.segmentRepo.GetByID(id); err != nil {
+		return fromRepo(err, "segment_not_found", "segment not found")
+	}
+	return s.segmentRepo.Delete(id)
+}
+
+// SegmentMember is one customer matching a segment's rules. LastOrderAt
+// is a "2006-01-02" day.
+type SegmentMember struct {
+	Name        string  `json:"name"`
+	Email       string  `json:"email"`
+	Orders      int64   `json:"orders"`
+	Spend       float64 `json:"spend"`
+	LastOrderAt string  `json:"last_order_at"`
+}
+
+// Members evaluates a segment against order history, biggest spenders
+// first.
+func (s *SegmentService) Members(id uint) ([]SegmentMember, error) {
+	segment, err := s.segmentRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "segment_not_found", "segment not found")
+	}
+	since := time.Now().UTC().AddDate(0, 0, -segment.WindowDays)
+	rows, err := s.segmentRepo.CustomerStats(segment.RestaurantID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]SegmentMember, 0, len(rows))
+	for _, row := range rows {
+		if segment.MinOrders > 0 && row.Orders < int64(segment.MinOrders) {
+			continue
+		}
+		if segment.MinSpend > 0 && row.Spend < segment.MinSpend {
+			continue
+		}
+		members = append(members, SegmentMember{
+			Name:        row.CustomerName,
+			Email:       row.CustomerEmail,
+			Orders:      row.Orders,
+			Spend:       row.Spend,
+			LastOrderAt: row.LastOrderAt,
+		})
+	}
+	return members, nil
+}
+
+// ExportMembers renders a segment's members as CSV for campaign tools.
+func (s *SegmentService) ExportMembers(id uint) ([]byte, string, error) {
+	segment, err := s.segmentRepo.GetByID(id)
+	if err != nil {
+		return nil, "", fromRepo(err, "segment_not_found", "segment not found")
+	}
+	members, err := s.Members(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"name", "email", "orders", "total_spend", "last_order_at"})
+	for _, m := range members {
+		w.Write([]string{
+			m.Name,
+			m.Email,
+			strconv.FormatInt(m.Orders, 10),
+			money(m.Spend),
+			m.LastOrderAt,
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), fmt.Sprintf("segment-%d.csv", segment.ID), nil
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestMembersApplySegmentCriteria(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	// A regular: four orders. A one-off big spender. A cancelled order
+	// and an emailless guest that must both be ignored.
+	orders := []models.Order{
+		{RestaurantID: 1, CustomerName: "Ana", CustomerEmail: "ana@example.com", Total: 9000},
+		{RestaurantID: 1, CustomerName: "Ana", CustomerEmail: "ana@example.com", Total: 7000},
+		{RestaurantID: 1, CustomerName: "Ana", CustomerEmail: "ana@example.com", Total: 8000},
+		{RestaurantID: 1, CustomerName: "Ana", CustomerEmail: "ana@example.com", Total: 6000},
+		{RestaurantID: 1, CustomerName: "Ben", CustomerEmail: "ben@example.com", Total: 90000},
+		{RestaurantID: 1, CustomerName: "Cy", CustomerEmail: "cy@example.com", Total: 50000, Status: models.OrderCancelled},
+		{RestaurantID: 1, CustomerName: "Guest", Total: 40000},
+	}
+	for i := range orders {
+		if err := db.Create(&orders[i]).Error; err != nil {
+			t.Fatalf("creating order: %v", err)
+		}
+	}
+
+	svc := NewSegmentService(repositories.NewSegmentRepository(db))
+
+	regulars, err := svc.CreateSegment(1, "regulars", 4, 0, 90)
+	if err != nil {
+		t.Fatalf("CreateSegment: %v", err)
+	}
+	members, err := svc.Members(regulars.ID)
+	if err != nil {
+		t.Fatalf("Members: %v", err)
+	}
+	if len(members) != 1 || members[0].Email != "ana@example.com" || members[0].Orders != 4 {
+		t.Fatalf("unexpected regulars: %+v", members)
+	}
+
+	spenders, err := svc.CreateSegment(1, "big spenders", 0, 50000, 90)
+	if err != nil {
+		t.Fatalf("CreateSegment: %v", err)
+	}
+	members, err = svc.Members(spenders.ID)
+	if err != nil {
+		t.Fatalf("Members: %v", err)
+	}
+	if len(members) != 1 || members[0].Email != "ben@example.com" {
+		t.Fatalf("unexpected big spenders: %+v", members)
+	}
+
+	body, filename, err := svc.ExportMembers(spenders.ID)
+	if err != nil {
+		t.Fatalf("ExportMembers: %v", err)
+	}
+	if !strings.HasSuffix(filename, ".csv") {
+		t.Fatalf("unexpected filename %q", filename)
+	}
+	if !strings.Contains(string(body), "Ben,ben@example.com,1,90000.00") {
+		t.Fatalf("unexpected export:\n%s", body)
+	}
+}
+
+func TestCreateSegmentRequiresCriteria(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+	svc := NewSegmentService(repositories.NewSegmentRepository(db))
+
+	if _, err := svc.CreateSegment(1, "everyone", 0, 0, 90); err == nil {
+		t.Fatal("expected a segment without criteria to be rejected")
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// overtimeWeeklyHours is the weekly threshold beyond which worked hours
+// pay the overtime rate (the Chilean legal workweek).
+const overtimeWeeklyHours = 45.0
+
+// overtimeMultiplier is the legal surcharge on overtime hours.
+const overtimeMultiplier = 1.5
+
+// StaffService manages a restaurant's employees and shifts and exports
+// payroll data.
+type StaffService struct {
+	staffRepo StaffRepo
+}
+
+// NewStaffService builds a StaffService.
+func NewStaffService(staffRepo StaffRepo) *StaffService {
+	return &StaffService{staffRepo: staffRepo}
+}
+
+// CreateEmployee adds an employee to a restaurant's payroll.
+func (s *StaffService) CreateEmployee(restaurantID uint, name, email, payrollRef string, hourlyRate float64) (*models.Employee, error) {
+	if name == "" {
+		return nil, Validation("name_required", "employee name is required")
+	}
+	if hourlyRate < 0 {
+		return nil, Validation("invalid_rate", "hourly rate cannot be negative")
+	}
+	employee := &models.Employee{
+		RestaurantID: restaurantID,
+		Name:         utils.SanitizeText(name),
+		Email:        utils.NormalizeEmail(email),
+		PayrollRef:   payrollRef,
+		HourlyRate:   hourlyRate,
+	}
+	if err := s.staffRepo.CreateEmployee(employee); err != nil {
+		return nil, err
+	}
+	return employee, nil
+}
+
+// GetEmployeeByID returns one employee.
+func (s *StaffService) GetEmployeeByID(id uint) (*models.Employee, error) {
+	employee, err := s.staffRepo.GetEmployeeByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "employee_not_found", "employee not found")
+	}
+	return employee, nil
+}
+
+// Employees returns a restaurant's employees.
+func (s *StaffService) Employees(restaurantID uint) ([]models.Employee, error) {
+	return s.staffRepo.GetEmployeesByRestaurantID(restaurantID)
+}
+
+// CreateShift schedules a block of work for an employee.
+func (s *StaffService) CreateShift(restaurantID, employeeID uint, startsAt, endsAt time.Time) (*models.Shift, error) {
+	if !startsAt.Before(endsAt) {
+		return nil, Validation("invalid_shift", "shift must end after it starts")
+	}
+	employee, err := s.staffRepo.GetEmployeeByID(employeeID)
+	if err != nil {
+		return nil, fromRepo(err, "employee_not_found", "employee not found")
+	}
+	if employee.RestaurantID != restaurantID {
+		return nil, NotFound("employee_not_found", "employee not found")
+	}
+	shift := &models.Shift{
+		RestaurantID: restaurantID,
+		EmployeeID:   employeeID,
+		StartsAt:     startsAt.UTC(),
+		EndsAt:       endsAt.UTC(),
+	}
+	if err := s.staffRepo.CreateShift(shift); err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+// GetShiftByID returns one shift.
+func (s *StaffService) GetShiftByID(id uint) (*models.Shift, error) {
+	shift, err := s.staffRepo.GetShiftByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "shift_not_found", "shift not found")
+	}
+	return shift, nil
+}
+
+// Shifts returns a restaurant's shifts overlapping [from, to).
+func (s *StaffService) Shifts(restaurantID uint, from, to time.Time) ([]models.Shift, error) {
+	return s.staffRepo.GetShiftsByRange(restaurantID, from, to)
+}
+
+// PayrollExport renders worked hours per employee between from
+// (inclusive) and to (exclusive) as CSV for external payroll providers.
+// Hours beyond the weekly threshold count as overtime at the legal
+// surcharge; weeks run Monday to Sunday.
+func (s *StaffService) PayrollExport(restaurantID uint, from, to time.Time) ([]byte, string, error) {
+	if !from.Before(to) {
+		return nil, "", Validation("invalid_range", "from must be before to")
+	}
+	employees, err := s.staffRepo.GetEmployeesByRestaurantID(restaurantID)
+	if err != nil {
+		return nil, "", err
+	}
+	shifts, err := s.staffRepo.GetShiftsByRange(restaurantID, from, to)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Hours per employee per ISO week, clipped to the period. A shift
+	// counts toward the week it starts in.
+	weekly := make(map[uint]map[string]float64)
+	for _, shift := range shifts {
+		start, end := shift.StartsAt, shift.EndsAt
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		year, week := start.ISOWeek()
+		key := fmt.Sprintf("%d-W%02d", year, week)
+		if weekly[shift.EmployeeID] == nil {
+			weekly[shift.EmployeeID] = make(map[string]float64)
+		}
+		weekly[shift.EmployeeID][key] += end.Sub(start).Hours()
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"employee_id", "name", "email", "payroll_ref", "hourly_rate",
+		"hours", "overtime_hours", "regular_pay", "overtime_pay", "total_pay"})
+	sort.Slice(employees, func(i, j int) bool { return employees[i].ID < employees[j].ID })
+	for _, employee := range employees {
+		var regular, overtime float64
+		for _, hours := range weekly[employee.ID] {
+			if hours > overtimeWeeklyHours {
+				regular += overtimeWeeklyHours
+				overtime += hours - overtimeWeeklyHours
+			} else {
+				regular += hours
+			}
+		}
+		if regular == 0 && overtime == 0 {
+			continue
+		}
+		regularPay := regular * employee.HourlyRate
+		overtimePay := overtime * employee.HourlyRate * overtimeMultiplier
+		w.Write([]string{
+			fmt.Sprint(employee.ID), employee.Name, employee.Email, employee.PayrollRef,
+			money(employee.HourlyRate),
+			money(regular + overtime), money(overtime),
+			money(regularPay), money(overtimePay), money(regularPay + overtimePay),
+		})
+	}
+	w.Flush()
+
+	filename := fmt.Sprintf("payroll-%s-%s.csv", from.Format("20060102"), to.Format("20060102"))
+	return buf.Bytes(), filename, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestPayrollExportAppliesWeeklyOvertime(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+	svc := NewStaffService(repositories.NewStaffRepository(db))
+
+	employee, err := svc.CreateEmployee(1, "Valentina", "vale@example.com", "EMP-7", 4000)
+	if err != nil {
+		t.Fatalf("CreateEmployee: %v", err)
+	}
+
+	// Five 10-hour shifts in one week: 45 regular hours and 5 overtime.
+	monday := time.Date(2026, 8, 3, 10, 0, 0, 0, time.UTC)
+	for day := 0; day < 5; day++ {
+		start := monday.AddDate(0, 0, day)
+		if _, err := svc.CreateShift(1, employee.ID, start, start.Add(10*time.Hour)); err != nil {
+			t.Fatalf("CreateShift: %v", err)
+		}
+	}
+
+	body, _, err := svc.PayrollExport(1, monday.AddDate(0, 0, -1), monday.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("PayrollExport: %v", err)
+	}
+
+	out := string(body)
+	// 45h at 4000 plus 5h at 6000.
+	want := "Valentina,vale@example.com,EMP-7,4000.00,50.00,5.00,180000.00,30000.00,210000.00"
+	if !strings.Contains(out, want) {
+		t.Fatalf("export missing %q:\n%s", want, out)
+	}
+}
+
+func TestCreateShiftRejectsForeignEmployee(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+	svc := NewStaffService(repositories.NewStaffRepository(db))
+
+	employee, err := svc.CreateEmployee(1, "Valentina", "", "", 4000)
+	if err != nil {
+		t.Fatalf("CreateEmployee: %v", err)
+	}
+
+	start := time.Now().UTC()
+	if _, err := svc.CreateShift(2, employee.ID, start, start.Add(4*time.Hour)); err == nil {
+		t.Fatal("expected an error scheduling another restaurant's employee")
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// StockService keeps ingredient stock in step with completed orders and
+// takes manual adjustments for everything orders cannot see.
+type StockService struct {
+	inventoryRepo  InventoryRepo
+	restaurantRepo RestaurantRepo
+	userRepo       UserRepo
+	jobs           JobEnqueuer
+}
+
+// NewStockService builds a StockService.
+func NewStockService(inventoryRepo InventoryRepo, restaurantRepo RestaurantRepo, userRepo UserRepo, jobs JobEnqueuer) *StockService {
+	return &StockService{inventoryRepo: inventoryRepo, restaurantRepo: restaurantRepo, userRepo: userRepo, jobs: jobs}
+}
+
+// DepleteForOrder walks a completed order's recipes and depletes each
+// ingredient's stock. Depletion is recorded even when it drives stock
+// negative — the counts should reflect reality — but every ingredient
+// that ends up below zero is flagged to the owner in one alert.
+func (s *StockService) DepleteForOrder(order *models.Order) error {
+	var negative []string
+	for _, orderItem := range order.Items {
+		recipe, err := s.inventoryRepo.GetRecipe(orderItem.MenuItemID)
+		if err != nil {
+			return err
+		}
+		for _, line := range recipe {
+			movement := &models.StockMovement{
+				IngredientID: line.IngredientID,
+				Delta:        -line.Quantity * float64(orderItem.Quantity),
+				Reason:       "order completed",
+				OrderID:      order.ID,
+			}
+			if err := s.inventoryRepo.AdjustStock(movement); err != nil {
+				return err
+			}
+			ingredient, err := s.inventoryRepo.GetIngredientByID(line.IngredientID)
+			if err != nil {
+				return err
+			}
+			if ingredient.Stock < 0 {
+				negative = append(negative, fmt.Sprintf("%s (%.2f %s)", ingredient.Name, ingredient.Stock, ingredient.Unit))
+			}
+		}
+	}
+
+	if len(negative) > 0 {
+		if err := s.alertNegativeStock(order.RestaurantID, negative); err != nil {
+			log.Printf("stock: alerting negative stock for restaurant %d: %v", order.RestaurantID, err)
+		}
+	}
+	return nil
+}
+
+// alertNegativeStock queues one email to the restaurant's owner listing
+// the ingredients that ran below zero.
+func (s *StockService) alertNegativeStock(restaurantID uint, negative []string) error {
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return err
+	}
+	owner, err := s.userRepo.GetByID(restaurant.UserID)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"to":      owner.Email,
+		"subject": fmt.Sprintf("%s: ingredients out of stock", restaurant.Name),
+		"text":    fmt.Sprintf("Stock went negative for: %s. Recount and adjust the inventory.", strings.Join(negative, ", ")),
+	})
+	if err != nil {
+		return err
+	}
+	return s.jobs.Enqueue(&models.Job{Type: "email.send", Payload: string(payload)})
+}
+
+// Adjust applies a manual stock correction and records it with its
+// reason.
+func (s *StockService) Adjust(ingredientID uint, delta float64, reason string) (*models.Ingredient, error) {
+	if delta == 0 {
+		return nil, Validation("invalid_delta", "the adjustment cannot be zero")
+	}
+	reason = utils.SanitizeText(reason)
+	if reason == "" {
+		return nil, Validation("missing_reason", "a reason is required")
+	}
+	if _, err := s.inventoryRepo.GetIngredientByID(ingredientID); err != nil {
+		return nil, fromRepo(err, "ingredient_not_found", "ingredient not found")
+	}
+
+	movement := &models.StockMovement{IngredientID: ingredientID, Delta: delta, Reason: reason}
+	if err := s.inventoryRepo.AdjustStock(movement); err != nil {
+		return nil, err
+	}
+	ingredient, err := s.inventoryRepo.GetIngredientByID(ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	return ingredient, nil
+}
+
+// Movements returns an ingredient's stock history, newest first.
+func (s *StockService) Movements(ingredientID uint) ([]models.StockMovement, error) {
+	return s.inventoryRepo.GetMovementsByIngredientID(ingredientID)
+}
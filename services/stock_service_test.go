@@ -0,0 +1,134 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/cache"
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestCompletedOrderDepletesStockThroughRecipes(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Olga", Email: "olga@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Stocked", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	menuSvc := NewMenuService(repositories.NewMenuRepository(db), cache.NewMemory(), nil, nil)
+	dish, err := menuSvc.CreateMenuItem(restaurant.ID, "Empanada", "", "starters", 2500, 0)
+	if err != nil {
+		t.Fatalf("create menu item: %v", err)
+	}
+
+	inventoryRepo := repositories.NewInventoryRepository(db)
+	recipeSvc := NewRecipeService(inventoryRepo, repositories.NewMenuRepository(db))
+	flour, err := recipeSvc.CreateIngredient(restaurant.ID, "Flour", "kg", 800)
+	if err != nil {
+		t.Fatalf("create ingredient: %v", err)
+	}
+	if _, err := recipeSvc.SetRecipe(dish.ID, []RecipeItemInput{{IngredientID: flour.ID, Quantity: 0.25}}); err != nil {
+		t.Fatalf("set recipe: %v", err)
+	}
+
+	stockSvc := NewStockService(inventoryRepo, repositories.NewRestaurantRepository(db), repositories.NewUserRepository(db), repositories.NewJobRepository(db))
+	if _, err := stockSvc.Adjust(flour.ID, 1, "initial count"); err != nil {
+		t.Fatalf("initial adjustment: %v", err)
+	}
+
+	orderSvc := NewOrderService(
+		repositories.NewOrderRepository(db),
+		repositories.NewMenuRepository(db),
+		repositories.NewRefundRepository(db),
+		repositories.NewTxManager(db),
+		repositories.NewOutboxRepository(db),
+		payments.LogProvider{},
+		stockSvc,
+		nil,
+		nil,
+		nil,
+		19,
+	)
+	order, err := orderSvc.CreateOrder(restaurant.ID, 0, "Pedro", "pedro@example.com", []OrderLine{{MenuItemID: dish.ID, Quantity: 3}}, 0)
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+
+	if _, err := orderSvc.UpdateOrderStatus(order.ID, models.OrderCompleted); err != nil {
+		t.Fatalf("complete order: %v", err)
+	}
+	updated, err := stockSvc.Adjust(flour.ID, 0.75, "delivery")
+	if err != nil {
+		t.Fatalf("adjust after depletion: %v", err)
+	}
+	// 1 - 3*0.25 + 0.75 = 1.
+	if updated.Stock != 1 {
+		t.Fatalf("expected 1kg of flour, got %v", updated.Stock)
+	}
+
+	// Re-completing an already completed order must not deplete twice.
+	if _, err := orderSvc.UpdateOrderStatus(order.ID, models.OrderCompleted); err != nil {
+		t.Fatalf("re-complete order: %v", err)
+	}
+	ingredient, err := stockSvc.inventoryRepo.GetIngredientByID(flour.ID)
+	if err != nil {
+		t.Fatalf("reload ingredient: %v", err)
+	}
+	if ingredient.Stock != 1 {
+		t.Fatalf("expected stock unchanged at 1, got %v", ingredient.Stock)
+	}
+
+	// A second large order drives the stock negative and alerts the owner.
+	big, err := orderSvc.CreateOrder(restaurant.ID, 0, "Pedro", "pedro@example.com", []OrderLine{{MenuItemID: dish.ID, Quantity: 10}}, 0)
+	if err != nil {
+		t.Fatalf("create big order: %v", err)
+	}
+	if _, err := orderSvc.UpdateOrderStatus(big.ID, models.OrderCompleted); err != nil {
+		t.Fatalf("complete big order: %v", err)
+	}
+	var count int64
+	if err := db.Model(&models.Job{}).Where("type = ? AND payload LIKE ?", "email.send", "%out of stock%").Count(&count).Error; err != nil {
+		t.Fatalf("count alert jobs: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected one negative-stock alert, got %d", count)
+	}
+
+	movements, err := stockSvc.Movements(flour.ID)
+	if err != nil {
+		t.Fatalf("movements: %v", err)
+	}
+	if len(movements) != 4 {
+		t.Fatalf("expected four stock movements, got %d", len(movements))
+	}
+}
+
+func TestAdjustValidatesDeltaAndReason(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+	inventoryRepo := repositories.NewInventoryRepository(db)
+	flour := &models.Ingredient{RestaurantID: 1, Name: "Flour", Unit: "kg"}
+	if err := db.Create(flour).Error; err != nil {
+		t.Fatalf("create ingredient: %v", err)
+	}
+
+	svc := NewStockService(inventoryRepo, repositories.NewRestaurantRepository(db), repositories.NewUserRepository(db), repositories.NewJobRepository(db))
+	if _, err := svc.Adjust(flour.ID, 0, "recount"); err == nil {
+		t.Fatal("expected a zero delta to be rejected")
+	}
+	if _, err := svc.Adjust(flour.ID, 1, ""); err == nil {
+		t.Fatal("expected a missing reason to be rejected")
+	}
+}
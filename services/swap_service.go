@@ -0,0 +1,192 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// SwapService handles shift swap and drop requests: staff ask, eligible
+// colleagues hear about it, and a manager's approval updates the
+// schedule.
+type SwapService struct {
+	swapRepo  SwapRepo
+	staffRepo StaffRepo
+	jobs      JobEnqueuer
+}
+
+// NewSwapService builds a SwapService.
+func NewSwapService(swapRepo SwapRepo, staffRepo StaffRepo, jobs JobEnqueuer) *SwapService {
+	return &SwapService{swapRepo: swapRepo, staffRepo: staffRepo, jobs: jobs}
+}
+
+// RequestSwap opens a swap request for a shift. A zero toEmployeeID
+// asks to drop the shift instead of handing it to someone specific.
+func (s *SwapService) RequestSwap(shiftID, toEmployeeID uint, note string) (*models.ShiftSwap, error) {
+	shift, err := s.staffRepo.GetShiftByID(shiftID)
+	if err != nil {
+		return nil, fromRepo(err, "shift_not_found", "shift not found")
+	}
+	if toEmployeeID != 0 {
+		if toEmployeeID == shift.EmployeeID {
+			return nil, Validation("same_employee", "the shift already belongs to that employee")
+		}
+		colleague, err := s.staffRepo.GetEmployeeByID(toEmployeeID)
+		if err != nil {
+			return nil, fromRepo(err, "employee_not_found", "employee not found")
+		}
+		if colleague.RestaurantID != shift.RestaurantID {
+			return nil, Validation("foreign_employee", "the employee works at another restaurant")
+		}
+	}
+
+	swap := &models.ShiftSwap{
+		RestaurantID:   shift.RestaurantID,
+		ShiftID:        shift.ID,
+		FromEmployeeID: shift.EmployeeID,
+		ToEmployeeID:   toEmployeeID,
+		Note:           utils.SanitizeText(note),
+		Status:         models.SwapPending,
+	}
+	if err := s.swapRepo.Create(swap); err != nil {
+		return nil, err
+	}
+
+	if err := s.notifyColleagues(swap, shift); err != nil {
+		log.Printf("swaps: notifying colleagues for swap %d: %v", swap.ID, err)
+	}
+	return swap, nil
+}
+
+// notifyColleagues queues the request's emails: the named colleague for
+// a targeted swap, every other colleague with an email on file for a
+// drop.
+func (s *SwapService) notifyColleagues(swap *models.ShiftSwap, shift *models.Shift) error {
+	from, err := s.staffRepo.GetEmployeeByID(swap.FromEmployeeID)
+	if err != nil {
+		return err
+	}
+
+	var recipients []models.Employee
+	if swap.ToEmployeeID != 0 {
+		colleague, err := s.staffRepo.GetEmployeeByID(swap.ToEmployeeID)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, *colleague)
+	} else {
+		employees, err := s.staffRepo.GetEmployeesByRestaurantID(swap.RestaurantID)
+		if err != nil {
+			return err
+		}
+		for _, employee := range employees {
+			if employee.ID != swap.FromEmployeeID {
+				recipients = append(recipients, employee)
+			}
+		}
+	}
+
+	when := shift.StartsAt.UTC().Format("2006-01-02 15:04")
+	for _, recipient := range recipients {
+		if recipient.Email == "" {
+			continue
+		}
+		payload, err := json.Marshal(map[string]interface{}{
+			"to":      recipient.Email,
+			"subject": fmt.Sprintf("%s wants to give away a shift on %s", from.Name, when),
+			"text":    fmt.Sprintf("%s asked to hand over the shift starting %s. Talk to your manager if you can take it.", from.Name, when),
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.jobs.Enqueue(&models.Job{Type: "email.send", Payload: string(payload)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Swaps returns a restaurant's swap requests, newest first.
+func (s *SwapService) Swaps(restaurantID uint) ([]models.ShiftSwap, error) {
+	return s.swapRepo.GetByRestaurantID(restaurantID)
+}
+
+// GetSwapByID returns one swap request.
+func (s *SwapService) GetSwapByID(id uint) (*models.ShiftSwap, error) {
+	swap, err := s.swapRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "swap_not_found", "swap request not found")
+	}
+	return swap, nil
+}
+
+// Approve applies a pending swap: the shift moves to the named
+// colleague, or comes off the schedule for a drop, and the change is
+// written to the history in the same transaction.
+func (s *SwapService) Approve(id uint) (*models.ShiftSwap, error) {
+	swap, err := s.GetSwapByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if swap.Status != models.SwapPending {
+		return nil, Conflict("already_decided", "the swap request has already been decided")
+	}
+	shift, err := s.staffRepo.GetShiftByID(swap.ShiftID)
+	if err != nil {
+		return nil, fromRepo(err, "shift_not_found", "the shift no longer exists")
+	}
+
+	from, err := s.staffRepo.GetEmployeeByID(swap.FromEmployeeID)
+	if err != nil {
+		return nil, fromRepo(err, "employee_not_found", "employee not found")
+	}
+	when := shift.StartsAt.UTC().Format("2006-01-02 15:04")
+	drop := swap.ToEmployeeID == 0
+	detail := fmt.Sprintf("shift starting %s dropped by %s", when, from.Name)
+	if !drop {
+		to, err := s.staffRepo.GetEmployeeByID(swap.ToEmployeeID)
+		if err != nil {
+			return nil, fromRepo(err, "employee_not_found", "employee not found")
+		}
+		shift.EmployeeID = to.ID
+		detail = fmt.Sprintf("shift starting %s reassigned from %s to %s", when, from.Name, to.Name)
+	}
+
+	now := time.Now().UTC()
+	swap.Status = models.SwapApproved
+	swap.DecidedAt = &now
+	change := &models.ShiftChange{RestaurantID: swap.RestaurantID, ShiftID: shift.ID, Detail: detail}
+	if err := s.swapRepo.Apply(swap, shift, drop, change); err != nil {
+		return nil, err
+	}
+	return swap, nil
+}
+
+// Reject declines a pending swap, leaving the schedule as it is.
+func (s *SwapService) Reject(id uint) (*models.ShiftSwap, error) {
+	swap, err := s.GetSwapByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if swap.Status != models.SwapPending {
+		return nil, Conflict("already_decided", "the swap request has already been decided")
+	}
+
+	now := time.Now().UTC()
+	swap.Status = models.SwapRejected
+	swap.DecidedAt = &now
+	if err := s.swapRepo.Update(swap); err != nil {
+		return nil, err
+	}
+	return swap, nil
+}
+
+// Changes returns a restaurant's schedule change history, newest
+// first.
+func (s *SwapService) Changes(restaurantID uint) ([]models.ShiftChange, error) {
+	return s.swapRepo.GetChangesByRestaurantID(restaurantID)
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestApprovedSwapReassignsShiftAndWritesHistory(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Mara", Email: "mara@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Swappy", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+	ana := &models.Employee{RestaurantID: restaurant.ID, Name: "Ana"}
+	bo := &models.Employee{RestaurantID: restaurant.ID, Name: "Bo", Email: "bo@example.com"}
+	for _, employee := range []*models.Employee{ana, bo} {
+		if err := db.Create(employee).Error; err != nil {
+			t.Fatalf("create employee: %v", err)
+		}
+	}
+	staffRepo := repositories.NewStaffRepository(db)
+	shift := &models.Shift{
+		RestaurantID: restaurant.ID,
+		EmployeeID:   ana.ID,
+		StartsAt:     time.Now().UTC().AddDate(0, 0, 3),
+		EndsAt:       time.Now().UTC().AddDate(0, 0, 3).Add(8 * time.Hour),
+	}
+	if err := staffRepo.CreateShift(shift); err != nil {
+		t.Fatalf("create shift: %v", err)
+	}
+
+	svc := NewSwapService(repositories.NewSwapRepository(db), staffRepo, repositories.NewJobRepository(db))
+
+	swap, err := svc.RequestSwap(shift.ID, bo.ID, "dentist appointment")
+	if err != nil {
+		t.Fatalf("request swap: %v", err)
+	}
+	if swap.Status != models.SwapPending {
+		t.Fatalf("expected a pending swap, got %q", swap.Status)
+	}
+	var emails int64
+	if err := db.Model(&models.Job{}).Where("type = ?", "email.send").Count(&emails).Error; err != nil {
+		t.Fatalf("count email jobs: %v", err)
+	}
+	if emails != 1 {
+		t.Fatalf("expected the named colleague to be notified once, got %d emails", emails)
+	}
+
+	if _, err := svc.RequestSwap(shift.ID, ana.ID, ""); err == nil {
+		t.Fatal("expected swapping a shift to its own employee to fail")
+	}
+
+	approved, err := svc.Approve(swap.ID)
+	if err != nil {
+		t.Fatalf("approve swap: %v", err)
+	}
+	if approved.Status != models.SwapApproved || approved.DecidedAt == nil {
+		t.Fatalf("expected an approved, decided swap, got %+v", approved)
+	}
+	moved, err := staffRepo.GetShiftByID(shift.ID)
+	if err != nil {
+		t.Fatalf("reload shift: %v", err)
+	}
+	if moved.EmployeeID != bo.ID {
+		t.Fatalf("expected the shift to move to Bo, got employee %d", moved.EmployeeID)
+	}
+	changes, err := svc.Changes(restaurant.ID)
+	if err != nil {
+		t.Fatalf("load history: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("expected one history entry, got %+v", changes)
+	}
+
+	// Decided swaps stay decided.
+	if _, err := svc.Approve(swap.ID); err == nil {
+		t.Fatal("expected re-approving a decided swap to fail")
+	}
+}
+
+func TestApprovedDropRemovesShiftFromSchedule(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Dora", Email: "dora@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Droppy", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+	carl := &models.Employee{RestaurantID: restaurant.ID, Name: "Carl"}
+	dee := &models.Employee{RestaurantID: restaurant.ID, Name: "Dee", Email: "dee@example.com"}
+	for _, employee := range []*models.Employee{carl, dee} {
+		if err := db.Create(employee).Error; err != nil {
+			t.Fatalf("create employee: %v", err)
+		}
+	}
+	staffRepo := repositories.NewStaffRepository(db)
+	start := time.Now().UTC().AddDate(0, 0, 2)
+	shift := &models.Shift{RestaurantID: restaurant.ID, EmployeeID: carl.ID, StartsAt: start, EndsAt: start.Add(6 * time.Hour)}
+	if err := staffRepo.CreateShift(shift); err != nil {
+		t.Fatalf("create shift: %v", err)
+	}
+
+	svc := NewSwapService(repositories.NewSwapRepository(db), staffRepo, repositories.NewJobRepository(db))
+
+	swap, err := svc.RequestSwap(shift.ID, 0, "")
+	if err != nil {
+		t.Fatalf("request drop: %v", err)
+	}
+	var emails int64
+	if err := db.Model(&models.Job{}).Where("type = ?", "email.send").Count(&emails).Error; err != nil {
+		t.Fatalf("count email jobs: %v", err)
+	}
+	if emails != 1 {
+		t.Fatalf("expected only the colleague with an email to be notified, got %d", emails)
+	}
+
+	if _, err := svc.Approve(swap.ID); err != nil {
+		t.Fatalf("approve drop: %v", err)
+	}
+	if _, err := staffRepo.GetShiftByID(shift.ID); err == nil {
+		t.Fatal("expected the dropped shift to be gone")
+	}
+
+	rejected, err := svc.RequestSwap(0, 0, "")
+	if err == nil {
+		t.Fatalf("expected a missing shift to be rejected, got %+v", rejected)
+	}
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// syncOrderUpdater is the slice of OrderService sync pushes need.
+type syncOrderUpdater interface {
+	UpdateOrderStatus(id uint, status string) (*models.Order, error)
+}
+
+// syncReservationUpdater is the slice of ReservationService sync pushes
+// need.
+type syncReservationUpdater interface {
+	UpdateReservationStatus(id uint, status string, table int) (*models.Reservation, error)
+}
+
+// syncMenuUpdater is the slice of MenuService sync pushes need:
+// toggling availability keeps going through the service so caches and
+// price-change bookkeeping stay correct.
+type syncMenuUpdater interface {
+	UpdateMenuItem(id, callerID uint, name, description, category string, price, cost float64, available bool) (*models.MenuItem, error)
+}
+
+// SyncService serves delta sync for POS tablets that go offline: pull
+// everything changed since a token, push queued local changes with
+// conflict detection.
+type SyncService struct {
+	menuRepo        MenuRepo
+	orderRepo       OrderRepo
+	reservationRepo ReservationRepo
+	orders          syncOrderUpdater
+	reservations    syncReservationUpdater
+	menu            syncMenuUpdater
+}
+
+// NewSyncService builds a SyncService.
+func NewSyncService(menuRepo MenuRepo, orderRepo OrderRepo, reservationRepo ReservationRepo, orders syncOrderUpdater, reservations syncReservationUpdater, menu syncMenuUpdater) *SyncService {
+	return &SyncService{menuRepo: menuRepo, orderRepo: orderRepo, reservationRepo: reservationRepo, orders: orders, reservations: reservations, menu: menu}
+}
+
+// SyncDelta is everything that changed since the client's token, plus
+// the token to present next time. Tokens grow monotonically: they are
+// the newest change the delta covered.
+type SyncDelta struct {
+	Token        string               `json:"token"`
+	MenuItems    []models.MenuItem    `json:"menu_items"`
+	Orders       []models.Order       `json:"orders"`
+	Reservations []models.Reservation `json:"reservations"`
+}
+
+// encodeSyncToken turns the newest covered change into an opaque token.
+func encodeSyncToken(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// decodeSyncToken reverses encodeSyncToken. An empty token means "from
+// the beginning", which is a tablet's first sync.
+func decodeSyncToken(token string) (time.Time, error) {
+	if token == "" {
+		return time.Time{}, nil
+	}
+	nanos, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		return time.Time{}, Validation("invalid_token", "invalid sync token")
+	}
+	return time.Unix(0, nanos), nil
+}
+
+// Changes returns the restaurant's menu, order and reservation changes
+// after the given token. The next token is the newest change seen, so a
+// quiet restaurant keeps handing back the same token.
+func (s *SyncService) Changes(restaurantID uint, token string) (*SyncDelta, error) {
+	since, err := decodeSyncToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.menuRepo.GetUpdatedSince(restaurantID, since)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := s.orderRepo.GetUpdatedSince(restaurantID, since)
+	if err != nil {
+		return nil, err
+	}
+	reservations, err := s.reservationRepo.GetUpdatedSince(restaurantID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	newest := since
+	for _, item := range items {
+		if item.UpdatedAt.After(newest) {
+			newest = item.UpdatedAt
+		}
+	}
+	for _, order := range orders {
+		if order.UpdatedAt.After(newest) {
+			newest = order.UpdatedAt
+		}
+	}
+	for _, reservation := range reservations {
+		if reservation.UpdatedAt.After(newest) {
+			newest = reservation.UpdatedAt
+		}
+	}
+
+	return &SyncDelta{
+		Token:        encodeSyncToken(newest),
+		MenuItems:    items,
+		Orders:       orders,
+		Reservations: reservations,
+	}, nil
+}
+
+// SyncOrderChange is a queued order status change. BaseUpdatedAt is the
+// UpdatedAt the tablet last saw; the push conflicts if the server moved
+// past it.
+type SyncOrderChange struct {
+	ID            uint      `json:"id"`
+	Status        string    `json:"status"`
+	BaseUpdatedAt time.Time `json:"base_updated_at"`
+}
+
+// SyncReservationChange is a queued reservation status change.
+type SyncReservationChange struct {
+	ID            uint      `json:"id"`
+	Status        string    `json:"status"`
+	Table         int       `json:"table"`
+	BaseUpdatedAt time.Time `json:"base_updated_at"`
+}
+
+// SyncMenuItemChange is a queued availability toggle, the one menu edit
+// a tablet makes offline (86'ing a dish).
+type SyncMenuItemChange struct {
+	ID            uint      `json:"id"`
+	Available     bool      `json:"available"`
+	BaseUpdatedAt time.Time `json:"base_updated_at"`
+}
+
+// SyncPush is a tablet's queue of offline changes.
+type SyncPush struct {
+	Orders       []SyncOrderChange       `json:"orders"`
+	Reservations []SyncReservationChange `json:"reservations"`
+	MenuItems    []SyncMenuItemChange    `json:"menu_items"`
+}
+
+// SyncConflict is one change the push did not apply, and why.
+type SyncConflict struct {
+	Entity string `json:"entity"`
+	ID     uint   `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// SyncResult reports a push: what applied and what conflicted. The
+// tablet resolves conflicts by pulling and re-deciding; the server
+// never guesses.
+type SyncResult struct {
+	Applied   int            `json:"applied"`
+	Conflicts []SyncConflict `json:"conflicts"`
+}
+
+// Push applies a tablet's queued changes. A change whose base is older
+// than the server row conflicts instead of overwriting what someone
+// else did while the tablet was offline.
+func (s *SyncService) Push(restaurantID, callerID uint, push SyncPush) (*SyncResult, error) {
+	result := &SyncResult{}
+
+	for _, change := range push.Orders {
+		order, err := s.orderRepo.GetByID(change.ID)
+		if err != nil || order.RestaurantID != restaurantID {
+			result.Conflicts = append(result.Conflicts, SyncConflict{Entity: "order", ID: change.ID, Reason: "not found"})
+			continue
+		}
+		if order.UpdatedAt.After(change.BaseUpdatedAt) {
+			result.Conflicts = append(result.Conflicts, SyncConflict{Entity: "order", ID: change.ID, Reason: "changed on server"})
+			continue
+		}
+		if _, err := s.orders.UpdateOrderStatus(change.ID, change.Status); err != nil {
+			result.Conflicts = append(result.Conflicts, SyncConflict{Entity: "order", ID: change.ID, Reason: err.Error()})
+			continue
+		}
+		result.Applied++
+	}
+
+	for _, change := range push.Reservations {
+		reservation, err := s.reservationRepo.GetByID(change.ID)
+		if err != nil || reservation.RestaurantID != restaurantID {
+			result.Conflicts = append(result.Conflicts, SyncConflict{Entity: "reservation", ID: change.ID, Reason: "not found"})
+			continue
+		}
+		if reservation.UpdatedAt.After(change.BaseUpdatedAt) {
+			result.Conflicts = append(result.Conflicts, SyncConflict{Entity: "reservation", ID: change.ID, Reason: "changed on server"})
+			continue
+		}
+		if _, err := s.reservations.UpdateReservationStatus(change.ID, change.Status, change.Table); err != nil {
+			result.Conflicts = append(result.Conflicts, SyncConflict{Entity: "reservation", ID: change.ID, Reason: err.Error()})
+			continue
+		}
+		result.Applied++
+	}
+
+	for _, change := range push.MenuItems {
+		item, err := s.menuRepo.GetByID(change.ID)
+		if err != nil || item.RestaurantID != restaurantID {
+			result.Conflicts = append(result.Conflicts, SyncConflict{Entity: "menu_item", ID: change.ID, Reason: "not found"})
+			continue
+		}
+		if item.UpdatedAt.After(change.BaseUpdatedAt) {
+			result.Conflicts = append(result.Conflicts, SyncConflict{Entity: "menu_item", ID: change.ID, Reason: "changed on server"})
+			continue
+		}
+		if _, err := s.menu.UpdateMenuItem(change.ID, callerID, "", "", "", 0, 0, change.Available); err != nil {
+			result.Conflicts = append(result.Conflicts, SyncConflict{Entity: "menu_item", ID: change.ID, Reason: err.Error()})
+			continue
+		}
+		result.Applied++
+	}
+
+	return result, nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/cache"
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/payments"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+
+	"gorm.io/gorm"
+)
+
+func syncTestService(t *testing.T) (*SyncService, *gorm.DB) {
+	t.Helper()
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	menuRepo := repositories.NewMenuRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+	reservationRepo := repositories.NewReservationRepository(db)
+	menuSvc := NewMenuService(menuRepo, cache.NewMemory(), nil, nil)
+	orderSvc := NewOrderService(
+		orderRepo,
+		menuRepo,
+		repositories.NewRefundRepository(db),
+		repositories.NewTxManager(db),
+		repositories.NewOutboxRepository(db),
+		payments.LogProvider{},
+		nil,
+		nil,
+		nil,
+		nil,
+		19,
+	)
+	reservationSvc := NewReservationService(reservationRepo, repositories.NewRestaurantRepository(db), repositories.NewCustomerRepository(db), payments.LogProvider{})
+	return NewSyncService(menuRepo, orderRepo, reservationRepo, orderSvc, reservationSvc, menuSvc), db
+}
+
+func TestSyncChangesAdvancesTokenMonotonically(t *testing.T) {
+	syncSvc, db := syncTestService(t)
+
+	restaurant := models.Restaurant{Name: "Offline", UserID: 1}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+	item := models.MenuItem{RestaurantID: restaurant.ID, Name: "Lomo", Price: 9000, Available: true}
+	order := models.Order{RestaurantID: restaurant.ID, CustomerName: "Rosa", Status: models.OrderPending, Total: 9000}
+	reservation := models.Reservation{RestaurantID: restaurant.ID, CustomerName: "Rosa", PartySize: 2, Date: time.Now().Add(24 * time.Hour)}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatalf("create menu item: %v", err)
+	}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	if err := db.Create(&reservation).Error; err != nil {
+		t.Fatalf("create reservation: %v", err)
+	}
+
+	if _, err := syncSvc.Changes(restaurant.ID, "not-a-token"); err == nil {
+		t.Fatal("expected a malformed token to be rejected")
+	}
+
+	// A first sync (empty token) covers everything.
+	first, err := syncSvc.Changes(restaurant.ID, "")
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if len(first.MenuItems) != 1 || len(first.Orders) != 1 || len(first.Reservations) != 1 {
+		t.Fatalf("expected the full state on first sync, got %d/%d/%d", len(first.MenuItems), len(first.Orders), len(first.Reservations))
+	}
+	if first.Token == "" {
+		t.Fatal("expected a token on first sync")
+	}
+
+	// Nothing changed: the delta is empty and the token stays put.
+	second, err := syncSvc.Changes(restaurant.ID, first.Token)
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if len(second.MenuItems)+len(second.Orders)+len(second.Reservations) != 0 {
+		t.Fatalf("expected an empty delta, got %d/%d/%d", len(second.MenuItems), len(second.Orders), len(second.Reservations))
+	}
+	if second.Token != first.Token {
+		t.Fatalf("expected the token to hold at %s, got %s", first.Token, second.Token)
+	}
+
+	// One change shows up in the next delta and advances the token.
+	if err := db.Model(&item).Update("available", false).Error; err != nil {
+		t.Fatalf("update menu item: %v", err)
+	}
+	third, err := syncSvc.Changes(restaurant.ID, first.Token)
+	if err != nil {
+		t.Fatalf("third sync: %v", err)
+	}
+	if len(third.MenuItems) != 1 || len(third.Orders) != 0 || len(third.Reservations) != 0 {
+		t.Fatalf("expected only the changed item, got %d/%d/%d", len(third.MenuItems), len(third.Orders), len(third.Reservations))
+	}
+	if third.Token == first.Token {
+		t.Fatal("expected the token to advance after a change")
+	}
+}
+
+func TestSyncPushDetectsConflicts(t *testing.T) {
+	syncSvc, db := syncTestService(t)
+
+	restaurant := models.Restaurant{Name: "Conflicto", UserID: 1}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+	order := models.Order{RestaurantID: restaurant.ID, CustomerName: "Rosa", Status: models.OrderPending, Total: 9000}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+
+	// A stale base means the server moved on while the tablet was
+	// offline: the push reports a conflict and applies nothing.
+	result, err := syncSvc.Push(restaurant.ID, 1, SyncPush{
+		Orders: []SyncOrderChange{{ID: order.ID, Status: models.OrderAccepted, BaseUpdatedAt: order.UpdatedAt.Add(-time.Hour)}},
+	})
+	if err != nil {
+		t.Fatalf("stale push: %v", err)
+	}
+	if result.Applied != 0 || len(result.Conflicts) != 1 || result.Conflicts[0].Reason != "changed on server" {
+		t.Fatalf("expected one conflict and no applies, got %+v", result)
+	}
+	var kept models.Order
+	if err := db.First(&kept, order.ID).Error; err != nil {
+		t.Fatalf("reload order: %v", err)
+	}
+	if kept.Status != models.OrderPending {
+		t.Fatalf("expected the stale push to leave the order pending, got %s", kept.Status)
+	}
+
+	// A fresh base applies cleanly.
+	result, err = syncSvc.Push(restaurant.ID, 1, SyncPush{
+		Orders: []SyncOrderChange{{ID: order.ID, Status: models.OrderAccepted, BaseUpdatedAt: kept.UpdatedAt}},
+	})
+	if err != nil {
+		t.Fatalf("fresh push: %v", err)
+	}
+	if result.Applied != 1 || len(result.Conflicts) != 0 {
+		t.Fatalf("expected one apply and no conflicts, got %+v", result)
+	}
+	if err := db.First(&kept, order.ID).Error; err != nil {
+		t.Fatalf("reload order: %v", err)
+	}
+	if kept.Status != models.OrderAccepted {
+		t.Fatalf("expected the fresh push to accept the order, got %s", kept.Status)
+	}
+
+	// Changes for another restaurant never apply.
+	result, err = syncSvc.Push(restaurant.ID+1, 1, SyncPush{
+		Orders: []SyncOrderChange{{ID: order.ID, Status: models.OrderCompleted, BaseUpdatedAt: kept.UpdatedAt}},
+	})
+	if err != nil {
+		t.Fatalf("cross-restaurant push: %v", err)
+	}
+	if result.Applied != 0 || len(result.Conflicts) != 1 || result.Conflicts[0].Reason != "not found" {
+		t.Fatalf("expected the foreign order to read as not found, got %+v", result)
+	}
+}
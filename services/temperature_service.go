@@ -0,0 +1,176 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// TemperatureService keeps the HACCP temperature log: monitored units,
+// their readings, and alerts when a unit drifts out of its safe range.
+type TemperatureService struct {
+	temperatureRepo TemperatureRepo
+	restaurantRepo  RestaurantRepo
+	userRepo        UserRepo
+	jobs            JobEnqueuer
+}
+
+// NewTemperatureService builds a TemperatureService.
+func NewTemperatureService(temperatureRepo TemperatureRepo, restaurantRepo RestaurantRepo, userRepo UserRepo, jobs JobEnqueuer) *TemperatureService {
+	return &TemperatureService{temperatureRepo: temperatureRepo, restaurantRepo: restaurantRepo, userRepo: userRepo, jobs: jobs}
+}
+
+// CreateUnit registers a fridge or freezer to monitor.
+func (s *TemperatureService) CreateUnit(restaurantID uint, name string, minC, maxC float64) (*models.TemperatureUnit, error) {
+	name = utils.SanitizeText(name)
+	if name == "" {
+		return nil, Validation("missing_name", "a unit name is required")
+	}
+	if minC >= maxC {
+		return nil, Validation("invalid_range", "the minimum temperature must be below the maximum")
+	}
+
+	unit := &models.TemperatureUnit{RestaurantID: restaurantID, Name: name, MinC: minC, MaxC: maxC}
+	if err := s.temperatureRepo.CreateUnit(unit); err != nil {
+		return nil, err
+	}
+	return unit, nil
+}
+
+// GetUnitByID returns one monitored unit.
+func (s *TemperatureService) GetUnitByID(id uint) (*models.TemperatureUnit, error) {
+	unit, err := s.temperatureRepo.GetUnitByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "unit_not_found", "temperature unit not found")
+	}
+	return unit, nil
+}
+
+// Units returns a restaurant's monitored units.
+func (s *TemperatureService) Units(restaurantID uint) ([]models.TemperatureUnit, error) {
+	return s.temperatureRepo.GetUnitsByRestaurantID(restaurantID)
+}
+
+// RecordManual logs a temperature check taken by hand.
+func (s *TemperatureService) RecordManual(unitID uint, celsius float64) (*models.TemperatureReading, error) {
+	unit, err := s.GetUnitByID(unitID)
+	if err != nil {
+		return nil, err
+	}
+	return s.record(unit, celsius, models.ReadingManual)
+}
+
+// RecordSensor logs a reading pushed by a sensor, authenticated by the
+// unit's sensor key.
+func (s *TemperatureService) RecordSensor(sensorKey string, celsius float64) (*models.TemperatureReading, error) {
+	unit, err := s.temperatureRepo.GetUnitBySensorKey(sensorKey)
+	if err != nil {
+		return nil, fromRepo(err, "unknown_sensor", "no unit matches that sensor key")
+	}
+	return s.record(unit, celsius, models.ReadingSensor)
+}
+
+// record stores the reading and manages the unit's alert state: the
+// first out-of-range reading emails the owner, further ones stay quiet,
+// and a reading back in range re-arms the alert.
+func (s *TemperatureService) record(unit *models.TemperatureUnit, celsius float64, source string) (*models.TemperatureReading, error) {
+	outOfRange := celsius < unit.MinC || celsius > unit.MaxC
+	reading := &models.TemperatureReading{
+		TemperatureUnitID: unit.ID,
+		Celsius:           celsius,
+		Source:            source,
+		OutOfRange:        outOfRange,
+	}
+	if err := s.temperatureRepo.CreateReading(reading); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case outOfRange && unit.AlertedAt == nil:
+		if err := s.alertOutOfRange(unit, celsius); err != nil {
+			log.Printf("temperature: alerting for unit %d: %v", unit.ID, err)
+		} else {
+			now := time.Now().UTC()
+			unit.AlertedAt = &now
+			if err := s.temperatureRepo.UpdateUnit(unit); err != nil {
+				return nil, err
+			}
+		}
+	case !outOfRange && unit.AlertedAt != nil:
+		unit.AlertedAt = nil
+		if err := s.temperatureRepo.UpdateUnit(unit); err != nil {
+			return nil, err
+		}
+	}
+	return reading, nil
+}
+
+// alertOutOfRange queues one email to the restaurant's owner about the
+// out-of-range unit.
+func (s *TemperatureService) alertOutOfRange(unit *models.TemperatureUnit, celsius float64) error {
+	restaurant, err := s.restaurantRepo.GetByID(unit.RestaurantID)
+	if err != nil {
+		return err
+	}
+	owner, err := s.userRepo.GetByID(restaurant.UserID)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"to":      owner.Email,
+		"subject": fmt.Sprintf("%s: %s out of range", restaurant.Name, unit.Name),
+		"text": fmt.Sprintf("%s read %.1f°C, outside its safe range of %.1f°C to %.1f°C. Check the unit and move the stock if needed.",
+			unit.Name, celsius, unit.MinC, unit.MaxC),
+	})
+	if err != nil {
+		return err
+	}
+	return s.jobs.Enqueue(&models.Job{Type: "email.send", Payload: string(payload)})
+}
+
+// Readings returns a unit's log in the range, oldest first.
+func (s *TemperatureService) Readings(unitID uint, from, to time.Time) ([]models.TemperatureReading, error) {
+	return s.temperatureRepo.GetReadingsByUnitID(unitID, from, to)
+}
+
+// ExportLog renders a restaurant's full temperature log as a
+// downloadable file for inspections. format is csv or empty for csv.
+func (s *TemperatureService) ExportLog(restaurantID uint, from, to time.Time, format string) ([]byte, string, error) {
+	if format != "" && format != "csv" {
+		return nil, "", Validation("invalid_format", "format must be csv")
+	}
+	units, err := s.Units(restaurantID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"unit", "recorded_at", "celsius", "source", "in_range"})
+	for i := range units {
+		readings, err := s.Readings(units[i].ID, from, to)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, reading := range readings {
+			w.Write([]string{
+				units[i].Name,
+				reading.CreatedAt.UTC().Format(time.RFC3339),
+				strconv.FormatFloat(reading.Celsius, 'f', 1, 64),
+				reading.Source,
+				strconv.FormatBool(!reading.OutOfRange),
+			})
+		}
+	}
+	w.Flush()
+
+	stamp := from.Format("20060102") + "-" + to.Format("20060102")
+	return buf.Bytes(), fmt.Sprintf("temperature-log-%s.csv", stamp), nil
+}
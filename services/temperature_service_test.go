@@ -0,0 +1,143 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestOutOfRangeReadingAlertsOnceUntilRecovery(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Tina", Email: "tina@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Chilled", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewTemperatureService(repositories.NewTemperatureRepository(db), repositories.NewRestaurantRepository(db), repositories.NewUserRepository(db), repositories.NewJobRepository(db))
+
+	unit, err := svc.CreateUnit(restaurant.ID, "Walk-in fridge", 0, 4)
+	if err != nil {
+		t.Fatalf("create unit: %v", err)
+	}
+	if unit.SensorKey == "" {
+		t.Fatal("expected the unit to get a sensor key")
+	}
+	if _, err := svc.CreateUnit(restaurant.ID, "Broken", 4, 0); err == nil {
+		t.Fatal("expected an inverted range to be rejected")
+	}
+
+	emails := func() int64 {
+		var n int64
+		if err := db.Model(&models.Job{}).Where("type = ?", "email.send").Count(&n).Error; err != nil {
+			t.Fatalf("count email jobs: %v", err)
+		}
+		return n
+	}
+
+	if _, err := svc.RecordManual(unit.ID, 3); err != nil {
+		t.Fatalf("recording in-range reading: %v", err)
+	}
+	if emails() != 0 {
+		t.Fatal("expected no alert for an in-range reading")
+	}
+
+	reading, err := svc.RecordManual(unit.ID, 8)
+	if err != nil {
+		t.Fatalf("recording out-of-range reading: %v", err)
+	}
+	if !reading.OutOfRange {
+		t.Fatal("expected the reading to be flagged out of range")
+	}
+	if emails() != 1 {
+		t.Fatalf("expected one alert email, got %d", emails())
+	}
+
+	// A still-bad unit does not alert again until it has recovered.
+	if _, err := svc.RecordManual(unit.ID, 9); err != nil {
+		t.Fatalf("recording second out-of-range reading: %v", err)
+	}
+	if emails() != 1 {
+		t.Fatalf("expected no repeat alert, got %d emails", emails())
+	}
+	if _, err := svc.RecordManual(unit.ID, 2); err != nil {
+		t.Fatalf("recording recovery reading: %v", err)
+	}
+	if _, err := svc.RecordManual(unit.ID, 10); err != nil {
+		t.Fatalf("recording new out-of-range reading: %v", err)
+	}
+	if emails() != 2 {
+		t.Fatalf("expected a fresh alert after recovery, got %d emails", emails())
+	}
+}
+
+func TestSensorReadingsAndExportableLog(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Sam", Email: "sam@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Sensored", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	svc := NewTemperatureService(repositories.NewTemperatureRepository(db), repositories.NewRestaurantRepository(db), repositories.NewUserRepository(db), repositories.NewJobRepository(db))
+
+	unit, err := svc.CreateUnit(restaurant.ID, "Freezer", -22, -16)
+	if err != nil {
+		t.Fatalf("create unit: %v", err)
+	}
+
+	if _, err := svc.RecordSensor("not-a-key", -18); err == nil {
+		t.Fatal("expected an unknown sensor key to be rejected")
+	}
+	reading, err := svc.RecordSensor(unit.SensorKey, -18)
+	if err != nil {
+		t.Fatalf("recording sensor reading: %v", err)
+	}
+	if reading.Source != models.ReadingSensor || reading.OutOfRange {
+		t.Fatalf("expected an in-range sensor reading, got %+v", reading)
+	}
+	if _, err := svc.RecordManual(unit.ID, -12); err != nil {
+		t.Fatalf("recording manual reading: %v", err)
+	}
+
+	now := time.Now().UTC()
+	body, filename, err := svc.ExportLog(restaurant.ID, now.AddDate(0, 0, -1), now.AddDate(0, 0, 1), "")
+	if err != nil {
+		t.Fatalf("exporting log: %v", err)
+	}
+	if !strings.HasPrefix(filename, "temperature-log-") || !strings.HasSuffix(filename, ".csv") {
+		t.Fatalf("unexpected filename %q", filename)
+	}
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and two readings, got %d lines", len(lines))
+	}
+	if !strings.Contains(lines[1], "Freezer") || !strings.Contains(lines[1], "sensor") || !strings.Contains(lines[1], "true") {
+		t.Fatalf("unexpected first log line %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "manual") || !strings.Contains(lines[2], "false") {
+		t.Fatalf("unexpected second log line %q", lines[2])
+	}
+
+	if _, _, err := svc.ExportLog(restaurant.ID, now, now, "pdf"); err == nil {
+		t.Fatal("expected an unsupported format to be rejected")
+	}
+}
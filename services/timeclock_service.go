@@ -0,0 +1,232 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/hashing"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// TimeclockService records clock-in/clock-out punches — by employee id
+// or by PIN from a shared tablet — and turns them into timesheets and
+// variance reports against the schedule.
+type TimeclockService struct {
+	timeclockRepo  TimeclockRepo
+	staffRepo      StaffRepo
+	restaurantRepo RestaurantRepo
+	hasher         hashing.Hasher
+}
+
+// NewTimeclockService builds a TimeclockService.
+func NewTimeclockService(timeclockRepo TimeclockRepo, staffRepo StaffRepo, restaurantRepo RestaurantRepo, hasher hashing.Hasher) *TimeclockService {
+	return &TimeclockService{timeclockRepo: timeclockRepo, staffRepo: staffRepo, restaurantRepo: restaurantRepo, hasher: hasher}
+}
+
+// SetPin sets an employee's time-clock PIN for shared tablets. The PIN
+// is stored hashed, like a password.
+func (s *TimeclockService) SetPin(employeeID uint, pin string) error {
+	if len(pin) < 4 || len(pin) > 8 {
+		return Validation("invalid_pin", "the PIN must be 4 to 8 digits")
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return Validation("invalid_pin", "the PIN must be 4 to 8 digits")
+		}
+	}
+	employee, err := s.staffRepo.GetEmployeeByID(employeeID)
+	if err != nil {
+		return fromRepo(err, "employee_not_found", "employee not found")
+	}
+	hash, err := s.hasher.Hash(pin)
+	if err != nil {
+		return err
+	}
+	employee.PinHash = hash
+	return s.staffRepo.UpdateEmployee(employee)
+}
+
+// ClockIn opens a punch for the employee.
+func (s *TimeclockService) ClockIn(employeeID uint) (*models.TimePunch, error) {
+	employee, err := s.staffRepo.GetEmployeeByID(employeeID)
+	if err != nil {
+		return nil, fromRepo(err, "employee_not_found", "employee not found")
+	}
+	if _, err := s.timeclockRepo.GetOpenPunch(employee.ID); err == nil {
+		return nil, Conflict("already_clocked_in", "the employee is already on the clock")
+	}
+
+	punch := &models.TimePunch{
+		RestaurantID: employee.RestaurantID,
+		EmployeeID:   employee.ID,
+		ClockIn:      time.Now().UTC(),
+	}
+	if err := s.timeclockRepo.Create(punch); err != nil {
+		return nil, err
+	}
+	return punch, nil
+}
+
+// ClockOut closes the employee's open punch.
+func (s *TimeclockService) ClockOut(employeeID uint) (*models.TimePunch, error) {
+	punch, err := s.timeclockRepo.GetOpenPunch(employeeID)
+	if err != nil {
+		return nil, Conflict("not_clocked_in", "the employee is not on the clock")
+	}
+
+	now := time.Now().UTC()
+	punch.ClockOut = &now
+	if err := s.timeclockRepo.Update(punch); err != nil {
+		return nil, err
+	}
+	return punch, nil
+}
+
+// PunchByPin toggles the clock for whichever of the restaurant's
+// employees the PIN belongs to: off the clock punches in, on the clock
+// punches out. When the restaurant has a geofence configured the
+// tablet's coordinates must fall inside it.
+func (s *TimeclockService) PunchByPin(restaurantID uint, pin string, lat, lng float64) (*models.TimePunch, error) {
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	if restaurant.GeofenceMeters > 0 {
+		if distanceMeters(restaurant.Latitude, restaurant.Longitude, lat, lng) > float64(restaurant.GeofenceMeters) {
+			return nil, Forbidden("outside_geofence", "the punch came from outside the restaurant's geofence")
+		}
+	}
+
+	employees, err := s.staffRepo.GetEmployeesByRestaurantID(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range employees {
+		if employees[i].PinHash == "" || !hashing.Verify(pin, employees[i].PinHash) {
+			continue
+		}
+		if _, err := s.timeclockRepo.GetOpenPunch(employees[i].ID); err == nil {
+			return s.ClockOut(employees[i].ID)
+		}
+		return s.ClockIn(employees[i].ID)
+	}
+	return nil, Forbidden("invalid_pin", "no employee matches that PIN")
+}
+
+// SetGeofence stores the restaurant's punch geofence. A zero radius
+// disables the check.
+func (s *TimeclockService) SetGeofence(restaurantID uint, lat, lng float64, meters int) (*models.Restaurant, error) {
+	if meters < 0 {
+		return nil, Validation("invalid_radius", "the radius cannot be negative")
+	}
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil, Validation("invalid_coordinates", "coordinates are out of range")
+	}
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return nil, fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	restaurant.Latitude = lat
+	restaurant.Longitude = lng
+	restaurant.GeofenceMeters = meters
+	if err := s.restaurantRepo.Update(restaurant); err != nil {
+		return nil, err
+	}
+	return restaurant, nil
+}
+
+// TimesheetRow is one employee's worked hours over a pay period.
+type TimesheetRow struct {
+	EmployeeID   uint    `json:"employee_id"`
+	EmployeeName string  `json:"employee_name"`
+	PayrollRef   string  `json:"payroll_ref,omitempty"`
+	Hours        float64 `json:"hours"`
+	Punches      int     `json:"punches"`
+}
+
+// Timesheet totals each employee's closed punches in the range. Open
+// punches are left out — they have no duration yet.
+func (s *TimeclockService) Timesheet(restaurantID uint, from, to time.Time) ([]TimesheetRow, error) {
+	employees, err := s.staffRepo.GetEmployeesByRestaurantID(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	punches, err := s.timeclockRepo.GetByRestaurantID(restaurantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	hours := make(map[uint]float64)
+	counts := make(map[uint]int)
+	for _, punch := range punches {
+		if punch.ClockOut == nil {
+			continue
+		}
+		hours[punch.EmployeeID] += punch.ClockOut.Sub(punch.ClockIn).Hours()
+		counts[punch.EmployeeID]++
+	}
+
+	rows := make([]TimesheetRow, 0, len(employees))
+	for i := range employees {
+		rows = append(rows, TimesheetRow{
+			EmployeeID:   employees[i].ID,
+			EmployeeName: employees[i].Name,
+			PayrollRef:   employees[i].PayrollRef,
+			Hours:        hours[employees[i].ID],
+			Punches:      counts[employees[i].ID],
+		})
+	}
+	return rows, nil
+}
+
+// VarianceRow compares one employee's worked hours against the
+// schedule.
+type VarianceRow struct {
+	EmployeeID     uint    `json:"employee_id"`
+	EmployeeName   string  `json:"employee_name"`
+	ScheduledHours float64 `json:"scheduled_hours"`
+	WorkedHours    float64 `json:"worked_hours"`
+	VarianceHours  float64 `json:"variance_hours"`
+}
+
+// Variance reports worked minus scheduled hours per employee in the
+// range. Positive variance means more time on the clock than on the
+// rota.
+func (s *TimeclockService) Variance(restaurantID uint, from, to time.Time) ([]VarianceRow, error) {
+	timesheet, err := s.Timesheet(restaurantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	shifts, err := s.staffRepo.GetShiftsByRange(restaurantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled := make(map[uint]float64)
+	for _, shift := range shifts {
+		scheduled[shift.EmployeeID] += shift.EndsAt.Sub(shift.StartsAt).Hours()
+	}
+
+	rows := make([]VarianceRow, 0, len(timesheet))
+	for _, row := range timesheet {
+		rows = append(rows, VarianceRow{
+			EmployeeID:     row.EmployeeID,
+			EmployeeName:   row.EmployeeName,
+			ScheduledHours: scheduled[row.EmployeeID],
+			WorkedHours:    row.Hours,
+			VarianceHours:  row.Hours - scheduled[row.EmployeeID],
+		})
+	}
+	return rows, nil
+}
+
+// distanceMeters is the haversine distance between two coordinates.
+func distanceMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadius = 6371000.0
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := rad(lat2 - lat1)
+	dLng := rad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/hashing"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestPinPunchTogglesClockInsideGeofence(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Pam", Email: "pam@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Punchy", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+	employee := &models.Employee{RestaurantID: restaurant.ID, Name: "Pia"}
+	if err := db.Create(employee).Error; err != nil {
+		t.Fatalf("create employee: %v", err)
+	}
+
+	svc := NewTimeclockService(repositories.NewTimeclockRepository(db), repositories.NewStaffRepository(db), repositories.NewRestaurantRepository(db), &hashing.BcryptHasher{Cost: 4})
+
+	if err := svc.SetPin(employee.ID, "12ab"); err == nil {
+		t.Fatal("expected a non-numeric PIN to be rejected")
+	}
+	if err := svc.SetPin(employee.ID, "4321"); err != nil {
+		t.Fatalf("set pin: %v", err)
+	}
+
+	// Santiago city centre, with the tablet right next door.
+	if _, err := svc.SetGeofence(restaurant.ID, -33.4489, -70.6693, 150); err != nil {
+		t.Fatalf("set geofence: %v", err)
+	}
+
+	punch, err := svc.PunchByPin(restaurant.ID, "4321", -33.4490, -70.6694)
+	if err != nil {
+		t.Fatalf("punching in: %v", err)
+	}
+	if punch.ClockOut != nil {
+		t.Fatal("expected the first punch to clock in")
+	}
+	punch, err = svc.PunchByPin(restaurant.ID, "4321", -33.4490, -70.6694)
+	if err != nil {
+		t.Fatalf("punching out: %v", err)
+	}
+	if punch.ClockOut == nil {
+		t.Fatal("expected the second punch to clock out")
+	}
+
+	if _, err := svc.PunchByPin(restaurant.ID, "4321", -33.5000, -70.7000); err == nil {
+		t.Fatal("expected a punch from outside the geofence to be rejected")
+	}
+	if _, err := svc.PunchByPin(restaurant.ID, "0000", -33.4490, -70.6694); err == nil {
+		t.Fatal("expected an unknown PIN to be rejected")
+	}
+
+	// Double clock-ins are conflicts.
+	if _, err := svc.ClockIn(employee.ID); err != nil {
+		t.Fatalf("clock in: %v", err)
+	}
+	if _, err := svc.ClockIn(employee.ID); err == nil {
+		t.Fatal("expected a second clock-in to fail")
+	}
+	if _, err := svc.ClockOut(employee.ID); err != nil {
+		t.Fatalf("clock out: %v", err)
+	}
+	if _, err := svc.ClockOut(employee.ID); err == nil {
+		t.Fatal("expected clocking out while off the clock to fail")
+	}
+}
+
+func TestTimesheetAndVarianceAgainstSchedule(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Vic", Email: "vic@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Varied", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+	employee := &models.Employee{RestaurantID: restaurant.ID, Name: "Wim", PayrollRef: "W-7"}
+	if err := db.Create(employee).Error; err != nil {
+		t.Fatalf("create employee: %v", err)
+	}
+
+	staffRepo := repositories.NewStaffRepository(db)
+	start := time.Now().UTC().AddDate(0, 0, -1).Truncate(time.Hour)
+	shift := &models.Shift{RestaurantID: restaurant.ID, EmployeeID: employee.ID, StartsAt: start, EndsAt: start.Add(8 * time.Hour)}
+	if err := staffRepo.CreateShift(shift); err != nil {
+		t.Fatalf("create shift: %v", err)
+	}
+
+	// Worked an hour over the scheduled eight, plus an open punch that
+	// must not count yet.
+	out := start.Add(9 * time.Hour)
+	if err := db.Create(&models.TimePunch{RestaurantID: restaurant.ID, EmployeeID: employee.ID, ClockIn: start, ClockOut: &out}).Error; err != nil {
+		t.Fatalf("create punch: %v", err)
+	}
+	if err := db.Create(&models.TimePunch{RestaurantID: restaurant.ID, EmployeeID: employee.ID, ClockIn: out.Add(time.Hour)}).Error; err != nil {
+		t.Fatalf("create open punch: %v", err)
+	}
+
+	svc := NewTimeclockService(repositories.NewTimeclockRepository(db), staffRepo, repositories.NewRestaurantRepository(db), &hashing.BcryptHasher{Cost: 4})
+
+	from := start.AddDate(0, 0, -1)
+	to := start.AddDate(0, 0, 2)
+	timesheet, err := svc.Timesheet(restaurant.ID, from, to)
+	if err != nil {
+		t.Fatalf("timesheet: %v", err)
+	}
+	if len(timesheet) != 1 {
+		t.Fatalf("expected one timesheet row, got %+v", timesheet)
+	}
+	if timesheet[0].Hours != 9 || timesheet[0].Punches != 1 {
+		t.Fatalf("expected 9 worked hours over 1 closed punch, got %+v", timesheet[0])
+	}
+	if timesheet[0].PayrollRef != "W-7" {
+		t.Fatalf("expected the payroll ref on the row, got %+v", timesheet[0])
+	}
+
+	variance, err := svc.Variance(restaurant.ID, from, to)
+	if err != nil {
+		t.Fatalf("variance: %v", err)
+	}
+	if len(variance) != 1 {
+		t.Fatalf("expected one variance row, got %+v", variance)
+	}
+	if variance[0].ScheduledHours != 8 || variance[0].WorkedHours != 9 || variance[0].VarianceHours != 1 {
+		t.Fatalf("expected an hour of overtime, got %+v", variance[0])
+	}
+}
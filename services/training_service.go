@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// trainingReminderDays is how far before expiry the renewal reminder
+// goes out.
+const trainingReminderDays = 30
+
+// TrainingService stores per-employee training and certification
+// records and chases renewals before they expire.
+type TrainingService struct {
+	trainingRepo   TrainingRepo
+	staffRepo      StaffRepo
+	restaurantRepo RestaurantRepo
+	userRepo       UserRepo
+	jobs           JobEnqueuer
+}
+
+// NewTrainingService builds a TrainingService.
+func NewTrainingService(trainingRepo TrainingRepo, staffRepo StaffRepo, restaurantRepo RestaurantRepo, userRepo UserRepo, jobs JobEnqueuer) *TrainingService {
+	return &TrainingService{trainingRepo: trainingRepo, staffRepo: staffRepo, restaurantRepo: restaurantRepo, userRepo: userRepo, jobs: jobs}
+}
+
+// AddRecord stores a completed training for an employee. completedAt
+// defaults to now.
+func (s *TrainingService) AddRecord(employeeID uint, name string, completedAt, expiresAt time.Time) (*models.TrainingRecord, error) {
+	name = utils.SanitizeText(name)
+	if name == "" {
+		return nil, Validation("missing_name", "the training name is required")
+	}
+	if expiresAt.IsZero() {
+		return nil, Validation("missing_expiry", "an expiry date is required")
+	}
+	if completedAt.IsZero() {
+		completedAt = time.Now().UTC()
+	}
+	if !expiresAt.After(completedAt) {
+		return nil, Validation("invalid_expiry", "the expiry date must be after completion")
+	}
+
+	record := &models.TrainingRecord{
+		EmployeeID:  employeeID,
+		Name:        name,
+		CompletedAt: completedAt.UTC(),
+		ExpiresAt:   expiresAt.UTC(),
+	}
+	if err := s.trainingRepo.Create(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Records returns an employee's training records, soonest to expire
+// first.
+func (s *TrainingService) Records(employeeID uint) ([]models.TrainingRecord, error) {
+	return s.trainingRepo.GetByEmployeeID(employeeID)
+}
+
+// RunOnce sends the renewal reminders due on the given day and returns
+// how many went out. Per-record failures are logged and skipped so one
+// bad record cannot hold up the rest.
+func (s *TrainingService) RunOnce(now time.Time) (int, error) {
+	cutoff := now.UTC().AddDate(0, 0, trainingReminderDays)
+	records, err := s.trainingRepo.ExpiringBefore(cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range records {
+		if err := s.remind(&records[i], now); err != nil {
+			log.Printf("training: record %d: %v", records[i].ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// remind queues one record's renewal reminder and marks the record as
+// reminded. The reminder goes to the employee when their email is on
+// file, and to the restaurant's owner otherwise.
+func (s *TrainingService) remind(record *models.TrainingRecord, now time.Time) error {
+	employee, err := s.staffRepo.GetEmployeeByID(record.EmployeeID)
+	if err != nil {
+		return err
+	}
+	to := employee.Email
+	if to == "" {
+		restaurant, err := s.restaurantRepo.GetByID(employee.RestaurantID)
+		if err != nil {
+			return err
+		}
+		owner, err := s.userRepo.GetByID(restaurant.UserID)
+		if err != nil {
+			return err
+		}
+		to = owner.Email
+	}
+
+	expiry := record.ExpiresAt.Format("2006-01-02")
+	payload, err := json.Marshal(map[string]interface{}{
+		"to":      to,
+		"subject": fmt.Sprintf("%s: %s expires on %s", employee.Name, record.Name, expiry),
+		"text":    fmt.Sprintf("The %s for %s expires on %s. Book the renewal before then to stay compliant.", record.Name, employee.Name, expiry),
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.jobs.Enqueue(&models.Job{Type: "email.send", Payload: string(payload)}); err != nil {
+		return fmt.Errorf("queueing reminder: %w", err)
+	}
+
+	remindedAt := now.UTC()
+	record.RemindedAt = &remindedAt
+	return s.trainingRepo.Update(record)
+}
+
+// Start runs the daily reminder loop until ctx is cancelled. It checks
+// hourly and runs once per calendar day, same as the license loop.
+func (s *TrainingService) Start(ctx context.Context) {
+	run := func(now time.Time) {
+		sent, err := s.RunOnce(now)
+		if err != nil {
+			log.Printf("training: daily run: %v", err)
+			return
+		}
+		if sent > 0 {
+			log.Printf("training: sent %d renewal reminder(s)", sent)
+		}
+	}
+	now := time.Now().UTC()
+	run(now)
+	lastDay := now.Format("2006-01-02")
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now().UTC()
+			if day := now.Format("2006-01-02"); day != lastDay {
+				run(now)
+				lastDay = day
+			}
+		}
+	}
+}
+
+// TrainingStatusRow is one line of a restaurant's training status
+// report.
+type TrainingStatusRow struct {
+	EmployeeID   uint   `json:"employee_id"`
+	EmployeeName string `json:"employee_name"`
+	Training     string `json:"training,omitempty"`
+	ExpiresAt    string `json:"expires_at,omitempty"` // "2006-01-02"
+	Status       string `json:"status"`
+}
+
+// Status reports every employee's trainings and their expiry status,
+// reusing the license status names. Employees with no records get a
+// single "missing" row so they do not disappear from the report.
+func (s *TrainingService) Status(restaurantID uint, now time.Time) ([]TrainingStatusRow, error) {
+	employees, err := s.staffRepo.GetEmployeesByRestaurantID(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := now.UTC().AddDate(0, 0, trainingReminderDays)
+	rows := make([]TrainingStatusRow, 0, len(employees))
+	for i := range employees {
+		employee := &employees[i]
+		records, err := s.trainingRepo.GetByEmployeeID(employee.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			rows = append(rows, TrainingStatusRow{
+				EmployeeID:   employee.ID,
+				EmployeeName: employee.Name,
+				Status:       LicenseMissing,
+			})
+			continue
+		}
+		for _, record := range records {
+			status := LicenseValid
+			switch {
+			case record.ExpiresAt.Before(now):
+				status = LicenseExpired
+			case record.ExpiresAt.Before(cutoff):
+				status = LicenseExpiring
+			}
+			rows = append(rows, TrainingStatusRow{
+				EmployeeID:   employee.ID,
+				EmployeeName: employee.Name,
+				Training:     record.Name,
+				ExpiresAt:    record.ExpiresAt.Format("2006-01-02"),
+				Status:       status,
+			})
+		}
+	}
+	return rows, nil
+}
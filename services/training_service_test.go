@@ -0,0 +1,142 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestTrainingRemindersGoOutOncePerRecord(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Olive", Email: "olive@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Trained", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+	withEmail := &models.Employee{RestaurantID: restaurant.ID, Name: "Eva", Email: "eva@example.com"}
+	if err := db.Create(withEmail).Error; err != nil {
+		t.Fatalf("create employee: %v", err)
+	}
+	withoutEmail := &models.Employee{RestaurantID: restaurant.ID, Name: "Nico"}
+	if err := db.Create(withoutEmail).Error; err != nil {
+		t.Fatalf("create employee: %v", err)
+	}
+
+	svc := NewTrainingService(repositories.NewTrainingRepository(db), repositories.NewStaffRepository(db), repositories.NewRestaurantRepository(db), repositories.NewUserRepository(db), repositories.NewJobRepository(db))
+
+	now := time.Now().UTC()
+	if _, err := svc.AddRecord(withEmail.ID, "Food handler cert", now.AddDate(-1, 0, 0), now.AddDate(0, 0, 10)); err != nil {
+		t.Fatalf("add expiring record: %v", err)
+	}
+	if _, err := svc.AddRecord(withoutEmail.ID, "Allergy training", now.AddDate(-1, 0, 0), now.AddDate(0, 0, 5)); err != nil {
+		t.Fatalf("add expiring record: %v", err)
+	}
+	if _, err := svc.AddRecord(withEmail.ID, "First aid", now, now.AddDate(1, 0, 0)); err != nil {
+		t.Fatalf("add valid record: %v", err)
+	}
+	if _, err := svc.AddRecord(withEmail.ID, "", now, now.AddDate(1, 0, 0)); err == nil {
+		t.Fatal("expected a nameless record to be rejected")
+	}
+	if _, err := svc.AddRecord(withEmail.ID, "Backdated", now, now.AddDate(-1, 0, 0)); err == nil {
+		t.Fatal("expected an expiry before completion to be rejected")
+	}
+
+	sent, err := svc.RunOnce(now)
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if sent != 2 {
+		t.Fatalf("expected 2 reminders, got %d", sent)
+	}
+
+	var jobs []models.Job
+	if err := db.Where("type = ?", "email.send").Find(&jobs).Error; err != nil {
+		t.Fatalf("load email jobs: %v", err)
+	}
+	recipients := ""
+	for _, job := range jobs {
+		recipients += job.Payload
+	}
+	if !strings.Contains(recipients, "eva@example.com") {
+		t.Fatal("expected the reminder to go to the employee's email")
+	}
+	if !strings.Contains(recipients, "olive@example.com") {
+		t.Fatal("expected the email-less employee's reminder to go to the owner")
+	}
+
+	// A second run finds nothing left to remind.
+	sent, err = svc.RunOnce(now)
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if sent != 0 {
+		t.Fatalf("expected no repeat reminders, got %d", sent)
+	}
+}
+
+func TestTrainingStatusCoversEveryEmployee(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Stan", Email: "stan@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Status", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+	trained := &models.Employee{RestaurantID: restaurant.ID, Name: "Tess"}
+	if err := db.Create(trained).Error; err != nil {
+		t.Fatalf("create employee: %v", err)
+	}
+	untrained := &models.Employee{RestaurantID: restaurant.ID, Name: "Udo"}
+	if err := db.Create(untrained).Error; err != nil {
+		t.Fatalf("create employee: %v", err)
+	}
+
+	svc := NewTrainingService(repositories.NewTrainingRepository(db), repositories.NewStaffRepository(db), repositories.NewRestaurantRepository(db), repositories.NewUserRepository(db), repositories.NewJobRepository(db))
+
+	now := time.Now().UTC()
+	if _, err := svc.AddRecord(trained.ID, "Food handler cert", now.AddDate(-2, 0, 0), now.AddDate(0, 0, 10)); err != nil {
+		t.Fatalf("add expiring record: %v", err)
+	}
+	if _, err := svc.AddRecord(trained.ID, "Allergy training", now, now.AddDate(1, 0, 0)); err != nil {
+		t.Fatalf("add valid record: %v", err)
+	}
+
+	rows, err := svc.Status(restaurant.ID, now)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %+v", rows)
+	}
+	statuses := map[string]string{}
+	for _, row := range rows {
+		key := row.EmployeeName + "/" + row.Training
+		statuses[key] = row.Status
+	}
+	if statuses["Tess/Food handler cert"] != LicenseExpiring {
+		t.Fatalf("expected the near-expiry cert to be expiring, got %+v", statuses)
+	}
+	if statuses["Tess/Allergy training"] != LicenseValid {
+		t.Fatalf("expected the fresh training to be valid, got %+v", statuses)
+	}
+	if statuses["Udo/"] != LicenseMissing {
+		t.Fatalf("expected the untrained employee to show as missing, got %+v", statuses)
+	}
+}
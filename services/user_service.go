@@ -0,0 +1,147 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// UserService implements user profile management.
+type UserService struct {
+	userRepo       UserRepo
+	restaurantRepo RestaurantRepo
+	tx             TxRunner
+	deletePolicy   string // "block" or "cascade", from USER_DELETE_POLICY
+}
+
+// NewUserService builds a UserService.
+func NewUserService(userRepo UserRepo, restaurantRepo RestaurantRepo, tx TxRunner, deletePolicy string) *UserService {
+	return &UserService{userRepo: userRepo, restaurantRepo: restaurantRepo, tx: tx, deletePolicy: deletePolicy}
+}
+
+// GetUserByID returns the user with the given id.
+func (s *UserService) GetUserByID(id uint) (*models.User, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "user_not_found", "user not found")
+	}
+	return user, nil
+}
+
+// ResolveID maps a path identifier to the internal primary key. Public
+// UUIDs are looked up; bare integers are still accepted so pre-UUID
+// clients keep working.
+func (s *UserService) ResolveID(param string) (uint, error) {
+	if n, err := strconv.ParseUint(param, 10, 32); err == nil {
+		return uint(n), nil
+	}
+	user, err := s.userRepo.GetByPublicID(param)
+	if err != nil {
+		return 0, fromRepo(err, "user_not_found", "user not found")
+	}
+	return user.ID, nil
+}
+
+// UpdateUser updates the fields of an existing user. Nil pointers mean
+// "not provided"; a pointer to the empty string clears the field, so a
+// phone number can be removed without a dedicated endpoint.
+func (s *UserService) UpdateUser(id uint, name, phone *string, birthday *time.Time) (*models.User, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "user_not_found", "user not found")
+	}
+	if name != nil {
+		if *name == "" {
+			return nil, Validation("name_required", "name cannot be empty")
+		}
+		user.Name = utils.SanitizeText(*name)
+	}
+	if phone != nil {
+		user.Phone = utils.SanitizeText(*phone)
+	}
+	if birthday != nil {
+		if birthday.IsZero() {
+			user.Birthday = nil
+		} else {
+			user.Birthday = birthday
+		}
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// DeleteUser soft-deletes the user with the given id. Admin accounts are
+// guarded: an admin cannot delete themselves, and the last remaining
+// admin cannot be deleted at all, so the platform can never end up
+// without one.
+func (s *UserService) DeleteUser(id, callerID uint) error {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return fromRepo(err, "user_not_found", "user not found")
+	}
+	if user.Role == "admin" {
+		if id == callerID {
+			return ErrSelfDelete
+		}
+		admins, err := s.userRepo.CountAdmins()
+		if err != nil {
+			return err
+		}
+		if admins <= 1 {
+			return ErrLastAdmin
+		}
+	}
+
+	owned, err := s.restaurantRepo.CountByUserID(id)
+	if err != nil {
+		return err
+	}
+	if owned > 0 {
+		switch s.deletePolicy {
+		case "cascade":
+			// The account and its restaurants disappear together or not
+			// at all.
+			return s.tx.Transaction(func(tx *gorm.DB) error {
+				if err := s.restaurantRepo.DeleteByUserIDInTx(tx, id); err != nil {
+					return err
+				}
+				return s.userRepo.DeleteInTx(tx, id)
+			})
+		default: // "block"
+			return ErrOwnsRestaurants
+		}
+	}
+	return s.userRepo.Delete(id)
+}
+
+// UpdateRole changes a user's role. Demoting the last admin is refused
+// for the same reason deleting them is.
+func (s *UserService) UpdateRole(id uint, role models.Role) (*models.User, error) {
+	if !role.Valid() {
+		return nil, Validation("invalid_role", "role must be owner or admin")
+	}
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "user_not_found", "user not found")
+	}
+	if user.Role == models.RoleAdmin && role != models.RoleAdmin {
+		admins, err := s.userRepo.CountAdmins()
+		if err != nil {
+			return nil, err
+		}
+		if admins <= 1 {
+			return nil, ErrLastAdmin
+		}
+	}
+	user.Role = role
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
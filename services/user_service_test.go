@@ -0,0 +1,60 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func newUserServiceForTest(t *testing.T, policy string) (*UserService, *models.User, *models.Restaurant) {
+	t.Helper()
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("ConnectTest: %v", err)
+	}
+
+	owner := models.User{Name: "Ana", Email: "ana@example.com", Role: "owner"}
+	if err := db.Create(&owner).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{Name: "Testaurant", UserID: owner.ID}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("creating restaurant: %v", err)
+	}
+
+	svc := NewUserService(
+		repositories.NewUserRepository(db),
+		repositories.NewRestaurantRepository(db),
+		repositories.NewTxManager(db),
+		policy,
+	)
+	return svc, &owner, &restaurant
+}
+
+func TestDeleteUserBlockedWhileOwningRestaurants(t *testing.T) {
+	svc, owner, _ := newUserServiceForTest(t, "block")
+
+	if err := svc.DeleteUser(owner.ID, owner.ID); !errors.Is(err, ErrOwnsRestaurants) {
+		t.Fatalf("expected ErrOwnsRestaurants, got %v", err)
+	}
+	if _, err := svc.GetUserByID(owner.ID); err != nil {
+		t.Fatalf("user should still exist: %v", err)
+	}
+}
+
+func TestDeleteUserCascadesRestaurants(t *testing.T) {
+	svc, owner, restaurant := newUserServiceForTest(t, "cascade")
+
+	if err := svc.DeleteUser(owner.ID, owner.ID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, err := svc.GetUserByID(owner.ID); err == nil {
+		t.Fatalf("user should be deleted")
+	}
+	if _, err := svc.restaurantRepo.GetByID(restaurant.ID); err == nil {
+		t.Fatalf("restaurant should be deleted with its owner")
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+	"github.com/marc0cl/wets-ma-bu-api/utils"
+)
+
+// WasteService logs kitchen losses and reports what they cost.
+type WasteService struct {
+	wasteRepo     WasteRepo
+	inventoryRepo InventoryRepo
+}
+
+// NewWasteService builds a WasteService.
+func NewWasteService(wasteRepo WasteRepo, inventoryRepo InventoryRepo) *WasteService {
+	return &WasteService{wasteRepo: wasteRepo, inventoryRepo: inventoryRepo}
+}
+
+// LogWaste records a loss. When the entry names an ingredient, the
+// description and cost default from the inventory and the wasted
+// quantity comes off the ingredient's stock.
+func (s *WasteService) LogWaste(restaurantID, ingredientID uint, description string, quantity float64, reason string, cost float64) (*models.WasteEntry, error) {
+	if quantity <= 0 {
+		return nil, Validation("invalid_quantity", "the wasted quantity must be positive")
+	}
+	reason = utils.SanitizeText(reason)
+	if reason == "" {
+		return nil, Validation("missing_reason", "a reason is required")
+	}
+	if cost < 0 {
+		return nil, Validation("invalid_cost", "the cost cannot be negative")
+	}
+	description = utils.SanitizeText(description)
+
+	if ingredientID != 0 {
+		ingredient, err := s.inventoryRepo.GetIngredientByID(ingredientID)
+		if err != nil {
+			return nil, fromRepo(err, "ingredient_not_found", "ingredient not found")
+		}
+		if ingredient.RestaurantID != restaurantID {
+			return nil, Validation("foreign_ingredient", "the ingredient belongs to another restaurant")
+		}
+		if description == "" {
+			description = ingredient.Name
+		}
+		if cost == 0 {
+			cost = quantity * ingredient.CostPerUnit
+		}
+		movement := &models.StockMovement{IngredientID: ingredientID, Delta: -quantity, Reason: "waste"}
+		if err := s.inventoryRepo.AdjustStock(movement); err != nil {
+			return nil, err
+		}
+	} else if description == "" {
+		return nil, Validation("missing_description", "a description is required")
+	}
+
+	entry := &models.WasteEntry{
+		RestaurantID: restaurantID,
+		IngredientID: ingredientID,
+		Description:  description,
+		Quantity:     quantity,
+		Reason:       reason,
+		Cost:         cost,
+	}
+	if err := s.wasteRepo.Create(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Entries returns a restaurant's waste log in the range, newest first.
+func (s *WasteService) Entries(restaurantID uint, from, to time.Time) ([]models.WasteEntry, error) {
+	return s.wasteRepo.GetByRestaurantID(restaurantID, from, to)
+}
+
+// WasteReport is a restaurant's waste cost over a period, broken down
+// by day.
+type WasteReport struct {
+	Total float64                 `json:"total"`
+	Days  []repositories.WasteDay `json:"days"`
+}
+
+// Report totals a restaurant's waste cost in the range.
+func (s *WasteService) Report(restaurantID uint, from, to time.Time) (*WasteReport, error) {
+	days, err := s.wasteRepo.CostByDay(restaurantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	report := &WasteReport{Days: days}
+	for _, day := range days {
+		report.Total += day.Cost
+	}
+	return report, nil
+}
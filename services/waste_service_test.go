@@ -0,0 +1,128 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestLogWasteDefaultsFromIngredientAndDepletesStock(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Wanda", Email: "wanda@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Wasteful", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	inventoryRepo := repositories.NewInventoryRepository(db)
+	recipeSvc := NewRecipeService(inventoryRepo, repositories.NewMenuRepository(db))
+	milk, err := recipeSvc.CreateIngredient(restaurant.ID, "Milk", "l", 1200)
+	if err != nil {
+		t.Fatalf("create ingredient: %v", err)
+	}
+	stockSvc := NewStockService(inventoryRepo, repositories.NewRestaurantRepository(db), repositories.NewUserRepository(db), repositories.NewJobRepository(db))
+	if _, err := stockSvc.Adjust(milk.ID, 4, "delivery"); err != nil {
+		t.Fatalf("stock delivery: %v", err)
+	}
+
+	wasteSvc := NewWasteService(repositories.NewWasteRepository(db), inventoryRepo)
+
+	entry, err := wasteSvc.LogWaste(restaurant.ID, milk.ID, "", 0.5, "spoiled", 0)
+	if err != nil {
+		t.Fatalf("logging ingredient waste: %v", err)
+	}
+	if entry.Description != "Milk" {
+		t.Fatalf("expected description to default to the ingredient name, got %q", entry.Description)
+	}
+	if entry.Cost != 600 {
+		t.Fatalf("expected cost to default to quantity times unit cost (600), got %v", entry.Cost)
+	}
+	after, err := recipeSvc.GetIngredientByID(milk.ID)
+	if err != nil {
+		t.Fatalf("reloading ingredient: %v", err)
+	}
+	if after.Stock != 3.5 {
+		t.Fatalf("expected waste to deplete stock to 3.5, got %v", after.Stock)
+	}
+	movements, err := stockSvc.Movements(milk.ID)
+	if err != nil {
+		t.Fatalf("listing movements: %v", err)
+	}
+	if len(movements) != 2 || movements[0].Reason != "waste" {
+		t.Fatalf("expected a waste movement on top of the delivery, got %+v", movements)
+	}
+
+	// An ingredient from another restaurant must be rejected.
+	other := &models.Restaurant{Name: "Elsewhere", UserID: owner.ID}
+	if err := db.Create(other).Error; err != nil {
+		t.Fatalf("create other restaurant: %v", err)
+	}
+	if _, err := wasteSvc.LogWaste(other.ID, milk.ID, "", 1, "spoiled", 0); err == nil {
+		t.Fatal("expected logging waste against a foreign ingredient to fail")
+	}
+
+	// Free-form entries carry their own description and cost.
+	if _, err := wasteSvc.LogWaste(restaurant.ID, 0, "", 1, "dropped", 500); err == nil {
+		t.Fatal("expected a free-form entry without a description to fail")
+	}
+	if _, err := wasteSvc.LogWaste(restaurant.ID, 0, "Plated salmon", 1, "dropped", 9500); err != nil {
+		t.Fatalf("logging free-form waste: %v", err)
+	}
+}
+
+func TestWasteReportTotalsCostByDay(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	owner := &models.User{Name: "Rita", Email: "rita@example.com", Password: "x", Role: models.RoleOwner}
+	if err := db.Create(owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	restaurant := &models.Restaurant{Name: "Reporting", UserID: owner.ID}
+	if err := db.Create(restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	wasteSvc := NewWasteService(repositories.NewWasteRepository(db), repositories.NewInventoryRepository(db))
+	if _, err := wasteSvc.LogWaste(restaurant.ID, 0, "Burnt toast", 2, "overcooked", 300); err != nil {
+		t.Fatalf("logging waste: %v", err)
+	}
+	if _, err := wasteSvc.LogWaste(restaurant.ID, 0, "Stale bread", 1, "expired", 700); err != nil {
+		t.Fatalf("logging waste: %v", err)
+	}
+
+	now := time.Now().UTC()
+	report, err := wasteSvc.Report(restaurant.ID, now.AddDate(0, 0, -1), now.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("building report: %v", err)
+	}
+	if report.Total != 1000 {
+		t.Fatalf("expected a total of 1000, got %v", report.Total)
+	}
+	if len(report.Days) != 1 {
+		t.Fatalf("expected both entries grouped into one day, got %+v", report.Days)
+	}
+	if report.Days[0].Cost != 1000 {
+		t.Fatalf("expected the day's cost to be 1000, got %v", report.Days[0].Cost)
+	}
+
+	empty, err := wasteSvc.Report(restaurant.ID, now.AddDate(0, -2, 0), now.AddDate(0, -1, 0))
+	if err != nil {
+		t.Fatalf("building empty report: %v", err)
+	}
+	if empty.Total != 0 || len(empty.Days) != 0 {
+		t.Fatalf("expected an empty report outside the range, got %+v", empty)
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"encoding/json"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// WebhookService fans domain events out to restaurant webhook
+// endpoints. Each dispatch becomes a WebhookDelivery row and a
+// "webhook.deliver" job; the jobs worker does the signed HTTP call with
+// its usual backoff and dead-lettering.
+type WebhookService struct {
+	webhookRepo    WebhookRepo
+	restaurantRepo RestaurantRepo
+	brands         brandSettingsResolver
+	jobs           JobEnqueuer
+}
+
+// NewWebhookService builds a WebhookService.
+func NewWebhookService(webhookRepo WebhookRepo, restaurantRepo RestaurantRepo, brands brandSettingsResolver, jobs JobEnqueuer) *WebhookService {
+	return &WebhookService{webhookRepo: webhookRepo, restaurantRepo: restaurantRepo, brands: brands, jobs: jobs}
+}
+
+// Publish implements events.Publisher: events naming a restaurant_id in
+// their payload are dispatched to that restaurant's webhook. Events
+// without one have no webhook audience and are dropped silently.
+func (s *WebhookService) Publish(eventType string, payload []byte) error {
+	var subject struct {
+		RestaurantID uint `json:"restaurant_id"`
+	}
+	if err := json.Unmarshal(payload, &subject); err != nil || subject.RestaurantID == 0 {
+		return nil
+	}
+	_, err := s.Dispatch(subject.RestaurantID, eventType, payload)
+	return err
+}
+
+// Dispatch queues one event for delivery to the restaurant's effective
+// webhook URL. A restaurant without one configured is a no-op, not an
+// error.
+func (s *WebhookService) Dispatch(restaurantID uint, eventType string, payload []byte) (*models.WebhookDelivery, error) {
+	settings, err := s.brands.EffectiveSettings(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.WebhookURL == "" {
+		return nil, nil
+	}
+	if _, err := s.Secret(restaurantID); err != nil {
+		return nil, err
+	}
+
+	delivery := &models.WebhookDelivery{
+		RestaurantID: restaurantID,
+		EventType:    eventType,
+		URL:          settings.WebhookURL,
+		Payload:      string(payload),
+		Status:       models.WebhookPending,
+	}
+	if err := s.webhookRepo.Create(delivery); err != nil {
+		return nil, err
+	}
+	if err := s.enqueue(delivery.ID); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Secret returns the restaurant's webhook signing secret, minting it on
+// first use. Owners fetch it once to verify signatures on their end.
+func (s *WebhookService) Secret(restaurantID uint) (string, error) {
+	restaurant, err := s.restaurantRepo.GetByID(restaurantID)
+	if err != nil {
+		return "", fromRepo(err, "restaurant_not_found", "restaurant not found")
+	}
+	if restaurant.WebhookSecret == "" {
+		restaurant.WebhookSecret = models.NewUUID()
+		if err := s.restaurantRepo.Update(restaurant); err != nil {
+			return "", err
+		}
+	}
+	return restaurant.WebhookSecret, nil
+}
+
+// GetDeliveryByID returns one delivery; handlers use it for ownership
+// checks.
+func (s *WebhookService) GetDeliveryByID(id uint) (*models.WebhookDelivery, error) {
+	delivery, err := s.webhookRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "delivery_not_found", "webhook delivery not found")
+	}
+	return delivery, nil
+}
+
+// Deliveries returns the restaurant's delivery log, newest first.
+func (s *WebhookService) Deliveries(restaurantID uint) ([]models.WebhookDelivery, error) {
+	return s.webhookRepo.GetByRestaurantID(restaurantID)
+}
+
+// Replay re-queues a failed delivery. Pending and delivered ones are
+// refused: the first is still in flight, the second already landed.
+func (s *WebhookService) Replay(id uint) (*models.WebhookDelivery, error) {
+	delivery, err := s.webhookRepo.GetByID(id)
+	if err != nil {
+		return nil, fromRepo(err, "delivery_not_found", "webhook delivery not found")
+	}
+	if delivery.Status != models.WebhookFailed {
+		return nil, Conflict("delivery_not_failed", "only failed deliveries can be replayed")
+	}
+	delivery.Status = models.WebhookPending
+	if err := s.webhookRepo.Update(delivery); err != nil {
+		return nil, err
+	}
+	if err := s.enqueue(delivery.ID); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// enqueue queues the delivery job for the given delivery row.
+func (s *WebhookService) enqueue(deliveryID uint) error {
+	payload, err := json.Marshal(map[string]interface{}{"delivery_id": deliveryID})
+	if err != nil {
+		return err
+	}
+	return s.jobs.Enqueue(&models.Job{Type: "webhook.deliver", Payload: string(payload)})
+}
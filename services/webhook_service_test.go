@@ -0,0 +1,89 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/marc0cl/wets-ma-bu-api/database"
+	"github.com/marc0cl/wets-ma-bu-api/models"
+	"github.com/marc0cl/wets-ma-bu-api/repositories"
+)
+
+func TestWebhookDispatchAndReplay(t *testing.T) {
+	db, err := database.ConnectTest()
+	if err != nil {
+		t.Fatalf("connect test db: %v", err)
+	}
+
+	restaurant := models.Restaurant{Name: "Ganchos", UserID: 1}
+	if err := db.Create(&restaurant).Error; err != nil {
+		t.Fatalf("create restaurant: %v", err)
+	}
+
+	brandSvc := NewBrandService(repositories.NewBrandRepository(db), repositories.NewRestaurantRepository(db))
+	svc := NewWebhookService(repositories.NewWebhookRepository(db), repositories.NewRestaurantRepository(db), brandSvc, repositories.NewJobRepository(db))
+
+	payload := []byte(`{"order_id":7,"restaurant_id":` + strconv.FormatUint(uint64(restaurant.ID), 10) + `}`)
+
+	// No URL configured: the event is dropped, not queued.
+	if err := svc.Publish("order.created", payload); err != nil {
+		t.Fatalf("publish without url: %v", err)
+	}
+	var count int64
+	db.Model(&models.WebhookDelivery{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected no deliveries without a url, got %d", count)
+	}
+
+	if err := db.Model(&restaurant).Update("webhook_url", "https://hooks.example.com/ganchos").Error; err != nil {
+		t.Fatalf("set webhook url: %v", err)
+	}
+	if err := svc.Publish("order.created", payload); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	deliveries, err := svc.Deliveries(restaurant.ID)
+	if err != nil {
+		t.Fatalf("list deliveries: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Status != models.WebhookPending || deliveries[0].URL != "https://hooks.example.com/ganchos" {
+		t.Fatalf("expected one pending delivery to the configured url, got %+v", deliveries)
+	}
+	var jobs int64
+	db.Model(&models.Job{}).Where("type = ?", "webhook.deliver").Count(&jobs)
+	if jobs != 1 {
+		t.Fatalf("expected one delivery job, got %d", jobs)
+	}
+
+	// Dispatching minted the signing secret, and it stays put.
+	secret, err := svc.Secret(restaurant.ID)
+	if err != nil {
+		t.Fatalf("get secret: %v", err)
+	}
+	again, err := svc.Secret(restaurant.ID)
+	if err != nil {
+		t.Fatalf("get secret again: %v", err)
+	}
+	if secret == "" || secret != again {
+		t.Fatalf("expected a stable minted secret, got %q then %q", secret, again)
+	}
+
+	// Only failed deliveries replay.
+	if _, err := svc.Replay(deliveries[0].ID); err == nil {
+		t.Fatal("expected a pending delivery to refuse replay")
+	}
+	if err := db.Model(&deliveries[0]).Update("status", models.WebhookFailed).Error; err != nil {
+		t.Fatalf("fail delivery: %v", err)
+	}
+	replayed, err := svc.Replay(deliveries[0].ID)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayed.Status != models.WebhookPending {
+		t.Fatalf("expected the replay to re-queue the delivery, got %s", replayed.Status)
+	}
+	db.Model(&models.Job{}).Where("type = ?", "webhook.deliver").Count(&jobs)
+	if jobs != 2 {
+		t.Fatalf("expected a second delivery job after replay, got %d", jobs)
+	}
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Client talks to S3 (or a compatible store like MinIO) with AWS
+// Signature V4 over plain HTTP requests — the same thin-client approach
+// we use for SendGrid, FCM and Elasticsearch. Path-style addressing is
+// used so it works against MinIO out of the box.
+type S3Client struct {
+	endpoint  string // e.g. https://s3.us-east-1.amazonaws.com
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+// NewS3Client builds an S3Client.
+func NewS3Client(endpoint, region, bucket, accessKey, secretKey string) *S3Client {
+	return &S3Client{
+		endpoint:  strings.TrimRight(endpoint, "/"),
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Put uploads an object. Server-side encryption is always requested;
+// stores that do not support the header ignore it.
+func (c *S3Client) Put(key string, body []byte) error {
+	_, err := c.do(http.MethodPut, key, body)
+	return err
+}
+
+// Get downloads an object.
+func (c *S3Client) Get(key string) ([]byte, error) {
+	return c.do(http.MethodGet, key, nil)
+}
+
+func (c *S3Client) do(method, key string, body []byte) ([]byte, error) {
+	rawURL := c.endpoint + "/" + c.bucket + "/" + key
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, rawURL, strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+	payloadHash := hexSHA256(body)
+
+	// Signed headers must stay sorted; append in alphabetical order.
+	headers := [][2]string{
+		{"host", u.Host},
+		{"x-amz-content-sha256", payloadHash},
+		{"x-amz-date", amzDate},
+	}
+	if method == http.MethodPut {
+		headers = append(headers, [2]string{"x-amz-server-side-encryption", "AES256"})
+	}
+	var names []string
+	var canonicalHeaders string
+	for _, h := range headers {
+		req.Header.Set(h[0], h[1])
+		names = append(names, h[0])
+		canonicalHeaders += h[0] + ":" + h[1] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		"", // no query string
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := dateStamp + "/" + c.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256(
+		hmacSHA256(
+			hmacSHA256(
+				hmacSHA256([]byte("AWS4"+c.secretKey), dateStamp),
+				c.region),
+			"s3"),
+		"aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKey, scope, signedHeaders, signature))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("s3 returned status %d for %s %s", resp.StatusCode, method, key)
+	}
+	return data, nil
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,12 @@
+package utils
+
+import "strings"
+
+// NormalizeEmail canonicalises an email address for storage and lookup:
+// surrounding whitespace is dropped and the whole address lowercased.
+// RFC 5321 technically allows a case-sensitive local part, but no real
+// mail provider distinguishes User@x.com from user@x.com, and treating
+// them as different accounts is far worse than the theoretical loss.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+
+	"github.com/marc0cl/wets-ma-bu-api/models"
+)
+
+// JWTClaims are the custom claims embedded in every access token. The
+// numeric UserID stays in the token for internal lookups; PublicID is
+// what matches the identifiers the API exposes.
+type JWTClaims struct {
+	UserID   uint        `json:"user_id"`
+	PublicID string      `json:"public_id"`
+	Email    string      `json:"email"`
+	Role     models.Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// GenerateToken signs a new access token for the given user.
+func GenerateToken(userID uint, publicID, email string, role models.Role, secret string) (string, error) {
+	claims := &JWTClaims{
+		UserID:   userID,
+		PublicID: publicID,
+		Email:    email,
+		Role:     role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+// claimsContextKey is where the JWT middleware stores the parsed claims.
+const claimsContextKey = "claims"
+
+// SetClaims stores the parsed claims in the request context; called by
+// the JWT middleware after validating the token.
+func SetClaims(c echo.Context, claims *JWTClaims) {
+	c.Set(claimsContextKey, claims)
+}
+
+// GetClaims returns the claims stored by the JWT middleware. Unlike the
+// old ExtractTokenClaims it returns an error instead of panicking when
+// the middleware did not run — a route accidentally registered outside
+// the authenticated group fails with a 401, not a crash.
+func GetClaims(c echo.Context) (*JWTClaims, error) {
+	claims, ok := c.Get(claimsContextKey).(*JWTClaims)
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	return claims, nil
+}
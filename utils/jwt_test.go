@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newTestContext() echo.Context {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	return e.NewContext(req, httptest.NewRecorder())
+}
+
+func TestGetClaimsRoundTrip(t *testing.T) {
+	c := newTestContext()
+	SetClaims(c, &JWTClaims{UserID: 42, Role: "owner"})
+
+	claims, err := GetClaims(c)
+	if err != nil {
+		t.Fatalf("GetClaims: %v", err)
+	}
+	if claims.UserID != 42 || claims.Role != "owner" {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+}
+
+func TestGetClaimsMissingReturnsError(t *testing.T) {
+	c := newTestContext()
+
+	if _, err := GetClaims(c); err == nil {
+		t.Fatal("expected an error when the JWT middleware has not run")
+	}
+}
+
+func TestGetClaimsWrongTypeReturnsError(t *testing.T) {
+	c := newTestContext()
+	c.Set("claims", "not-claims")
+
+	if _, err := GetClaims(c); err == nil {
+		t.Fatal("expected an error for a foreign value under the claims key")
+	}
+}
@@ -0,0 +1,40 @@
+package utils
+
+import "github.com/labstack/echo/v4"
+
+// Response is the standard envelope returned by every endpoint.
+type Response struct {
+	Success   bool        `json:"success"`
+	Message   string      `json:"message,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	Code      string      `json:"code,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// OK builds a successful response with the given payload.
+func OK(message string, data interface{}) Response {
+	return Response{Success: true, Message: message, Data: data}
+}
+
+// Fail builds an error response with the given message.
+func Fail(err string) Response {
+	return Response{Success: false, Error: err}
+}
+
+// Error writes an error response carrying the request ID assigned by the
+// RequestID middleware, so clients can quote it in support tickets.
+func Error(c echo.Context, status int, err string) error {
+	return ErrorCode(c, status, "", err)
+}
+
+// ErrorCode is Error with a machine-readable code clients can branch on
+// without parsing the human-readable message.
+func ErrorCode(c echo.Context, status int, code, err string) error {
+	return c.JSON(status, Response{
+		Success:   false,
+		Error:     err,
+		Code:      code,
+		RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+	})
+}
@@ -0,0 +1,35 @@
+package utils
+
+import "strings"
+
+// SanitizeText strips HTML markup from user-supplied free text. The API
+// stores and serves plain text only, so the policy is strict: tags are
+// removed outright rather than escaped, entities for < and > are
+// neutralised so they cannot reassemble into markup downstream, and
+// control characters are dropped. Frontends rendering these fields
+// without escaping get no stored-XSS payloads to execute.
+func SanitizeText(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	inTag := false
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>':
+			inTag = false
+		case inTag:
+			// skip tag contents
+		case r == '\n' || r == '\t':
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			// skip control characters
+		default:
+			b.WriteRune(r)
+		}
+	}
+	out := b.String()
+	out = strings.ReplaceAll(out, "&lt;", "")
+	out = strings.ReplaceAll(out, "&gt;", "")
+	return strings.TrimSpace(out)
+}
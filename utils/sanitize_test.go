@@ -0,0 +1,18 @@
+package utils
+
+import "testing"
+
+func TestSanitizeText(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"Pastel de choclo", "Pastel de choclo"},
+		{"<script>alert(1)</script>Empanadas", "alert(1)Empanadas"},
+		{"Best <b>pisco</b> in town", "Best pisco in town"},
+		{"&lt;img src=x onerror=alert(1)&gt;", "img src=x onerror=alert(1)"},
+		{"line1\nline2\x00", "line1\nline2"},
+	}
+	for _, tc := range cases {
+		if got := SanitizeText(tc.in); got != tc.want {
+			t.Errorf("SanitizeText(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
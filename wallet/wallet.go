@@ -0,0 +1,57 @@
+package wallet
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/marc0cl/wets-ma-bu-api/config"
+)
+
+// Pass is one loyalty pass as the wallet platforms see it: who it
+// belongs to, which restaurant issued it, and the current balance.
+type Pass struct {
+	Serial       string
+	Platform     string
+	Restaurant   string
+	CustomerName string
+	Balance      int
+}
+
+// Provider talks to a wallet platform. Passes are identified by their
+// serial; the platform name picks the wire format inside multi-platform
+// drivers.
+type Provider interface {
+	// IssuePass registers the pass with the platform and returns the
+	// URL the customer opens to add it to their wallet.
+	IssuePass(pass Pass) (string, error)
+	// PushUpdate tells the platform the pass changed so installed
+	// copies refresh their balance.
+	PushUpdate(pass Pass) error
+}
+
+// LogProvider logs pass traffic instead of executing it; it is the dev
+// default.
+type LogProvider struct{}
+
+// IssuePass implements Provider.
+func (LogProvider) IssuePass(pass Pass) (string, error) {
+	log.Printf("wallet (dev mode) issue platform=%s serial=%s balance=%d", pass.Platform, pass.Serial, pass.Balance)
+	return "https://wallet.invalid/passes/" + pass.Serial, nil
+}
+
+// PushUpdate implements Provider.
+func (LogProvider) PushUpdate(pass Pass) error {
+	log.Printf("wallet (dev mode) update platform=%s serial=%s balance=%d", pass.Platform, pass.Serial, pass.Balance)
+	return nil
+}
+
+// NewFromConfig builds the configured driver. "log" is the dev default:
+// platform traffic is printed instead of executed.
+func NewFromConfig(cfg *config.Config) (Provider, error) {
+	switch cfg.WalletDriver {
+	case "log", "":
+		return LogProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported wallet driver %q", cfg.WalletDriver)
+	}
+}